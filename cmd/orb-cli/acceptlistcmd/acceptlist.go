@@ -26,7 +26,7 @@ const (
 	actorEnvKey = "ORB_CLI_ACTOR"
 
 	typeFlagName  = "type"
-	typeFlagUsage = "Accept list type (follow or invite-witness)." +
+	typeFlagUsage = "Accept list type (follow, invite-witness or anchor-origin)." +
 		" Alternatively, this can be set with the following environment variable: " + typeEnvKey
 	typeEnvKey = "ORB_CLI_ACCEPT_TYPE"
 )
@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backfillcmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the backfill REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	serviceFlagName  = "service"
+	serviceFlagUsage = "The IRI of the service whose outbox is to be backfilled." +
+		" Alternatively, this can be set with the following environment variable: " + serviceEnvKey
+	serviceEnvKey = "ORB_CLI_SERVICE"
+
+	idFlagName  = "id"
+	idFlagUsage = "The ID of the backfill job. If not specified then all jobs are returned." +
+		" Alternatively, this can be set with the following environment variable: " + idEnvKey
+	idEnvKey = "ORB_CLI_ID"
+)
+
+// GetCmd returns the Cobra backfill command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "backfill",
+		Short:        "Manages backfills of the anchor history from the outboxes of remote services.",
+		Long:         "Manages backfills of the anchor history from the outboxes of remote services.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand start or get")
+		},
+	}
+
+	cmd.AddCommand(
+		newStartCmd(),
+		newGetCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backfillcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+func newStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "start",
+		Short:        "Starts a backfill from the outbox of a remote service.",
+		Long:         "Starts (or resumes) a backfill of the anchor history from the outbox of a remote service.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeStart(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringP(serviceFlagName, "", "", serviceFlagUsage)
+
+	return cmd
+}
+
+func executeStart(cmd *cobra.Command) error {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	service, err := cmdutil.GetUserSetVarFromString(cmd, serviceFlagName, serviceEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = url.Parse(service)
+	if err != nil {
+		return fmt.Errorf("invalid service URL %s: %w", service, err)
+	}
+
+	reqBytes, err := json.Marshal(&backfillRequest{Service: service})
+	if err != nil {
+		return err
+	}
+
+	resp, err := common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(resp))
+
+	return nil
+}
+
+type backfillRequest struct {
+	Service string `json:"service"`
+}
@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backfillcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	flag = "--"
+
+	start = "start"
+
+	testService = "https://orb.domain2.com/services/orb"
+)
+
+func TestStartCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{start})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid url arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{start}
+		args = append(args, urlArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL")
+	})
+
+	t.Run("test missing service arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{start}
+		args = append(args, urlArg("localhost:8080")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither service (command line flag) nor ORB_CLI_SERVICE (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid service arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{start}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, serviceArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid service URL")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			req := &backfillRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, req))
+			require.Equal(t, testService, req.Service)
+
+			_, err = fmt.Fprint(w, `{"id":"job1","status":"pending"}`)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{start}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, serviceArg(testService)...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.NoError(t, err)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{start}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, serviceArg(testService)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+	})
+}
+
+func urlArg(value string) []string {
+	return []string{flag + urlFlagName, value}
+}
+
+func serviceArg(value string) []string {
+	return []string{flag + serviceFlagName, value}
+}
+
+func idArg(value string) []string {
+	return []string{flag + idFlagName, value}
+}
+
+func authTokenArg(value string) []string {
+	return []string{flag + common.AuthTokenFlagName, value}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverycmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	urlFlagName  = "url"
+	urlFlagUsage = "The URL of the outbox deliveries REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+	urlEnvKey = "ORB_CLI_URL"
+
+	statusFlagName  = "status"
+	statusFlagUsage = "Filter by delivery status (failed or pending). Defaults to failed." +
+		" Alternatively, this can be set with the following environment variable: " + statusEnvKey
+	statusEnvKey = "ORB_CLI_STATUS"
+
+	idFlagName  = "id"
+	idFlagUsage = "A comma-separated list of delivery IDs to re-drive." +
+		" Alternatively, this can be set with the following environment variable: " + idEnvKey
+	idEnvKey = "ORB_CLI_ID"
+
+	targetFlagName  = "target"
+	targetFlagUsage = "The target inbox URL. All failed deliveries to this inbox are re-driven." +
+		" Alternatively, this can be set with the following environment variable: " + targetEnvKey
+	targetEnvKey = "ORB_CLI_TARGET"
+)
+
+// GetCmd returns the Cobra delivery command.
+func GetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "delivery",
+		Short:        "Manages failed outbox deliveries.",
+		Long:         "Manages failed outbox deliveries.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand get or redeliver")
+		},
+	}
+
+	cmd.AddCommand(
+		newGetCmd(),
+		newRedeliverCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverycmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+func newRedeliverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "redeliver",
+		Short:        "Re-drives failed outbox deliveries.",
+		Long:         "Re-drives failed outbox deliveries, either by delivery ID or by target inbox.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeRedeliver(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
+	cmd.Flags().StringArrayP(idFlagName, "", nil, idFlagUsage)
+	cmd.Flags().StringP(targetFlagName, "", "", targetFlagUsage)
+
+	return cmd
+}
+
+func executeRedeliver(cmd *cobra.Command) error {
+	u, req, err := getRedeliverArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	_, err = common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("deliveries successfully re-driven.")
+
+	return nil
+}
+
+func getRedeliverArgs(cmd *cobra.Command) (string, *redeliveryRequest, error) {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = url.Parse(u)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	ids, err := cmdutil.GetUserSetVarFromArrayString(cmd, idFlagName, idEnvKey, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	target, err := cmdutil.GetUserSetVarFromString(cmd, targetFlagName, targetEnvKey, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(ids) == 0 && target == "" {
+		return "", nil, errors.New("either delivery ID or target must be specified")
+	}
+
+	if target != "" {
+		_, err = url.Parse(target)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid target URL %s: %w", target, err)
+		}
+	}
+
+	return u, &redeliveryRequest{IDs: ids, Target: target}, nil
+}
+
+type redeliveryRequest struct {
+	IDs    []string `json:"ids,omitempty"`
+	Target string   `json:"target,omitempty"`
+}
@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverycmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+)
+
+const (
+	flag = "--"
+
+	redeliver = "redeliver"
+
+	testTarget = "https://orb.domain2.com/services/orb/inbox"
+)
+
+func TestRedeliverCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{redeliver})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid url arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{redeliver}
+		args = append(args, urlArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL")
+	})
+
+	t.Run("test missing id and target args", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{redeliver}
+		args = append(args, urlArg("localhost:8080")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "either delivery ID or target must be specified")
+	})
+
+	t.Run("test invalid target arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{redeliver}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, targetArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid target URL")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			req := &redeliveryRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, req))
+			require.Equal(t, []string{"id1", "id2"}, req.IDs)
+			require.Equal(t, testTarget, req.Target)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{redeliver}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, idArg("id1")...)
+		args = append(args, idArg("id2")...)
+		args = append(args, targetArg(testTarget)...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.NoError(t, err)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{redeliver}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, idArg("id1")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+	})
+}
+
+func urlArg(value string) []string {
+	return []string{flag + urlFlagName, value}
+}
+
+func statusArg(value string) []string {
+	return []string{flag + statusFlagName, value}
+}
+
+func idArg(value string) []string {
+	return []string{flag + idFlagName, value}
+}
+
+func targetArg(value string) []string {
+	return []string{flag + targetFlagName, value}
+}
+
+func authTokenArg(value string) []string {
+	return []string{flag + common.AuthTokenFlagName, value}
+}
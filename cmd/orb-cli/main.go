@@ -12,8 +12,10 @@ import (
 
 	"github.com/trustbloc/orb/cmd/orb-cli/acceptlistcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/allowedoriginscmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/backfillcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/createdidcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/deactivatedidcmd"
+	"github.com/trustbloc/orb/cmd/orb-cli/deliverycmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/followcmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipfskeygencmd"
 	"github.com/trustbloc/orb/cmd/orb-cli/ipnshostmetagencmd"
@@ -71,6 +73,8 @@ func main() {
 
 	rootCmd.AddCommand(logmonitorcmd.GetCmd())
 	rootCmd.AddCommand(logcmd.GetCmd())
+	rootCmd.AddCommand(deliverycmd.GetCmd())
+	rootCmd.AddCommand(backfillcmd.GetCmd())
 
 	rootCmd.AddCommand(vctcmd.GetCmd())
 
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	explainURLFlagUsage = "The URL of the witness policy explain REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+
+	anchorFlagName  = "anchor"
+	anchorEnvKey    = "ORB_CLI_ANCHOR"
+	anchorFlagUsage = "The ID (hashlink) or resource hash of the anchor." +
+		" Alternatively, this can be set with the following environment variable: " + anchorEnvKey
+)
+
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Explains why an anchor is still pending the witness policy.",
+		Long: "Explains why an anchor is still pending the witness policy. The response includes the selected " +
+			"witnesses, the received proofs, the result of each condition of the witness policy and the next " +
+			"action of the policy inspector. For example: policy explain --url https://orb.domain1.com/policy/explain " +
+			"--anchor hl:uEiD1W_21fZwLNqV-T10km8jMvsT_lQZmmR4vIHQD7Wu73g",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeExplain(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", explainURLFlagUsage)
+	cmd.Flags().StringP(anchorFlagName, "", "", anchorFlagUsage)
+
+	return cmd
+}
+
+func executeExplain(cmd *cobra.Command) error {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	anchor, err := cmdutil.GetUserSetVarFromString(cmd, anchorFlagName, anchorEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	explainURL, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	query := explainURL.Query()
+	query.Set(anchorFlagName, anchor)
+
+	explainURL.RawQuery = query.Encode()
+
+	resp, err := common.SendHTTPRequest(cmd, nil, http.MethodGet, explainURL.String())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(resp))
+
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testAnchor = "hl:uEiD1W_21fZwLNqV-T10km8jMvsT_lQZmmR4vIHQD7Wu73g"
+
+func TestExplainCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"explain"})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing anchor arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"explain"}
+		args = append(args, urlArg("https://orb.domain1.com/policy/explain")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither anchor (command line flag) nor ORB_CLI_ANCHOR (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid url arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"explain"}
+		args = append(args, urlArg(":invalid")...)
+		args = append(args, anchorArg(testAnchor)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, testAnchor, r.URL.Query().Get(anchorFlagName))
+
+			_, err := fmt.Fprint(w, `{"status":"in-process"}`)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"explain"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, anchorArg(testAnchor)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.NoError(t, err)
+	})
+}
+
+func anchorArg(value string) []string {
+	return []string{flag + anchorFlagName, value}
+}
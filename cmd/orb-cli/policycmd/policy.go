@@ -20,8 +20,17 @@ const (
 
 	policyFlagName  = "policy"
 	policyEnvKey    = "ORB_CLI_POLICY"
-	policyFlagUsage = `The witness policy. For example "MinPercent(100,batch) AND OutOf(1,system)".` +
+	policyFlagUsage = `The witness policy. For example "MinPercent(100,batch) AND OutOf(1,system)". A policy may also ` +
+		`define named witness groups and weights and may contain nested expressions, for example ` +
+		`"Group(regulator,https://regulator.com/services/orb) 2 of {https://w1.com/services/orb,` +
+		`https://w2.com/services/orb,https://w3.com/services/orb} AND (regulator OR MinPercent(50,system))".` +
 		" Alternatively, this can be set with the following environment variable: " + policyEnvKey
+
+	policyFileFlagName  = "policy-file"
+	policyFileEnvKey    = "ORB_CLI_POLICY_FILE"
+	policyFileFlagUsage = "The path of a file that contains the witness policy. This flag is useful for policies " +
+		"that span multiple lines. If set then the policy flag is ignored." +
+		" Alternatively, this can be set with the following environment variable: " + policyFileEnvKey
 )
 
 // GetCmd returns the Cobra policy command.
@@ -31,13 +40,17 @@ func GetCmd() *cobra.Command {
 		Short:        "Manages the witness policy.",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return errors.New("expecting subcommand update or get")
+			return errors.New("expecting subcommand update, get, explain, revoke, scoped or simulate")
 		},
 	}
 
 	cmd.AddCommand(
 		newUpdateCmd(),
 		newGetCmd(),
+		newExplainCmd(),
+		newRevokeCmd(),
+		newScopedCmd(),
+		newSimulateCmd(),
 	)
 
 	return cmd
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	revokeURLFlagUsage = "The URL of the witness revocations REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+
+	witnessFlagName  = "witness"
+	witnessEnvKey    = "ORB_CLI_WITNESS"
+	witnessFlagUsage = "The URI of the witness whose proofs are revoked." +
+		" Alternatively, this can be set with the following environment variable: " + witnessEnvKey
+
+	sinceFlagName  = "since"
+	sinceEnvKey    = "ORB_CLI_SINCE"
+	sinceFlagUsage = "The time (RFC3339) from which the proofs of the witness are untrusted, " +
+		"for example 2022-03-15T00:00:00Z. If not specified then all proofs of the witness are revoked." +
+		" Alternatively, this can be set with the following environment variable: " + sinceEnvKey
+
+	reasonFlagName  = "reason"
+	reasonEnvKey    = "ORB_CLI_REASON"
+	reasonFlagUsage = "The reason for revoking the witness." +
+		" Alternatively, this can be set with the following environment variable: " + reasonEnvKey
+)
+
+type revocationRequest struct {
+	Witness string     `json:"witness"`
+	Since   *time.Time `json:"since,omitempty"`
+	Reason  string     `json:"reason,omitempty"`
+}
+
+func newRevokeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revokes the proofs of a witness.",
+		Long: "Revokes the proofs of a witness (for example, if the witness' key was compromised). Anchors that were " +
+			"witnessed using a revoked proof are offered to other witnesses in order to obtain replacement proofs. " +
+			"For example: policy revoke --url https://orb.domain1.com/witness-revocations " +
+			"--witness https://orb.domain2.com/services/orb --since 2022-03-15T00:00:00Z --reason \"key compromised\"",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeRevoke(cmd)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", revokeURLFlagUsage)
+	cmd.Flags().StringP(witnessFlagName, "", "", witnessFlagUsage)
+	cmd.Flags().StringP(sinceFlagName, "", "", sinceFlagUsage)
+	cmd.Flags().StringP(reasonFlagName, "", "", reasonFlagUsage)
+
+	return cmd
+}
+
+func executeRevoke(cmd *cobra.Command) error {
+	u, request, err := getRevokeArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal revocation request: %w", err)
+	}
+
+	resp, err := common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(resp))
+
+	return nil
+}
+
+func getRevokeArgs(cmd *cobra.Command) (string, *revocationRequest, error) {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	_, err = url.Parse(u)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	witness, err := cmdutil.GetUserSetVarFromString(cmd, witnessFlagName, witnessEnvKey, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	request := &revocationRequest{
+		Witness: witness,
+		Reason:  cmdutil.GetUserSetOptionalVarFromString(cmd, reasonFlagName, reasonEnvKey),
+	}
+
+	if sinceStr := cmdutil.GetUserSetOptionalVarFromString(cmd, sinceFlagName, sinceEnvKey); sinceStr != "" {
+		since, e := time.Parse(time.RFC3339, sinceStr)
+		if e != nil {
+			return "", nil, fmt.Errorf("invalid since %s: %w", sinceStr, e)
+		}
+
+		request.Since = &since
+	}
+
+	return u, request, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testWitness = "https://orb.domain2.com/services/orb"
+
+func TestRevokeCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"revoke"})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test missing witness arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"revoke"}
+		args = append(args, urlArg("https://orb.domain1.com/witness-revocations")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither witness (command line flag) nor ORB_CLI_WITNESS (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid url arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"revoke"}
+		args = append(args, urlArg(":invalid")...)
+		args = append(args, witnessArg(testWitness)...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL")
+	})
+
+	t.Run("test invalid since arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"revoke"}
+		args = append(args, urlArg("https://orb.domain1.com/witness-revocations")...)
+		args = append(args, witnessArg(testWitness)...)
+		args = append(args, flag+sinceFlagName, "yesterday")
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid since")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			request := &revocationRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, request))
+			require.Equal(t, testWitness, request.Witness)
+			require.NotNil(t, request.Since)
+			require.Equal(t, "key compromised", request.Reason)
+
+			_, err = fmt.Fprint(w, `{"witness":"`+testWitness+`"}`)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"revoke"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, witnessArg(testWitness)...)
+		args = append(args, flag+sinceFlagName, "2022-03-15T00:00:00Z")
+		args = append(args, flag+reasonFlagName, "key compromised")
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.NoError(t, err)
+	})
+}
+
+func witnessArg(value string) []string {
+	return []string{flag + witnessFlagName, value}
+}
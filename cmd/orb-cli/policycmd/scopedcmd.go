@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	scopedURLFlagUsage = "The URL of the scoped witness policy REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+
+	namespaceFlagName  = "namespace"
+	namespaceEnvKey    = "ORB_CLI_NAMESPACE"
+	namespaceFlagUsage = "The DID namespace of the scope, for example did:orb." +
+		" Alternatively, this can be set with the following environment variable: " + namespaceEnvKey
+
+	originFlagName  = "origin"
+	originEnvKey    = "ORB_CLI_ORIGIN"
+	originFlagUsage = "The anchor origin of the scope, for example https://orb.domain1.com." +
+		" Alternatively, this can be set with the following environment variable: " + originEnvKey
+)
+
+type scopedPolicy struct {
+	Namespace string `json:"namespace,omitempty"`
+	Origin    string `json:"origin,omitempty"`
+	Policy    string `json:"policy"`
+}
+
+func newScopedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "scoped",
+		Short:        "Manages the witness policies for DID namespaces and anchor origins.",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("expecting subcommand update, get or delete")
+		},
+	}
+
+	cmd.AddCommand(
+		newScopedUpdateCmd(),
+		newScopedGetCmd(),
+		newScopedDeleteCmd(),
+	)
+
+	return cmd
+}
+
+func newScopedUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Updates the witness policy for a DID namespace and/or anchor origin.",
+		Long: `Updates the witness policy for a DID namespace and/or anchor origin. For example: policy scoped update ` +
+			`--namespace did:orb --origin https://orb.domain2.com --policy "OutOf(2,system)" ` +
+			`--url https://orb.domain1.com/policy/scoped`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeScopedUpdate(cmd)
+		},
+	}
+
+	addUpdateFlags(cmd)
+	addScopeFlags(cmd)
+
+	cmd.Flags().Lookup(urlFlagName).Usage = scopedURLFlagUsage
+
+	return cmd
+}
+
+func newScopedGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Retrieves the scoped witness policies.",
+		Long: `Retrieves the scoped witness policies. If namespace and/or origin is specified then only the policy ` +
+			`for that scope is retrieved. For example: policy scoped get --namespace did:orb ` +
+			`--url https://orb.domain1.com/policy/scoped`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeScoped(cmd, http.MethodGet, false)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", scopedURLFlagUsage)
+	addScopeFlags(cmd)
+
+	return cmd
+}
+
+func newScopedDeleteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Deletes the witness policy for a DID namespace and/or anchor origin.",
+		Long: `Deletes the witness policy for a DID namespace and/or anchor origin. For example: policy scoped delete ` +
+			`--origin https://orb.domain2.com --url https://orb.domain1.com/policy/scoped`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeScoped(cmd, http.MethodDelete, true)
+		},
+	}
+
+	common.AddCommonFlags(cmd)
+
+	cmd.Flags().StringP(urlFlagName, "", "", scopedURLFlagUsage)
+	addScopeFlags(cmd)
+
+	return cmd
+}
+
+func addScopeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP(namespaceFlagName, "", "", namespaceFlagUsage)
+	cmd.Flags().StringP(originFlagName, "", "", originFlagUsage)
+}
+
+func executeScopedUpdate(cmd *cobra.Command) error {
+	u, policy, err := getUpdateArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	namespace, origin, err := getScopeArgs(cmd, true)
+	if err != nil {
+		return err
+	}
+
+	reqBytes, err := json.Marshal(&scopedPolicy{
+		Namespace: namespace,
+		Origin:    origin,
+		Policy:    policy,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal scoped policy: %w", err)
+	}
+
+	_, err = common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Scoped witness policy has successfully been updated.")
+
+	return nil
+}
+
+func executeScoped(cmd *cobra.Command, method string, scopeRequired bool) error {
+	u, err := cmdutil.GetUserSetVarFromString(cmd, urlFlagName, urlEnvKey, false)
+	if err != nil {
+		return err
+	}
+
+	scopedURL, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("invalid URL %s: %w", u, err)
+	}
+
+	namespace, origin, err := getScopeArgs(cmd, scopeRequired)
+	if err != nil {
+		return err
+	}
+
+	query := scopedURL.Query()
+
+	if namespace != "" {
+		query.Set(namespaceFlagName, namespace)
+	}
+
+	if origin != "" {
+		query.Set(originFlagName, origin)
+	}
+
+	scopedURL.RawQuery = query.Encode()
+
+	resp, err := common.SendHTTPRequest(cmd, nil, method, scopedURL.String())
+	if err != nil {
+		return err
+	}
+
+	if method == http.MethodDelete {
+		fmt.Println("Scoped witness policy has successfully been deleted.")
+	} else {
+		fmt.Println(string(resp))
+	}
+
+	return nil
+}
+
+func getScopeArgs(cmd *cobra.Command, required bool) (namespace, origin string, err error) {
+	namespace = cmdutil.GetUserSetOptionalVarFromString(cmd, namespaceFlagName, namespaceEnvKey)
+	origin = cmdutil.GetUserSetOptionalVarFromString(cmd, originFlagName, originEnvKey)
+
+	if required && namespace == "" && origin == "" {
+		return "", "", fmt.Errorf("either %s or %s must be set", namespaceFlagName, originFlagName)
+	}
+
+	return namespace, origin, nil
+}
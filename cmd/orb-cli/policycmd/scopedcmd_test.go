@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopedCmd(t *testing.T) {
+	t.Run("test missing subcommand", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"scoped"})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting subcommand update, get or delete")
+	})
+
+	t.Run("update - missing scope", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"scoped", "update"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, policyArg("OutOf(2,system)")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t, "either namespace or origin must be set", err.Error())
+	})
+
+	t.Run("update - success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			p := &scopedPolicy{}
+			require.NoError(t, json.Unmarshal(reqBytes, p))
+			require.Equal(t, "did:orb", p.Namespace)
+			require.Equal(t, "https://orb.domain2.com", p.Origin)
+			require.Equal(t, "OutOf(2,system)", p.Policy)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"scoped", "update"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, policyArg("OutOf(2,system)")...)
+		args = append(args, namespaceArg("did:orb")...)
+		args = append(args, originArg("https://orb.domain2.com")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("get - success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Equal(t, "did:orb", r.URL.Query().Get(namespaceFlagName))
+
+			_, err := fmt.Fprint(w, `[{"namespace":"did:orb","policy":"OutOf(2,system)"}]`)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"scoped", "get"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, namespaceArg("did:orb")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+	})
+
+	t.Run("get - invalid url arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"scoped", "get"}
+		args = append(args, urlArg(":invalid")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid URL")
+	})
+
+	t.Run("delete - missing scope", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"scoped", "delete"}
+		args = append(args, urlArg("localhost:8080")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Equal(t, "either namespace or origin must be set", err.Error())
+	})
+
+	t.Run("delete - success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			require.Equal(t, "https://orb.domain2.com", r.URL.Query().Get(originFlagName))
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"scoped", "delete"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, originArg("https://orb.domain2.com")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+	})
+}
+
+func namespaceArg(value string) []string {
+	return []string{flag + namespaceFlagName, value}
+}
+
+func originArg(value string) []string {
+	return []string{flag + originFlagName, value}
+}
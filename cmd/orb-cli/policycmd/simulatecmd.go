@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/trustbloc/orb/cmd/orb-cli/common"
+	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+)
+
+const (
+	simulateURLFlagUsage = "The URL of the witness policy simulation REST endpoint." +
+		" Alternatively, this can be set with the following environment variable: " + urlEnvKey
+
+	anchorsFlagName  = "anchors"
+	anchorsEnvKey    = "ORB_CLI_ANCHORS"
+	anchorsFlagUsage = "The maximum number of recent anchors against which the policy is evaluated (default 100)." +
+		" Alternatively, this can be set with the following environment variable: " + anchorsEnvKey
+)
+
+type simulationRequest struct {
+	Policy  string `json:"policy"`
+	Anchors int    `json:"anchors,omitempty"`
+}
+
+func newSimulateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulates a witness policy against the recent anchors.",
+		Long: "Simulates a candidate witness policy against the recent anchors without changing the current " +
+			"witness policy. The response includes the number of anchors that would have completed or stalled and " +
+			"how long the completed anchors would have taken. For example: policy simulate " +
+			`--policy "OutOf(2,system)" --anchors 50 --url https://orb.domain1.com/policy/simulate`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeSimulate(cmd)
+		},
+	}
+
+	addUpdateFlags(cmd)
+
+	cmd.Flags().Lookup(urlFlagName).Usage = simulateURLFlagUsage
+	cmd.Flags().StringP(anchorsFlagName, "", "", anchorsFlagUsage)
+
+	return cmd
+}
+
+func executeSimulate(cmd *cobra.Command) error {
+	u, policy, err := getUpdateArgs(cmd)
+	if err != nil {
+		return err
+	}
+
+	request := &simulationRequest{
+		Policy: policy,
+	}
+
+	if anchorsStr := cmdutil.GetUserSetOptionalVarFromString(cmd, anchorsFlagName, anchorsEnvKey); anchorsStr != "" {
+		request.Anchors, err = strconv.Atoi(anchorsStr)
+		if err != nil {
+			return fmt.Errorf("invalid anchors %s: %w", anchorsStr, err)
+		}
+	}
+
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal simulation request: %w", err)
+	}
+
+	resp, err := common.SendHTTPRequest(cmd, reqBytes, http.MethodPost, u)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(resp))
+
+	return nil
+}
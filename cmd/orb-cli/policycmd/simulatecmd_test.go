@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policycmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateCmd(t *testing.T) {
+	t.Run("test missing url arg", func(t *testing.T) {
+		cmd := GetCmd()
+		cmd.SetArgs([]string{"simulate"})
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Equal(t,
+			"Neither url (command line flag) nor ORB_CLI_URL (environment variable) have been set.",
+			err.Error())
+	})
+
+	t.Run("test invalid anchors arg", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"simulate"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, policyArg("OutOf(2,system)")...)
+		args = append(args, anchorsArg("xxx")...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid anchors")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqBytes, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			request := &simulationRequest{}
+			require.NoError(t, json.Unmarshal(reqBytes, request))
+			require.Equal(t, "OutOf(2,system)", request.Policy)
+			require.Equal(t, 50, request.Anchors)
+
+			_, err = fmt.Fprint(w, `{"total":1,"completed":1}`)
+			require.NoError(t, err)
+		}))
+		defer serv.Close()
+
+		cmd := GetCmd()
+
+		args := []string{"simulate"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, policyArg("OutOf(2,system)")...)
+		args = append(args, anchorsArg("50")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+	})
+}
+
+func anchorsArg(value string) []string {
+	return []string{flag + anchorsFlagName, value}
+}
@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -22,7 +25,8 @@ func newUpdateCmd() *cobra.Command {
 		Use:   "update",
 		Short: "Updates the witness policy.",
 		Long: `Updates the witness policy. For example: policy update ` +
-			`--policy "MinPercent(100,batch) AND OutOf(1,system)" --url https://orb.domain1.com/policy`,
+			`--policy "MinPercent(100,batch) AND OutOf(1,system)" --url https://orb.domain1.com/policy` +
+			` or policy update --policy-file ./policy.txt --url https://orb.domain1.com/policy`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return executeUpdate(cmd)
@@ -55,6 +59,7 @@ func addUpdateFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringP(urlFlagName, "", "", urlFlagUsage)
 	cmd.Flags().StringP(policyFlagName, "", "", policyFlagUsage)
+	cmd.Flags().StringP(policyFileFlagName, "", "", policyFileFlagUsage)
 }
 
 func getUpdateArgs(cmd *cobra.Command) (u, policy string, err error) {
@@ -68,10 +73,24 @@ func getUpdateArgs(cmd *cobra.Command) (u, policy string, err error) {
 		return "", "", fmt.Errorf("invalid URL %s: %w", u, err)
 	}
 
-	policy, err = cmdutil.GetUserSetVarFromString(cmd, policyFlagName, policyEnvKey, false)
+	policy, err = getPolicy(cmd)
 	if err != nil {
 		return "", "", err
 	}
 
 	return u, policy, nil
 }
+
+func getPolicy(cmd *cobra.Command) (string, error) {
+	policyFile := cmdutil.GetUserSetOptionalVarFromString(cmd, policyFileFlagName, policyFileEnvKey)
+	if policyFile == "" {
+		return cmdutil.GetUserSetVarFromString(cmd, policyFlagName, policyEnvKey, false)
+	}
+
+	policyBytes, err := os.ReadFile(filepath.Clean(policyFile))
+	if err != nil {
+		return "", fmt.Errorf("read policy file %s: %w", policyFile, err)
+	}
+
+	return strings.TrimSpace(string(policyBytes)), nil
+}
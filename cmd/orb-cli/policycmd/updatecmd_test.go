@@ -7,8 +7,11 @@ package policycmd
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -80,6 +83,47 @@ func TestUpdateCmd(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("update from policy file -> success", func(t *testing.T) {
+		const policy = "Group(regulator,https://regulator.com/services/orb)\n" +
+			"2 of {https://w1.com/services/orb,https://w2.com/services/orb} AND regulator"
+
+		var received string
+
+		serv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			received = string(b)
+		}))
+
+		policyFile := filepath.Join(t.TempDir(), "policy.txt")
+		require.NoError(t, os.WriteFile(policyFile, []byte(policy+"\n"), 0o600))
+
+		cmd := GetCmd()
+
+		args := []string{"update"}
+		args = append(args, urlArg(serv.URL)...)
+		args = append(args, policyFileArg(policyFile)...)
+		args = append(args, authTokenArg("ADMIN_TOKEN")...)
+		cmd.SetArgs(args)
+
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, policy, received)
+	})
+
+	t.Run("policy file not found", func(t *testing.T) {
+		cmd := GetCmd()
+
+		args := []string{"update"}
+		args = append(args, urlArg("localhost:8080")...)
+		args = append(args, policyFileArg(filepath.Join(t.TempDir(), "missing.txt"))...)
+		cmd.SetArgs(args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read policy file")
+	})
 }
 
 func urlArg(value string) []string {
@@ -90,6 +134,10 @@ func policyArg(value string) []string {
 	return []string{flag + policyFlagName, value}
 }
 
+func policyFileArg(value string) []string {
+	return []string{flag + policyFileFlagName, value}
+}
+
 func authTokenArg(value string) []string {
 	return []string{flag + common.AuthTokenFlagName, value}
 }
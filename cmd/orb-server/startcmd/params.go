@@ -20,6 +20,13 @@ import (
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/ratelimiter"
+	"github.com/trustbloc/orb/pkg/anchor/witness/admission"
+	"github.com/trustbloc/orb/pkg/anchor/writer/splitter"
+	s3cas "github.com/trustbloc/orb/pkg/cas/blob/s3"
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/gc"
+	"github.com/trustbloc/orb/pkg/cas/scrub"
 	"github.com/trustbloc/orb/pkg/context/opqueue"
 	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/document/util"
@@ -292,9 +299,111 @@ const (
 	casTypeFlagShorthand = "c"
 	casTypeEnvKey        = "CAS_TYPE"
 	casTypeFlagUsage     = "The type of the Content Addressable Storage (CAS). " +
-		"Supported options: local, ipfs. For local, the storage provider specified by " + databaseTypeFlagName +
+		"Supported options: local, ipfs, filesystem, s3. For local, the storage provider specified by " + databaseTypeFlagName +
 		" will be used. For ipfs, the node specified by " + ipfsURLFlagName +
-		" will be used. This is a required parameter. " + commonEnvVarUsageText + casTypeEnvKey
+		" will be used. For filesystem, content is stored as files in the directory specified by " + casFSDirFlagName +
+		". For s3, content is stored as objects in the S3-compatible bucket specified by " + casS3BucketFlagName +
+		". This is a required parameter. " + commonEnvVarUsageText + casTypeEnvKey
+
+	casTypeFilesystem = "filesystem"
+	casTypeS3         = "s3"
+
+	casFSDirFlagName  = "cas-fs-dir"
+	casFSDirEnvKey    = "CAS_FS_DIR"
+	casFSDirFlagUsage = "The directory in which CAS content is stored. Required if the CAS type is filesystem. " +
+		commonEnvVarUsageText + casFSDirEnvKey
+
+	casS3EndpointFlagName  = "cas-s3-endpoint"
+	casS3EndpointEnvKey    = "CAS_S3_ENDPOINT"
+	casS3EndpointFlagUsage = "The base URL of the S3-compatible object store, e.g. https://s3.us-east-1.amazonaws.com. " +
+		"Objects are addressed using path-style URLs. Required if the CAS type is s3. " +
+		commonEnvVarUsageText + casS3EndpointEnvKey
+
+	casS3RegionFlagName  = "cas-s3-region"
+	casS3RegionEnvKey    = "CAS_S3_REGION"
+	casS3RegionFlagUsage = "The region of the S3-compatible object store. Defaults to us-east-1. " +
+		commonEnvVarUsageText + casS3RegionEnvKey
+
+	casS3BucketFlagName  = "cas-s3-bucket"
+	casS3BucketEnvKey    = "CAS_S3_BUCKET"
+	casS3BucketFlagUsage = "The bucket in which CAS content is stored. Required if the CAS type is s3. " +
+		commonEnvVarUsageText + casS3BucketEnvKey
+
+	casS3KeyPrefixFlagName  = "cas-s3-key-prefix"
+	casS3KeyPrefixEnvKey    = "CAS_S3_KEY_PREFIX"
+	casS3KeyPrefixFlagUsage = "An optional prefix that is prepended to the keys of CAS objects, e.g. cas/. " +
+		commonEnvVarUsageText + casS3KeyPrefixEnvKey
+
+	casS3AccessKeyIDFlagName  = "cas-s3-access-key-id"
+	casS3AccessKeyIDEnvKey    = "CAS_S3_ACCESS_KEY_ID"
+	casS3AccessKeyIDFlagUsage = "The access key ID used to sign requests to the S3-compatible object store. " +
+		"If not set then requests are not signed. " + commonEnvVarUsageText + casS3AccessKeyIDEnvKey
+
+	casS3SecretAccessKeyFlagName  = "cas-s3-secret-access-key" //nolint:gosec
+	casS3SecretAccessKeyEnvKey    = "CAS_S3_SECRET_ACCESS_KEY" //nolint:gosec
+	casS3SecretAccessKeyFlagUsage = "The secret access key used to sign requests to the S3-compatible object store. " +
+		commonEnvVarUsageText + casS3SecretAccessKeyEnvKey
+
+	casGCEnabledFlagName  = "cas-gc-enabled"
+	casGCEnabledEnvKey    = "CAS_GC_ENABLED"
+	casGCEnabledFlagUsage = "Enables garbage collection of unreferenced content in the local CAS. " +
+		"Content that isn't reachable from any live anchor is deleted (or archived) after the grace period. " +
+		"Only applies if the CAS type is local. Defaults to false. " + commonEnvVarUsageText + casGCEnabledEnvKey
+
+	casGCIntervalFlagName  = "cas-gc-interval"
+	casGCIntervalEnvKey    = "CAS_GC_INTERVAL"
+	casGCIntervalFlagUsage = "The interval at which CAS garbage collection runs. Defaults to 24h. " +
+		commonEnvVarUsageText + casGCIntervalEnvKey
+
+	casGCGracePeriodFlagName  = "cas-gc-grace-period"
+	casGCGracePeriodEnvKey    = "CAS_GC_GRACE_PERIOD"
+	casGCGracePeriodFlagUsage = "The minimum age of unreferenced CAS content before it's garbage collected. " +
+		"This must be long enough for a batch to be anchored and witnessed. Defaults to 168h (7 days). " +
+		commonEnvVarUsageText + casGCGracePeriodEnvKey
+
+	casGCMaxSweepCountFlagName  = "cas-gc-max-sweep-count"
+	casGCMaxSweepCountEnvKey    = "CAS_GC_MAX_SWEEP_COUNT"
+	casGCMaxSweepCountFlagUsage = "The maximum number of items of CAS content that are garbage collected in a " +
+		"single run. Defaults to 10000. " + commonEnvVarUsageText + casGCMaxSweepCountEnvKey
+
+	casGCDryRunFlagName  = "cas-gc-dry-run"
+	casGCDryRunEnvKey    = "CAS_GC_DRY_RUN"
+	casGCDryRunFlagUsage = "If true then CAS garbage collection only reports (logs) unreferenced content and " +
+		"doesn't delete it. Defaults to false. " + commonEnvVarUsageText + casGCDryRunEnvKey
+
+	casGCArchiveDirFlagName  = "cas-gc-archive-dir"
+	casGCArchiveDirEnvKey    = "CAS_GC_ARCHIVE_DIR"
+	casGCArchiveDirFlagUsage = "An optional directory to which unreferenced CAS content is copied before it's " +
+		"deleted by garbage collection. " + commonEnvVarUsageText + casGCArchiveDirEnvKey
+
+	casScrubEnabledFlagName  = "cas-scrub-enabled"
+	casScrubEnabledEnvKey    = "CAS_SCRUB_ENABLED"
+	casScrubEnabledFlagUsage = "Enables the content-integrity scrubber for the local CAS. Content that doesn't match " +
+		"its resource hash is quarantined and re-fetched from IPFS (if an IPFS URL is configured) or from peer " +
+		"WebCAS endpoints. Only applies if the CAS type is local. Defaults to false. " +
+		commonEnvVarUsageText + casScrubEnabledEnvKey
+
+	casScrubIntervalFlagName  = "cas-scrub-interval"
+	casScrubIntervalEnvKey    = "CAS_SCRUB_INTERVAL"
+	casScrubIntervalFlagUsage = "The interval at which the CAS scrubber runs. Defaults to 1h. " +
+		commonEnvVarUsageText + casScrubIntervalEnvKey
+
+	casScrubMaxItemsPerRunFlagName  = "cas-scrub-max-items-per-run"
+	casScrubMaxItemsPerRunEnvKey    = "CAS_SCRUB_MAX_ITEMS_PER_RUN"
+	casScrubMaxItemsPerRunFlagUsage = "The maximum number of items of CAS content that are verified in a single " +
+		"scrub run. The next run resumes from where the previous run stopped. Defaults to 1000. " +
+		commonEnvVarUsageText + casScrubMaxItemsPerRunEnvKey
+
+	casScrubMaxRepairAttemptsFlagName  = "cas-scrub-max-repair-attempts"
+	casScrubMaxRepairAttemptsEnvKey    = "CAS_SCRUB_MAX_REPAIR_ATTEMPTS"
+	casScrubMaxRepairAttemptsFlagUsage = "The maximum number of attempts to re-fetch corrupted CAS content before " +
+		"it's marked as failed. Defaults to 10. " + commonEnvVarUsageText + casScrubMaxRepairAttemptsEnvKey
+
+	casScrubPeerWebCASURLsFlagName  = "cas-scrub-peer-webcas-urls"
+	casScrubPeerWebCASURLsEnvKey    = "CAS_SCRUB_PEER_WEBCAS_URLS"
+	casScrubPeerWebCASURLsFlagUsage = "A comma-separated list of peer WebCAS endpoints " +
+		"(e.g. https://orb.domain2.com/cas) from which corrupted CAS content is re-fetched. " +
+		commonEnvVarUsageText + casScrubPeerWebCASURLsEnvKey
 
 	ipfsURLFlagName      = "ipfs-url"
 	ipfsURLFlagShorthand = "r"
@@ -426,12 +535,36 @@ const (
 	cidVersionFlagUsage = "The version of the CID format to use for generating CIDs. " +
 		"Supported options: 0, 1. If not set, defaults to 1." + commonEnvVarUsageText + cidVersionEnvKey
 
+	cidMultibaseFlagName  = "cid-multibase"
+	cidMultibaseEnvKey    = "CID_MULTIBASE"
+	cidMultibaseFlagUsage = "The multibase used to encode v1 CIDs and hashlink resource hashes. " +
+		"Supported options: base32, base58btc, base64url. If not set, v1 CIDs are encoded using base32 and " +
+		"resource hashes are encoded using base64url." + commonEnvVarUsageText + cidMultibaseEnvKey
+
 	batchWriterTimeoutFlagName      = "batch-writer-timeout"
 	batchWriterTimeoutFlagShorthand = "b"
 	batchWriterTimeoutEnvKey        = "BATCH_WRITER_TIMEOUT"
 	batchWriterTimeoutFlagUsage     = "Maximum time (in millisecond) in-between cutting batches." +
 		commonEnvVarUsageText + batchWriterTimeoutEnvKey
 
+	anchorMaxOperationsFlagName  = "anchor-max-operations"
+	anchorMaxOperationsEnvKey    = "ANCHOR_MAX_OPERATIONS"
+	anchorMaxOperationsFlagUsage = "The maximum number of operations in an anchor. A batch with more operations " +
+		"is split into multiple anchors. Defaults to 0 (no limit other than the Sidetree protocol limit) if not set. " +
+		commonEnvVarUsageText + anchorMaxOperationsEnvKey
+
+	anchorMaxSizeFlagName  = "anchor-max-size"
+	anchorMaxSizeEnvKey    = "ANCHOR_MAX_SIZE"
+	anchorMaxSizeFlagUsage = "The maximum total size (in bytes) of the operations in an anchor. A larger batch " +
+		"is split into multiple anchors. Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + anchorMaxSizeEnvKey
+
+	anchorMaxWitnessesFlagName  = "anchor-max-witnesses"
+	anchorMaxWitnessesEnvKey    = "ANCHOR_MAX_WITNESSES"
+	anchorMaxWitnessesFlagUsage = "The maximum number of distinct batch witnesses (anchor origins) in an anchor. " +
+		"A batch with more witnesses is split into multiple anchors. Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + anchorMaxWitnessesEnvKey
+
 	databaseTypeFlagName      = "database-type"
 	databaseTypeEnvKey        = "DATABASE_TYPE"
 	databaseTypeFlagShorthand = "t"
@@ -500,6 +633,37 @@ const (
 	signWithLocalWitnessFlagUsage     = "Always sign with local witness flag (default true). " +
 		commonEnvVarUsageText + signWithLocalWitnessEnvKey
 
+	witnessSelectorFlagName  = "witness-selector"
+	witnessSelectorEnvKey    = "WITNESS_SELECTOR"
+	witnessSelectorFlagUsage = "The algorithm used to select witnesses. Supported values are 'random' and 'scored'. " +
+		"The 'random' selector selects witnesses randomly. The 'scored' selector prefers witnesses that have " +
+		"historically returned proofs quickly and reliably (default random). " +
+		commonEnvVarUsageText + witnessSelectorEnvKey
+
+	offerQuotaPerMinuteFlagName  = "offer-quota-per-minute"
+	offerQuotaPerMinuteEnvKey    = "OFFER_QUOTA_PER_MINUTE"
+	offerQuotaPerMinuteFlagUsage = "The maximum number of 'Offer' activities that this witness accepts from a single " +
+		"actor per minute. Offers that exceed the quota are rejected. Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + offerQuotaPerMinuteEnvKey
+
+	offerQuotaPerDayFlagName  = "offer-quota-per-day"
+	offerQuotaPerDayEnvKey    = "OFFER_QUOTA_PER_DAY"
+	offerQuotaPerDayFlagUsage = "The maximum number of 'Offer' activities that this witness accepts from a single " +
+		"actor per day. Offers that exceed the quota are rejected. Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + offerQuotaPerDayEnvKey
+
+	maxOfferedAnchorSizeFlagName  = "max-offered-anchor-size"
+	maxOfferedAnchorSizeEnvKey    = "MAX_OFFERED_ANCHOR_SIZE"
+	maxOfferedAnchorSizeFlagUsage = "The maximum size (in bytes) of an anchor offered to this witness. " +
+		"Offers with larger anchors are rejected. Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + maxOfferedAnchorSizeEnvKey
+
+	offerOriginAllowListEnabledFlagName  = "offer-origin-allow-list-enabled"
+	offerOriginAllowListEnabledEnvKey    = "OFFER_ORIGIN_ALLOW_LIST_ENABLED"
+	offerOriginAllowListEnabledFlagUsage = "If true then this witness only accepts 'Offer' activities from actors " +
+		"(anchor origins) in the 'anchor-origin' accept list. Defaults to false if not set. " +
+		commonEnvVarUsageText + offerOriginAllowListEnabledEnvKey
+
 	discoveryDomainsFlagName  = "discovery-domains"
 	discoveryDomainsEnvKey    = "DISCOVERY_DOMAINS"
 	discoveryDomainsFlagUsage = "Discovery domains. " + commonEnvVarUsageText + discoveryDomainsEnvKey
@@ -515,6 +679,18 @@ const (
 	httpSignaturesEnabledUsage     = `Set to "true" to enable HTTP signatures in ActivityPub. ` +
 		commonEnvVarUsageText + httpSignaturesEnabledEnvKey
 
+	activityProofsEnabledFlagName = "enable-activity-proofs"
+	activityProofsEnabledEnvKey   = "ACTIVITY_PROOFS_ENABLED"
+	activityProofsEnabledUsage    = `Set to "true" to embed a Linked Data proof (signed with the HTTP signature key) ` +
+		`in each activity posted to the outbox and to verify the proof of inbound and synced activities ` +
+		`(if present). ` + commonEnvVarUsageText + activityProofsEnabledEnvKey
+
+	activityProofsRequiredFlagName = "activity-proofs-required"
+	activityProofsRequiredEnvKey   = "ACTIVITY_PROOFS_REQUIRED"
+	activityProofsRequiredUsage    = `Set to "true" to reject inbound and synced activities that don't contain ` +
+		`a Linked Data proof. This flag is only applicable if activity proofs are enabled. ` +
+		commonEnvVarUsageText + activityProofsRequiredEnvKey
+
 	enableDidDiscoveryFlagName = "enable-did-discovery"
 	enableDidDiscoveryEnvKey   = "DID_DISCOVERY_ENABLED"
 	enableDidDiscoveryUsage    = `Set to "true" to enable did discovery. ` +
@@ -698,6 +874,31 @@ const (
 	activityPubIRICacheExpirationFlagUsage = "The expiration time of an ActivityPub actor IRI cache. " +
 		commonEnvVarUsageText + activityPubIRICacheExpirationEnvKey
 
+	inboxActorRateLimitFlagName  = "inbox-actor-rate-limit"
+	inboxActorRateLimitEnvKey    = "ACTIVITYPUB_INBOX_ACTOR_RATE_LIMIT"
+	inboxActorRateLimitFlagUsage = "The number of requests per second that a single actor (resolved from the HTTP signature) " +
+		"may post to the inbox. Requests that exceed the limit are rejected with status 429 (Too Many Requests). " +
+		"Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + inboxActorRateLimitEnvKey
+
+	inboxActorRateBurstFlagName  = "inbox-actor-rate-burst"
+	inboxActorRateBurstEnvKey    = "ACTIVITYPUB_INBOX_ACTOR_RATE_BURST"
+	inboxActorRateBurstFlagUsage = "The maximum number of requests that a single actor may post to the inbox in a burst. " +
+		"Defaults to 1 if not set. " +
+		commonEnvVarUsageText + inboxActorRateBurstEnvKey
+
+	inboxGlobalRateLimitFlagName  = "inbox-global-rate-limit"
+	inboxGlobalRateLimitEnvKey    = "ACTIVITYPUB_INBOX_GLOBAL_RATE_LIMIT"
+	inboxGlobalRateLimitFlagUsage = "The number of requests per second that may be posted to the inbox by all actors combined. " +
+		"Requests that exceed the limit are rejected with status 429 (Too Many Requests). Defaults to 0 (no limit) if not set. " +
+		commonEnvVarUsageText + inboxGlobalRateLimitEnvKey
+
+	inboxGlobalRateBurstFlagName  = "inbox-global-rate-burst"
+	inboxGlobalRateBurstEnvKey    = "ACTIVITYPUB_INBOX_GLOBAL_RATE_BURST"
+	inboxGlobalRateBurstFlagUsage = "The maximum number of requests that may be posted to the inbox in a burst by all actors combined. " +
+		"Defaults to 1 if not set. " +
+		commonEnvVarUsageText + inboxGlobalRateBurstEnvKey
+
 	serverIdleTimeoutFlagName  = "server-idle-timeout"
 	serverIdleTimeoutEnvKey    = "SERVER_IDLE_TIMEOUT"
 	serverIdleTimeoutFlagUsage = "The timeout for server idle timeout. For example, '30s' for a 30 second timeout. " +
@@ -767,6 +968,13 @@ const (
 		commonEnvVarUsageText + tracingServiceNameEnvKey
 )
 
+type witnessSelectorType string
+
+const (
+	randomWitnessSelector witnessSelectorType = "random"
+	scoredWitnessSelector witnessSelectorType = "scored"
+)
+
 type acceptRejectPolicy string
 
 const (
@@ -788,6 +996,7 @@ type orbParameters struct {
 	discoveryDomain                string
 	dataURIMediaType               datauri.MediaType
 	batchWriterTimeout             time.Duration
+	anchorLimits                   splitter.Config
 	cas                            *casParams
 	mqParams                       *mqParams
 	opQueueParams                  *opqueue.Config
@@ -1009,6 +1218,11 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 
 	opQueueParams.BatchWriterTimeout = batchWriterTimeout
 
+	anchorLimits, err := getAnchorLimitsParams(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	witnessProofParams, err := getWitnessProofParams(cmd)
 	if err != nil {
 		return nil, err
@@ -1184,6 +1398,7 @@ func getOrbParameters(cmd *cobra.Command) (*orbParameters, error) {
 		mqParams:                       mqParams,
 		opQueueParams:                  opQueueParams,
 		batchWriterTimeout:             batchWriterTimeout,
+		anchorLimits:                   anchorLimits,
 		anchorCredentialParams:         anchorCredentialParams,
 		logLevel:                       loggingLevel,
 		dbParameters:                   dbParams,
@@ -1318,7 +1533,23 @@ type casParams struct {
 	ipfsURL                        string
 	localCASReplicateInIPFSEnabled bool
 	cidVersion                     int
+	cidMultibase                   extendedcasclient.Multibase
 	ipfsTimeout                    time.Duration
+	fsDir                          string
+	s3                             s3cas.Config
+	gc                             *casGCParams
+	scrub                          *casScrubParams
+}
+
+type casGCParams struct {
+	enabled    bool
+	config     gc.Config
+	archiveDir string
+}
+
+type casScrubParams struct {
+	enabled bool
+	config  scrub.Config
 }
 
 func getCASParams(cmd *cobra.Command) (*casParams, error) {
@@ -1373,21 +1604,194 @@ func getCASParams(cmd *cobra.Command) (*casParams, error) {
 		}
 	}
 
+	cidMultibase, err := getCIDFormatParams(cmd, cidVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	fsDir, s3Config, err := getBlobCASParams(cmd, casType)
+	if err != nil {
+		return nil, err
+	}
+
+	gcParams, err := getCASGCParams(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	scrubParams, err := getCASScrubParams(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	return &casParams{
 		casType:                        casType,
 		ipfsURL:                        ipfsURL,
 		ipfsTimeout:                    ipfsTimeout,
 		localCASReplicateInIPFSEnabled: localCASReplicateInIPFSEnabled,
 		cidVersion:                     cidVersion,
+		cidMultibase:                   cidMultibase,
+		fsDir:                          fsDir,
+		s3:                             s3Config,
+		gc:                             gcParams,
+		scrub:                          scrubParams,
 	}, nil
 }
 
+func getCASGCParams(cmd *cobra.Command) (*casGCParams, error) {
+	enabled, err := cmdutil.GetBool(cmd, casGCEnabledFlagName, casGCEnabledEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCEnabledFlagName, err)
+	}
+
+	interval, err := cmdutil.GetDuration(cmd, casGCIntervalFlagName, casGCIntervalEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCIntervalFlagName, err)
+	}
+
+	gracePeriod, err := cmdutil.GetDuration(cmd, casGCGracePeriodFlagName, casGCGracePeriodEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCGracePeriodFlagName, err)
+	}
+
+	maxSweepCount, err := cmdutil.GetInt(cmd, casGCMaxSweepCountFlagName, casGCMaxSweepCountEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCMaxSweepCountFlagName, err)
+	}
+
+	dryRun, err := cmdutil.GetBool(cmd, casGCDryRunFlagName, casGCDryRunEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCDryRunFlagName, err)
+	}
+
+	archiveDir, err := cmdutil.GetUserSetVarFromString(cmd, casGCArchiveDirFlagName, casGCArchiveDirEnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casGCArchiveDirFlagName, err)
+	}
+
+	return &casGCParams{
+		enabled: enabled,
+		config: gc.Config{
+			Interval:      interval,
+			GracePeriod:   gracePeriod,
+			MaxSweepCount: maxSweepCount,
+			DryRun:        dryRun,
+		},
+		archiveDir: archiveDir,
+	}, nil
+}
+
+func getCASScrubParams(cmd *cobra.Command) (*casScrubParams, error) {
+	enabled, err := cmdutil.GetBool(cmd, casScrubEnabledFlagName, casScrubEnabledEnvKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubEnabledFlagName, err)
+	}
+
+	interval, err := cmdutil.GetDuration(cmd, casScrubIntervalFlagName, casScrubIntervalEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubIntervalFlagName, err)
+	}
+
+	maxItemsPerRun, err := cmdutil.GetInt(cmd, casScrubMaxItemsPerRunFlagName, casScrubMaxItemsPerRunEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubMaxItemsPerRunFlagName, err)
+	}
+
+	maxRepairAttempts, err := cmdutil.GetInt(cmd, casScrubMaxRepairAttemptsFlagName,
+		casScrubMaxRepairAttemptsEnvKey, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubMaxRepairAttemptsFlagName, err)
+	}
+
+	peerWebCASURLs, err := cmdutil.GetUserSetVarFromArrayString(cmd, casScrubPeerWebCASURLsFlagName,
+		casScrubPeerWebCASURLsEnvKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", casScrubPeerWebCASURLsFlagName, err)
+	}
+
+	return &casScrubParams{
+		enabled: enabled,
+		config: scrub.Config{
+			Interval:          interval,
+			MaxItemsPerRun:    maxItemsPerRun,
+			MaxRepairAttempts: maxRepairAttempts,
+			PeerWebCASURLs:    peerWebCASURLs,
+		},
+	}, nil
+}
+
+func getCIDFormatParams(cmd *cobra.Command, cidVersion int) (extendedcasclient.Multibase, error) {
+	cidMultibase, err := cmdutil.GetUserSetVarFromString(cmd, cidMultibaseFlagName, cidMultibaseEnvKey, true)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = extendedcasclient.GetCIDFormatOptions(
+		extendedcasclient.WithCIDVersion(cidVersion),
+		extendedcasclient.WithMultibase(extendedcasclient.Multibase(cidMultibase)),
+	)
+	if err != nil {
+		return "", fmt.Errorf("invalid CID format: %w", err)
+	}
+
+	return extendedcasclient.Multibase(cidMultibase), nil
+}
+
+// cidFormatOptions returns the CID format options that are used for writing to the CAS. The codec isn't configurable
+// since most content (e.g. compressed Sidetree batch files) isn't encoded in a codec other than raw. The codec may
+// only be specified for an individual write (see extendedcasclient.WithCodec).
+func (p *casParams) cidFormatOptions() []extendedcasclient.CIDFormatOption {
+	return []extendedcasclient.CIDFormatOption{
+		extendedcasclient.WithCIDVersion(p.cidVersion),
+		extendedcasclient.WithMultibase(p.cidMultibase),
+	}
+}
+
+func getBlobCASParams(cmd *cobra.Command, casType string) (string, s3cas.Config, error) {
+	fsDir, err := cmdutil.GetUserSetVarFromString(cmd, casFSDirFlagName, casFSDirEnvKey, true)
+	if err != nil {
+		return "", s3cas.Config{}, err
+	}
+
+	if strings.EqualFold(casType, casTypeFilesystem) && fsDir == "" {
+		return "", s3cas.Config{}, fmt.Errorf("%s is required for CAS type %s", casFSDirFlagName, casTypeFilesystem)
+	}
+
+	s3Config := s3cas.Config{}
+
+	for _, p := range []struct {
+		flagName, envKey string
+		value            *string
+	}{
+		{casS3EndpointFlagName, casS3EndpointEnvKey, &s3Config.Endpoint},
+		{casS3RegionFlagName, casS3RegionEnvKey, &s3Config.Region},
+		{casS3BucketFlagName, casS3BucketEnvKey, &s3Config.Bucket},
+		{casS3KeyPrefixFlagName, casS3KeyPrefixEnvKey, &s3Config.KeyPrefix},
+		{casS3AccessKeyIDFlagName, casS3AccessKeyIDEnvKey, &s3Config.AccessKeyID},
+		{casS3SecretAccessKeyFlagName, casS3SecretAccessKeyEnvKey, &s3Config.SecretAccessKey},
+	} {
+		*p.value, err = cmdutil.GetUserSetVarFromString(cmd, p.flagName, p.envKey, true)
+		if err != nil {
+			return "", s3cas.Config{}, err
+		}
+	}
+
+	if strings.EqualFold(casType, casTypeS3) && (s3Config.Endpoint == "" || s3Config.Bucket == "") {
+		return "", s3cas.Config{}, fmt.Errorf("%s and %s are required for CAS type %s",
+			casS3EndpointFlagName, casS3BucketFlagName, casTypeS3)
+	}
+
+	return fsDir, s3Config, nil
+}
+
 type witnessProofParams struct {
 	maxWitnessDelay             time.Duration
 	maxClockSkew                time.Duration
 	witnessStoreExpiryPeriod    time.Duration
 	proofMonitoringExpiryPeriod time.Duration
 	signWithLocalWitness        bool
+	witnessSelector             witnessSelectorType
+	offerAdmission              admission.Config
 }
 
 func getWitnessProofParams(cmd *cobra.Command) (*witnessProofParams, error) {
@@ -1423,15 +1827,96 @@ func getWitnessProofParams(cmd *cobra.Command) (*witnessProofParams, error) {
 		return nil, fmt.Errorf("%s: %w", vctProofMonitoringExpiryPeriodFlagName, err)
 	}
 
+	witnessSelector, err := getWitnessSelector(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	offerAdmission, err := getOfferAdmissionParams(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	return &witnessProofParams{
 		maxWitnessDelay:             maxWitnessDelay,
 		maxClockSkew:                maxClockSkew,
 		witnessStoreExpiryPeriod:    witnessStoreExpiryPeriod,
 		proofMonitoringExpiryPeriod: proofMonitoringExpiryPeriod,
 		signWithLocalWitness:        signWithLocalWitness,
+		witnessSelector:             witnessSelector,
+		offerAdmission:              offerAdmission,
+	}, nil
+}
+
+func getAnchorLimitsParams(cmd *cobra.Command) (splitter.Config, error) {
+	maxOperations, err := cmdutil.GetInt(cmd, anchorMaxOperationsFlagName, anchorMaxOperationsEnvKey, 0)
+	if err != nil {
+		return splitter.Config{}, fmt.Errorf("%s: %w", anchorMaxOperationsFlagName, err)
+	}
+
+	maxSize, err := cmdutil.GetInt(cmd, anchorMaxSizeFlagName, anchorMaxSizeEnvKey, 0)
+	if err != nil {
+		return splitter.Config{}, fmt.Errorf("%s: %w", anchorMaxSizeFlagName, err)
+	}
+
+	maxWitnesses, err := cmdutil.GetInt(cmd, anchorMaxWitnessesFlagName, anchorMaxWitnessesEnvKey, 0)
+	if err != nil {
+		return splitter.Config{}, fmt.Errorf("%s: %w", anchorMaxWitnessesFlagName, err)
+	}
+
+	return splitter.Config{
+		MaxOperations: maxOperations,
+		MaxSize:       maxSize,
+		MaxWitnesses:  maxWitnesses,
+	}, nil
+}
+
+func getOfferAdmissionParams(cmd *cobra.Command) (admission.Config, error) {
+	perMinute, err := cmdutil.GetInt(cmd, offerQuotaPerMinuteFlagName, offerQuotaPerMinuteEnvKey, 0)
+	if err != nil {
+		return admission.Config{}, fmt.Errorf("%s: %w", offerQuotaPerMinuteFlagName, err)
+	}
+
+	perDay, err := cmdutil.GetInt(cmd, offerQuotaPerDayFlagName, offerQuotaPerDayEnvKey, 0)
+	if err != nil {
+		return admission.Config{}, fmt.Errorf("%s: %w", offerQuotaPerDayFlagName, err)
+	}
+
+	maxAnchorSize, err := cmdutil.GetInt(cmd, maxOfferedAnchorSizeFlagName, maxOfferedAnchorSizeEnvKey, 0)
+	if err != nil {
+		return admission.Config{}, fmt.Errorf("%s: %w", maxOfferedAnchorSizeFlagName, err)
+	}
+
+	allowListEnabled, err := cmdutil.GetBool(cmd, offerOriginAllowListEnabledFlagName, offerOriginAllowListEnabledEnvKey, false)
+	if err != nil {
+		return admission.Config{}, fmt.Errorf("%s: %w", offerOriginAllowListEnabledFlagName, err)
+	}
+
+	return admission.Config{
+		MaxOffersPerMinute:     perMinute,
+		MaxOffersPerDay:        perDay,
+		MaxAnchorSize:          maxAnchorSize,
+		OriginAllowListEnabled: allowListEnabled,
 	}, nil
 }
 
+func getWitnessSelector(cmd *cobra.Command) (witnessSelectorType, error) {
+	selector, err := cmdutil.GetUserSetVarFromString(cmd, witnessSelectorFlagName, witnessSelectorEnvKey, true)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", witnessSelectorFlagName, err)
+	}
+
+	witnessSelector := witnessSelectorType(selector)
+
+	if witnessSelector == "" {
+		witnessSelector = randomWitnessSelector
+	} else if witnessSelector != randomWitnessSelector && witnessSelector != scoredWitnessSelector {
+		return "", fmt.Errorf("unsupported witness selector: %s", witnessSelector)
+	}
+
+	return witnessSelector, nil
+}
+
 type unpublishedOperationsStoreParams struct {
 	enabled            bool
 	operationTypes     []operation.Type
@@ -1513,6 +1998,8 @@ func getDiscoveryParams(cmd *cobra.Command) (*discoveryParams, error) {
 
 type authParams struct {
 	httpSignaturesEnabled  bool
+	activityProofsEnabled  bool
+	activityProofsRequired bool
 	tokenDefinitions       []*auth.TokenDef
 	tokens                 map[string]string
 	clientTokenDefinitions []*auth.TokenDef
@@ -1528,6 +2015,18 @@ func getAuthParams(cmd *cobra.Command) (*authParams, error) {
 		return nil, err
 	}
 
+	activityProofsEnabled, err := cmdutil.GetBool(cmd, activityProofsEnabledFlagName, activityProofsEnabledEnvKey,
+		defaultActivityProofsEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	activityProofsRequired, err := cmdutil.GetBool(cmd, activityProofsRequiredFlagName, activityProofsRequiredEnvKey,
+		defaultActivityProofsRequired)
+	if err != nil {
+		return nil, err
+	}
+
 	authTokenDefs, err := getAuthTokenDefinitions(cmd, authTokensDefFlagName, authTokensDefEnvKey, nil)
 	if err != nil {
 		return nil, fmt.Errorf("authorization token definitions: %w", err)
@@ -1560,6 +2059,8 @@ func getAuthParams(cmd *cobra.Command) (*authParams, error) {
 
 	return &authParams{
 		httpSignaturesEnabled:  httpSignaturesEnabled,
+		activityProofsEnabled:  activityProofsEnabled,
+		activityProofsRequired: activityProofsRequired,
 		tokenDefinitions:       authTokenDefs,
 		tokens:                 authTokens,
 		clientTokenDefinitions: clientAuthTokenDefs,
@@ -1647,6 +2148,7 @@ type activityPubParams struct {
 	clientCacheExpiration       time.Duration
 	iriCacheSize                int
 	iriCacheExpiration          time.Duration
+	inboxRateLimit              ratelimiter.Config
 }
 
 func getActivityPubParams(cmd *cobra.Command) (*activityPubParams, error) {
@@ -1670,6 +2172,11 @@ func getActivityPubParams(cmd *cobra.Command) (*activityPubParams, error) {
 		return nil, err
 	}
 
+	inboxRateLimit, err := getInboxRateLimitParameters(cmd)
+	if err != nil {
+		return nil, err
+	}
+
 	return &activityPubParams{
 		pageSize:                    activityPubPageSize,
 		anchorSyncPeriod:            syncPeriod,
@@ -1680,6 +2187,7 @@ func getActivityPubParams(cmd *cobra.Command) (*activityPubParams, error) {
 		clientCacheExpiration:       apClientCacheExpiration,
 		iriCacheSize:                apIRICacheSize,
 		iriCacheExpiration:          apIRICacheExpiration,
+		inboxRateLimit:              inboxRateLimit,
 	}, nil
 }
 
@@ -1705,6 +2213,35 @@ func getActivityPubIRICacheParameters(cmd *cobra.Command) (int, time.Duration, e
 	})
 }
 
+func getInboxRateLimitParameters(cmd *cobra.Command) (ratelimiter.Config, error) {
+	actorRate, err := cmdutil.GetFloat(cmd, inboxActorRateLimitFlagName, inboxActorRateLimitEnvKey, 0)
+	if err != nil {
+		return ratelimiter.Config{}, fmt.Errorf("%s: %w", inboxActorRateLimitFlagName, err)
+	}
+
+	actorBurst, err := cmdutil.GetInt(cmd, inboxActorRateBurstFlagName, inboxActorRateBurstEnvKey, 0)
+	if err != nil {
+		return ratelimiter.Config{}, fmt.Errorf("%s: %w", inboxActorRateBurstFlagName, err)
+	}
+
+	globalRate, err := cmdutil.GetFloat(cmd, inboxGlobalRateLimitFlagName, inboxGlobalRateLimitEnvKey, 0)
+	if err != nil {
+		return ratelimiter.Config{}, fmt.Errorf("%s: %w", inboxGlobalRateLimitFlagName, err)
+	}
+
+	globalBurst, err := cmdutil.GetInt(cmd, inboxGlobalRateBurstFlagName, inboxGlobalRateBurstEnvKey, 0)
+	if err != nil {
+		return ratelimiter.Config{}, fmt.Errorf("%s: %w", inboxGlobalRateBurstFlagName, err)
+	}
+
+	return ratelimiter.Config{
+		ActorRate:   actorRate,
+		ActorBurst:  actorBurst,
+		GlobalRate:  globalRate,
+		GlobalBurst: globalBurst,
+	}, nil
+}
+
 type anchorStatusParams struct {
 	monitoringInterval    time.Duration
 	maxRecordsPerInterval int
@@ -2397,11 +2934,21 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(tlsSystemCertPoolFlagName, "", "", tlsSystemCertPoolFlagUsage)
 	startCmd.Flags().StringArrayP(tlsCACertsFlagName, "", []string{}, tlsCACertsFlagUsage)
 	startCmd.Flags().StringP(batchWriterTimeoutFlagName, batchWriterTimeoutFlagShorthand, "", batchWriterTimeoutFlagUsage)
+	startCmd.Flags().String(anchorMaxOperationsFlagName, "", anchorMaxOperationsFlagUsage)
+	startCmd.Flags().String(anchorMaxSizeFlagName, "", anchorMaxSizeFlagUsage)
+	startCmd.Flags().String(anchorMaxWitnessesFlagName, "", anchorMaxWitnessesFlagUsage)
 	startCmd.Flags().StringP(maxWitnessDelayFlagName, maxWitnessDelayFlagShorthand, "", maxWitnessDelayFlagUsage)
 	startCmd.Flags().StringP(maxClockSkewFlagName, "", "", maxClockSkewFlagUsage)
 	startCmd.Flags().StringP(witnessStoreExpiryPeriodFlagName, "", "", witnessStoreExpiryPeriodFlagUsage)
 	startCmd.Flags().StringP(signWithLocalWitnessFlagName, signWithLocalWitnessFlagShorthand, "", signWithLocalWitnessFlagUsage)
+	startCmd.Flags().String(witnessSelectorFlagName, "", witnessSelectorFlagUsage)
+	startCmd.Flags().String(offerQuotaPerMinuteFlagName, "", offerQuotaPerMinuteFlagUsage)
+	startCmd.Flags().String(offerQuotaPerDayFlagName, "", offerQuotaPerDayFlagUsage)
+	startCmd.Flags().String(maxOfferedAnchorSizeFlagName, "", maxOfferedAnchorSizeFlagUsage)
+	startCmd.Flags().String(offerOriginAllowListEnabledFlagName, "", offerOriginAllowListEnabledFlagUsage)
 	startCmd.Flags().StringP(httpSignaturesEnabledFlagName, httpSignaturesEnabledShorthand, "", httpSignaturesEnabledUsage)
+	startCmd.Flags().String(activityProofsEnabledFlagName, "", activityProofsEnabledUsage)
+	startCmd.Flags().String(activityProofsRequiredFlagName, "", activityProofsRequiredUsage)
 	startCmd.Flags().String(enableDidDiscoveryFlagName, "", enableDidDiscoveryUsage)
 	startCmd.Flags().String(enableUnpublishedOperationStoreFlagName, "", enableUnpublishedOperationStoreUsage)
 	startCmd.Flags().String(unpublishedOperationStoreOperationTypesFlagName, "", unpublishedOperationStoreOperationTypesUsage)
@@ -2410,6 +2957,13 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().String(resolveFromAnchorOriginFlagName, "", resolveFromAnchorOriginUsage)
 	startCmd.Flags().String(verifyLatestFromAnchorOriginFlagName, "", verifyLatestFromAnchorOriginUsage)
 	startCmd.Flags().StringP(casTypeFlagName, casTypeFlagShorthand, "", casTypeFlagUsage)
+	startCmd.Flags().String(casFSDirFlagName, "", casFSDirFlagUsage)
+	startCmd.Flags().String(casS3EndpointFlagName, "", casS3EndpointFlagUsage)
+	startCmd.Flags().String(casS3RegionFlagName, "", casS3RegionFlagUsage)
+	startCmd.Flags().String(casS3BucketFlagName, "", casS3BucketFlagUsage)
+	startCmd.Flags().String(casS3KeyPrefixFlagName, "", casS3KeyPrefixFlagUsage)
+	startCmd.Flags().String(casS3AccessKeyIDFlagName, "", casS3AccessKeyIDFlagUsage)
+	startCmd.Flags().String(casS3SecretAccessKeyFlagName, "", casS3SecretAccessKeyFlagUsage)
 	startCmd.Flags().StringP(ipfsURLFlagName, ipfsURLFlagShorthand, "", ipfsURLFlagUsage)
 	startCmd.Flags().StringP(localCASReplicateInIPFSFlagName, "", "false", localCASReplicateInIPFSFlagUsage)
 	startCmd.Flags().StringP(mqURLFlagName, mqURLFlagShorthand, "", mqURLFlagUsage)
@@ -2433,6 +2987,18 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(opQueueMaxContiguousOperationsWithErrFlagName, "", "", opQueueMaxContiguousOperationsWithErrFlagUsage)
 	startCmd.Flags().StringP(opQueueMaxContiguousOperationsWithoutErrFlagName, "", "", opQueueMaxContiguousOperationsWithoutErrFlagUsage)
 	startCmd.Flags().String(cidVersionFlagName, "1", cidVersionFlagUsage)
+	startCmd.Flags().String(cidMultibaseFlagName, "", cidMultibaseFlagUsage)
+	startCmd.Flags().String(casGCEnabledFlagName, "", casGCEnabledFlagUsage)
+	startCmd.Flags().String(casGCIntervalFlagName, "", casGCIntervalFlagUsage)
+	startCmd.Flags().String(casGCGracePeriodFlagName, "", casGCGracePeriodFlagUsage)
+	startCmd.Flags().String(casGCMaxSweepCountFlagName, "", casGCMaxSweepCountFlagUsage)
+	startCmd.Flags().String(casGCDryRunFlagName, "", casGCDryRunFlagUsage)
+	startCmd.Flags().String(casGCArchiveDirFlagName, "", casGCArchiveDirFlagUsage)
+	startCmd.Flags().String(casScrubEnabledFlagName, "", casScrubEnabledFlagUsage)
+	startCmd.Flags().String(casScrubIntervalFlagName, "", casScrubIntervalFlagUsage)
+	startCmd.Flags().String(casScrubMaxItemsPerRunFlagName, "", casScrubMaxItemsPerRunFlagUsage)
+	startCmd.Flags().String(casScrubMaxRepairAttemptsFlagName, "", casScrubMaxRepairAttemptsFlagUsage)
+	startCmd.Flags().StringArrayP(casScrubPeerWebCASURLsFlagName, "", []string{}, casScrubPeerWebCASURLsFlagUsage)
 	startCmd.Flags().StringP(didNamespaceFlagName, didNamespaceFlagShorthand, "", didNamespaceFlagUsage)
 	startCmd.Flags().StringArrayP(didAliasesFlagName, didAliasesFlagShorthand, []string{}, didAliasesFlagUsage)
 	startCmd.Flags().StringArrayP(allowedOriginsFlagName, allowedOriginsFlagShorthand, []string{}, allowedOriginsFlagUsage)
@@ -2486,6 +3052,10 @@ func createFlags(startCmd *cobra.Command) {
 	startCmd.Flags().StringP(activityPubClientCacheSizeFlagName, "", "", activityPubClientCacheSizeFlagUsage)
 	startCmd.Flags().StringP(activityPubIRICacheSizeFlagName, "", "", activityPubIRICacheSizeFlagUsage)
 	startCmd.Flags().StringP(activityPubIRICacheExpirationFlagName, "", "", activityPubIRICacheExpirationFlagUsage)
+	startCmd.Flags().StringP(inboxActorRateLimitFlagName, "", "", inboxActorRateLimitFlagUsage)
+	startCmd.Flags().StringP(inboxActorRateBurstFlagName, "", "", inboxActorRateBurstFlagUsage)
+	startCmd.Flags().StringP(inboxGlobalRateLimitFlagName, "", "", inboxGlobalRateLimitFlagUsage)
+	startCmd.Flags().StringP(inboxGlobalRateBurstFlagName, "", "", inboxGlobalRateBurstFlagUsage)
 	startCmd.Flags().StringP(activityPubClientCacheExpirationFlagName, "", "", activityPubClientCacheExpirationFlagUsage)
 	startCmd.Flags().StringP(serverIdleTimeoutFlagName, "", "", serverIdleTimeoutFlagUsage)
 	startCmd.Flags().StringP(serverReadHeaderTimeoutFlagName, "", "", serverReadHeaderTimeoutFlagUsage)
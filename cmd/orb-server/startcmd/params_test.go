@@ -21,6 +21,7 @@ import (
 	"github.com/trustbloc/logutil-go/pkg/log"
 
 	"github.com/trustbloc/orb/internal/pkg/cmdutil"
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	"github.com/trustbloc/orb/pkg/observability/tracing"
 )
 
@@ -355,6 +356,32 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid value for sign-with-local-witness")
 	})
 
+	t.Run("test invalid witness selector", func(t *testing.T) {
+		startCmd := GetStartCmd()
+
+		args := []string{
+			"--" + hostURLFlagName, "localhost:8247",
+			"--" + metricsProviderFlagName, "prometheus",
+			"--" + promHTTPURLFlagName, "localhost:8248",
+			"--" + externalEndpointFlagName, "orb.example.com",
+			"--" + casTypeFlagName, "ipfs",
+			"--" + ipfsURLFlagName, "localhost:8081",
+			"--" + maxWitnessDelayFlagName, "1m",
+			"--" + witnessSelectorFlagName, "fastest",
+			"--" + didNamespaceFlagName, "namespace", "--" + databaseTypeFlagName, databaseTypeMemOption,
+			"--" + kmsSecretsDatabaseTypeFlagName, databaseTypeMemOption,
+			"--" + anchorCredentialDomainFlagName, "domain.com",
+			"--" + LogLevelFlagName, log.ERROR.String(),
+		}
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported witness selector: fastest")
+	})
+
 	t.Run("test invalid sync time format", func(t *testing.T) {
 		startCmd := GetStartCmd()
 
@@ -405,6 +432,58 @@ func TestStartCmdWithMissingArg(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid value for enable-http-signatures")
 	})
 
+	t.Run("test invalid enable-activity-proofs", func(t *testing.T) {
+		startCmd := GetStartCmd()
+
+		args := []string{
+			"--" + hostURLFlagName, "localhost:8247",
+			"--" + metricsProviderFlagName, "prometheus",
+			"--" + promHTTPURLFlagName, "localhost:8248",
+			"--" + externalEndpointFlagName, "orb.example.com",
+			"--" + casTypeFlagName, "ipfs",
+			"--" + ipfsURLFlagName, "localhost:8081",
+			"--" + didNamespaceFlagName, "namespace", "--" + databaseTypeFlagName, databaseTypeMemOption,
+			"--" + kmsTypeFlagName, "local",
+			"--" + kmsSecretsDatabaseTypeFlagName, databaseTypeMemOption,
+			"--" + anchorCredentialDomainFlagName, "domain.com",
+			"--" + LogLevelFlagName, log.ERROR.String(),
+			"--" + activityProofsEnabledFlagName, "invalid bool",
+		}
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for enable-activity-proofs")
+	})
+
+	t.Run("test invalid activity-proofs-required", func(t *testing.T) {
+		startCmd := GetStartCmd()
+
+		args := []string{
+			"--" + hostURLFlagName, "localhost:8247",
+			"--" + metricsProviderFlagName, "prometheus",
+			"--" + promHTTPURLFlagName, "localhost:8248",
+			"--" + externalEndpointFlagName, "orb.example.com",
+			"--" + casTypeFlagName, "ipfs",
+			"--" + ipfsURLFlagName, "localhost:8081",
+			"--" + didNamespaceFlagName, "namespace", "--" + databaseTypeFlagName, databaseTypeMemOption,
+			"--" + kmsTypeFlagName, "local",
+			"--" + kmsSecretsDatabaseTypeFlagName, databaseTypeMemOption,
+			"--" + anchorCredentialDomainFlagName, "domain.com",
+			"--" + LogLevelFlagName, log.ERROR.String(),
+			"--" + activityProofsRequiredFlagName, "invalid bool",
+		}
+
+		startCmd.SetArgs(args)
+
+		err := startCmd.Execute()
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid value for activity-proofs-required")
+	})
+
 	t.Run("test invalid vct enabled flag", func(t *testing.T) {
 		startCmd := GetStartCmd()
 
@@ -1220,7 +1299,7 @@ func TestStartCmdWithInvalidCASType(t *testing.T) {
 	startCmd.SetArgs(getTestArgs("localhost:8081", "InvalidName", "false", databaseTypeMemOption))
 
 	err := startCmd.Execute()
-	require.EqualError(t, err, "InvalidName is not a valid CAS type. It must be either local or ipfs")
+	require.EqualError(t, err, "InvalidName is not a valid CAS type. It must be one of local, ipfs, filesystem or s3")
 }
 
 func TestGetActivityPubPageSize(t *testing.T) {
@@ -1771,6 +1850,375 @@ func TestGetActivityPubIRICacheParameters(t *testing.T) {
 	})
 }
 
+func TestGetInboxRateLimitParameters(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreActorRateEnv := setEnv(t, inboxActorRateLimitEnvKey, "2.5")
+		restoreActorBurstEnv := setEnv(t, inboxActorRateBurstEnvKey, "10")
+		restoreGlobalRateEnv := setEnv(t, inboxGlobalRateLimitEnvKey, "100")
+		restoreGlobalBurstEnv := setEnv(t, inboxGlobalRateBurstEnvKey, "200")
+
+		defer func() {
+			restoreActorRateEnv()
+			restoreActorBurstEnv()
+			restoreGlobalRateEnv()
+			restoreGlobalBurstEnv()
+		}()
+
+		cmd := getTestCmd(t)
+
+		cfg, err := getInboxRateLimitParameters(cmd)
+		require.NoError(t, err)
+		require.Equal(t, 2.5, cfg.ActorRate)
+		require.Equal(t, 10, cfg.ActorBurst)
+		require.Equal(t, float64(100), cfg.GlobalRate)
+		require.Equal(t, 200, cfg.GlobalBurst)
+	})
+
+	t.Run("Not specified -> no limit", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		cfg, err := getInboxRateLimitParameters(cmd)
+		require.NoError(t, err)
+		require.False(t, cfg.Enabled())
+	})
+
+	t.Run("Invalid env value -> error", func(t *testing.T) {
+		for _, envKey := range []string{
+			inboxActorRateLimitEnvKey, inboxActorRateBurstEnvKey, inboxGlobalRateLimitEnvKey, inboxGlobalRateBurstEnvKey,
+		} {
+			restoreEnv := setEnv(t, envKey, "invalid")
+
+			cmd := getTestCmd(t)
+
+			_, err := getInboxRateLimitParameters(cmd)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid")
+
+			restoreEnv()
+		}
+	})
+}
+
+func TestGetAnchorLimitsParams(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreMaxOperationsEnv := setEnv(t, anchorMaxOperationsEnvKey, "1000")
+		restoreMaxSizeEnv := setEnv(t, anchorMaxSizeEnvKey, "2000000")
+		restoreMaxWitnessesEnv := setEnv(t, anchorMaxWitnessesEnvKey, "10")
+
+		defer func() {
+			restoreMaxOperationsEnv()
+			restoreMaxSizeEnv()
+			restoreMaxWitnessesEnv()
+		}()
+
+		cmd := getTestCmd(t)
+
+		cfg, err := getAnchorLimitsParams(cmd)
+		require.NoError(t, err)
+		require.Equal(t, 1000, cfg.MaxOperations)
+		require.Equal(t, 2000000, cfg.MaxSize)
+		require.Equal(t, 10, cfg.MaxWitnesses)
+	})
+
+	t.Run("Not specified -> not enabled", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		cfg, err := getAnchorLimitsParams(cmd)
+		require.NoError(t, err)
+		require.False(t, cfg.Enabled())
+	})
+
+	t.Run("Invalid env value -> error", func(t *testing.T) {
+		for _, envKey := range []string{anchorMaxOperationsEnvKey, anchorMaxSizeEnvKey, anchorMaxWitnessesEnvKey} {
+			restoreEnv := setEnv(t, envKey, "invalid")
+
+			cmd := getTestCmd(t)
+
+			_, err := getAnchorLimitsParams(cmd)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid")
+
+			restoreEnv()
+		}
+	})
+}
+
+func TestGetCIDFormatParams(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		multibase, err := getCIDFormatParams(cmd, 1)
+		require.NoError(t, err)
+		require.Empty(t, multibase)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		restoreMultibaseEnv := setEnv(t, cidMultibaseEnvKey, "base58btc")
+
+		defer restoreMultibaseEnv()
+
+		cmd := getTestCmd(t)
+
+		multibase, err := getCIDFormatParams(cmd, 1)
+		require.NoError(t, err)
+		require.Equal(t, extendedcasclient.MultibaseBase58BTC, multibase)
+	})
+
+	t.Run("Invalid multibase", func(t *testing.T) {
+		restoreMultibaseEnv := setEnv(t, cidMultibaseEnvKey, "base16")
+
+		defer restoreMultibaseEnv()
+
+		cmd := getTestCmd(t)
+
+		_, err := getCIDFormatParams(cmd, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "base16 is not a supported multibase")
+	})
+
+	t.Run("Multibase not supported by CID version 0", func(t *testing.T) {
+		restoreMultibaseEnv := setEnv(t, cidMultibaseEnvKey, "base32")
+
+		defer restoreMultibaseEnv()
+
+		cmd := getTestCmd(t)
+
+		_, err := getCIDFormatParams(cmd, 0)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "CID version 0 doesn't support the base32 multibase")
+	})
+}
+
+func TestGetCASGCParams(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreEnabledEnv := setEnv(t, casGCEnabledEnvKey, "true")
+		restoreIntervalEnv := setEnv(t, casGCIntervalEnvKey, "1h")
+		restoreGracePeriodEnv := setEnv(t, casGCGracePeriodEnvKey, "48h")
+		restoreMaxSweepCountEnv := setEnv(t, casGCMaxSweepCountEnvKey, "500")
+		restoreDryRunEnv := setEnv(t, casGCDryRunEnvKey, "true")
+		restoreArchiveDirEnv := setEnv(t, casGCArchiveDirEnvKey, "/var/orb/cas-archive")
+
+		defer func() {
+			restoreEnabledEnv()
+			restoreIntervalEnv()
+			restoreGracePeriodEnv()
+			restoreMaxSweepCountEnv()
+			restoreDryRunEnv()
+			restoreArchiveDirEnv()
+		}()
+
+		cmd := getTestCmd(t)
+
+		params, err := getCASGCParams(cmd)
+		require.NoError(t, err)
+		require.True(t, params.enabled)
+		require.Equal(t, time.Hour, params.config.Interval)
+		require.Equal(t, 48*time.Hour, params.config.GracePeriod)
+		require.Equal(t, 500, params.config.MaxSweepCount)
+		require.True(t, params.config.DryRun)
+		require.Equal(t, "/var/orb/cas-archive", params.archiveDir)
+	})
+
+	t.Run("Not specified -> not enabled", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		params, err := getCASGCParams(cmd)
+		require.NoError(t, err)
+		require.False(t, params.enabled)
+		require.Empty(t, params.archiveDir)
+	})
+
+	t.Run("Invalid env value -> error", func(t *testing.T) {
+		for _, envKey := range []string{
+			casGCEnabledEnvKey, casGCIntervalEnvKey, casGCGracePeriodEnvKey, casGCMaxSweepCountEnvKey, casGCDryRunEnvKey,
+		} {
+			restoreEnv := setEnv(t, envKey, "invalid")
+
+			cmd := getTestCmd(t)
+
+			_, err := getCASGCParams(cmd)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid")
+
+			restoreEnv()
+		}
+	})
+}
+
+func TestGetCASScrubParams(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restoreEnabledEnv := setEnv(t, casScrubEnabledEnvKey, "true")
+		restoreIntervalEnv := setEnv(t, casScrubIntervalEnvKey, "30m")
+		restoreMaxItemsPerRunEnv := setEnv(t, casScrubMaxItemsPerRunEnvKey, "500")
+		restoreMaxRepairAttemptsEnv := setEnv(t, casScrubMaxRepairAttemptsEnvKey, "5")
+		restorePeerWebCASURLsEnv := setEnv(t, casScrubPeerWebCASURLsEnvKey,
+			"https://orb.domain2.com/cas,https://orb.domain3.com/cas")
+
+		defer func() {
+			restoreEnabledEnv()
+			restoreIntervalEnv()
+			restoreMaxItemsPerRunEnv()
+			restoreMaxRepairAttemptsEnv()
+			restorePeerWebCASURLsEnv()
+		}()
+
+		cmd := getTestCmd(t)
+
+		params, err := getCASScrubParams(cmd)
+		require.NoError(t, err)
+		require.True(t, params.enabled)
+		require.Equal(t, 30*time.Minute, params.config.Interval)
+		require.Equal(t, 500, params.config.MaxItemsPerRun)
+		require.Equal(t, 5, params.config.MaxRepairAttempts)
+		require.Equal(t, []string{"https://orb.domain2.com/cas", "https://orb.domain3.com/cas"},
+			params.config.PeerWebCASURLs)
+	})
+
+	t.Run("Not specified -> not enabled", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		params, err := getCASScrubParams(cmd)
+		require.NoError(t, err)
+		require.False(t, params.enabled)
+		require.Empty(t, params.config.PeerWebCASURLs)
+	})
+
+	t.Run("Invalid env value -> error", func(t *testing.T) {
+		for _, envKey := range []string{
+			casScrubEnabledEnvKey, casScrubIntervalEnvKey, casScrubMaxItemsPerRunEnvKey, casScrubMaxRepairAttemptsEnvKey,
+		} {
+			restoreEnv := setEnv(t, envKey, "invalid")
+
+			cmd := getTestCmd(t)
+
+			_, err := getCASScrubParams(cmd)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid")
+
+			restoreEnv()
+		}
+	})
+}
+
+func TestGetBlobCASParams(t *testing.T) {
+	t.Run("Filesystem", func(t *testing.T) {
+		restoreDirEnv := setEnv(t, casFSDirEnvKey, "/var/orb/cas")
+
+		defer restoreDirEnv()
+
+		cmd := getTestCmd(t)
+
+		fsDir, _, err := getBlobCASParams(cmd, casTypeFilesystem)
+		require.NoError(t, err)
+		require.Equal(t, "/var/orb/cas", fsDir)
+	})
+
+	t.Run("S3", func(t *testing.T) {
+		restoreEndpointEnv := setEnv(t, casS3EndpointEnvKey, "http://localhost:9000")
+		restoreRegionEnv := setEnv(t, casS3RegionEnvKey, "ca-central-1")
+		restoreBucketEnv := setEnv(t, casS3BucketEnvKey, "orb")
+		restorePrefixEnv := setEnv(t, casS3KeyPrefixEnvKey, "cas/")
+		restoreAccessKeyIDEnv := setEnv(t, casS3AccessKeyIDEnvKey, "AKID")
+		restoreSecretEnv := setEnv(t, casS3SecretAccessKeyEnvKey, "secret")
+
+		defer func() {
+			restoreEndpointEnv()
+			restoreRegionEnv()
+			restoreBucketEnv()
+			restorePrefixEnv()
+			restoreAccessKeyIDEnv()
+			restoreSecretEnv()
+		}()
+
+		cmd := getTestCmd(t)
+
+		_, cfg, err := getBlobCASParams(cmd, casTypeS3)
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:9000", cfg.Endpoint)
+		require.Equal(t, "ca-central-1", cfg.Region)
+		require.Equal(t, "orb", cfg.Bucket)
+		require.Equal(t, "cas/", cfg.KeyPrefix)
+		require.Equal(t, "AKID", cfg.AccessKeyID)
+		require.Equal(t, "secret", cfg.SecretAccessKey)
+	})
+
+	t.Run("Not required for other CAS types", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		_, _, err := getBlobCASParams(cmd, "local")
+		require.NoError(t, err)
+	})
+
+	t.Run("Missing directory -> error", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		_, _, err := getBlobCASParams(cmd, casTypeFilesystem)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), casFSDirFlagName+" is required")
+	})
+
+	t.Run("Missing bucket -> error", func(t *testing.T) {
+		restoreEndpointEnv := setEnv(t, casS3EndpointEnvKey, "http://localhost:9000")
+
+		defer restoreEndpointEnv()
+
+		cmd := getTestCmd(t)
+
+		_, _, err := getBlobCASParams(cmd, casTypeS3)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), casS3BucketFlagName)
+	})
+}
+
+func TestGetOfferAdmissionParams(t *testing.T) {
+	t.Run("Valid env values", func(t *testing.T) {
+		restorePerMinuteEnv := setEnv(t, offerQuotaPerMinuteEnvKey, "10")
+		restorePerDayEnv := setEnv(t, offerQuotaPerDayEnvKey, "1000")
+		restoreMaxSizeEnv := setEnv(t, maxOfferedAnchorSizeEnvKey, "500000")
+		restoreAllowListEnv := setEnv(t, offerOriginAllowListEnabledEnvKey, "true")
+
+		defer func() {
+			restorePerMinuteEnv()
+			restorePerDayEnv()
+			restoreMaxSizeEnv()
+			restoreAllowListEnv()
+		}()
+
+		cmd := getTestCmd(t)
+
+		cfg, err := getOfferAdmissionParams(cmd)
+		require.NoError(t, err)
+		require.Equal(t, 10, cfg.MaxOffersPerMinute)
+		require.Equal(t, 1000, cfg.MaxOffersPerDay)
+		require.Equal(t, 500000, cfg.MaxAnchorSize)
+		require.True(t, cfg.OriginAllowListEnabled)
+	})
+
+	t.Run("Not specified -> not enabled", func(t *testing.T) {
+		cmd := getTestCmd(t)
+
+		cfg, err := getOfferAdmissionParams(cmd)
+		require.NoError(t, err)
+		require.False(t, cfg.Enabled())
+	})
+
+	t.Run("Invalid env value -> error", func(t *testing.T) {
+		for _, envKey := range []string{
+			offerQuotaPerMinuteEnvKey, offerQuotaPerDayEnvKey, maxOfferedAnchorSizeEnvKey, offerOriginAllowListEnabledEnvKey,
+		} {
+			restoreEnv := setEnv(t, envKey, "invalid")
+
+			cmd := getTestCmd(t)
+
+			_, err := getOfferAdmissionParams(cmd)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "invalid")
+
+			restoreEnv()
+		}
+	})
+}
+
 func TestTracingParameters(t *testing.T) {
 	t.Run("Default (not enabled)", func(t *testing.T) {
 		cmd := getTestCmd(t)
@@ -1837,6 +2285,7 @@ func setEnvVars(t *testing.T, databaseType, casType, replicateLocalCASToIPFS str
 	t.Setenv(maxWitnessDelayEnvKey, "10m")
 	t.Setenv(witnessStoreExpiryPeriodEnvKey, "12m")
 	t.Setenv(signWithLocalWitnessEnvKey, "true")
+	t.Setenv(witnessSelectorEnvKey, "scored")
 	t.Setenv(didNamespaceEnvKey, "namespace")
 	t.Setenv(databaseTypeEnvKey, databaseType)
 	t.Setenv(kmsSecretsDatabaseTypeEnvKey, databaseTypeMemOption)
@@ -61,6 +61,8 @@ import (
 	casapi "github.com/trustbloc/sidetree-svc-go/pkg/api/cas"
 	"github.com/trustbloc/sidetree-svc-go/pkg/api/protocol"
 	"github.com/trustbloc/sidetree-svc-go/pkg/batch"
+	"github.com/trustbloc/sidetree-svc-go/pkg/batch/cutter"
+	"github.com/trustbloc/sidetree-svc-go/pkg/compression"
 	"github.com/trustbloc/sidetree-svc-go/pkg/dochandler"
 	"github.com/trustbloc/sidetree-svc-go/pkg/processor"
 	restcommon "github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
@@ -71,14 +73,19 @@ import (
 	"github.com/trustbloc/orb/internal/pkg/ldcontext"
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/internal/pkg/tlsutil"
+	"github.com/trustbloc/orb/pkg/activitypub/activityproof"
 	"github.com/trustbloc/orb/pkg/activitypub/client"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
 	aphandler "github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	apservice "github.com/trustbloc/orb/pkg/activitypub/service"
 	"github.com/trustbloc/orb/pkg/activitypub/service/acceptlist"
 	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/anchorsynctask"
+	"github.com/trustbloc/orb/pkg/activitypub/service/backfill"
+	inboxfilter "github.com/trustbloc/orb/pkg/activitypub/service/inbox/filter"
+	apoutbox "github.com/trustbloc/orb/pkg/activitypub/service/outbox"
 	apspi "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	apariesstore "github.com/trustbloc/orb/pkg/activitypub/store/ariesstore"
 	apmemstore "github.com/trustbloc/orb/pkg/activitypub/store/memstore"
@@ -95,14 +102,27 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/handler/credential"
 	"github.com/trustbloc/orb/pkg/anchor/handler/proof"
 	"github.com/trustbloc/orb/pkg/anchor/linkstore"
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
+	"github.com/trustbloc/orb/pkg/anchor/witness/admission"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
 	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy/inspector"
 	policyhandler "github.com/trustbloc/orb/pkg/anchor/witness/policy/resthandler"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/selector/scored"
+	policysimulator "github.com/trustbloc/orb/pkg/anchor/witness/policy/simulator"
+	"github.com/trustbloc/orb/pkg/anchor/witness/rewitness"
 	"github.com/trustbloc/orb/pkg/anchor/writer"
+	"github.com/trustbloc/orb/pkg/anchor/writer/splitter"
+	blobcas "github.com/trustbloc/orb/pkg/cas/blob"
+	fscas "github.com/trustbloc/orb/pkg/cas/blob/fs"
+	s3cas "github.com/trustbloc/orb/pkg/cas/blob/s3"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/gc"
+	"github.com/trustbloc/orb/pkg/cas/gc/gcrest"
 	ipfscas "github.com/trustbloc/orb/pkg/cas/ipfs"
 	"github.com/trustbloc/orb/pkg/cas/resolver"
+	"github.com/trustbloc/orb/pkg/cas/scrub"
+	"github.com/trustbloc/orb/pkg/cas/scrub/scrubrest"
 	"github.com/trustbloc/orb/pkg/config"
 	configclient "github.com/trustbloc/orb/pkg/config/client"
 	sidetreecontext "github.com/trustbloc/orb/pkg/context"
@@ -144,6 +164,8 @@ import (
 	anchorlinkstore "github.com/trustbloc/orb/pkg/store/anchorlink"
 	"github.com/trustbloc/orb/pkg/store/anchorstatus"
 	casstore "github.com/trustbloc/orb/pkg/store/cas"
+	"github.com/trustbloc/orb/pkg/store/casscrub"
+	"github.com/trustbloc/orb/pkg/store/delivery"
 	didanchorstore "github.com/trustbloc/orb/pkg/store/didanchor"
 	"github.com/trustbloc/orb/pkg/store/expiry"
 	"github.com/trustbloc/orb/pkg/store/logentry"
@@ -151,6 +173,7 @@ import (
 	opstore "github.com/trustbloc/orb/pkg/store/operation"
 	unpublishedopstore "github.com/trustbloc/orb/pkg/store/operation/unpublished"
 	"github.com/trustbloc/orb/pkg/store/publickey"
+	"github.com/trustbloc/orb/pkg/store/quarantine"
 	proofstore "github.com/trustbloc/orb/pkg/store/witness"
 	"github.com/trustbloc/orb/pkg/store/wrapper"
 	"github.com/trustbloc/orb/pkg/taskmgr"
@@ -176,6 +199,8 @@ const (
 	defaultProofMonitoringExpiryPeriod      = 1 * time.Hour
 	defaultSyncTimeout                      = 1
 	defaulthttpSignaturesEnabled            = true
+	defaultActivityProofsEnabled            = false
+	defaultActivityProofsRequired           = false
 	defaultDidDiscoveryEnabled              = false
 	defaultUnpublishedOperationStoreEnabled = false
 	defaultIncludeUnpublishedOperations     = false
@@ -604,7 +629,7 @@ func startOrbServices(parameters *orbParameters) error {
 	var casResolver *resolver.Resolver
 	if parameters.cas.ipfsURL != "" {
 		ipfsReader = ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
-			extendedcasclient.WithCIDVersion(parameters.cas.cidVersion))
+			parameters.cas.cidFormatOptions()...)
 		casResolver = resolver.New(coreCASClient, ipfsReader, webCASResolver, metrics)
 	} else {
 		casResolver = resolver.New(coreCASClient, nil, webCASResolver, metrics)
@@ -702,7 +727,8 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("failed to create anchor event store: %s", err.Error())
 	}
 
-	witnessProofStore, err := proofstore.New(storeProviders.provider, expiryService, parameters.witnessProof.witnessStoreExpiryPeriod)
+	witnessProofStore, err := proofstore.New(storeProviders.provider, expiryService,
+		parameters.witnessProof.witnessStoreExpiryPeriod, proofstore.WithMetrics(metrics))
 	if err != nil {
 		return fmt.Errorf("failed to create proof store: %s", err.Error())
 	}
@@ -724,6 +750,20 @@ func startOrbServices(parameters *orbParameters) error {
 		return err
 	}
 
+	deliveryStore, err := delivery.New(storeProviders.provider)
+	if err != nil {
+		return fmt.Errorf("create outbox delivery store: %w", err)
+	}
+
+	peerHealthRegistry := peerhealth.New(peerhealth.Config{})
+
+	quarantineStore, err := quarantine.New(storeProviders.provider)
+	if err != nil {
+		return fmt.Errorf("create inbox quarantine store: %w", err)
+	}
+
+	inboxFilterStore := inboxfilter.NewStore(configStore)
+
 	httpSignActivePubKey, httpSignKeyType, err := km.ExportPubKeyBytes(parameters.kmsParams.httpSignActiveKeyID)
 	if err != nil {
 		return fmt.Errorf("failed to export pub key: %w", err)
@@ -767,7 +807,9 @@ func startOrbServices(parameters *orbParameters) error {
 	apClient := client.New(client.Config{
 		CacheSize:            parameters.activityPub.clientCacheSize,
 		CacheRefreshInterval: parameters.activityPub.clientCacheExpiration,
-	}, httpTransport, publicKeyFetcher, resourceResolver)
+	}, httpTransport, publicKeyFetcher, resourceResolver,
+		client.WithDIDPublicKeyPurger(pkStore.Purge),
+	)
 
 	apSigVerifier := getActivityPubVerifier(parameters, km, cr, apClient)
 
@@ -788,7 +830,16 @@ func startOrbServices(parameters *orbParameters) error {
 
 	policyStore := policycfg.NewPolicyStore(configStore)
 
-	witnessPolicy, err := policy.New(policyStore, parameters.witnessPolicyCacheExpiration)
+	witnessPolicyOpts := []policy.Opt{
+		policy.WithScopedPolicies(policyStore),
+		policy.WithNamespaceAliases(parameters.sidetree.didNamespace, parameters.sidetree.didAliases...),
+	}
+
+	if parameters.witnessProof.witnessSelector == scoredWitnessSelector {
+		witnessPolicyOpts = append(witnessPolicyOpts, policy.WithSelector(scored.New(witnessProofStore)))
+	}
+
+	witnessPolicy, err := policy.New(policyStore, parameters.witnessPolicyCacheExpiration, witnessPolicyOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create witness policy: %s", err.Error())
 	}
@@ -800,6 +851,8 @@ func startOrbServices(parameters *orbParameters) error {
 		WitnessStore:    witnessProofStore,
 		Outbox:          func() inspector.Outbox { return activityPubService.Outbox() },
 		WitnessPolicy:   witnessPolicy,
+		WitnessStats:    witnessProofStore,
+		PolicyScopes:    witnessProofStore,
 	}
 
 	policyInspector, err := inspector.New(witnessPolicyInspectorProviders, parameters.witnessProof.maxWitnessDelay)
@@ -829,6 +882,8 @@ func startOrbServices(parameters *orbParameters) error {
 			WitnessStore:    witnessProofStore,
 			WitnessPolicy:   witnessPolicy,
 			Metrics:         metrics,
+			Revocations:     witnessProofStore,
+			PolicyScopes:    witnessProofStore,
 		},
 		pubSub, parameters.dataURIMediaType, parameters.witnessProof.maxClockSkew,
 	)
@@ -876,6 +931,27 @@ func startOrbServices(parameters *orbParameters) error {
 
 	anchorEventHandler := acknowlegement.New(anchorLinkStore)
 
+	var (
+		activitySigner    apoutbox.ActivitySigner
+		activityProofOpts []apspi.HandlerOpt
+		syncTaskOpts      []anchorsynctask.Opt
+		backfillOpts      []backfill.Opt
+	)
+
+	if parameters.auth.activityProofsEnabled {
+		logger.Info("Activity proofs are enabled", zap.Bool("required", parameters.auth.activityProofsRequired))
+
+		activitySigner = activityproof.NewSigner(cr, km, parameters.kmsParams.httpSignActiveKeyID, publicKeyID,
+			orbDocumentLoader)
+
+		activityProofVerifier := activityproof.NewVerifier(apClient, orbDocumentLoader,
+			activityproof.WithProofRequired(parameters.auth.activityProofsRequired))
+
+		activityProofOpts = append(activityProofOpts, apspi.WithActivityProofVerifier(activityProofVerifier))
+		syncTaskOpts = append(syncTaskOpts, anchorsynctask.WithProofVerifier(activityProofVerifier))
+		backfillOpts = append(backfillOpts, backfill.WithProofVerifier(activityProofVerifier))
+	}
+
 	err = anchorsynctask.Register(
 		anchorsynctask.Config{
 			ServiceIRI:          parameters.apServiceParams.serviceIRI(),
@@ -884,15 +960,26 @@ func startOrbServices(parameters *orbParameters) error {
 			MinActivityAge:      parameters.activityPub.anchorSyncMinActivityAge,
 			MaxActivitiesToSync: parameters.activityPub.anchorSyncMaxActivities,
 		},
-		taskMgr, apClient, apStore, storeProviders.provider,
+		taskMgr, apClient, apStore, storeProviders.provider, peerHealthRegistry,
 		func() apspi.InboxHandler {
 			return activityPubService.InboxHandler()
 		},
+		syncTaskOpts...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to register anchor sync task: %w", err)
 	}
 
+	backfiller, err := backfill.New(backfill.Config{}, taskMgr, apClient, apStore, storeProviders.provider,
+		func() apspi.InboxHandler {
+			return activityPubService.InboxHandler()
+		},
+		backfillOpts...,
+	)
+	if err != nil {
+		return fmt.Errorf("create activity backfiller: %w", err)
+	}
+
 	apConfig := &apservice.Config{
 		ServicePath:              parameters.apServiceParams.serviceEndpoint().Path,
 		ServiceIRI:               parameters.apServiceParams.serviceIRI(),
@@ -901,12 +988,17 @@ func startOrbServices(parameters *orbParameters) error {
 		MaxWitnessDelay:          parameters.witnessProof.maxWitnessDelay,
 		IRICacheSize:             parameters.activityPub.iriCacheSize,
 		IRICacheExpiration:       parameters.activityPub.iriCacheExpiration,
+		InboxRateLimit:           parameters.activityPub.inboxRateLimit,
 		OutboxSubscriberPoolSize: parameters.mqParams.outboxPoolSize,
 		InboxSubscriberPoolSize:  parameters.mqParams.inboxPoolSize,
+		// The first delivery plus the maximum number of redeliveries by the message queue.
+		MaxDeliveryAttempts:       parameters.mqParams.maxRedeliveryAttempts + 1,
+		RedeliveryInitialInterval: parameters.mqParams.redeliveryInitialInterval,
+		RedeliveryMultiplier:      parameters.mqParams.redeliveryMultiplier,
+		MaxRedeliveryInterval:     parameters.mqParams.maxRedeliveryInterval,
 	}
 
-	activityPubService, err = apservice.New(apConfig,
-		apStore, httpTransport, apSigVerifier, pubSub, apClient, resourceResolver, authTokenManager, metrics,
+	apHandlerOpts := []apspi.HandlerOpt{
 		apspi.WithProofHandler(proofHandler),
 		apspi.WithAcceptFollowHandler(logMonitorHandler),
 		apspi.WithUndoFollowHandler(logMonitorHandler),
@@ -918,6 +1010,21 @@ func startOrbServices(parameters *orbParameters) error {
 		apspi.WithInviteWitnessAuth(newAcceptRejectHandler(activityhandler.InviteWitnessType, parameters.auth.inviteWitnessPolicy, configStore)),
 		apspi.WithFollowAuth(newAcceptRejectHandler(activityhandler.FollowType, parameters.auth.followPolicy, configStore)),
 		apspi.WithAnchorEventAcknowledgementHandler(anchorEventHandler),
+	}
+
+	if offerAdmissionCfg := parameters.witnessProof.offerAdmission; offerAdmissionCfg.Enabled() {
+		logger.Info("Admission control for incoming offers is enabled", logfields.WithConfig(offerAdmissionCfg))
+
+		apHandlerOpts = append(apHandlerOpts, apspi.WithOfferAdmission(
+			admission.New(offerAdmissionCfg, acceptlist.NewManager(configStore), metrics),
+		))
+	}
+
+	activityPubService, err = apservice.New(apConfig,
+		apStore, deliveryStore, peerHealthRegistry, inboxfilter.New(inboxFilterStore, 0), quarantineStore,
+		activitySigner, httpTransport, apSigVerifier, pubSub, apClient, resourceResolver,
+		authTokenManager, metrics,
+		append(apHandlerOpts, activityProofOpts...)...,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create ActivityPub service: %s", err.Error())
@@ -925,11 +1032,31 @@ func startOrbServices(parameters *orbParameters) error {
 
 	go monitorActivities(activityPubService.Subscribe(), logger)
 
-	vcStore, err := store.Open(storeProviders.provider, "verifiable")
+	vcStore, err := store.Open(storeProviders.provider, "verifiable", store.NewTagGroup(anchorutil.VCStoreTagName))
 	if err != nil {
 		return fmt.Errorf("open store: %w", err)
 	}
 
+	casCollector, err := newCASGarbageCollector(parameters.cas.gc, coreCASClient, taskMgr, &gc.Providers{
+		AnchorRefStore:       anchorLinkStore,
+		AnchorLinkStore:      alStore,
+		VCStore:              vcStore,
+		AnchorLinksetBuilder: anchorLinksetBuilder,
+		Decompressor:         compression.New(compression.WithDefaultAlgorithms()),
+	})
+	if err != nil {
+		return err
+	}
+
+	casScrubber, err := newCASScrubber(parameters.cas, coreCASClient, taskMgr, &scrub.Providers{
+		Resolver:       casResolver,
+		AnchorRefStore: anchorLinkStore,
+		Metrics:        metrics,
+	}, storeProviders.provider)
+	if err != nil {
+		return err
+	}
+
 	anchorWriterProviders := &writer.Providers{
 		AnchorGraph:            anchorGraph,
 		DidAnchors:             didAnchors,
@@ -950,6 +1077,7 @@ func startOrbServices(parameters *orbParameters) error {
 		VCStore:                vcStore,
 		GeneratorRegistry:      generatorRegistry,
 		AnchorLinkBuilder:      anchorLinksetBuilder,
+		PeerHealth:             peerHealthRegistry,
 	}
 
 	anchorWriter, err := writer.New(parameters.sidetree.didNamespace,
@@ -967,15 +1095,38 @@ func startOrbServices(parameters *orbParameters) error {
 		return fmt.Errorf("failed to create writer: %s", err.Error())
 	}
 
+	witnessRevoker := rewitness.New(
+		&rewitness.Providers{
+			WitnessStore:    witnessProofStore,
+			VCStore:         vcStore,
+			AnchorGraph:     anchorGraph,
+			AnchorLinkStore: alStore,
+			StatusStore:     anchorEventStatusStore,
+			ProofHandler:    proofHandler,
+			PolicyHandler:   policyInspector,
+			DocLoader:       orbDocumentLoader,
+		},
+		parameters.dataURIMediaType,
+	)
+
 	opQueue, err := opqueue.New(parameters.opQueueParams, pubSub, storeProviders.provider,
 		taskMgr, expiryService, metrics)
 	if err != nil {
 		return fmt.Errorf("failed to create operation queue: %s", err.Error())
 	}
 
+	var batchQueue cutter.OperationQueue = opQueue
+
+	if parameters.anchorLimits.Enabled() {
+		logger.Info("Anchor limits are enabled. Batches that exceed the limits are split into multiple anchors.",
+			logfields.WithConfig(parameters.anchorLimits))
+
+		batchQueue = splitter.NewQueue(opQueue, parameters.anchorLimits, metrics)
+	}
+
 	// create new batch writer
 	batchWriter, err := batch.New(parameters.sidetree.didNamespace,
-		sidetreecontext.New(pc, anchorWriter, opQueue),
+		sidetreecontext.New(pc, anchorWriter, batchQueue),
 		batch.WithBatchTimeout(parameters.batchWriterTimeout))
 	if err != nil {
 		return fmt.Errorf("failed to create batch writer: %s", err.Error())
@@ -1100,7 +1251,7 @@ func startOrbServices(parameters *orbParameters) error {
 	}
 
 	nodeInfoService := nodeinfo.NewService(parameters.apServiceParams.serviceEndpoint(),
-		parameters.nodeInfoRefreshInterval, apStore, usingMongoDB)
+		parameters.nodeInfoRefreshInterval, apStore, usingMongoDB, nodeinfo.WithPeerHealth(peerHealthRegistry))
 
 	handlers := make([]restcommon.HTTPHandler, 0)
 
@@ -1159,10 +1310,25 @@ func startOrbServices(parameters *orbParameters) error {
 		),
 		auth.NewHandlerWrapper(policyhandler.New(policyStore), authTokenManager),
 		auth.NewHandlerWrapper(policyhandler.NewRetriever(policyStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewScopedConfigurator(policyStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewScopedRetriever(policyStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewScopedDeleter(policyStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewStatsRetriever(witnessProofStore), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewExplainer(anchorEventStatusStore, policyInspector), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewRevoker(witnessRevoker), authTokenManager),
+		auth.NewHandlerWrapper(policyhandler.NewSimulator(
+			policysimulator.New(&policysimulator.Providers{
+				WitnessStore:  witnessProofStore,
+				StatusStore:   anchorEventStatusStore,
+				WitnessPolicy: witnessPolicy,
+			}),
+		), authTokenManager),
 		auth.NewHandlerWrapper(logmonitorhandler.NewUpdateHandler(logMonitorStore), authTokenManager),
 		auth.NewHandlerWrapper(logmonitorhandler.NewRetriever(logMonitorStore), authTokenManager),
 		auth.NewHandlerWrapper(vcthandler.New(configStore, logMonitorStore), authTokenManager),
 		auth.NewHandlerWrapper(vcthandler.NewRetriever(configStore), authTokenManager),
+		auth.NewHandlerWrapper(vcthandler.NewMirrorsConfigurator(configStore, logMonitorStore), authTokenManager),
+		auth.NewHandlerWrapper(vcthandler.NewMirrorsRetriever(configStore), authTokenManager),
 		auth.NewHandlerWrapper(nodeinfo.NewHandler(nodeinfo.V2_0, nodeInfoService), authTokenManager),
 		auth.NewHandlerWrapper(nodeinfo.NewHandler(nodeinfo.V2_1, nodeInfoService), authTokenManager),
 		auth.NewHandlerWrapper(vcresthandler.New(vcStore), authTokenManager),
@@ -1170,6 +1336,16 @@ func startOrbServices(parameters *orbParameters) error {
 		auth.NewHandlerWrapper(allowedoriginsrest.NewReader(allowedOriginsStore), authTokenManager),
 		auth.NewHandlerWrapper(loglevels.NewWriteHandler(), authTokenManager),
 		auth.NewHandlerWrapper(loglevels.NewReadHandler(), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewDeliveryReader(apEndpointCfg, deliveryStore), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewRedeliveryWriter(apEndpointCfg, deliveryStore, activityPubService), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewPeerHealth(apEndpointCfg, peerHealthRegistry), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewActorCache(apEndpointCfg, apClient), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewInboxFilterReader(apEndpointCfg, inboxFilterStore), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewInboxFilterWriter(apEndpointCfg, inboxFilterStore), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewQuarantineReader(apEndpointCfg, quarantineStore), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewQuarantineWriter(apEndpointCfg, quarantineStore, activityPubService), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewBackfillReader(apEndpointCfg, backfiller), authTokenManager),
+		auth.NewHandlerWrapper(aphandler.NewBackfillWriter(apEndpointCfg, backfiller), authTokenManager),
 	)
 
 	handlers = append(handlers, endpointDiscoveryOp.GetRESTHandlers()...)
@@ -1182,6 +1358,18 @@ func startOrbServices(parameters *orbParameters) error {
 		)
 	}
 
+	if casCollector != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(gcrest.NewReportHandler(casCollector), authTokenManager),
+		)
+	}
+
+	if casScrubber != nil {
+		handlers = append(handlers,
+			auth.NewHandlerWrapper(scrubrest.NewStatusHandler(casScrubber), authTokenManager),
+		)
+	}
+
 	handlers = append(handlers, healthcheck.NewHandler(pubSub, logEndpoint, storeProviders.provider, km, parameters.enableMaintenanceMode))
 
 	httpServer := httpserver.New(
@@ -1250,30 +1438,128 @@ func newHTTPClient(parameters *orbParameters) (*http.Client, error) {
 func newCASClient(parameters *orbParameters, p dbProvider, casIRI *url.URL,
 	metrics metricsProvider.Metrics,
 ) (extendedcasclient.Client, error) {
+	var replicaIPFSClient *ipfscas.Client
+
+	if parameters.cas.localCASReplicateInIPFSEnabled {
+		replicaIPFSClient = ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
+			parameters.cas.cidFormatOptions()...)
+	}
+
 	switch {
 	case strings.EqualFold(parameters.cas.casType, "ipfs"):
 		logger.Info("Initializing Orb CAS with IPFS.")
 
 		return ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
-			extendedcasclient.WithCIDVersion(parameters.cas.cidVersion)), nil
+			parameters.cas.cidFormatOptions()...), nil
 	case strings.EqualFold(parameters.cas.casType, "local"):
 		logger.Info("Initializing Orb CAS with local storage provider.")
 
-		if parameters.cas.localCASReplicateInIPFSEnabled {
+		if replicaIPFSClient != nil {
 			logger.Info("Local CAS writes will be replicated in IPFS.")
+		}
 
-			return casstore.New(p, casIRI.String(),
-				ipfscas.New(parameters.cas.ipfsURL, parameters.cas.ipfsTimeout, defaultCasCacheSize, metrics,
-					extendedcasclient.WithCIDVersion(parameters.cas.cidVersion)),
-				metrics, defaultCasCacheSize, extendedcasclient.WithCIDVersion(parameters.cas.cidVersion))
-		} else {
-			return casstore.New(p, casIRI.String(), nil,
-				metrics, defaultCasCacheSize, extendedcasclient.WithCIDVersion(parameters.cas.cidVersion))
+		return casstore.New(p, casIRI.String(), replicaIPFSClient,
+			metrics, defaultCasCacheSize, parameters.cas.cidFormatOptions()...)
+	case strings.EqualFold(parameters.cas.casType, casTypeFilesystem), strings.EqualFold(parameters.cas.casType, casTypeS3):
+		store, err := newBlobStore(parameters.cas)
+		if err != nil {
+			return nil, err
+		}
+
+		if replicaIPFSClient != nil {
+			logger.Info("CAS writes will be replicated in IPFS.")
 		}
 
+		return blobcas.New(store, casIRI.String(), replicaIPFSClient, metrics, defaultCasCacheSize,
+			parameters.cas.cidFormatOptions()...), nil
 	default:
-		return nil, fmt.Errorf("%s is not a valid CAS type. It must be either local or ipfs", parameters.cas.casType)
+		return nil, fmt.Errorf("%s is not a valid CAS type. It must be one of local, ipfs, filesystem or s3",
+			parameters.cas.casType)
+	}
+}
+
+// newCASGarbageCollector registers the CAS garbage collection task if garbage collection is enabled and returns
+// the garbage collector (or nil if it's not enabled). Garbage collection is only supported by the local CAS.
+func newCASGarbageCollector(parameters *casGCParams, casClient extendedcasclient.Client, taskMgr *taskmgr.Manager,
+	providers *gc.Providers,
+) (*gc.Collector, error) {
+	if !parameters.enabled {
+		return nil, nil //nolint:nilnil
+	}
+
+	localCAS, ok := casClient.(*casstore.CAS)
+	if !ok {
+		logger.Warn("CAS garbage collection is enabled but it's only supported by the local CAS. " +
+			"Garbage collection is disabled.")
+
+		return nil, nil //nolint:nilnil
+	}
+
+	providers.CAS = localCAS
+
+	if parameters.archiveDir != "" {
+		archive, err := fscas.New(parameters.archiveDir)
+		if err != nil {
+			return nil, fmt.Errorf("create CAS garbage collection archive: %w", err)
+		}
+
+		providers.Archive = archive
+	}
+
+	return gc.New(parameters.config, providers, taskMgr), nil
+}
+
+// newCASScrubber registers the CAS scrub task if the scrubber is enabled and returns the scrubber (or nil if it's
+// not enabled). The scrubber is only supported by the local CAS.
+func newCASScrubber(parameters *casParams, casClient extendedcasclient.Client, taskMgr *taskmgr.Manager,
+	providers *scrub.Providers, storageProvider storage.Provider,
+) (*scrub.Scrubber, error) {
+	if !parameters.scrub.enabled {
+		return nil, nil //nolint:nilnil
+	}
+
+	localCAS, ok := casClient.(*casstore.CAS)
+	if !ok {
+		logger.Warn("The CAS scrubber is enabled but it's only supported by the local CAS. " +
+			"The scrubber is disabled.")
+
+		return nil, nil //nolint:nilnil
 	}
+
+	scrubStore, err := casscrub.New(storageProvider)
+	if err != nil {
+		return nil, fmt.Errorf("create CAS scrub store: %w", err)
+	}
+
+	providers.CAS = localCAS
+	providers.Store = scrubStore
+
+	cfg := parameters.scrub.config
+	cfg.IPFSEnabled = parameters.ipfsURL != ""
+
+	return scrub.New(cfg, providers, taskMgr), nil
+}
+
+func newBlobStore(parameters *casParams) (blobcas.Store, error) {
+	if strings.EqualFold(parameters.casType, casTypeFilesystem) {
+		logger.Info("Initializing Orb CAS with filesystem blob store.")
+
+		store, err := fscas.New(parameters.fsDir)
+		if err != nil {
+			return nil, fmt.Errorf("create filesystem CAS: %w", err)
+		}
+
+		return store, nil
+	}
+
+	logger.Info("Initializing Orb CAS with S3 blob store.", logfields.WithURLString(parameters.s3.Endpoint))
+
+	store, err := s3cas.New(parameters.s3)
+	if err != nil {
+		return nil, fmt.Errorf("create S3 CAS: %w", err)
+	}
+
+	return store, nil
 }
 
 func newPubSub(parameters *orbParameters) publisherSubscriber {
@@ -1779,7 +2065,7 @@ func (r *noOpRetriever) HealthCheck() error {
 
 func getActivityPubVerifier(parameters *orbParameters, km keyManager, cr crypto, apClient *client.Client) signatureVerifier {
 	if parameters.auth.httpSignaturesEnabled {
-		return httpsig.NewVerifier(apClient, cr, km)
+		return httpsig.NewVerifier(apClient, cr, km, httpsig.WithPublicKeyPurger(apClient))
 	}
 
 	logger.Warn("HTTP signature verification for ActivityPub is disabled.")
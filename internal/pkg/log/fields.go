@@ -63,6 +63,7 @@ const (
 	FieldCurrent                  = "current"
 	FieldNext                     = "next"
 	FieldTotal                    = "total"
+	FieldTotalDeferred            = "totalDeferred"
 	FieldMinimum                  = "minimum"
 	FieldType                     = "type"
 	FieldQuery                    = "query"
@@ -130,6 +131,7 @@ const (
 	FieldIssuer                   = "issuer"
 	FieldStatus                   = "status"
 	FieldLogURL                   = "logURL"
+	FieldLogURLs                  = "logURLs"
 	FieldNamespace                = "namespace"
 	FieldCanonicalRef             = "canonicalRef"
 	FieldAnchorString             = "anchorString"
@@ -152,6 +154,14 @@ const (
 	FieldNumActivitiesSynced      = "numActivitiesSynced"
 	FieldNextActivitySyncInterval = "nextActivitySyncInterval"
 	FieldRecordsProcessed         = "recordsProcessed"
+	FieldScore                    = "score"
+	FieldReason                   = "reason"
+	FieldDryRun                   = "dryRun"
+	FieldMarked                   = "marked"
+	FieldCandidates               = "candidates"
+	FieldDeleted                  = "deleted"
+	FieldArchived                 = "archived"
+	FieldSkipped                  = "skipped"
 )
 
 // WithMessageID sets the message-id field.
@@ -429,6 +439,11 @@ func WithTotal(value int) zap.Field {
 	return zap.Int(FieldTotal, value)
 }
 
+// WithTotalDeferred sets the total-deferred field.
+func WithTotalDeferred(value int) zap.Field {
+	return zap.Int(FieldTotalDeferred, value)
+}
+
 // WithMinimum sets the minimum field.
 func WithMinimum(value int) zap.Field {
 	return zap.Int(FieldMinimum, value)
@@ -795,6 +810,11 @@ func WithLogURLString(value string) zap.Field {
 	return zap.String(FieldLogURL, value)
 }
 
+// WithLogURLStrings sets the log-urls field.
+func WithLogURLStrings(value ...string) zap.Field {
+	return zap.Array(FieldLogURLs, NewStringArrayMarshaller(value))
+}
+
 // WithNamespace sets the namespace field.
 func WithNamespace(value string) zap.Field {
 	return zap.String(FieldNamespace, value)
@@ -910,6 +930,46 @@ func WithRecordsProcessed(value int) zap.Field {
 	return zap.Int(FieldRecordsProcessed, value)
 }
 
+// WithScore sets the score field.
+func WithScore(value float64) zap.Field {
+	return zap.Float64(FieldScore, value)
+}
+
+// WithReason sets the reason field.
+func WithReason(value string) zap.Field {
+	return zap.String(FieldReason, value)
+}
+
+// WithDryRun sets the dryRun field.
+func WithDryRun(value bool) zap.Field {
+	return zap.Bool(FieldDryRun, value)
+}
+
+// WithMarked sets the marked field.
+func WithMarked(value int) zap.Field {
+	return zap.Int(FieldMarked, value)
+}
+
+// WithCandidates sets the candidates field.
+func WithCandidates(value int) zap.Field {
+	return zap.Int(FieldCandidates, value)
+}
+
+// WithDeleted sets the deleted field.
+func WithDeleted(value int) zap.Field {
+	return zap.Int(FieldDeleted, value)
+}
+
+// WithArchived sets the archived field.
+func WithArchived(value int) zap.Field {
+	return zap.Int(FieldArchived, value)
+}
+
+// WithSkipped sets the skipped field.
+func WithSkipped(value int) zap.Field {
+	return zap.Int(FieldSkipped, value)
+}
+
 type jsonMarshaller struct {
 	key string
 	obj interface{}
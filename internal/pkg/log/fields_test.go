@@ -63,7 +63,8 @@ func TestStandardFields(t *testing.T) {
 			WithCreatedTime(now), WithWitnessURI(u1), WithWitnessURIs(u1, u2), WithWitnessPolicy("some policy"),
 			WithAnchorOrigin(u1.String()), WithOperationType("Create"), WithCoreIndex("1234"),
 			WithMaxOperationsToRepost(300), WithMaxActivitiesToSync(11), WithNextActivitySyncInterval(3*time.Second),
-			WithNumActivitiesSynced(123), WithRecordsProcessed(23),
+			WithNumActivitiesSynced(123), WithRecordsProcessed(23), WithScore(0.75),
+			WithReason("quota-exceeded"), WithTotalDeferred(5),
 		)
 
 		t.Logf(stdOut.String())
@@ -124,6 +125,9 @@ func TestStandardFields(t *testing.T) {
 		require.Equal(t, "3s", l.NextActivitySyncInterval)
 		require.Equal(t, 123, l.NumActivitiesSynced)
 		require.Equal(t, 23, l.RecordsProcessed)
+		require.Equal(t, 0.75, l.Score)
+		require.Equal(t, "quota-exceeded", l.Reason)
+		require.Equal(t, 5, l.TotalDeferred)
 	})
 
 	t.Run("json fields 2", func(t *testing.T) {
@@ -298,7 +302,8 @@ func TestStandardFields(t *testing.T) {
 
 		logger.Info("Some message",
 			WithMaxSizeUInt64(30), WithURLString(u1.String()), WithLogURLString(u3.String()), WithIndexUint64(7),
-			WithLogSpec(logSpec),
+			WithLogSpec(logSpec), WithLogURLStrings(u1.String(), u2.String()),
+			WithDryRun(true), WithMarked(11), WithCandidates(12), WithDeleted(13), WithArchived(14), WithSkipped(15),
 		)
 
 		l := unmarshalLogData(t, stdOut.Bytes())
@@ -306,8 +311,15 @@ func TestStandardFields(t *testing.T) {
 		require.Equal(t, 30, l.MaxSize)
 		require.Equal(t, u1.String(), l.URL)
 		require.Equal(t, u3.String(), l.LogURL)
+		require.Equal(t, []string{u1.String(), u2.String()}, l.LogURLs)
 		require.Equal(t, 7, l.Index)
 		require.Equal(t, logSpec, l.LogSpec)
+		require.True(t, l.DryRun)
+		require.Equal(t, 11, l.Marked)
+		require.Equal(t, 12, l.Candidates)
+		require.Equal(t, 13, l.Deleted)
+		require.Equal(t, 14, l.Archived)
+		require.Equal(t, 15, l.Skipped)
 	})
 }
 
@@ -357,6 +369,7 @@ type logData struct {
 	Current                  string              `json:"current"`
 	Next                     string              `json:"next"`
 	Total                    int                 `json:"total"`
+	TotalDeferred            int                 `json:"totalDeferred"`
 	Minimum                  int                 `json:"minimum"`
 	Type                     string              `json:"type"`
 	Query                    *mockObject         `json:"query"`
@@ -433,6 +446,7 @@ type logData struct {
 	Issuer                   string              `json:"issuer"`
 	Status                   string              `json:"status"`
 	LogURL                   string              `json:"logUrl"`
+	LogURLs                  []string            `json:"logUrls"`
 	Namespace                string              `json:"namespace"`
 	CanonicalRef             string              `json:"canonicalRef"`
 	AnchorString             string              `json:"anchorString"`
@@ -454,6 +468,14 @@ type logData struct {
 	NextActivitySyncInterval string              `json:"nextActivitySyncInterval"`
 	NumActivitiesSynced      int                 `json:"numActivitiesSynced"`
 	RecordsProcessed         int                 `json:"recordsProcessed"`
+	Score                    float64             `json:"score"`
+	Reason                   string              `json:"reason"`
+	DryRun                   bool                `json:"dryRun"`
+	Marked                   int                 `json:"marked"`
+	Candidates               int                 `json:"candidates"`
+	Deleted                  int                 `json:"deleted"`
+	Archived                 int                 `json:"archived"`
+	Skipped                  int                 `json:"skipped"`
 }
 
 func unmarshalLogData(t *testing.T, b []byte) *logData {
@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activityproof
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/url"
+	"testing"
+
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/vcsigner"
+)
+
+var (
+	service1IRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+)
+
+func TestSignAndVerify(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	publicKey := newPublicKey(service1IRI, pubKey)
+	service1 := aptestutil.NewMockService(service1IRI, aptestutil.WithPublicKey(publicKey))
+
+	apClient := mocks.NewActivitPubClient().WithActor(service1).WithPublicKey(publicKey)
+
+	docLoader := testutil.GetLoader(t)
+
+	signer := NewSigner(&ed25519Crypto{privKey: privKey}, &mockkms.KeyManager{}, "123", publicKey.ID(), docLoader)
+	verifier := NewVerifier(apClient, docLoader, WithProofRequired(true))
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+		vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+		vocab.WithActor(service1IRI),
+		vocab.WithTo(service2IRI),
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		signedActivity, err := signer.Sign(follow)
+		require.NoError(t, err)
+
+		p, ok := signedActivity.Value(proofProperty)
+		require.True(t, ok)
+		require.NotNil(t, p)
+		require.True(t, signedActivity.Context().Contains(vcsigner.CtxJWS))
+
+		require.NoError(t, verifier.Verify(signedActivity))
+
+		// Ensure that the proof survives a marshal/unmarshal round trip.
+		activityBytes, err := json.Marshal(signedActivity)
+		require.NoError(t, err)
+
+		activity := &vocab.ActivityType{}
+		require.NoError(t, json.Unmarshal(activityBytes, activity))
+
+		require.NoError(t, verifier.Verify(activity))
+
+		// Signing again replaces the existing proof.
+		resignedActivity, err := signer.Sign(signedActivity)
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(resignedActivity))
+	})
+
+	t.Run("Tampered activity", func(t *testing.T) {
+		signedActivity, err := signer.Sign(follow)
+		require.NoError(t, err)
+
+		signedActivity.SetID(aptestutil.NewActivityID(service1IRI))
+
+		err = verifier.Verify(signedActivity)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+
+	t.Run("No proof", func(t *testing.T) {
+		err := verifier.Verify(follow)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrProofNotFound))
+		require.True(t, orberrors.IsBadRequest(err))
+
+		require.NoError(t, NewVerifier(apClient, docLoader).Verify(follow))
+	})
+
+	t.Run("Key not owned by actor", func(t *testing.T) {
+		signedActivity, err := signer.Sign(follow)
+		require.NoError(t, err)
+
+		signedActivity.SetActor(service2IRI)
+
+		v := NewVerifier(mocks.NewActivitPubClient().WithActor(aptestutil.NewMockService(service2IRI)).
+			WithPublicKey(publicKey), docLoader)
+
+		err = v.Verify(signedActivity)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "is not a key of actor")
+	})
+
+	t.Run("Get actor error", func(t *testing.T) {
+		signedActivity, err := signer.Sign(follow)
+		require.NoError(t, err)
+
+		errExpected := orberrors.NewTransient(errors.New("injected client error"))
+
+		v := NewVerifier(mocks.NewActivitPubClient().WithError(errExpected), docLoader)
+
+		err = v.Verify(signedActivity)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Get key error", func(t *testing.T) {
+		km := &mockkms.KeyManager{GetKeyErr: errors.New("injected get key error")}
+
+		_, err := NewSigner(&ed25519Crypto{privKey: privKey}, km, "123", publicKey.ID(), docLoader).Sign(follow)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), km.GetKeyErr.Error())
+	})
+
+	t.Run("Sign error", func(t *testing.T) {
+		cr := &ed25519Crypto{err: errors.New("injected sign error")}
+
+		_, err := NewSigner(cr, &mockkms.KeyManager{}, "123", publicKey.ID(), docLoader).Sign(follow)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), cr.err.Error())
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	const ctx = "https://example.com/context"
+
+	require.Equal(t, ctx, withContext(nil, ctx))
+	require.Equal(t, ctx, withContext(ctx, ctx))
+	require.Equal(t, []interface{}{"ctx1", ctx}, withContext("ctx1", ctx))
+	require.Equal(t, []interface{}{"ctx1", ctx}, withContext([]interface{}{"ctx1"}, ctx))
+	require.Equal(t, []interface{}{"ctx1", ctx}, withContext([]interface{}{"ctx1", ctx}, ctx))
+}
+
+func newPublicKey(serviceIRI *url.URL, pubKey ed25519.PublicKey) *vocab.PublicKeyType {
+	return vocab.NewPublicKey(
+		vocab.WithID(testutil.NewMockID(serviceIRI, "/keys/main-key")),
+		vocab.WithOwner(serviceIRI),
+		vocab.WithPublicKeyPem(string(pem.EncodeToMemory(&pem.Block{Type: "Ed25519", Bytes: pubKey}))),
+	)
+}
+
+type ed25519Crypto struct {
+	privKey ed25519.PrivateKey
+	err     error
+}
+
+func (c *ed25519Crypto) Sign(msg []byte, _ interface{}) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return ed25519.Sign(c.privKey, msg), nil
+}
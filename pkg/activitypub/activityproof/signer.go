@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activityproof
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
+	ariessigner "github.com/hyperledger/aries-framework-go/pkg/doc/signature/signer"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	"github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/vcsigner"
+)
+
+var logger = log.New("activity-proof")
+
+const (
+	contextProperty = "@context"
+	proofProperty   = "proof"
+)
+
+type keyManager interface {
+	Get(keyID string) (interface{}, error)
+}
+
+type crypto interface {
+	Sign(msg []byte, kh interface{}) ([]byte, error)
+}
+
+// Signer adds a Linked Data proof (JsonWebSignature2020) to an activity using the service's signing key.
+// The proof allows the activity to be verified independently of the transport, for example, when the
+// activity is retrieved from the outbox of another service.
+type Signer struct {
+	crypto       crypto
+	keyManager   keyManager
+	kmsKeyID     string
+	publicKeyIRI *url.URL
+	docLoader    ld.DocumentLoader
+	now          func() time.Time
+}
+
+// NewSigner returns a new activity signer. The KMS key ID is the ID of the signing key in the KMS and
+// the public key IRI is the ID of the corresponding public key of the service actor (which is used as
+// the verification method in the proof).
+func NewSigner(cr crypto, km keyManager, kmsKeyID string, publicKeyIRI *url.URL,
+	docLoader ld.DocumentLoader,
+) *Signer {
+	return &Signer{
+		crypto:       cr,
+		keyManager:   km,
+		kmsKeyID:     kmsKeyID,
+		publicKeyIRI: publicKeyIRI,
+		docLoader:    docLoader,
+		now:          time.Now,
+	}
+}
+
+// Sign returns a copy of the given activity with an embedded Linked Data proof. Any existing proof is replaced.
+func (s *Signer) Sign(activity *vocab.ActivityType) (*vocab.ActivityType, error) {
+	doc, err := toDocument(activity)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(doc, proofProperty)
+
+	doc[contextProperty] = withContext(doc[contextProperty], vcsigner.CtxJWS)
+
+	docBytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	kh, err := s.keyManager.Get(s.kmsKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("get KMS key handle: %w", err)
+	}
+
+	created := s.now()
+
+	signer := ariessigner.New(jsonwebsignature2020.New(
+		suite.WithSigner(&kmsSigner{crypto: s.crypto, keyHandle: kh}),
+	))
+
+	signedBytes, err := signer.Sign(
+		&ariessigner.Context{
+			SignatureType:           vcsigner.JSONWebSignature2020,
+			SignatureRepresentation: proof.SignatureJWS,
+			VerificationMethod:      s.publicKeyIRI.String(),
+			Purpose:                 vcsigner.AssertionMethod,
+			Created:                 &created,
+		},
+		docBytes, jsonld.WithDocumentLoader(s.docLoader),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("add proof to activity [%s]: %w", activity.ID(), err)
+	}
+
+	signedActivity := &vocab.ActivityType{}
+
+	err = json.Unmarshal(signedBytes, signedActivity)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal signed activity [%s]: %w", activity.ID(), err)
+	}
+
+	logger.Debug("Added proof to activity", logfields.WithActivityID(activity.ID()),
+		logfields.WithKeyIRI(s.publicKeyIRI))
+
+	return signedActivity, nil
+}
+
+func toDocument(activity *vocab.ActivityType) (map[string]interface{}, error) {
+	activityBytes, err := json.Marshal(activity)
+	if err != nil {
+		return nil, fmt.Errorf("marshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	doc := make(map[string]interface{})
+
+	err = json.Unmarshal(activityBytes, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	return doc, nil
+}
+
+// withContext adds the given context to the @context property (if it isn't already there).
+func withContext(ctx interface{}, context string) interface{} {
+	switch c := ctx.(type) {
+	case nil:
+		return context
+	case string:
+		if c == context {
+			return c
+		}
+
+		return []interface{}{c, context}
+	case []interface{}:
+		for _, v := range c {
+			if v == context {
+				return c
+			}
+		}
+
+		return append(c, context)
+	default:
+		return []interface{}{c, context}
+	}
+}
+
+type kmsSigner struct {
+	crypto    crypto
+	keyHandle interface{}
+}
+
+// Sign signs the given data using the KMS key.
+func (s *kmsSigner) Sign(data []byte) ([]byte, error) {
+	return s.crypto.Sign(data, s.keyHandle)
+}
+
+// Alg returns the algorithm. An empty string is returned since the algorithm is determined by the KMS key.
+func (s *kmsSigner) Alg() string {
+	return ""
+}
@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package activityproof
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/jsonwebsignature2020"
+	ariesverifier "github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
+	"github.com/piprate/json-gold/ld"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// ErrProofNotFound indicates that the activity doesn't contain a proof.
+var ErrProofNotFound = errors.New("activity proof not found")
+
+type actorRetriever interface {
+	GetPublicKey(keyIRI *url.URL) (*vocab.PublicKeyType, error)
+	GetActor(actorIRI *url.URL) (*vocab.ActorType, error)
+}
+
+// Verifier verifies the Linked Data proof embedded in an activity.
+type Verifier struct {
+	actorRetriever actorRetriever
+	keyResolver    *httpsig.KeyResolver
+	docLoader      ld.DocumentLoader
+	required       bool
+}
+
+// VerifierOpt is a verifier option.
+type VerifierOpt func(v *Verifier)
+
+// WithProofRequired indicates that activities without a proof should be rejected. If not set then
+// the proof is verified only if it is present in the activity.
+func WithProofRequired(required bool) VerifierOpt {
+	return func(v *Verifier) {
+		v.required = required
+	}
+}
+
+// NewVerifier returns a new activity proof verifier.
+func NewVerifier(actorRetriever actorRetriever, docLoader ld.DocumentLoader, opts ...VerifierOpt) *Verifier {
+	v := &Verifier{
+		actorRetriever: actorRetriever,
+		keyResolver:    httpsig.NewKeyResolver(actorRetriever),
+		docLoader:      docLoader,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Verify verifies the proof in the given activity and ensures that the verification method of the proof
+// is the public key of the activity's actor. If the activity has no proof then ErrProofNotFound is returned
+// if a proof is required, otherwise nil is returned. A bad request error is returned if the proof is invalid.
+func (v *Verifier) Verify(activity *vocab.ActivityType) error {
+	doc, err := toDocument(activity)
+	if err != nil {
+		return err
+	}
+
+	proofs, err := proof.GetProofs(doc)
+	if err != nil {
+		if errors.Is(err, proof.ErrProofNotFound) {
+			if v.required {
+				return orberrors.NewBadRequest(fmt.Errorf("activity [%s]: %w", activity.ID(), ErrProofNotFound))
+			}
+
+			logger.Debug("Activity has no proof", logfields.WithActivityID(activity.ID()))
+
+			return nil
+		}
+
+		return orberrors.NewBadRequest(fmt.Errorf("get proofs from activity [%s]: %w", activity.ID(), err))
+	}
+
+	for _, p := range proofs {
+		if e := v.verifyKeyOwner(activity, p); e != nil {
+			return e
+		}
+	}
+
+	docBytes, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	verifier, err := ariesverifier.New(v.keyResolver,
+		jsonwebsignature2020.New(suite.WithVerifier(&publicKeyVerifier{})),
+	)
+	if err != nil {
+		return fmt.Errorf("create verifier: %w", err)
+	}
+
+	err = verifier.Verify(docBytes, jsonld.WithDocumentLoader(v.docLoader))
+	if err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("verify proof of activity [%s]: %w", activity.ID(), err))
+	}
+
+	logger.Debug("Verified activity proof", logfields.WithActivityID(activity.ID()),
+		logfields.WithActorIRI(activity.Actor()))
+
+	return nil
+}
+
+// verifyKeyOwner ensures that the verification method of the proof is the public key of the activity's actor.
+// Otherwise, it could be an attempt to impersonate an actor.
+func (v *Verifier) verifyKeyOwner(activity *vocab.ActivityType, p *proof.Proof) error {
+	if activity.Actor() == nil {
+		return orberrors.NewBadRequestf("activity [%s] has no actor", activity.ID())
+	}
+
+	keyID, err := p.PublicKeyID()
+	if err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("proof of activity [%s]: %w", activity.ID(), err))
+	}
+
+	keyIRI, err := url.Parse(keyID)
+	if err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("parse verification method [%s]: %w", keyID, err))
+	}
+
+	actor, err := v.actorRetriever.GetActor(activity.Actor())
+	if err != nil {
+		return fmt.Errorf("get actor [%s]: %w", activity.Actor(), err)
+	}
+
+	if actor.PublicKey() == nil || actor.PublicKey().ID().String() != keyIRI.String() {
+		return orberrors.NewBadRequestf("verification method [%s] of activity [%s] is not a key of actor [%s]",
+			keyIRI, activity.ID(), activity.Actor())
+	}
+
+	return nil
+}
+
+// publicKeyVerifier verifies signatures using the public key of an actor (resolved by httpsig.KeyResolver).
+type publicKeyVerifier struct{}
+
+// Verify verifies the signature over the given message.
+func (pv *publicKeyVerifier) Verify(pubKey *ariesverifier.PublicKey, msg, signature []byte) error {
+	return httpsig.VerifySignature(pubKey, msg, signature)
+}
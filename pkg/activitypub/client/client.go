@@ -100,6 +100,8 @@ type Config struct {
 
 type refreshingCache interface {
 	Get(key interface{}) (interface{}, error)
+	MarkAsStale(key interface{})
+	Remove(key interface{}) interface{}
 	Start()
 	Stop()
 }
@@ -115,15 +117,36 @@ type Client struct {
 	fetchPublicKey verifiable.PublicKeyFetcher
 	resolver       serviceResolver
 	tracer         trace.Tracer
+	purgeDIDKey    didPublicKeyPurger
+}
+
+type didPublicKeyPurger func(issuerID, keyID string) error
+
+// Opt is a client option.
+type Opt func(c *Client)
+
+// WithDIDPublicKeyPurger sets the function that's invoked to purge a public key that was resolved from
+// a DID (for example, from a persistent public key store) when the public key is purged from the client's cache.
+func WithDIDPublicKeyPurger(purge func(issuerID, keyID string) error) Opt {
+	return func(c *Client) {
+		c.purgeDIDKey = purge
+	}
 }
 
 // New returns a new ActivityPub client.
-func New(cfg Config, t httpTransport, fetchPublicKey verifiable.PublicKeyFetcher, resolver serviceResolver) *Client {
+func New(cfg Config, t httpTransport, fetchPublicKey verifiable.PublicKeyFetcher, resolver serviceResolver,
+	opts ...Opt,
+) *Client {
 	c := &Client{
 		httpTransport:  t,
 		fetchPublicKey: fetchPublicKey,
 		resolver:       resolver,
 		tracer:         tracing.Tracer(tracing.SubsystemActivityPub),
+		purgeDIDKey:    func(issuerID, keyID string) error { return nil },
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
 
 	config := resolveConfig(&cfg)
@@ -177,6 +200,81 @@ func (c *Client) GetActor(actorIRI *url.URL) (*vocab.ActorType, error) {
 	return result.(*vocab.ActorType), nil //nolint:forcetypeassert
 }
 
+// MarkActorAsStale marks the cached actor, along with the actor's public key, as stale so that they are reloaded
+// as soon as possible. This should be called when an actor is known to have changed, for example, after an
+// 'Update' activity was received (and authenticated) for an actor that rotated its key. The public key is
+// located from the cached actor rather than from the actor in the activity, which is not trusted.
+//
+//nolint:interfacer
+func (c *Client) MarkActorAsStale(actorIRI *url.URL) {
+	logger.Debug("Marking actor as stale", logfields.WithActorIRI(actorIRI))
+
+	if value, err := c.actorCache.Get(actorIRI.String()); err == nil {
+		actor := value.(*vocab.ActorType) //nolint:forcetypeassert
+
+		if pubKey := actor.PublicKey(); pubKey != nil && pubKey.ID() != nil {
+			c.publicKeyCache.MarkAsStale(pubKey.ID().String())
+		}
+	}
+
+	c.actorCache.MarkAsStale(actorIRI.String())
+}
+
+// PurgeActor removes the actor, along with the actor's public key, from the cache so that they are reloaded
+// from the source upon next access.
+//
+//nolint:interfacer
+func (c *Client) PurgeActor(actorIRI *url.URL) error {
+	logger.Info("Purging actor from cache", logfields.WithActorIRI(actorIRI))
+
+	value := c.actorCache.Remove(actorIRI.String())
+	if value == nil {
+		return nil
+	}
+
+	actor := value.(*vocab.ActorType) //nolint:forcetypeassert
+
+	if pubKey := actor.PublicKey(); pubKey != nil && pubKey.ID() != nil {
+		return c.PurgePublicKey(pubKey.ID())
+	}
+
+	return nil
+}
+
+// PurgePublicKey removes the public key, along with the owner of the key, from the cache so that they are
+// reloaded from the source upon next access. This should be called when signature verification fails using
+// a cached key since the owner may have rotated its key. If the key was resolved from a DID then the key
+// is also purged using the DID public key purger (if configured).
+//
+//nolint:interfacer
+func (c *Client) PurgePublicKey(keyIRI *url.URL) error {
+	logger.Info("Purging public key from cache", logfields.WithKeyIRI(keyIRI))
+
+	value := c.publicKeyCache.Remove(keyIRI.String())
+
+	if value != nil {
+		pubKey := value.(*vocab.PublicKeyType) //nolint:forcetypeassert
+
+		if owner := pubKey.Owner(); owner != nil {
+			c.actorCache.Remove(owner.String())
+		}
+	}
+
+	if docutil.IsDID(keyIRI.String()) {
+		did, keyID, err := docutil.ParseKeyURI(keyIRI.String())
+		if err != nil {
+			return fmt.Errorf("parse key IRI [%s]: %w", keyIRI, err)
+		}
+
+		err = c.purgeDIDKey(did, keyID)
+		if err != nil {
+			return fmt.Errorf("purge public key - DID [%s], KeyID [%s]: %w", did, keyID, err)
+		}
+	}
+
+	return nil
+}
+
 func (c *Client) loadActor(actorIRI string) (*vocab.ActorType, error) {
 	logger.Debug("Cache miss. Resolving actor for target.", logfields.WithTarget(actorIRI))
 
@@ -238,6 +238,163 @@ func TestClient_GetActor(t *testing.T) {
 }
 
 //nolint:maintidx
+func TestClient_MarkActorAsStale(t *testing.T) {
+	actorIRI := testutil.MustParseURL("https://example.com/services/service1")
+
+	actor := aptestutil.NewMockService(actorIRI)
+
+	actorBytes, err := json.Marshal(actor)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+
+	_, err = rw.Write(actorBytes)
+	require.NoError(t, err)
+
+	result := rw.Result()
+
+	httpClient := &mocks.HTTPTransport{}
+	httpClient.GetReturns(result, nil)
+
+	c := newMockClient(httpClient)
+
+	c.Start()
+	defer c.Stop()
+
+	_, err = c.GetActor(actorIRI)
+	require.NoError(t, err)
+
+	require.NotPanics(t, func() { c.MarkActorAsStale(actorIRI) })
+
+	// The stale actor should still be returned from the cache until it's reloaded.
+	a, err := c.GetActor(actorIRI)
+	require.NoError(t, err)
+	require.Equal(t, actorIRI.String(), a.ID().String())
+
+	require.NoError(t, result.Body.Close())
+}
+
+func TestClient_Purge(t *testing.T) {
+	actorIRI := testutil.MustParseURL("https://example.com/services/service1")
+	keyIRI := testutil.NewMockID(actorIRI, "/keys/main-key")
+
+	actorBytes, err := json.Marshal(aptestutil.NewMockService(actorIRI))
+	require.NoError(t, err)
+
+	publicKeyBytes, err := json.Marshal(aptestutil.NewMockPublicKey(actorIRI))
+	require.NoError(t, err)
+
+	newResponse := func(respBytes []byte) *http.Response {
+		rw := httptest.NewRecorder()
+
+		_, e := rw.Write(respBytes)
+		require.NoError(t, e)
+
+		return rw.Result() //nolint:bodyclose
+	}
+
+	// The actor and public key are each retrieved twice: before and after the purge.
+	newHTTPClient := func() *mocks.HTTPTransport {
+		httpClient := &mocks.HTTPTransport{}
+		httpClient.GetReturnsOnCall(0, newResponse(actorBytes), nil)
+		httpClient.GetReturnsOnCall(1, newResponse(publicKeyBytes), nil)
+		httpClient.GetReturnsOnCall(2, newResponse(actorBytes), nil)
+		httpClient.GetReturnsOnCall(3, newResponse(publicKeyBytes), nil)
+
+		return httpClient
+	}
+
+	t.Run("Purge actor", func(t *testing.T) {
+		httpClient := newHTTPClient()
+
+		c := newMockClient(httpClient)
+
+		_, err = c.GetActor(actorIRI)
+		require.NoError(t, err)
+
+		_, err = c.GetPublicKey(keyIRI)
+		require.NoError(t, err)
+
+		require.Equal(t, 2, httpClient.GetCallCount())
+
+		require.NoError(t, c.PurgeActor(actorIRI))
+
+		// Both the actor and the actor's public key should be reloaded.
+		_, err = c.GetActor(actorIRI)
+		require.NoError(t, err)
+
+		_, err = c.GetPublicKey(keyIRI)
+		require.NoError(t, err)
+
+		require.Equal(t, 4, httpClient.GetCallCount())
+
+		// Purging an actor that's not in the cache should not fail.
+		require.NoError(t, c.PurgeActor(testutil.MustParseURL("https://example.com/services/service2")))
+	})
+
+	t.Run("Purge public key", func(t *testing.T) {
+		httpClient := newHTTPClient()
+
+		c := newMockClient(httpClient)
+
+		_, err = c.GetActor(actorIRI)
+		require.NoError(t, err)
+
+		_, err = c.GetPublicKey(keyIRI)
+		require.NoError(t, err)
+
+		require.NoError(t, c.PurgePublicKey(keyIRI))
+
+		// Both the public key and the owner of the key should be reloaded.
+		_, err = c.GetActor(actorIRI)
+		require.NoError(t, err)
+
+		_, err = c.GetPublicKey(keyIRI)
+		require.NoError(t, err)
+
+		require.Equal(t, 4, httpClient.GetCallCount())
+	})
+
+	t.Run("Purge DID public key", func(t *testing.T) {
+		didKeyIRI := testutil.MustParseURL("did:web:example.com:services:service1#123456")
+
+		var purgedDID, purgedKeyID string
+
+		c := New(Config{}, &mocks.HTTPTransport{},
+			func(issuerID, keyID string) (*verifier.PublicKey, error) {
+				return &verifier.PublicKey{}, nil
+			}, &wellKnownResolver{},
+			WithDIDPublicKeyPurger(func(issuerID, keyID string) error {
+				purgedDID = issuerID
+				purgedKeyID = keyID
+
+				return nil
+			}),
+		)
+
+		require.NoError(t, c.PurgePublicKey(didKeyIRI))
+		require.Equal(t, "did:web:example.com:services:service1", purgedDID)
+		require.Equal(t, "123456", purgedKeyID)
+	})
+
+	t.Run("Purge DID public key error", func(t *testing.T) {
+		errExpected := errors.New("injected purge error")
+
+		c := New(Config{}, &mocks.HTTPTransport{},
+			func(issuerID, keyID string) (*verifier.PublicKey, error) {
+				return &verifier.PublicKey{}, nil
+			}, &wellKnownResolver{},
+			WithDIDPublicKeyPurger(func(issuerID, keyID string) error {
+				return errExpected
+			}),
+		)
+
+		err := c.PurgePublicKey(testutil.MustParseURL("did:web:example.com:services:service1#123456"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
 func TestClient_GetReferences(t *testing.T) {
 	log.SetLevel("activitypub_client", log.DEBUG)
 
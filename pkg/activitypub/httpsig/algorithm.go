@@ -86,6 +86,12 @@ func (a *SignatureHashAlgorithm) Verify(secret httpsig.Secret, data, signature [
 
 	logger.Debug("Got public key", logfields.WithKeyType(pubKey.Type), logfields.WithKeyID(secret.KeyID))
 
+	return VerifySignature(pubKey, data, signature)
+}
+
+// VerifySignature verifies the signature over data using the given public key. The key type is
+// determined from the PEM block type of the actor's public key (see KeyResolver).
+func VerifySignature(pubKey *ariesverifier.PublicKey, data, signature []byte) error {
 	switch {
 	case strings.HasPrefix(pubKey.Type, "Ed25519"):
 		return ariesverifier.NewEd25519SignatureVerifier().Verify(pubKey, data, signature)
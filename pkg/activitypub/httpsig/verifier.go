@@ -11,7 +11,9 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/bluele/gcache"
 	httpsig "github.com/igor-pavlenko/httpsignatures-go"
 	"github.com/trustbloc/logutil-go/pkg/log"
 	"go.uber.org/zap"
@@ -35,18 +37,50 @@ type verifier interface {
 	Verify(r *http.Request) error
 }
 
+type publicKeyPurger interface {
+	PurgePublicKey(keyIRI *url.URL) error
+}
+
+const (
+	defaultMinPurgeInterval = time.Minute
+	maxPurgedKeys           = 1000
+)
+
 // Verifier verifies signatures of HTTP requests.
 type Verifier struct {
-	actorRetriever actorRetriever
-	verifier       func() verifier
+	actorRetriever   actorRetriever
+	verifier         func() verifier
+	purger           publicKeyPurger
+	minPurgeInterval time.Duration
+	purgedKeys       gcache.Cache
+}
+
+// VerifierOpt is a verifier option.
+type VerifierOpt func(v *Verifier)
+
+// WithPublicKeyPurger sets the purger that's used to remove a cached public key (along with the owner of the key)
+// when signature verification fails. The request is then verified once more using the reloaded key. This allows
+// a request to be verified after the sender has rotated its key and the cache still contains the old key.
+func WithPublicKeyPurger(purger publicKeyPurger) VerifierOpt {
+	return func(v *Verifier) {
+		v.purger = purger
+	}
+}
+
+// WithMinPurgeInterval sets the minimum interval between purges of the same public key. This prevents
+// a remote server from causing excessive reloads of a key by sending requests with invalid signatures.
+func WithMinPurgeInterval(value time.Duration) VerifierOpt {
+	return func(v *Verifier) {
+		v.minPurgeInterval = value
+	}
 }
 
 // NewVerifier returns a new HTTP signature verifier.
-func NewVerifier(actorRetriever actorRetriever, cr crypto, km keyManager) *Verifier {
+func NewVerifier(actorRetriever actorRetriever, cr crypto, km keyManager, opts ...VerifierOpt) *Verifier {
 	algo := NewVerifierAlgorithm(cr, km, NewKeyResolver(actorRetriever))
 	secretRetriever := &SecretRetriever{}
 
-	return &Verifier{
+	v := &Verifier{
 		actorRetriever: actorRetriever,
 		verifier: func() verifier {
 			// Return a new instance for each verification since the HTTP signature
@@ -56,18 +90,45 @@ func NewVerifier(actorRetriever actorRetriever, cr crypto, km keyManager) *Verif
 
 			return hs
 		},
+		minPurgeInterval: defaultMinPurgeInterval,
 	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	v.purgedKeys = gcache.New(maxPurgedKeys).LRU().Expiration(v.minPurgeInterval).Build()
+
+	return v
 }
 
 // VerifyRequest verifies the following:
 // - HTTP signature on the request.
 // - Ensures that the key ID in the request header is owned by the actor.
 //
+// If verification fails and a public key purger is configured then the cached public key (and owner) is purged
+// and the request is verified once more, since the sender may have rotated its key.
+//
 // Returns:
 // - true if the signature was successfully verified, otherwise false.
 // - Actor IRI if the signature was successfully verified.
 // - An error if the signature could not be verified due to server error.
 func (v *Verifier) VerifyRequest(req *http.Request) (bool, *url.URL, error) {
+	ok, actorIRI, err := v.verifyRequest(req)
+	if err != nil || ok || v.purger == nil {
+		return ok, actorIRI, err
+	}
+
+	if !v.purgePublicKey(req) {
+		return false, nil, nil
+	}
+
+	logger.Debug("Verifying request again after purging cached public key.", logfields.WithRequestURL(req.URL))
+
+	return v.verifyRequest(req)
+}
+
+func (v *Verifier) verifyRequest(req *http.Request) (bool, *url.URL, error) {
 	logger.Debug("Verifying request.", logfields.WithRequestHeaders(req.Header))
 
 	verified, err := v.verify(req)
@@ -156,6 +217,44 @@ func (v *Verifier) verify(req *http.Request) (bool, error) {
 	return false, nil
 }
 
+// purgePublicKey purges the public key referenced in the Signature header of the request so that it's reloaded
+// upon next access. False is returned if the key wasn't purged, for example, if the key was purged recently.
+func (v *Verifier) purgePublicKey(req *http.Request) bool {
+	keyID := getKeyIDFromSignatureHeader(req)
+	if keyID == "" {
+		return false
+	}
+
+	keyIRI, err := url.Parse(keyID)
+	if err != nil {
+		return false
+	}
+
+	if v.purgedKeys != nil {
+		if v.purgedKeys.Has(keyIRI.String()) {
+			logger.Debug("Public key was purged recently. Not purging again.", logfields.WithKeyIRI(keyIRI))
+
+			return false
+		}
+
+		if e := v.purgedKeys.Set(keyIRI.String(), true); e != nil {
+			logger.Warn("Error adding public key to purged key cache", logfields.WithKeyIRI(keyIRI), log.WithError(e))
+		}
+	}
+
+	logger.Info("Signature verification failed. Purging cached public key in case the key was rotated.",
+		logfields.WithKeyIRI(keyIRI), logfields.WithRequestURL(req.URL))
+
+	err = v.purger.PurgePublicKey(keyIRI)
+	if err != nil {
+		logger.Warn("Error purging public key", logfields.WithKeyIRI(keyIRI), log.WithError(err))
+
+		return false
+	}
+
+	return true
+}
+
 func getKeyIDFromSignatureHeader(req *http.Request) string {
 	signatureHeader, ok := req.Header["Signature"]
 	if !ok || len(signatureHeader) == 0 {
@@ -16,6 +16,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
@@ -280,6 +281,126 @@ func TestVerifier_VerifyRequest(t *testing.T) {
 	})
 }
 
+func TestVerifier_VerifyRequestWithPurge(t *testing.T) {
+	const keyID = "123456"
+
+	actorIRI := testutil.MustParseURL("https://example.com/services/orb")
+	pubKeyIRI := testutil.NewMockID(actorIRI, "/keys/main-key")
+
+	signer := NewSigner(DefaultGetSignerConfig(), &mockcrypto.Crypto{}, &mockkms.KeyManager{}, keyID)
+	require.NotNil(t, signer)
+
+	payload := []byte("payload")
+
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	pubKeyPem, err := getPublicKeyPem(pubKey)
+	require.NoError(t, err)
+
+	publicKey := vocab.NewPublicKey(
+		vocab.WithID(pubKeyIRI),
+		vocab.WithOwner(actorIRI),
+		vocab.WithPublicKeyPem(string(pubKeyPem)),
+	)
+
+	newRequest := func() *http.Request {
+		req, e := http.NewRequest(http.MethodPost, "https://domain1.com", bytes.NewBuffer(payload))
+		require.NoError(t, e)
+
+		require.NoError(t, signer.SignRequest(publicKey.ID().String(), req))
+
+		return req
+	}
+
+	newVerifier := func(retriever *servicemocks.ActivityPubClient, sigVerifier verifier) *Verifier {
+		v := NewVerifier(retriever, &mockcrypto.Crypto{}, &mockkms.KeyManager{},
+			WithPublicKeyPurger(retriever), WithMinPurgeInterval(time.Minute))
+		v.verifier = func() verifier { return sigVerifier }
+
+		return v
+	}
+
+	t.Run("Success after purge", func(t *testing.T) {
+		retriever := servicemocks.NewActivitPubClient().
+			WithPublicKey(publicKey).
+			WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(publicKey)))
+
+		sigVerifier := &mocks.HTTPSignatureVerifier{}
+		sigVerifier.VerifyReturnsOnCall(0, errors.New("invalid signature"))
+
+		v := newVerifier(retriever, sigVerifier)
+
+		ok, actorID, err := v.VerifyRequest(newRequest())
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, actorIRI.String(), actorID.String())
+		require.Equal(t, []string{pubKeyIRI.String()}, retriever.Purged())
+		require.Equal(t, 2, sigVerifier.VerifyCallCount())
+	})
+
+	t.Run("Key purged recently", func(t *testing.T) {
+		retriever := servicemocks.NewActivitPubClient().
+			WithPublicKey(publicKey).
+			WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(publicKey)))
+
+		sigVerifier := &mocks.HTTPSignatureVerifier{}
+		sigVerifier.VerifyReturns(errors.New("invalid signature"))
+
+		v := newVerifier(retriever, sigVerifier)
+
+		ok, actorID, err := v.VerifyRequest(newRequest())
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, actorID)
+		require.Len(t, retriever.Purged(), 1)
+
+		// The key shouldn't be purged again within the minimum purge interval.
+		ok, actorID, err = v.VerifyRequest(newRequest())
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, actorID)
+		require.Len(t, retriever.Purged(), 1)
+		require.Equal(t, 3, sigVerifier.VerifyCallCount())
+	})
+
+	t.Run("Purge error", func(t *testing.T) {
+		retriever := servicemocks.NewActivitPubClient().
+			WithPublicKey(publicKey).
+			WithActor(aptestutil.NewMockService(actorIRI, aptestutil.WithPublicKey(publicKey))).
+			WithPurgeError(errors.New("injected purge error"))
+
+		sigVerifier := &mocks.HTTPSignatureVerifier{}
+		sigVerifier.VerifyReturns(errors.New("invalid signature"))
+
+		v := newVerifier(retriever, sigVerifier)
+
+		ok, actorID, err := v.VerifyRequest(newRequest())
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, actorID)
+		require.Equal(t, 1, sigVerifier.VerifyCallCount())
+	})
+
+	t.Run("No key ID in signature header", func(t *testing.T) {
+		retriever := servicemocks.NewActivitPubClient()
+
+		sigVerifier := &mocks.HTTPSignatureVerifier{}
+		sigVerifier.VerifyReturns(errors.New("invalid signature"))
+
+		v := newVerifier(retriever, sigVerifier)
+
+		req, err := http.NewRequest(http.MethodPost, "https://domain1.com", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+
+		ok, actorID, err := v.VerifyRequest(req)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Nil(t, actorID)
+		require.Empty(t, retriever.Purged())
+	})
+}
+
 func getPublicKeyPem(pubKey interface{}) ([]byte, error) {
 	keyBytes, err := x509.MarshalPKIXPublicKey(pubKey)
 	if err != nil {
@@ -0,0 +1,371 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerhealth
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+var logger = log.New("peer-health")
+
+const (
+	defaultFailureThreshold  = 3
+	defaultInitialBackoff    = 30 * time.Second
+	defaultMaxBackoff        = 30 * time.Minute
+	defaultBackoffMultiplier = 2.0
+	defaultTrialTimeout      = time.Minute
+
+	// scoreWeight is the weight given to the most recent result when calculating the health score.
+	scoreWeight = 0.2
+
+	scorePrecision = 100
+)
+
+// State is the state of the circuit for a peer.
+type State = string
+
+const (
+	// StateClosed indicates that the peer is healthy and requests may be sent to it.
+	StateClosed State = "closed"
+
+	// StateOpen indicates that the peer has failed too many times in a row and requests to it should
+	// not be sent until the back-off period has expired.
+	StateOpen State = "open"
+
+	// StateHalfOpen indicates that the back-off period has expired and a trial request may be sent to the peer.
+	// If the trial request succeeds then the circuit is closed, otherwise it is opened again with a longer
+	// back-off period.
+	StateHalfOpen State = "half-open"
+)
+
+// Config holds the configuration parameters for the peer health registry.
+type Config struct {
+	// FailureThreshold is the number of consecutive failures after which the circuit is opened.
+	FailureThreshold int
+
+	// InitialBackoff is the back-off period after the circuit is opened for the first time.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum back-off period.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier is the factor by which the back-off period is increased each time the circuit is
+	// re-opened after a failed trial request.
+	BackoffMultiplier float64
+
+	// TrialTimeout is the time after which another trial request may be allowed to a half-open peer if the
+	// result of the previous trial request was never recorded.
+	TrialTimeout time.Duration
+}
+
+// Health contains the health information of a peer.
+type Health struct {
+	IRI                 string     `json:"iri"`
+	State               State      `json:"state"`
+	Score               float64    `json:"score"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	TotalFailures       int        `json:"totalFailures"`
+	TotalSuccesses      int        `json:"totalSuccesses"`
+	AverageLatencyMS    int64      `json:"averageLatencyMs"`
+	LastError           string     `json:"lastError,omitempty"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure         *time.Time `json:"lastFailure,omitempty"`
+	RetryAfter          *time.Time `json:"retryAfter,omitempty"`
+}
+
+type peer struct {
+	iri                 string
+	state               State
+	score               float64
+	consecutiveFailures int
+	totalFailures       int
+	totalSuccesses      int
+	timesOpened         int
+	averageLatency      time.Duration
+	lastError           string
+	lastSuccess         time.Time
+	lastFailure         time.Time
+	openUntil           time.Time
+	trialUntil          time.Time
+}
+
+// Registry tracks the health of ActivityPub peers, keyed by actor IRI. A circuit breaker is maintained for each
+// peer. The circuit is opened after a configured number of consecutive failures, after which the peer is considered
+// unavailable until an exponentially increasing back-off period has expired.
+type Registry struct {
+	Config
+
+	mutex sync.RWMutex
+	peers map[string]*peer
+	now   func() time.Time
+}
+
+// New returns a new peer health registry.
+func New(cfg Config) *Registry {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultFailureThreshold
+	}
+
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultInitialBackoff
+	}
+
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+
+	if cfg.BackoffMultiplier < 1 {
+		cfg.BackoffMultiplier = defaultBackoffMultiplier
+	}
+
+	if cfg.TrialTimeout <= 0 {
+		cfg.TrialTimeout = defaultTrialTimeout
+	}
+
+	return &Registry{
+		Config: cfg,
+		peers:  make(map[string]*peer),
+		now:    time.Now,
+	}
+}
+
+// IsAvailable returns true if requests may be sent to the given peer, i.e. the circuit for the peer is closed or
+// the back-off period has expired and no trial request is currently in flight. IsAvailable doesn't change the
+// state of the circuit; Allow should be called immediately before sending a request whose result is recorded.
+func (r *Registry) IsAvailable(iri *url.URL) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	p, ok := r.peers[iri.String()]
+	if !ok {
+		return true
+	}
+
+	return r.isAvailable(p)
+}
+
+// RetryAfter returns the time at which the back-off period of the given peer's open circuit expires. A zero time is
+// returned if the circuit isn't open or if the back-off period has already expired.
+func (r *Registry) RetryAfter(iri *url.URL) time.Time {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	p, ok := r.peers[iri.String()]
+	if !ok || p.state != StateOpen || !r.now().Before(p.openUntil) {
+		return time.Time{}
+	}
+
+	return p.openUntil
+}
+
+// Allow returns true if a request may be sent to the given peer. If the back-off period of an open circuit has
+// expired then the circuit transitions to half-open and exactly one trial request is allowed. Subsequent calls
+// return false until the result of the trial request is recorded (or the trial times out).
+func (r *Registry) Allow(iri *url.URL) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p, ok := r.peers[iri.String()]
+	if !ok {
+		return true
+	}
+
+	if !r.isAvailable(p) {
+		return false
+	}
+
+	if p.state != StateClosed {
+		logger.Info("Back-off period expired for peer. Allowing trial request.", logfields.WithActorIRI(iri))
+
+		p.state = StateHalfOpen
+		p.trialUntil = r.now().Add(r.TrialTimeout)
+	}
+
+	return true
+}
+
+// RecordSuccess records a successful request to the given peer along with the latency of the request.
+// The circuit for the peer is closed.
+func (r *Registry) RecordSuccess(iri *url.URL, latency time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.getOrCreate(iri)
+
+	if p.state != StateClosed {
+		logger.Info("Closing circuit for peer", logfields.WithActorIRI(iri))
+	}
+
+	if p.totalSuccesses == 0 {
+		p.averageLatency = latency
+	} else {
+		p.averageLatency = time.Duration(scoreWeight*float64(latency) + (1-scoreWeight)*float64(p.averageLatency))
+	}
+
+	p.state = StateClosed
+	p.score = scoreWeight + (1-scoreWeight)*p.score
+	p.consecutiveFailures = 0
+	p.timesOpened = 0
+	p.totalSuccesses++
+	p.lastSuccess = r.now()
+	p.openUntil = time.Time{}
+	p.trialUntil = time.Time{}
+}
+
+// RecordFailure records a failed request to the given peer. The circuit is opened if the number of consecutive
+// failures reaches the failure threshold or if the trial request of a half-open circuit failed.
+func (r *Registry) RecordFailure(iri *url.URL, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.getOrCreate(iri)
+
+	p.score = (1 - scoreWeight) * p.score
+	p.consecutiveFailures++
+	p.totalFailures++
+	p.lastFailure = r.now()
+
+	p.trialUntil = time.Time{}
+
+	if err != nil {
+		p.lastError = err.Error()
+	}
+
+	switch p.state {
+	case StateHalfOpen:
+		r.open(p)
+	case StateOpen:
+		// A failure recorded after the back-off period has expired counts as a failed trial.
+		if !r.now().Before(p.openUntil) {
+			r.open(p)
+		}
+	case StateClosed:
+		if p.consecutiveFailures >= r.FailureThreshold {
+			r.open(p)
+		}
+	}
+}
+
+// Get returns the health of the given peer or nil if nothing was recorded for the peer.
+func (r *Registry) Get(iri *url.URL) *Health {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	p, ok := r.peers[iri.String()]
+	if !ok {
+		return nil
+	}
+
+	return r.health(p)
+}
+
+// GetAll returns the health of all peers, sorted by IRI.
+func (r *Registry) GetAll() []*Health {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	peers := make([]*Health, 0, len(r.peers))
+
+	for _, p := range r.peers {
+		peers = append(peers, r.health(p))
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].IRI < peers[j].IRI
+	})
+
+	return peers
+}
+
+func (r *Registry) getOrCreate(iri *url.URL) *peer {
+	p, ok := r.peers[iri.String()]
+	if !ok {
+		p = &peer{
+			iri:   iri.String(),
+			state: StateClosed,
+			score: 1,
+		}
+
+		r.peers[p.iri] = p
+	}
+
+	return p
+}
+
+func (r *Registry) isAvailable(p *peer) bool {
+	now := r.now()
+
+	switch p.state {
+	case StateOpen:
+		return !now.Before(p.openUntil)
+	case StateHalfOpen:
+		return p.trialUntil.IsZero() || !now.Before(p.trialUntil)
+	default:
+		return true
+	}
+}
+
+func (r *Registry) open(p *peer) {
+	p.timesOpened++
+	p.state = StateOpen
+	p.openUntil = r.now().Add(r.backoff(p.timesOpened))
+
+	logger.Warn("Opened circuit for peer", logfields.WithActorID(p.iri),
+		logfields.WithTotal(p.consecutiveFailures), logfields.WithBackoff(p.openUntil.Sub(r.now())))
+}
+
+func (r *Registry) backoff(timesOpened int) time.Duration {
+	backoff := time.Duration(float64(r.InitialBackoff) * math.Pow(r.BackoffMultiplier, float64(timesOpened-1)))
+
+	if backoff <= 0 || backoff > r.MaxBackoff {
+		return r.MaxBackoff
+	}
+
+	return backoff
+}
+
+func (r *Registry) health(p *peer) *Health {
+	h := &Health{
+		IRI:                 p.iri,
+		State:               p.state,
+		Score:               math.Round(p.score*scorePrecision) / scorePrecision,
+		ConsecutiveFailures: p.consecutiveFailures,
+		TotalFailures:       p.totalFailures,
+		TotalSuccesses:      p.totalSuccesses,
+		AverageLatencyMS:    p.averageLatency.Milliseconds(),
+		LastError:           p.lastError,
+	}
+
+	if p.state == StateOpen && !r.now().Before(p.openUntil) {
+		h.State = StateHalfOpen
+	}
+
+	if !p.lastSuccess.IsZero() {
+		lastSuccess := p.lastSuccess
+		h.LastSuccess = &lastSuccess
+	}
+
+	if !p.lastFailure.IsZero() {
+		lastFailure := p.lastFailure
+		h.LastFailure = &lastFailure
+	}
+
+	if h.State == StateOpen {
+		retryAfter := p.openUntil
+		h.RetryAfter = &retryAfter
+	}
+
+	return h
+}
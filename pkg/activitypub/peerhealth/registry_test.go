@@ -0,0 +1,160 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerhealth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	service1IRI = testutil.MustParseURL("https://domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://domain2.com/services/orb")
+)
+
+func TestNew(t *testing.T) {
+	r := New(Config{})
+	require.NotNil(t, r)
+	require.Equal(t, defaultFailureThreshold, r.FailureThreshold)
+	require.Equal(t, defaultInitialBackoff, r.InitialBackoff)
+	require.Equal(t, defaultMaxBackoff, r.MaxBackoff)
+	require.Equal(t, defaultBackoffMultiplier, r.BackoffMultiplier)
+}
+
+func TestRegistry_CircuitBreaker(t *testing.T) {
+	now := time.Now()
+
+	r := New(Config{
+		FailureThreshold:  2,
+		InitialBackoff:    time.Minute,
+		MaxBackoff:        3 * time.Minute,
+		BackoffMultiplier: 2,
+	})
+
+	r.now = func() time.Time { return now }
+
+	errExpected := errors.New("connection refused")
+
+	require.True(t, r.IsAvailable(service1IRI))
+	require.Nil(t, r.Get(service1IRI))
+	require.True(t, r.RetryAfter(service1IRI).IsZero())
+
+	r.RecordFailure(service1IRI, errExpected)
+	require.True(t, r.IsAvailable(service1IRI))
+
+	h := r.Get(service1IRI)
+	require.NotNil(t, h)
+	require.Equal(t, StateClosed, h.State)
+	require.Equal(t, 1, h.ConsecutiveFailures)
+	require.Equal(t, errExpected.Error(), h.LastError)
+	require.NotNil(t, h.LastFailure)
+	require.Nil(t, h.RetryAfter)
+
+	r.RecordFailure(service1IRI, errExpected)
+	require.False(t, r.IsAvailable(service1IRI))
+
+	h = r.Get(service1IRI)
+	require.Equal(t, StateOpen, h.State)
+	require.NotNil(t, h.RetryAfter)
+	require.Equal(t, now.Add(time.Minute), *h.RetryAfter)
+	require.Equal(t, now.Add(time.Minute), r.RetryAfter(service1IRI))
+
+	// Back-off period expires.
+	now = now.Add(time.Minute)
+
+	require.True(t, r.RetryAfter(service1IRI).IsZero())
+
+	require.Equal(t, StateHalfOpen, r.Get(service1IRI).State)
+	require.True(t, r.IsAvailable(service1IRI))
+	require.True(t, r.Allow(service1IRI))
+
+	// Only one trial request is allowed while the circuit is half-open.
+	require.False(t, r.Allow(service1IRI))
+	require.False(t, r.IsAvailable(service1IRI))
+
+	// Trial request fails. The back-off period should be doubled.
+	r.RecordFailure(service1IRI, errExpected)
+	require.False(t, r.IsAvailable(service1IRI))
+	require.Equal(t, now.Add(2*time.Minute), *r.Get(service1IRI).RetryAfter)
+
+	now = now.Add(2 * time.Minute)
+
+	require.True(t, r.Allow(service1IRI))
+
+	// Trial request fails again. The back-off period should be capped.
+	r.RecordFailure(service1IRI, errExpected)
+	require.Equal(t, now.Add(3*time.Minute), *r.Get(service1IRI).RetryAfter)
+
+	now = now.Add(3 * time.Minute)
+
+	require.True(t, r.Allow(service1IRI))
+	require.False(t, r.Allow(service1IRI))
+
+	// Trial request succeeds.
+	r.RecordSuccess(service1IRI, 100*time.Millisecond)
+	require.True(t, r.IsAvailable(service1IRI))
+
+	h = r.Get(service1IRI)
+	require.Equal(t, StateClosed, h.State)
+	require.Equal(t, 0, h.ConsecutiveFailures)
+	require.Equal(t, 4, h.TotalFailures)
+	require.Equal(t, 1, h.TotalSuccesses)
+	require.Equal(t, int64(100), h.AverageLatencyMS)
+	require.NotNil(t, h.LastSuccess)
+	require.Nil(t, h.RetryAfter)
+	require.Less(t, h.Score, 1.0)
+}
+
+func TestRegistry_TrialTimeout(t *testing.T) {
+	now := time.Now()
+
+	r := New(Config{
+		FailureThreshold: 1,
+		InitialBackoff:   time.Minute,
+		TrialTimeout:     10 * time.Second,
+	})
+
+	r.now = func() time.Time { return now }
+
+	r.RecordFailure(service1IRI, errors.New("connection refused"))
+	require.False(t, r.Allow(service1IRI))
+
+	now = now.Add(time.Minute)
+
+	require.True(t, r.Allow(service1IRI))
+	require.False(t, r.Allow(service1IRI))
+
+	// The result of the trial request was never recorded so another trial is allowed after the timeout.
+	now = now.Add(10 * time.Second)
+
+	require.True(t, r.IsAvailable(service1IRI))
+	require.True(t, r.Allow(service1IRI))
+	require.False(t, r.Allow(service1IRI))
+}
+
+func TestRegistry_GetAll(t *testing.T) {
+	r := New(Config{})
+
+	require.Empty(t, r.GetAll())
+
+	r.RecordSuccess(service2IRI, 200*time.Millisecond)
+	r.RecordSuccess(service2IRI, 100*time.Millisecond)
+	r.RecordFailure(service1IRI, nil)
+
+	peers := r.GetAll()
+	require.Len(t, peers, 2)
+	require.Equal(t, service1IRI.String(), peers[0].IRI)
+	require.Empty(t, peers[0].LastError)
+	require.Equal(t, service2IRI.String(), peers[1].IRI)
+	require.Equal(t, 1.0, peers[1].Score)
+	require.Equal(t, int64(180), peers[1].AverageLatencyMS)
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/trustbloc/logutil-go/pkg/log"
 
@@ -140,17 +141,28 @@ func (h *Activities) handleActivityRefsOfType(w http.ResponseWriter, req *http.R
 		return
 	}
 
+	filter, err := h.getActivityFilter(req)
+	if err != nil {
+		h.logger.Debug("Invalid activity filter", log.WithError(err))
+
+		h.writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	id = filter.applyTo(id)
+
 	if h.isPaging(req) {
-		h.handleActivitiesPage(w, req, objectIRI, id, refType)
+		h.handleActivitiesPage(w, req, objectIRI, id, refType, filter)
 	} else {
-		h.handleActivities(w, req, objectIRI, id, refType)
+		h.handleActivities(w, req, objectIRI, id, refType, filter)
 	}
 }
 
 func (h *Activities) handleActivities(rw http.ResponseWriter, _ *http.Request, objectIRI, id *url.URL,
-	refType spi.ReferenceType,
+	refType spi.ReferenceType, filter *activityFilter,
 ) {
-	activities, err := h.getActivities(objectIRI, id, refType)
+	activities, err := h.getActivities(objectIRI, id, refType, filter)
 	if err != nil {
 		h.logger.Error("Error retrieving references of the given type",
 			logfields.WithReferenceType(string(h.refType)), logfields.WithObjectIRI(objectIRI), log.WithError(err))
@@ -174,7 +186,7 @@ func (h *Activities) handleActivities(rw http.ResponseWriter, _ *http.Request, o
 }
 
 func (h *Activities) handleActivitiesPage(rw http.ResponseWriter, req *http.Request, objectIRI, id *url.URL,
-	refType spi.ReferenceType,
+	refType spi.ReferenceType, filter *activityFilter,
 ) {
 	var page *vocab.OrderedCollectionPageType
 
@@ -182,13 +194,13 @@ func (h *Activities) handleActivitiesPage(rw http.ResponseWriter, req *http.Requ
 
 	pageNum, ok := h.getPageNum(req)
 	if ok {
-		page, err = h.getPage(objectIRI, id, refType,
+		page, err = h.getPage(objectIRI, id, refType, filter,
 			spi.WithPageSize(h.PageSize),
 			spi.WithPageNum(pageNum),
 			spi.WithSortOrder(h.sortOrder),
 		)
 	} else {
-		page, err = h.getPage(objectIRI, id, refType,
+		page, err = h.getPage(objectIRI, id, refType, filter,
 			spi.WithPageSize(h.PageSize),
 			spi.WithSortOrder(h.sortOrder),
 		)
@@ -217,34 +229,18 @@ func (h *Activities) handleActivitiesPage(rw http.ResponseWriter, req *http.Requ
 }
 
 func (h *Activities) getActivities(objectIRI, id *url.URL,
-	refType spi.ReferenceType,
+	refType spi.ReferenceType, filter *activityFilter,
 ) (*vocab.OrderedCollectionType, error) {
-	it, err := h.activityStore.QueryReferences(refType,
-		spi.NewCriteria(
-			spi.WithObjectIRI(objectIRI),
-		),
-	)
+	totalItems, err := h.getTotalItems(objectIRI, refType, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	defer func() {
-		err = it.Close()
-		if err != nil {
-			log.CloseIteratorError(h.logger, err)
-		}
-	}()
-
 	firstURL, err := h.getPageURL(id, -1)
 	if err != nil {
 		return nil, err
 	}
 
-	totalItems, err := it.TotalItems()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get total items from reference query: %w", err)
-	}
-
 	lastURL, err := h.getPageURL(id, getLastPageNum(totalItems, h.PageSize, h.sortOrder))
 	if err != nil {
 		return nil, err
@@ -259,15 +255,58 @@ func (h *Activities) getActivities(objectIRI, id *url.URL,
 	), nil
 }
 
-func (h *Activities) getPage(objectIRI, id *url.URL, refType spi.ReferenceType,
+// getTotalItems returns the total number of items in the collection. If no filter is specified then the
+// references are counted, otherwise the activities are queried so that the filters are applied.
+func (h *Activities) getTotalItems(objectIRI *url.URL, refType spi.ReferenceType,
+	filter *activityFilter,
+) (int, error) {
+	if filter.isEmpty() {
+		it, err := h.activityStore.QueryReferences(refType,
+			spi.NewCriteria(
+				spi.WithObjectIRI(objectIRI),
+			),
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		defer func() {
+			if e := it.Close(); e != nil {
+				log.CloseIteratorError(h.logger, e)
+			}
+		}()
+
+		totalItems, err := it.TotalItems()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get total items from reference query: %w", err)
+		}
+
+		return totalItems, nil
+	}
+
+	it, err := h.activityStore.QueryActivities(filter.criteria(refType, objectIRI), spi.WithPageSize(h.PageSize))
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if e := it.Close(); e != nil {
+			log.CloseIteratorError(h.logger, e)
+		}
+	}()
+
+	totalItems, err := it.TotalItems()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total items from activity query: %w", err)
+	}
+
+	return totalItems, nil
+}
+
+func (h *Activities) getPage(objectIRI, id *url.URL, refType spi.ReferenceType, filter *activityFilter,
 	opts ...spi.QueryOpt,
 ) (*vocab.OrderedCollectionPageType, error) {
-	it, err := h.activityStore.QueryActivities(
-		spi.NewCriteria(
-			spi.WithReferenceType(refType),
-			spi.WithObjectIRI(objectIRI),
-		), opts...,
-	)
+	it, err := h.activityStore.QueryActivities(filter.criteria(refType, objectIRI), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -440,3 +479,109 @@ func getObjectIRIFromIDParam(req *http.Request) (*url.URL, error) {
 
 	return url.Parse(id)
 }
+
+// activityFilter holds the filters that were specified in the query parameters of a request for a collection
+// of activities.
+type activityFilter struct {
+	opts   []spi.CriteriaOpt
+	values url.Values
+}
+
+func (f *activityFilter) criteria(refType spi.ReferenceType, objectIRI *url.URL) *spi.Criteria {
+	c := spi.NewCriteria(
+		spi.WithReferenceType(refType),
+		spi.WithObjectIRI(objectIRI),
+	)
+
+	for _, opt := range f.opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (f *activityFilter) isEmpty() bool {
+	return len(f.opts) == 0
+}
+
+// applyTo adds the filter parameters to the given collection ID so that the 'first', 'last', 'prev' and 'next'
+// URLs of the collection retain the filters.
+func (f *activityFilter) applyTo(id *url.URL) *url.URL {
+	if f.isEmpty() {
+		return id
+	}
+
+	u := *id
+
+	if u.RawQuery == "" {
+		u.RawQuery = f.values.Encode()
+	} else {
+		u.RawQuery = fmt.Sprintf("%s&%s", u.RawQuery, f.values.Encode())
+	}
+
+	return &u
+}
+
+// getActivityFilter returns the activity filter from the 'type', 'actor', 'since', 'until' and 'anchor' query
+// parameters. The 'since' and 'until' parameters must be in RFC3339 format.
+func (h *handler) getActivityFilter(req *http.Request) (*activityFilter, error) {
+	params := h.getParams(req)
+
+	filter := &activityFilter{values: make(url.Values)}
+
+	if v := firstValue(params, typeParam); v != "" {
+		filter.add(typeParam, v, spi.WithType(vocab.Type(v)))
+	}
+
+	for _, p := range []string{actorParam, anchorParam} {
+		v := firstValue(params, p)
+		if v == "" {
+			continue
+		}
+
+		u, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter [%s]: %w", p, err)
+		}
+
+		if p == actorParam {
+			filter.add(p, v, spi.WithActor(u))
+		} else {
+			filter.add(p, v, spi.WithAnchor(u))
+		}
+	}
+
+	for _, p := range []string{sinceParam, untilParam} {
+		v := firstValue(params, p)
+		if v == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for parameter [%s]: %w", p, err)
+		}
+
+		if p == sinceParam {
+			filter.add(p, v, spi.WithPublishedSince(t))
+		} else {
+			filter.add(p, v, spi.WithPublishedUntil(t))
+		}
+	}
+
+	return filter, nil
+}
+
+func (f *activityFilter) add(param, value string, opt spi.CriteriaOpt) {
+	f.values.Set(param, value)
+	f.opts = append(f.opts, opt)
+}
+
+func firstValue(params map[string][]string, param string) string {
+	values := params[param]
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -913,6 +914,139 @@ func TestActivity_Handler(t *testing.T) {
 	})
 }
 
+func TestActivities_Filter(t *testing.T) {
+	actor1 := testutil.MustParseURL("https://example1.com/services/orb")
+	actor2 := testutil.MustParseURL("https://example2.com/services/orb")
+	anchor := testutil.MustParseURL("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ")
+
+	published := getStaticTime()
+
+	activityStore := memstore.New("")
+
+	for i := 0; i < 10; i++ {
+		id := testutil.MustParseURL(fmt.Sprintf("https://activity_%d", i))
+		publishedTime := published.Add(time.Duration(i) * time.Minute)
+
+		var activity *vocab.ActivityType
+
+		if i%2 == 0 {
+			activity = vocab.NewCreateActivity(
+				vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://sally.example.com/transactions/bafkreihwsn"))),
+				vocab.WithID(id), vocab.WithActor(actor1), vocab.WithPublishedTime(&publishedTime),
+			)
+		} else {
+			objIRI := testutil.MustParseURL(fmt.Sprintf("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElr%d", i))
+			if i == 3 {
+				objIRI = anchor
+			}
+
+			activity = vocab.NewAnnounceActivity(
+				vocab.NewObjectProperty(vocab.WithIRI(objIRI)),
+				vocab.WithID(id), vocab.WithActor(actor2), vocab.WithPublishedTime(&publishedTime),
+			)
+		}
+
+		require.NoError(t, activityStore.AddActivity(activity))
+		require.NoError(t, activityStore.AddReference(spi.Inbox, serviceIRI, activity.ID().URL()))
+	}
+
+	verifier := &mocks.SignatureVerifier{}
+	verifier.VerifyRequestReturns(true, serviceIRI, nil)
+
+	cfg := &Config{
+		ObjectIRI:          serviceIRI,
+		ServiceEndpointURL: serviceIRI,
+		PageSize:           4,
+	}
+
+	h := NewInbox(cfg, activityStore, verifier, spi.SortAscending, &apmocks.AuthTokenMgr{})
+	require.NotNil(t, h)
+
+	t.Run("Filter by type", func(t *testing.T) {
+		respBytes := handleFilterRequest(t, h, "?type=Create", http.StatusOK)
+
+		coll := &vocab.OrderedCollectionType{}
+		require.NoError(t, json.Unmarshal(respBytes, coll))
+		require.Equal(t, 5, coll.TotalItems())
+		require.Equal(t, fmt.Sprintf("%s/inbox?type=Create&page=true", serviceIRI), coll.First().String())
+	})
+
+	t.Run("Filter by actor", func(t *testing.T) {
+		respBytes := handleFilterRequest(t, h, "?page=true&actor="+url.QueryEscape(actor2.String()), http.StatusOK)
+
+		page := &vocab.OrderedCollectionPageType{}
+		require.NoError(t, json.Unmarshal(respBytes, page))
+		require.Equal(t, 5, page.TotalItems())
+		require.Len(t, page.Items(), 4)
+		require.NotNil(t, page.Next())
+		require.Contains(t, page.Next().String(), "actor=")
+
+		for _, item := range page.Items() {
+			require.Equal(t, actor2.String(), item.Activity().Actor().String())
+		}
+	})
+
+	t.Run("Filter by published time range", func(t *testing.T) {
+		respBytes := handleFilterRequest(t, h, fmt.Sprintf("?page=true&since=%s&until=%s",
+			url.QueryEscape(published.Add(4*time.Minute).Format(time.RFC3339)),
+			url.QueryEscape(published.Add(6*time.Minute).Format(time.RFC3339))), http.StatusOK)
+
+		page := &vocab.OrderedCollectionPageType{}
+		require.NoError(t, json.Unmarshal(respBytes, page))
+		require.Equal(t, 3, page.TotalItems())
+		require.Len(t, page.Items(), 3)
+		require.Equal(t, "https://activity_4", page.Items()[0].Activity().ID().String())
+		require.Equal(t, "https://activity_6", page.Items()[2].Activity().ID().String())
+	})
+
+	t.Run("Filter by anchor", func(t *testing.T) {
+		respBytes := handleFilterRequest(t, h, "?page=true&anchor="+url.QueryEscape(anchor.String()), http.StatusOK)
+
+		page := &vocab.OrderedCollectionPageType{}
+		require.NoError(t, json.Unmarshal(respBytes, page))
+		require.Equal(t, 1, page.TotalItems())
+		require.Len(t, page.Items(), 1)
+		require.Equal(t, "https://activity_3", page.Items()[0].Activity().ID().String())
+	})
+
+	t.Run("Combined filters -> no results", func(t *testing.T) {
+		respBytes := handleFilterRequest(t, h, "?page=true&type=Create&actor="+url.QueryEscape(actor2.String()),
+			http.StatusOK)
+
+		page := &vocab.OrderedCollectionPageType{}
+		require.NoError(t, json.Unmarshal(respBytes, page))
+		require.Equal(t, 0, page.TotalItems())
+		require.Empty(t, page.Items())
+	})
+
+	t.Run("Invalid time -> BadRequest", func(t *testing.T) {
+		handleFilterRequest(t, h, "?since=yesterday", http.StatusBadRequest)
+		handleFilterRequest(t, h, "?page=true&until=2021-01-27", http.StatusBadRequest)
+	})
+
+	t.Run("Invalid actor -> BadRequest", func(t *testing.T) {
+		handleFilterRequest(t, h, "?actor=%25zz%3A", http.StatusBadRequest)
+	})
+}
+
+func handleFilterRequest(t *testing.T, h *Activities, query string, expectedStatus int) []byte {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, inboxURL+query, http.NoBody)
+
+	h.handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	return respBytes
+}
+
 func TestGetActivities(t *testing.T) {
 	store, err := ariesstore.New("", &mock.Provider{
 		OpenStoreReturn: &mock.Store{
@@ -923,7 +1057,7 @@ func TestGetActivities(t *testing.T) {
 
 	activitiesHandler := Activities{handler: &handler{AuthHandler: &AuthHandler{activityStore: store}}}
 
-	activities, err := activitiesHandler.getActivities(&url.URL{}, &url.URL{}, spi.Inbox)
+	activities, err := activitiesHandler.getActivities(&url.URL{}, &url.URL{}, spi.Inbox, &activityFilter{})
 	require.EqualError(t, err, "failed to get total items from reference query: total items error")
 	require.Nil(t, activities)
 }
@@ -939,7 +1073,7 @@ func TestActivityHandlerGetPage(t *testing.T) {
 
 	activitiesHandler := Activities{handler: &handler{AuthHandler: &AuthHandler{activityStore: &mockActivityStore}}}
 
-	page, err := activitiesHandler.getPage(&url.URL{}, &url.URL{}, spi.Inbox)
+	page, err := activitiesHandler.getPage(&url.URL{}, &url.URL{}, spi.Inbox, &activityFilter{})
 	require.EqualError(t, err, "failed to get total items from activity query: total items error")
 	require.Nil(t, page)
 }
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+type actorPurger interface {
+	PurgeActor(actorIRI *url.URL) error
+}
+
+// ActorCache implements a REST handler that purges an actor, along with the actor's public key, from the
+// ActivityPub client's cache. The actor is specified with the parameter, iri=<actor IRI>. This is useful
+// when a peer has rotated its key and signature verification fails until the cache entry expires.
+type ActorCache struct {
+	endpoint string
+	purger   actorPurger
+	logger   *log.Log
+}
+
+// NewActorCache returns a new REST handler to purge actors from the cache.
+func NewActorCache(cfg *Config, purger actorPurger) *ActorCache {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, ActorCachePath)
+
+	return &ActorCache{
+		endpoint: endpoint,
+		purger:   purger,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always DELETE.
+func (h *ActorCache) Method() string {
+	return http.MethodDelete
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *ActorCache) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP DELETE is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *ActorCache) Handler() common.HTTPRequestHandler {
+	return h.handleDelete
+}
+
+func (h *ActorCache) handleDelete(w http.ResponseWriter, req *http.Request) {
+	values := req.URL.Query()[iriParam]
+	if len(values) == 0 || values[0] == "" {
+		h.logger.Debug("Actor IRI not specified", logfields.WithParameter(iriParam))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	actorIRI, err := url.Parse(values[0])
+	if err != nil {
+		h.logger.Debug("Invalid actor IRI", logfields.WithParameter(iriParam), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	err = h.purger.PurgeActor(actorIRI)
+	if err != nil {
+		h.logger.Error("Error purging actor from cache", logfields.WithActorIRI(actorIRI), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.logger.Info("Purged actor from cache", logfields.WithActorIRI(actorIRI))
+
+	writeResponse(h.logger, w, http.StatusOK, nil)
+}
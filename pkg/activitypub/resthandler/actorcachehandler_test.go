@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+)
+
+const actorCacheURL = "https://example.com/services/orb/actor-cache"
+
+func TestNewActorCache(t *testing.T) {
+	h := NewActorCache(&Config{BasePath: "/services/orb"}, mocks.NewActivitPubClient())
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodDelete, h.Method())
+	require.Equal(t, "/services/orb/actor-cache", h.Path())
+}
+
+func TestActorCache_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	const actorIRI = "https://domain1.com/services/orb"
+
+	t.Run("Success", func(t *testing.T) {
+		apClient := mocks.NewActivitPubClient()
+
+		status := purgeActor(t, NewActorCache(cfg, apClient), actorCacheURL+"?iri="+actorIRI)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{actorIRI}, apClient.Purged())
+	})
+
+	t.Run("No IRI", func(t *testing.T) {
+		status := purgeActor(t, NewActorCache(cfg, mocks.NewActivitPubClient()), actorCacheURL)
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Invalid IRI", func(t *testing.T) {
+		status := purgeActor(t, NewActorCache(cfg, mocks.NewActivitPubClient()), actorCacheURL+"?iri=%3Ainvalid")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Purge error", func(t *testing.T) {
+		apClient := mocks.NewActivitPubClient().WithPurgeError(errors.New("injected purge error"))
+
+		status := purgeActor(t, NewActorCache(cfg, apClient), actorCacheURL+"?iri="+actorIRI)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func purgeActor(t *testing.T, h *ActorCache, u string) int {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handleDelete(rw, httptest.NewRequest(http.MethodDelete, u, nil))
+
+	result := rw.Result()
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode
+}
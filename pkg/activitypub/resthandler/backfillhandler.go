@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/service/backfill"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const jobIDParam = "id"
+
+type backfiller interface {
+	Start(serviceIRI *url.URL) (*backfill.Job, error)
+	Get(id string) (*backfill.Job, error)
+	GetAll() ([]*backfill.Job, error)
+}
+
+// BackfillReader implements a REST handler that returns the progress of backfill jobs. All jobs are returned
+// unless a job ID is specified with the parameter, id.
+type BackfillReader struct {
+	endpoint   string
+	backfiller backfiller
+	marshal    func(v interface{}) ([]byte, error)
+	logger     *log.Log
+}
+
+// NewBackfillReader returns a new REST handler to read backfill jobs.
+func NewBackfillReader(cfg *Config, b backfiller) *BackfillReader {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, BackfillPath)
+
+	return &BackfillReader{
+		endpoint:   endpoint,
+		backfiller: b,
+		marshal:    json.Marshal,
+		logger:     log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *BackfillReader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *BackfillReader) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *BackfillReader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *BackfillReader) handleGet(w http.ResponseWriter, req *http.Request) {
+	var result interface{}
+
+	if values := req.URL.Query()[jobIDParam]; len(values) > 0 && values[0] != "" {
+		job, err := h.backfiller.Get(values[0])
+		if err != nil {
+			if errors.Is(err, orberrors.ErrContentNotFound) {
+				h.logger.Debug("Backfill job not found", logfields.WithID(values[0]))
+
+				writeResponse(h.logger, w, http.StatusNotFound, []byte(notFoundResponse))
+
+				return
+			}
+
+			h.logger.Error("Error retrieving backfill job", logfields.WithID(values[0]), log.WithError(err))
+
+			writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+
+		result = job
+	} else {
+		jobs, err := h.backfiller.GetAll()
+		if err != nil {
+			h.logger.Error("Error retrieving backfill jobs", log.WithError(err))
+
+			writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+
+		if jobs == nil {
+			jobs = []*backfill.Job{}
+		}
+
+		result = jobs
+	}
+
+	respBytes, err := h.marshal(result)
+	if err != nil {
+		h.logger.Error("Error marshalling backfill jobs", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, respBytes)
+}
+
+// BackfillWriter implements a REST handler that starts a backfill from the outbox of a remote service.
+type BackfillWriter struct {
+	endpoint   string
+	backfiller backfiller
+	readAll    func(r io.Reader) ([]byte, error)
+	marshal    func(v interface{}) ([]byte, error)
+	logger     *log.Log
+}
+
+// NewBackfillWriter returns a new REST handler to start backfill jobs.
+func NewBackfillWriter(cfg *Config, b backfiller) *BackfillWriter {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, BackfillPath)
+
+	return &BackfillWriter{
+		endpoint:   endpoint,
+		backfiller: b,
+		readAll:    io.ReadAll,
+		marshal:    json.Marshal,
+		logger:     log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *BackfillWriter) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *BackfillWriter) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *BackfillWriter) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *BackfillWriter) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		h.logger.Error("Error reading request body", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.logger.Debug("Got request to start backfill", logfields.WithRequestBody(reqBytes))
+
+	request := &backfillRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil || request.Service == "" {
+		h.logger.Info("Invalid backfill request", logfields.WithRequestBody(reqBytes))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	serviceIRI, err := url.Parse(request.Service)
+	if err != nil || !serviceIRI.IsAbs() {
+		h.logger.Info("Invalid service IRI in backfill request", logfields.WithRequestBody(reqBytes))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	job, err := h.backfiller.Start(serviceIRI)
+	if err != nil {
+		h.logger.Error("Error starting backfill", logfields.WithServiceIRI(serviceIRI), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(job)
+	if err != nil {
+		h.logger.Error("Error marshalling backfill job", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, respBytes)
+}
+
+type backfillRequest struct {
+	Service string `json:"service"`
+}
@@ -0,0 +1,237 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/backfill"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const backfillURL = "https://example.com/services/orb/backfill"
+
+func TestNewBackfillHandlers(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	r := NewBackfillReader(cfg, &mockBackfiller{})
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/services/orb/backfill", r.Path())
+
+	w := NewBackfillWriter(cfg, &mockBackfiller{})
+	require.NotNil(t, w.Handler())
+	require.Equal(t, http.MethodPost, w.Method())
+	require.Equal(t, "/services/orb/backfill", w.Path())
+}
+
+func TestBackfillReader_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	job := &backfill.Job{ID: "job1", ServiceIRI: "https://domain2.com/services/orb", Status: backfill.StatusPending}
+
+	t.Run("All jobs", func(t *testing.T) {
+		status, respBytes := getBackfill(t, NewBackfillReader(cfg, &mockBackfiller{jobs: []*backfill.Job{job}}), "")
+		require.Equal(t, http.StatusOK, status)
+
+		var jobs []*backfill.Job
+
+		require.NoError(t, json.Unmarshal(respBytes, &jobs))
+		require.Len(t, jobs, 1)
+		require.Equal(t, job.ID, jobs[0].ID)
+	})
+
+	t.Run("No jobs", func(t *testing.T) {
+		status, respBytes := getBackfill(t, NewBackfillReader(cfg, &mockBackfiller{}), "")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "[]", string(respBytes))
+	})
+
+	t.Run("Job by ID", func(t *testing.T) {
+		status, respBytes := getBackfill(t, NewBackfillReader(cfg, &mockBackfiller{jobs: []*backfill.Job{job}}), job.ID)
+		require.Equal(t, http.StatusOK, status)
+
+		j := &backfill.Job{}
+
+		require.NoError(t, json.Unmarshal(respBytes, j))
+		require.Equal(t, job.ServiceIRI, j.ServiceIRI)
+	})
+
+	t.Run("Job not found", func(t *testing.T) {
+		status, _ := getBackfill(t, NewBackfillReader(cfg, &mockBackfiller{}), "job2")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		b := &mockBackfiller{err: errors.New("injected store error")}
+
+		status, _ := getBackfill(t, NewBackfillReader(cfg, b), "")
+		require.Equal(t, http.StatusInternalServerError, status)
+
+		status, _ = getBackfill(t, NewBackfillReader(cfg, b), "job1")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewBackfillReader(cfg, &mockBackfiller{})
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		status, _ := getBackfill(t, h, "")
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func TestBackfillWriter_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		b := &mockBackfiller{}
+
+		status, respBytes := postBackfill(t, NewBackfillWriter(cfg, b), []byte(`{"service":"https://domain2.com/services/orb"}`))
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, b.jobs, 1)
+
+		job := &backfill.Job{}
+
+		require.NoError(t, json.Unmarshal(respBytes, job))
+		require.Equal(t, "https://domain2.com/services/orb", job.ServiceIRI)
+		require.Equal(t, backfill.StatusPending, job.Status)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		h := NewBackfillWriter(cfg, &mockBackfiller{})
+
+		status, _ := postBackfill(t, h, []byte(`{`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = postBackfill(t, h, []byte(`{}`))
+		require.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = postBackfill(t, h, []byte(`{"service":"services/orb"}`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Start error", func(t *testing.T) {
+		h := NewBackfillWriter(cfg, &mockBackfiller{err: errors.New("injected start error")})
+
+		status, _ := postBackfill(t, h, []byte(`{"service":"https://domain2.com/services/orb"}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		h := NewBackfillWriter(cfg, &mockBackfiller{})
+		h.readAll = func(r io.Reader) ([]byte, error) {
+			return nil, errors.New("injected read error")
+		}
+
+		status, _ := postBackfill(t, h, []byte(`{"service":"https://domain2.com/services/orb"}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewBackfillWriter(cfg, &mockBackfiller{})
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		status, _ := postBackfill(t, h, []byte(`{"service":"https://domain2.com/services/orb"}`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func getBackfill(t *testing.T, h *BackfillReader, id string) (int, []byte) {
+	t.Helper()
+
+	u := backfillURL
+	if id != "" {
+		u += "?id=" + id
+	}
+
+	rw := httptest.NewRecorder()
+
+	h.handleGet(rw, httptest.NewRequest(http.MethodGet, u, nil))
+
+	result := rw.Result()
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode, respBytes
+}
+
+func postBackfill(t *testing.T, h *BackfillWriter, reqBytes []byte) (int, []byte) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handlePost(rw, httptest.NewRequest(http.MethodPost, backfillURL, bytes.NewBuffer(reqBytes)))
+
+	result := rw.Result()
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode, respBytes
+}
+
+type mockBackfiller struct {
+	jobs []*backfill.Job
+	err  error
+}
+
+func (m *mockBackfiller) Start(serviceIRI *url.URL) (*backfill.Job, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	job := &backfill.Job{
+		ID:         backfill.JobID(serviceIRI),
+		ServiceIRI: serviceIRI.String(),
+		Status:     backfill.StatusPending,
+	}
+
+	m.jobs = append(m.jobs, job)
+
+	return job, nil
+}
+
+func (m *mockBackfiller) Get(id string) (*backfill.Job, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	for _, job := range m.jobs {
+		if job.ID == id {
+			return job, nil
+		}
+	}
+
+	return nil, orberrors.ErrContentNotFound
+}
+
+func (m *mockBackfiller) GetAll() ([]*backfill.Job, error) {
+	return m.jobs, m.err
+}
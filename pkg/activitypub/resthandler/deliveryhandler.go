@@ -0,0 +1,235 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/delivery"
+)
+
+const statusParam = "status"
+
+type deliveryStore interface {
+	Query(status delivery.Status) ([]*delivery.Delivery, error)
+}
+
+type redeliverer interface {
+	Redeliver(ctx context.Context, deliveryID string) error
+}
+
+// DeliveryReader implements a REST handler that returns the outbox deliveries that have failed. By default, the
+// deliveries in the dead-letter collection are returned. Deliveries that are still being retried may be returned
+// by specifying the parameter, status=pending, and deliveries that are waiting for the circuit of the target to
+// close may be returned by specifying status=parked.
+type DeliveryReader struct {
+	endpoint string
+	store    deliveryStore
+	marshal  func(v interface{}) ([]byte, error)
+	logger   *log.Log
+}
+
+// NewDeliveryReader returns a new REST handler to read failed outbox deliveries.
+func NewDeliveryReader(cfg *Config, s deliveryStore) *DeliveryReader {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, DeliveriesPath)
+
+	return &DeliveryReader{
+		endpoint: endpoint,
+		store:    s,
+		marshal:  json.Marshal,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *DeliveryReader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *DeliveryReader) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *DeliveryReader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *DeliveryReader) handleGet(w http.ResponseWriter, req *http.Request) {
+	status := delivery.StatusFailed
+
+	if values := req.URL.Query()[statusParam]; len(values) > 0 && values[0] != "" {
+		status = values[0]
+	}
+
+	if status != delivery.StatusFailed && status != delivery.StatusPending && status != delivery.StatusParked {
+		h.logger.Debug("Invalid delivery status", logfields.WithStatus(status))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	deliveries, err := h.store.Query(status)
+	if err != nil {
+		h.logger.Error("Error querying deliveries", logfields.WithStatus(status), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []*delivery.Delivery{}
+	}
+
+	respBytes, err := h.marshal(deliveries)
+	if err != nil {
+		h.logger.Error("Error marshalling deliveries", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, respBytes)
+}
+
+// RedeliveryWriter implements a REST handler that re-drives failed outbox deliveries. The deliveries may be
+// specified by ID or by target inbox. If a target is specified then all failed deliveries to that target are
+// re-driven.
+type RedeliveryWriter struct {
+	endpoint    string
+	store       deliveryStore
+	redeliverer redeliverer
+	readAll     func(r io.Reader) ([]byte, error)
+	logger      *log.Log
+}
+
+// NewRedeliveryWriter returns a new REST handler to re-drive failed outbox deliveries.
+func NewRedeliveryWriter(cfg *Config, s deliveryStore, r redeliverer) *RedeliveryWriter {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, DeliveriesPath)
+
+	return &RedeliveryWriter{
+		endpoint:    endpoint,
+		store:       s,
+		redeliverer: r,
+		readAll:     io.ReadAll,
+		logger:      log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *RedeliveryWriter) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *RedeliveryWriter) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *RedeliveryWriter) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *RedeliveryWriter) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		h.logger.Error("Error reading request body", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.logger.Debug("Got request to redeliver activities", logfields.WithRequestBody(reqBytes))
+
+	request := &redeliveryRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil || (len(request.IDs) == 0 && request.Target == "") {
+		h.logger.Info("Invalid redelivery request", logfields.WithRequestBody(reqBytes))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	ids, err := h.resolveIDs(request)
+	if err != nil {
+		h.logger.Error("Error resolving deliveries", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	for _, id := range ids {
+		err = h.redeliverer.Redeliver(req.Context(), id)
+		if err != nil {
+			switch {
+			case errors.Is(err, orberrors.ErrContentNotFound):
+				h.logger.Info("Delivery not found", logfields.WithID(id))
+
+				writeResponse(h.logger, w, http.StatusNotFound, []byte(notFoundResponse))
+			case orberrors.IsBadRequest(err):
+				h.logger.Info("Delivery cannot be redelivered", logfields.WithID(id), log.WithError(err))
+
+				writeResponse(h.logger, w, http.StatusBadRequest, []byte(err.Error()))
+			default:
+				h.logger.Error("Error redelivering activity", logfields.WithID(id), log.WithError(err))
+
+				writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+			}
+
+			return
+		}
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, nil)
+}
+
+func (h *RedeliveryWriter) resolveIDs(request *redeliveryRequest) ([]string, error) {
+	if request.Target == "" {
+		return request.IDs, nil
+	}
+
+	deliveries, err := h.store.Query(delivery.StatusFailed)
+	if err != nil {
+		return nil, fmt.Errorf("query failed deliveries: %w", err)
+	}
+
+	ids := request.IDs
+
+	for _, d := range deliveries {
+		if d.Target == request.Target {
+			ids = append(ids, d.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+type redeliveryRequest struct {
+	IDs    []string `json:"ids,omitempty"`
+	Target string   `json:"target,omitempty"`
+}
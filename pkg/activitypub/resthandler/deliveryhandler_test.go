@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/delivery"
+)
+
+const (
+	deliveriesURL = "https://example.com/services/orb/deliveries"
+	targetInbox   = "https://domain2.com/services/orb/inbox"
+)
+
+func TestNewDeliveryHandlers(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	r := NewDeliveryReader(cfg, &mockDeliveryStore{})
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/services/orb/deliveries", r.Path())
+
+	w := NewRedeliveryWriter(cfg, &mockDeliveryStore{}, &mockRedeliverer{})
+	require.NotNil(t, w.Handler())
+	require.Equal(t, http.MethodPost, w.Method())
+	require.Equal(t, "/services/orb/deliveries", w.Path())
+}
+
+func TestDeliveryReader_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	s, err := delivery.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(&delivery.Delivery{ID: "1", Target: targetInbox, Status: delivery.StatusFailed}))
+	require.NoError(t, s.Put(&delivery.Delivery{ID: "2", Target: targetInbox, Status: delivery.StatusPending}))
+
+	t.Run("Success - default status", func(t *testing.T) {
+		deliveries := getDeliveries(t, NewDeliveryReader(cfg, s), deliveriesURL)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, "1", deliveries[0].ID)
+	})
+
+	t.Run("Success - pending status", func(t *testing.T) {
+		deliveries := getDeliveries(t, NewDeliveryReader(cfg, s), deliveriesURL+"?status=pending")
+		require.Len(t, deliveries, 1)
+		require.Equal(t, "2", deliveries[0].ID)
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		NewDeliveryReader(cfg, s).handleGet(rw, httptest.NewRequest(http.MethodGet, deliveriesURL+"?status=xxx", nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusBadRequest, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h := NewDeliveryReader(cfg, &mockDeliveryStore{err: errors.New("injected query error")})
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, deliveriesURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h := NewDeliveryReader(cfg, s)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, deliveriesURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestRedeliveryWriter_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	s := &mockDeliveryStore{
+		deliveries: []*delivery.Delivery{
+			{ID: "1", Target: targetInbox, Status: delivery.StatusFailed},
+			{ID: "2", Target: "https://domain3.com/services/orb/inbox", Status: delivery.StatusFailed},
+			{ID: "3", Target: targetInbox, Status: delivery.StatusFailed},
+		},
+	}
+
+	t.Run("Success - by ID", func(t *testing.T) {
+		r := &mockRedeliverer{}
+
+		status := postRedelivery(t, NewRedeliveryWriter(cfg, s, r), &redeliveryRequest{IDs: []string{"2"}})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{"2"}, r.ids)
+	})
+
+	t.Run("Success - by target", func(t *testing.T) {
+		r := &mockRedeliverer{}
+
+		status := postRedelivery(t, NewRedeliveryWriter(cfg, s, r), &redeliveryRequest{Target: targetInbox})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{"1", "3"}, r.ids)
+	})
+
+	t.Run("Empty request", func(t *testing.T) {
+		status := postRedelivery(t, NewRedeliveryWriter(cfg, s, &mockRedeliverer{}), &redeliveryRequest{})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		r := &mockRedeliverer{err: orberrors.ErrContentNotFound}
+
+		status := postRedelivery(t, NewRedeliveryWriter(cfg, s, r), &redeliveryRequest{IDs: []string{"4"}})
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		r := &mockRedeliverer{err: orberrors.NewBadRequestf("not failed")}
+
+		status := postRedelivery(t, NewRedeliveryWriter(cfg, s, r), &redeliveryRequest{IDs: []string{"1"}})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Redeliver error", func(t *testing.T) {
+		r := &mockRedeliverer{err: errors.New("injected redeliver error")}
+
+		status := postRedelivery(t, NewRedeliveryWriter(cfg, s, r), &redeliveryRequest{IDs: []string{"1"}})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		h := NewRedeliveryWriter(cfg, &mockDeliveryStore{err: errors.New("injected query error")}, &mockRedeliverer{})
+
+		status := postRedelivery(t, h, &redeliveryRequest{Target: targetInbox})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		h := NewRedeliveryWriter(cfg, s, &mockRedeliverer{})
+		h.readAll = func(r io.Reader) ([]byte, error) {
+			return nil, errors.New("injected read error")
+		}
+
+		status := postRedelivery(t, h, &redeliveryRequest{IDs: []string{"1"}})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func getDeliveries(t *testing.T, h *DeliveryReader, u string) []*delivery.Delivery {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handleGet(rw, httptest.NewRequest(http.MethodGet, u, nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	var deliveries []*delivery.Delivery
+
+	require.NoError(t, json.Unmarshal(respBytes, &deliveries))
+
+	return deliveries
+}
+
+func postRedelivery(t *testing.T, h *RedeliveryWriter, request *redeliveryRequest) int {
+	t.Helper()
+
+	reqBytes, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+
+	h.handlePost(rw, httptest.NewRequest(http.MethodPost, deliveriesURL, bytes.NewBuffer(reqBytes)))
+
+	result := rw.Result()
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode
+}
+
+type mockDeliveryStore struct {
+	deliveries []*delivery.Delivery
+	err        error
+}
+
+func (m *mockDeliveryStore) Query(status delivery.Status) ([]*delivery.Delivery, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var deliveries []*delivery.Delivery
+
+	for _, d := range m.deliveries {
+		if d.Status == status {
+			deliveries = append(deliveries, d)
+		}
+	}
+
+	return deliveries, nil
+}
+
+type mockRedeliverer struct {
+	ids []string
+	err error
+}
+
+func (m *mockRedeliverer) Redeliver(_ context.Context, deliveryID string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.ids = append(m.ids, deliveryID)
+
+	return nil
+}
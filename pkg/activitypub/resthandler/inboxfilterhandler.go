@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/filter"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+type inboxFilterStore interface {
+	PutRules(rules []*filter.Rule) error
+	GetRules() ([]*filter.Rule, error)
+}
+
+// InboxFilterReader implements a REST handler that returns the ordered filter rules that are applied
+// to inbound activities.
+type InboxFilterReader struct {
+	endpoint string
+	store    inboxFilterStore
+	marshal  func(v interface{}) ([]byte, error)
+	logger   *log.Log
+}
+
+// NewInboxFilterReader returns a new REST handler to read the inbox filter rules.
+func NewInboxFilterReader(cfg *Config, s inboxFilterStore) *InboxFilterReader {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, InboxFiltersPath)
+
+	return &InboxFilterReader{
+		endpoint: endpoint,
+		store:    s,
+		marshal:  json.Marshal,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *InboxFilterReader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *InboxFilterReader) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *InboxFilterReader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *InboxFilterReader) handleGet(w http.ResponseWriter, _ *http.Request) {
+	rules, err := h.store.GetRules()
+	if err != nil {
+		h.logger.Error("Error retrieving inbox filter rules", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := h.marshal(rules)
+	if err != nil {
+		h.logger.Error("Error marshalling inbox filter rules", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, respBytes)
+}
+
+// InboxFilterWriter implements a REST handler that replaces the ordered filter rules that are applied
+// to inbound activities. The new rules take effect once the cached rules expire.
+type InboxFilterWriter struct {
+	endpoint string
+	store    inboxFilterStore
+	readAll  func(r io.Reader) ([]byte, error)
+	logger   *log.Log
+}
+
+// NewInboxFilterWriter returns a new REST handler to update the inbox filter rules.
+func NewInboxFilterWriter(cfg *Config, s inboxFilterStore) *InboxFilterWriter {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, InboxFiltersPath)
+
+	return &InboxFilterWriter{
+		endpoint: endpoint,
+		store:    s,
+		readAll:  io.ReadAll,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *InboxFilterWriter) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *InboxFilterWriter) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *InboxFilterWriter) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *InboxFilterWriter) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		h.logger.Error("Error reading request body", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.logger.Debug("Got request to update inbox filter rules", logfields.WithRequestBody(reqBytes))
+
+	var rules []*filter.Rule
+
+	err = json.Unmarshal(reqBytes, &rules)
+	if err != nil {
+		h.logger.Info("Invalid inbox filter rules request", logfields.WithRequestBody(reqBytes), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	err = h.store.PutRules(rules)
+	if err != nil {
+		if orberrors.IsBadRequest(err) {
+			h.logger.Info("Invalid inbox filter rules", log.WithError(err))
+
+			writeResponse(h.logger, w, http.StatusBadRequest, []byte(err.Error()))
+
+			return
+		}
+
+		h.logger.Error("Error storing inbox filter rules", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, nil)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/filter"
+)
+
+const inboxFiltersURL = "https://example.com/services/orb/inbox-filters"
+
+func TestNewInboxFilterHandlers(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	s := filter.NewStore(&mockstore.Store{})
+
+	r := NewInboxFilterReader(cfg, s)
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/services/orb/inbox-filters", r.Path())
+
+	w := NewInboxFilterWriter(cfg, s)
+	require.NotNil(t, w.Handler())
+	require.Equal(t, http.MethodPost, w.Method())
+	require.Equal(t, "/services/orb/inbox-filters", w.Path())
+}
+
+func TestInboxFilterHandlers(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	configStore, err := mem.NewProvider().OpenStore("config")
+	require.NoError(t, err)
+
+	s := filter.NewStore(configStore)
+
+	t.Run("Success", func(t *testing.T) {
+		rules := getInboxFilterRules(t, NewInboxFilterReader(cfg, s))
+		require.Empty(t, rules)
+
+		status := postInboxFilterRules(t, NewInboxFilterWriter(cfg, s), []byte(`[
+			{"type":"actorDomain","action":"accept","values":["domain1.com"]},
+			{"type":"payloadSize","action":"quarantine","maxSize":100000}
+		]`))
+		require.Equal(t, http.StatusOK, status)
+
+		rules = getInboxFilterRules(t, NewInboxFilterReader(cfg, s))
+		require.Len(t, rules, 2)
+		require.Equal(t, filter.RuleTypeActorDomain, rules[0].Type)
+		require.Equal(t, filter.ActionQuarantine, rules[1].Action)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		status := postInboxFilterRules(t, NewInboxFilterWriter(cfg, s), []byte(`{`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		status := postInboxFilterRules(t, NewInboxFilterWriter(cfg, s), []byte(`[{"type":"rate","action":"reject"}]`))
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := filter.NewStore(&mockstore.Store{ErrGet: errExpected, ErrPut: errExpected})
+
+		rw := httptest.NewRecorder()
+
+		NewInboxFilterReader(cfg, s).handleGet(rw, httptest.NewRequest(http.MethodGet, inboxFiltersURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+
+		status := postInboxFilterRules(t, NewInboxFilterWriter(cfg, s), []byte(`[]`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewInboxFilterReader(cfg, s)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		rw := httptest.NewRecorder()
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, inboxFiltersURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		h := NewInboxFilterWriter(cfg, s)
+		h.readAll = func(r io.Reader) ([]byte, error) {
+			return nil, errors.New("injected read error")
+		}
+
+		status := postInboxFilterRules(t, h, []byte(`[]`))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func getInboxFilterRules(t *testing.T, h *InboxFilterReader) []*filter.Rule {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handleGet(rw, httptest.NewRequest(http.MethodGet, inboxFiltersURL, nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	var rules []*filter.Rule
+
+	require.NoError(t, json.Unmarshal(respBytes, &rules))
+
+	return rules
+}
+
+func postInboxFilterRules(t *testing.T, h *InboxFilterWriter, reqBytes []byte) int {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handlePost(rw, httptest.NewRequest(http.MethodPost, inboxFiltersURL, bytes.NewBuffer(reqBytes)))
+
+	result := rw.Result()
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode
+}
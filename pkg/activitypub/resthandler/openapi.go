@@ -6,14 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 
 package resthandler
 
-import "github.com/trustbloc/orb/pkg/activitypub/vocab"
+import (
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
+	"github.com/trustbloc/orb/pkg/activitypub/service/backfill"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/filter"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/store/delivery"
+	"github.com/trustbloc/orb/pkg/store/quarantine"
+)
 
 // Request message
 //
 // swagger:parameters acceptListGetReq
 type acceptListGetReq struct { //nolint: unused
 	// Type
-	// enum: follow,invite-witness
+	// enum: follow,invite-witness,anchor-origin
 	Type string `json:"type"`
 }
 
@@ -27,7 +34,7 @@ type acceptListGetResp struct { //nolint: unused
 
 // handleGet swagger:route GET /acceptlist ActivityPub acceptListGetReq
 //
-// Returns the accept-list. If type is specified then the accept-list for the given type (follow or invite-witness) is returned, otherwise all accept-lists are returned.
+// Returns the accept-list. If type is specified then the accept-list for the given type (follow, invite-witness or anchor-origin) is returned, otherwise all accept-lists are returned.
 //
 // Responses:
 //
@@ -62,6 +69,257 @@ type acceptListPostResp struct { //nolint: unused
 func acceptlistPostRequest() { //nolint: unused
 }
 
+// Request message
+//
+// swagger:parameters deliveriesGetReq
+type deliveriesGetReq struct { //nolint: unused
+	// Status
+	// enum: failed,pending
+	Status string `json:"status"`
+}
+
+// Response message
+//
+// swagger:response deliveriesGetResp
+type deliveriesGetResp struct { //nolint: unused
+	// in: body
+	Body []delivery.Delivery
+}
+
+// handleGet swagger:route GET /deliveries ActivityPub deliveriesGetReq
+//
+// Returns the failed outbox deliveries. By default, the deliveries in the dead-letter collection (status=failed) are returned. Deliveries that are still being retried are returned with status=pending.
+//
+// Responses:
+//
+//	200: deliveriesGetResp
+//
+//nolint:lll
+func deliveriesGetRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters deliveriesPostReq
+type deliveriesPostReq struct { //nolint: unused
+	// in: body
+	Body redeliveryRequest
+}
+
+// Response message
+//
+// swagger:response deliveriesPostResp
+type deliveriesPostResp struct { //nolint: unused
+	Body string
+}
+
+// handlePost swagger:route POST /deliveries ActivityPub deliveriesPostReq
+//
+// Re-drives the given failed outbox deliveries. If a target inbox is specified then all failed deliveries to that inbox are re-driven.
+//
+// Responses:
+//
+//	200: deliveriesPostResp
+//
+//nolint:lll
+func deliveriesPostRequest() { //nolint: unused
+}
+
+// Response message
+//
+// swagger:response inboxFiltersGetResp
+type inboxFiltersGetResp struct { //nolint: unused
+	// in: body
+	Body []filter.Rule
+}
+
+// handleGet swagger:route GET /inbox-filters ActivityPub inboxFiltersGetReq
+//
+// Returns the ordered list of inbox filter rules.
+//
+// Responses:
+//
+//	200: inboxFiltersGetResp
+func inboxFiltersGetRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters inboxFiltersPostReq
+type inboxFiltersPostReq struct { //nolint: unused
+	// in: body
+	Body []filter.Rule
+}
+
+// Response message
+//
+// swagger:response inboxFiltersPostResp
+type inboxFiltersPostResp struct { //nolint: unused
+	Body string
+}
+
+// handlePost swagger:route POST /inbox-filters ActivityPub inboxFiltersPostReq
+//
+// Replaces the inbox filter rules. The rules are evaluated in order and the action (accept, reject or quarantine) of the first matching rule is applied to the inbound activity.
+//
+// Responses:
+//
+//	200: inboxFiltersPostResp
+//
+//nolint:lll
+func inboxFiltersPostRequest() { //nolint: unused
+}
+
+// Response message
+//
+// swagger:response quarantineGetResp
+type quarantineGetResp struct { //nolint: unused
+	// in: body
+	Body []quarantine.Entry
+}
+
+// handleGet swagger:route GET /quarantine ActivityPub quarantineGetReq
+//
+// Returns the inbound activities that were quarantined by the inbox filter.
+//
+// Responses:
+//
+//	200: quarantineGetResp
+func quarantineGetRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters quarantinePostReq
+type quarantinePostReq struct { //nolint: unused
+	// in: body
+	Body quarantineRequest
+}
+
+// Response message
+//
+// swagger:response quarantinePostResp
+type quarantinePostResp struct { //nolint: unused
+	Body string
+}
+
+// handlePost swagger:route POST /quarantine ActivityPub quarantinePostReq
+//
+// Releases the given quarantined activities into the inbox or discards them.
+//
+// Responses:
+//
+//	200: quarantinePostResp
+func quarantinePostRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters backfillGetReq
+type backfillGetReq struct { //nolint: unused
+	// ID
+	ID string `json:"id"`
+}
+
+// Response message
+//
+// swagger:response backfillGetResp
+type backfillGetResp struct { //nolint: unused
+	// in: body
+	Body []backfill.Job
+}
+
+// handleGet swagger:route GET /backfill ActivityPub backfillGetReq
+//
+// Returns the progress of backfill jobs. If an ID is specified then only the given job is returned.
+//
+// Responses:
+//
+//	200: backfillGetResp
+func backfillGetRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters backfillPostReq
+type backfillPostReq struct { //nolint: unused
+	// in: body
+	Body backfillRequest
+}
+
+// Response message
+//
+// swagger:response backfillPostResp
+type backfillPostResp struct { //nolint: unused
+	// in: body
+	Body backfill.Job
+}
+
+// handlePost swagger:route POST /backfill ActivityPub backfillPostReq
+//
+// Starts a backfill of the anchor history from the outbox of the given service. The outbox is read from the beginning and the position is checkpointed so that the backfill resumes after a restart.
+//
+// Responses:
+//
+//	200: backfillPostResp
+//
+//nolint:lll
+func backfillPostRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters peersGetReq
+type peersGetReq struct { //nolint: unused
+	// IRI
+	IRI string `json:"iri"`
+}
+
+// Response message
+//
+// swagger:response peersGetResp
+type peersGetResp struct { //nolint: unused
+	// in: body
+	Body []peerhealth.Health
+}
+
+// handleGet swagger:route GET /peers ActivityPub peersGetReq
+//
+// Returns the health of the ActivityPub peers (circuit state, consecutive failures, latency and health score). If the iri parameter is specified then only the health of the given peer is returned.
+//
+// Responses:
+//
+//	200: peersGetResp
+//
+//nolint:lll
+func peersGetRequest() { //nolint: unused
+}
+
+// Request message
+//
+// swagger:parameters actorCacheDeleteReq
+type actorCacheDeleteReq struct { //nolint: unused
+	// IRI
+	IRI string `json:"iri"`
+}
+
+// Response message
+//
+// swagger:response actorCacheDeleteResp
+type actorCacheDeleteResp struct { //nolint: unused
+	Body string
+}
+
+// handleDelete swagger:route DELETE /actor-cache ActivityPub actorCacheDeleteReq
+//
+// Purges the given actor, along with the actor's public key, from the cache so that they are reloaded upon next access.
+//
+// Responses:
+//
+//	200: actorCacheDeleteResp
+func actorCacheDeleteRequest() { //nolint: unused
+}
+
 // swagger:parameters serviceGetReq
 type serviceGetReq struct { //nolint: unused
 }
@@ -232,6 +490,16 @@ func witnessingGetRequest() { //nolint: unused
 type inboxGetReq struct { //nolint: unused
 	Page    bool   `json:"page"`
 	PageNum string `json:"page-num"`
+	// Filters the activities by type, e.g. Create
+	Type string `json:"type"`
+	// Filters the activities by actor IRI
+	Actor string `json:"actor"`
+	// Returns only the activities published at or after the given time (RFC3339)
+	Since string `json:"since"`
+	// Returns only the activities published at or before the given time (RFC3339)
+	Until string `json:"until"`
+	// Returns only the activities that reference the given anchor hashlink
+	Anchor string `json:"anchor"`
 }
 
 // swagger:response inboxGetResp
@@ -242,7 +510,7 @@ type inboxGetResp struct { //nolint: unused
 
 // inboxGetRequest swagger:route GET /services/orb/inbox ActivityPub inboxGetReq
 //
-// The activities posted to the inbox of this service are returned via this endpoint. If no paging parameters are specified in the URL then the response contains information about the inbox collection, i.e. the links to the first and last page, as well as the total number of items in the inbox. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items.
+// The activities posted to the inbox of this service are returned via this endpoint. If no paging parameters are specified in the URL then the response contains information about the inbox collection, i.e. the links to the first and last page, as well as the total number of items in the inbox. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items. The activities may be filtered using the 'type', 'actor', 'since', 'until' and 'anchor' query parameters, in which case the total number of items and the page links reflect the filtered results.
 //
 // Produces:
 // - application/json
@@ -288,6 +556,16 @@ func inboxPostRequest() { //nolint: unused
 type outboxGetReq struct { //nolint: unused
 	Page    bool   `json:"page"`
 	PageNum string `json:"page-num"`
+	// Filters the activities by type, e.g. Create
+	Type string `json:"type"`
+	// Filters the activities by actor IRI
+	Actor string `json:"actor"`
+	// Returns only the activities published at or after the given time (RFC3339)
+	Since string `json:"since"`
+	// Returns only the activities published at or before the given time (RFC3339)
+	Until string `json:"until"`
+	// Returns only the activities that reference the given anchor hashlink
+	Anchor string `json:"anchor"`
 }
 
 // swagger:response outboxGetResp
@@ -298,7 +576,7 @@ type outboxGetResp struct { //nolint: unused
 
 // outboxGetRequest swagger:route GET /services/orb/outbox ActivityPub outboxGetReq
 //
-// A GET request to the outbox endpoint returns the activities that were posted to a service’s Outbox. This endpoint is restricted by authorization rules, i.e. the requester must have a valid authorization bearer token or must be verified using HTTP signatures and also must be in the following or witnesses collection. Although, any activity sent to a public URI, is returned without authorization. If no paging parameters are specified in the URL then the response contains information about the outbox collection, i.e. the links to the first and last page, as well as the total number of items in the collection. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items.
+// A GET request to the outbox endpoint returns the activities that were posted to a service’s Outbox. This endpoint is restricted by authorization rules, i.e. the requester must have a valid authorization bearer token or must be verified using HTTP signatures and also must be in the following or witnesses collection. Although, any activity sent to a public URI, is returned without authorization. If no paging parameters are specified in the URL then the response contains information about the outbox collection, i.e. the links to the first and last page, as well as the total number of items in the collection. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items. The activities may be filtered using the 'type', 'actor', 'since', 'until' and 'anchor' query parameters, in which case the total number of items and the page links reflect the filtered results.
 //
 // Produces:
 // - application/json
@@ -346,6 +624,16 @@ type likesGetReq struct { //nolint: unused
 	ID      string `json:"id"`
 	Page    bool   `json:"page"`
 	PageNum string `json:"page-num"`
+	// Filters the activities by type, e.g. Create
+	Type string `json:"type"`
+	// Filters the activities by actor IRI
+	Actor string `json:"actor"`
+	// Returns only the activities published at or after the given time (RFC3339)
+	Since string `json:"since"`
+	// Returns only the activities published at or before the given time (RFC3339)
+	Until string `json:"until"`
+	// Returns only the activities that reference the given anchor hashlink
+	Anchor string `json:"anchor"`
 }
 
 // swagger:response likesGetResp
@@ -356,7 +644,7 @@ type likesGetResp struct { //nolint: unused
 
 // likesGetRequest swagger:route GET /services/orb/likes/{id} ActivityPub likesGetReq
 //
-// This endpoint returns a collection of Like activities for a given anchor. If no paging parameters are specified in the URL then the response contains information about the collection, i.e. the links to the first and last page, as well as the total number of items in the collection. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items.
+// This endpoint returns a collection of Like activities for a given anchor. If no paging parameters are specified in the URL then the response contains information about the collection, i.e. the links to the first and last page, as well as the total number of items in the collection. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items. The activities may be filtered using the 'type', 'actor', 'since', 'until' and 'anchor' query parameters, in which case the total number of items and the page links reflect the filtered results.
 //
 // Produces:
 // - application/json
@@ -402,6 +690,16 @@ type sharesGetReq struct { //nolint: unused
 	ID      string `json:"id"`
 	Page    bool   `json:"page"`
 	PageNum string `json:"page-num"` //nolint:tagliatelle
+	// Filters the activities by type, e.g. Create
+	Type string `json:"type"`
+	// Filters the activities by actor IRI
+	Actor string `json:"actor"`
+	// Returns only the activities published at or after the given time (RFC3339)
+	Since string `json:"since"`
+	// Returns only the activities published at or before the given time (RFC3339)
+	Until string `json:"until"`
+	// Returns only the activities that reference the given anchor hashlink
+	Anchor string `json:"anchor"`
 }
 
 // swagger:response sharesGetResp
@@ -412,7 +710,7 @@ type sharesGetResp struct { //nolint: unused
 
 // sharesGetRequest swagger:route GET /services/orb/shares/{id} ActivityPub sharesGetReq
 //
-// The Create activities that were Announced are returned via this endpoint. If no paging parameters are specified in the URL then the response contains information about the collection, i.e. the links to the first and last page, as well as the total number of items in the collection. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items.
+// The Create activities that were Announced are returned via this endpoint. If no paging parameters are specified in the URL then the response contains information about the collection, i.e. the links to the first and last page, as well as the total number of items in the collection. A subsequent request may be made using parameters that include a specified page number in order to retrieve the actual items. The activities may be filtered using the 'type', 'actor', 'since', 'until' and 'anchor' query parameters, in which case the total number of items and the page links reflect the filtered results.
 //
 // Produces:
 // - application/json
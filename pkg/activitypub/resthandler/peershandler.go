@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
+)
+
+const iriParam = "iri"
+
+type peerHealthRegistry interface {
+	Get(iri *url.URL) *peerhealth.Health
+	GetAll() []*peerhealth.Health
+}
+
+// PeerHealth implements a REST handler that returns the health of the ActivityPub peers. By default, the health
+// of all known peers is returned. The health of a single peer may be returned by specifying the parameter,
+// iri=<actor IRI>.
+type PeerHealth struct {
+	endpoint string
+	registry peerHealthRegistry
+	marshal  func(v interface{}) ([]byte, error)
+	logger   *log.Log
+}
+
+// NewPeerHealth returns a new REST handler to read the health of ActivityPub peers.
+func NewPeerHealth(cfg *Config, registry peerHealthRegistry) *PeerHealth {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, PeersPath)
+
+	return &PeerHealth{
+		endpoint: endpoint,
+		registry: registry,
+		marshal:  json.Marshal,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *PeerHealth) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *PeerHealth) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *PeerHealth) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *PeerHealth) handleGet(w http.ResponseWriter, req *http.Request) {
+	var peers []*peerhealth.Health
+
+	if values := req.URL.Query()[iriParam]; len(values) > 0 && values[0] != "" {
+		iri, err := url.Parse(values[0])
+		if err != nil {
+			h.logger.Debug("Invalid peer IRI", logfields.WithParameter(iriParam), log.WithError(err))
+
+			writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+
+		health := h.registry.Get(iri)
+		if health == nil {
+			writeResponse(h.logger, w, http.StatusNotFound, []byte(notFoundResponse))
+
+			return
+		}
+
+		peers = []*peerhealth.Health{health}
+	} else {
+		peers = h.registry.GetAll()
+	}
+
+	respBytes, err := h.marshal(peers)
+	if err != nil {
+		h.logger.Error("Error marshalling peer health", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, respBytes)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+const peersURL = "https://example.com/services/orb/peers"
+
+func TestNewPeerHealth(t *testing.T) {
+	h := NewPeerHealth(&Config{BasePath: "/services/orb"}, peerhealth.New(peerhealth.Config{}))
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, "/services/orb/peers", h.Path())
+}
+
+func TestPeerHealth_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	service1IRI := testutil.MustParseURL("https://domain1.com/services/orb")
+	service2IRI := testutil.MustParseURL("https://domain2.com/services/orb")
+
+	registry := peerhealth.New(peerhealth.Config{FailureThreshold: 1})
+	registry.RecordSuccess(service1IRI, 50*time.Millisecond)
+	registry.RecordFailure(service2IRI, errors.New("connection refused"))
+
+	t.Run("All peers", func(t *testing.T) {
+		status, peers := getPeers(t, NewPeerHealth(cfg, registry), peersURL)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, peers, 2)
+		require.Equal(t, peerhealth.StateClosed, peers[0].State)
+		require.Equal(t, peerhealth.StateOpen, peers[1].State)
+	})
+
+	t.Run("Single peer", func(t *testing.T) {
+		status, peers := getPeers(t, NewPeerHealth(cfg, registry), peersURL+"?iri="+service2IRI.String())
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, peers, 1)
+		require.Equal(t, service2IRI.String(), peers[0].IRI)
+	})
+
+	t.Run("Peer not found", func(t *testing.T) {
+		status, _ := getPeers(t, NewPeerHealth(cfg, registry), peersURL+"?iri=https://domain3.com/services/orb")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Invalid IRI", func(t *testing.T) {
+		status, _ := getPeers(t, NewPeerHealth(cfg, registry), peersURL+"?iri=%3Ainvalid")
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewPeerHealth(cfg, registry)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		status, _ := getPeers(t, h, peersURL)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func getPeers(t *testing.T, h *PeerHealth, u string) (int, []*peerhealth.Health) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handleGet(rw, httptest.NewRequest(http.MethodGet, u, nil))
+
+	result := rw.Result()
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	if result.StatusCode != http.StatusOK {
+		return result.StatusCode, nil
+	}
+
+	var peers []*peerhealth.Health
+
+	require.NoError(t, json.Unmarshal(respBytes, &peers))
+
+	return result.StatusCode, peers
+}
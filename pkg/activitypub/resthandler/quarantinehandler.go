@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/quarantine"
+)
+
+type quarantineStore interface {
+	GetAll() ([]*quarantine.Entry, error)
+	Get(id string) (*quarantine.Entry, error)
+	Delete(id string) error
+}
+
+type quarantineReleaser interface {
+	ReleaseQuarantined(ctx context.Context, id string) error
+}
+
+// QuarantineReader implements a REST handler that returns the inbound activities that were quarantined
+// by the inbox filter.
+type QuarantineReader struct {
+	endpoint string
+	store    quarantineStore
+	marshal  func(v interface{}) ([]byte, error)
+	logger   *log.Log
+}
+
+// NewQuarantineReader returns a new REST handler to read quarantined activities.
+func NewQuarantineReader(cfg *Config, s quarantineStore) *QuarantineReader {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, QuarantinePath)
+
+	return &QuarantineReader{
+		endpoint: endpoint,
+		store:    s,
+		marshal:  json.Marshal,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *QuarantineReader) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *QuarantineReader) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *QuarantineReader) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *QuarantineReader) handleGet(w http.ResponseWriter, _ *http.Request) {
+	entries, err := h.store.GetAll()
+	if err != nil {
+		h.logger.Error("Error querying quarantined activities", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	if entries == nil {
+		entries = []*quarantine.Entry{}
+	}
+
+	respBytes, err := h.marshal(entries)
+	if err != nil {
+		h.logger.Error("Error marshalling quarantined activities", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, respBytes)
+}
+
+// QuarantineWriter implements a REST handler that releases or discards quarantined activities. A released
+// activity is processed by the inbox (bypassing the filter) and a discarded activity is simply deleted.
+type QuarantineWriter struct {
+	endpoint string
+	store    quarantineStore
+	releaser quarantineReleaser
+	readAll  func(r io.Reader) ([]byte, error)
+	logger   *log.Log
+}
+
+// NewQuarantineWriter returns a new REST handler to release or discard quarantined activities.
+func NewQuarantineWriter(cfg *Config, s quarantineStore, r quarantineReleaser) *QuarantineWriter {
+	endpoint := fmt.Sprintf("%s%s", cfg.BasePath, QuarantinePath)
+
+	return &QuarantineWriter{
+		endpoint: endpoint,
+		store:    s,
+		releaser: r,
+		readAll:  io.ReadAll,
+		logger:   log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(endpoint))),
+	}
+}
+
+// Method returns the HTTP method, which is always POST.
+func (h *QuarantineWriter) Method() string {
+	return http.MethodPost
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *QuarantineWriter) Path() string {
+	return h.endpoint
+}
+
+// Handler returns the handler that should be invoked when an HTTP POST is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *QuarantineWriter) Handler() common.HTTPRequestHandler {
+	return h.handlePost
+}
+
+func (h *QuarantineWriter) handlePost(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := h.readAll(req.Body)
+	if err != nil {
+		h.logger.Error("Error reading request body", log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	h.logger.Debug("Got request to release/discard quarantined activities", logfields.WithRequestBody(reqBytes))
+
+	request := &quarantineRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil || (len(request.Release) == 0 && len(request.Discard) == 0) {
+		h.logger.Info("Invalid quarantine request", logfields.WithRequestBody(reqBytes))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	for _, id := range request.Release {
+		if err = h.releaser.ReleaseQuarantined(req.Context(), id); err != nil {
+			h.writeError(w, id, err)
+
+			return
+		}
+	}
+
+	for _, id := range request.Discard {
+		if err = h.discard(id); err != nil {
+			h.writeError(w, id, err)
+
+			return
+		}
+	}
+
+	writeResponse(h.logger, w, http.StatusOK, nil)
+}
+
+func (h *QuarantineWriter) discard(id string) error {
+	// Ensure that the entry exists so that a 404 is returned for an unknown ID.
+	if _, err := h.store.Get(id); err != nil {
+		return err
+	}
+
+	h.logger.Info("Discarding quarantined activity", logfields.WithID(id))
+
+	return h.store.Delete(id)
+}
+
+func (h *QuarantineWriter) writeError(w http.ResponseWriter, id string, err error) {
+	switch {
+	case errors.Is(err, orberrors.ErrContentNotFound):
+		h.logger.Info("Quarantined activity not found", logfields.WithID(id))
+
+		writeResponse(h.logger, w, http.StatusNotFound, []byte(notFoundResponse))
+	case orberrors.IsBadRequest(err):
+		h.logger.Info("Released activity was not processed", logfields.WithID(id), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusBadRequest, []byte(err.Error()))
+	default:
+		h.logger.Error("Error handling quarantined activity", logfields.WithID(id), log.WithError(err))
+
+		writeResponse(h.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+	}
+}
+
+type quarantineRequest struct {
+	Release []string `json:"release,omitempty"`
+	Discard []string `json:"discard,omitempty"`
+}
@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/quarantine"
+)
+
+const quarantineURL = "https://example.com/services/orb/quarantine"
+
+func TestNewQuarantineHandlers(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	r := NewQuarantineReader(cfg, &mockQuarantineStore{})
+	require.NotNil(t, r.Handler())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.Equal(t, "/services/orb/quarantine", r.Path())
+
+	w := NewQuarantineWriter(cfg, &mockQuarantineStore{}, &mockQuarantineReleaser{})
+	require.NotNil(t, w.Handler())
+	require.Equal(t, http.MethodPost, w.Method())
+	require.Equal(t, "/services/orb/quarantine", w.Path())
+}
+
+func TestQuarantineReader_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	s, err := quarantine.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	t.Run("Success - empty", func(t *testing.T) {
+		entries := getQuarantined(t, NewQuarantineReader(cfg, s))
+		require.Empty(t, entries)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, s.Put(&quarantine.Entry{ID: "1", Activity: []byte(`{"type":"Follow"}`)}))
+
+		entries := getQuarantined(t, NewQuarantineReader(cfg, s))
+		require.Len(t, entries, 1)
+		require.Equal(t, "1", entries[0].ID)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h := NewQuarantineReader(cfg, &mockQuarantineStore{err: errors.New("injected query error")})
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, quarantineURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+
+		h := NewQuarantineReader(cfg, s)
+		h.marshal = func(v interface{}) ([]byte, error) {
+			return nil, errors.New("injected marshal error")
+		}
+
+		h.handleGet(rw, httptest.NewRequest(http.MethodGet, quarantineURL, nil))
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+func TestQuarantineWriter_Handler(t *testing.T) {
+	cfg := &Config{
+		BasePath: "/services/orb",
+	}
+
+	t.Run("Success - release", func(t *testing.T) {
+		r := &mockQuarantineReleaser{}
+
+		status := postQuarantine(t, NewQuarantineWriter(cfg, &mockQuarantineStore{}, r),
+			&quarantineRequest{Release: []string{"1", "2"}})
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []string{"1", "2"}, r.ids)
+	})
+
+	t.Run("Success - discard", func(t *testing.T) {
+		s, err := quarantine.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(&quarantine.Entry{ID: "1", Activity: []byte(`{}`)}))
+
+		status := postQuarantine(t, NewQuarantineWriter(cfg, s, &mockQuarantineReleaser{}),
+			&quarantineRequest{Discard: []string{"1"}})
+		require.Equal(t, http.StatusOK, status)
+
+		_, err = s.Get("1")
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		status = postQuarantine(t, NewQuarantineWriter(cfg, s, &mockQuarantineReleaser{}),
+			&quarantineRequest{Discard: []string{"1"}})
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Empty request", func(t *testing.T) {
+		status := postQuarantine(t, NewQuarantineWriter(cfg, &mockQuarantineStore{}, &mockQuarantineReleaser{}),
+			&quarantineRequest{})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Release - not found", func(t *testing.T) {
+		r := &mockQuarantineReleaser{err: orberrors.ErrContentNotFound}
+
+		status := postQuarantine(t, NewQuarantineWriter(cfg, &mockQuarantineStore{}, r),
+			&quarantineRequest{Release: []string{"1"}})
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("Release - bad request", func(t *testing.T) {
+		r := &mockQuarantineReleaser{err: orberrors.NewBadRequestf("invalid activity")}
+
+		status := postQuarantine(t, NewQuarantineWriter(cfg, &mockQuarantineStore{}, r),
+			&quarantineRequest{Release: []string{"1"}})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("Release - error", func(t *testing.T) {
+		r := &mockQuarantineReleaser{err: errors.New("injected release error")}
+
+		status := postQuarantine(t, NewQuarantineWriter(cfg, &mockQuarantineStore{}, r),
+			&quarantineRequest{Release: []string{"1"}})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		h := NewQuarantineWriter(cfg, &mockQuarantineStore{}, &mockQuarantineReleaser{})
+		h.readAll = func(r io.Reader) ([]byte, error) {
+			return nil, errors.New("injected read error")
+		}
+
+		status := postQuarantine(t, h, &quarantineRequest{Release: []string{"1"}})
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func getQuarantined(t *testing.T, h *QuarantineReader) []*quarantine.Entry {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	h.handleGet(rw, httptest.NewRequest(http.MethodGet, quarantineURL, nil))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	var entries []*quarantine.Entry
+
+	require.NoError(t, json.Unmarshal(respBytes, &entries))
+
+	return entries
+}
+
+func postQuarantine(t *testing.T, h *QuarantineWriter, request *quarantineRequest) int {
+	t.Helper()
+
+	reqBytes, err := json.Marshal(request)
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+
+	h.handlePost(rw, httptest.NewRequest(http.MethodPost, quarantineURL, bytes.NewBuffer(reqBytes)))
+
+	result := rw.Result()
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode
+}
+
+type mockQuarantineStore struct {
+	entries []*quarantine.Entry
+	err     error
+}
+
+func (m *mockQuarantineStore) GetAll() ([]*quarantine.Entry, error) {
+	return m.entries, m.err
+}
+
+func (m *mockQuarantineStore) Get(string) (*quarantine.Entry, error) {
+	return nil, orberrors.ErrContentNotFound
+}
+
+func (m *mockQuarantineStore) Delete(string) error {
+	return m.err
+}
+
+type mockQuarantineReleaser struct {
+	ids []string
+	err error
+}
+
+func (m *mockQuarantineReleaser) ReleaseQuarantined(_ context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.ids = append(m.ids, id)
+
+	return nil
+}
@@ -50,6 +50,18 @@ const (
 	ActivitiesPath = "/activities/{id}"
 	// AcceptListPath specifies the endpoint to manage an "accept list" for a service.
 	AcceptListPath = "/acceptlist"
+	// DeliveriesPath specifies the endpoint to inspect and re-drive failed outbox deliveries.
+	DeliveriesPath = "/deliveries"
+	// PeersPath specifies the endpoint to inspect the health of ActivityPub peers.
+	PeersPath = "/peers"
+	// InboxFiltersPath specifies the endpoint to manage the filter rules that are applied to inbound activities.
+	InboxFiltersPath = "/inbox-filters"
+	// QuarantinePath specifies the endpoint to review, release and discard quarantined inbound activities.
+	QuarantinePath = "/quarantine"
+	// BackfillPath specifies the endpoint to start and monitor backfills from the outboxes of remote services.
+	BackfillPath = "/backfill"
+	// ActorCachePath specifies the endpoint to purge cached actors (along with their public keys).
+	ActorCachePath = "/actor-cache"
 )
 
 const (
@@ -57,6 +69,10 @@ const (
 	pageNumParam = "page-num"
 	idParam      = "id"
 	typeParam    = "type"
+	actorParam   = "actor"
+	sinceParam   = "since"
+	untilParam   = "until"
+	anchorParam  = "anchor"
 
 	authHeader  = "Authorization"
 	tokenPrefix = "Bearer "
@@ -170,6 +170,8 @@ func (h *Services) newService() (*vocab.ActorType, error) {
 	return vocab.NewService(h.ObjectIRI,
 		vocab.WithPublicKey(h.publicKey),
 		vocab.WithInbox(inbox),
+		// The inbox accepts activities for all local addressees so it also serves as the shared inbox.
+		vocab.WithSharedInbox(inbox),
 		vocab.WithOutbox(outbox),
 		vocab.WithFollowers(followers),
 		vocab.WithFollowing(following),
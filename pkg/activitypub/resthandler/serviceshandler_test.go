@@ -262,6 +262,9 @@ const (
     "https://w3id.org/security/v1",
     "https://w3id.org/activityanchors/v1"
   ],
+  "endpoints": {
+    "sharedInbox": "https://example1.com/services/orb/inbox"
+  },
   "followers": "https://example1.com/services/orb/followers",
   "following": "https://example1.com/services/orb/following",
   "id": "https://example1.com/services/orb",
@@ -53,6 +53,7 @@ type Config struct {
 
 type activityPubClient interface {
 	GetActor(iri *url.URL) (*vocab.ActorType, error)
+	MarkActorAsStale(actorIRI *url.URL)
 }
 
 type undoFunc func(activity *vocab.ActivityType) error
@@ -68,11 +69,12 @@ type handler struct {
 	undoFollow        undoFunc
 	undoInviteWitness undoFunc
 	undoLike          undoFunc
+	undoBlock         undoFunc
 	logger            *log.Log
 }
 
 func newHandler(cfg *Config, s store.Store, activityPubClient activityPubClient,
-	undoFollow, undoInviteWitness, undoLike undoFunc,
+	undoFollow, undoInviteWitness, undoLike, undoBlock undoFunc,
 ) *handler {
 	if cfg.BufferSize == 0 {
 		cfg.BufferSize = defaultBufferSize
@@ -89,6 +91,7 @@ func newHandler(cfg *Config, s store.Store, activityPubClient activityPubClient,
 		undoFollow:        undoFollow,
 		undoInviteWitness: undoInviteWitness,
 		undoLike:          undoLike,
+		undoBlock:         undoBlock,
 		logger:            log.New(loggerModule, log.WithFields(logfields.WithServiceName(cfg.ServiceName))),
 	}
 
@@ -181,6 +184,9 @@ func (h *handler) undoActivity(activity *vocab.ActivityType) error {
 	case activity.Type().Is(vocab.TypeLike):
 		return h.undoLike(activity)
 
+	case activity.Type().Is(vocab.TypeBlock):
+		return h.undoBlock(activity)
+
 	default:
 		return fmt.Errorf("undo of type %s is not supported", activity.Type())
 	}
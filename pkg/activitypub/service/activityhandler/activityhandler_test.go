@@ -8,6 +8,7 @@ package activityhandler
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
 
@@ -28,11 +30,15 @@ import (
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
+	"github.com/trustbloc/orb/pkg/datauri"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/internal/aptestutil"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/lifecycle"
 	"github.com/trustbloc/orb/pkg/linkset"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	casstore "github.com/trustbloc/orb/pkg/store/cas"
 )
 
 func TestNewInbox(t *testing.T) {
@@ -108,6 +114,133 @@ func TestHandler_HandleUnsupportedActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_InboxSharedInbox(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	tenantIRI := testutil.MustParseURL("http://localhost:8301/services/tenant2")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	h := NewInbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewOutbox(),
+		servicemocks.NewActivitPubClient().WithActor(vocab.NewService(service2IRI)))
+	require.NotNil(t, h)
+
+	h.Start()
+	defer h.Stop()
+
+	subscriber := newMockActivitySubscriber(h.Subscribe())
+	go subscriber.Listen()
+
+	t.Run("Addressed to other actor on local domain -> ignored", func(t *testing.T) {
+		follow := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(tenantIRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(tenantIRI),
+		)
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, follow))
+
+		time.Sleep(50 * time.Millisecond)
+
+		require.Nil(t, subscriber.Activity(follow.ID()))
+	})
+
+	t.Run("Addressed to local service and other actor on local domain -> handled", func(t *testing.T) {
+		follow := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(tenantIRI, service1IRI),
+		)
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, follow))
+
+		time.Sleep(50 * time.Millisecond)
+
+		require.NotNil(t, subscriber.Activity(follow.ID()))
+	})
+
+	t.Run("Addressed to sibling path of local service -> ignored", func(t *testing.T) {
+		siblingIRI := testutil.MustParseURL("http://localhost:8301/services/service12")
+
+		follow := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(siblingIRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(siblingIRI),
+		)
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, follow))
+
+		time.Sleep(50 * time.Millisecond)
+
+		require.Nil(t, subscriber.Activity(follow.ID()))
+	})
+}
+
+func TestIsLocalEndpoint(t *testing.T) {
+	endpoint := testutil.MustParseURL("http://localhost:8301/services/service1")
+
+	require.True(t, isLocalEndpoint(endpoint, endpoint))
+	require.True(t, isLocalEndpoint(testutil.MustParseURL("http://localhost:8301/services/service1/inbox"), endpoint))
+	require.False(t, isLocalEndpoint(testutil.MustParseURL("http://localhost:8301/services/service12"), endpoint))
+	require.False(t, isLocalEndpoint(testutil.MustParseURL("http://localhost:8301/services"), endpoint))
+}
+
+func TestHandler_InboxVerifyActivityProof(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+		vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+		vocab.WithActor(service2IRI),
+		vocab.WithTo(service1IRI),
+	)
+
+	t.Run("Valid proof -> handled", func(t *testing.T) {
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewOutbox(),
+			servicemocks.NewActivitPubClient().WithActor(vocab.NewService(service2IRI)),
+			spi.WithActivityProofVerifier(&mockProofVerifier{}),
+		)
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, follow))
+	})
+
+	t.Run("Invalid proof -> rejected", func(t *testing.T) {
+		errExpected := orberrors.NewBadRequest(errors.New("injected verify error"))
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewOutbox(),
+			servicemocks.NewActivitPubClient().WithActor(vocab.NewService(service2IRI)),
+			spi.WithActivityProofVerifier(&mockProofVerifier{err: errExpected}),
+		)
+		require.NotNil(t, h)
+
+		h.Start()
+		defer h.Stop()
+
+		err := h.HandleActivity(context.Background(), nil, follow)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "injected verify error")
+	})
+}
+
 func TestHandler_InboxHandleCreateActivity(t *testing.T) {
 	log.SetLevel("activitypub_service", log.DEBUG)
 
@@ -167,6 +300,53 @@ func TestHandler_InboxHandleCreateActivity(t *testing.T) {
 			require.NotEmpty(t, refs)
 		})
 
+		t.Run("Anchor linkset larger than a single chunk", func(t *testing.T) {
+			// Random content isn't compressed so the linkset spans multiple (256KB) chunks.
+			largeContent := make([]byte, 300*1024)
+			_, err := rand.Read(largeContent)
+			require.NoError(t, err)
+
+			dataURI, err := datauri.New(largeContent, datauri.MediaTypeDataURIGzipBase64)
+			require.NoError(t, err)
+
+			mockLink := aptestutil.NewMockAnchorLink(t)
+
+			anchorLinkset := linkset.New(linkset.NewLink(mockLink.Anchor(), mockLink.Author(), mockLink.Profile(),
+				mockLink.Original(), mockLink.Related(), linkset.NewReference(dataURI, linkset.TypeJSONLD)))
+
+			// Write the anchor linkset to the CAS in the same way as the anchor writer does. The linkset
+			// is addressed by the root of its UnixFS DAG.
+			casClient, err := casstore.New(mem.NewProvider(), "https://orb.domain1.com/cas", nil,
+				&orbmocks.MetricsProvider{}, 0)
+			require.NoError(t, err)
+
+			linksetBytes := testutil.MarshalCanonical(t, anchorLinkset)
+			require.Greater(t, len(linksetBytes), unixfs.ChunkSize)
+
+			hl, err := casClient.Write(linksetBytes)
+			require.NoError(t, err)
+
+			flatHash, err := unixfs.FlatResourceHash(linksetBytes)
+			require.NoError(t, err)
+			require.NotContains(t, hl, flatHash)
+
+			anchorLinksetDoc, err := vocab.MarshalToDoc(anchorLinkset)
+			require.NoError(t, err)
+
+			anchorEvent := vocab.NewAnchorEvent(
+				vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc)),
+				vocab.WithURL(testutil.MustParseURL(hl)),
+			)
+
+			create := aptestutil.NewMockCreateActivity(service1IRI, service2IRI,
+				vocab.NewObjectProperty(vocab.WithAnchorEvent(anchorEvent)))
+
+			require.NoError(t, h.HandleActivity(context.Background(), nil, create))
+
+			_, exists := anchorEventHandler.AnchorEvent(hl)
+			require.True(t, exists)
+		})
+
 		t.Run("Handler error", func(t *testing.T) {
 			anchorEvent := aptestutil.NewMockAnchorEvent(t, aptestutil.NewMockAnchorLink(t))
 
@@ -918,6 +1098,7 @@ func TestHandler_HandleAcceptActivityValidationError(t *testing.T) {
 
 	ob := servicemocks.NewOutbox()
 	as := &servicemocks.ActivityStore{}
+	as.QueryReferencesReturns(memstore.NewReferenceIterator(nil, 0), nil)
 
 	h := NewInbox(cfg, as, ob, servicemocks.NewActivitPubClient())
 	require.NotNil(t, h)
@@ -1050,7 +1231,7 @@ func TestHandler_HandleAcceptActivityError(t *testing.T) {
 
 		errExpected := fmt.Errorf("injected storage error")
 
-		as.QueryReferencesReturns(nil, errExpected)
+		as.QueryReferencesCalls(queryReferencesErrorExceptBlocked(errExpected))
 
 		err := h.HandleActivity(context.Background(), nil, acceptFollow)
 		require.Error(t, err)
@@ -1077,7 +1258,7 @@ func TestHandler_HandleAcceptActivityError(t *testing.T) {
 
 		errExpected := fmt.Errorf("injected storage error")
 
-		as.QueryReferencesReturns(nil, errExpected)
+		as.QueryReferencesCalls(queryReferencesErrorExceptBlocked(errExpected))
 
 		err := h.HandleActivity(context.Background(), nil, acceptInvite)
 		require.Error(t, err)
@@ -1721,6 +1902,136 @@ func TestHandler_HandleOfferActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_HandleOfferActivityAdmission(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service2IRI,
+		ServiceEndpointURL: service2IRI,
+	}
+
+	anchorLink := aptestutil.NewMockAnchorLink(t)
+
+	anchorLinksetDoc, err := vocab.MarshalToDoc(linkset.New(anchorLink))
+	require.NoError(t, err)
+
+	newOffer := func() *vocab.ActivityType {
+		startTime := time.Now()
+		endTime := startTime.Add(time.Hour)
+
+		return vocab.NewOfferActivity(
+			vocab.NewObjectProperty(vocab.WithDocument(anchorLinksetDoc)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+			vocab.WithStartTime(&startTime),
+			vocab.WithEndTime(&endTime),
+			vocab.WithTarget(vocab.NewObjectProperty(vocab.WithIRI(vocab.AnchorWitnessTargetIRI))),
+		)
+	}
+
+	t.Run("Admitted", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+		witness := servicemocks.NewWitnessHandler().WithProof([]byte(proof))
+		admission := &mockOfferAdmission{}
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithWitness(witness), spi.WithOfferAdmission(admission))
+
+		offer := newOffer()
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, offer))
+		require.Len(t, witness.AnchorCreds(), 1)
+		require.Len(t, ob.Activities().QueryByType(vocab.TypeAccept), 1)
+
+		require.Equal(t, offer.ID().String(), admission.offerID.String())
+		require.Equal(t, service1IRI.String(), admission.actor.String())
+		require.Equal(t, anchorLink.Author().String(), admission.origin.String())
+		require.Positive(t, admission.anchorSize)
+	})
+
+	t.Run("Admitted - verified actor", func(t *testing.T) {
+		verifiedActorIRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+		admission := &mockOfferAdmission{}
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewOutbox(),
+			servicemocks.NewActivitPubClient(),
+			spi.WithWitness(servicemocks.NewWitnessHandler().WithProof([]byte(proof))),
+			spi.WithOfferAdmission(admission))
+
+		ctx := spi.ContextWithVerifiedActor(context.Background(), verifiedActorIRI)
+
+		require.NoError(t, h.HandleActivity(ctx, nil, newOffer()))
+		require.Equal(t, verifiedActorIRI.String(), admission.actor.String())
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		ob := servicemocks.NewOutbox()
+		witness := servicemocks.NewWitnessHandler().WithProof([]byte(proof))
+		admission := &mockOfferAdmission{
+			err: spi.NewOfferRejectedError(spi.RejectReasonQuotaExceeded, "quota exceeded"),
+		}
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithWitness(witness), spi.WithOfferAdmission(admission))
+
+		offer := newOffer()
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, offer))
+		require.Empty(t, witness.AnchorCreds())
+		require.Empty(t, ob.Activities().QueryByType(vocab.TypeAccept))
+
+		rejects := ob.Activities().QueryByType(vocab.TypeReject)
+		require.Len(t, rejects, 1)
+
+		reject := rejects[0]
+		require.Equal(t, offer.ID().String(), reject.Object().Activity().ID().String())
+		require.Equal(t, service1IRI.String(), reject.To()[0].String())
+
+		result := reject.Result().Object()
+		require.NotNil(t, result)
+		require.True(t, result.Type().Is(vocab.TypeRejection))
+		require.Equal(t, spi.RejectReasonQuotaExceeded, result.Reason())
+		require.Equal(t, anchorLink.Anchor().String(), result.InReplyTo().String())
+	})
+
+	t.Run("Rejected - outbox error", func(t *testing.T) {
+		errExpected := errors.New("injected outbox error")
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewOutbox().WithError(errExpected),
+			servicemocks.NewActivitPubClient(),
+			spi.WithWitness(servicemocks.NewWitnessHandler()),
+			spi.WithOfferAdmission(&mockOfferAdmission{
+				err: spi.NewOfferRejectedError(spi.RejectReasonAnchorTooLarge, "anchor too large"),
+			}),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, newOffer())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errExpected))
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Admission error", func(t *testing.T) {
+		errExpected := errors.New("injected admission error")
+
+		ob := servicemocks.NewOutbox()
+
+		h := NewInbox(cfg, memstore.New(cfg.ServiceName), ob, servicemocks.NewActivitPubClient(),
+			spi.WithWitness(servicemocks.NewWitnessHandler()),
+			spi.WithOfferAdmission(&mockOfferAdmission{err: errExpected}),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, newOffer())
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errExpected))
+		require.Empty(t, ob.Activities())
+	})
+}
+
 //nolint:maintidx
 func TestHandler_HandleAcceptOfferActivity(t *testing.T) {
 	log.SetLevel("activitypub_service", log.WARNING)
@@ -2161,6 +2472,7 @@ func TestHandler_HandleUndoFollowActivity(t *testing.T) {
 
 		s := &servicemocks.ActivityStore{}
 		s.GetActivityReturns(nil, errExpected)
+		s.QueryReferencesReturns(memstore.NewReferenceIterator(nil, 0), nil)
 
 		ob := servicemocks.NewOutbox().WithError(errExpected)
 
@@ -3049,6 +3361,7 @@ func TestHandler_InboxHandleLikeActivity(t *testing.T) {
 
 		activityStore := &servicemocks.ActivityStore{}
 		activityStore.AddReferenceReturns(errExpected)
+		activityStore.QueryReferencesReturns(memstore.NewReferenceIterator(nil, 0), nil)
 
 		h := NewInbox(cfg, activityStore, ob,
 			servicemocks.NewActivitPubClient(),
@@ -3156,6 +3469,417 @@ func TestHandler_OutboxHandleLikeActivity(t *testing.T) {
 	})
 }
 
+func TestHandler_InboxHandleUpdateActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	oldKeyIRI := testutil.NewMockID(service2IRI, "/keys/main-key")
+	newKeyIRI := testutil.NewMockID(service2IRI, "/keys/rotated-key")
+
+	apClient := servicemocks.NewActivitPubClient().
+		WithActor(vocab.NewService(service2IRI,
+			vocab.WithPublicKey(vocab.NewPublicKey(vocab.WithID(oldKeyIRI), vocab.WithOwner(service2IRI))),
+		))
+
+	h := NewInbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewOutbox(), apClient)
+	require.NotNil(t, h)
+
+	h.Start()
+	defer h.Stop()
+
+	subscriber := newMockActivitySubscriber(h.Subscribe())
+	go subscriber.Listen()
+
+	t.Run("Success", func(t *testing.T) {
+		update := vocab.NewUpdateActivity(
+			vocab.NewObjectProperty(vocab.WithActorObject(vocab.NewService(service2IRI,
+				vocab.WithPublicKey(vocab.NewPublicKey(vocab.WithID(newKeyIRI), vocab.WithOwner(service2IRI))),
+			))),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+		)
+
+		ctx := spi.ContextWithVerifiedActor(context.Background(), service2IRI)
+
+		require.NoError(t, h.HandleActivity(ctx, nil, update))
+
+		time.Sleep(50 * time.Millisecond)
+
+		require.NotNil(t, subscriber.Activity(update.ID()))
+		require.Equal(t, []string{service2IRI.String()}, apClient.Stale())
+	})
+
+	t.Run("Actor not verified -> ignored", func(t *testing.T) {
+		staleCount := len(apClient.Stale())
+
+		newUpdate := func() *vocab.ActivityType {
+			return vocab.NewUpdateActivity(
+				vocab.NewObjectProperty(vocab.WithActorObject(vocab.NewService(service2IRI,
+					vocab.WithPublicKey(vocab.NewPublicKey(vocab.WithID(newKeyIRI), vocab.WithOwner(service2IRI))),
+				))),
+				vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+				vocab.WithActor(service2IRI),
+				vocab.WithTo(service1IRI),
+			)
+		}
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, newUpdate()))
+
+		ctx := spi.ContextWithVerifiedActor(context.Background(), service3IRI)
+
+		require.NoError(t, h.HandleActivity(ctx, nil, newUpdate()))
+
+		require.Len(t, apClient.Stale(), staleCount)
+	})
+
+	t.Run("No actor in object", func(t *testing.T) {
+		update := vocab.NewUpdateActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, update)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "no actor specified in 'object' field")
+	})
+
+	t.Run("Actor mismatch", func(t *testing.T) {
+		update := vocab.NewUpdateActivity(
+			vocab.NewObjectProperty(vocab.WithActorObject(vocab.NewService(service3IRI))),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, update)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "is not allowed to update actor")
+	})
+}
+
+func TestHandler_InboxHandleDeleteActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	as := memstore.New(cfg.ServiceName)
+
+	h := NewInbox(cfg, as, servicemocks.NewOutbox(), servicemocks.NewActivitPubClient(),
+		spi.WithUndoFollowHandler(servicemocks.NewUndoFollowHandler()))
+	require.NotNil(t, h)
+
+	h.Start()
+	defer h.Stop()
+
+	subscriber := newMockActivitySubscriber(h.Subscribe())
+	go subscriber.Listen()
+
+	t.Run("Success", func(t *testing.T) {
+		require.NoError(t, as.AddReference(store.Follower, service1IRI, service2IRI))
+		require.NoError(t, as.AddReference(store.Following, service1IRI, service2IRI))
+		require.NoError(t, as.AddReference(store.Witness, service1IRI, service2IRI))
+		require.NoError(t, as.AddReference(store.Follower, service1IRI, service3IRI))
+
+		del := vocab.NewDeleteActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(vocab.PublicIRI),
+		)
+
+		require.NoError(t, h.HandleActivity(context.Background(), nil, del))
+
+		time.Sleep(50 * time.Millisecond)
+
+		require.NotNil(t, subscriber.Activity(del.ID()))
+
+		for _, refType := range []store.ReferenceType{store.Follower, store.Following, store.Witness} {
+			exists, err := h.hasReference(service1IRI, service2IRI, refType)
+			require.NoError(t, err)
+			require.Falsef(t, exists, "expecting reference of type %s to be deleted", refType)
+		}
+
+		exists, err := h.hasReference(service1IRI, service3IRI, store.Follower)
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+
+	t.Run("Object is not the actor", func(t *testing.T) {
+		del := vocab.NewDeleteActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service3IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(vocab.PublicIRI),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, del)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "is not allowed to delete object")
+
+		exists, err := h.hasReference(service1IRI, service3IRI, store.Follower)
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+
+	t.Run("No object IRI", func(t *testing.T) {
+		del := vocab.NewDeleteActivity(
+			vocab.NewObjectProperty(),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(vocab.PublicIRI),
+		)
+
+		err := h.HandleActivity(context.Background(), nil, del)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		s := &servicemocks.ActivityStore{}
+		s.QueryReferencesReturns(memstore.NewReferenceIterator([]*url.URL{service2IRI}, 1), nil)
+		s.DeleteReferenceReturns(errExpected)
+
+		ib := NewInbox(cfg, s, servicemocks.NewOutbox(), servicemocks.NewActivitPubClient())
+		require.NotNil(t, ib)
+
+		del := vocab.NewDeleteActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(vocab.PublicIRI),
+		)
+
+		// The actor appears to be blocked since the mock store returns the reference for all queries,
+		// so call the handler directly.
+		err := ib.handleDeleteActivity(context.Background(), del)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestHandler_HandleBlockActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+	service3IRI := testutil.MustParseURL("http://localhost:8303/services/service3")
+
+	t.Run("Outbox block and undo", func(t *testing.T) {
+		cfg := &Config{
+			ServiceName:        "service1",
+			ServiceIRI:         service1IRI,
+			ServiceEndpointURL: service1IRI,
+		}
+
+		as := memstore.New(cfg.ServiceName)
+
+		ob := NewOutbox(cfg, as, servicemocks.NewActivitPubClient())
+		require.NotNil(t, ob)
+
+		ib := NewInbox(cfg, as, servicemocks.NewOutbox(), servicemocks.NewActivitPubClient())
+		require.NotNil(t, ib)
+
+		ob.Start()
+		defer ob.Stop()
+
+		ib.Start()
+		defer ib.Stop()
+
+		block := vocab.NewBlockActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+		)
+
+		require.NoError(t, as.AddActivity(block))
+		require.NoError(t, ob.HandleActivity(context.Background(), nil, block))
+
+		it, err := as.QueryReferences(store.Blocked, store.NewCriteria(store.WithObjectIRI(service1IRI)))
+		require.NoError(t, err)
+
+		refs, err := storeutil.ReadReferences(it, -1)
+		require.NoError(t, err)
+		require.True(t, containsIRI(refs, service2IRI))
+
+		follow := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+			vocab.WithActor(service2IRI),
+			vocab.WithTo(service1IRI),
+		)
+
+		// Activities from the blocked actor should be rejected.
+		err = ib.HandleActivity(context.Background(), nil, follow)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "is blocked")
+
+		undo := vocab.NewUndoActivity(
+			vocab.NewObjectProperty(vocab.WithActivity(block)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+		)
+
+		require.NoError(t, ob.HandleActivity(context.Background(), nil, undo))
+
+		blocked, err := ib.hasReference(service1IRI, service2IRI, store.Blocked)
+		require.NoError(t, err)
+		require.False(t, blocked)
+	})
+
+	t.Run("Outbox block validation", func(t *testing.T) {
+		cfg := &Config{
+			ServiceName:        "service1",
+			ServiceIRI:         service1IRI,
+			ServiceEndpointURL: service1IRI,
+		}
+
+		ob := NewOutbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewActivitPubClient())
+		require.NotNil(t, ob)
+
+		err := ob.HandleActivity(context.Background(), nil, vocab.NewBlockActivity(
+			vocab.NewObjectProperty(),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+		))
+		require.True(t, orberrors.IsBadRequest(err))
+
+		err = ob.HandleActivity(context.Background(), nil, vocab.NewBlockActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+		))
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "cannot block itself")
+	})
+
+	t.Run("Outbox block store error", func(t *testing.T) {
+		cfg := &Config{
+			ServiceName:        "service1",
+			ServiceIRI:         service1IRI,
+			ServiceEndpointURL: service1IRI,
+		}
+
+		errExpected := errors.New("injected store error")
+
+		s := &servicemocks.ActivityStore{}
+		s.AddReferenceReturns(errExpected)
+
+		ob := NewOutbox(cfg, s, servicemocks.NewActivitPubClient())
+		require.NotNil(t, ob)
+
+		err := ob.HandleActivity(context.Background(), nil, vocab.NewBlockActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+		))
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Inbox", func(t *testing.T) {
+		cfg := &Config{
+			ServiceName:        "service2",
+			ServiceIRI:         service2IRI,
+			ServiceEndpointURL: service2IRI,
+		}
+
+		as := memstore.New(cfg.ServiceName)
+
+		ib := NewInbox(cfg, as, servicemocks.NewOutbox(), servicemocks.NewActivitPubClient(),
+			spi.WithUndoFollowHandler(servicemocks.NewUndoFollowHandler()))
+		require.NotNil(t, ib)
+
+		ib.Start()
+		defer ib.Stop()
+
+		require.NoError(t, as.AddReference(store.Follower, service2IRI, service1IRI))
+
+		block := vocab.NewBlockActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service2IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+		)
+
+		require.NoError(t, ib.HandleActivity(context.Background(), nil, block))
+
+		exists, err := ib.hasReference(service2IRI, service1IRI, store.Follower)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		block = vocab.NewBlockActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(service3IRI)),
+			vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+			vocab.WithActor(service1IRI),
+			vocab.WithTo(service2IRI),
+		)
+
+		err = ib.HandleActivity(context.Background(), nil, block)
+		require.True(t, orberrors.IsBadRequest(err))
+		require.Contains(t, err.Error(), "this service is not the target object")
+	})
+}
+
+func TestHandler_OutboxHandleUpdateActivity(t *testing.T) {
+	service1IRI := testutil.MustParseURL("http://localhost:8301/services/service1")
+	service2IRI := testutil.MustParseURL("http://localhost:8302/services/service2")
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1IRI,
+		ServiceEndpointURL: service1IRI,
+	}
+
+	ob := NewOutbox(cfg, memstore.New(cfg.ServiceName), servicemocks.NewActivitPubClient())
+	require.NotNil(t, ob)
+
+	require.NoError(t, ob.HandleActivity(context.Background(), nil, vocab.NewUpdateActivity(
+		vocab.NewObjectProperty(vocab.WithActorObject(vocab.NewService(service1IRI))),
+		vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+		vocab.WithActor(service1IRI),
+		vocab.WithTo(vocab.PublicIRI),
+	)))
+
+	err := ob.HandleActivity(context.Background(), nil, vocab.NewUpdateActivity(
+		vocab.NewObjectProperty(vocab.WithActorObject(vocab.NewService(service2IRI))),
+		vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+		vocab.WithActor(service1IRI),
+		vocab.WithTo(vocab.PublicIRI),
+	))
+	require.True(t, orberrors.IsBadRequest(err))
+
+	err = ob.HandleActivity(context.Background(), nil, vocab.NewUpdateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+		vocab.WithID(aptestutil.NewActivityID(service1IRI)),
+		vocab.WithActor(service1IRI),
+		vocab.WithTo(vocab.PublicIRI),
+	))
+	require.True(t, orberrors.IsBadRequest(err))
+}
+
 type mockActivitySubscriber struct {
 	mutex        sync.RWMutex
 	activities   map[string]*vocab.ActivityType
@@ -3270,3 +3994,39 @@ func TestAcceptAllActorsAuth_AuthorizeActor(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ok)
 }
+
+func queryReferencesErrorExceptBlocked(err error) func(store.ReferenceType, *store.Criteria,
+	...store.QueryOpt) (store.ReferenceIterator, error) {
+	return func(refType store.ReferenceType, _ *store.Criteria, _ ...store.QueryOpt) (store.ReferenceIterator, error) {
+		if refType == store.Blocked {
+			return memstore.NewReferenceIterator(nil, 0), nil
+		}
+
+		return nil, err
+	}
+}
+
+type mockProofVerifier struct {
+	err error
+}
+
+func (m *mockProofVerifier) Verify(*vocab.ActivityType) error {
+	return m.err
+}
+
+type mockOfferAdmission struct {
+	err        error
+	offerID    *url.URL
+	actor      *url.URL
+	origin     *url.URL
+	anchorSize int
+}
+
+func (m *mockOfferAdmission) AdmitOffer(offerID, actor, origin *url.URL, anchorSize int) error {
+	m.offerID = offerID
+	m.actor = actor
+	m.origin = origin
+	m.anchorSize = anchorSize
+
+	return m.err
+}
@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/trustbloc/logutil-go/pkg/log"
@@ -76,6 +77,7 @@ func NewInbox(cfg *Config, s store.Store, outbox service.Outbox,
 			})
 		},
 		h.inboxUndoLike,
+		h.inboxUndoBlock,
 	)
 
 	return h
@@ -85,6 +87,22 @@ func NewInbox(cfg *Config, s store.Store, outbox service.Outbox,
 //
 //nolint:cyclop
 func (h *Inbox) HandleActivity(ctx context.Context, source *url.URL, activity *vocab.ActivityType) error {
+	if !h.hasLocalAddressee(activity) {
+		// The activity was delivered to a shared inbox on behalf of other actors on this domain.
+		h.logger.Debugc(ctx, "Ignoring activity since none of the addressees are local",
+			logfields.WithActivityID(activity.ID()), logfields.WithTargetIRIs(activity.To()...))
+
+		return nil
+	}
+
+	if err := h.ensureActorNotBlocked(activity); err != nil {
+		return err
+	}
+
+	if err := h.verifyProof(activity); err != nil {
+		return err
+	}
+
 	typeProp := activity.Type()
 
 	spanCtx, span := h.tracer.Start(ctx, fmt.Sprintf("inbox handle %s activity", typeProp),
@@ -115,11 +133,86 @@ func (h *Inbox) HandleActivity(ctx context.Context, source *url.URL, activity *v
 		return h.handleLikeActivity(activity)
 	case typeProp.Is(vocab.TypeUndo):
 		return h.handleUndoActivity(spanCtx, activity)
+	case typeProp.Is(vocab.TypeUpdate):
+		return h.handleUpdateActivity(spanCtx, activity)
+	case typeProp.Is(vocab.TypeDelete):
+		return h.handleDeleteActivity(spanCtx, activity)
+	case typeProp.Is(vocab.TypeBlock):
+		return h.handleBlockActivity(spanCtx, activity)
 	default:
 		return fmt.Errorf("unsupported activity type: %s", typeProp.Types())
 	}
 }
 
+// ensureActorNotBlocked returns an error if the actor of the given activity is in the 'Blocked' collection.
+func (h *Inbox) ensureActorNotBlocked(activity *vocab.ActivityType) error {
+	actorIRI := activity.Actor()
+	if actorIRI == nil {
+		return nil
+	}
+
+	blocked, err := h.hasReference(h.ServiceIRI, actorIRI, store.Blocked)
+	if err != nil {
+		return fmt.Errorf("check if actor [%s] is blocked: %w", actorIRI, err)
+	}
+
+	if blocked {
+		h.logger.Info("Rejecting activity from blocked actor", logfields.WithActorIRI(actorIRI),
+			logfields.WithActivityID(activity.ID()))
+
+		return orberrors.NewBadRequestf("actor [%s] is blocked", actorIRI)
+	}
+
+	return nil
+}
+
+// verifyProof verifies the Linked Data proof embedded in the activity (if a proof verifier is configured).
+func (h *Inbox) verifyProof(activity *vocab.ActivityType) error {
+	if h.ProofVerifier == nil {
+		return nil
+	}
+
+	if err := h.ProofVerifier.Verify(activity); err != nil {
+		h.logger.Info("Rejecting activity with invalid proof", logfields.WithActorIRI(activity.Actor()),
+			logfields.WithActivityID(activity.ID()), log.WithError(err))
+
+		return fmt.Errorf("verify activity proof: %w", err)
+	}
+
+	return nil
+}
+
+// hasLocalAddressee returns true if the activity should be handled on behalf of the local service. An activity
+// that is delivered to a shared inbox is fanned out to the local addressees, i.e. the activity is handled
+// only if it is addressed to the local service (or to a resource of the local service), to the public, or to
+// a collection (such as 'followers') hosted on another domain. Activities addressed exclusively to other actors
+// on this domain are ignored.
+func (h *Inbox) hasLocalAddressee(activity *vocab.ActivityType) bool {
+	to := activity.To()
+
+	if len(to) == 0 {
+		return true
+	}
+
+	for _, iri := range to {
+		if iri.String() == vocab.PublicIRI.String() ||
+			iri.String() == h.ServiceIRI.String() ||
+			iri.Host != h.ServiceEndpointURL.Host ||
+			isLocalEndpoint(iri, h.ServiceEndpointURL) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLocalEndpoint returns true if the given IRI is the service endpoint or a path below it.
+func isLocalEndpoint(iri, serviceEndpoint *url.URL) bool {
+	endpoint := strings.TrimSuffix(serviceEndpoint.String(), "/")
+
+	return iri.String() == endpoint || strings.HasPrefix(iri.String(), endpoint+"/")
+}
+
 // HandleCreateActivity handles a 'Create' ActivityPub activity.
 func (h *Inbox) HandleCreateActivity(ctx context.Context, source *url.URL, create *vocab.ActivityType, announce bool) error {
 	h.logger.Debugc(ctx, "Handling 'Create' activity", logfields.WithActivityID(create.ID()))
@@ -366,6 +459,12 @@ func (h *Inbox) handleRejectActivity(reject *vocab.ActivityType) error {
 		return err
 	}
 
+	if reject.Object().Activity().Type().Is(vocab.TypeOffer) {
+		h.logger.Warn("'Offer' activity was rejected by witness", logfields.WithActorIRI(reject.Actor()),
+			logfields.WithActivityID(reject.Object().Activity().ID()),
+			logfields.WithReason(reject.Result().Object().Reason()))
+	}
+
 	h.notify(reject)
 
 	return nil
@@ -521,6 +620,24 @@ func (h *Inbox) handleOfferActivity(ctx context.Context, offer *vocab.ActivityTy
 		return fmt.Errorf("validate 'Offer' activity [%s]: %w", offer.ID(), err)
 	}
 
+	// Create a new offer activity with only the bare essentials to return in the 'Accept' or 'Reject'.
+	oa := vocab.NewOfferActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(anchorLink.Anchor())),
+		vocab.WithID(offer.ID().URL()),
+		vocab.WithActor(offer.Actor()),
+		vocab.WithTo(offer.To()...),
+		vocab.WithTarget(offer.Target()),
+	)
+
+	if err = h.admitOffer(ctx, offer, anchorLink); err != nil {
+		rejectedErr := &service.OfferRejectedError{}
+		if errors.As(err, &rejectedErr) {
+			return h.postOfferReject(ctx, oa, anchorLink.Anchor(), rejectedErr.Reason)
+		}
+
+		return fmt.Errorf("admit 'Offer' activity [%s]: %w", offer.ID(), err)
+	}
+
 	vcBytes, err := anchorLink.Replies().Content()
 	if err != nil {
 		return fmt.Errorf("get content from 'replies' of anchor Linkset: %w", err)
@@ -534,15 +651,6 @@ func (h *Inbox) handleOfferActivity(ctx context.Context, offer *vocab.ActivityTy
 	startTime := time.Now()
 	endTime := startTime.Add(h.MaxWitnessDelay)
 
-	// Create a new offer activity with only the bare essentials to return in the 'Accept'.
-	oa := vocab.NewOfferActivity(
-		vocab.NewObjectProperty(vocab.WithIRI(anchorLink.Anchor())),
-		vocab.WithID(offer.ID().URL()),
-		vocab.WithActor(offer.Actor()),
-		vocab.WithTo(offer.To()...),
-		vocab.WithTarget(offer.Target()),
-	)
-
 	accept := vocab.NewAcceptActivity(
 		vocab.NewObjectProperty(vocab.WithActivity(oa)),
 		vocab.WithTo(oa.Actor(), vocab.PublicIRI),
@@ -570,6 +678,60 @@ func (h *Inbox) handleOfferActivity(ctx context.Context, offer *vocab.ActivityTy
 	return nil
 }
 
+// admitOffer returns an error if the offer is not admitted by the (optional) offer admission controller. The
+// anchor origin is the author of the anchor or, if not specified, the actor of the offer. The allow list and quotas
+// are enforced on the actor that was verified by the HTTP signature (or, if the signature wasn't verified, the actor
+// of the offer) since the anchor origin is self-declared.
+func (h *Inbox) admitOffer(ctx context.Context, offer *vocab.ActivityType, anchorLink *linkset.Link) error {
+	if h.OfferAdmission == nil {
+		return nil
+	}
+
+	actor := service.VerifiedActorFromContext(ctx)
+	if actor == nil {
+		actor = offer.Actor()
+	}
+
+	origin := anchorLink.Author()
+	if origin == nil {
+		origin = offer.Actor()
+	}
+
+	anchorBytes, err := json.Marshal(offer.Object().Document())
+	if err != nil {
+		return fmt.Errorf("marshal anchor Linkset: %w", err)
+	}
+
+	return h.OfferAdmission.AdmitOffer(offer.ID().URL(), actor, origin, len(anchorBytes))
+}
+
+// postOfferReject replies to the actor of the offer with a 'Reject' activity whose result contains the
+// machine-readable reason for the rejection.
+func (h *Inbox) postOfferReject(ctx context.Context, offer *vocab.ActivityType, anchor *url.URL,
+	reason service.RejectReason,
+) error {
+	rejection, err := vocab.NewRejectionObject(reason, vocab.WithInReplyTo(anchor))
+	if err != nil {
+		return fmt.Errorf("create rejection for 'Offer' activity [%s]: %w", offer.ID(), err)
+	}
+
+	reject := vocab.NewRejectActivity(
+		vocab.NewObjectProperty(vocab.WithActivity(offer)),
+		vocab.WithTo(offer.Actor()),
+		vocab.WithResult(vocab.NewObjectProperty(vocab.WithObject(rejection))),
+	)
+
+	h.logger.Info("Rejecting 'Offer' activity", logfields.WithActivityID(offer.ID()),
+		logfields.WithActorIRI(offer.Actor()), logfields.WithAnchorURI(anchor), logfields.WithReason(reason))
+
+	if _, err = h.outbox.Post(ctx, reject); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("unable to reply with 'Reject' to %s for offer [%s]: %w",
+			offer.Actor(), offer.ID(), err))
+	}
+
+	return nil
+}
+
 func (h *Inbox) handleAcceptOfferActivity(ctx context.Context, accept, offer *vocab.ActivityType) error {
 	h.logger.Info("Handling 'Accept' offer activity", logfields.WithActivityID(accept.ID()),
 		logfields.WithActorIRI(accept.Actor()))
@@ -734,6 +896,126 @@ func (h *Inbox) handleAnnounceCollection(ctx context.Context, source *url.URL, a
 	return len(anchorURIs), nil
 }
 
+// handleUpdateActivity handles an 'Update' activity which is sent by an actor after it has been updated, for example,
+// after a key rotation. The actor embedded in the activity is not trusted. Instead, if the actor of the activity was
+// authenticated by its HTTP signature, the cached actor (and its public key) is marked as stale so that it is
+// reloaded from its origin.
+func (h *Inbox) handleUpdateActivity(ctx context.Context, update *vocab.ActivityType) error {
+	h.logger.Debugc(ctx, "Handling 'Update' activity", logfields.WithActivityID(update.ID()))
+
+	if update.Actor() == nil {
+		return orberrors.NewBadRequestf("no actor specified in 'Update' activity")
+	}
+
+	actor := update.Object().Actor()
+	if actor == nil || actor.ID() == nil {
+		return orberrors.NewBadRequestf("no actor specified in 'object' field of the 'Update' activity")
+	}
+
+	if actor.ID().String() != update.Actor().String() {
+		return orberrors.NewBadRequestf("actor [%s] is not allowed to update actor [%s]", update.Actor(), actor.ID())
+	}
+
+	verifiedActor := service.VerifiedActorFromContext(ctx)
+	if verifiedActor == nil || verifiedActor.String() != update.Actor().String() {
+		h.logger.Infoc(ctx, "Ignoring 'Update' activity since the actor was not verified by an HTTP signature",
+			logfields.WithActivityID(update.ID()), logfields.WithActorIRI(update.Actor()))
+
+		return nil
+	}
+
+	h.logger.Debugc(ctx, "Marking updated actor as stale", logfields.WithActorIRI(verifiedActor))
+
+	h.client.MarkActorAsStale(verifiedActor)
+
+	h.notify(update)
+
+	return nil
+}
+
+// handleDeleteActivity handles a 'Delete' activity which is sent by an actor that is being retracted. All
+// references to the actor (follower, following, witness, witnessing) are removed.
+func (h *Inbox) handleDeleteActivity(ctx context.Context, del *vocab.ActivityType) error {
+	h.logger.Debugc(ctx, "Handling 'Delete' activity", logfields.WithActivityID(del.ID()))
+
+	actorIRI := del.Actor()
+	if actorIRI == nil {
+		return orberrors.NewBadRequestf("no actor specified in 'Delete' activity")
+	}
+
+	objectIRI := del.Object().IRI()
+	if objectIRI == nil {
+		return orberrors.NewBadRequestf("no IRI specified in 'object' field of the 'Delete' activity")
+	}
+
+	// Only the deletion of the actor itself is supported.
+	if objectIRI.String() != actorIRI.String() {
+		return orberrors.NewBadRequestf("actor [%s] is not allowed to delete object [%s]", actorIRI, objectIRI)
+	}
+
+	err := h.removeActorReferences(actorIRI, store.Follower, store.Following, store.Witness, store.Witnessing)
+	if err != nil {
+		return fmt.Errorf("remove references to deleted actor [%s]: %w", actorIRI, err)
+	}
+
+	h.notify(del)
+
+	return nil
+}
+
+// handleBlockActivity handles a 'Block' activity which is sent by an actor that has blocked this service. The actor
+// is removed from the 'Followers' collection so that activities are no longer sent to it.
+func (h *Inbox) handleBlockActivity(ctx context.Context, block *vocab.ActivityType) error {
+	h.logger.Debugc(ctx, "Handling 'Block' activity", logfields.WithActivityID(block.ID()))
+
+	err := h.validateActivity(block, func() *url.URL {
+		return block.Object().IRI()
+	})
+	if err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("validate 'Block' activity [%s]: %w", block.ID(), err))
+	}
+
+	err = h.removeActorReferences(block.Actor(), store.Follower)
+	if err != nil {
+		return fmt.Errorf("remove references to blocking actor [%s]: %w", block.Actor(), err)
+	}
+
+	h.notify(block)
+
+	return nil
+}
+
+func (h *Inbox) removeActorReferences(actorIRI *url.URL, refTypes ...store.ReferenceType) error {
+	for _, refType := range refTypes {
+		exists, err := h.hasReference(h.ServiceIRI, actorIRI, refType)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			continue
+		}
+
+		err = h.store.DeleteReference(refType, h.ServiceIRI, actorIRI)
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("unable to delete %s from %s's collection of %s: %w",
+				actorIRI, h.ServiceIRI, refType, err))
+		}
+
+		h.logger.Debug("Reference was successfully deleted", logfields.WithActorIRI(actorIRI),
+			logfields.WithServiceIRI(h.ServiceIRI), logfields.WithReferenceType(string(refType)))
+
+		if refType == store.Follower {
+			err = h.UndoFollowHandler.Undo(actorIRI)
+			if err != nil {
+				return fmt.Errorf("undo follow for actor %s: %w", actorIRI, err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (h *Inbox) handleLikeActivity(like *vocab.ActivityType) error {
 	h.logger.Debug("Handling 'Like' activity", logfields.WithActivityID(like.ID()))
 
@@ -1035,6 +1317,15 @@ func (h *Inbox) inboxUndoLike(like *vocab.ActivityType) error {
 	return nil
 }
 
+// inboxUndoBlock handles the 'Undo' of a 'Block' activity. Nothing needs to be undone since the
+// actor that unblocked this service may simply be followed again.
+func (h *Inbox) inboxUndoBlock(block *vocab.ActivityType) error {
+	h.logger.Debug("Actor has unblocked this service", logfields.WithActorIRI(block.Actor()),
+		logfields.WithActivityID(block.ID()))
+
+	return nil
+}
+
 func (h *Inbox) ensureActivityInOutbox(activity *vocab.ActivityType) (*vocab.ActivityType, error) {
 	obActivity, err := h.getActivityFromOutbox(activity.ID().URL())
 	if err != nil {
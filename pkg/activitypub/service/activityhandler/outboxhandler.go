@@ -51,6 +51,11 @@ func NewOutbox(cfg *Config, s store.Store, activityPubClient activityPubClient)
 				return activity.ID().URL()
 			})
 		},
+		func(activity *vocab.ActivityType) error {
+			return h.undoAddReference(activity, store.Blocked, func() *url.URL {
+				return activity.Object().IRI()
+			})
+		},
 	)
 
 	return h
@@ -74,6 +79,10 @@ func (h *Outbox) HandleActivity(ctx context.Context, source *url.URL, activity *
 		return h.handleUndoActivity(spanCtx, activity)
 	case typeProp.Is(vocab.TypeLike):
 		return h.handleLikeActivity(spanCtx, activity)
+	case typeProp.Is(vocab.TypeUpdate):
+		return h.handleUpdateActivity(spanCtx, activity)
+	case typeProp.Is(vocab.TypeBlock):
+		return h.handleBlockActivity(spanCtx, activity)
 	default:
 		// Nothing to do for activity.
 		return nil
@@ -143,6 +152,43 @@ func (h *Outbox) undoAddReference(activity *vocab.ActivityType, refType store.Re
 	return nil
 }
 
+func (h *Outbox) handleUpdateActivity(ctx context.Context, update *vocab.ActivityType) error {
+	h.logger.Debugc(ctx, "Handling 'Update' activity", logfields.WithActivityID(update.ID()))
+
+	actor := update.Object().Actor()
+	if actor == nil || actor.ID() == nil {
+		return orberrors.NewBadRequestf("no actor specified in 'object' field of the 'Update' activity")
+	}
+
+	// Only updates to the local service are supported.
+	if actor.ID().String() != h.ServiceIRI.String() {
+		return orberrors.NewBadRequestf("this service is not the object of the 'Update' activity: %s", actor.ID())
+	}
+
+	return nil
+}
+
+func (h *Outbox) handleBlockActivity(ctx context.Context, block *vocab.ActivityType) error {
+	h.logger.Debugc(ctx, "Handling 'Block' activity", logfields.WithActivityID(block.ID()))
+
+	actorIRI := block.Object().IRI()
+	if actorIRI == nil {
+		return orberrors.NewBadRequestf("no IRI specified in 'object' field of the 'Block' activity")
+	}
+
+	if actorIRI.String() == h.ServiceIRI.String() {
+		return orberrors.NewBadRequestf("this service cannot block itself")
+	}
+
+	h.logger.Debugc(ctx, "Adding actor to the 'Blocked' collection", logfields.WithActorIRI(actorIRI))
+
+	if err := h.store.AddReference(store.Blocked, h.ServiceIRI, actorIRI); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("add actor to 'Blocked' collection: %w", err))
+	}
+
+	return nil
+}
+
 func (h *handler) handleLikeActivity(_ context.Context, like *vocab.ActivityType) error {
 	h.logger.Debug("Handling 'Like' activity", logfields.WithActivityID(like.ID()))
 
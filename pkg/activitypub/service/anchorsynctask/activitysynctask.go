@@ -24,6 +24,7 @@ import (
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/observability/tracing"
 	store2 "github.com/trustbloc/orb/pkg/store"
 )
@@ -53,6 +54,16 @@ type activityPubClient interface {
 	GetActivities(ctx context.Context, iri *url.URL, order client.Order) (client.ActivityIterator, error)
 }
 
+type peerHealthRegistry interface {
+	Allow(iri *url.URL) bool
+	RecordSuccess(iri *url.URL, latency time.Duration)
+	RecordFailure(iri *url.URL, err error)
+}
+
+type proofVerifier interface {
+	Verify(activity *vocab.ActivityType) error
+}
+
 type taskManager interface {
 	RegisterTaskEx(taskType string, interval time.Duration, task func() time.Duration)
 }
@@ -66,12 +77,25 @@ type Config struct {
 	MaxActivitiesToSync int
 }
 
+// Opt is an activity-sync task option.
+type Opt func(t *task)
+
+// WithProofVerifier sets the verifier of the Linked Data proof embedded in synced activities. Activities
+// with an invalid proof are skipped. If not set then activity proofs are not verified.
+func WithProofVerifier(v proofVerifier) Opt {
+	return func(t *task) {
+		t.proofVerifier = v
+	}
+}
+
 type task struct {
 	serviceIRI          *url.URL
 	apClient            activityPubClient
 	store               *syncStore
 	getHandler          func() spi.InboxHandler
 	activityPubStore    store.Store
+	peerHealth          peerHealthRegistry
+	proofVerifier       proofVerifier
 	closed              chan struct{}
 	minActivityAge      time.Duration
 	maxActivitiesToSync int
@@ -79,17 +103,23 @@ type task struct {
 	tracer              trace.Tracer
 }
 
-// Register registers the anchor event synchronization task.
+// Register registers the anchor event synchronization task. Services whose circuit is open in the given peer
+// health registry are skipped until the circuit is half-open again.
 func Register(cfg Config, taskMgr taskManager, apClient activityPubClient, apStore store.Store,
-	storageProvider storage.Provider, handlerFactory func() spi.InboxHandler,
+	storageProvider storage.Provider, peerHealth peerHealthRegistry, handlerFactory func() spi.InboxHandler,
+	opts ...Opt,
 ) error {
 	config := resolveConfig(&cfg)
 
-	t, err := newTask(config, apClient, apStore, storageProvider, handlerFactory)
+	t, err := newTask(config, apClient, apStore, storageProvider, peerHealth, handlerFactory)
 	if err != nil {
 		return fmt.Errorf("create task: %w", err)
 	}
 
+	for _, opt := range opts {
+		opt(t)
+	}
+
 	logger.Info("Registering activity-sync task.",
 		logfields.WithServiceIRI(config.ServiceIRI), logfields.WithTaskMonitorInterval(config.Interval),
 		logfields.WithMinAge(config.MinActivityAge), logfields.WithMaxActivitiesToSync(config.MaxActivitiesToSync))
@@ -100,7 +130,7 @@ func Register(cfg Config, taskMgr taskManager, apClient activityPubClient, apSto
 }
 
 func newTask(cfg *Config, apClient activityPubClient, apStore store.Store,
-	storageProvider storage.Provider, handlerFactory func() spi.InboxHandler,
+	storageProvider storage.Provider, peerHealth peerHealthRegistry, handlerFactory func() spi.InboxHandler,
 ) (*task, error) {
 	s, err := newSyncStore(storageProvider)
 	if err != nil {
@@ -112,6 +142,7 @@ func newTask(cfg *Config, apClient activityPubClient, apStore store.Store,
 		apClient:            apClient,
 		store:               s,
 		activityPubStore:    apStore,
+		peerHealth:          peerHealth,
 		getHandler:          handlerFactory,
 		minActivityAge:      cfg.MinActivityAge,
 		maxActivitiesToSync: cfg.MaxActivitiesToSync,
@@ -171,6 +202,13 @@ func (m *task) syncFollowers(maxActivitiesToSync int) (int, error) {
 	var numProcessed int
 
 	for _, serviceIRI := range followers {
+		if !m.peerHealth.Allow(serviceIRI) {
+			logger.Debug("Not synchronizing activities from inbox of service since its circuit is open",
+				logfields.WithServiceIRI(serviceIRI))
+
+			continue
+		}
+
 		num, err := m.sync(serviceIRI, inbox, maxActivitiesToSync-numProcessed, func(a *vocab.ActivityType) bool {
 			// Only sync Create activities that were originated by this service.
 			return a.Type().Is(vocab.TypeCreate) && a.Actor().String() == m.serviceIRI.String()
@@ -206,6 +244,13 @@ func (m *task) syncFollowing(maxActivitiesToSync int) (int, error) {
 	var numProcessed int
 
 	for _, serviceIRI := range following {
+		if !m.peerHealth.Allow(serviceIRI) {
+			logger.Debug("Not synchronizing activities from outbox of service since its circuit is open",
+				logfields.WithServiceIRI(serviceIRI))
+
+			continue
+		}
+
 		num, err := m.sync(serviceIRI, outbox, maxActivitiesToSync-numProcessed, func(a *vocab.ActivityType) bool {
 			return a.Type().IsAny(vocab.TypeCreate, vocab.TypeAnnounce)
 		})
@@ -231,11 +276,17 @@ func (m *task) syncFollowing(maxActivitiesToSync int) (int, error) {
 func (m *task) sync(serviceIRI *url.URL, src activitySource, maxNumActivitiesToProcess int,
 	shouldSync func(*vocab.ActivityType) bool,
 ) (int, error) {
+	start := time.Now()
+
 	it, lastSyncedPage, lastSyncedIndex, err := m.getNewActivities(serviceIRI, src)
 	if err != nil {
+		m.peerHealth.RecordFailure(serviceIRI, err)
+
 		return 0, fmt.Errorf("get new activities: %w", err)
 	}
 
+	m.peerHealth.RecordSuccess(serviceIRI, time.Since(start))
+
 	page, index := lastSyncedPage, lastSyncedIndex
 
 	var numProcessed int
@@ -252,6 +303,8 @@ func (m *task) sync(serviceIRI *url.URL, src activitySource, maxNumActivitiesToP
 				break
 			}
 
+			m.peerHealth.RecordFailure(serviceIRI, e)
+
 			return numProcessed, fmt.Errorf("next activity: %w", e)
 		}
 
@@ -331,6 +384,19 @@ func (m *task) syncActivity(ctx context.Context, serviceIRI, currentPage *url.UR
 		return 0, nil
 	}
 
+	if m.proofVerifier != nil {
+		if e := m.proofVerifier.Verify(a); e != nil {
+			if orberrors.IsTransient(e) {
+				return 0, fmt.Errorf("verify proof of activity [%s]: %w", a.ID(), e)
+			}
+
+			logger.Warn("Ignoring activity since its proof could not be verified.", logfields.WithActivityID(a.ID()),
+				logfields.WithActivityType(a.Type().String()), logfields.WithURL(currentPage), log.WithError(e))
+
+			return 0, nil
+		}
+	}
+
 	logger.Debug("Processing activity.", logfields.WithActivityID(a.ID()), logfields.WithActivityType(a.Type().String()),
 		logfields.WithURL(currentPage))
 
@@ -20,12 +20,14 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	mocks2 "github.com/trustbloc/orb/pkg/activitypub/store/mocks"
 	spi2 "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/internal/aptestutil"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 )
@@ -37,7 +39,7 @@ func TestRegister(t *testing.T) {
 		require.NoError(t, Register(
 			Config{},
 			mocks.NewTaskManager("anchor-sync"), &mocks.ActivityPubClient{},
-			memstore.New("service1"), storage.NewMockStoreProvider(),
+			memstore.New("service1"), storage.NewMockStoreProvider(), peerhealth.New(peerhealth.Config{}),
 			func() spi.InboxHandler {
 				return nil
 			},
@@ -54,7 +56,7 @@ func TestRegister(t *testing.T) {
 		err := Register(
 			Config{},
 			mocks.NewTaskManager("anchor-sync"), &mocks.ActivityPubClient{},
-			memstore.New("service1"), p,
+			memstore.New("service1"), p, peerhealth.New(peerhealth.Config{}),
 			func() spi.InboxHandler {
 				return nil
 			},
@@ -114,7 +116,7 @@ func TestRun(t *testing.T) {
 		handler.duplicateAnchors = append(handler.duplicateAnchors, announceActivities[1], createActivities[1])
 
 		task, err := newTask(
-			cfg, apClient, apStore, storage.NewMockStoreProvider(),
+			cfg, apClient, apStore, storage.NewMockStoreProvider(), peerhealth.New(peerhealth.Config{}),
 			func() spi.InboxHandler {
 				return handler
 			},
@@ -150,7 +152,7 @@ func TestRun(t *testing.T) {
 		handler := &mockHandler{}
 
 		task, err := newTask(
-			cfg, apClient, s, storage.NewMockStoreProvider(),
+			cfg, apClient, s, storage.NewMockStoreProvider(), peerhealth.New(peerhealth.Config{}),
 			func() spi.InboxHandler {
 				return handler
 			},
@@ -180,7 +182,7 @@ func TestRun(t *testing.T) {
 		handler := &mockHandler{}
 
 		task, err := newTask(
-			cfg, apClient, s, storage.NewMockStoreProvider(),
+			cfg, apClient, s, storage.NewMockStoreProvider(), peerhealth.New(peerhealth.Config{}),
 			func() spi.InboxHandler {
 				return handler
 			},
@@ -206,7 +208,7 @@ func TestRun(t *testing.T) {
 		handler := &mockHandler{}
 
 		task, err := newTask(
-			cfg, apClient, apStore, storage.NewMockStoreProvider(),
+			cfg, apClient, apStore, storage.NewMockStoreProvider(), peerhealth.New(peerhealth.Config{}),
 			func() spi.InboxHandler {
 				return handler
 			},
@@ -218,6 +220,95 @@ func TestRun(t *testing.T) {
 
 		require.Empty(t, handler.activities)
 	})
+
+	t.Run("Circuit open", func(t *testing.T) {
+		cfg := resolveConfig(&Config{
+			ServiceIRI:     serviceIRI,
+			MinActivityAge: time.Nanosecond,
+		})
+
+		peerHealth := peerhealth.New(peerhealth.Config{FailureThreshold: 1, InitialBackoff: time.Minute})
+
+		handler := &mockHandler{}
+
+		task, err := newTask(
+			cfg, mocks.NewActivitPubClient().WithError(errors.New("injected client error")), apStore,
+			storage.NewMockStoreProvider(), peerHealth,
+			func() spi.InboxHandler {
+				return handler
+			},
+		)
+		require.NoError(t, err)
+
+		task.run()
+
+		require.Empty(t, handler.activities)
+		require.False(t, peerHealth.IsAvailable(service2IRI))
+
+		// The service should be skipped, even though the client no longer returns an error.
+		task.apClient = apClient
+
+		task.run()
+
+		require.Empty(t, handler.activities)
+	})
+}
+
+func TestSyncActivity_ProofVerifier(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://domain1.com/services/orb")
+	service2IRI := testutil.MustParseURL("https://domain2.com/services/orb")
+
+	cfg := resolveConfig(&Config{ServiceIRI: serviceIRI})
+
+	newTestTask := func(t *testing.T, handler *mockHandler, verifier *mockProofVerifier) *task {
+		t.Helper()
+
+		tsk, err := newTask(
+			cfg, mocks.NewActivitPubClient(), memstore.New("service1"), storage.NewMockStoreProvider(),
+			peerhealth.New(peerhealth.Config{}),
+			func() spi.InboxHandler {
+				return handler
+			},
+		)
+		require.NoError(t, err)
+
+		WithProofVerifier(verifier)(tsk)
+
+		return tsk
+	}
+
+	t.Run("Valid proof", func(t *testing.T) {
+		handler := &mockHandler{}
+
+		tsk := newTestTask(t, handler, &mockProofVerifier{})
+
+		n, err := tsk.syncActivity(context.Background(), service2IRI, service2IRI, aptestutil.NewMockCreateActivities(1)[0])
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.Len(t, handler.activities, 1)
+	})
+
+	t.Run("Invalid proof -> skipped", func(t *testing.T) {
+		handler := &mockHandler{}
+
+		tsk := newTestTask(t, handler, &mockProofVerifier{err: orberrors.NewBadRequest(errors.New("invalid proof"))})
+
+		n, err := tsk.syncActivity(context.Background(), service2IRI, service2IRI, aptestutil.NewMockCreateActivities(1)[0])
+		require.NoError(t, err)
+		require.Equal(t, 0, n)
+		require.Empty(t, handler.activities)
+	})
+
+	t.Run("Transient error", func(t *testing.T) {
+		handler := &mockHandler{}
+
+		tsk := newTestTask(t, handler, &mockProofVerifier{err: orberrors.NewTransient(errors.New("injected error"))})
+
+		_, err := tsk.syncActivity(context.Background(), service2IRI, service2IRI, aptestutil.NewMockCreateActivities(1)[0])
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Empty(t, handler.activities)
+	})
 }
 
 func getPublicKeyPem(pubKey interface{}) ([]byte, error) {
@@ -276,3 +367,11 @@ func (m *mockHandler) exists(activity *vocab.ActivityType) bool {
 
 	return false
 }
+
+type mockProofVerifier struct {
+	err error
+}
+
+func (m *mockProofVerifier) Verify(*vocab.ActivityType) error {
+	return m.err
+}
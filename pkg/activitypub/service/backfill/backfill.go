@@ -0,0 +1,431 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("activity_backfill")
+
+const (
+	taskName = "activity-backfill"
+
+	defaultInterval            = 30 * time.Second
+	defaultAcceleratedInterval = time.Second
+	defaultMaxActivitiesPerRun = 500
+	defaultMaxFailedAttempts   = 10
+)
+
+type activityPubClient interface {
+	GetActor(iri *url.URL) (*vocab.ActorType, error)
+	GetActivities(ctx context.Context, iri *url.URL, order client.Order) (client.ActivityIterator, error)
+}
+
+type taskManager interface {
+	RegisterTaskEx(taskType string, interval time.Duration, task func() time.Duration)
+}
+
+type proofVerifier interface {
+	Verify(activity *vocab.ActivityType) error
+}
+
+// Config contains configuration parameters for the backfill task.
+type Config struct {
+	// Interval is the interval at which the task checks for pending backfill jobs.
+	Interval time.Duration
+	// AcceleratedInterval is the interval at which the task runs while there are pending jobs.
+	AcceleratedInterval time.Duration
+	// MaxActivitiesPerRun is the maximum number of activities that are read from remote outboxes in a single run.
+	MaxActivitiesPerRun int
+	// MaxFailedAttempts is the number of consecutive failed runs after which a job is marked as failed.
+	MaxFailedAttempts int
+}
+
+// Opt is a backfiller option.
+type Opt func(b *Backfiller)
+
+// WithProofVerifier sets the verifier of the Linked Data proof embedded in backfilled activities. Activities
+// with an invalid proof are skipped. If not set then activity proofs are not verified.
+func WithProofVerifier(v proofVerifier) Opt {
+	return func(b *Backfiller) {
+		b.proofVerifier = v
+	}
+}
+
+// Backfiller imports the complete anchor history of remote services by walking their outboxes from the
+// beginning. The Create and Announce activities are processed by the inbox handler, just as if they were
+// posted to the inbox. Jobs are processed by a task (so that only one server instance processes them) in
+// bounded runs, and the position in the remote outbox is checkpointed after each page so that a job resumes
+// where it left off after a restart.
+type Backfiller struct {
+	*Config
+
+	apClient         activityPubClient
+	activityPubStore store.Store
+	store            *jobStore
+	getHandler       func() spi.InboxHandler
+	proofVerifier    proofVerifier
+	now              func() time.Time
+}
+
+// New returns a new backfiller and registers its task with the task manager.
+func New(cfg Config, taskMgr taskManager, apClient activityPubClient, apStore store.Store,
+	storageProvider storage.Provider, handlerFactory func() spi.InboxHandler, opts ...Opt,
+) (*Backfiller, error) {
+	s, err := newJobStore(storageProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backfiller{
+		Config:           resolveConfig(&cfg),
+		apClient:         apClient,
+		activityPubStore: apStore,
+		store:            s,
+		getHandler:       handlerFactory,
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	logger.Info("Registering activity-backfill task.", logfields.WithTaskMonitorInterval(b.Interval),
+		logfields.WithMaxActivitiesToSync(b.MaxActivitiesPerRun))
+
+	taskMgr.RegisterTaskEx(taskName, b.Interval, b.run)
+
+	return b, nil
+}
+
+// Start starts a backfill from the outbox of the given service. If a job for the service is already pending then
+// the existing job is returned. A job that has failed resumes from its last checkpoint and a job that has completed
+// is restarted from the beginning (activities that were already processed are skipped).
+func (b *Backfiller) Start(serviceIRI *url.URL) (*Job, error) {
+	id := JobID(serviceIRI)
+
+	job, err := b.store.get(id)
+
+	switch {
+	case err == nil:
+		switch job.Status {
+		case StatusPending:
+			logger.Debug("Backfill job is already pending", logfields.WithServiceIRI(serviceIRI), logfields.WithID(id))
+
+			return job, nil
+		case StatusCompleted:
+			job.Page = ""
+			job.Index = 0
+			job.TotalItems = 0
+			job.NumActivities = 0
+			job.NumProcessed = 0
+		}
+
+		job.Status = StatusPending
+		job.FailedAttempts = 0
+		job.Error = ""
+	case errors.Is(err, orberrors.ErrContentNotFound):
+		job = &Job{
+			ID:         id,
+			ServiceIRI: serviceIRI.String(),
+			Status:     StatusPending,
+			Created:    b.now(),
+		}
+	default:
+		return nil, err
+	}
+
+	job.Updated = b.now()
+
+	if err := b.store.put(job); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Started backfill job", logfields.WithServiceIRI(serviceIRI), logfields.WithID(id))
+
+	return job, nil
+}
+
+// Get returns the backfill job for the given ID. If the job is not found then orberrors.ErrContentNotFound is returned.
+func (b *Backfiller) Get(id string) (*Job, error) {
+	return b.store.get(id)
+}
+
+// GetAll returns all backfill jobs.
+func (b *Backfiller) GetAll() ([]*Job, error) {
+	return b.store.getAll()
+}
+
+func (b *Backfiller) run() time.Duration {
+	jobs, err := b.store.getAll()
+	if err != nil {
+		logger.Error("Error retrieving backfill jobs", log.WithError(err))
+
+		return 0
+	}
+
+	remaining := b.MaxActivitiesPerRun
+
+	var pending bool
+
+	for _, job := range jobs {
+		if job.Status != StatusPending {
+			continue
+		}
+
+		if remaining <= 0 {
+			pending = true
+
+			break
+		}
+
+		remaining -= b.runJob(job, remaining)
+
+		if job.Status == StatusPending {
+			pending = true
+		}
+	}
+
+	if pending {
+		// Continue processing soon rather than waiting for the default interval.
+		return b.AcceleratedInterval
+	}
+
+	return 0
+}
+
+func (b *Backfiller) runJob(job *Job, maxActivities int) int {
+	serviceIRI, err := url.Parse(job.ServiceIRI)
+	if err != nil {
+		logger.Error("Invalid service IRI in backfill job", logfields.WithID(job.ID), log.WithError(err))
+
+		job.Status = StatusFailed
+		job.Error = err.Error()
+
+		if e := b.checkpoint(job); e != nil {
+			logger.Error("Error storing backfill job", logfields.WithID(job.ID), log.WithError(e))
+		}
+
+		return 0
+	}
+
+	numRead, err := b.backfill(job, serviceIRI, maxActivities)
+	if err != nil {
+		job.FailedAttempts++
+		job.Error = err.Error()
+
+		if job.FailedAttempts >= b.MaxFailedAttempts {
+			logger.Error("Backfill job failed", logfields.WithID(job.ID), logfields.WithServiceIRI(serviceIRI),
+				log.WithError(err))
+
+			job.Status = StatusFailed
+		} else {
+			logger.Warn("Error processing backfill job. The job will be retried in the next run.",
+				logfields.WithID(job.ID), logfields.WithServiceIRI(serviceIRI), log.WithError(err))
+		}
+	} else {
+		job.FailedAttempts = 0
+		job.Error = ""
+	}
+
+	if e := b.checkpoint(job); e != nil {
+		logger.Error("Error storing backfill job", logfields.WithID(job.ID), log.WithError(e))
+	}
+
+	if job.Status == StatusCompleted {
+		logger.Info("Backfill job completed", logfields.WithID(job.ID), logfields.WithServiceIRI(serviceIRI),
+			logfields.WithTotal(job.NumActivities), logfields.WithNumActivitiesSynced(job.NumProcessed))
+	}
+
+	return numRead
+}
+
+// backfill processes up to the given maximum number of activities from the remote outbox and returns the
+// number of activities that were read. The job's checkpoint is updated after each activity is processed and
+// is persisted whenever a new page is started.
+func (b *Backfiller) backfill(job *Job, serviceIRI *url.URL, maxActivities int) (int, error) {
+	it, err := b.getActivities(job, serviceIRI)
+	if err != nil {
+		return 0, err
+	}
+
+	var numRead int
+
+	for numRead < maxActivities {
+		a, e := it.Next()
+		if e != nil {
+			if errors.Is(e, client.ErrNotFound) {
+				job.Status = StatusCompleted
+
+				return numRead, nil
+			}
+
+			return numRead, fmt.Errorf("next activity: %w", e)
+		}
+
+		numRead++
+
+		if a != nil && a.Type().IsAny(vocab.TypeCreate, vocab.TypeAnnounce) {
+			n, e := b.process(serviceIRI, it.CurrentPage(), a)
+			if e != nil {
+				return numRead, e
+			}
+
+			job.NumProcessed += n
+		}
+
+		job.NumActivities++
+
+		previousPage := job.Page
+
+		job.Page, job.Index = it.CurrentPage().String(), it.NextIndex()
+
+		if previousPage != "" && previousPage != job.Page {
+			if e := b.checkpoint(job); e != nil {
+				return numRead, e
+			}
+		}
+	}
+
+	return numRead, nil
+}
+
+func (b *Backfiller) checkpoint(job *Job) error {
+	job.Updated = b.now()
+
+	logger.Debug("Storing backfill checkpoint", logfields.WithID(job.ID), logfields.WithURLString(job.Page),
+		logfields.WithIndex(job.Index))
+
+	return b.store.put(job)
+}
+
+func (b *Backfiller) getActivities(job *Job, serviceIRI *url.URL) (client.ActivityIterator, error) {
+	if job.Page == "" {
+		actor, err := b.apClient.GetActor(serviceIRI)
+		if err != nil {
+			return nil, fmt.Errorf("get actor [%s]: %w", serviceIRI, err)
+		}
+
+		it, err := b.apClient.GetActivities(context.Background(), actor.Outbox(), client.Forward)
+		if err != nil {
+			return nil, fmt.Errorf("get activities from [%s]: %w", actor.Outbox(), err)
+		}
+
+		job.TotalItems = it.TotalItems()
+
+		return it, nil
+	}
+
+	page, err := url.Parse(job.Page)
+	if err != nil {
+		return nil, fmt.Errorf("parse checkpoint page [%s]: %w", job.Page, err)
+	}
+
+	it, err := b.apClient.GetActivities(context.Background(), page, client.Forward)
+	if err != nil {
+		return nil, fmt.Errorf("get activities from [%s]: %w", page, err)
+	}
+
+	// Resume from the checkpoint.
+	it.SetNextIndex(job.Index)
+
+	return it, nil
+}
+
+func (b *Backfiller) process(serviceIRI, currentPage *url.URL, a *vocab.ActivityType) (int, error) {
+	_, err := b.activityPubStore.GetActivity(a.ID().URL())
+	if err == nil {
+		logger.Debug("Ignoring activity since it has already been processed.", logfields.WithActivityID(a.ID()),
+			logfields.WithURL(currentPage))
+
+		return 0, nil
+	}
+
+	if !errors.Is(err, store.ErrNotFound) {
+		return 0, fmt.Errorf("get activity [%s]: %w", a.ID(), err)
+	}
+
+	if b.proofVerifier != nil {
+		if e := b.proofVerifier.Verify(a); e != nil {
+			if orberrors.IsTransient(e) {
+				return 0, fmt.Errorf("verify proof of activity [%s]: %w", a.ID(), e)
+			}
+
+			logger.Warn("Ignoring activity since its proof could not be verified.", logfields.WithActivityID(a.ID()),
+				logfields.WithActivityType(a.Type().String()), logfields.WithURL(currentPage), log.WithError(e))
+
+			return 0, nil
+		}
+	}
+
+	ctx := context.Background()
+
+	var numProcessed int
+
+	if a.Type().Is(vocab.TypeCreate) {
+		err = b.getHandler().HandleCreateActivity(ctx, serviceIRI, a, false)
+
+		numProcessed = 1
+	} else {
+		numProcessed, err = b.getHandler().HandleAnnounceActivity(ctx, serviceIRI, a)
+	}
+
+	if err != nil {
+		if errors.Is(err, spi.ErrDuplicateAnchorEvent) {
+			logger.Debug("Ignoring activity since the anchor event has already been processed.",
+				logfields.WithActivityID(a.ID()), logfields.WithURL(currentPage))
+
+			numProcessed = 0
+		} else {
+			return 0, fmt.Errorf("handle %s activity [%s]: %w", a.Type(), a.ID(), err)
+		}
+	}
+
+	// Store the activity so that we don't process it again.
+	if err := b.activityPubStore.AddActivity(a); err != nil {
+		return 0, fmt.Errorf("store activity [%s]: %w", a.ID(), err)
+	}
+
+	return numProcessed, nil
+}
+
+func resolveConfig(cfg *Config) *Config {
+	config := *cfg
+
+	if config.Interval == 0 {
+		config.Interval = defaultInterval
+	}
+
+	if config.AcceleratedInterval == 0 {
+		config.AcceleratedInterval = defaultAcceleratedInterval
+	}
+
+	if config.MaxActivitiesPerRun == 0 {
+		config.MaxActivitiesPerRun = defaultMaxActivitiesPerRun
+	}
+
+	if config.MaxFailedAttempts == 0 {
+		config.MaxFailedAttempts = defaultMaxFailedAttempts
+	}
+
+	return &config
+}
@@ -0,0 +1,458 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/client"
+	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var service2IRI = testutil.MustParseURL("https://domain2.com/services/orb")
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), &mockClient{}, memstore.New("service1"),
+			mem.NewProvider(), func() spi.InboxHandler { return nil })
+		require.NoError(t, err)
+		require.NotNil(t, b)
+		require.Equal(t, defaultInterval, b.Interval)
+		require.Equal(t, defaultMaxActivitiesPerRun, b.MaxActivitiesPerRun)
+	})
+
+	t.Run("Open store error", func(t *testing.T) {
+		errExpected := errors.New("injected open store error")
+
+		_, err := New(Config{}, mocks.NewTaskManager("backfill"), &mockClient{}, memstore.New("service1"),
+			&mockstore.Provider{ErrOpenStore: errExpected}, func() spi.InboxHandler { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestBackfiller_Start(t *testing.T) {
+	b, err := New(Config{}, mocks.NewTaskManager("backfill"), &mockClient{}, memstore.New("service1"),
+		mem.NewProvider(), func() spi.InboxHandler { return nil })
+	require.NoError(t, err)
+
+	job, err := b.Start(service2IRI)
+	require.NoError(t, err)
+	require.Equal(t, JobID(service2IRI), job.ID)
+	require.Equal(t, StatusPending, job.Status)
+
+	job.Page = "https://domain2.com/services/orb/outbox?page=true&page-num=1"
+	job.NumProcessed = 5
+	require.NoError(t, b.store.put(job))
+
+	t.Run("Already pending", func(t *testing.T) {
+		j, err := b.Start(service2IRI)
+		require.NoError(t, err)
+		require.Equal(t, job.Page, j.Page)
+	})
+
+	t.Run("Failed -> resume from checkpoint", func(t *testing.T) {
+		job.Status = StatusFailed
+		job.FailedAttempts = 10
+		job.Error = "some error"
+		require.NoError(t, b.store.put(job))
+
+		j, err := b.Start(service2IRI)
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, j.Status)
+		require.Equal(t, job.Page, j.Page)
+		require.Zero(t, j.FailedAttempts)
+		require.Empty(t, j.Error)
+	})
+
+	t.Run("Completed -> restart", func(t *testing.T) {
+		job.Status = StatusCompleted
+		require.NoError(t, b.store.put(job))
+
+		j, err := b.Start(service2IRI)
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, j.Status)
+		require.Empty(t, j.Page)
+		require.Zero(t, j.NumProcessed)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		j, err := b.Get(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, job.ID, j.ID)
+
+		_, err = b.Get("unknown")
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		jobs, err := b.GetAll()
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		b2, err := New(Config{}, mocks.NewTaskManager("backfill"), &mockClient{}, memstore.New("service1"),
+			&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrGet: errExpected}},
+			func() spi.InboxHandler { return nil })
+		require.NoError(t, err)
+
+		_, err = b2.Start(service2IRI)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	createActivities := aptestutil.NewMockCreateActivities(4)
+	announceActivities := aptestutil.NewMockAnnounceActivities(2)
+	likeActivities := aptestutil.NewMockLikeActivities(1)
+
+	outbox := testutil.NewMockID(service2IRI, "/outbox")
+
+	newClient := func() *mockClient {
+		return &mockClient{
+			actor: aptestutil.NewMockService(service2IRI),
+			pages: [][]*vocab.ActivityType{
+				{createActivities[0], createActivities[1], likeActivities[0]},
+				{announceActivities[0], createActivities[2]},
+				{announceActivities[1], createActivities[3]},
+			},
+			outbox: outbox,
+		}
+	}
+
+	t.Run("Resumes from checkpoint", func(t *testing.T) {
+		apStore := memstore.New("service1")
+
+		// This activity was already processed and should be skipped.
+		require.NoError(t, apStore.AddActivity(createActivities[1]))
+
+		handler := &mockHandler{}
+		apClient := newClient()
+
+		b, err := New(Config{MaxActivitiesPerRun: 4, AcceleratedInterval: time.Millisecond},
+			mocks.NewTaskManager("backfill"), apClient, apStore, mem.NewProvider(),
+			func() spi.InboxHandler { return handler })
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		require.Equal(t, time.Millisecond, b.run())
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, job.Status)
+		require.Equal(t, 7, job.TotalItems)
+		require.Equal(t, 4, job.NumActivities)
+		require.Equal(t, 2, job.NumProcessed)
+		require.Equal(t, apClient.pageIRI(1).String(), job.Page)
+		require.Equal(t, 1, job.Index)
+
+		// Simulate a restart by creating a new backfiller with the same storage.
+		b2 := &Backfiller{
+			Config:           b.Config,
+			apClient:         apClient,
+			activityPubStore: apStore,
+			store:            b.store,
+			getHandler:       b.getHandler,
+			now:              time.Now,
+		}
+
+		require.Zero(t, b2.run())
+
+		job, err = b2.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusCompleted, job.Status)
+		require.Equal(t, 7, job.NumActivities)
+		require.Equal(t, 5, job.NumProcessed)
+		require.Len(t, handler.activities, 5)
+
+		// Nothing to do.
+		require.Zero(t, b2.run())
+	})
+
+	t.Run("Duplicate anchor event", func(t *testing.T) {
+		handler := &mockHandler{duplicate: true}
+
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), newClient(), memstore.New("service1"),
+			mem.NewProvider(), func() spi.InboxHandler { return handler })
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		b.run()
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusCompleted, job.Status)
+		require.Zero(t, job.NumProcessed)
+	})
+
+	t.Run("Handler error -> retry then fail", func(t *testing.T) {
+		handler := &mockHandler{err: errors.New("injected handler error")}
+
+		b, err := New(Config{MaxFailedAttempts: 2}, mocks.NewTaskManager("backfill"), newClient(),
+			memstore.New("service1"), mem.NewProvider(), func() spi.InboxHandler { return handler })
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		b.run()
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, job.Status)
+		require.Equal(t, 1, job.FailedAttempts)
+		require.Contains(t, job.Error, "injected handler error")
+
+		b.run()
+
+		job, err = b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusFailed, job.Status)
+		require.Equal(t, 2, job.FailedAttempts)
+	})
+
+	t.Run("Invalid proof -> activity skipped", func(t *testing.T) {
+		handler := &mockHandler{}
+		apStore := memstore.New("service1")
+
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), newClient(), apStore,
+			mem.NewProvider(), func() spi.InboxHandler { return handler },
+			WithProofVerifier(&mockProofVerifier{err: errors.New("injected verify error")}))
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		b.run()
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusCompleted, job.Status)
+		require.Zero(t, job.NumProcessed)
+		require.Empty(t, handler.activities)
+
+		_, err = apStore.GetActivity(createActivities[0].ID().URL())
+		require.Error(t, err)
+	})
+
+	t.Run("Proof verifier transient error -> retry", func(t *testing.T) {
+		handler := &mockHandler{}
+
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), newClient(), memstore.New("service1"),
+			mem.NewProvider(), func() spi.InboxHandler { return handler },
+			WithProofVerifier(&mockProofVerifier{err: orberrors.NewTransientf("injected verify error")}))
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		b.run()
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, job.Status)
+		require.Contains(t, job.Error, "injected verify error")
+		require.Empty(t, handler.activities)
+	})
+
+	t.Run("Valid proof", func(t *testing.T) {
+		handler := &mockHandler{}
+
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), newClient(), memstore.New("service1"),
+			mem.NewProvider(), func() spi.InboxHandler { return handler },
+			WithProofVerifier(&mockProofVerifier{}))
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		b.run()
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusCompleted, job.Status)
+		require.Len(t, handler.activities, 6)
+	})
+
+	t.Run("GetActor error", func(t *testing.T) {
+		apClient := newClient()
+		apClient.err = errors.New("injected client error")
+
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), apClient, memstore.New("service1"),
+			mem.NewProvider(), func() spi.InboxHandler { return &mockHandler{} })
+		require.NoError(t, err)
+
+		_, err = b.Start(service2IRI)
+		require.NoError(t, err)
+
+		b.run()
+
+		job, err := b.Get(JobID(service2IRI))
+		require.NoError(t, err)
+		require.Equal(t, StatusPending, job.Status)
+		require.Contains(t, job.Error, "injected client error")
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		b, err := New(Config{}, mocks.NewTaskManager("backfill"), newClient(), memstore.New("service1"),
+			&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrQuery: errors.New("injected query error")}},
+			func() spi.InboxHandler { return &mockHandler{} })
+		require.NoError(t, err)
+
+		require.Zero(t, b.run())
+	})
+}
+
+type mockProofVerifier struct {
+	err error
+}
+
+func (m *mockProofVerifier) Verify(*vocab.ActivityType) error {
+	return m.err
+}
+
+type mockHandler struct {
+	activities []*vocab.ActivityType
+	duplicate  bool
+	err        error
+}
+
+func (m *mockHandler) HandleCreateActivity(_ context.Context, _ *url.URL, a *vocab.ActivityType, _ bool) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	if m.duplicate {
+		return spi.ErrDuplicateAnchorEvent
+	}
+
+	m.activities = append(m.activities, a)
+
+	return nil
+}
+
+func (m *mockHandler) HandleAnnounceActivity(_ context.Context, _ *url.URL, a *vocab.ActivityType) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+
+	if m.duplicate {
+		return 0, spi.ErrDuplicateAnchorEvent
+	}
+
+	m.activities = append(m.activities, a)
+
+	return 1, nil
+}
+
+type mockClient struct {
+	actor  *vocab.ActorType
+	outbox *url.URL
+	pages  [][]*vocab.ActivityType
+	err    error
+}
+
+func (m *mockClient) GetActor(*url.URL) (*vocab.ActorType, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return vocab.NewService(m.actor.ID().URL(), vocab.WithOutbox(m.outbox)), nil
+}
+
+func (m *mockClient) GetActivities(_ context.Context, iri *url.URL, _ client.Order) (client.ActivityIterator, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var total int
+
+	for _, p := range m.pages {
+		total += len(p)
+	}
+
+	if iri.String() == m.outbox.String() {
+		return &mockIterator{client: m, pageNum: 0, total: total}, nil
+	}
+
+	for i := range m.pages {
+		if m.pageIRI(i).String() == iri.String() {
+			return &mockIterator{client: m, pageNum: i, total: total}, nil
+		}
+	}
+
+	return nil, client.ErrNotFound
+}
+
+func (m *mockClient) pageIRI(i int) *url.URL {
+	return testutil.MustParseURL(fmt.Sprintf("%s?page=true&page-num=%d", m.outbox, i))
+}
+
+type mockIterator struct {
+	client  *mockClient
+	pageNum int
+	index   int
+	total   int
+}
+
+func (it *mockIterator) Next() (*vocab.ActivityType, error) {
+	for it.index >= len(it.client.pages[it.pageNum]) {
+		if it.pageNum+1 >= len(it.client.pages) {
+			return nil, client.ErrNotFound
+		}
+
+		it.pageNum++
+		it.index = 0
+	}
+
+	a := it.client.pages[it.pageNum][it.index]
+
+	it.index++
+
+	return a, nil
+}
+
+func (it *mockIterator) NextPage() (*url.URL, error) {
+	return nil, client.ErrNotFound
+}
+
+func (it *mockIterator) SetNextIndex(index int) {
+	it.index = index
+}
+
+func (it *mockIterator) TotalItems() int {
+	return it.total
+}
+
+func (it *mockIterator) CurrentPage() *url.URL {
+	return it.client.pageIRI(it.pageNum)
+}
+
+func (it *mockIterator) NextIndex() int {
+	return it.index
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backfill
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	namespace = "activity-backfill"
+
+	jobTagName = "job"
+)
+
+// Status is the status of a backfill job.
+type Status = string
+
+const (
+	// StatusPending indicates that the job was started and is waiting to be (or is being) processed.
+	StatusPending Status = "pending"
+	// StatusCompleted indicates that all activities in the remote outbox were processed.
+	StatusCompleted Status = "completed"
+	// StatusFailed indicates that the job was abandoned after the maximum number of failed attempts.
+	StatusFailed Status = "failed"
+)
+
+// Job holds the state of a backfill from the outbox of a remote service. The page and index
+// are the checkpoint from which the job resumes.
+type Job struct {
+	ID             string    `json:"id"`
+	ServiceIRI     string    `json:"serviceIri"`
+	Status         Status    `json:"status"`
+	Page           string    `json:"page,omitempty"`
+	Index          int       `json:"index"`
+	TotalItems     int       `json:"totalItems"`
+	NumActivities  int       `json:"numActivities"`
+	NumProcessed   int       `json:"numProcessed"`
+	FailedAttempts int       `json:"failedAttempts,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Created        time.Time `json:"created"`
+	Updated        time.Time `json:"updated"`
+}
+
+// JobID returns the ID of the backfill job for the given service.
+func JobID(serviceIRI *url.URL) string {
+	h := sha256.Sum256([]byte(serviceIRI.String()))
+
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+type jobStore struct {
+	store     storage.Store
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+func newJobStore(provider storage.Provider) (*jobStore, error) {
+	s, err := store.Open(provider, namespace, store.NewTagGroup(jobTagName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backfill store: %w", err)
+	}
+
+	return &jobStore{
+		store:     s,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}, nil
+}
+
+func (s *jobStore) put(job *Job) error {
+	jobBytes, err := s.marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal backfill job [%s]: %w", job.ID, err)
+	}
+
+	err = s.store.Put(job.ID, jobBytes, storage.Tag{Name: jobTagName})
+	if err != nil {
+		return orberrors.NewTransientf("store backfill job [%s]: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+func (s *jobStore) get(id string) (*Job, error) {
+	jobBytes, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get backfill job [%s]: %w", id, err)
+	}
+
+	job := &Job{}
+
+	err = s.unmarshal(jobBytes, job)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal backfill job [%s]: %w", id, err)
+	}
+
+	return job, nil
+}
+
+func (s *jobStore) getAll() ([]*Job, error) {
+	it, err := s.store.Query(jobTagName)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query backfill jobs: %w", err)
+	}
+
+	defer store.CloseIterator(it)
+
+	var jobs []*Job
+
+	ok, err := it.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("iterator error for backfill jobs: %w", err)
+	}
+
+	for ok {
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get iterator value for backfill jobs: %w", e)
+		}
+
+		job := &Job{}
+
+		e = s.unmarshal(value, job)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal backfill job: %w", e)
+		}
+
+		jobs = append(jobs, job)
+
+		ok, e = it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("iterator error for backfill jobs: %w", e)
+		}
+	}
+
+	return jobs, nil
+}
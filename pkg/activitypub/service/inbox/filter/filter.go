@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+var logger = log.New("inbox-filter")
+
+const (
+	rulesCacheKey = "rules"
+
+	defaultCacheExpiry = 30 * time.Second
+
+	// maxRateCounters is the number of rate counters after which expired counters are purged.
+	maxRateCounters = 1000
+)
+
+type rulesRetriever interface {
+	GetRules() ([]*Rule, error)
+}
+
+// Decision is the result of evaluating the filter rules against an inbound activity.
+type Decision struct {
+	// Action is the action to take on the activity.
+	Action Action
+
+	// Reason describes the rule that matched. It is empty if no rule matched.
+	Reason string
+}
+
+var acceptDecision = &Decision{Action: ActionAccept}
+
+type rateCounter struct {
+	windowStart time.Time
+	period      time.Duration
+	count       int
+}
+
+// Filter evaluates an ordered chain of filter rules against inbound activities. The rules are evaluated in order
+// and the action of the first matching rule is returned. If no rule matches then the activity is accepted.
+// The rules are loaded from the config store and cached for the given expiry so that updates are picked up
+// at runtime.
+type Filter struct {
+	retriever rulesRetriever
+	cache     gcache.Cache
+	expiry    time.Duration
+	now       func() time.Time
+
+	mutex    sync.Mutex
+	counters map[string]*rateCounter
+}
+
+// New returns a new inbound activity filter.
+func New(retriever rulesRetriever, cacheExpiry time.Duration) *Filter {
+	if cacheExpiry <= 0 {
+		cacheExpiry = defaultCacheExpiry
+	}
+
+	f := &Filter{
+		retriever: retriever,
+		expiry:    cacheExpiry,
+		now:       time.Now,
+		counters:  make(map[string]*rateCounter),
+	}
+
+	f.cache = gcache.New(1).LoaderExpireFunc(f.loadRules).Build()
+
+	return f
+}
+
+// Evaluate evaluates the filter rules against the given activity and returns the resulting decision.
+// The payload size is the size (in bytes) of the activity as it was received.
+func (f *Filter) Evaluate(activity *vocab.ActivityType, payloadSize int) (*Decision, error) {
+	value, err := f.cache.Get(rulesCacheKey)
+	if err != nil {
+		return nil, fmt.Errorf("get inbox filter rules: %w", err)
+	}
+
+	rules := value.([]*Rule) //nolint:forcetypeassert
+
+	for i, r := range rules {
+		matched, reason := f.match(i, r, activity, payloadSize)
+		if !matched {
+			continue
+		}
+
+		logger.Debug("Inbound activity matched filter rule", logfields.WithActivityID(activity.ID()),
+			logfields.WithIndex(i), logfields.WithType(r.Type), logfields.WithStatus(r.Action))
+
+		return &Decision{
+			Action: r.Action,
+			Reason: fmt.Sprintf("rule %d (%s): %s", i, r.Type, reason),
+		}, nil
+	}
+
+	return acceptDecision, nil
+}
+
+func (f *Filter) loadRules(interface{}) (interface{}, *time.Duration, error) {
+	rules, err := f.retriever.GetRules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Debug("Loaded inbox filter rules", logfields.WithTotal(len(rules)))
+
+	return rules, &f.expiry, nil
+}
+
+func (f *Filter) match(i int, r *Rule, activity *vocab.ActivityType, payloadSize int) (bool, string) {
+	switch r.Type {
+	case RuleTypeActorDomain:
+		return matchActorDomain(r.Values, activity)
+	case RuleTypeActivityType:
+		return matchActivityType(r.Values, activity)
+	case RuleTypeAnchorOrigin:
+		return matchAnchorOrigin(r.Values, activity)
+	case RuleTypePayloadSize:
+		if payloadSize > r.MaxSize {
+			return true, fmt.Sprintf("payload size %d exceeds maximum size %d", payloadSize, r.MaxSize)
+		}
+
+		return false, ""
+	case RuleTypeRate:
+		return f.matchRate(i, r, activity)
+	default:
+		logger.Warn("Ignoring unsupported filter rule", logfields.WithType(r.Type))
+
+		return false, ""
+	}
+}
+
+func matchActorDomain(domains []string, activity *vocab.ActivityType) (bool, string) {
+	if activity.Actor() == nil {
+		return false, ""
+	}
+
+	host := strings.ToLower(activity.Actor().Hostname())
+
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true, fmt.Sprintf("actor domain [%s] matches [%s]", host, domain)
+		}
+	}
+
+	return false, ""
+}
+
+func matchActivityType(types []string, activity *vocab.ActivityType) (bool, string) {
+	for _, t := range types {
+		if activity.Type().Is(vocab.Type(t)) {
+			return true, fmt.Sprintf("activity type is [%s]", t)
+		}
+	}
+
+	return false, ""
+}
+
+func matchAnchorOrigin(origins []string, activity *vocab.ActivityType) (bool, string) {
+	origin := anchorOrigin(activity)
+	if origin == "" {
+		return false, ""
+	}
+
+	for _, o := range origins {
+		if origin == o {
+			return true, fmt.Sprintf("anchor origin is [%s]", o)
+		}
+	}
+
+	return false, ""
+}
+
+// anchorOrigin returns the origin (author) of the anchor embedded in a 'Create' activity. An empty string is
+// returned if the activity doesn't contain an embedded anchor.
+func anchorOrigin(activity *vocab.ActivityType) string {
+	if !activity.Type().Is(vocab.TypeCreate) {
+		return ""
+	}
+
+	anchorEvent := activity.Object().AnchorEvent()
+	if anchorEvent == nil || anchorEvent.Object() == nil {
+		return ""
+	}
+
+	anchorLinkset := &linkset.Linkset{}
+
+	err := vocab.UnmarshalFromDoc(anchorEvent.Object().Document(), anchorLinkset)
+	if err != nil {
+		logger.Debug("Unable to unmarshal anchor linkset in activity", logfields.WithActivityID(activity.ID()),
+			log.WithError(err))
+
+		return ""
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil || anchorLink.Author() == nil {
+		return ""
+	}
+
+	return anchorLink.Author().String()
+}
+
+func (f *Filter) matchRate(i int, r *Rule, activity *vocab.ActivityType) (bool, string) {
+	if activity.Actor() == nil {
+		return false, ""
+	}
+
+	period, err := r.period()
+	if err != nil {
+		logger.Warn("Ignoring invalid rate filter rule", log.WithError(err))
+
+		return false, ""
+	}
+
+	// The rule's parameters are included in the key so that the counters are reset when the rule is changed.
+	key := fmt.Sprintf("%d|%d|%s|%s", i, r.MaxActivities, r.Period, activity.Actor())
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := f.now()
+
+	counter, ok := f.counters[key]
+	if !ok || now.Sub(counter.windowStart) >= period {
+		if !ok && len(f.counters) >= maxRateCounters {
+			f.purgeExpiredCounters(now)
+		}
+
+		counter = &rateCounter{windowStart: now, period: period}
+
+		f.counters[key] = counter
+	}
+
+	counter.count++
+
+	if counter.count > r.MaxActivities {
+		return true, fmt.Sprintf("actor [%s] exceeded %d activities in %s", activity.Actor(), r.MaxActivities, r.Period)
+	}
+
+	return false, ""
+}
+
+func (f *Filter) purgeExpiredCounters(now time.Time) {
+	for key, counter := range f.counters {
+		if now.Sub(counter.windowStart) >= counter.period {
+			delete(f.counters, key)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/aptestutil"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	service1IRI = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	service2IRI = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	service3IRI = testutil.MustParseURL("https://domain3.com/services/orb")
+)
+
+func TestFilter_Evaluate(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore("config")
+	require.NoError(t, err)
+
+	s := NewStore(configStore)
+
+	follow := vocab.NewFollowActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(service1IRI)),
+		vocab.WithID(aptestutil.NewActivityID(service2IRI)),
+		vocab.WithActor(service2IRI),
+		vocab.WithTo(service1IRI),
+	)
+
+	create := aptestutil.NewMockCreateActivity(service3IRI, service1IRI,
+		vocab.NewObjectProperty(vocab.WithAnchorEvent(aptestutil.NewMockAnchorEvent(t, aptestutil.NewMockAnchorLink(t)))),
+	)
+
+	t.Run("No rules -> accept", func(t *testing.T) {
+		f := New(s, time.Minute)
+
+		d, err := f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+		require.Empty(t, d.Reason)
+	})
+
+	t.Run("Actor domain", func(t *testing.T) {
+		f := New(&mockRetriever{rules: []*Rule{
+			{Type: RuleTypeActorDomain, Action: ActionReject, Values: []string{"domain2.com"}},
+		}}, time.Minute)
+
+		d, err := f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionReject, d.Action)
+		require.Contains(t, d.Reason, "actor domain [orb.domain2.com] matches [domain2.com]")
+
+		d, err = f.Evaluate(create, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+	})
+
+	t.Run("Activity type", func(t *testing.T) {
+		f := New(&mockRetriever{rules: []*Rule{
+			{Type: RuleTypeActivityType, Action: ActionQuarantine, Values: []string{"Follow", "Invite"}},
+		}}, time.Minute)
+
+		d, err := f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionQuarantine, d.Action)
+
+		d, err = f.Evaluate(create, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+	})
+
+	t.Run("Anchor origin", func(t *testing.T) {
+		f := New(&mockRetriever{rules: []*Rule{
+			{Type: RuleTypeAnchorOrigin, Action: ActionReject, Values: []string{service2IRI.String()}},
+		}}, time.Minute)
+
+		d, err := f.Evaluate(create, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionReject, d.Action)
+
+		d, err = f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+	})
+
+	t.Run("Payload size", func(t *testing.T) {
+		f := New(&mockRetriever{rules: []*Rule{
+			{Type: RuleTypePayloadSize, Action: ActionReject, MaxSize: 1000},
+		}}, time.Minute)
+
+		d, err := f.Evaluate(follow, 1000)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+
+		d, err = f.Evaluate(follow, 1001)
+		require.NoError(t, err)
+		require.Equal(t, ActionReject, d.Action)
+	})
+
+	t.Run("Rate", func(t *testing.T) {
+		now := time.Now()
+
+		f := New(&mockRetriever{rules: []*Rule{
+			{Type: RuleTypeRate, Action: ActionQuarantine, MaxActivities: 2, Period: "1m"},
+		}}, time.Minute)
+
+		f.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			d, err := f.Evaluate(follow, 100)
+			require.NoError(t, err)
+			require.Equal(t, ActionAccept, d.Action)
+		}
+
+		d, err := f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionQuarantine, d.Action)
+
+		// Other actors are not affected.
+		d, err = f.Evaluate(create, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+
+		now = now.Add(time.Minute)
+
+		d, err = f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+	})
+
+	t.Run("Ordered rules", func(t *testing.T) {
+		f := New(&mockRetriever{rules: []*Rule{
+			{Type: RuleTypeActorDomain, Action: ActionAccept, Values: []string{"orb.domain2.com"}},
+			{Type: RuleTypeActivityType, Action: ActionReject, Values: []string{"Follow"}},
+		}}, time.Minute)
+
+		d, err := f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+		require.Contains(t, d.Reason, "rule 0")
+	})
+
+	t.Run("Rules updated at runtime", func(t *testing.T) {
+		f := New(s, time.Millisecond)
+
+		d, err := f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionAccept, d.Action)
+
+		require.NoError(t, s.PutRules([]*Rule{
+			{Type: RuleTypeActivityType, Action: ActionReject, Values: []string{"Follow"}},
+		}))
+
+		time.Sleep(10 * time.Millisecond)
+
+		d, err = f.Evaluate(follow, 100)
+		require.NoError(t, err)
+		require.Equal(t, ActionReject, d.Action)
+	})
+
+	t.Run("Retriever error", func(t *testing.T) {
+		errExpected := errors.New("injected retriever error")
+
+		f := New(&mockRetriever{err: errExpected}, 0)
+
+		_, err := f.Evaluate(follow, 100)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
+
+func TestFilter_PurgeExpiredCounters(t *testing.T) {
+	now := time.Now()
+
+	f := New(&mockRetriever{}, time.Minute)
+
+	f.counters["expired"] = &rateCounter{windowStart: now.Add(-2 * time.Minute), period: time.Minute}
+	f.counters["current"] = &rateCounter{windowStart: now, period: time.Minute}
+
+	f.purgeExpiredCounters(now)
+
+	require.Len(t, f.counters, 1)
+	require.NotNil(t, f.counters["current"])
+}
+
+type mockRetriever struct {
+	rules []*Rule
+	err   error
+}
+
+func (m *mockRetriever) GetRules() ([]*Rule, error) {
+	return m.rules, m.err
+}
@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"fmt"
+	"time"
+)
+
+// Action is the action that is taken on an inbound activity that matches a filter rule.
+type Action = string
+
+const (
+	// ActionAccept indicates that the activity is accepted and processed. No further rules are evaluated.
+	ActionAccept Action = "accept"
+
+	// ActionReject indicates that the activity is dropped.
+	ActionReject Action = "reject"
+
+	// ActionQuarantine indicates that the activity is not processed but is stored in the quarantine
+	// so that it may be reviewed (and possibly released) by an operator.
+	ActionQuarantine Action = "quarantine"
+)
+
+// RuleType is the type of filter rule.
+type RuleType = string
+
+const (
+	// RuleTypeActorDomain matches activities whose actor is hosted on one of the given domains (or a subdomain thereof).
+	RuleTypeActorDomain RuleType = "actorDomain"
+
+	// RuleTypeActivityType matches activities of one of the given types.
+	RuleTypeActivityType RuleType = "activityType"
+
+	// RuleTypeAnchorOrigin matches 'Create' activities containing an embedded anchor whose origin (author)
+	// is one of the given values.
+	RuleTypeAnchorOrigin RuleType = "anchorOrigin"
+
+	// RuleTypePayloadSize matches activities whose payload exceeds the given maximum size (in bytes).
+	RuleTypePayloadSize RuleType = "payloadSize"
+
+	// RuleTypeRate matches activities from an actor that has sent more than the given maximum number of
+	// activities within the given period.
+	RuleTypeRate RuleType = "rate"
+)
+
+// Rule is a single inbound activity filter rule.
+type Rule struct {
+	// Type is the type of rule.
+	Type RuleType `json:"type"`
+
+	// Action is the action to take when the rule matches.
+	Action Action `json:"action"`
+
+	// Values contains the domains, activity types or anchor origins to match,
+	// depending on the type of rule.
+	Values []string `json:"values,omitempty"`
+
+	// MaxSize is the maximum payload size (in bytes) for the 'payloadSize' rule.
+	MaxSize int `json:"maxSize,omitempty"`
+
+	// MaxActivities is the maximum number of activities allowed from an actor within the period
+	// for the 'rate' rule.
+	MaxActivities int `json:"maxActivities,omitempty"`
+
+	// Period is the period (for example "1m") for the 'rate' rule.
+	Period string `json:"period,omitempty"`
+}
+
+// Validate returns an error if the rule is invalid.
+func (r *Rule) Validate() error {
+	switch r.Action {
+	case ActionAccept, ActionReject, ActionQuarantine:
+	default:
+		return fmt.Errorf("invalid action [%s] for rule of type [%s]", r.Action, r.Type)
+	}
+
+	switch r.Type {
+	case RuleTypeActorDomain, RuleTypeActivityType, RuleTypeAnchorOrigin:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("at least one value is required for rule of type [%s]", r.Type)
+		}
+	case RuleTypePayloadSize:
+		if r.MaxSize <= 0 {
+			return fmt.Errorf("maxSize must be greater than 0 for rule of type [%s]", r.Type)
+		}
+	case RuleTypeRate:
+		if r.MaxActivities <= 0 {
+			return fmt.Errorf("maxActivities must be greater than 0 for rule of type [%s]", r.Type)
+		}
+
+		if _, err := r.period(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid rule type [%s]", r.Type)
+	}
+
+	return nil
+}
+
+func (r *Rule) period() (time.Duration, error) {
+	period, err := time.ParseDuration(r.Period)
+	if err != nil {
+		return 0, fmt.Errorf("invalid period [%s] for rule of type [%s]: %w", r.Period, r.Type, err)
+	}
+
+	if period <= 0 {
+		return 0, fmt.Errorf("period must be greater than 0 for rule of type [%s]", r.Type)
+	}
+
+	return period, nil
+}
+
+// ValidateRules returns an error if any of the given rules is invalid.
+func ValidateRules(rules []*Rule) error {
+	for i, r := range rules {
+		if r == nil {
+			return fmt.Errorf("rule %d is nil", i)
+		}
+
+		if err := r.Validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const rulesKey = "inbox-filters"
+
+// Store implements the inbox filter rules config store.
+type Store struct {
+	store     storage.Store
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// NewStore returns a new inbox filter rules config store.
+func NewStore(store storage.Store) *Store {
+	return &Store{
+		store:     store,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}
+}
+
+// PutRules validates and stores the given (ordered) filter rules, replacing the existing rules.
+func (s *Store) PutRules(rules []*Rule) error {
+	if err := ValidateRules(rules); err != nil {
+		return orberrors.NewBadRequest(err)
+	}
+
+	valueBytes, err := s.marshal(rules)
+	if err != nil {
+		return fmt.Errorf("marshal inbox filter rules: %w", err)
+	}
+
+	err = s.store.Put(rulesKey, valueBytes)
+	if err != nil {
+		return orberrors.NewTransientf("store inbox filter rules: %w", err)
+	}
+
+	return nil
+}
+
+// GetRules returns the filter rules. An empty slice is returned if no rules were configured.
+func (s *Store) GetRules() ([]*Rule, error) {
+	rulesBytes, err := s.store.Get(rulesKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return []*Rule{}, nil
+		}
+
+		return nil, orberrors.NewTransientf("get inbox filter rules: %w", err)
+	}
+
+	var rules []*Rule
+
+	err = s.unmarshal(rulesBytes, &rules)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal inbox filter rules: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+func TestStore(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore("config")
+	require.NoError(t, err)
+
+	s := NewStore(configStore)
+
+	rules, err := s.GetRules()
+	require.NoError(t, err)
+	require.Empty(t, rules)
+
+	require.NoError(t, s.PutRules([]*Rule{
+		{Type: RuleTypeActorDomain, Action: ActionAccept, Values: []string{"domain1.com"}},
+		{Type: RuleTypeRate, Action: ActionQuarantine, MaxActivities: 10, Period: "1m"},
+	}))
+
+	rules, err = s.GetRules()
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, RuleTypeActorDomain, rules[0].Type)
+	require.Equal(t, RuleTypeRate, rules[1].Type)
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		err := s.PutRules([]*Rule{{Type: RuleTypePayloadSize, Action: ActionReject}})
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		errExpected := errors.New("injected put error")
+
+		err := NewStore(&mockstore.Store{ErrPut: errExpected}).PutRules(nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		_, err := NewStore(&mockstore.Store{ErrGet: errExpected}).GetRules()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("Unmarshal error", func(t *testing.T) {
+		_, err := NewStore(&mockstore.Store{GetReturn: []byte("{")}).GetRules()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal inbox filter rules")
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	require.NoError(t, ValidateRules([]*Rule{
+		{Type: RuleTypeActorDomain, Action: ActionAccept, Values: []string{"domain1.com"}},
+		{Type: RuleTypeActivityType, Action: ActionReject, Values: []string{"Follow"}},
+		{Type: RuleTypeAnchorOrigin, Action: ActionQuarantine, Values: []string{"did:web:domain1.com"}},
+		{Type: RuleTypePayloadSize, Action: ActionReject, MaxSize: 100000},
+		{Type: RuleTypeRate, Action: ActionQuarantine, MaxActivities: 10, Period: "1m"},
+	}))
+
+	tests := []struct {
+		rule *Rule
+		err  string
+	}{
+		{rule: nil, err: "rule 0 is nil"},
+		{rule: &Rule{Type: RuleTypeActorDomain, Action: "drop"}, err: "invalid action [drop]"},
+		{rule: &Rule{Type: "xxx", Action: ActionReject}, err: "invalid rule type [xxx]"},
+		{rule: &Rule{Type: RuleTypeActorDomain, Action: ActionReject}, err: "at least one value is required"},
+		{rule: &Rule{Type: RuleTypePayloadSize, Action: ActionReject}, err: "maxSize must be greater than 0"},
+		{rule: &Rule{Type: RuleTypeRate, Action: ActionReject, Period: "1m"}, err: "maxActivities must be greater than 0"},
+		{rule: &Rule{Type: RuleTypeRate, Action: ActionReject, MaxActivities: 1, Period: "x"}, err: "invalid period [x]"},
+		{rule: &Rule{Type: RuleTypeRate, Action: ActionReject, MaxActivities: 1, Period: "0s"}, err: "period must be greater than 0"},
+	}
+
+	for _, test := range tests {
+		err := ValidateRules([]*Rule{test.rule})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), test.err)
+	}
+}
@@ -8,8 +8,11 @@ package httpsubscriber
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	wmhttp "github.com/ThreeDotsLabs/watermill-http/pkg/http"
 	"github.com/ThreeDotsLabs/watermill/message"
@@ -45,6 +48,21 @@ type authTokenManager interface {
 	RequiredAuthTokens(endpoint, method string) ([]string, error)
 }
 
+type rateLimiter interface {
+	Allow(actor *url.URL) (bool, time.Duration)
+}
+
+// Option is an HTTP subscriber option.
+type Option func(s *Subscriber)
+
+// WithRateLimiter sets the rate limiter that is applied to incoming requests after the
+// request has been authorized. If not set then requests are not rate limited.
+func WithRateLimiter(rl rateLimiter) Option {
+	return func(s *Subscriber) {
+		s.rateLimiter = rl
+	}
+}
+
 // Subscriber implements a subscriber for Watermill that handles HTTP requests.
 type Subscriber struct {
 	*lifecycle.Lifecycle
@@ -57,11 +75,12 @@ type Subscriber struct {
 	unmarshalMessage wmhttp.UnmarshalMessageFunc
 	verifier         signatureVerifier
 	tokenVerifier    *auth.TokenVerifier
+	rateLimiter      rateLimiter
 	logger           *log.Log
 }
 
 // New returns a new HTTP subscriber.
-func New(cfg *Config, sigVerifier signatureVerifier, tm authTokenManager) *Subscriber {
+func New(cfg *Config, sigVerifier signatureVerifier, tm authTokenManager, opts ...Option) *Subscriber {
 	if cfg.BufferSize == 0 {
 		cfg.BufferSize = defaultBufferSize
 	}
@@ -78,6 +97,10 @@ func New(cfg *Config, sigVerifier signatureVerifier, tm authTokenManager) *Subsc
 		logger:           log.New(loggerModule, log.WithFields(logfields.WithServiceName(cfg.ServiceEndpoint))),
 	}
 
+	for _, opt := range opts {
+		opt(s)
+	}
+
 	s.Lifecycle = lifecycle.New("httpsubscriber-"+cfg.ServiceEndpoint,
 		lifecycle.WithStop(s.stop),
 		lifecycle.WithStart(func() {
@@ -150,6 +173,18 @@ func (s *Subscriber) handleMessage(w http.ResponseWriter, r *http.Request) {
 		s.logger.Debugc(ctx, "Request was verified with a bearer token or no authorization was required.", logfields.WithSenderURL(r.URL))
 	}
 
+	if s.rateLimiter != nil {
+		if allowed, retryAfter := s.rateLimiter.Allow(actorIRI); !allowed {
+			s.logger.Infoc(ctx, "Request was throttled", logfields.WithActorIRI(actorIRI), logfields.WithSenderURL(r.URL))
+
+			// Retry-After is specified in whole seconds so round up.
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+	}
+
 	msg, err := s.unmarshalMessage("", r)
 	if err != nil {
 		s.logger.Warnc(ctx, "Error reading message", log.WithError(err), logfields.WithSenderURL(r.URL))
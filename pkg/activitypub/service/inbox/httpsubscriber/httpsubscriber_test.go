@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"sync"
 	"testing"
 	"time"
@@ -79,6 +80,51 @@ func TestSubscriber_HandleAck(t *testing.T) {
 	require.NoError(t, result.Body.Close())
 }
 
+func TestSubscriber_RateLimited(t *testing.T) {
+	sigVerifier := &mocks.SignatureVerifier{}
+	sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
+
+	tm := &apmocks.AuthTokenMgr{}
+	tm.RequiredAuthTokensReturns([]string{"admin"}, nil)
+
+	rl := &mockRateLimiter{retryAfter: 1500 * time.Millisecond}
+
+	s := New(&Config{ServiceEndpoint: endpoint}, sigVerifier, tm, WithRateLimiter(rl))
+	require.NotNil(t, s)
+
+	defer s.Stop()
+
+	msgChan, err := s.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+	require.NotNil(t, msgChan)
+
+	go func() {
+		for msg := range msgChan {
+			msg.Ack()
+		}
+	}()
+
+	rw := httptest.NewRecorder()
+
+	s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, http.NoBody))
+
+	result := rw.Result()
+	require.Equal(t, http.StatusTooManyRequests, result.StatusCode)
+	require.Equal(t, "2", result.Header.Get("Retry-After"))
+	require.NoError(t, result.Body.Close())
+	require.Equal(t, serviceURL, rl.actor.String())
+
+	rl.retryAfter = 0
+
+	rw = httptest.NewRecorder()
+
+	s.handleMessage(rw, httptest.NewRequest(http.MethodPost, endpoint, http.NoBody))
+
+	result = rw.Result()
+	require.Equal(t, http.StatusOK, result.StatusCode)
+	require.NoError(t, result.Body.Close())
+}
+
 func TestSubscriber_HandleNack(t *testing.T) {
 	sigVerifier := &mocks.SignatureVerifier{}
 	sigVerifier.VerifyRequestReturns(true, testutil.MustParseURL(serviceURL), nil)
@@ -336,3 +382,14 @@ func TestSubscriber_NoHTTPSignatureRequired(t *testing.T) {
 	require.Equal(t, http.StatusOK, result.StatusCode)
 	require.NoError(t, result.Body.Close())
 }
+
+type mockRateLimiter struct {
+	retryAfter time.Duration
+	actor      *url.URL
+}
+
+func (m *mockRateLimiter) Allow(actor *url.URL) (bool, time.Duration) {
+	m.actor = actor
+
+	return m.retryAfter == 0, m.retryAfter
+}
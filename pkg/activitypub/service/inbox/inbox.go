@@ -21,7 +21,9 @@ import (
 	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/filter"
 	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/httpsubscriber"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/ratelimiter"
 	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -30,6 +32,7 @@ import (
 	"github.com/trustbloc/orb/pkg/pubsub"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
 	"github.com/trustbloc/orb/pkg/pubsub/wmlogger"
+	"github.com/trustbloc/orb/pkg/store/quarantine"
 )
 
 const (
@@ -50,12 +53,44 @@ type signatureVerifier interface {
 
 type metricsProvider interface {
 	InboxHandlerTime(activityType string, value time.Duration)
+	InboxIncrementThrottledCount(scope string)
 }
 
 type authTokenManager interface {
 	RequiredAuthTokens(endpoint, method string) ([]string, error)
 }
 
+// ActivityFilter evaluates inbound activities and decides whether they are to be accepted, rejected or quarantined.
+type ActivityFilter interface {
+	Evaluate(activity *vocab.ActivityType, payloadSize int) (*filter.Decision, error)
+}
+
+// QuarantineStore stores quarantined activities for review by an operator.
+type QuarantineStore interface {
+	Put(e *quarantine.Entry) error
+	Get(id string) (*quarantine.Entry, error)
+	Delete(id string) error
+}
+
+// Option is an inbox option.
+type Option func(ib *Inbox)
+
+// WithActivityFilter sets the filter that is applied to inbound activities. If not set then all
+// activities are accepted.
+func WithActivityFilter(f ActivityFilter) Option {
+	return func(ib *Inbox) {
+		ib.activityFilter = f
+	}
+}
+
+// WithQuarantineStore sets the store for quarantined activities. If not set then activities that
+// are to be quarantined are rejected.
+func WithQuarantineStore(s QuarantineStore) Option {
+	return func(ib *Inbox) {
+		ib.quarantineStore = s
+	}
+}
+
 // Config holds configuration parameters for the Inbox.
 type Config struct {
 	ServiceEndpoint        string
@@ -63,6 +98,10 @@ type Config struct {
 	Topic                  string
 	VerifyActorInSignature bool
 	SubscriberPoolSize     int
+
+	// RateLimit holds the per-actor and global rate limits for requests posted to the inbox.
+	// Requests are not rate limited if no limit is set.
+	RateLimit ratelimiter.Config
 }
 
 // Inbox implements the ActivityPub inbox.
@@ -75,6 +114,9 @@ type Inbox struct {
 	msgChannel             <-chan *message.Message
 	activityHandler        service.ActivityHandler
 	activityStore          store.Store
+	activityFilter         ActivityFilter
+	quarantineStore        QuarantineStore
+	jsonMarshal            func(v interface{}) ([]byte, error)
 	jsonUnmarshal          func(data []byte, v interface{}) error
 	metrics                metricsProvider
 	verifyActorInSignature bool
@@ -83,7 +125,7 @@ type Inbox struct {
 
 // New returns a new ActivityPub inbox.
 func New(cnfg *Config, s store.Store, pubSub pubSub, activityHandler service.ActivityHandler,
-	sigVerifier signatureVerifier, tm authTokenManager, metrics metricsProvider,
+	sigVerifier signatureVerifier, tm authTokenManager, metrics metricsProvider, opts ...Option,
 ) (*Inbox, error) {
 	cfg := populateConfigDefaults(cnfg)
 
@@ -91,11 +133,16 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, activityHandler service.Act
 		Config:          &cfg,
 		activityHandler: activityHandler,
 		activityStore:   s,
+		jsonMarshal:     json.Marshal,
 		jsonUnmarshal:   json.Unmarshal,
 		metrics:         metrics,
 		logger:          log.New(loggerModule, log.WithFields(logfields.WithServiceName(cfg.ServiceEndpoint))),
 	}
 
+	for _, opt := range opts {
+		opt(h)
+	}
+
 	h.Lifecycle = lifecycle.New(cfg.ServiceEndpoint,
 		lifecycle.WithStart(h.start),
 		lifecycle.WithStop(h.stop),
@@ -106,11 +153,17 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, activityHandler service.Act
 		return nil, fmt.Errorf("subscribe to topic [%s]: %w", cfg.Topic, err)
 	}
 
+	var subscriberOpts []httpsubscriber.Option
+
+	if cfg.RateLimit.Enabled() {
+		subscriberOpts = append(subscriberOpts, httpsubscriber.WithRateLimiter(ratelimiter.New(cfg.RateLimit, metrics)))
+	}
+
 	httpSubscriber := httpsubscriber.New(
 		&httpsubscriber.Config{
 			ServiceEndpoint: cfg.ServiceEndpoint,
 		},
-		sigVerifier, tm,
+		sigVerifier, tm, subscriberOpts...,
 	)
 
 	router, err := message.NewRouter(message.RouterConfig{}, wmlogger.New())
@@ -228,6 +281,13 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 		return nil, err
 	}
 
+	// The actor IRI is only added to the message metadata if the HTTP signature was verified.
+	if actorIRI := msg.Metadata[httpsubscriber.ActorIRIKey]; actorIRI != "" {
+		if u, e := url.Parse(actorIRI); e == nil {
+			ctx = service.ContextWithVerifiedActor(ctx, u)
+		}
+	}
+
 	_, err = h.activityStore.GetActivity(activity.ID().URL())
 	if err != nil {
 		if !errors.Is(err, store.ErrNotFound) {
@@ -242,6 +302,15 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 		return activity, nil
 	}
 
+	accepted, err := h.applyFilter(ctx, activity, len(msg.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	if !accepted {
+		return activity, nil
+	}
+
 	err = h.activityHandler.HandleActivity(ctx, nil, activity)
 	if err != nil {
 		// If it's a transient error then return it so that the message is Nacked and retried. Otherwise, fall
@@ -254,6 +323,111 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 	h.logger.Debugc(ctx, "Handled message. Adding activity to inbox...",
 		logfields.WithMessageID(msg.UUID), logfields.WithActivityID(activity.ID()))
 
+	h.addToInbox(ctx, activity)
+
+	return activity, err
+}
+
+// ReleaseQuarantined processes the quarantined activity with the given ID (bypassing the inbox filter) and
+// removes it from the quarantine.
+func (h *Inbox) ReleaseQuarantined(ctx context.Context, id string) error {
+	if h.quarantineStore == nil {
+		return orberrors.ErrContentNotFound
+	}
+
+	entry, err := h.quarantineStore.Get(id)
+	if err != nil {
+		return err
+	}
+
+	activity := &vocab.ActivityType{}
+
+	err = h.jsonUnmarshal(entry.Activity, activity)
+	if err != nil {
+		return fmt.Errorf("unmarshal quarantined activity [%s]: %w", entry.ActivityID, err)
+	}
+
+	h.logger.Infoc(ctx, "Releasing quarantined activity", logfields.WithActivityID(activity.ID()))
+
+	err = h.activityHandler.HandleActivity(ctx, nil, activity)
+	if err != nil && orberrors.IsTransient(err) {
+		return err
+	}
+
+	h.addToInbox(ctx, activity)
+
+	if e := h.quarantineStore.Delete(id); e != nil {
+		return e
+	}
+
+	if err != nil {
+		return orberrors.NewBadRequest(fmt.Errorf("handle released activity [%s]: %w", activity.ID(), err))
+	}
+
+	return nil
+}
+
+// applyFilter applies the inbox filter to the given activity. True is returned if the activity should be processed.
+func (h *Inbox) applyFilter(ctx context.Context, activity *vocab.ActivityType, payloadSize int) (bool, error) {
+	if h.activityFilter == nil {
+		return true, nil
+	}
+
+	decision, err := h.activityFilter.Evaluate(activity, payloadSize)
+	if err != nil {
+		h.logger.Errorc(ctx, "Error applying inbox filter", log.WithError(err), logfields.WithActivityID(activity.ID()))
+
+		return false, orberrors.NewTransient(fmt.Errorf("apply inbox filter: %w", err))
+	}
+
+	switch decision.Action {
+	case filter.ActionQuarantine:
+		if h.quarantineStore != nil {
+			return false, h.quarantine(ctx, activity, decision.Reason)
+		}
+
+		h.logger.Warnc(ctx, "Rejecting activity since no quarantine store is configured",
+			logfields.WithActivityID(activity.ID()), logfields.WithActorIRI(activity.Actor()))
+
+		return false, nil
+	case filter.ActionReject:
+		h.logger.Infoc(ctx, "Inbound activity was rejected by the inbox filter", logfields.WithActivityID(activity.ID()),
+			logfields.WithActorIRI(activity.Actor()), logfields.WithActivityType(activity.Type().String()))
+
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+func (h *Inbox) quarantine(ctx context.Context, activity *vocab.ActivityType, reason string) error {
+	activityBytes, err := h.jsonMarshal(activity)
+	if err != nil {
+		return fmt.Errorf("marshal activity [%s]: %w", activity.ID(), err)
+	}
+
+	entry := &quarantine.Entry{
+		ID:            quarantine.ID(activity.ID().URL()),
+		ActivityID:    activity.ID().String(),
+		ActivityType:  activity.Type().String(),
+		Actor:         activity.Actor().String(),
+		Reason:        reason,
+		QuarantinedAt: time.Now(),
+		Activity:      activityBytes,
+	}
+
+	err = h.quarantineStore.Put(entry)
+	if err != nil {
+		return fmt.Errorf("quarantine activity [%s]: %w", activity.ID(), err)
+	}
+
+	h.logger.Infoc(ctx, "Inbound activity was quarantined by the inbox filter", logfields.WithActivityID(activity.ID()),
+		logfields.WithActorIRI(activity.Actor()), logfields.WithID(entry.ID))
+
+	return nil
+}
+
+func (h *Inbox) addToInbox(ctx context.Context, activity *vocab.ActivityType) {
 	// Don't return an error if we can't store the activity since we've already successfully processed the activity,
 	// and we don't want to reprocess the same message.
 	if e := h.activityStore.AddActivity(activity); e != nil {
@@ -262,8 +436,6 @@ func (h *Inbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, er
 		store.WithActivityType(activity.Type().Types()[0])); e != nil {
 		h.logger.Errorc(ctx, "Error adding reference to activity", log.WithError(e), logfields.WithActivityID(activity.ID()))
 	}
-
-	return activity, err
 }
 
 func (h *Inbox) unmarshalAndValidateActivity(msg *message.Message) (*vocab.ActivityType, error) {
@@ -21,14 +21,17 @@ import (
 	wmhttp "github.com/ThreeDotsLabs/watermill-http/pkg/http"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
 	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
 
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/filter"
 	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/httpsubscriber"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
+	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -38,6 +41,7 @@ import (
 	"github.com/trustbloc/orb/pkg/lifecycle"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
+	"github.com/trustbloc/orb/pkg/store/quarantine"
 )
 
 //go:generate counterfeiter -o ../mocks/activityhandler.gen.go --fake-name ActivityHandler ../spi ActivityHandler
@@ -630,6 +634,193 @@ func TestInbox_Error(t *testing.T) {
 	})
 }
 
+func TestInbox_ActivityFilter(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://example1.com/services/service1")
+	actorIRI := testutil.MustParseURL("https://example2.com/services/service2")
+
+	tm := &apmocks.AuthTokenMgr{}
+
+	newMsg := func(t *testing.T) (*vocab.ActivityType, *message.Message) {
+		t.Helper()
+
+		activity := vocab.NewFollowActivity(
+			vocab.NewObjectProperty(vocab.WithIRI(serviceIRI)),
+			vocab.WithID(newActivityID(actorIRI.String())),
+			vocab.WithActor(actorIRI),
+			vocab.WithTo(serviceIRI),
+		)
+
+		activityBytes, err := json.Marshal(activity)
+		require.NoError(t, err)
+
+		return activity, message.NewMessage(watermill.NewUUID(), activityBytes)
+	}
+
+	t.Run("Accept", func(t *testing.T) {
+		activityHandler := &mocks.ActivityHandler{}
+		activityStore := memstore.New("")
+
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, activityStore, mocks.NewPubSub(), activityHandler,
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+			WithActivityFilter(&mockActivityFilter{decision: &filter.Decision{Action: filter.ActionAccept}}),
+		)
+		require.NoError(t, err)
+
+		activity, msg := newMsg(t)
+
+		_, err = ib.handleActivityMsg(msg)
+		require.NoError(t, err)
+		require.Equal(t, 1, activityHandler.HandleActivityCallCount())
+
+		_, err = activityStore.GetActivity(activity.ID().URL())
+		require.NoError(t, err)
+
+		ctx, _, _ := activityHandler.HandleActivityArgsForCall(0)
+		require.Nil(t, service.VerifiedActorFromContext(ctx))
+	})
+
+	t.Run("Verified actor", func(t *testing.T) {
+		activityHandler := &mocks.ActivityHandler{}
+
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, memstore.New(""), mocks.NewPubSub(), activityHandler,
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+		)
+		require.NoError(t, err)
+
+		_, msg := newMsg(t)
+		msg.Metadata[httpsubscriber.ActorIRIKey] = actorIRI.String()
+
+		_, err = ib.handleActivityMsg(msg)
+		require.NoError(t, err)
+		require.Equal(t, 1, activityHandler.HandleActivityCallCount())
+
+		ctx, _, _ := activityHandler.HandleActivityArgsForCall(0)
+		require.Equal(t, actorIRI.String(), service.VerifiedActorFromContext(ctx).String())
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		activityHandler := &mocks.ActivityHandler{}
+		activityStore := memstore.New("")
+
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, activityStore, mocks.NewPubSub(), activityHandler,
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+			WithActivityFilter(&mockActivityFilter{decision: &filter.Decision{Action: filter.ActionReject}}),
+		)
+		require.NoError(t, err)
+
+		activity, msg := newMsg(t)
+
+		_, err = ib.handleActivityMsg(msg)
+		require.NoError(t, err)
+		require.Zero(t, activityHandler.HandleActivityCallCount())
+
+		_, err = activityStore.GetActivity(activity.ID().URL())
+		require.True(t, errors.Is(err, store.ErrNotFound))
+	})
+
+	t.Run("Quarantine and release", func(t *testing.T) {
+		activityHandler := &mocks.ActivityHandler{}
+		activityStore := memstore.New("")
+
+		qs, err := quarantine.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, activityStore, mocks.NewPubSub(), activityHandler,
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+			WithActivityFilter(&mockActivityFilter{
+				decision: &filter.Decision{Action: filter.ActionQuarantine, Reason: "suspicious"},
+			}),
+			WithQuarantineStore(qs),
+		)
+		require.NoError(t, err)
+
+		activity, msg := newMsg(t)
+
+		_, err = ib.handleActivityMsg(msg)
+		require.NoError(t, err)
+		require.Zero(t, activityHandler.HandleActivityCallCount())
+
+		entry, err := qs.Get(quarantine.ID(activity.ID().URL()))
+		require.NoError(t, err)
+		require.Equal(t, activity.ID().String(), entry.ActivityID)
+		require.Equal(t, actorIRI.String(), entry.Actor)
+		require.Equal(t, "suspicious", entry.Reason)
+
+		require.NoError(t, ib.ReleaseQuarantined(context.Background(), entry.ID))
+		require.Equal(t, 1, activityHandler.HandleActivityCallCount())
+
+		_, err = activityStore.GetActivity(activity.ID().URL())
+		require.NoError(t, err)
+
+		_, err = qs.Get(entry.ID)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		err = ib.ReleaseQuarantined(context.Background(), entry.ID)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+	})
+
+	t.Run("Release - handler error", func(t *testing.T) {
+		activityHandler := &mocks.ActivityHandler{}
+		activityHandler.HandleActivityReturns(errors.New("injected handler error"))
+
+		qs, err := quarantine.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, memstore.New(""), mocks.NewPubSub(), activityHandler,
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+			WithActivityFilter(&mockActivityFilter{decision: &filter.Decision{Action: filter.ActionQuarantine}}),
+			WithQuarantineStore(qs),
+		)
+		require.NoError(t, err)
+
+		activity, msg := newMsg(t)
+
+		_, err = ib.handleActivityMsg(msg)
+		require.NoError(t, err)
+
+		id := quarantine.ID(activity.ID().URL())
+
+		err = ib.ReleaseQuarantined(context.Background(), id)
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+
+		_, err = qs.Get(id)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+	})
+
+	t.Run("Quarantine - no store", func(t *testing.T) {
+		activityHandler := &mocks.ActivityHandler{}
+
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, memstore.New(""), mocks.NewPubSub(), activityHandler,
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+			WithActivityFilter(&mockActivityFilter{decision: &filter.Decision{Action: filter.ActionQuarantine}}),
+		)
+		require.NoError(t, err)
+
+		_, msg := newMsg(t)
+
+		_, err = ib.handleActivityMsg(msg)
+		require.NoError(t, err)
+		require.Zero(t, activityHandler.HandleActivityCallCount())
+
+		require.True(t, errors.Is(ib.ReleaseQuarantined(context.Background(), "123"), orberrors.ErrContentNotFound))
+	})
+
+	t.Run("Filter error", func(t *testing.T) {
+		ib, err := New(&Config{ServiceIRI: serviceIRI}, memstore.New(""), mocks.NewPubSub(), &mocks.ActivityHandler{},
+			&mocks.SignatureVerifier{}, tm, &orbmocks.MetricsProvider{},
+			WithActivityFilter(&mockActivityFilter{err: errors.New("injected filter error")}),
+		)
+		require.NoError(t, err)
+
+		_, msg := newMsg(t)
+
+		_, err = ib.handleActivityMsg(msg)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
 func TestUnmarshalAndValidateActivity(t *testing.T) {
 	activityID := testutil.MustParseURL("https://example1.com/activities/activity1")
 	actorIRI := testutil.MustParseURL("https://example1.com/services/service1")
@@ -749,3 +940,12 @@ func startHTTPServer(t *testing.T, listenAddress string, handlers ...common.HTTP
 		require.NoError(t, httpServer.Stop(context.Background()))
 	}
 }
+
+type mockActivityFilter struct {
+	decision *filter.Decision
+	err      error
+}
+
+func (m *mockActivityFilter) Evaluate(*vocab.ActivityType, int) (*filter.Decision, error) {
+	return m.decision, m.err
+}
@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimiter
+
+import (
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+var logger = log.New("inbox-rate-limiter")
+
+// Scope indicates which limit caused a request to be throttled.
+type Scope = string
+
+const (
+	// ScopeActor indicates that the per-actor limit was exceeded.
+	ScopeActor Scope = "actor"
+	// ScopeGlobal indicates that the global limit was exceeded.
+	ScopeGlobal Scope = "global"
+
+	// maxActorBuckets is the number of actor buckets after which idle buckets are purged.
+	maxActorBuckets = 10000
+)
+
+type metricsProvider interface {
+	InboxIncrementThrottledCount(scope string)
+}
+
+// Config holds the configuration parameters for the rate limiter. A rate of zero (or less) disables
+// the corresponding limit.
+type Config struct {
+	// ActorRate is the number of requests per second that a single actor may post to the inbox.
+	ActorRate float64
+	// ActorBurst is the maximum number of requests that a single actor may post in a burst. Defaults to 1 if not set.
+	ActorBurst int
+	// GlobalRate is the number of requests per second that may be posted to the inbox by all actors combined.
+	GlobalRate float64
+	// GlobalBurst is the maximum number of requests that may be posted in a burst by all actors combined.
+	// Defaults to 1 if not set.
+	GlobalBurst int
+}
+
+// Enabled returns true if either the per-actor or global limit is enabled.
+func (c *Config) Enabled() bool {
+	return c.ActorRate > 0 || c.GlobalRate > 0
+}
+
+// Limiter implements token-bucket rate limiting of inbox requests. A bucket is maintained for each actor
+// along with a global bucket that is shared by all actors.
+type Limiter struct {
+	*Config
+
+	metrics metricsProvider
+	now     func() time.Time
+
+	mutex  sync.Mutex
+	global *bucket
+	actors map[string]*bucket
+}
+
+// New returns a new rate limiter.
+func New(cfg Config, metrics metricsProvider) *Limiter {
+	if cfg.ActorBurst <= 0 {
+		cfg.ActorBurst = 1
+	}
+
+	if cfg.GlobalBurst <= 0 {
+		cfg.GlobalBurst = 1
+	}
+
+	l := &Limiter{
+		Config:  &cfg,
+		metrics: metrics,
+		now:     time.Now,
+		actors:  make(map[string]*bucket),
+	}
+
+	if cfg.GlobalRate > 0 {
+		l.global = newBucket(cfg.GlobalRate, cfg.GlobalBurst, l.now())
+	}
+
+	return l
+}
+
+// Allow returns true if a request from the given actor is allowed. If the request is not allowed then
+// the time after which the request may be retried is returned. The actor may be nil (for example, if the
+// request was authorized with a bearer token), in which case only the global limit is applied.
+func (l *Limiter) Allow(actor *url.URL) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+
+	var actorBucket *bucket
+
+	if actor != nil && l.ActorRate > 0 {
+		actorBucket = l.actorBucket(actor.String(), now)
+
+		if wait := actorBucket.wait(now); wait > 0 {
+			l.throttled(ScopeActor, actor)
+
+			return false, wait
+		}
+	}
+
+	if l.global != nil {
+		if wait := l.global.wait(now); wait > 0 {
+			l.throttled(ScopeGlobal, actor)
+
+			return false, wait
+		}
+
+		l.global.take()
+	}
+
+	if actorBucket != nil {
+		actorBucket.take()
+	}
+
+	return true, 0
+}
+
+func (l *Limiter) actorBucket(actor string, now time.Time) *bucket {
+	b, ok := l.actors[actor]
+	if ok {
+		return b
+	}
+
+	if len(l.actors) >= maxActorBuckets {
+		l.purgeIdleBuckets(now)
+	}
+
+	b = newBucket(l.ActorRate, l.ActorBurst, now)
+
+	l.actors[actor] = b
+
+	return b
+}
+
+// purgeIdleBuckets removes the buckets that have been refilled to capacity since they are
+// equivalent to new buckets.
+func (l *Limiter) purgeIdleBuckets(now time.Time) {
+	for actor, b := range l.actors {
+		b.refill(now)
+
+		if b.tokens >= b.capacity {
+			delete(l.actors, actor)
+		}
+	}
+
+	logger.Debug("Purged idle actor rate limit buckets", logfields.WithTotal(len(l.actors)))
+}
+
+func (l *Limiter) throttled(scope Scope, actor *url.URL) {
+	logger.Debug("Inbox request was throttled", logfields.WithType(scope), logfields.WithActorIRI(actor))
+
+	if l.metrics != nil {
+		l.metrics.InboxIncrementThrottledCount(scope)
+	}
+}
+
+type bucket struct {
+	rate       float64
+	capacity   float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newBucket(rate float64, burst int, now time.Time) *bucket {
+	return &bucket{
+		rate:       rate,
+		capacity:   float64(burst),
+		tokens:     float64(burst),
+		lastRefill: now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+	b.lastRefill = now
+}
+
+// wait refills the bucket and returns the time to wait until a token is available. Zero is returned if
+// a token is available now.
+func (b *bucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *bucket) take() {
+	b.tokens--
+}
@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	actor1 = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	actor2 = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+)
+
+func TestConfig_Enabled(t *testing.T) {
+	require.False(t, (&Config{}).Enabled())
+	require.True(t, (&Config{ActorRate: 1}).Enabled())
+	require.True(t, (&Config{GlobalRate: 1}).Enabled())
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Run("Actor limit", func(t *testing.T) {
+		m := &mockMetrics{counts: make(map[string]int)}
+
+		l := New(Config{ActorRate: 2, ActorBurst: 2}, m)
+
+		now := time.Now()
+		l.now = func() time.Time { return now }
+
+		for i := 0; i < 2; i++ {
+			allowed, _ := l.Allow(actor1)
+			require.True(t, allowed)
+		}
+
+		allowed, retryAfter := l.Allow(actor1)
+		require.False(t, allowed)
+		require.Equal(t, 500*time.Millisecond, retryAfter)
+		require.Equal(t, 1, m.counts[ScopeActor])
+
+		// Other actors are not affected.
+		allowed, _ = l.Allow(actor2)
+		require.True(t, allowed)
+
+		// No actor -> only the global limit applies.
+		allowed, _ = l.Allow(nil)
+		require.True(t, allowed)
+
+		now = now.Add(retryAfter)
+
+		allowed, _ = l.Allow(actor1)
+		require.True(t, allowed)
+	})
+
+	t.Run("Global limit", func(t *testing.T) {
+		m := &mockMetrics{counts: make(map[string]int)}
+
+		l := New(Config{ActorRate: 10, ActorBurst: 10, GlobalRate: 1}, m)
+
+		now := time.Now()
+		l.now = func() time.Time { return now }
+
+		allowed, _ := l.Allow(actor1)
+		require.True(t, allowed)
+
+		allowed, retryAfter := l.Allow(actor2)
+		require.False(t, allowed)
+		require.Equal(t, time.Second, retryAfter)
+		require.Equal(t, 1, m.counts[ScopeGlobal])
+
+		allowed, _ = l.Allow(nil)
+		require.False(t, allowed)
+		require.Equal(t, 2, m.counts[ScopeGlobal])
+
+		// The actor's token should not have been consumed when the global limit was exceeded.
+		require.Equal(t, float64(10), l.actors[actor2.String()].tokens)
+
+		now = now.Add(time.Second)
+
+		allowed, _ = l.Allow(actor2)
+		require.True(t, allowed)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		l := New(Config{}, nil)
+
+		for i := 0; i < 100; i++ {
+			allowed, _ := l.Allow(actor1)
+			require.True(t, allowed)
+		}
+
+		require.Empty(t, l.actors)
+	})
+}
+
+func TestLimiter_PurgeIdleBuckets(t *testing.T) {
+	l := New(Config{ActorRate: 1, ActorBurst: 1}, nil)
+
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	allowed, _ := l.Allow(actor1)
+	require.True(t, allowed)
+
+	now = now.Add(2 * time.Second)
+
+	allowed, _ = l.Allow(actor2)
+	require.True(t, allowed)
+
+	l.purgeIdleBuckets(now)
+
+	require.Len(t, l.actors, 1)
+	require.NotNil(t, l.actors[actor2.String()])
+}
+
+type mockMetrics struct {
+	counts map[string]int
+}
+
+func (m *mockMetrics) InboxIncrementThrottledCount(scope string) {
+	m.counts[scope]++
+}
@@ -22,6 +22,9 @@ type ActivityPubClient struct {
 	keys       map[string]*vocab.PublicKeyType
 	activities []*vocab.ActivityType
 	err        error
+	purgeErr   error
+	purged     []string
+	stale      []string
 }
 
 // NewActivitPubClient returns a mock ActivityPub client.
@@ -62,6 +65,23 @@ func (m *ActivityPubClient) WithError(err error) *ActivityPubClient {
 	return m
 }
 
+// WithPurgeError sets an error to be returned from PurgeActor and PurgePublicKey.
+func (m *ActivityPubClient) WithPurgeError(err error) *ActivityPubClient {
+	m.purgeErr = err
+
+	return m
+}
+
+// Purged returns the IRIs of the actors and public keys that were purged.
+func (m *ActivityPubClient) Purged() []string {
+	return m.purged
+}
+
+// Stale returns the IRIs of the actors that were marked as stale.
+func (m *ActivityPubClient) Stale() []string {
+	return m.stale
+}
+
 // GetPublicKey returns the public key for the given IRI.
 //
 //nolint:interfacer
@@ -94,6 +114,39 @@ func (m *ActivityPubClient) GetActor(actorIRI *url.URL) (*vocab.ActorType, error
 	return actor, nil
 }
 
+// MarkActorAsStale records the given actor IRI as stale.
+//
+//nolint:interfacer
+func (m *ActivityPubClient) MarkActorAsStale(actorIRI *url.URL) {
+	m.stale = append(m.stale, actorIRI.String())
+}
+
+// PurgeActor records the given actor IRI as purged.
+//
+//nolint:interfacer
+func (m *ActivityPubClient) PurgeActor(actorIRI *url.URL) error {
+	if m.purgeErr != nil {
+		return m.purgeErr
+	}
+
+	m.purged = append(m.purged, actorIRI.String())
+
+	return nil
+}
+
+// PurgePublicKey records the given public key IRI as purged.
+//
+//nolint:interfacer
+func (m *ActivityPubClient) PurgePublicKey(keyIRI *url.URL) error {
+	if m.purgeErr != nil {
+		return m.purgeErr
+	}
+
+	m.purged = append(m.purged, keyIRI.String())
+
+	return nil
+}
+
 // GetReferences simply returns an iterator that contains the IRI passed as an arg.
 func (m *ActivityPubClient) GetReferences(ctx context.Context, iri *url.URL) (client.ReferenceIterator, error) {
 	if m.err != nil {
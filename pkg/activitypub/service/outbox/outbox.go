@@ -9,7 +9,9 @@ package outbox
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
@@ -38,6 +40,7 @@ import (
 	"github.com/trustbloc/orb/pkg/pubsub"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
 	store2 "github.com/trustbloc/orb/pkg/store"
+	"github.com/trustbloc/orb/pkg/store/delivery"
 )
 
 const (
@@ -47,6 +50,12 @@ const (
 	defaultCacheSize              = 100
 	defaultCacheExpiration        = time.Minute
 	defaultSubscriberPoolSize     = 5
+
+	defaultMaxDeliveryAttempts       = 11
+	defaultRedeliveryInitialInterval = 2 * time.Second
+	defaultRedeliveryMultiplier      = 1.5
+	defaultMaxRedeliveryInterval     = 30 * time.Second
+	defaultParkedDeliveryInterval    = 30 * time.Second
 )
 
 type pubSub interface {
@@ -66,6 +75,21 @@ type Config struct {
 	CacheSize             int
 	CacheExpiration       time.Duration
 	SubscriberPoolSize    int
+
+	// MaxDeliveryAttempts is the maximum number of attempts to deliver an activity to a target inbox,
+	// after which the delivery is moved to the dead-letter collection.
+	MaxDeliveryAttempts int
+
+	// RedeliveryInitialInterval, RedeliveryMultiplier and MaxRedeliveryInterval should be set to the same values
+	// as the message queue's redelivery settings, so that the next retry time recorded in the delivery ledger
+	// reflects the time at which the message queue will redeliver the activity.
+	RedeliveryInitialInterval time.Duration
+	RedeliveryMultiplier      float64
+	MaxRedeliveryInterval     time.Duration
+
+	// ParkedDeliveryInterval is the interval at which parked deliveries (deliveries that weren't attempted since
+	// the circuit of the target was open) are checked and re-driven if the back-off period of the circuit expired.
+	ParkedDeliveryInterval time.Duration
 }
 
 type activityPubClient interface {
@@ -77,6 +101,57 @@ type resourceResolver interface {
 	ResolveHostMetaLink(uri, linkType string) (string, error)
 }
 
+// DeliveryStore persists the state of failed deliveries to target inboxes.
+type DeliveryStore interface {
+	Put(d *delivery.Delivery) error
+	Get(id string) (*delivery.Delivery, error)
+	Delete(id string) error
+	Query(status delivery.Status) ([]*delivery.Delivery, error)
+}
+
+// PeerHealthRegistry tracks the health of ActivityPub peers (keyed by actor IRI).
+type PeerHealthRegistry interface {
+	IsAvailable(iri *url.URL) bool
+	Allow(iri *url.URL) bool
+	RetryAfter(iri *url.URL) time.Time
+	RecordSuccess(iri *url.URL, latency time.Duration)
+	RecordFailure(iri *url.URL, err error)
+}
+
+// ActivitySigner adds a Linked Data proof to an activity.
+type ActivitySigner interface {
+	Sign(activity *vocab.ActivityType) (*vocab.ActivityType, error)
+}
+
+// Option is an outbox option.
+type Option func(ob *Outbox)
+
+// WithDeliveryStore sets the store in which failed deliveries are tracked. If not set
+// then failed deliveries are not tracked and the message queue's redelivery settings determine
+// when a delivery is abandoned.
+func WithDeliveryStore(s DeliveryStore) Option {
+	return func(ob *Outbox) {
+		ob.deliveryStore = s
+		ob.parkDeliveries = true
+	}
+}
+
+// WithPeerHealth sets the registry that tracks the health of peers. Activities are not delivered to
+// actors whose circuit is open. If not set then activities are always delivered.
+func WithPeerHealth(r PeerHealthRegistry) Option {
+	return func(ob *Outbox) {
+		ob.peerHealth = r
+	}
+}
+
+// WithActivitySigner sets the signer that embeds a Linked Data proof in each activity posted to the outbox
+// so that the activity may be verified independently of the transport. If not set then activities are not signed.
+func WithActivitySigner(s ActivitySigner) Option {
+	return func(ob *Outbox) {
+		ob.activitySigner = s
+	}
+}
+
 // Outbox implements the ActivityPub outbox.
 type Outbox struct {
 	*Config
@@ -87,6 +162,10 @@ type Outbox struct {
 	activityHandler  service.ActivityHandler
 	msgChan          <-chan *message.Message
 	activityStore    store.Store
+	deliveryStore    DeliveryStore
+	parkDeliveries   bool
+	peerHealth       PeerHealthRegistry
+	activitySigner   ActivitySigner
 	client           activityPubClient
 	resourceResolver resourceResolver
 	jsonMarshal      func(v interface{}) ([]byte, error)
@@ -97,6 +176,7 @@ type Outbox struct {
 	witnessesPath    string
 	logger           *log.Log
 	tracer           trace.Tracer
+	done             chan struct{}
 }
 
 type httpTransport interface {
@@ -112,7 +192,7 @@ type metricsProvider interface {
 
 // New returns a new ActivityPub Outbox.
 func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHandler service.ActivityHandler,
-	apClient activityPubClient, resourceResolver resourceResolver, metrics metricsProvider,
+	apClient activityPubClient, resourceResolver resourceResolver, metrics metricsProvider, opts ...Option,
 ) (*Outbox, error) {
 	cfg := populateConfigDefaults(cnfg)
 
@@ -129,6 +209,8 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 		Config:           &cfg,
 		activityHandler:  activityHandler,
 		activityStore:    s,
+		deliveryStore:    &noopDeliveryStore{},
+		peerHealth:       &noopPeerHealthRegistry{},
 		client:           apClient,
 		resourceResolver: resourceResolver,
 		publisher:        pubSub,
@@ -141,6 +223,11 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 		witnessesPath:    cfg.ServiceEndpointURL.String() + resthandler.WitnessesPath,
 		logger:           logger,
 		tracer:           tracing.Tracer(tracing.SubsystemActivityPub),
+		done:             make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(h)
 	}
 
 	h.Lifecycle = lifecycle.New(cfg.ServiceName,
@@ -161,9 +248,12 @@ func New(cnfg *Config, s store.Store, pubSub pubSub, t httpTransport, activityHa
 
 func (h *Outbox) start() {
 	go h.listen()
+	go h.redriveParked()
 }
 
 func (h *Outbox) stop() {
+	close(h.done)
+
 	h.logger.Info("Outbox stopped")
 }
 
@@ -193,6 +283,7 @@ type activityMessage struct {
 	TargetIRI   *vocab.URLProperty           `json:"target,omitempty"`
 	TargetIRIs  *vocab.URLCollectionProperty `json:"targets,omitempty"`
 	ExcludeIRIs *vocab.URLCollectionProperty `json:"exclude,omitempty"`
+	ActorIRIs   *vocab.URLCollectionProperty `json:"actors,omitempty"`
 }
 
 // Post posts an activity to the outbox and returns the ID of the activity that was posted.
@@ -216,6 +307,11 @@ func (h *Outbox) Post(ctx context.Context, activity *vocab.ActivityType, exclude
 		return nil, err
 	}
 
+	activity, err = h.signActivity(activity)
+	if err != nil {
+		return nil, err
+	}
+
 	spanCtx, span := h.tracer.Start(ctx, fmt.Sprintf("outbox post %s activity", activity.Type()),
 		trace.WithAttributes(
 			tracing.ActivityIDAttribute(activity.ID().String()),
@@ -301,7 +397,8 @@ func (h *Outbox) handleActivityMsg(msg *message.Message) (*vocab.ActivityType, e
 		h.logger.Debugc(ctx, "Handling 'deliver' activity message", logfields.WithMessageID(msg.UUID),
 			logfields.WithActivityID(activityMsg.Activity.ID()), logfields.WithTargetIRI(activityMsg.TargetIRI))
 
-		if err := h.sendActivity(ctx, activityMsg.Activity, activityMsg.TargetIRI.URL()); err != nil {
+		err := h.deliverActivity(ctx, activityMsg.Activity, activityMsg.TargetIRI.URL(), activityMsg.ActorIRIs.URLs())
+		if err != nil {
 			return nil, fmt.Errorf("handle 'deliver' message for activity [%s] of type [%s] to [%s]: %w",
 				activityMsg.Activity.ID(), activityMsg.Activity.Type(), activityMsg.TargetIRI, err)
 		}
@@ -327,7 +424,7 @@ func (h *Outbox) handleBroadcast(ctx context.Context, activity *vocab.ActivityTy
 	for _, r := range h.resolveInboxes(activity.To(), excludeIRIs) {
 		switch {
 		case r.err == nil:
-			if err := h.publishDeliverMessage(ctx, activity, r.iri); err != nil {
+			if err := h.publishDeliverMessage(ctx, activity, r.iri, r.actorIRIs...); err != nil {
 				// Return with an error since the only time publishToTarget returns an error is if
 				// there's something wrong with the local server. (Maybe it's being shut down.)
 				return fmt.Errorf("unable to publish activity to inbox %s: %w", r.iri, err)
@@ -352,19 +449,39 @@ func (h *Outbox) handleResolveIRIs(ctx context.Context, activity *vocab.Activity
 	h.logger.Debugc(ctx, "Resolving inboxes from [%s] for activity [%s]",
 		logfields.WithTargetIRI(toIRI), logfields.WithActivityID(activity.ID()))
 
-	for _, r := range h.resolveInboxes([]*url.URL{toIRI}, excludeIRIs) {
-		if r.err != nil {
+	responses := h.resolveInboxes([]*url.URL{toIRI}, excludeIRIs)
+
+	// If the target itself couldn't be resolved then nothing was delivered and the message is retried.
+	for _, r := range responses {
+		if r.err != nil && r.iri.String() == toIRI.String() {
 			h.logger.Warnc(ctx, "Error resolving inbox.",
 				logfields.WithTargetIRI(r.iri), log.WithError(r.err),
 				zap.Bool("is-transient-error", orberrors.IsTransient(r.err)))
 
 			return fmt.Errorf("resolve inbox [%s]: %w", r.iri, r.err)
 		}
+	}
+
+	// The target resolved to a number of actors (e.g. followers). Errors are handled per actor so that
+	// the inboxes that were resolved successfully aren't delivered to again when an actor is retried.
+	for _, r := range responses {
+		switch {
+		case r.err == nil:
+			if err := h.publishDeliverMessage(ctx, activity, r.iri, r.actorIRIs...); err != nil {
+				// Return with an error since the only time publishToTarget returns an error is if
+				// there's something wrong with the local server. (Maybe it's being shut down.)
+				return fmt.Errorf("unable to publish activity to inbox %s: %w", r.iri, err)
+			}
+		case orberrors.IsTransient(r.err):
+			h.logger.Warnc(ctx, "Transient error resolving inbox. IRI will be retried.",
+				logfields.WithTargetIRI(r.iri), log.WithError(r.err))
 
-		if err := h.publishDeliverMessage(ctx, activity, r.iri); err != nil {
-			// Return with an error since the only time publishToTarget returns an error is if
-			// there's something wrong with the local server. (Maybe it's being shut down.)
-			return fmt.Errorf("unable to publish activity to inbox %s: %w", r.iri, err)
+			if err := h.publishResolveAndDeliverMessage(ctx, activity, r.iri, excludeIRIs); err != nil {
+				return fmt.Errorf("unable to publish activity for resolve %s: %w", r.iri, err)
+			}
+		default:
+			h.logger.Errorc(ctx, "Persistent error resolving inbox. IRI will be ignored.",
+				log.WithError(r.err), logfields.WithTargetIRI(r.iri))
 		}
 	}
 
@@ -434,11 +551,14 @@ func (h *Outbox) publishResolveAndDeliverMessage(ctx context.Context,
 	return h.publisher.Publish(h.Topic, msg)
 }
 
-func (h *Outbox) publishDeliverMessage(ctx context.Context, activity *vocab.ActivityType, target *url.URL) error {
+func (h *Outbox) publishDeliverMessage(ctx context.Context, activity *vocab.ActivityType, target *url.URL,
+	actorIRIs ...*url.URL,
+) error {
 	activityMsg := &activityMessage{
 		Type:      deliverType,
 		Activity:  activity,
 		TargetIRI: vocab.NewURLProperty(target),
+		ActorIRIs: vocab.NewURLCollectionProperty(actorIRIs...),
 	}
 
 	msgBytes, err := h.jsonMarshal(activityMsg)
@@ -474,19 +594,26 @@ func (h *Outbox) resolveInboxes(toIRIs, excludeIRIs []*url.URL) []*resolveIRIRes
 		}
 	}
 
-	return append(responses, h.resolveIRIs(
+	return append(responses, h.collapseInboxes(h.resolveIRIs(
 		deduplicateAndFilter(actorIRIs, excludeIRIs),
 		func(iri *url.URL) []*resolveIRIResponse {
+			if !h.peerHealth.IsAvailable(iri) {
+				return []*resolveIRIResponse{{iri: iri, err: orberrors.NewTransientf("circuit is open for actor [%s]", iri)}}
+			}
+
 			inboxIRI, err := h.resolveInbox(iri)
 			if err != nil {
 				return []*resolveIRIResponse{{iri: iri, err: err}}
 			}
 
-			return []*resolveIRIResponse{{iri: inboxIRI}}
+			return []*resolveIRIResponse{{iri: inboxIRI, actorIRIs: []*url.URL{iri}}}
 		},
-	)...)
+	))...)
 }
 
+// resolveInbox returns the inbox to which activities for the given actor should be delivered. If the actor
+// specifies a shared inbox then the shared inbox is returned so that a single delivery is made to all actors
+// that share the inbox. An error is returned if the actor specifies neither a shared inbox nor an inbox.
 func (h *Outbox) resolveInbox(iri *url.URL) (*url.URL, error) {
 	h.logger.Debug("Retrieving actor", logfields.WithActorIRI(iri))
 
@@ -495,7 +622,44 @@ func (h *Outbox) resolveInbox(iri *url.URL) (*url.URL, error) {
 		return nil, err
 	}
 
-	return actor.Inbox(), nil
+	if sharedInbox := actor.SharedInbox(); sharedInbox != nil {
+		h.logger.Debug("Using shared inbox of actor", logfields.WithActorIRI(iri), logfields.WithTargetIRI(sharedInbox))
+
+		return sharedInbox, nil
+	}
+
+	inbox := actor.Inbox()
+	if inbox == nil {
+		return nil, fmt.Errorf("actor [%s] has no inbox", iri)
+	}
+
+	return inbox, nil
+}
+
+// collapseInboxes removes duplicate inboxes from the given responses so that actors which share an inbox
+// (typically all actors on the same domain) receive a single delivery. Error responses are left untouched.
+func (h *Outbox) collapseInboxes(responses []*resolveIRIResponse) []*resolveIRIResponse {
+	var collapsed []*resolveIRIResponse
+
+	inboxes := make(map[string]*resolveIRIResponse)
+
+	for _, r := range responses {
+		if r.err == nil {
+			if existing, exists := inboxes[r.iri.String()]; exists {
+				h.logger.Debug("Collapsing delivery to shared inbox", logfields.WithTargetIRI(r.iri))
+
+				existing.actorIRIs = append(existing.actorIRIs, r.actorIRIs...)
+
+				continue
+			}
+
+			inboxes[r.iri.String()] = r
+		}
+
+		collapsed = append(collapsed, r)
+	}
+
+	return collapsed
 }
 
 func (h *Outbox) resolveActorIRIs(iri *url.URL) []*resolveIRIResponse {
@@ -536,7 +700,7 @@ func (h *Outbox) doResolveActorIRIs(iri *url.URL) []*resolveIRIResponse {
 		var responses []*resolveIRIResponse
 
 		for _, r := range resolvedIRIs {
-			if strings.HasPrefix(r.String(), h.ServiceEndpointURL.String()) {
+			if isLocalEndpoint(r, h.ServiceEndpointURL) {
 				// Ignore local endpoint.
 				continue
 			}
@@ -548,9 +712,17 @@ func (h *Outbox) doResolveActorIRIs(iri *url.URL) []*resolveIRIResponse {
 	}
 }
 
+// isLocalEndpoint returns true if the given IRI is the service endpoint or a path below it.
+func isLocalEndpoint(iri, serviceEndpoint *url.URL) bool {
+	endpoint := strings.TrimSuffix(serviceEndpoint.String(), "/")
+
+	return iri.String() == endpoint || strings.HasPrefix(iri.String(), endpoint+"/")
+}
+
 type resolveIRIResponse struct {
-	iri *url.URL
-	err error
+	iri       *url.URL
+	err       error
+	actorIRIs []*url.URL
 }
 
 func (h *Outbox) resolveReferences(refType store.ReferenceType) ([]*resolveIRIResponse, error) {
@@ -704,12 +876,327 @@ func (h *Outbox) validateAndPopulateActivity(activity *vocab.ActivityType) (*voc
 	return activity, nil
 }
 
+func (h *Outbox) signActivity(activity *vocab.ActivityType) (*vocab.ActivityType, error) {
+	if h.activitySigner == nil {
+		return activity, nil
+	}
+
+	signedActivity, err := h.activitySigner.Sign(activity)
+	if err != nil {
+		return nil, fmt.Errorf("sign activity [%s]: %w", activity.ID(), err)
+	}
+
+	return signedActivity, nil
+}
+
 func (h *Outbox) incrementCount(types []vocab.Type) {
 	for _, activityType := range types {
 		h.metrics.OutboxIncrementActivityCount(string(activityType))
 	}
 }
 
+// Redeliver re-publishes the activity of the given failed (dead-letter) delivery to its target inbox.
+// The number of attempts for the delivery is reset.
+func (h *Outbox) Redeliver(ctx context.Context, deliveryID string) error {
+	if h.State() != lifecycle.StateStarted {
+		return lifecycle.ErrNotStarted
+	}
+
+	d, err := h.deliveryStore.Get(deliveryID)
+	if err != nil {
+		return fmt.Errorf("get delivery [%s]: %w", deliveryID, err)
+	}
+
+	if d.Status != delivery.StatusFailed {
+		return orberrors.NewBadRequestf("delivery [%s] has status [%s] and cannot be redelivered",
+			deliveryID, d.Status)
+	}
+
+	activityID, err := url.Parse(d.ActivityID)
+	if err != nil {
+		return fmt.Errorf("parse activity ID [%s]: %w", d.ActivityID, err)
+	}
+
+	target, err := url.Parse(d.Target)
+	if err != nil {
+		return fmt.Errorf("parse target [%s]: %w", d.Target, err)
+	}
+
+	activity, err := h.activityStore.GetActivity(activityID)
+	if err != nil {
+		return fmt.Errorf("get activity [%s]: %w", activityID, err)
+	}
+
+	d.Status = delivery.StatusPending
+	d.Attempts = 0
+	d.NextRetry = time.Now()
+
+	err = h.deliveryStore.Put(d)
+	if err != nil {
+		return fmt.Errorf("update delivery [%s]: %w", deliveryID, err)
+	}
+
+	h.logger.Infoc(ctx, "Redelivering activity to target", logfields.WithID(deliveryID),
+		logfields.WithActivityID(activityID), logfields.WithTargetIRI(target))
+
+	return h.publishDeliverMessage(ctx, activity, target)
+}
+
+// deliverActivity sends the activity to the given target and updates the delivery ledger with the outcome.
+func (h *Outbox) deliverActivity(ctx context.Context, activity *vocab.ActivityType, target *url.URL,
+	actorIRIs []*url.URL,
+) error {
+	deliveryID := delivery.ID(activity.ID().URL(), target)
+
+	if !h.isAnyPeerAvailable(actorIRIs) {
+		return h.parkDelivery(ctx, deliveryID, activity, target, actorIRIs)
+	}
+
+	startTime := time.Now()
+
+	err := h.sendActivity(ctx, activity, target)
+	if err != nil {
+		if orberrors.IsTransient(err) {
+			for _, actorIRI := range actorIRIs {
+				h.peerHealth.RecordFailure(actorIRI, err)
+			}
+		}
+
+		return h.handleFailedDelivery(ctx, deliveryID, activity, target, err)
+	}
+
+	latency := time.Since(startTime)
+
+	for _, actorIRI := range actorIRIs {
+		h.peerHealth.RecordSuccess(actorIRI, latency)
+	}
+
+	if e := h.deliveryStore.Delete(deliveryID); e != nil {
+		h.logger.Warnc(ctx, "Error deleting delivery record", logfields.WithID(deliveryID), log.WithError(e))
+	}
+
+	return nil
+}
+
+// isAnyPeerAvailable returns true if no actors are specified or if a request is allowed to at least one
+// of the given actors. If the circuit of the actor is half-open then the delivery is the trial request.
+func (h *Outbox) isAnyPeerAvailable(actorIRIs []*url.URL) bool {
+	if len(actorIRIs) == 0 {
+		return true
+	}
+
+	for _, actorIRI := range actorIRIs {
+		if h.peerHealth.Allow(actorIRI) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parkDelivery records a delivery that wasn't attempted since the circuit is open for all of the target's actors.
+// The delivery is re-driven after the back-off period of the circuit expires and the attempt isn't counted, so
+// a peer that is down for longer than the message queue's redelivery window isn't moved to the dead-letter
+// collection without a request ever having been sent. A transient error is returned if no delivery store is
+// configured or if the delivery can't be parked so that the message queue redelivers the message.
+func (h *Outbox) parkDelivery(ctx context.Context, deliveryID string, activity *vocab.ActivityType,
+	target *url.URL, actorIRIs []*url.URL,
+) error {
+	if !h.parkDeliveries {
+		// Parked deliveries can't be tracked so let the message queue redeliver the message.
+		return orberrors.NewTransientf("circuit is open for all actors of inbox [%s]", target)
+	}
+
+	d, err := h.deliveryStore.Get(deliveryID)
+	if err != nil {
+		if !errors.Is(err, orberrors.ErrContentNotFound) {
+			return fmt.Errorf("get delivery record [%s]: %w", deliveryID, err)
+		}
+
+		d = &delivery.Delivery{
+			ID:           deliveryID,
+			ActivityID:   activity.ID().String(),
+			ActivityType: activity.Type().String(),
+			Target:       target.String(),
+		}
+	}
+
+	d.Status = delivery.StatusParked
+	d.ActorIRIs = toStrings(actorIRIs)
+	d.LastError = fmt.Sprintf("circuit is open for all actors of inbox [%s]", target)
+	d.NextRetry = h.retryAfter(actorIRIs)
+
+	if err := h.deliveryStore.Put(d); err != nil {
+		return orberrors.NewTransientf("park delivery [%s]: %w", deliveryID, err)
+	}
+
+	h.logger.Infoc(ctx, "Circuit is open for all actors of the target. The delivery is parked.",
+		logfields.WithID(deliveryID), logfields.WithActivityID(activity.ID()), logfields.WithTargetIRI(target),
+		logfields.WithBackoff(time.Until(d.NextRetry)))
+
+	return nil
+}
+
+// retryAfter returns the earliest time at which the circuit of one of the given actors may allow a request.
+func (h *Outbox) retryAfter(actorIRIs []*url.URL) time.Time {
+	var retryAfter time.Time
+
+	for _, actorIRI := range actorIRIs {
+		t := h.peerHealth.RetryAfter(actorIRI)
+		if t.IsZero() {
+			// The back-off period has expired but a trial request is in flight.
+			t = time.Now().Add(h.ParkedDeliveryInterval)
+		}
+
+		if retryAfter.IsZero() || t.Before(retryAfter) {
+			retryAfter = t
+		}
+	}
+
+	return retryAfter
+}
+
+func (h *Outbox) redriveParked() {
+	ticker := time.NewTicker(h.ParkedDeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.redriveParkedDeliveries()
+		case <-h.done:
+			h.logger.Debug("Parked delivery re-drive stopped")
+
+			return
+		}
+	}
+}
+
+// redriveParkedDeliveries re-publishes the parked deliveries whose retry time has passed.
+func (h *Outbox) redriveParkedDeliveries() {
+	deliveries, err := h.deliveryStore.Query(delivery.StatusParked)
+	if err != nil {
+		h.logger.Warn("Error querying parked deliveries", log.WithError(err))
+
+		return
+	}
+
+	now := time.Now()
+
+	for _, d := range deliveries {
+		if now.Before(d.NextRetry) {
+			continue
+		}
+
+		if err := h.redrive(d); err != nil {
+			h.logger.Warn("Error re-driving parked delivery. The delivery will be retried.",
+				logfields.WithID(d.ID), log.WithError(err))
+		}
+	}
+}
+
+func (h *Outbox) redrive(d *delivery.Delivery) error {
+	activityID, err := url.Parse(d.ActivityID)
+	if err != nil {
+		return fmt.Errorf("parse activity ID [%s]: %w", d.ActivityID, err)
+	}
+
+	target, err := url.Parse(d.Target)
+	if err != nil {
+		return fmt.Errorf("parse target [%s]: %w", d.Target, err)
+	}
+
+	actorIRIs, err := toURLs(d.ActorIRIs)
+	if err != nil {
+		return fmt.Errorf("parse actor IRIs: %w", err)
+	}
+
+	activity, err := h.activityStore.GetActivity(activityID)
+	if err != nil {
+		return fmt.Errorf("get activity [%s]: %w", activityID, err)
+	}
+
+	d.Status = delivery.StatusPending
+	d.NextRetry = time.Now()
+
+	if err := h.deliveryStore.Put(d); err != nil {
+		return fmt.Errorf("update delivery [%s]: %w", d.ID, err)
+	}
+
+	h.logger.Debug("Re-driving parked delivery", logfields.WithID(d.ID),
+		logfields.WithActivityID(activityID), logfields.WithTargetIRI(target))
+
+	return h.publishDeliverMessage(context.Background(), activity, target, actorIRIs...)
+}
+
+// handleFailedDelivery records the failed delivery attempt. If the error is persistent or if the maximum
+// number of attempts has been reached then the delivery is moved to the dead-letter collection and a
+// persistent error is returned so that the message is not redelivered.
+func (h *Outbox) handleFailedDelivery(ctx context.Context, deliveryID string, activity *vocab.ActivityType,
+	target *url.URL, deliveryErr error,
+) error {
+	d, err := h.deliveryStore.Get(deliveryID)
+	if err != nil {
+		if !errors.Is(err, orberrors.ErrContentNotFound) {
+			h.logger.Warnc(ctx, "Error retrieving delivery record", logfields.WithID(deliveryID), log.WithError(err))
+
+			return deliveryErr
+		}
+
+		d = &delivery.Delivery{
+			ID:           deliveryID,
+			ActivityID:   activity.ID().String(),
+			ActivityType: activity.Type().String(),
+			Target:       target.String(),
+		}
+	}
+
+	d.Attempts++
+	d.LastError = deliveryErr.Error()
+	d.LastAttempt = time.Now()
+
+	if orberrors.IsTransient(deliveryErr) && d.Attempts < h.MaxDeliveryAttempts {
+		d.Status = delivery.StatusPending
+		d.NextRetry = d.LastAttempt.Add(h.redeliveryInterval(d.Attempts))
+
+		if e := h.deliveryStore.Put(d); e != nil {
+			h.logger.Warnc(ctx, "Error storing delivery record", logfields.WithID(deliveryID), log.WithError(e))
+		}
+
+		return deliveryErr
+	}
+
+	d.Status = delivery.StatusFailed
+	d.NextRetry = time.Time{}
+
+	if e := h.deliveryStore.Put(d); e != nil {
+		h.logger.Warnc(ctx, "Error storing delivery record", logfields.WithID(deliveryID), log.WithError(e))
+	}
+
+	h.logger.Errorc(ctx, "Activity could not be delivered to target and was added to the dead-letter collection",
+		logfields.WithID(deliveryID), logfields.WithActivityID(activity.ID()), logfields.WithTargetIRI(target),
+		logfields.WithDeliveryAttempts(d.Attempts), log.WithError(deliveryErr))
+
+	// Return a persistent error so that the message is not redelivered.
+	return fmt.Errorf("delivery failed after %d attempt(s): %s", d.Attempts, deliveryErr)
+}
+
+// redeliveryInterval returns the delay until the message queue redelivers a message that has been
+// delivered the given number of times. The first redelivery is immediate.
+func (h *Outbox) redeliveryInterval(attempts int) time.Duration {
+	if attempts <= 1 {
+		return 0
+	}
+
+	interval := time.Duration(float64(h.RedeliveryInitialInterval) * math.Pow(h.RedeliveryMultiplier, float64(attempts-2)))
+
+	if interval > h.MaxRedeliveryInterval {
+		interval = h.MaxRedeliveryInterval
+	}
+
+	return interval
+}
+
 func (h *Outbox) sendActivity(ctx context.Context, activity *vocab.ActivityType, target *url.URL) error {
 	h.logger.Debugc(ctx, "Sending activity to target", logfields.WithActivityID(activity.ID()), logfields.WithTargetIRI(target))
 
@@ -779,9 +1266,54 @@ func populateConfigDefaults(cnfg *Config) Config {
 		cfg.SubscriberPoolSize = defaultSubscriberPoolSize
 	}
 
+	if cfg.MaxDeliveryAttempts <= 0 {
+		cfg.MaxDeliveryAttempts = defaultMaxDeliveryAttempts
+	}
+
+	if cfg.RedeliveryInitialInterval == 0 {
+		cfg.RedeliveryInitialInterval = defaultRedeliveryInitialInterval
+	}
+
+	if cfg.RedeliveryMultiplier == 0 {
+		cfg.RedeliveryMultiplier = defaultRedeliveryMultiplier
+	}
+
+	if cfg.MaxRedeliveryInterval == 0 {
+		cfg.MaxRedeliveryInterval = defaultMaxRedeliveryInterval
+	}
+
+	if cfg.ParkedDeliveryInterval <= 0 {
+		cfg.ParkedDeliveryInterval = defaultParkedDeliveryInterval
+	}
+
 	return cfg
 }
 
+func toStrings(iris []*url.URL) []string {
+	values := make([]string, len(iris))
+
+	for i, iri := range iris {
+		values[i] = iri.String()
+	}
+
+	return values
+}
+
+func toURLs(values []string) ([]*url.URL, error) {
+	iris := make([]*url.URL, len(values))
+
+	for i, v := range values {
+		iri, err := url.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("parse IRI [%s]: %w", v, err)
+		}
+
+		iris[i] = iri
+	}
+
+	return iris, nil
+}
+
 func deduplicateAndFilter(toIRIs, excludeIRIs []*url.URL) []*url.URL {
 	m := make(map[string]struct{})
 
@@ -808,3 +1340,39 @@ func contains(arr []*url.URL, u *url.URL) bool {
 
 	return false
 }
+
+type noopDeliveryStore struct{}
+
+func (s *noopDeliveryStore) Put(*delivery.Delivery) error {
+	return nil
+}
+
+func (s *noopDeliveryStore) Get(string) (*delivery.Delivery, error) {
+	return nil, orberrors.ErrContentNotFound
+}
+
+func (s *noopDeliveryStore) Delete(string) error {
+	return nil
+}
+
+func (s *noopDeliveryStore) Query(delivery.Status) ([]*delivery.Delivery, error) {
+	return nil, nil
+}
+
+type noopPeerHealthRegistry struct{}
+
+func (r *noopPeerHealthRegistry) IsAvailable(*url.URL) bool {
+	return true
+}
+
+func (r *noopPeerHealthRegistry) Allow(*url.URL) bool {
+	return true
+}
+
+func (r *noopPeerHealthRegistry) RetryAfter(*url.URL) time.Time {
+	return time.Time{}
+}
+
+func (r *noopPeerHealthRegistry) RecordSuccess(*url.URL, time.Duration) {}
+
+func (r *noopPeerHealthRegistry) RecordFailure(*url.URL, error) {}
@@ -13,14 +13,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/verifier"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
@@ -28,6 +31,7 @@ import (
 
 	"github.com/trustbloc/orb/pkg/activitypub/client"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
@@ -40,6 +44,7 @@ import (
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/lifecycle"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/store/delivery"
 )
 
 //go:generate counterfeiter -o ../mocks/referenceiterator.gen.go --fake-name ReferenceIterator ./../../client ReferenceIterator
@@ -580,7 +585,9 @@ func TestOutbox_HandleActivityMessage(t *testing.T) {
 		msg := message.NewMessage(watermill.NewUUID(), msgBytes)
 
 		t.Run("success", func(t *testing.T) {
-			apClient := mocks.NewActivitPubClient().WithActor(vocab.NewService(service2URL))
+			apClient := mocks.NewActivitPubClient().WithActor(vocab.NewService(service2URL,
+				vocab.WithInbox(testutil.NewMockID(service2URL, resthandler.InboxPath)),
+			))
 
 			ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
 				&mocks.ActivityHandler{}, apClient, &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{})
@@ -593,6 +600,21 @@ func TestOutbox_HandleActivityMessage(t *testing.T) {
 			require.Equal(t, activity.ID().String(), a.ID().String())
 		})
 
+		t.Run("actor has no inbox", func(t *testing.T) {
+			apClient := mocks.NewActivitPubClient().WithActor(vocab.NewService(service2URL))
+
+			ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
+				&mocks.ActivityHandler{}, apClient, &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{})
+			require.NoError(t, err)
+			require.NotNil(t, ob)
+
+			a, err := ob.handleActivityMsg(msg)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "has no inbox")
+			require.False(t, orberrors.IsTransient(err))
+			require.Nil(t, a)
+		})
+
 		t.Run("resolve error", func(t *testing.T) {
 			t.Run("transient error", func(t *testing.T) {
 				errExpected := orberrors.NewTransientf("injected resolver transient error")
@@ -685,6 +707,426 @@ func TestOutbox_HandleActivityMessage(t *testing.T) {
 	})
 }
 
+func TestOutbox_SharedInbox(t *testing.T) {
+	service1URL := testutil.MustParseURL("https://domain1.com/services/orb")
+	service2URL := testutil.MustParseURL("https://domain2.com/services/tenant1")
+	service3URL := testutil.MustParseURL("https://domain2.com/services/tenant2")
+	service4URL := testutil.MustParseURL("https://domain3.com/services/orb")
+
+	sharedInbox := testutil.MustParseURL("https://domain2.com/services/sharedinbox")
+	inbox4 := testutil.NewMockID(service4URL, resthandler.InboxPath)
+
+	apClient := mocks.NewActivitPubClient().
+		WithActor(vocab.NewService(service2URL,
+			vocab.WithInbox(testutil.NewMockID(service2URL, resthandler.InboxPath)),
+			vocab.WithSharedInbox(sharedInbox),
+		)).
+		WithActor(vocab.NewService(service3URL,
+			vocab.WithInbox(testutil.NewMockID(service3URL, resthandler.InboxPath)),
+			vocab.WithSharedInbox(sharedInbox),
+		)).
+		WithActor(vocab.NewService(service4URL,
+			vocab.WithInbox(inbox4),
+		))
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1URL,
+		ServiceEndpointURL: service1URL,
+		Topic:              "activities",
+	}
+
+	ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, apClient, &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{})
+	require.NoError(t, err)
+
+	t.Run("Resolve inbox", func(t *testing.T) {
+		iri, err := ob.resolveInbox(service2URL)
+		require.NoError(t, err)
+		require.Equal(t, sharedInbox.String(), iri.String())
+
+		iri, err = ob.resolveInbox(service4URL)
+		require.NoError(t, err)
+		require.Equal(t, inbox4.String(), iri.String())
+	})
+
+	t.Run("Collapse inboxes", func(t *testing.T) {
+		errExpected := errors.New("injected resolve error")
+
+		responses := ob.collapseInboxes(ob.resolveIRIs(
+			[]*url.URL{service2URL, service3URL, service4URL},
+			func(iri *url.URL) []*resolveIRIResponse {
+				inboxIRI, err := ob.resolveInbox(iri)
+				require.NoError(t, err)
+
+				return []*resolveIRIResponse{{iri: inboxIRI, actorIRIs: []*url.URL{iri}}}
+			},
+		))
+		require.Len(t, responses, 2)
+
+		for _, r := range responses {
+			if r.iri.String() == sharedInbox.String() {
+				require.Len(t, r.actorIRIs, 2)
+			} else {
+				require.Len(t, r.actorIRIs, 1)
+			}
+		}
+
+		responses = ob.collapseInboxes([]*resolveIRIResponse{
+			{iri: sharedInbox},
+			{iri: service2URL, err: errExpected},
+			{iri: service2URL, err: errExpected},
+			{iri: sharedInbox},
+		})
+		require.Len(t, responses, 3)
+	})
+
+	t.Run("Local endpoint", func(t *testing.T) {
+		require.True(t, isLocalEndpoint(service1URL, service1URL))
+		require.True(t, isLocalEndpoint(testutil.NewMockID(service1URL, resthandler.InboxPath), service1URL))
+		require.False(t, isLocalEndpoint(testutil.MustParseURL("https://domain1.com/services/orb2"), service1URL))
+	})
+}
+
+func TestOutbox_PeerHealth(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+	service2URL := testutil.MustParseURL("http://localhost:8003/services/service2")
+
+	var numRequests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&numRequests, 1)
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	targetURL := testutil.MustParseURL(server.URL + "/services/service2/inbox")
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://example.com/transactions/txn1"))),
+		vocab.WithID(aptestutil.NewActivityID(service1URL)),
+		vocab.WithActor(service1URL),
+		vocab.WithTo(service2URL),
+	)
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1URL,
+		ServiceEndpointURL: service1URL,
+		Topic:              "outbox",
+	}
+
+	registry := peerhealth.New(peerhealth.Config{FailureThreshold: 1, InitialBackoff: time.Minute})
+
+	ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{},
+		WithPeerHealth(registry))
+	require.NoError(t, err)
+
+	err = ob.deliverActivity(context.Background(), activity, targetURL, []*url.URL{service2URL})
+	require.Error(t, err)
+	require.True(t, orberrors.IsTransient(err))
+	require.Equal(t, int32(1), atomic.LoadInt32(&numRequests))
+
+	health := registry.Get(service2URL)
+	require.NotNil(t, health)
+	require.Equal(t, peerhealth.StateOpen, health.State)
+
+	// The circuit is open so the activity should not be sent.
+	err = ob.deliverActivity(context.Background(), activity, targetURL, []*url.URL{service2URL})
+	require.Error(t, err)
+	require.True(t, orberrors.IsTransient(err))
+	require.Contains(t, err.Error(), "circuit is open")
+	require.Equal(t, int32(1), atomic.LoadInt32(&numRequests))
+
+	t.Run("Parked delivery", func(t *testing.T) {
+		activityStore := memstore.New("service1")
+		require.NoError(t, activityStore.AddActivity(activity))
+
+		deliveryStore, err := delivery.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		ob, err := New(cfg, activityStore, mocks.NewPubSub(), transport.Default(),
+			&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{},
+			WithPeerHealth(registry), WithDeliveryStore(deliveryStore))
+		require.NoError(t, err)
+
+		deliveryID := delivery.ID(activity.ID().URL(), targetURL)
+
+		// The circuit is open so the delivery should be parked without counting an attempt.
+		for i := 0; i < 3; i++ {
+			require.NoError(t, ob.deliverActivity(context.Background(), activity, targetURL, []*url.URL{service2URL}))
+		}
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&numRequests))
+
+		d, err := deliveryStore.Get(deliveryID)
+		require.NoError(t, err)
+		require.Equal(t, delivery.StatusParked, d.Status)
+		require.Equal(t, 0, d.Attempts)
+		require.Equal(t, []string{service2URL.String()}, d.ActorIRIs)
+		require.True(t, registry.RetryAfter(service2URL).Equal(d.NextRetry))
+
+		// The back-off period hasn't expired so the delivery should not be re-driven.
+		ob.redriveParkedDeliveries()
+
+		d, err = deliveryStore.Get(deliveryID)
+		require.NoError(t, err)
+		require.Equal(t, delivery.StatusParked, d.Status)
+
+		d.NextRetry = time.Now().Add(-time.Second)
+		require.NoError(t, deliveryStore.Put(d))
+
+		ob.redriveParkedDeliveries()
+
+		d, err = deliveryStore.Get(deliveryID)
+		require.NoError(t, err)
+		require.Equal(t, delivery.StatusPending, d.Status)
+		require.Equal(t, 0, d.Attempts)
+	})
+}
+
+func TestOutbox_ResolveIRIsPerActor(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+	service2URL := testutil.MustParseURL("http://localhost:8003/services/service2")
+	service3URL := testutil.MustParseURL("http://localhost:8004/services/service3")
+
+	apClient := mocks.NewActivitPubClient().
+		WithActor(vocab.NewService(service2URL, vocab.WithInbox(testutil.NewMockID(service2URL, resthandler.InboxPath)))).
+		WithActor(vocab.NewService(service3URL, vocab.WithInbox(testutil.NewMockID(service3URL, resthandler.InboxPath))))
+
+	it := &storemocks.ReferenceIterator{}
+	it.NextReturnsOnCall(0, service2URL, nil)
+	it.NextReturnsOnCall(1, service3URL, nil)
+	it.NextReturnsOnCall(2, nil, store.ErrNotFound)
+
+	activityStore := &mocks.ActivityStore{}
+	activityStore.QueryReferencesReturns(it, nil)
+
+	registry := peerhealth.New(peerhealth.Config{FailureThreshold: 1, InitialBackoff: time.Minute})
+	registry.RecordFailure(service3URL, errors.New("injected error"))
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1URL,
+		ServiceEndpointURL: service1URL,
+		Topic:              "outbox",
+	}
+
+	ob, err := New(cfg, activityStore, mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, apClient, &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{},
+		WithPeerHealth(registry))
+	require.NoError(t, err)
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://example.com/transactions/txn1"))),
+		vocab.WithID(aptestutil.NewActivityID(service1URL)),
+		vocab.WithActor(service1URL),
+	)
+
+	// The circuit for service3 is open. The inbox of service2 should be delivered to and only service3
+	// should be retried.
+	require.NoError(t, ob.handleResolveIRIs(context.Background(), activity,
+		testutil.NewMockID(service1URL, resthandler.FollowersPath), nil))
+
+	msgTypes := make(map[string]messageType)
+
+	for i := 0; i < 2; i++ {
+		msg := <-ob.msgChan
+
+		activityMsg := &activityMessage{}
+		require.NoError(t, json.Unmarshal(msg.Payload, activityMsg))
+
+		msgTypes[activityMsg.TargetIRI.String()] = activityMsg.Type
+	}
+
+	require.Equal(t, deliverType, msgTypes[testutil.NewMockID(service2URL, resthandler.InboxPath).String()])
+	require.Equal(t, resolveAndDeliverType, msgTypes[service3URL.String()])
+
+	// Resolving service3 alone should fail so that the message is retried.
+	err = ob.handleResolveIRIs(context.Background(), activity, service3URL, nil)
+	require.Error(t, err)
+	require.True(t, orberrors.IsTransient(err))
+	require.Contains(t, err.Error(), "circuit is open")
+}
+
+func TestOutbox_SignActivity(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://example.com/transactions/txn1"))),
+		vocab.WithID(aptestutil.NewActivityID(service1URL)),
+		vocab.WithActor(service1URL),
+	)
+
+	cfg := &Config{
+		ServiceName:        "service1",
+		ServiceIRI:         service1URL,
+		ServiceEndpointURL: service1URL,
+		Topic:              "outbox",
+	}
+
+	t.Run("No signer", func(t *testing.T) {
+		ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
+			&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		a, err := ob.signActivity(activity)
+		require.NoError(t, err)
+		require.True(t, a == activity)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		signer := &mockActivitySigner{}
+
+		ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
+			&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{},
+			WithActivitySigner(signer))
+		require.NoError(t, err)
+
+		a, err := ob.signActivity(activity)
+		require.NoError(t, err)
+		require.Equal(t, activity.ID().String(), a.ID().String())
+		require.Equal(t, 1, signer.numCalls)
+	})
+
+	t.Run("Sign error", func(t *testing.T) {
+		signer := &mockActivitySigner{err: errors.New("injected sign error")}
+
+		ob, err := New(cfg, memstore.New("service1"), mocks.NewPubSub(), transport.Default(),
+			&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{},
+			WithActivitySigner(signer))
+		require.NoError(t, err)
+
+		_, err = ob.signActivity(activity)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), signer.err.Error())
+	})
+}
+
+func TestOutbox_DeadLetter(t *testing.T) {
+	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
+
+	var mutex sync.RWMutex
+
+	responseStatus := http.StatusInternalServerError
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mutex.RLock()
+		defer mutex.RUnlock()
+
+		w.WriteHeader(responseStatus)
+	}))
+	defer server.Close()
+
+	targetURL := testutil.MustParseURL(server.URL + "/services/service2/inbox")
+
+	activity := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithIRI(testutil.MustParseURL("http://example.com/transactions/txn1"))),
+		vocab.WithID(aptestutil.NewActivityID(service1URL)),
+		vocab.WithActor(service1URL),
+		vocab.WithTo(targetURL),
+	)
+
+	activityStore := memstore.New("service1")
+	require.NoError(t, activityStore.AddActivity(activity))
+
+	deliveryStore, err := delivery.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	cfg := &Config{
+		ServiceName:         "service1",
+		ServiceIRI:          service1URL,
+		ServiceEndpointURL:  service1URL,
+		Topic:               "outbox",
+		MaxDeliveryAttempts: 2,
+	}
+
+	ob, err := New(cfg, activityStore, mocks.NewPubSub(), transport.Default(),
+		&mocks.ActivityHandler{}, mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, &orbmocks.MetricsProvider{},
+		WithDeliveryStore(deliveryStore))
+	require.NoError(t, err)
+
+	msgBytes, err := json.Marshal(&activityMessage{
+		Type:      deliverType,
+		Activity:  activity,
+		TargetIRI: vocab.NewURLProperty(targetURL),
+	})
+	require.NoError(t, err)
+
+	deliveryID := delivery.ID(activity.ID().URL(), targetURL)
+
+	_, err = ob.handleActivityMsg(message.NewMessage(watermill.NewUUID(), msgBytes))
+	require.Error(t, err)
+	require.True(t, orberrors.IsTransient(err))
+
+	d, err := deliveryStore.Get(deliveryID)
+	require.NoError(t, err)
+	require.Equal(t, delivery.StatusPending, d.Status)
+	require.Equal(t, 1, d.Attempts)
+	require.Equal(t, targetURL.String(), d.Target)
+	require.Contains(t, d.LastError, "500")
+
+	_, err = ob.handleActivityMsg(message.NewMessage(watermill.NewUUID(), msgBytes))
+	require.Error(t, err)
+	require.False(t, orberrors.IsTransient(err))
+	require.Contains(t, err.Error(), "delivery failed after 2 attempt(s)")
+
+	deadLetters, err := deliveryStore.Query(delivery.StatusFailed)
+	require.NoError(t, err)
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, deliveryID, deadLetters[0].ID)
+	require.Equal(t, 2, deadLetters[0].Attempts)
+
+	require.ErrorIs(t, ob.Redeliver(context.Background(), deliveryID), lifecycle.ErrNotStarted)
+
+	ob.Start()
+	defer ob.Stop()
+
+	t.Run("not found", func(t *testing.T) {
+		err := ob.Redeliver(context.Background(), "invalid")
+		require.ErrorIs(t, err, orberrors.ErrContentNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mutex.Lock()
+		responseStatus = http.StatusOK
+		mutex.Unlock()
+
+		require.NoError(t, ob.Redeliver(context.Background(), deliveryID))
+
+		time.Sleep(200 * time.Millisecond)
+
+		_, err := deliveryStore.Get(deliveryID)
+		require.ErrorIs(t, err, orberrors.ErrContentNotFound)
+	})
+
+	t.Run("not a failed delivery", func(t *testing.T) {
+		require.NoError(t, deliveryStore.Put(&delivery.Delivery{ID: "pending", Status: delivery.StatusPending}))
+
+		err := ob.Redeliver(context.Background(), "pending")
+		require.Error(t, err)
+		require.True(t, orberrors.IsBadRequest(err))
+	})
+}
+
+func TestOutbox_RedeliveryInterval(t *testing.T) {
+	cfg := populateConfigDefaults(&Config{
+		RedeliveryInitialInterval: time.Second,
+		RedeliveryMultiplier:      2,
+		MaxRedeliveryInterval:     5 * time.Second,
+	})
+
+	ob := &Outbox{Config: &cfg}
+
+	require.Equal(t, time.Duration(0), ob.redeliveryInterval(1))
+	require.Equal(t, time.Second, ob.redeliveryInterval(2))
+	require.Equal(t, 2*time.Second, ob.redeliveryInterval(3))
+	require.Equal(t, 4*time.Second, ob.redeliveryInterval(4))
+	require.Equal(t, 5*time.Second, ob.redeliveryInterval(5))
+}
+
 func TestDeduplicate(t *testing.T) {
 	service1URL := testutil.MustParseURL("http://localhost:8002/services/service1")
 	service2URL := testutil.MustParseURL("http://localhost:8002/services/service2")
@@ -954,3 +1396,18 @@ func (m *wellKnownResolver) ResolveHostMetaLink(uri, _ string) (string, error) {
 
 	return uri, nil
 }
+
+type mockActivitySigner struct {
+	numCalls int
+	err      error
+}
+
+func (m *mockActivitySigner) Sign(activity *vocab.ActivityType) (*vocab.ActivityType, error) {
+	m.numCalls++
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return activity, nil
+}
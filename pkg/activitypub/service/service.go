@@ -21,6 +21,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/activityhandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/inbox"
+	"github.com/trustbloc/orb/pkg/activitypub/service/inbox/ratelimiter"
 	"github.com/trustbloc/orb/pkg/activitypub/service/outbox"
 	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -57,6 +58,16 @@ type Config struct {
 	IRICacheExpiration       time.Duration
 	OutboxSubscriberPoolSize int
 	InboxSubscriberPoolSize  int
+
+	// InboxRateLimit holds the per-actor and global rate limits for requests posted to the inbox.
+	InboxRateLimit ratelimiter.Config
+
+	// MaxDeliveryAttempts is the maximum number of attempts to deliver an activity to a target inbox
+	// before the delivery is moved to the dead-letter collection.
+	MaxDeliveryAttempts       int
+	RedeliveryInitialInterval time.Duration
+	RedeliveryMultiplier      float64
+	MaxRedeliveryInterval     time.Duration
 }
 
 // Service implements an ActivityPub service which has an inbox, outbox, and
@@ -80,6 +91,7 @@ type signatureVerifier interface {
 
 type activityPubClient interface {
 	GetActor(iri *url.URL) (*vocab.ActorType, error)
+	MarkActorAsStale(actorIRI *url.URL)
 	GetReferences(ctx context.Context, iri *url.URL) (client.ReferenceIterator, error)
 	GetActivities(ctx context.Context, iri *url.URL, order client.Order) (client.ActivityIterator, error)
 }
@@ -94,15 +106,18 @@ type authTokenManager interface {
 
 type metricsProvider interface {
 	InboxHandlerTime(activityType string, value time.Duration)
+	InboxIncrementThrottledCount(scope string)
 	OutboxPostTime(value time.Duration)
 	OutboxResolveInboxesTime(value time.Duration)
 	OutboxIncrementActivityCount(activityType string)
 }
 
 // New returns a new ActivityPub service.
-func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier signatureVerifier,
-	pubSub PubSub, activityPubClient activityPubClient, resourceResolver resourceResolver,
-	tm authTokenManager, m metricsProvider, handlerOpts ...spi.HandlerOpt,
+func New(cfg *Config, activityStore store.Store, deliveryStore outbox.DeliveryStore,
+	peerHealth outbox.PeerHealthRegistry, inboxFilter inbox.ActivityFilter, quarantineStore inbox.QuarantineStore,
+	activitySigner outbox.ActivitySigner, t httpTransport, sigVerifier signatureVerifier, pubSub PubSub,
+	activityPubClient activityPubClient, resourceResolver resourceResolver, tm authTokenManager, m metricsProvider,
+	handlerOpts ...spi.HandlerOpt,
 ) (*Service, error) {
 	outboxHandler := activityhandler.NewOutbox(
 		&activityhandler.Config{
@@ -113,6 +128,15 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 		},
 		activityStore, activityPubClient)
 
+	outboxOpts := []outbox.Option{
+		outbox.WithDeliveryStore(deliveryStore),
+		outbox.WithPeerHealth(peerHealth),
+	}
+
+	if activitySigner != nil {
+		outboxOpts = append(outboxOpts, outbox.WithActivitySigner(activitySigner))
+	}
+
 	ob, err := outbox.New(
 		&outbox.Config{
 			ServiceName:        cfg.ServicePath,
@@ -122,9 +146,15 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			CacheSize:          cfg.IRICacheSize,
 			CacheExpiration:    cfg.IRICacheExpiration,
 			SubscriberPoolSize: cfg.OutboxSubscriberPoolSize,
+
+			MaxDeliveryAttempts:       cfg.MaxDeliveryAttempts,
+			RedeliveryInitialInterval: cfg.RedeliveryInitialInterval,
+			RedeliveryMultiplier:      cfg.RedeliveryMultiplier,
+			MaxRedeliveryInterval:     cfg.MaxRedeliveryInterval,
 		},
 		activityStore, pubSub,
 		t, outboxHandler, activityPubClient, resourceResolver, m,
+		outboxOpts...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create outbox failed: %w", err)
@@ -147,9 +177,12 @@ func New(cfg *Config, activityStore store.Store, t httpTransport, sigVerifier si
 			Topic:                  inboxActivitiesTopic,
 			VerifyActorInSignature: cfg.VerifyActorInSignature,
 			SubscriberPoolSize:     cfg.InboxSubscriberPoolSize,
+			RateLimit:              cfg.InboxRateLimit,
 		},
 		activityStore, pubSub,
 		inboxHandler, sigVerifier, tm, m,
+		inbox.WithActivityFilter(inboxFilter),
+		inbox.WithQuarantineStore(quarantineStore),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create inbox failed: %w", err)
@@ -186,6 +219,16 @@ func (s *Service) Outbox() spi.Outbox {
 	return s.outbox
 }
 
+// Redeliver re-publishes the activity of the given failed (dead-letter) outbox delivery to its target inbox.
+func (s *Service) Redeliver(ctx context.Context, deliveryID string) error {
+	return s.outbox.Redeliver(ctx, deliveryID)
+}
+
+// ReleaseQuarantined processes the quarantined inbox activity with the given ID and removes it from the quarantine.
+func (s *Service) ReleaseQuarantined(ctx context.Context, id string) error {
+	return s.inbox.ReleaseQuarantined(ctx, id)
+}
+
 // InboxHandler returns the handler for inbox activities.
 func (s *Service) InboxHandler() spi.InboxHandler {
 	return s.activityHandler
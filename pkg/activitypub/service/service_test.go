@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/stretchr/testify/require"
@@ -31,6 +32,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/activitypub/httpsig"
 	apmocks "github.com/trustbloc/orb/pkg/activitypub/mocks"
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	service "github.com/trustbloc/orb/pkg/activitypub/service/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
@@ -44,6 +46,7 @@ import (
 	"github.com/trustbloc/orb/pkg/linkset"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
 	"github.com/trustbloc/orb/pkg/pubsub/wmlogger"
+	"github.com/trustbloc/orb/pkg/store/delivery"
 )
 
 //go:generate counterfeiter -o ./mocks/activityiterator.gen.go --fake-name ActivityIterator ./../client ActivityIterator
@@ -61,8 +64,11 @@ func TestNewService(t *testing.T) {
 
 	store1 := memstore.New(cfg1.ServicePath)
 
-	service1, err := New(cfg1, store1, transport.Default(), &mocks.SignatureVerifier{}, mocks.NewPubSub(),
-		mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, tm, &orbmocks.MetricsProvider{})
+	deliveryStore, err := delivery.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	service1, err := New(cfg1, store1, deliveryStore, peerhealth.New(peerhealth.Config{}), nil, nil, nil, transport.Default(),
+		&mocks.SignatureVerifier{}, mocks.NewPubSub(), mocks.NewActivitPubClient(), &mocks.WebFingerResolver{}, tm, &orbmocks.MetricsProvider{})
 	require.NoError(t, err)
 	require.NotNil(t, service1.InboxHandler())
 
@@ -983,8 +989,11 @@ func newServiceWithMocks(t *testing.T, endpoint string, serviceIRI *url.URL) (*S
 
 	activityStore := memstore.New(cfg.ServicePath)
 
-	s, err := New(cfg, activityStore, trnspt, httpsig.NewVerifier(providers.actorRetriever, cr, km),
-		mocks.NewPubSub(), providers.actorRetriever, &mocks.WebFingerResolver{},
+	deliveryStore, err := delivery.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	s, err := New(cfg, activityStore, deliveryStore, peerhealth.New(peerhealth.Config{}), nil, nil, nil, trnspt,
+		httpsig.NewVerifier(providers.actorRetriever, cr, km), mocks.NewPubSub(), providers.actorRetriever, &mocks.WebFingerResolver{},
 		serverAuthTokenMgr, &orbmocks.MetricsProvider{},
 		service.WithAnchorEventHandler(providers.anchorEventHandler),
 		service.WithFollowAuth(providers.followerAuth),
@@ -9,6 +9,7 @@ package spi
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"time"
 
@@ -98,6 +99,49 @@ type InboxHandler interface {
 	HandleAnnounceActivity(ctx context.Context, source *url.URL, create *vocab.ActivityType) (numProcessed int, err error)
 }
 
+// ActivityProofVerifier verifies the Linked Data proof embedded in an activity.
+type ActivityProofVerifier interface {
+	Verify(activity *vocab.ActivityType) error
+}
+
+// OfferAdmission determines whether or not an 'Offer' activity should be witnessed. The offer ID is the ID of
+// the 'Offer' activity, the actor is the (authenticated) actor of the offer and the origin is the declared origin
+// of the anchor. An OfferRejectedError is returned if the offer is not admitted.
+type OfferAdmission interface {
+	AdmitOffer(offerID, actor, origin *url.URL, anchorSize int) error
+}
+
+// RejectReason is a machine-readable reason for rejecting an activity.
+type RejectReason = string
+
+const (
+	// RejectReasonQuotaExceeded indicates that the actor of the offer exceeded its quota of offers.
+	RejectReasonQuotaExceeded RejectReason = "quota-exceeded"
+	// RejectReasonAnchorTooLarge indicates that the offered anchor exceeds the maximum size.
+	RejectReasonAnchorTooLarge RejectReason = "anchor-too-large"
+	// RejectReasonOriginNotAllowed indicates that the actor of the offer is not in the allow list.
+	RejectReasonOriginNotAllowed RejectReason = "origin-not-allowed"
+)
+
+// OfferRejectedError indicates that an 'Offer' activity was not admitted for the given reason.
+type OfferRejectedError struct {
+	Reason RejectReason
+	msg    string
+}
+
+// NewOfferRejectedError returns a new OfferRejectedError with the given reason.
+func NewOfferRejectedError(reason RejectReason, format string, args ...interface{}) *OfferRejectedError {
+	return &OfferRejectedError{
+		Reason: reason,
+		msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// Error returns the error message.
+func (e *OfferRejectedError) Error() string {
+	return fmt.Sprintf("offer rejected (%s): %s", e.Reason, e.msg)
+}
+
 // UndeliverableActivityHandler handles undeliverable activities.
 type UndeliverableActivityHandler interface {
 	HandleUndeliverableActivity(activity *vocab.ActivityType, toURL string)
@@ -113,6 +157,8 @@ type Handlers struct {
 	AnchorAckHandler      AnchorEventAcknowledgementHandler
 	AcceptFollowHandler   AcceptFollowHandler
 	UndoFollowHandler     UndoFollowHandler
+	ProofVerifier         ActivityProofVerifier
+	OfferAdmission        OfferAdmission
 }
 
 // HandlerOpt sets a specific handler.
@@ -167,6 +213,14 @@ func WithUndoFollowHandler(handler UndoFollowHandler) HandlerOpt {
 	}
 }
 
+// WithActivityProofVerifier sets the verifier of the Linked Data proof embedded in inbound activities.
+// If not set then activity proofs are not verified.
+func WithActivityProofVerifier(verifier ActivityProofVerifier) HandlerOpt {
+	return func(options *Handlers) {
+		options.ProofVerifier = verifier
+	}
+}
+
 // WithAnchorEventAcknowledgementHandler sets the handler for an acknowledgement of a successful anchor event
 // that was processed by another Orb server.
 func WithAnchorEventAcknowledgementHandler(handler AnchorEventAcknowledgementHandler) HandlerOpt {
@@ -175,9 +229,37 @@ func WithAnchorEventAcknowledgementHandler(handler AnchorEventAcknowledgementHan
 	}
 }
 
+// WithOfferAdmission sets the admission controller for incoming 'Offer' activities. If not set then
+// all valid offers are witnessed.
+func WithOfferAdmission(admission OfferAdmission) HandlerOpt {
+	return func(options *Handlers) {
+		options.OfferAdmission = admission
+	}
+}
+
 // AcceptList contains the URIs that are to be accepted by an authorization handler
-// for the given type. Known types are "follow" and "invite-witness".
+// for the given type. Known types are "follow", "invite-witness"
+// and "anchor-origin".
 type AcceptList struct {
 	Type string
 	URL  []*url.URL
 }
+
+type verifiedActorKey struct{}
+
+// ContextWithVerifiedActor returns a context that contains the IRI of the actor that was authenticated
+// (for example, with an HTTP signature) when the activity was received.
+func ContextWithVerifiedActor(ctx context.Context, actorIRI *url.URL) context.Context {
+	return context.WithValue(ctx, verifiedActorKey{}, actorIRI)
+}
+
+// VerifiedActorFromContext returns the IRI of the authenticated actor from the given context or nil if
+// the actor was not authenticated.
+func VerifiedActorFromContext(ctx context.Context) *url.URL {
+	actorIRI, ok := ctx.Value(verifiedActorKey{}).(*url.URL)
+	if !ok {
+		return nil
+	}
+
+	return actorIRI
+}
@@ -42,6 +42,9 @@ const loggerModule = "activitypub_store"
 
 const base10 = 10
 
+// filterBatchSize is the number of activities that are loaded at a time when applying activity filters.
+const filterBatchSize = 100
+
 // Provider implements an ActivityPub store backed by an Aries storage provider.
 type Provider struct {
 	activityStore           ariesstorage.Store
@@ -244,6 +247,10 @@ func (s *Provider) QueryReferences(referenceType spi.ReferenceType,
 func (s *Provider) queryActivitiesByRef(refType spi.ReferenceType,
 	query *spi.Criteria, opts ...spi.QueryOpt,
 ) (spi.ActivityIterator, error) {
+	if query.HasActivityFilters() {
+		return s.queryFilteredActivitiesByRef(refType, query, opts...)
+	}
+
 	refs, totalItems, err := s.queryActivityRefs(refType, query, opts...)
 	if err != nil {
 		return nil, err
@@ -253,6 +260,208 @@ func (s *Provider) queryActivitiesByRef(refType spi.ReferenceType,
 		return memstore.NewActivityIterator(nil, totalItems), nil
 	}
 
+	activities, err := s.getActivities(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	return memstore.NewActivityIterator(activities, totalItems), nil
+}
+
+// queryFilteredActivitiesByRef pages through the references of the given type in ascending order, loading the
+// corresponding activities in batches and applying the activity filters to each batch. Only the activities that
+// fall within the requested page are retained so that memory usage is bounded by the batch and page sizes, while
+// the total number of items still reflects all of the activities that satisfy the filters.
+func (s *Provider) queryFilteredActivitiesByRef(refType spi.ReferenceType,
+	query *spi.Criteria, opts ...spi.QueryOpt,
+) (spi.ActivityIterator, error) {
+	it, err := s.QueryReferences(refType, query,
+		spi.WithSortOrder(spi.SortAscending), spi.WithPageSize(filterBatchSize),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer store.CloseIterator(it)
+
+	window := newActivityWindow(storeutil.GetQueryOptions(opts...))
+
+	for {
+		refs, done, e := nextReferences(it, filterBatchSize)
+		if e != nil {
+			return nil, e
+		}
+
+		if len(refs) > 0 {
+			batch, errGet := s.getActivities(refs)
+			if errGet != nil {
+				return nil, errGet
+			}
+
+			for _, activity := range batch {
+				if storeutil.MatchesActivity(query, activity) {
+					window.add(activity)
+				}
+			}
+		}
+
+		if done {
+			break
+		}
+	}
+
+	return memstore.NewActivityIterator(window.results()), nil
+}
+
+// nextReferences reads up to maxItems references from the given iterator. True is returned if the iterator
+// has no more references.
+func nextReferences(it spi.ReferenceIterator, maxItems int) ([]*url.URL, bool, error) {
+	var refs []*url.URL
+
+	for len(refs) < maxItems {
+		ref, err := it.Next()
+		if err != nil {
+			if errors.Is(err, spi.ErrNotFound) {
+				return refs, true, nil
+			}
+
+			return nil, false, err
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, false, nil
+}
+
+// activityWindow receives the filtered activities in ascending order and retains only the activities that may
+// fall within the requested page. The page boundaries are consistent with the paging semantics of the
+// in-memory store. When sorting in descending order, the page boundaries depend on the total number of items,
+// which is unknown until all activities have been added, so up to two pages of activities are retained.
+type activityWindow struct {
+	options    *spi.QueryOptions
+	total      int
+	offset     int
+	activities []*vocab.ActivityType
+}
+
+func newActivityWindow(options *spi.QueryOptions) *activityWindow {
+	return &activityWindow{options: options}
+}
+
+func (w *activityWindow) add(activity *vocab.ActivityType) {
+	idx := w.total
+	w.total++
+
+	pageSize := w.options.PageSize
+
+	switch {
+	case pageSize <= 0:
+		w.activities = append(w.activities, activity)
+	case w.options.SortOrder == spi.SortAscending:
+		start := 0
+		if w.options.PageNumber > 0 {
+			start = w.options.PageNumber * pageSize
+		}
+
+		if idx >= start && idx < start+pageSize {
+			if len(w.activities) == 0 {
+				w.offset = idx
+			}
+
+			w.activities = append(w.activities, activity)
+		}
+	case w.options.PageNumber < 0:
+		// Descending with no page number: keep the last page of activities.
+		w.activities = append(w.activities, activity)
+
+		if len(w.activities) > pageSize {
+			w.activities = w.activities[1:]
+			w.offset++
+		}
+	default:
+		// Descending with a page number: depending on the total number of items, the page starts within
+		// one page before the ascending position of the page.
+		start := (w.options.PageNumber - 1) * pageSize
+		if start < 0 {
+			start = 0
+		}
+
+		if idx >= start && idx < (w.options.PageNumber+1)*pageSize {
+			if len(w.activities) == 0 {
+				w.offset = idx
+			}
+
+			w.activities = append(w.activities, activity)
+		}
+	}
+}
+
+// results returns the activities in the requested page (in the requested sort order) along with the total
+// number of activities that were added.
+func (w *activityWindow) results() ([]*vocab.ActivityType, int) {
+	pageSize := w.options.PageSize
+
+	if w.options.SortOrder == spi.SortAscending {
+		return w.activities, w.total
+	}
+
+	var lo, hi int
+
+	if pageSize <= 0 {
+		lo, hi = 0, w.total
+	} else {
+		startIdx := 0
+		if w.options.PageNumber >= 0 {
+			startIdx = (firstPageNum(w.total, pageSize) - w.options.PageNumber) * pageSize
+		}
+
+		if startIdx < 0 || startIdx >= w.total {
+			return nil, w.total
+		}
+
+		// Convert the descending start index into a range of ascending indexes.
+		hi = w.total - startIdx
+		lo = hi - pageSize
+
+		if lo < 0 {
+			lo = 0
+		}
+	}
+
+	lo -= w.offset
+	hi -= w.offset
+
+	if lo < 0 {
+		lo = 0
+	}
+
+	if hi > len(w.activities) {
+		hi = len(w.activities)
+	}
+
+	if lo >= hi {
+		return nil, w.total
+	}
+
+	results := make([]*vocab.ActivityType, 0, hi-lo)
+
+	for i := hi - 1; i >= lo; i-- {
+		results = append(results, w.activities[i])
+	}
+
+	return results, w.total
+}
+
+func firstPageNum(totalItems, pageSize int) int {
+	if totalItems%pageSize > 0 {
+		return totalItems / pageSize
+	}
+
+	return totalItems/pageSize - 1
+}
+
+func (s *Provider) getActivities(refs []*url.URL) ([]*vocab.ActivityType, error) {
 	activityIDs := make([]string, len(refs))
 
 	for i, ref := range refs {
@@ -278,7 +487,7 @@ func (s *Provider) queryActivitiesByRef(refType spi.ReferenceType,
 		}
 	}
 
-	return memstore.NewActivityIterator(activities, totalItems), nil
+	return activities, nil
 }
 
 func (s *Provider) queryActivityRefs(refType spi.ReferenceType, query *spi.Criteria, opts ...spi.QueryOpt) ([]*url.URL, int, error) {
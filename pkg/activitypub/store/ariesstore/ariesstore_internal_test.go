@@ -7,10 +7,17 @@ package ariesstore
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
+	"github.com/trustbloc/orb/pkg/activitypub/store/storeutil"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
 )
 
 func TestIterators_FailureCases(t *testing.T) {
@@ -30,3 +37,54 @@ func TestIterators_FailureCases(t *testing.T) {
 		require.Nil(t, activity)
 	})
 }
+
+func TestActivityWindow(t *testing.T) {
+	const maxItems = 12
+
+	for total := 0; total <= maxItems; total++ {
+		activities := make([]*vocab.ActivityType, total)
+
+		for i := range activities {
+			activities[i] = vocab.NewCreateActivity(nil,
+				vocab.WithID(testutil.MustParseURL(fmt.Sprintf("https://example.com/activities/%d", i))),
+			)
+		}
+
+		for _, sortOrder := range []spi.SortOrder{spi.SortAscending, spi.SortDescending} {
+			for _, pageSize := range []int{-1, 1, 3, 5} {
+				for pageNum := -1; pageNum <= maxItems; pageNum++ {
+					opts := []spi.QueryOpt{
+						spi.WithSortOrder(sortOrder), spi.WithPageSize(pageSize), spi.WithPageNum(pageNum),
+					}
+
+					expected, expectedTotal := memstore.FilterActivities(activities, spi.NewCriteria(), opts...)
+					if pageSize > 0 && len(expected) > pageSize {
+						expected = expected[:pageSize]
+					}
+
+					window := newActivityWindow(storeutil.GetQueryOptions(opts...))
+
+					for _, activity := range activities {
+						window.add(activity)
+					}
+
+					results, totalItems := window.results()
+
+					msg := fmt.Sprintf("total=%d, sortOrder=%d, pageSize=%d, pageNum=%d",
+						total, sortOrder, pageSize, pageNum)
+
+					require.Equal(t, expectedTotal, totalItems, msg)
+					require.Equal(t, len(expected), len(results), msg)
+
+					for i := range expected {
+						require.Equal(t, expected[i].ID().String(), results[i].ID().String(), msg)
+					}
+
+					if pageSize > 0 {
+						require.LessOrEqual(t, len(window.activities), 2*pageSize, msg)
+					}
+				}
+			}
+		}
+	}
+}
@@ -46,6 +46,7 @@ func New(serviceName string) *Store {
 			spi.Liked:         newReferenceStore(),
 			spi.Share:         newReferenceStore(),
 			spi.AnchorLinkset: newReferenceStore(),
+			spi.Blocked:       newReferenceStore(),
 		},
 	}
 }
@@ -117,6 +118,10 @@ func (s *Store) QueryReferences(refType spi.ReferenceType, query *spi.Criteria,
 }
 
 func (s *Store) queryActivitiesByRef(refType spi.ReferenceType, query *spi.Criteria, opts ...spi.QueryOpt) (spi.ActivityIterator, error) {
+	if len(query.Types) > 0 || query.HasActivityFilters() {
+		return s.queryFilteredActivitiesByRef(refType, query, opts...)
+	}
+
 	it, err := s.QueryReferences(refType, query, opts...)
 	if err != nil {
 		return nil, err
@@ -148,6 +153,31 @@ func (s *Store) queryActivitiesByRef(refType spi.ReferenceType, query *spi.Crite
 	return ait, nil
 }
 
+// queryFilteredActivitiesByRef reads all references of the given type and applies the activity filters
+// before paging, so that the total number of items and the pages reflect the filtered results.
+func (s *Store) queryFilteredActivitiesByRef(refType spi.ReferenceType, query *spi.Criteria,
+	opts ...spi.QueryOpt,
+) (spi.ActivityIterator, error) {
+	it, err := s.QueryReferences(refType, spi.NewCriteria(spi.WithObjectIRI(query.ObjectIRI)))
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := storeutil.ReadReferences(it, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(refs) == 0 {
+		return NewActivityIterator(nil, 0), nil
+	}
+
+	filter := *query
+	filter.ActivityIRIs = refs
+
+	return s.activityStore.query(&filter, opts...), nil
+}
+
 type activityStore struct {
 	mutex        sync.RWMutex
 	activities   []*vocab.ActivityType
@@ -252,18 +282,12 @@ func newQueryFilter(query *spi.Criteria) *activityQueryFilter {
 func (q *activityQueryFilter) apply(activities []*vocab.ActivityType) []*vocab.ActivityType {
 	var results []*vocab.ActivityType
 
-	if len(q.ActivityIRIs) > 0 {
-		for _, a := range activities {
-			if containsIRI(q.ActivityIRIs, a.ID().URL()) {
-				results = append(results, a)
-			}
+	for _, a := range activities {
+		if len(q.ActivityIRIs) > 0 && !containsIRI(q.ActivityIRIs, a.ID().URL()) {
+			continue
 		}
 
-		return results
-	}
-
-	for _, a := range activities {
-		if len(q.Types) == 0 || a.Type().IsAny(q.Types...) {
+		if storeutil.MatchesActivity(q.Criteria, a) {
 			results = append(results, a)
 		}
 	}
@@ -271,6 +295,15 @@ func (q *activityQueryFilter) apply(activities []*vocab.ActivityType) []*vocab.A
 	return results
 }
 
+// FilterActivities applies the given criteria and query options (sort order and paging) to the given activities,
+// which must be in ascending order. The results, starting at the requested page, are returned along with the
+// total number of activities that matched the criteria.
+func FilterActivities(activities []*vocab.ActivityType, query *spi.Criteria,
+	opts ...spi.QueryOpt,
+) ([]*vocab.ActivityType, int) {
+	return activityQueryResults(activities).filter(query, opts...)
+}
+
 type activityQueryResults []*vocab.ActivityType
 
 func (r activityQueryResults) filter(query *spi.Criteria, opts ...spi.QueryOpt) ([]*vocab.ActivityType, int) {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -76,6 +77,90 @@ func TestStore_Activity(t *testing.T) {
 
 		checkQueryResults(t, it, activityID1, activityID2, activityID3)
 	})
+
+	t.Run("Query by reference and type", func(t *testing.T) {
+		it, err := s.QueryActivities(spi.NewCriteria(spi.WithReferenceType(spi.Inbox), spi.WithObjectIRI(serviceID1),
+			spi.WithType(vocab.TypeAnnounce)))
+		require.NoError(t, err)
+		require.NotNil(t, it)
+
+		checkQueryResults(t, it, activityID2)
+	})
+}
+
+func TestStore_QueryWithActivityFilters(t *testing.T) {
+	s := New("service1")
+	require.NotNil(t, s)
+
+	var (
+		serviceID1  = testutil.MustParseURL("https://example.com/services/service1")
+		serviceID2  = testutil.MustParseURL("https://example.com/services/service2")
+		activityID1 = testutil.MustParseURL("https://example.com/activities/activity1")
+		activityID2 = testutil.MustParseURL("https://example.com/activities/activity2")
+		activityID3 = testutil.MustParseURL("https://example.com/activities/activity3")
+		anchor      = testutil.MustParseURL("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ")
+	)
+
+	published := time.Now().Add(-time.Hour)
+	published2 := published.Add(time.Minute)
+	published3 := published.Add(2 * time.Minute)
+
+	require.NoError(t, s.AddActivity(vocab.NewCreateActivity(vocab.NewObjectProperty(), vocab.WithID(activityID1),
+		vocab.WithActor(serviceID1), vocab.WithPublishedTime(&published))))
+	require.NoError(t, s.AddActivity(vocab.NewAnnounceActivity(vocab.NewObjectProperty(vocab.WithIRI(anchor)),
+		vocab.WithID(activityID2), vocab.WithActor(serviceID2), vocab.WithPublishedTime(&published2))))
+	require.NoError(t, s.AddActivity(vocab.NewCreateActivity(vocab.NewObjectProperty(), vocab.WithID(activityID3),
+		vocab.WithActor(serviceID2), vocab.WithPublishedTime(&published3))))
+
+	require.NoError(t, s.AddReference(spi.Outbox, serviceID1, activityID1))
+	require.NoError(t, s.AddReference(spi.Outbox, serviceID1, activityID2))
+	require.NoError(t, s.AddReference(spi.Outbox, serviceID1, activityID3))
+
+	t.Run("By actor", func(t *testing.T) {
+		it, err := s.QueryActivities(spi.NewCriteria(spi.WithReferenceType(spi.Outbox), spi.WithObjectIRI(serviceID1),
+			spi.WithActor(serviceID2)))
+		require.NoError(t, err)
+
+		checkQueryResults(t, it, activityID2, activityID3)
+	})
+
+	t.Run("By published time", func(t *testing.T) {
+		it, err := s.QueryActivities(spi.NewCriteria(spi.WithReferenceType(spi.Outbox), spi.WithObjectIRI(serviceID1),
+			spi.WithPublishedSince(published2), spi.WithPublishedUntil(published3)))
+		require.NoError(t, err)
+
+		checkQueryResults(t, it, activityID2, activityID3)
+	})
+
+	t.Run("By anchor", func(t *testing.T) {
+		it, err := s.QueryActivities(spi.NewCriteria(spi.WithReferenceType(spi.Outbox), spi.WithObjectIRI(serviceID1),
+			spi.WithAnchor(anchor)))
+		require.NoError(t, err)
+
+		checkQueryResults(t, it, activityID2)
+	})
+
+	t.Run("Paged", func(t *testing.T) {
+		it, err := s.QueryActivities(spi.NewCriteria(spi.WithReferenceType(spi.Outbox), spi.WithObjectIRI(serviceID1),
+			spi.WithActor(serviceID2)), spi.WithPageSize(1), spi.WithPageNum(1))
+		require.NoError(t, err)
+
+		totalItems, err := it.TotalItems()
+		require.NoError(t, err)
+		require.Equal(t, 2, totalItems)
+
+		a, err := it.Next()
+		require.NoError(t, err)
+		require.Equal(t, activityID3.String(), a.ID().String())
+	})
+
+	t.Run("No references", func(t *testing.T) {
+		it, err := s.QueryActivities(spi.NewCriteria(spi.WithReferenceType(spi.Inbox), spi.WithObjectIRI(serviceID1),
+			spi.WithActor(serviceID2)))
+		require.NoError(t, err)
+
+		checkQueryResults(t, it)
+	})
 }
 
 func TestStore_Reference(t *testing.T) {
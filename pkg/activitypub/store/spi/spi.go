@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 )
@@ -49,6 +50,9 @@ const (
 	Share ReferenceType = "SHARE"
 	// AnchorLinkset indicates that the reference is an anchor Linkset.
 	AnchorLinkset ReferenceType = "ANCHOR_LINKSET"
+	// Blocked indicates that the reference is an actor that the local service has blocked. Activities
+	// from blocked actors are not accepted.
+	Blocked ReferenceType = "BLOCKED"
 )
 
 // Store defines the functions of an ActivityPub store.
@@ -134,6 +138,19 @@ type Criteria struct {
 	ObjectIRI     *url.URL
 	ReferenceIRI  *url.URL
 	ActivityIRIs  []*url.URL
+
+	// Actor, PublishedSince, PublishedUntil and Anchor filter the activities that are returned from
+	// an activity query.
+	Actor          *url.URL
+	PublishedSince *time.Time
+	PublishedUntil *time.Time
+	Anchor         *url.URL
+}
+
+// HasActivityFilters returns true if the criteria filter activities by actor, published time or anchor.
+// These filters may only be applied to the activities themselves and not to the references.
+func (c *Criteria) HasActivityFilters() bool {
+	return c.Actor != nil || c.PublishedSince != nil || c.PublishedUntil != nil || c.Anchor != nil
 }
 
 // MarshalJSON marshals the criteria into a logger-friendly format.
@@ -190,6 +207,34 @@ func WithActivityIRIs(iris ...*url.URL) CriteriaOpt {
 	}
 }
 
+// WithActor sets the actor of the activities on the criteria.
+func WithActor(actor *url.URL) CriteriaOpt {
+	return func(query *Criteria) {
+		query.Actor = actor
+	}
+}
+
+// WithPublishedSince sets the criteria to return only activities that were published at or after the given time.
+func WithPublishedSince(t time.Time) CriteriaOpt {
+	return func(query *Criteria) {
+		query.PublishedSince = &t
+	}
+}
+
+// WithPublishedUntil sets the criteria to return only activities that were published at or before the given time.
+func WithPublishedUntil(t time.Time) CriteriaOpt {
+	return func(query *Criteria) {
+		query.PublishedUntil = &t
+	}
+}
+
+// WithAnchor sets the criteria to return only activities that reference the given anchor (hashlink).
+func WithAnchor(anchor *url.URL) CriteriaOpt {
+	return func(query *Criteria) {
+		query.Anchor = anchor
+	}
+}
+
 // ActivityIterator defines the query results iterator for activity queries.
 type ActivityIterator interface {
 	// TotalItems returns the total number of items as a result of the query.
@@ -217,6 +262,10 @@ type loggedCriteria struct {
 	ObjectIRI     *vocab.URLProperty           `json:"objectIRI,omitempty"`
 	ReferenceIRI  *vocab.URLProperty           `json:"referenceIRI,omitempty"`
 	ActivityIRIs  *vocab.URLCollectionProperty `json:"activityIRIs,omitempty"`
+	Actor         *vocab.URLProperty           `json:"actor,omitempty"`
+	Since         *time.Time                   `json:"publishedSince,omitempty"`
+	Until         *time.Time                   `json:"publishedUntil,omitempty"`
+	Anchor        *vocab.URLProperty           `json:"anchor,omitempty"`
 }
 
 func newLoggedCriteria(c *Criteria) *loggedCriteria {
@@ -226,5 +275,9 @@ func newLoggedCriteria(c *Criteria) *loggedCriteria {
 		ObjectIRI:     vocab.NewURLProperty(c.ObjectIRI),
 		ReferenceIRI:  vocab.NewURLProperty(c.ReferenceIRI),
 		ActivityIRIs:  vocab.NewURLCollectionProperty(c.ActivityIRIs...),
+		Actor:         vocab.NewURLProperty(c.Actor),
+		Since:         c.PublishedSince,
+		Until:         c.PublishedUntil,
+		Anchor:        vocab.NewURLProperty(c.Anchor),
 	}
 }
@@ -9,6 +9,7 @@ package spi
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -31,9 +32,33 @@ func TestCriteria(t *testing.T) {
 	require.Len(t, c.Types, 2)
 	require.Equal(t, vocab.TypeCreate, c.Types[0])
 	require.Equal(t, vocab.TypeAnnounce, c.Types[1])
+	require.False(t, c.HasActivityFilters())
 
 	b, err := json.Marshal(c)
 	require.NoError(t, err)
 
 	t.Logf("%s", b)
 }
+
+func TestCriteria_ActivityFilters(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	until := time.Now()
+
+	c := NewCriteria(
+		WithReferenceType(Outbox),
+		WithObjectIRI(testutil.MustParseURL("https://example.com/obj")),
+		WithActor(testutil.MustParseURL("https://example.com/services/orb")),
+		WithPublishedSince(since),
+		WithPublishedUntil(until),
+		WithAnchor(testutil.MustParseURL("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ")),
+	)
+	require.True(t, c.HasActivityFilters())
+	require.Equal(t, "https://example.com/services/orb", c.Actor.String())
+	require.True(t, since.Equal(*c.PublishedSince))
+	require.True(t, until.Equal(*c.PublishedUntil))
+	require.Equal(t, "hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ", c.Anchor.String())
+
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "publishedSince")
+}
@@ -9,6 +9,7 @@ package storeutil
 import (
 	"errors"
 	"net/url"
+	"time"
 
 	store "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
@@ -81,3 +82,75 @@ func ReadActivities(it store.ActivityIterator, maxItems int) ([]*vocab.ActivityT
 
 	return activities, nil
 }
+
+// MatchesActivity returns true if the given activity satisfies the type, actor, published time and
+// anchor filters of the given criteria.
+func MatchesActivity(query *store.Criteria, activity *vocab.ActivityType) bool {
+	if len(query.Types) > 0 && !activity.Type().IsAny(query.Types...) {
+		return false
+	}
+
+	if query.Actor != nil && (activity.Actor() == nil || activity.Actor().String() != query.Actor.String()) {
+		return false
+	}
+
+	if !matchesPublished(query, activity.Published()) {
+		return false
+	}
+
+	if query.Anchor != nil && !anchorURLs(activity).Contains(query.Anchor) {
+		return false
+	}
+
+	return true
+}
+
+func matchesPublished(query *store.Criteria, published *time.Time) bool {
+	if query.PublishedSince == nil && query.PublishedUntil == nil {
+		return true
+	}
+
+	if published == nil {
+		return false
+	}
+
+	if query.PublishedSince != nil && published.Before(*query.PublishedSince) {
+		return false
+	}
+
+	if query.PublishedUntil != nil && published.After(*query.PublishedUntil) {
+		return false
+	}
+
+	return true
+}
+
+// anchorURLs returns the URLs (hashlinks) of the anchors that are referenced by the given activity.
+func anchorURLs(activity *vocab.ActivityType) vocab.Urls {
+	obj := activity.Object()
+
+	var items []*vocab.ObjectProperty
+
+	switch {
+	case obj.Collection() != nil:
+		items = obj.Collection().Items()
+	case obj.OrderedCollection() != nil:
+		items = obj.OrderedCollection().Items()
+	default:
+		items = []*vocab.ObjectProperty{obj}
+	}
+
+	var urls vocab.Urls
+
+	for _, item := range items {
+		if item.AnchorEvent() != nil {
+			urls = append(urls, item.AnchorEvent().URL()...)
+		}
+	}
+
+	if obj.IRI() != nil {
+		urls = append(urls, obj.IRI())
+	}
+
+	return urls
+}
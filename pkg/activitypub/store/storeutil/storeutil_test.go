@@ -10,12 +10,14 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/activitypub/store/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
 )
 
 //go:generate counterfeiter -o ../mocks/referenceiterator.gen.go --fake-name ReferenceIterator ../spi ReferenceIterator
@@ -90,3 +92,33 @@ func TestReadReferences(t *testing.T) {
 		require.Empty(t, refs)
 	})
 }
+
+func TestMatchesActivity(t *testing.T) {
+	actor := testutil.MustParseURL("https://example.com/services/orb")
+	anchor := testutil.MustParseURL("hl:uEiCsFp-ft8tI1DFGbXs78tw-HS561mMPa3Z6GsGAHElrNQ")
+	published := time.Now()
+
+	create := vocab.NewCreateActivity(
+		vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(nil, vocab.WithURL(anchor)))),
+		vocab.WithActor(actor), vocab.WithPublishedTime(&published),
+	)
+
+	announce := vocab.NewAnnounceActivity(
+		vocab.NewObjectProperty(vocab.WithCollection(vocab.NewCollection([]*vocab.ObjectProperty{
+			vocab.NewObjectProperty(vocab.WithAnchorEvent(vocab.NewAnchorEvent(nil, vocab.WithURL(anchor)))),
+		}))),
+	)
+
+	require.True(t, MatchesActivity(spi.NewCriteria(), create))
+	require.True(t, MatchesActivity(spi.NewCriteria(spi.WithType(vocab.TypeCreate)), create))
+	require.False(t, MatchesActivity(spi.NewCriteria(spi.WithType(vocab.TypeAnnounce)), create))
+	require.True(t, MatchesActivity(spi.NewCriteria(spi.WithActor(actor)), create))
+	require.False(t, MatchesActivity(spi.NewCriteria(spi.WithActor(actor)), announce))
+	require.True(t, MatchesActivity(spi.NewCriteria(spi.WithPublishedSince(published)), create))
+	require.False(t, MatchesActivity(spi.NewCriteria(spi.WithPublishedSince(published.Add(time.Second))), create))
+	require.False(t, MatchesActivity(spi.NewCriteria(spi.WithPublishedUntil(published.Add(-time.Second))), create))
+	require.False(t, MatchesActivity(spi.NewCriteria(spi.WithPublishedUntil(published)), announce))
+	require.True(t, MatchesActivity(spi.NewCriteria(spi.WithAnchor(anchor)), create))
+	require.True(t, MatchesActivity(spi.NewCriteria(spi.WithAnchor(anchor)), announce))
+	require.False(t, MatchesActivity(spi.NewCriteria(spi.WithAnchor(actor)), create))
+}
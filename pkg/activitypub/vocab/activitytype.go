@@ -187,6 +187,7 @@ func NewRejectActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 		activity: &activityType{
 			Actor:  NewURLProperty(options.Actor),
 			Object: obj,
+			Result: options.Result,
 		},
 	}
 }
@@ -249,3 +250,60 @@ func NewUndoActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
 		},
 	}
 }
+
+// NewUpdateActivity returns a new 'Update' activity. The object of the activity is typically
+// the updated actor (for example, an actor whose public key was rotated).
+func NewUpdateActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
+	options := NewOptions(opts...)
+
+	return &ActivityType{
+		ObjectType: NewObject(
+			WithContext(getContexts(options, ContextActivityStreams)...),
+			WithID(options.ID),
+			WithType(TypeUpdate),
+			WithTo(options.To...),
+			WithPublishedTime(options.Published),
+		),
+		activity: &activityType{
+			Actor:  NewURLProperty(options.Actor),
+			Object: obj,
+		},
+	}
+}
+
+// NewDeleteActivity returns a new 'Delete' activity.
+func NewDeleteActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
+	options := NewOptions(opts...)
+
+	return &ActivityType{
+		ObjectType: NewObject(
+			WithContext(getContexts(options, ContextActivityStreams)...),
+			WithID(options.ID),
+			WithType(TypeDelete),
+			WithTo(options.To...),
+			WithPublishedTime(options.Published),
+		),
+		activity: &activityType{
+			Actor:  NewURLProperty(options.Actor),
+			Object: obj,
+		},
+	}
+}
+
+// NewBlockActivity returns a new 'Block' activity.
+func NewBlockActivity(obj *ObjectProperty, opts ...Opt) *ActivityType {
+	options := NewOptions(opts...)
+
+	return &ActivityType{
+		ObjectType: NewObject(
+			WithContext(getContexts(options, ContextActivityStreams)...),
+			WithID(options.ID),
+			WithType(TypeBlock),
+			WithTo(options.To...),
+		),
+		activity: &activityType{
+			Actor:  NewURLProperty(options.Actor),
+			Object: obj,
+		},
+	}
+}
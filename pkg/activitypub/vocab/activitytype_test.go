@@ -32,6 +32,9 @@ var (
 	offerActivityID   = newMockID(service1, "/activities/65b3d005-6bb6-673d-6879-18bc1ee84976")
 	undoActivityID    = newMockID(service1, "/activities/77bcd005-abb6-433d-a889-18bc1ce64981")
 	likeActivityID    = newMockID(witness1, "/likes/87bcd005-abb6-433d-a889-18bc1ce84988")
+	updateActivityID  = newMockID(service1, "/activities/57bcd005-abb6-433d-a889-18bc1ce64982")
+	deleteActivityID  = newMockID(service1, "/activities/47bcd005-abb6-433d-a889-18bc1ce64983")
+	blockActivityID   = newMockID(service1, "/activities/37bcd005-abb6-433d-a889-18bc1ce64984")
 
 	public = testutil.MustParseURL("https://www.w3.org/ns/activitystreams#Public")
 
@@ -787,6 +790,102 @@ func TestUndoTypeMarshal(t *testing.T) {
 	})
 }
 
+func TestUpdateTypeMarshal(t *testing.T) {
+	const keyPem = "-----BEGIN PUBLIC KEY-----\nMIIBIjANBgkqhki....."
+
+	followers := newMockID(service1, "/followers")
+	keyID := newMockID(service1, "/keys/main-key-2")
+
+	service := NewService(service1,
+		WithPublicKey(NewPublicKey(
+			WithID(keyID),
+			WithOwner(service1),
+			WithPublicKeyPem(keyPem),
+		)),
+		WithInbox(newMockID(service1, "/inbox")),
+	)
+
+	update := NewUpdateActivity(
+		NewObjectProperty(WithActorObject(service)),
+		WithID(updateActivityID),
+		WithActor(service1),
+		WithTo(followers),
+	)
+
+	bytes, err := json.Marshal(update)
+	require.NoError(t, err)
+	t.Log(string(bytes))
+
+	a := &ActivityType{}
+	require.NoError(t, json.Unmarshal(bytes, a))
+	require.True(t, a.Type().Is(TypeUpdate))
+	require.Equal(t, updateActivityID.String(), a.ID().String())
+	require.Equal(t, service1.String(), a.Actor().String())
+	require.True(t, a.Object().Type().Is(TypeService))
+
+	actor := a.Object().Actor()
+	require.NotNil(t, actor)
+	require.Equal(t, service1.String(), actor.ID().String())
+	require.NotNil(t, actor.PublicKey())
+	require.Equal(t, keyID.String(), actor.PublicKey().ID().String())
+	require.Equal(t, keyPem, actor.PublicKey().PublicKeyPem())
+}
+
+func TestDeleteTypeMarshal(t *testing.T) {
+	followers := newMockID(service1, "/followers")
+
+	t.Run("Marshal", func(t *testing.T) {
+		del := NewDeleteActivity(
+			NewObjectProperty(WithIRI(service1)),
+			WithID(deleteActivityID),
+			WithActor(service1),
+			WithTo(followers, public),
+		)
+
+		bytes, err := canonicalizer.MarshalCanonical(del)
+		require.NoError(t, err)
+		t.Log(string(bytes))
+
+		require.Equal(t, testutil.GetCanonical(t, jsonDelete), string(bytes))
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		a := &ActivityType{}
+		require.NoError(t, json.Unmarshal([]byte(jsonDelete), a))
+		require.True(t, a.Type().Is(TypeDelete))
+		require.Equal(t, deleteActivityID.String(), a.ID().String())
+		require.Equal(t, service1.String(), a.Actor().String())
+		require.Equal(t, service1.String(), a.Object().IRI().String())
+		require.Len(t, a.To(), 2)
+	})
+}
+
+func TestBlockTypeMarshal(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		block := NewBlockActivity(
+			NewObjectProperty(WithIRI(witness1)),
+			WithID(blockActivityID),
+			WithActor(service1),
+		)
+
+		bytes, err := canonicalizer.MarshalCanonical(block)
+		require.NoError(t, err)
+		t.Log(string(bytes))
+
+		require.Equal(t, testutil.GetCanonical(t, jsonBlock), string(bytes))
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		a := &ActivityType{}
+		require.NoError(t, json.Unmarshal([]byte(jsonBlock), a))
+		require.True(t, a.Type().Is(TypeBlock))
+		require.True(t, a.Type().IsActivity())
+		require.Equal(t, blockActivityID.String(), a.ID().String())
+		require.Equal(t, service1.String(), a.Actor().String())
+		require.Equal(t, witness1.String(), a.Object().IRI().String())
+	})
+}
+
 func TestActivityType_Accessors(t *testing.T) {
 	a := &ActivityType{}
 
@@ -1123,6 +1222,26 @@ const (
   "type": "Undo"
 }`
 
+	jsonDelete = `{
+  "@context": "https://www.w3.org/ns/activitystreams",
+  "actor": "https://sally.example.com/services/orb",
+  "id": "https://sally.example.com/services/orb/activities/47bcd005-abb6-433d-a889-18bc1ce64983",
+  "object": "https://sally.example.com/services/orb",
+  "to": [
+    "https://sally.example.com/services/orb/followers",
+    "https://www.w3.org/ns/activitystreams#Public"
+  ],
+  "type": "Delete"
+}`
+
+	jsonBlock = `{
+  "@context": "https://www.w3.org/ns/activitystreams",
+  "actor": "https://sally.example.com/services/orb",
+  "id": "https://sally.example.com/services/orb/activities/37bcd005-abb6-433d-a889-18bc1ce64984",
+  "object": "https://witness1.example.com/services/orb",
+  "type": "Block"
+}`
+
 	jsonInviteWitness = `{
   "@context": [
     "https://www.w3.org/ns/activitystreams",
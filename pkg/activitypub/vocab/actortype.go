@@ -103,6 +103,11 @@ type actorType struct {
 	Liked      *URLProperty   `json:"liked"`
 	Likes      *URLProperty   `json:"likes"`
 	Shares     *URLProperty   `json:"shares"`
+	Endpoints  *endpointsType `json:"endpoints,omitempty"`
+}
+
+type endpointsType struct {
+	SharedInbox *URLProperty `json:"sharedInbox,omitempty"`
 }
 
 // PublicKey returns the actor's public key.
@@ -119,6 +124,16 @@ func (t *ActorType) Inbox() *url.URL {
 	return t.actor.Inbox.URL()
 }
 
+// SharedInbox returns the URL of the shared inbox (from the actor's 'endpoints') or nil if the
+// actor does not specify a shared inbox.
+func (t *ActorType) SharedInbox() *url.URL {
+	if t.actor.Endpoints == nil || t.actor.Endpoints.SharedInbox == nil {
+		return nil
+	}
+
+	return t.actor.Endpoints.SharedInbox.URL()
+}
+
 // Outbox returns the URL of the actor's outbox.
 func (t *ActorType) Outbox() *url.URL {
 	if t.actor.Outbox == nil {
@@ -190,6 +205,14 @@ func (t *ActorType) UnmarshalJSON(bytes []byte) error {
 func NewService(id *url.URL, opts ...Opt) *ActorType {
 	options := NewOptions(opts...)
 
+	var endpoints *endpointsType
+
+	if options.SharedInbox != nil {
+		endpoints = &endpointsType{
+			SharedInbox: NewURLProperty(options.SharedInbox),
+		}
+	}
+
 	return &ActorType{
 		ObjectType: NewObject(
 			WithContext(getContexts(options, ContextActivityStreams, ContextSecurity, ContextActivityAnchors)...),
@@ -207,6 +230,7 @@ func NewService(id *url.URL, opts ...Opt) *ActorType {
 			Liked:      NewURLProperty(options.Liked),
 			Likes:      NewURLProperty(options.Likes),
 			Shares:     NewURLProperty(options.Shares),
+			Endpoints:  endpoints,
 		},
 	}
 }
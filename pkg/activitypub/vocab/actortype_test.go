@@ -24,6 +24,7 @@ func TestActor(t *testing.T) {
 	followers := testutil.MustParseURL("https://sally.example.com/services/orb/followers")
 	following := testutil.MustParseURL("https://sally.example.com/services/orb/following")
 	inbox := testutil.MustParseURL("https://alice.example.com/services/orb/inbox")
+	sharedInbox := testutil.MustParseURL("https://alice.example.com/services/orb/sharedinbox")
 	outbox := testutil.MustParseURL("https://alice.example.com/services/orb/outbox")
 	witnesses := testutil.MustParseURL("https://alice.example.com/services/orb/witnesses")
 	witnessing := testutil.MustParseURL("https://alice.example.com/services/orb/witnessing")
@@ -41,6 +42,7 @@ func TestActor(t *testing.T) {
 		service := NewService(serviceIRI,
 			WithPublicKey(publicKey),
 			WithInbox(inbox),
+			WithSharedInbox(sharedInbox),
 			WithOutbox(outbox),
 			WithFollowers(followers),
 			WithFollowing(following),
@@ -82,6 +84,10 @@ func TestActor(t *testing.T) {
 		require.NotNil(t, in)
 		require.Equal(t, inbox.String(), in.String())
 
+		sin := a.SharedInbox()
+		require.NotNil(t, sin)
+		require.Equal(t, sharedInbox.String(), sin.String())
+
 		out := a.Outbox()
 		require.NotNil(t, out)
 		require.Equal(t, outbox.String(), out.String())
@@ -117,6 +123,7 @@ func TestActor(t *testing.T) {
 		require.NotNil(t, a.Context())
 		require.Nil(t, a.PublicKey())
 		require.Nil(t, a.Inbox())
+		require.Nil(t, a.SharedInbox())
 		require.Nil(t, a.Outbox())
 		require.Nil(t, a.Followers())
 		require.Nil(t, a.Following())
@@ -193,5 +200,8 @@ const jsonService = `{
   "witnessing": "https://alice.example.com/services/orb/witnessing",
   "liked": "https://alice.example.com/services/orb/liked",
   "likes": "https://alice.example.com/services/orb/likes",
-  "shares": "https://alice.example.com/services/orb/shares"
+  "shares": "https://alice.example.com/services/orb/shares",
+  "endpoints": {
+    "sharedInbox": "https://alice.example.com/services/orb/sharedinbox"
+  }
 }`
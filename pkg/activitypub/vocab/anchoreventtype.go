@@ -11,6 +11,7 @@ import (
 
 	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
 
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	"github.com/trustbloc/orb/pkg/hashlink"
 )
 
@@ -72,21 +73,20 @@ func (t *AnchorEventType) Validate() error {
 		return fmt.Errorf("marshal document: %w", err)
 	}
 
-	hlClient := hashlink.New()
-
-	hlInfo, err := hlClient.ParseHashLink(t.URL()[0].String())
+	hlInfo, err := hashlink.New().ParseHashLink(t.URL()[0].String())
 	if err != nil {
 		return fmt.Errorf("parse hashlink: %w", err)
 	}
 
-	hash, err := hlClient.CreateResourceHash(docBytes)
+	// The CAS may address content that spans multiple chunks by the root of its UnixFS DAG
+	// or by the hash of the entire content, so either hash is accepted.
+	ok, err := unixfs.MatchesResourceHash(docBytes, hlInfo.ResourceHash)
 	if err != nil {
 		return fmt.Errorf("create resource hash: %w", err)
 	}
 
-	if hlInfo.ResourceHash != hash {
-		return fmt.Errorf("hash or URL [%s] does not match the hash of the object [%s]",
-			hlInfo.ResourceHash, hash)
+	if !ok {
+		return fmt.Errorf("hash or URL [%s] does not match the hash of the object", hlInfo.ResourceHash)
 	}
 
 	return nil
@@ -22,6 +22,7 @@ type ObjectProperty struct {
 	activity    *ActivityType
 	doc         Document
 	anchorEvent *AnchorEventType
+	actor       *ActorType
 }
 
 // NewObjectProperty returns a new 'object' property with the given options.
@@ -36,6 +37,7 @@ func NewObjectProperty(opts ...Opt) *ObjectProperty {
 		activity:    options.Activity,
 		anchorEvent: options.AnchorEvent,
 		doc:         options.Document,
+		actor:       options.ActorObject,
 	}
 }
 
@@ -66,6 +68,10 @@ func (p *ObjectProperty) Type() *TypeProperty {
 		return p.anchorEvent.Type()
 	}
 
+	if p.actor != nil {
+		return p.actor.Type()
+	}
+
 	return nil
 }
 
@@ -133,6 +139,15 @@ func (p *ObjectProperty) AnchorEvent() *AnchorEventType {
 	return p.anchorEvent
 }
 
+// Actor returns the actor or nil if the actor is not set.
+func (p *ObjectProperty) Actor() *ActorType {
+	if p == nil {
+		return nil
+	}
+
+	return p.actor
+}
+
 // MarshalJSON marshals the 'object' property.
 func (p *ObjectProperty) MarshalJSON() ([]byte, error) {
 	if p.iri != nil {
@@ -159,6 +174,10 @@ func (p *ObjectProperty) MarshalJSON() ([]byte, error) {
 		return json.Marshal(p.anchorEvent)
 	}
 
+	if p.actor != nil {
+		return json.Marshal(p.actor)
+	}
+
 	if p.doc != nil {
 		return json.Marshal(p.doc)
 	}
@@ -208,6 +227,9 @@ func (p *ObjectProperty) UnmarshalJSON(bytes []byte) error {
 	case obj.object.Type.Is(TypeAnchorEvent):
 		err = p.unmarshalAnchorEvent(bytes)
 
+	case obj.object.Type.Is(TypeService):
+		err = p.unmarshalActor(bytes)
+
 	default:
 		p.obj = obj
 	}
@@ -251,6 +273,18 @@ func (p *ObjectProperty) unmarshalActivity(bytes []byte) error {
 	return nil
 }
 
+func (p *ObjectProperty) unmarshalActor(bytes []byte) error {
+	actor := &ActorType{}
+
+	if err := json.Unmarshal(bytes, &actor); err != nil {
+		return err
+	}
+
+	p.actor = actor
+
+	return nil
+}
+
 func (p *ObjectProperty) unmarshalAnchorEvent(bytes []byte) error {
 	ae := &AnchorEventType{}
 
@@ -45,6 +45,12 @@ func NewObject(opts ...Opt) *ObjectType {
 	}
 }
 
+// NewRejectionObject returns a new 'Rejection' object with the given machine-readable reason. The object
+// is included in the 'result' of a 'Reject' activity.
+func NewRejectionObject(reason string, opts ...Opt) (*ObjectType, error) {
+	return NewObjectWithDocument(Document{propertyReason: reason}, append(opts, WithType(TypeRejection))...)
+}
+
 // NewObjectWithDocument returns a new object initialized with the given document.
 func NewObjectWithDocument(doc Document, opts ...Opt) (*ObjectType, error) {
 	if doc == nil {
@@ -299,6 +305,22 @@ func (t *ObjectType) Value(key string) (interface{}, bool) {
 	return v, ok
 }
 
+// Reason returns the machine-readable reason of a 'Rejection' object. An empty string is returned if
+// no reason is specified.
+func (t *ObjectType) Reason() string {
+	v, ok := t.Value(propertyReason)
+	if !ok {
+		return ""
+	}
+
+	reason, ok := v.(string)
+	if !ok {
+		return ""
+	}
+
+	return reason
+}
+
 // MarshalJSON marshals the object.
 func (t *ObjectType) MarshalJSON() ([]byte, error) {
 	return MarshalJSON(t.object, t.additional)
@@ -181,6 +181,27 @@ func TestObjectType_Accessors(t *testing.T) {
 	require.Nil(t, o.Tag())
 	require.Empty(t, o.Generator())
 	require.Nil(t, o.AttributedTo())
+	require.Empty(t, o.Reason())
+}
+
+func TestNewRejectionObject(t *testing.T) {
+	anchor := testutil.MustParseURL("hl:uEiAnchor")
+
+	obj, err := NewRejectionObject("quota-exceeded", WithInReplyTo(anchor))
+	require.NoError(t, err)
+	require.True(t, obj.Type().Is(TypeRejection))
+	require.Equal(t, "quota-exceeded", obj.Reason())
+	require.Equal(t, anchor.String(), obj.InReplyTo().String())
+
+	bytes, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	obj2 := &ObjectType{}
+	require.NoError(t, json.Unmarshal(bytes, obj2))
+	require.Equal(t, "quota-exceeded", obj2.Reason())
+
+	obj2.additional[propertyReason] = 10
+	require.Empty(t, obj2.Reason())
 }
 
 const (
@@ -212,6 +212,7 @@ type ObjectPropertyOptions struct {
 	OrderedCollection *OrderedCollectionType
 	Activity          *ActivityType
 	Document          Document
+	ActorObject       *ActorType
 }
 
 // WithIRI sets the 'object' property to an IRI.
@@ -249,6 +250,13 @@ func WithActivity(activity *ActivityType) Opt {
 	}
 }
 
+// WithActorObject sets the 'object' property to an embedded actor.
+func WithActorObject(actor *ActorType) Opt {
+	return func(opts *Options) {
+		opts.ActorObject = actor
+	}
+}
+
 // ActivityOptions holds the options for an Activity.
 type ActivityOptions struct {
 	Result *ObjectProperty
@@ -279,16 +287,17 @@ func WithResult(result *ObjectProperty) Opt {
 
 // ActorOptions holds the options for an Activity.
 type ActorOptions struct {
-	PublicKey  *PublicKeyType
-	Inbox      *url.URL
-	Outbox     *url.URL
-	Followers  *url.URL
-	Following  *url.URL
-	Witnesses  *url.URL
-	Witnessing *url.URL
-	Liked      *url.URL
-	Likes      *url.URL
-	Shares     *url.URL
+	PublicKey   *PublicKeyType
+	Inbox       *url.URL
+	SharedInbox *url.URL
+	Outbox      *url.URL
+	Followers   *url.URL
+	Following   *url.URL
+	Witnesses   *url.URL
+	Witnessing  *url.URL
+	Liked       *url.URL
+	Likes       *url.URL
+	Shares      *url.URL
 }
 
 // WithPublicKey sets the 'publicKey' property on the actor.
@@ -305,6 +314,13 @@ func WithInbox(inbox *url.URL) Opt {
 	}
 }
 
+// WithSharedInbox sets the 'sharedInbox' property in the 'endpoints' of the actor.
+func WithSharedInbox(sharedInbox *url.URL) Opt {
+	return func(opts *Options) {
+		opts.SharedInbox = sharedInbox
+	}
+}
+
 // WithOutbox sets the 'outbox' property on the actor.
 func WithOutbox(outbox *url.URL) Opt {
 	return func(opts *Options) {
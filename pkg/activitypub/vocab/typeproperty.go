@@ -113,7 +113,7 @@ func (p *TypeProperty) IsAny(types ...Type) bool {
 // IsActivity returns true if the type is an ActivityPub Activity.
 func (p *TypeProperty) IsActivity() bool {
 	return p.IsAny(TypeFollow, TypeAccept, TypeReject, TypeOffer, TypeLike, TypeInvite,
-		TypeCreate, TypeAnnounce, TypeUndo)
+		TypeCreate, TypeAnnounce, TypeUndo, TypeUpdate, TypeDelete, TypeBlock)
 }
 
 func (p *TypeProperty) is(t Type) bool {
@@ -69,10 +69,20 @@ const (
 
 	// TypeAnchorReceipt specifies the "AnchorReceipt" object type.
 	TypeAnchorReceipt Type = "AnchorReceipt"
+
+	// TypeRejection specifies the "Rejection" object type which may be included in the 'result' of a 'Reject'
+	// activity in order to provide a machine-readable reason for the rejection.
+	TypeRejection Type = "Rejection"
 	// TypeOffer specifies the "Offer" activity type.
 	TypeOffer Type = "Offer"
 	// TypeUndo specifies the "Undo" activity type.
 	TypeUndo Type = "Undo"
+	// TypeUpdate specifies the "Update" activity type.
+	TypeUpdate Type = "Update"
+	// TypeDelete specifies the "Delete" activity type.
+	TypeDelete Type = "Delete"
+	// TypeBlock specifies the "Block" activity type.
+	TypeBlock Type = "Block"
 )
 
 const (
@@ -103,6 +113,7 @@ const (
 	propertyAttachment   = "attachment"
 	propertyIndex        = "index"
 	propertyParent       = "parent"
+	propertyReason       = "reason"
 )
 
 // MediaType defines a type of encoding for content embedded within a document.
@@ -25,11 +25,13 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/util"
 	"github.com/trustbloc/orb/pkg/anchor/vcpubsub"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	proofapi "github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	"github.com/trustbloc/orb/pkg/datauri"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/linkset"
 	"github.com/trustbloc/orb/pkg/pubsub/spi"
+	witnessstore "github.com/trustbloc/orb/pkg/store/witness"
 	"github.com/trustbloc/orb/pkg/vcsigner"
 	"github.com/trustbloc/orb/pkg/vct"
 )
@@ -68,6 +70,12 @@ type Providers struct {
 	MonitoringSvc   monitoringSvc
 	DocLoader       ld.DocumentLoader
 	Metrics         metricsProvider
+
+	// Revocations (optional) is used to ignore proofs from revoked witnesses.
+	Revocations revocationStore
+	// PolicyScopes (optional) provides the scope that's used to resolve the witness policies of an anchor.
+	// If not set then the global witness policy applies.
+	PolicyScopes policyScopeStore
 }
 
 // WitnessProofHandler handles an anchor credential witness proof.
@@ -92,12 +100,20 @@ type statusStore interface {
 	GetStatus(anchorEventID string) (proofapi.AnchorIndexStatus, error)
 }
 
+type revocationStore interface {
+	GetRevocation(witness *url.URL) (*witnessstore.Revocation, error)
+}
+
+type policyScopeStore interface {
+	GetPolicyScope(anchorID string) (*policycfg.AnchorScope, error)
+}
+
 type monitoringSvc interface {
 	Watch(vc *verifiable.Credential, endTime time.Time, domain string, created time.Time) error
 }
 
 type witnessPolicy interface {
-	Evaluate(witnesses []*proofapi.WitnessProof) (bool, error)
+	EvaluateForScope(scope *policycfg.AnchorScope, witnesses []*proofapi.WitnessProof) (bool, error)
 }
 
 // HandleProof handles proof.
@@ -112,23 +128,14 @@ func (h *WitnessProofHandler) HandleProof(ctx context.Context, witness *url.URL,
 		return fmt.Errorf("failed to unmarshal incoming witness proof for anchor [%s]: %w", anchor, err)
 	}
 
-	anchorLink, err := h.AnchorLinkStore.Get(anchor)
+	anchorLink, vc, err := h.getAnchorLink(anchor)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve anchor link [%s]: %w", anchor, err)
-	}
-
-	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
-		verifiable.WithDisabledProofCheck(),
-		verifiable.WithJSONLDDocumentLoader(h.DocLoader),
-		verifiable.WithStrictValidation(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed get verifiable credential from anchor: %w", err)
+		return err
 	}
 
 	vcIssuedTime := vc.Issued.Time
 
-	proofCreatedTime, err := getCreatedTime(witnessProof)
+	proofCreatedTime, err := getCreatedTime(witnessProof.Proof)
 	if err != nil {
 		return fmt.Errorf("failed to get create time from witness[%s] proof for anchor[%s] : %w",
 			witness.String(), anchor, err)
@@ -147,6 +154,23 @@ func (h *WitnessProofHandler) HandleProof(ctx context.Context, witness *url.URL,
 		return nil
 	}
 
+	proof, err = filterAdditionalProofs(&witnessProof, proof, witness, anchor, startTimeForProof, endTimeForProof)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := h.isRevoked(witness, proofCreatedTime)
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		logger.Warn("Ignoring proof for anchor from revoked witness.", logfields.WithAnchorURIString(anchor),
+			logfields.WithActorIRI(witness), logfields.WithCreatedTime(proofCreatedTime))
+
+		return nil
+	}
+
 	status, err := h.StatusStore.GetStatus(anchor)
 	if err != nil {
 		if !errors.Is(err, orberrors.ErrContentNotFound) {
@@ -169,12 +193,106 @@ func (h *WitnessProofHandler) HandleProof(ctx context.Context, witness *url.URL,
 			witness.String(), anchor, err)
 	}
 
+	_, err = h.handleWitnessPolicy(ctx, anchorLink, vc)
+
+	return err
+}
+
+// ReevaluatePolicy evaluates the witness policy of the given anchor against the proofs that are currently in the
+// witness store and, if the policy is satisfied, publishes the anchor with the witness proofs. This function is
+// used when an anchor is witnessed again after the proof of a witness was revoked. True is returned if the
+// witness policy was satisfied.
+func (h *WitnessProofHandler) ReevaluatePolicy(ctx context.Context, anchor string) (bool, error) {
+	anchorLink, vc, err := h.getAnchorLink(anchor)
+	if err != nil {
+		return false, err
+	}
+
 	return h.handleWitnessPolicy(ctx, anchorLink, vc)
 }
 
-func getCreatedTime(wp vct.Proof) (time.Time, error) {
+func (h *WitnessProofHandler) getAnchorLink(anchor string) (*linkset.Link, *verifiable.Credential, error) {
+	anchorLink, err := h.AnchorLinkStore.Get(anchor)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve anchor link [%s]: %w", anchor, err)
+	}
+
+	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(h.DocLoader),
+		verifiable.WithStrictValidation(),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed get verifiable credential from anchor: %w", err)
+	}
+
+	return anchorLink, vc, nil
+}
+
+// isRevoked returns true if the given witness has been revoked and the revocation applies to a proof
+// that was created at the given time.
+func (h *WitnessProofHandler) isRevoked(witness *url.URL, proofCreatedTime time.Time) (bool, error) {
+	if h.Revocations == nil {
+		return false, nil
+	}
+
+	revocation, err := h.Revocations.GetRevocation(witness)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("get revocation for witness[%s]: %w", witness, err)
+	}
+
+	return revocation.Applies(proofCreatedTime), nil
+}
+
+// filterAdditionalProofs removes the additional (mirror log) proofs whose created time is invalid or outside of the
+// given time window. The (re-marshalled) proof is returned if any of the additional proofs were removed, otherwise
+// the original proof is returned.
+func filterAdditionalProofs(witnessProof *vct.Proof, proof []byte, witness *url.URL, anchor string,
+	startTime, endTime time.Time,
+) ([]byte, error) {
+	var additionalProofs []verifiable.Proof
+
+	for _, p := range witnessProof.AdditionalProofs {
+		createdTime, err := getCreatedTime(p)
+		if err != nil {
+			logger.Info("Ignoring additional proof for anchor from witness with invalid created time.",
+				logfields.WithAnchorURIString(anchor), logfields.WithActorIRI(witness), log.WithError(err))
+
+			continue
+		}
+
+		if createdTime.Before(startTime) || createdTime.After(endTime) {
+			logger.Info("Ignoring additional proof for anchor from witness since the created time is either "+
+				"too early or too late.", logfields.WithCreatedTime(createdTime),
+				logfields.WithAnchorURIString(anchor), logfields.WithActorIRI(witness))
+
+			continue
+		}
+
+		additionalProofs = append(additionalProofs, p)
+	}
+
+	if len(additionalProofs) == len(witnessProof.AdditionalProofs) {
+		return proof, nil
+	}
+
+	witnessProof.AdditionalProofs = additionalProofs
+
+	proofBytes, err := json.Marshal(witnessProof)
+	if err != nil {
+		return nil, fmt.Errorf("marshal witness[%s] proof for anchor[%s]: %w", witness, anchor, err)
+	}
+
+	return proofBytes, nil
+}
+
+func getCreatedTime(p verifiable.Proof) (time.Time, error) {
 	var created string
-	if createdVal, ok := wp.Proof["created"].(string); ok {
+	if createdVal, ok := p["created"].(string); ok {
 		created = createdVal
 	}
 
@@ -187,19 +305,26 @@ func getCreatedTime(wp vct.Proof) (time.Time, error) {
 }
 
 //nolint:cyclop
-func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLink *linkset.Link, vc *verifiable.Credential) error {
+func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLink *linkset.Link,
+	vc *verifiable.Credential,
+) (bool, error) {
 	anchorID := anchorLink.Anchor().String()
 
 	logger.Debug("Handling witness policy for anchor link", logfields.WithAnchorURIString(anchorID))
 
 	witnessProofs, err := h.WitnessStore.Get(anchorID)
 	if err != nil {
-		return fmt.Errorf("failed to get witness proofs for anchor [%s]: %w", anchorID, err)
+		return false, fmt.Errorf("failed to get witness proofs for anchor [%s]: %w", anchorID, err)
 	}
 
-	ok, err := h.WitnessPolicy.Evaluate(witnessProofs)
+	scope, err := h.getPolicyScope(anchorID)
 	if err != nil {
-		return fmt.Errorf("failed to evaluate witness policy for anchor [%s]: %w", anchorID, err)
+		return false, err
+	}
+
+	ok, err := h.WitnessPolicy.EvaluateForScope(scope, witnessProofs)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate witness policy for anchor [%s]: %w", anchorID, err)
 	}
 
 	if !ok {
@@ -207,7 +332,7 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 		logger.Info("Witness policy has not been satisfied for anchor. Waiting for other proofs.",
 			logfields.WithAnchorURIString(anchorID))
 
-		return nil
+		return false, nil
 	}
 
 	// Witness policy has been satisfied so add witness proofs to anchor, set 'complete' status for anchor
@@ -217,13 +342,13 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 
 	vc, err = addProofs(vc, witnessProofs)
 	if err != nil {
-		return fmt.Errorf("failed to add witness proofs: %w", err)
+		return false, fmt.Errorf("failed to add witness proofs: %w", err)
 	}
 
 	status, err := h.StatusStore.GetStatus(anchorID)
 	if err != nil {
 		if !errors.Is(err, orberrors.ErrContentNotFound) {
-			return fmt.Errorf("failed to get status for anchor [%s]: %w", anchorID, err)
+			return false, fmt.Errorf("failed to get status for anchor [%s]: %w", anchorID, err)
 		}
 	}
 
@@ -231,7 +356,7 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 		logger.Info("Anchor status has already been marked as completed for", logfields.WithAnchorURIString(anchorID),
 			logfields.WithVerifiableCredentialID(vc.ID))
 
-		return nil
+		return true, nil
 	}
 
 	// Publish the VC before setting the status to completed since, if the publisher returns a transient error,
@@ -243,12 +368,12 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 
 	vcBytes, err := canonicalizer.MarshalCanonical(vc)
 	if err != nil {
-		return fmt.Errorf("create new object with document: %w", err)
+		return false, fmt.Errorf("create new object with document: %w", err)
 	}
 
 	vcDataURI, err := datauri.New(vcBytes, h.dataURIMediaType)
 	if err != nil {
-		return fmt.Errorf("create data URI from VC: %w", err)
+		return false, fmt.Errorf("create data URI from VC: %w", err)
 	}
 
 	// Create a new anchor with the updated verifiable credential.
@@ -260,7 +385,7 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 
 	err = h.publisher.Publish(ctx, linkset.New(anchorLink))
 	if err != nil {
-		return fmt.Errorf("publish credential[%s]: %w", anchorID, err)
+		return false, fmt.Errorf("publish credential[%s]: %w", anchorID, err)
 	}
 
 	logger.Info("Setting anchor status to completed", logfields.WithAnchorURIString(anchorID),
@@ -268,7 +393,7 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 
 	err = h.StatusStore.AddStatus(anchorID, proofapi.AnchorIndexStatusCompleted)
 	if err != nil {
-		return fmt.Errorf("failed to change status to 'completed' for anchor [%s], VC [%s]: %w",
+		return false, fmt.Errorf("failed to change status to 'completed' for anchor [%s], VC [%s]: %w",
 			anchorID, vc.ID, err)
 	}
 
@@ -276,7 +401,7 @@ func (h *WitnessProofHandler) handleWitnessPolicy(ctx context.Context, anchorLin
 		h.Metrics.WitnessAnchorCredentialTime(time.Since(vc.Issued.Time))
 	}
 
-	return nil
+	return true, nil
 }
 
 func addProofs(vc *verifiable.Credential, proofs []*proofapi.WitnessProof) (*verifiable.Credential, error) {
@@ -289,14 +414,17 @@ func addProofs(vc *verifiable.Credential, proofs []*proofapi.WitnessProof) (*ver
 				return nil, fmt.Errorf("failed to unmarshal stored witness proof for anchor credential[%s]: %w", vc.ID, err)
 			}
 
-			if !proofExists(vc.Proofs, witnessProof.Proof) {
-				logger.Debug("Adding witness proof", logfields.WithProofDocument(witnessProof.Proof))
+			// A witness may provide a proof from each of its logs (primary and mirrors).
+			for _, proof := range witnessProof.Proofs() {
+				if !proofExists(vc.Proofs, proof) {
+					logger.Debug("Adding witness proof", logfields.WithProofDocument(proof))
 
-				vc.Context = addContextsFromProof(vc.Context, witnessProof)
+					vc.Context = addContextsFromProof(vc.Context, proof)
 
-				vc.Proofs = append(vc.Proofs, witnessProof.Proof)
-			} else {
-				logger.Debug("Not adding witness proof since it already exists", logfields.WithProofDocument(witnessProof.Proof))
+					vc.Proofs = append(vc.Proofs, proof)
+				} else {
+					logger.Debug("Not adding witness proof since it already exists", logfields.WithProofDocument(proof))
+				}
 			}
 		}
 	}
@@ -314,8 +442,8 @@ func proofExists(proofs []verifiable.Proof, proof verifiable.Proof) bool {
 	return false
 }
 
-func addContextsFromProof(contexts []string, witnessProof vct.Proof) []string {
-	proofType := witnessProof.Proof["type"]
+func addContextsFromProof(contexts []string, proof verifiable.Proof) []string {
+	proofType := proof["type"]
 
 	switch proofType {
 	case vcsigner.Ed25519Signature2020:
@@ -338,3 +466,22 @@ func add(values []string, value string) []string {
 
 	return append(values, value)
 }
+
+// getPolicyScope returns the scope that's used to resolve the witness policies of the anchor. Nil is returned
+// if no scope was stored for the anchor, in which case the global witness policy applies.
+func (h *WitnessProofHandler) getPolicyScope(anchorID string) (*policycfg.AnchorScope, error) {
+	if h.PolicyScopes == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	scope, err := h.PolicyScopes.GetPolicyScope(anchorID)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("get policy scope for anchor [%s]: %w", anchorID, err)
+	}
+
+	return scope, nil
+}
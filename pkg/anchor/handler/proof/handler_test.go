@@ -15,14 +15,17 @@ import (
 	"time"
 
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/handler/mocks"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	policymocks "github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
 	proofapi "github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	"github.com/trustbloc/orb/pkg/datauri"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/linkset"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
@@ -31,6 +34,7 @@ import (
 	"github.com/trustbloc/orb/pkg/store/anchorstatus"
 	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
 	"github.com/trustbloc/orb/pkg/store/witness"
+	"github.com/trustbloc/orb/pkg/vct"
 )
 
 //go:generate counterfeiter -o ../mocks/anchorindexstatus.gen.go --fake-name AnchorIndexStatusStore . statusStore
@@ -865,6 +869,355 @@ func TestWitnessProofHandler(t *testing.T) {
 	})
 }
 
+func TestAddProofs(t *testing.T) {
+	t.Run("primary and mirror log proofs", func(t *testing.T) {
+		vc := &verifiable.Credential{ID: anchorID}
+
+		proofs := []*proofapi.WitnessProof{
+			{
+				Proof: []byte(`{"proof":{"domain":"https://vct1.com/log","type":"Ed25519Signature2020"},` +
+					`"additionalProofs":[{"domain":"https://vct2.com/log","type":"Ed25519Signature2020"}]}`),
+			},
+			{
+				// Proofs that already exist in the credential are not added again.
+				Proof: []byte(`{"proof":{"domain":"https://vct1.com/log","type":"Ed25519Signature2020"}}`),
+			},
+			{},
+		}
+
+		vc, err := addProofs(vc, proofs)
+		require.NoError(t, err)
+		require.Len(t, vc.Proofs, 2)
+		require.Equal(t, "https://vct1.com/log", vc.Proofs[0]["domain"])
+		require.Equal(t, "https://vct2.com/log", vc.Proofs[1]["domain"])
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		_, err := addProofs(&verifiable.Credential{ID: anchorID}, []*proofapi.WitnessProof{{Proof: []byte(`{`)}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal stored witness proof")
+	})
+}
+
+func TestWitnessProofHandler_Revocation(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	witness1IRI := testutil.MustParseURL(witnessURL)
+
+	expiryTime := time.Now().Add(60 * time.Second)
+
+	als := &linkset.Linkset{}
+	require.NoError(t, json.Unmarshal([]byte(anchorLinkset), als))
+
+	al := als.Link()
+	require.NotNil(t, al)
+
+	newProviders := func(t *testing.T, revocations revocationStore) (*Providers, *mocks.WitnessStore) {
+		t.Helper()
+
+		aeStore, err := anchorlinkstore.New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NoError(t, aeStore.Put(al))
+
+		statusStore, err := anchorstatus.New(mem.NewProvider(), testutil.GetTaskMgr(t), testutil.GetExpiryService(t), time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, statusStore.AddStatus(al.Anchor().String(), proofapi.AnchorIndexStatusInProcess))
+
+		witnessStore := &mocks.WitnessStore{}
+
+		return &Providers{
+			AnchorLinkStore: aeStore,
+			StatusStore:     statusStore,
+			WitnessStore:    witnessStore,
+			WitnessPolicy:   &mockWitnessPolicy{eval: false},
+			Metrics:         &orbmocks.MetricsProvider{},
+			DocLoader:       testutil.GetLoader(t),
+			Revocations:     revocations,
+		}, witnessStore
+	}
+
+	t.Run("proof created after revocation -> ignored", func(t *testing.T) {
+		providers, witnessStore := newProviders(t, &mockRevocationStore{
+			revocation: &witness.Revocation{Since: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		})
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		err := proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(), expiryTime,
+			[]byte(witnessProofJSONWebSignature))
+		require.NoError(t, err)
+		require.Zero(t, witnessStore.AddProofCallCount())
+	})
+
+	t.Run("proof created before revocation -> added", func(t *testing.T) {
+		providers, witnessStore := newProviders(t, &mockRevocationStore{
+			revocation: &witness.Revocation{Since: time.Now()},
+		})
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		err := proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(), expiryTime,
+			[]byte(witnessProofJSONWebSignature))
+		require.NoError(t, err)
+		require.Equal(t, 1, witnessStore.AddProofCallCount())
+	})
+
+	t.Run("witness not revoked -> added", func(t *testing.T) {
+		providers, witnessStore := newProviders(t, &mockRevocationStore{err: orberrors.ErrContentNotFound})
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		err := proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(), expiryTime,
+			[]byte(witnessProofJSONWebSignature))
+		require.NoError(t, err)
+		require.Equal(t, 1, witnessStore.AddProofCallCount())
+	})
+
+	t.Run("error - get revocation error", func(t *testing.T) {
+		providers, _ := newProviders(t, &mockRevocationStore{err: fmt.Errorf("injected revocation error")})
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		err := proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(), expiryTime,
+			[]byte(witnessProofJSONWebSignature))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected revocation error")
+	})
+}
+
+func TestWitnessProofHandler_AdditionalProofs(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	witness1IRI := testutil.MustParseURL(witnessURL)
+
+	als := &linkset.Linkset{}
+	require.NoError(t, json.Unmarshal([]byte(anchorLinkset), als))
+
+	al := als.Link()
+	require.NotNil(t, al)
+
+	aeStore, err := anchorlinkstore.New(mem.NewProvider())
+	require.NoError(t, err)
+	require.NoError(t, aeStore.Put(al))
+
+	statusStore, err := anchorstatus.New(mem.NewProvider(), testutil.GetTaskMgr(t), testutil.GetExpiryService(t), time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, statusStore.AddStatus(al.Anchor().String(), proofapi.AnchorIndexStatusInProcess))
+
+	witnessStore := &mocks.WitnessStore{}
+
+	providers := &Providers{
+		AnchorLinkStore: aeStore,
+		StatusStore:     statusStore,
+		WitnessStore:    witnessStore,
+		WitnessPolicy:   &mockWitnessPolicy{eval: false},
+		Metrics:         &orbmocks.MetricsProvider{},
+		DocLoader:       testutil.GetLoader(t),
+	}
+
+	// The credential was issued at 2022-03-15T21:21:54.62Z.
+	proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, time.Minute)
+
+	t.Run("additional proofs outside of the time window are removed", func(t *testing.T) {
+		p := `{"proof":{"created":"2022-03-15T21:21:55Z","domain":"https://vct1.com/log"},` +
+			`"additionalProofs":[` +
+			`{"created":"2022-03-15T21:21:56Z","domain":"https://vct2.com/log"},` +
+			`{"created":"2021-01-01T00:00:00Z","domain":"https://vct3.com/log"},` +
+			`{"domain":"https://vct4.com/log"}]}`
+
+		err := proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(),
+			time.Date(2022, 3, 15, 21, 30, 0, 0, time.UTC), []byte(p))
+		require.NoError(t, err)
+		require.Equal(t, 1, witnessStore.AddProofCallCount())
+
+		_, _, storedProof := witnessStore.AddProofArgsForCall(0)
+
+		witnessProof := &vct.Proof{}
+		require.NoError(t, json.Unmarshal(storedProof, witnessProof))
+		require.Equal(t, "https://vct1.com/log", witnessProof.Proof["domain"])
+		require.Len(t, witnessProof.AdditionalProofs, 1)
+		require.Equal(t, "https://vct2.com/log", witnessProof.AdditionalProofs[0]["domain"])
+	})
+
+	t.Run("primary proof outside of the time window -> ignored", func(t *testing.T) {
+		p := `{"proof":{"created":"2021-01-01T00:00:00Z","domain":"https://vct1.com/log"},` +
+			`"additionalProofs":[{"created":"2022-03-15T21:21:56Z","domain":"https://vct2.com/log"}]}`
+
+		err := proofHandler.HandleProof(context.Background(), witness1IRI, al.Anchor().String(),
+			time.Date(2022, 3, 15, 21, 30, 0, 0, time.UTC), []byte(p))
+		require.NoError(t, err)
+		require.Equal(t, 1, witnessStore.AddProofCallCount())
+	})
+}
+
+func TestWitnessProofHandler_ReevaluatePolicy(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	witness1IRI := testutil.MustParseURL(witnessURL)
+
+	als := &linkset.Linkset{}
+	require.NoError(t, json.Unmarshal([]byte(anchorLinksetTwoProofs), als))
+
+	al := als.Link()
+	require.NotNil(t, al)
+
+	aeStore, err := anchorlinkstore.New(mem.NewProvider())
+	require.NoError(t, err)
+	require.NoError(t, aeStore.Put(al))
+
+	witnessStore := &mocks.WitnessStore{}
+	witnessStore.GetReturns(
+		[]*proofapi.WitnessProof{
+			{
+				Witness: &proofapi.Witness{
+					Type:   proofapi.WitnessTypeSystem,
+					URI:    vocab.NewURLProperty(witness1IRI),
+					HasLog: true,
+				},
+				Proof: []byte(witnessProofJSONWebSignature),
+			},
+		}, nil)
+
+	newProviders := func(t *testing.T, eval bool) *Providers {
+		t.Helper()
+
+		statusStore, err := anchorstatus.New(mem.NewProvider(), testutil.GetTaskMgr(t), testutil.GetExpiryService(t), time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, statusStore.Reopen(al.Anchor().String()))
+
+		return &Providers{
+			AnchorLinkStore: aeStore,
+			StatusStore:     statusStore,
+			WitnessStore:    witnessStore,
+			WitnessPolicy:   &mockWitnessPolicy{eval: eval},
+			Metrics:         &orbmocks.MetricsProvider{},
+			DocLoader:       testutil.GetLoader(t),
+		}
+	}
+
+	t.Run("policy satisfied", func(t *testing.T) {
+		providers := newProviders(t, true)
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		satisfied, err := proofHandler.ReevaluatePolicy(context.Background(), al.Anchor().String())
+		require.NoError(t, err)
+		require.True(t, satisfied)
+
+		status, err := providers.StatusStore.GetStatus(al.Anchor().String())
+		require.NoError(t, err)
+		require.Equal(t, proofapi.AnchorIndexStatusCompleted, status)
+	})
+
+	t.Run("policy not satisfied", func(t *testing.T) {
+		providers := newProviders(t, false)
+
+		proofHandler := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		satisfied, err := proofHandler.ReevaluatePolicy(context.Background(), al.Anchor().String())
+		require.NoError(t, err)
+		require.False(t, satisfied)
+
+		status, err := providers.StatusStore.GetStatus(al.Anchor().String())
+		require.NoError(t, err)
+		require.Equal(t, proofapi.AnchorIndexStatusInProcess, status)
+	})
+
+	t.Run("error - anchor link not found", func(t *testing.T) {
+		proofHandler := New(newProviders(t, true), ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew)
+
+		satisfied, err := proofHandler.ReevaluatePolicy(context.Background(), anchorID)
+		require.Error(t, err)
+		require.False(t, satisfied)
+		require.Contains(t, err.Error(), "failed to retrieve anchor link")
+	})
+}
+
+func TestWitnessProofHandler_PolicyScope(t *testing.T) {
+	ps := mempubsub.New(mempubsub.Config{})
+	defer ps.Stop()
+
+	als := &linkset.Linkset{}
+	require.NoError(t, json.Unmarshal([]byte(anchorLinksetTwoProofs), als))
+
+	al := als.Link()
+	require.NotNil(t, al)
+
+	aeStore, err := anchorlinkstore.New(mem.NewProvider())
+	require.NoError(t, err)
+	require.NoError(t, aeStore.Put(al))
+
+	scope := &policycfg.AnchorScope{Namespace: "did:orb", Origins: []string{"https://orb.domain1.com"}}
+
+	newProviders := func(t *testing.T, scopes *mockPolicyScopeStore) (*Providers, *mockWitnessPolicy) {
+		t.Helper()
+
+		statusStore, err := anchorstatus.New(mem.NewProvider(), testutil.GetTaskMgr(t), testutil.GetExpiryService(t), time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, statusStore.Reopen(al.Anchor().String()))
+
+		wp := &mockWitnessPolicy{}
+
+		return &Providers{
+			AnchorLinkStore: aeStore,
+			StatusStore:     statusStore,
+			WitnessStore:    &mocks.WitnessStore{},
+			WitnessPolicy:   wp,
+			Metrics:         &orbmocks.MetricsProvider{},
+			DocLoader:       testutil.GetLoader(t),
+			PolicyScopes:    scopes,
+		}, wp
+	}
+
+	t.Run("scope found", func(t *testing.T) {
+		providers, wp := newProviders(t, &mockPolicyScopeStore{scope: scope})
+
+		_, err := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew).
+			ReevaluatePolicy(context.Background(), al.Anchor().String())
+		require.NoError(t, err)
+		require.Equal(t, scope, wp.scope)
+	})
+
+	t.Run("scope not found -> global policy", func(t *testing.T) {
+		providers, wp := newProviders(t, &mockPolicyScopeStore{err: orberrors.ErrContentNotFound})
+
+		_, err := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew).
+			ReevaluatePolicy(context.Background(), al.Anchor().String())
+		require.NoError(t, err)
+		require.Nil(t, wp.scope)
+	})
+
+	t.Run("error - get scope", func(t *testing.T) {
+		providers, _ := newProviders(t, &mockPolicyScopeStore{err: fmt.Errorf("injected scope error")})
+
+		_, err := New(providers, ps, datauri.MediaTypeDataURIGzipBase64, defaultClockSkew).
+			ReevaluatePolicy(context.Background(), al.Anchor().String())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected scope error")
+	})
+}
+
+type mockPolicyScopeStore struct {
+	scope *policycfg.AnchorScope
+	err   error
+}
+
+func (m *mockPolicyScopeStore) GetPolicyScope(string) (*policycfg.AnchorScope, error) {
+	return m.scope, m.err
+}
+
+type mockRevocationStore struct {
+	revocation *witness.Revocation
+	err        error
+}
+
+func (m *mockRevocationStore) GetRevocation(*url.URL) (*witness.Revocation, error) {
+	return m.revocation, m.err
+}
+
 type mockWitnessStore struct {
 	WitnessProof []*proofapi.WitnessProof
 	AddProofErr  error
@@ -888,11 +1241,14 @@ func (w *mockWitnessStore) Get(_ string) ([]*proofapi.WitnessProof, error) {
 }
 
 type mockWitnessPolicy struct {
-	eval bool
-	Err  error
+	eval  bool
+	Err   error
+	scope *policycfg.AnchorScope
 }
 
-func (wp *mockWitnessPolicy) Evaluate(_ []*proofapi.WitnessProof) (bool, error) {
+func (wp *mockWitnessPolicy) EvaluateForScope(scope *policycfg.AnchorScope, _ []*proofapi.WitnessProof) (bool, error) {
+	wp.scope = scope
+
 	if wp.Err != nil {
 		return false, wp.Err
 	}
@@ -302,6 +302,51 @@ func (s *Store) getLinks(anchorHash, query string) ([]*url.URL, error) {
 	return links, nil
 }
 
+// ForEachLink invokes the given function for each processed and pending anchor link reference in the store.
+// Iteration stops if the function returns an error.
+func (s *Store) ForEachLink(fn func(link *url.URL) error) error {
+	iter, err := s.store.Query(hashTag)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to query anchor refs: %w", err))
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+	}
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator value: %w", e))
+		}
+
+		linkRef := anchorLinkRef{}
+
+		if e = s.unmarshal(value, &linkRef); e != nil {
+			return fmt.Errorf("unmarshal link [%s]: %w", value, e)
+		}
+
+		u, e := url.Parse(linkRef.URL)
+		if e != nil {
+			return fmt.Errorf("parse link [%s]: %w", linkRef.URL, e)
+		}
+
+		if err = fn(u); err != nil {
+			return err
+		}
+
+		ok, err = iter.Next()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+		}
+	}
+
+	return nil
+}
+
 // HandleExpiredKeys is invoked by the data expiration handler.
 func (s *Store) HandleExpiredKeys(keys ...string) ([]string, error) {
 	var keysToDelete []string
@@ -266,6 +266,42 @@ func TestStore_DeleteLinks(t *testing.T) {
 	})
 }
 
+func TestStore_ForEachLink(t *testing.T) {
+	s, err := New(storage.NewMockStoreProvider(), &mocks.DataExpiryService{})
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	const hash1 = "uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ"
+	const hash2 = "uEiBUQDRI5ttIzXbe1LZKUaZWb6yFsnMnrgDksAtQ-wCaKw"
+
+	link1 := testutil.MustParseURL(fmt.Sprintf("hl:%s:uoQ-BeEtodmdEa3NBdFEtd0NhS3c", hash1))
+	link2 := testutil.MustParseURL(fmt.Sprintf("hl:%s:uoQ-BeEtodzZ4OVhtYkNTZjRfTWc", hash2))
+
+	require.NoError(t, s.PutLinks([]*url.URL{link1}))
+	require.NoError(t, s.PutPendingLinks([]*url.URL{link2}))
+
+	t.Run("Success", func(t *testing.T) {
+		var links []string
+
+		require.NoError(t, s.ForEachLink(func(link *url.URL) error {
+			links = append(links, link.String())
+
+			return nil
+		}))
+
+		require.ElementsMatch(t, []string{link1.String(), link2.String()}, links)
+	})
+
+	t.Run("Function error", func(t *testing.T) {
+		errExpected := errors.New("injected error")
+
+		err := s.ForEachLink(func(link *url.URL) error {
+			return errExpected
+		})
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
 func TestStore_HandleExpiredKeys(t *testing.T) {
 	const (
 		key1 = "key1"
@@ -16,6 +16,10 @@ import (
 	"github.com/trustbloc/orb/pkg/linkset"
 )
 
+// VCStoreTagName is the tag used for querying all verifiable credentials in the VC store. It's the same as the
+// name of the ID field of a stored credential.
+const VCStoreTagName = "id"
+
 // VerifiableCredentialFromAnchorLink validates the AnchorEvent and returns the embedded verifiable credential.
 func VerifiableCredentialFromAnchorLink(anchorLink *linkset.Link, opts ...verifiable.CredentialOpt) (*verifiable.Credential, error) {
 	if err := anchorLink.Validate(); err != nil {
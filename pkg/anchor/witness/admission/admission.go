@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admission
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+)
+
+var logger = log.New("offer-admission")
+
+const (
+	// AnchorOriginType defines the 'anchor-origin' accept list type which contains the anchor origins
+	// (i.e. the actors of the offers) from which offers are admitted (if the origin allow list is enabled).
+	AnchorOriginType = "anchor-origin"
+
+	// maxActors is the number of actor quotas after which expired quotas are purged.
+	maxActors = 10000
+)
+
+type acceptListMgr interface {
+	Get(acceptType string) ([]*url.URL, error)
+}
+
+type metricsProvider interface {
+	WitnessIncrementOfferRejectedCount(reason string)
+}
+
+// Config holds the admission control parameters for incoming offers. A value of zero (or less) disables the
+// corresponding limit.
+type Config struct {
+	// MaxOffersPerMinute is the maximum number of offers that are admitted from a single actor per minute.
+	MaxOffersPerMinute int
+	// MaxOffersPerDay is the maximum number of offers that are admitted from a single actor per day.
+	MaxOffersPerDay int
+	// MaxAnchorSize is the maximum size (in bytes) of an offered anchor.
+	MaxAnchorSize int
+	// OriginAllowListEnabled indicates that offers are only admitted from the actors in the
+	// 'anchor-origin' accept list.
+	OriginAllowListEnabled bool
+}
+
+// Enabled returns true if any of the admission controls is enabled.
+func (c *Config) Enabled() bool {
+	return c.MaxOffersPerMinute > 0 || c.MaxOffersPerDay > 0 || c.MaxAnchorSize > 0 || c.OriginAllowListEnabled
+}
+
+// Controller determines whether or not an offer from a given actor and anchor origin should be witnessed. Offers
+// are rejected if the anchor is too large, if the actor is not in the allow list (when enabled), or if the actor
+// has exceeded its per-minute or per-day quota. The allow list and quotas are keyed on the actor of the offer
+// (which is authenticated by the inbox) rather than on the anchor origin, since the origin is declared by the
+// sender and could otherwise be used to bypass the allow list or to exhaust the quota of another server. An offer
+// is charged against the quota only once, so a redelivery of an admitted offer is admitted again. Note that quotas
+// are maintained in memory, i.e. each server instance in a cluster enforces the quotas independently.
+type Controller struct {
+	*Config
+
+	acceptList acceptListMgr
+	metrics    metricsProvider
+	now        func() time.Time
+
+	mutex  sync.Mutex
+	actors map[string]*quota
+}
+
+// New returns a new offer admission controller.
+func New(cfg Config, acceptList acceptListMgr, metrics metricsProvider) *Controller {
+	return &Controller{
+		Config:     &cfg,
+		acceptList: acceptList,
+		metrics:    metrics,
+		now:        time.Now,
+		actors:     make(map[string]*quota),
+	}
+}
+
+// AdmitOffer returns nil if the offer with the given ID for an anchor of the given size from the given actor and
+// anchor origin is admitted. If the offer is not admitted then a spi.OfferRejectedError is returned with the reason
+// for the rejection.
+func (c *Controller) AdmitOffer(offerID, actor, origin *url.URL, anchorSize int) error {
+	if c.MaxAnchorSize > 0 && anchorSize > c.MaxAnchorSize {
+		return c.reject(spi.NewOfferRejectedError(spi.RejectReasonAnchorTooLarge,
+			"anchor size %d exceeds the maximum size %d", anchorSize, c.MaxAnchorSize), actor, origin)
+	}
+
+	if c.OriginAllowListEnabled {
+		allowed, err := c.isAllowed(actor)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			return c.reject(spi.NewOfferRejectedError(spi.RejectReasonOriginNotAllowed,
+				"actor [%s] is not in the allow list", actor), actor, origin)
+		}
+	}
+
+	if err := c.checkQuota(offerID, actor); err != nil {
+		return c.reject(err, actor, origin)
+	}
+
+	return nil
+}
+
+func (c *Controller) isAllowed(actor *url.URL) (bool, error) {
+	if actor == nil {
+		return false, nil
+	}
+
+	allowList, err := c.acceptList.Get(AnchorOriginType)
+	if err != nil {
+		return false, fmt.Errorf("load anchor origin accept list: %w", err)
+	}
+
+	for _, u := range allowList {
+		if u.String() == actor.String() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c *Controller) checkQuota(offerID, actor *url.URL) *spi.OfferRejectedError {
+	if c.MaxOffersPerMinute <= 0 && c.MaxOffersPerDay <= 0 {
+		return nil
+	}
+
+	var key string
+
+	if actor != nil {
+		key = actor.String()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+
+	q := c.quota(key, now)
+
+	q.perMinute.roll(now, time.Minute)
+
+	if q.perDay.roll(now, 24*time.Hour) {
+		q.admitted = make(map[string]struct{})
+	}
+
+	var id string
+
+	if offerID != nil {
+		id = offerID.String()
+
+		if _, ok := q.admitted[id]; ok {
+			// The offer was already charged against the quota (the offer is being retried).
+			return nil
+		}
+	}
+
+	if c.MaxOffersPerMinute > 0 && q.perMinute.count >= c.MaxOffersPerMinute {
+		return spi.NewOfferRejectedError(spi.RejectReasonQuotaExceeded,
+			"actor [%s] exceeded the quota of %d offers per minute", key, c.MaxOffersPerMinute)
+	}
+
+	if c.MaxOffersPerDay > 0 && q.perDay.count >= c.MaxOffersPerDay {
+		return spi.NewOfferRejectedError(spi.RejectReasonQuotaExceeded,
+			"actor [%s] exceeded the quota of %d offers per day", key, c.MaxOffersPerDay)
+	}
+
+	q.perMinute.count++
+	q.perDay.count++
+
+	if id != "" {
+		q.admitted[id] = struct{}{}
+	}
+
+	return nil
+}
+
+func (c *Controller) quota(actor string, now time.Time) *quota {
+	q, ok := c.actors[actor]
+	if ok {
+		return q
+	}
+
+	if len(c.actors) >= maxActors {
+		c.purgeExpiredQuotas(now)
+	}
+
+	q = &quota{
+		perMinute: window{start: now},
+		perDay:    window{start: now},
+		admitted:  make(map[string]struct{}),
+	}
+
+	c.actors[actor] = q
+
+	return q
+}
+
+// purgeExpiredQuotas removes the quotas whose daily window has expired since they are equivalent to new quotas.
+func (c *Controller) purgeExpiredQuotas(now time.Time) {
+	for actor, q := range c.actors {
+		if now.Sub(q.perDay.start) >= 24*time.Hour {
+			delete(c.actors, actor)
+		}
+	}
+
+	logger.Debug("Purged expired actor quotas", logfields.WithTotal(len(c.actors)))
+}
+
+func (c *Controller) reject(err *spi.OfferRejectedError, actor, origin *url.URL) error {
+	logger.Info("Rejecting offer", logfields.WithActorIRI(actor), logfields.WithAnchorOrigin(origin),
+		logfields.WithReason(err.Reason), log.WithError(err))
+
+	if c.metrics != nil {
+		c.metrics.WitnessIncrementOfferRejectedCount(err.Reason)
+	}
+
+	return err
+}
+
+type quota struct {
+	perMinute window
+	perDay    window
+
+	// admitted contains the IDs of the offers that were charged against the quota in the current daily window.
+	admitted map[string]struct{}
+}
+
+// window is a fixed time window that counts the number of admitted offers.
+type window struct {
+	start time.Time
+	count int
+}
+
+// roll starts a new window if the current window has expired. True is returned if a new window was started.
+func (w *window) roll(now time.Time, period time.Duration) bool {
+	if now.Sub(w.start) < period {
+		return false
+	}
+
+	w.start = now
+	w.count = 0
+
+	return true
+}
@@ -0,0 +1,204 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admission
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/service/spi"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	actor1  = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	actor2  = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+	origin1 = testutil.MustParseURL("https://orb.domain1.com/services/orb")
+	origin2 = testutil.MustParseURL("https://orb.domain2.com/services/orb")
+)
+
+func TestConfig_Enabled(t *testing.T) {
+	require.False(t, (&Config{}).Enabled())
+	require.True(t, (&Config{MaxOffersPerMinute: 1}).Enabled())
+	require.True(t, (&Config{MaxOffersPerDay: 1}).Enabled())
+	require.True(t, (&Config{MaxAnchorSize: 1}).Enabled())
+	require.True(t, (&Config{OriginAllowListEnabled: true}).Enabled())
+}
+
+func TestController_AdmitOffer(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		c := New(Config{}, &mockAcceptList{}, nil)
+
+		for i := 0; i < 100; i++ {
+			require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000000))
+		}
+	})
+
+	t.Run("anchor too large", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		c := New(Config{MaxAnchorSize: 1000}, &mockAcceptList{}, metrics)
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+
+		err := c.AdmitOffer(newOfferID(), actor1, origin1, 1001)
+		requireRejected(t, err, spi.RejectReasonAnchorTooLarge)
+		require.Equal(t, 1, metrics.counts[spi.RejectReasonAnchorTooLarge])
+	})
+
+	t.Run("origin allow list", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		c := New(Config{OriginAllowListEnabled: true}, &mockAcceptList{urls: []*url.URL{origin1}}, metrics)
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+
+		requireRejected(t, c.AdmitOffer(newOfferID(), actor2, origin2, 1000), spi.RejectReasonOriginNotAllowed)
+		requireRejected(t, c.AdmitOffer(newOfferID(), nil, origin1, 1000), spi.RejectReasonOriginNotAllowed)
+
+		// The declared anchor origin isn't trusted.
+		requireRejected(t, c.AdmitOffer(newOfferID(), actor2, origin1, 1000), spi.RejectReasonOriginNotAllowed)
+		require.Equal(t, 3, metrics.counts[spi.RejectReasonOriginNotAllowed])
+	})
+
+	t.Run("accept list error", func(t *testing.T) {
+		c := New(Config{OriginAllowListEnabled: true}, &mockAcceptList{err: errors.New("injected error")}, nil)
+
+		err := c.AdmitOffer(newOfferID(), actor1, origin1, 1000)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected error")
+
+		rejectedErr := &spi.OfferRejectedError{}
+		require.False(t, errors.As(err, &rejectedErr))
+	})
+
+	t.Run("quota per minute", func(t *testing.T) {
+		now := time.Now()
+
+		c := New(Config{MaxOffersPerMinute: 2, MaxOffersPerDay: 3}, &mockAcceptList{}, &mockMetrics{})
+		c.now = func() time.Time { return now }
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+		requireRejected(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000), spi.RejectReasonQuotaExceeded)
+
+		// Quotas are per actor.
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor2, origin2, 1000))
+
+		// The declared anchor origin doesn't affect the quota.
+		requireRejected(t, c.AdmitOffer(newOfferID(), actor1, origin2, 1000), spi.RejectReasonQuotaExceeded)
+
+		now = now.Add(time.Minute)
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+
+		// The daily quota is exceeded.
+		err := c.AdmitOffer(newOfferID(), actor1, origin1, 1000)
+		requireRejected(t, err, spi.RejectReasonQuotaExceeded)
+		require.Contains(t, err.Error(), "per day")
+
+		now = now.Add(24 * time.Hour)
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+	})
+
+	t.Run("quota keyed on actor", func(t *testing.T) {
+		c := New(Config{MaxOffersPerDay: 1}, &mockAcceptList{}, nil)
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor2, origin1, 1000))
+
+		// Another actor that declares the same anchor origin has its own quota.
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+
+		err := c.AdmitOffer(newOfferID(), actor2, origin2, 1000)
+		requireRejected(t, err, spi.RejectReasonQuotaExceeded)
+		require.Contains(t, err.Error(), actor2.String())
+	})
+
+	t.Run("offer charged once", func(t *testing.T) {
+		now := time.Now()
+
+		c := New(Config{MaxOffersPerDay: 2}, &mockAcceptList{}, nil)
+		c.now = func() time.Time { return now }
+
+		offerID := newOfferID()
+
+		require.NoError(t, c.AdmitOffer(offerID, actor1, origin1, 1000))
+
+		// Retries of the same offer aren't charged again.
+		require.NoError(t, c.AdmitOffer(offerID, actor1, origin1, 1000))
+		require.NoError(t, c.AdmitOffer(offerID, actor1, origin1, 1000))
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+		requireRejected(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000), spi.RejectReasonQuotaExceeded)
+
+		// The same offer ID from another actor is charged against that actor's quota.
+		require.NoError(t, c.AdmitOffer(offerID, actor2, origin2, 1000))
+
+		// The admitted offers are cleared when the daily window rolls over.
+		now = now.Add(24 * time.Hour)
+
+		require.NoError(t, c.AdmitOffer(offerID, actor1, origin1, 1000))
+		require.Len(t, c.actors[actor1.String()].admitted, 1)
+	})
+
+	t.Run("purge expired quotas", func(t *testing.T) {
+		now := time.Now()
+
+		c := New(Config{MaxOffersPerDay: 1}, &mockAcceptList{}, nil)
+		c.now = func() time.Time { return now }
+
+		for i := 0; i < maxActors; i++ {
+			c.actors[string(rune(i))] = &quota{perMinute: window{start: now}, perDay: window{start: now}}
+		}
+
+		now = now.Add(24 * time.Hour)
+
+		require.NoError(t, c.AdmitOffer(newOfferID(), actor1, origin1, 1000))
+		require.Len(t, c.actors, 1)
+	})
+}
+
+func newOfferID() *url.URL {
+	return testutil.MustParseURL("https://orb.domain1.com/services/orb/activities/" + uuid.New().String())
+}
+
+func requireRejected(t *testing.T, err error, reason spi.RejectReason) {
+	t.Helper()
+
+	require.Error(t, err)
+
+	rejectedErr := &spi.OfferRejectedError{}
+	require.True(t, errors.As(err, &rejectedErr))
+	require.Equal(t, reason, rejectedErr.Reason)
+}
+
+type mockAcceptList struct {
+	urls []*url.URL
+	err  error
+}
+
+func (m *mockAcceptList) Get(string) ([]*url.URL, error) {
+	return m.urls, m.err
+}
+
+type mockMetrics struct {
+	counts map[string]int
+}
+
+func (m *mockMetrics) WitnessIncrementOfferRejectedCount(reason string) {
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+
+	m.counts[reason]++
+}
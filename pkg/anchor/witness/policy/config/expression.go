@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultWeight = 1
+
+// Expression is a node in the expression tree of a witness policy. A node is either a rule (leaf) or
+// a boolean operator (AND, OR) that's applied to the operands of the node.
+type Expression struct {
+	Operator string
+	Operands []*Expression
+	Rule     *Rule
+}
+
+func (e *Expression) String() string {
+	if e.Rule != nil {
+		return e.Rule.String()
+	}
+
+	operands := make([]string, len(e.Operands))
+
+	for i, o := range e.Operands {
+		operands[i] = o.String()
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(operands, fmt.Sprintf(" %s ", e.Operator)))
+}
+
+// Rule is a threshold (OutOf, MinPercent or MinWeight) that's applied to a group of witnesses.
+type Rule struct {
+	Type  string
+	Value int
+	// Group is the name of the witness group, i.e. one of the roles (batch or system) or a group that was
+	// defined in the policy with Group(name,uri,...). Group is empty if the members are specified inline.
+	Group string
+	// Members contains the witness URIs of an inline group, for example {https://w1.com/orb,https://w2.com/orb}.
+	Members []string
+}
+
+func (r *Rule) String() string {
+	group := r.Group
+	if group == "" {
+		group = fmt.Sprintf("{%s}", strings.Join(r.Members, ","))
+	}
+
+	return fmt.Sprintf("%s(%d,%s)", r.Type, r.Value, group)
+}
+
+// IsRole returns true if the rule applies to one of the witness roles (batch or system) as opposed to
+// a named or inline group.
+func (r *Rule) IsRole() bool {
+	return r.Members == nil && isRole(r.Group)
+}
+
+// GroupMembers returns the witness URIs of the named or inline group that's referenced by the given rule.
+// Nil is returned if the rule applies to a witness role (batch or system) since the members of a role
+// are determined by the type of the witness.
+func (wp *WitnessPolicyConfig) GroupMembers(rule *Rule) []string {
+	if rule.Members != nil {
+		return rule.Members
+	}
+
+	return wp.Groups[rule.Group]
+}
+
+// WitnessWeight returns the weight of the given witness. The weight of a witness is 1 unless specified
+// in the policy with Weight(uri,weight).
+func (wp *WitnessPolicyConfig) WitnessWeight(uri string) int {
+	if w, ok := wp.Weights[uri]; ok {
+		return w
+	}
+
+	return defaultWeight
+}
+
+func isRole(name string) bool {
+	return name == RoleBatch || name == RoleSystem
+}
+
+// combine returns an expression that applies the given operator to the left and right expressions. If either
+// expression is nil (e.g. a group definition) then the other expression is returned.
+func combine(operator string, left, right *Expression) *Expression {
+	if left == nil {
+		return right
+	}
+
+	if right == nil {
+		return left
+	}
+
+	if left.Rule == nil && left.Operator == operator {
+		left.Operands = append(left.Operands, right)
+
+		return left
+	}
+
+	return &Expression{
+		Operator: operator,
+		Operands: []*Expression{left, right},
+	}
+}
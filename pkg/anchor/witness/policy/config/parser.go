@@ -24,29 +24,73 @@ type WitnessPolicyConfig struct {
 	Operator    string
 
 	LogRequired bool
+	// MinLogs is the minimum number of distinct logs across the proofs that are counted. It is set with
+	// LogRequired(n). A value of zero means that the number of distinct logs isn't checked.
+	MinLogs int
+
+	// Expression is set for a policy that uses named witness groups, weights, MinWeight, nested expressions
+	// or both AND and OR operators. If Expression is nil then the batch and system fields above apply.
+	Expression *Expression
+	// Groups contains the named witness groups (group name -> witness URIs) that are defined in the policy.
+	Groups map[string][]string
+	// Weights contains the weights of witnesses (witness URI -> weight) that are defined in the policy.
+	Weights map[string]int
 }
 
 // Gate values.
 const (
 	OutOf       = "OutOf"
 	MinPercent  = "MinPercent"
+	MinWeight   = "MinWeight"
 	LogRequired = "LogRequired"
 
 	AND = "AND"
 	OR  = "OR"
 )
 
+// Definition values.
+const (
+	// Group defines a named group of witnesses, e.g. Group(regulator,https://regulator.com/services/orb).
+	Group = "Group"
+	// Weight defines the weight of a witness, e.g. Weight(https://regulator.com/services/orb,3).
+	Weight = "Weight"
+	// Of is used in the "<n> of <group>" form of OutOf, e.g. 2 of {A,B,C}.
+	Of = "of"
+)
+
 // Role values.
 const (
 	RoleBatch  = "batch"
 	RoleSystem = "system"
 )
 
-const maxPercent = 100
+const (
+	maxPercent = 100
+	numArgs    = 2
+)
 
 type operatorFnc func(a, b bool) bool
 
 // Parse parses witness policy from policy string.
+//
+// A policy is a boolean expression of rules which may be combined with AND and OR (AND takes precedence)
+// and grouped with parentheses. The following rules are supported:
+//   - OutOf(n,group) (or "n of group"): proofs from at least n witnesses in the group are required.
+//   - MinPercent(p,group): proofs from at least p percent of the witnesses in the group are required.
+//   - MinWeight(w,group): the total weight of the witnesses in the group that provided a proof must be at least w.
+//   - group: proofs from all witnesses in the group are required.
+//
+// A group is one of the roles (batch or system), a named group defined with Group(name,uri,...),
+// or an inline set of witness URIs, e.g. {uri1,uri2}. The weight of a witness (default 1) is defined with
+// Weight(uri,weight). LogRequired indicates that only witnesses with a log are counted and LogRequired(n)
+// additionally requires that the counted proofs were added to at least n distinct logs. For example:
+//
+//	Group(regulator,https://regulator.com/services/orb) 2 of {https://w1.com/services/orb,
+//	https://w2.com/services/orb,https://w3.com/services/orb} AND regulator
+//
+// Policies that only apply OutOf and MinPercent to the batch and system roles with a single type of
+// operator retain their original semantics, i.e. the operator is applied to the batch and system
+// conditions and a role that isn't referenced requires proofs from 100% of its witnesses.
 func Parse(policy string) (*WitnessPolicyConfig, error) {
 	// default policy is 100% batch and 100% system witnesses
 	wp := &WitnessPolicyConfig{
@@ -56,135 +100,653 @@ func Parse(policy string) (*WitnessPolicyConfig, error) {
 		Operator:         AND,
 	}
 
-	if policy == "" {
+	if strings.TrimSpace(policy) == "" {
+		return wp, nil
+	}
+
+	p := &parser{
+		tokens:    tokenize(policy),
+		cfg:       wp,
+		operators: make(map[string]bool),
+	}
+
+	expr, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if expr == nil || (!p.extended && len(p.operators) <= 1) {
+		wp.applyLegacyRules(p.rules, p.operators)
+
 		return wp, nil
 	}
 
-	tokens := strings.Split(policy, " ")
+	wp.Expression = expr
+
+	return wp, nil
+}
+
+// applyLegacyRules applies the OutOf and MinPercent rules to the batch and system fields.
+func (wp *WitnessPolicyConfig) applyLegacyRules(rules []*Rule, operators map[string]bool) {
+	for _, r := range rules {
+		switch {
+		case r.Type == OutOf && r.Group == RoleSystem:
+			wp.MinNumberSystem = r.Value
+
+			if wp.MinNumberSystem == 0 {
+				wp.MinPercentSystem = 0
+			}
+		case r.Type == OutOf && r.Group == RoleBatch:
+			wp.MinNumberBatch = r.Value
+
+			if wp.MinNumberBatch == 0 {
+				wp.MinPercentBatch = 0
+			}
+		case r.Type == MinPercent && r.Group == RoleSystem:
+			wp.MinPercentSystem = r.Value
+		case r.Type == MinPercent && r.Group == RoleBatch:
+			wp.MinPercentBatch = r.Value
+		}
+	}
+
+	if operators[OR] {
+		wp.OperatorFnc = or
+		wp.Operator = OR
+	}
+}
+
+func (wp *WitnessPolicyConfig) String() string {
+	var minLogs string
+
+	if wp.MinLogs > 0 {
+		minLogs = fmt.Sprintf(", minLogs:%d", wp.MinLogs)
+	}
+
+	if wp.Expression != nil {
+		return fmt.Sprintf("expression:%s, groups:%v, weights:%v, log:%t%s",
+			wp.Expression, wp.Groups, wp.Weights, wp.LogRequired, minLogs)
+	}
+
+	return fmt.Sprintf("minBatch:%d, minSystem:%d, percentBatch:%d, percentSystem:%d, operator: %s, log:%t%s",
+		wp.MinNumberBatch, wp.MinNumberSystem, wp.MinPercentBatch, wp.MinPercentSystem, wp.Operator, wp.LogRequired,
+		minLogs)
+}
+
+func and(a, b bool) bool {
+	return a && b
+}
+
+func or(a, b bool) bool {
+	return a || b
+}
+
+type groupRef struct {
+	name   string
+	errMsg string
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	cfg    *WitnessPolicyConfig
+
+	rules     []*Rule
+	refs      []groupRef
+	operators map[string]bool
+	// extended is set if the policy uses any of the features that are not supported by the batch/system fields.
+	extended bool
+}
 
-	for _, token := range tokens {
-		err := wp.processToken(token)
+func (p *parser) parse() (*Expression, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, fmt.Errorf("unexpected '%s' in policy", t.value)
+	}
+
+	// Groups may be defined after they're referenced, so resolve the references after parsing.
+	for _, ref := range p.refs {
+		if _, ok := p.cfg.Groups[ref.name]; !ok {
+			return nil, fmt.Errorf("%s", ref.errMsg)
+		}
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseOr() (*Expression, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekWord(OR) {
+		p.next()
+
+		p.operators[OR] = true
+
+		right, e := p.parseAnd()
+		if e != nil {
+			return nil, e
+		}
+
+		expr = combine(OR, expr, right)
+	}
+
+	return expr, nil
+}
+
+// parseAnd parses terms that are separated by AND. Terms that are separated only by whitespace are
+// also combined with AND.
+func (p *parser) parseAnd() (*Expression, error) {
+	expr, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if p.peekWord(AND) {
+			p.next()
+
+			p.operators[AND] = true
+		} else if !p.startsTerm() {
+			return expr, nil
+		}
+
+		right, e := p.parseTerm()
+		if e != nil {
+			return nil, e
+		}
+
+		expr = combine(AND, expr, right)
+	}
+}
+
+func (p *parser) startsTerm() bool {
+	t := p.peek()
+
+	switch t.typ {
+	case tokenLParen, tokenLBrace:
+		return true
+	case tokenWord:
+		return t.value != AND && t.value != OR
+	default:
+		return false
+	}
+}
+
+// parseTerm parses a rule, a definition or an expression in parentheses. A nil expression is returned
+// for definitions (Group, Weight and LogRequired).
+func (p *parser) parseTerm() (*Expression, error) {
+	t := p.next()
+
+	switch t.typ {
+	case tokenLParen:
+		p.extended = true
+
+		expr, err := p.parseOr()
 		if err != nil {
 			return nil, err
 		}
+
+		if closing := p.next(); closing.typ != tokenRParen {
+			return nil, fmt.Errorf("expecting ')' but got '%s' in policy", closing.value)
+		}
+
+		return expr, nil
+	case tokenLBrace:
+		members, err := p.parseSet()
+		if err != nil {
+			return nil, err
+		}
+
+		return p.newRule(&Rule{Type: MinPercent, Value: maxPercent, Members: members}), nil
+	case tokenWord:
+		return p.parseWord(t.value)
+	case tokenEOF:
+		return nil, fmt.Errorf("expecting rule but reached end of policy")
+	default:
+		return nil, fmt.Errorf("unexpected '%s' in policy", t.value)
 	}
+}
 
-	return wp, nil
+func (p *parser) parseWord(word string) (*Expression, error) {
+	if word == LogRequired {
+		return nil, p.parseLogRequired()
+	}
+
+	if p.peek().typ == tokenLParen {
+		p.next()
+
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+
+		return p.parseFunction(word, args)
+	}
+
+	if n, err := strconv.Atoi(word); err == nil && p.peekWord(Of) {
+		p.next()
+
+		return p.parseOf(n)
+	}
+
+	// A group by itself means that proofs from all witnesses in the group are required.
+	p.extended = true
+
+	rule := &Rule{Type: MinPercent, Value: maxPercent, Group: word}
+
+	if !isRole(word) {
+		p.refs = append(p.refs, groupRef{name: word, errMsg: fmt.Sprintf("rule not supported: %s", word)})
+	}
+
+	return p.newRule(rule), nil
 }
 
-func (wp *WitnessPolicyConfig) processToken(token string) error {
-	switch t := token; {
-	case strings.HasPrefix(t, OutOf):
-		err := wp.processOutOf(token)
+// parseLogRequired parses LogRequired or LogRequired(n), where n is the minimum number of distinct logs,
+// e.g. LogRequired(2) means that the counted proofs must have been added to at least two distinct logs.
+// A parenthesis following LogRequired is only treated as an argument list if it encloses a single integer,
+// otherwise it's the start of a parenthesized expression.
+func (p *parser) parseLogRequired() error {
+	p.cfg.LogRequired = true
+
+	if p.peekAt(0).typ != tokenLParen || p.peekAt(2).typ != tokenRParen {
+		return nil
+	}
+
+	t := p.peekAt(1)
+	if t.typ != tokenWord {
+		return nil
+	}
+
+	minLogs, err := strconv.Atoi(t.value)
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	// Consume '(', n and ')'.
+	p.next()
+	p.next()
+	p.next()
+
+	if minLogs < 1 {
+		return fmt.Errorf("minimum number of logs[%s] for %s must be a positive integer", t.value, LogRequired)
+	}
+
+	p.cfg.MinLogs = minLogs
+
+	return nil
+}
+
+// parseOf parses the "<n> of <group>" form of the OutOf rule.
+func (p *parser) parseOf(n int) (*Expression, error) {
+	p.extended = true
+
+	if n < 0 {
+		return nil, fmt.Errorf("number[%d] for '%s' rule must be 0 or positive integer", n, Of)
+	}
+
+	t := p.next()
+
+	switch t.typ {
+	case tokenLBrace:
+		members, err := p.parseSet()
 		if err != nil {
-			return err
+			return nil, err
 		}
-	case strings.HasPrefix(t, MinPercent):
-		err := wp.processMinPercent(token)
+
+		return p.newRule(&Rule{Type: OutOf, Value: n, Members: members}), nil
+	case tokenWord:
+		if !isRole(t.value) {
+			p.refs = append(p.refs, groupRef{
+				name:   t.value,
+				errMsg: fmt.Sprintf("role '%s' not supported for '%s' rule", t.value, Of),
+			})
+		}
+
+		return p.newRule(&Rule{Type: OutOf, Value: n, Group: t.value}), nil
+	default:
+		return nil, fmt.Errorf("expecting group after '%d %s' but got '%s'", n, Of, t.value)
+	}
+}
+
+func (p *parser) parseFunction(name string, args []*arg) (*Expression, error) {
+	switch name {
+	case OutOf, MinPercent, MinWeight:
+		rule, err := p.parseRule(name, args)
 		if err != nil {
-			return err
-		}
-	case t == LogRequired:
-		wp.LogRequired = true
-	case t == AND:
-		wp.OperatorFnc = and
-		wp.Operator = AND
-	case t == OR:
-		wp.OperatorFnc = or
-		wp.Operator = OR
+			return nil, err
+		}
+
+		return p.newRule(rule), nil
+	case Group:
+		return nil, p.parseGroup(args)
+	case Weight:
+		return nil, p.parseWeight(args)
 	default:
-		return fmt.Errorf("rule not supported: %s", token)
+		return nil, fmt.Errorf("rule not supported: %s(%s)", name, joinArgs(args))
 	}
+}
 
-	return nil
+// parseRule parses an OutOf, MinPercent or MinWeight rule, e.g. OutOf(2,system) rule means that proofs from
+// at least 2 system witnesses are required and MinPercent(20,system) means that proofs from at least 20% of
+// system witnesses are required.
+func (p *parser) parseRule(name string, args []*arg) (*Rule, error) {
+	if len(args) != numArgs {
+		return nil, fmt.Errorf("expected 2 but got %d arguments for %s policy", len(args), name)
+	}
+
+	if args[0].members != nil {
+		return nil, fmt.Errorf("first argument for %s policy must be an integer", name)
+	}
+
+	value, err := parseRuleValue(name, args[0].value)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &Rule{Type: name, Value: value}
+
+	if args[1].members != nil {
+		p.extended = true
+
+		rule.Members = args[1].members
+
+		return rule, nil
+	}
+
+	rule.Group = args[1].value
+
+	if !isRole(rule.Group) {
+		p.extended = true
+
+		p.refs = append(p.refs, groupRef{
+			name:   rule.Group,
+			errMsg: fmt.Sprintf("role '%s' not supported for %s policy", rule.Group, name),
+		})
+	}
+
+	if name == MinWeight {
+		p.extended = true
+	}
+
+	return rule, nil
 }
 
-// processOutOf rule (e.g. OutOf(2,system) rule means that proofs from at least 2 system witnesses are required.
-func (wp *WitnessPolicyConfig) processOutOf(token string) error {
-	insideBrackets := token[len(OutOf)+1 : len(token)-1]
+func parseRuleValue(name, value string) (int, error) {
+	if name == MinPercent {
+		minPercent, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("first argument for OutOf policy must be an integer between 0 and 100: %w", err)
+		}
 
-	outOfArgs := strings.Split(insideBrackets, ",")
+		if minPercent < 0 || minPercent > maxPercent {
+			return 0, fmt.Errorf("first argument for OutOf policy must be an integer between 0 and 100")
+		}
 
-	const outOfArgsNo = 2
-	if len(outOfArgs) != outOfArgsNo {
-		return fmt.Errorf("expected 2 but got %d arguments for OutOf policy", len(outOfArgs))
+		return minPercent, nil
 	}
 
-	minNo, err := strconv.Atoi(outOfArgs[0])
+	minNo, err := strconv.Atoi(value)
 	if err != nil {
-		return fmt.Errorf("first argument for OutOf policy must be an integer: %w", err)
+		return 0, fmt.Errorf("first argument for %s policy must be an integer: %w", name, err)
 	}
 
 	if minNo < 0 {
-		return fmt.Errorf("first argument[%d] for OutOf policy rule must be 0 or positive integer", minNo)
+		return 0, fmt.Errorf("first argument[%d] for %s policy rule must be 0 or positive integer", minNo, name)
 	}
 
-	switch outOfArgs[1] {
-	case RoleSystem:
-		wp.MinNumberSystem = minNo
+	return minNo, nil
+}
 
-		if wp.MinNumberSystem == 0 {
-			wp.MinPercentSystem = 0
-		}
+// parseGroup parses a group definition, e.g. Group(eu-witnesses,https://w1.eu/services/orb,https://w2.eu/services/orb).
+func (p *parser) parseGroup(args []*arg) error {
+	p.extended = true
+
+	if len(args) < numArgs || args[0].members != nil {
+		return fmt.Errorf("expecting a name and at least one witness URI for %s", Group)
+	}
+
+	name := args[0].value
+
+	if isRole(name) || isKeyword(name) {
+		return fmt.Errorf("'%s' is a reserved word and cannot be used as a group name", name)
+	}
 
-	case RoleBatch:
-		wp.MinNumberBatch = minNo
+	if _, ok := p.cfg.Groups[name]; ok {
+		return fmt.Errorf("group '%s' is already defined", name)
+	}
+
+	var members []string
 
-		if wp.MinNumberBatch == 0 {
-			wp.MinPercentBatch = 0
+	for _, a := range args[1:] {
+		if a.members != nil {
+			members = append(members, a.members...)
+		} else {
+			members = append(members, a.value)
 		}
+	}
 
-	default:
-		return fmt.Errorf("role '%s' not supported for OutOf policy", outOfArgs[1])
+	if p.cfg.Groups == nil {
+		p.cfg.Groups = make(map[string][]string)
 	}
 
+	p.cfg.Groups[name] = members
+
 	return nil
 }
 
-// processMinPercent will process minimum percent rule.
-// e.g. MinPercent(0.2,system) rule means that proofs from at least 20% of system witnesses are required.
-func (wp *WitnessPolicyConfig) processMinPercent(token string) error {
-	insideBrackets := token[len(MinPercent)+1 : len(token)-1]
+// parseWeight parses the weight of a witness, e.g. Weight(https://w1.eu/services/orb,3).
+func (p *parser) parseWeight(args []*arg) error {
+	p.extended = true
 
-	minPercentArgs := strings.Split(insideBrackets, ",")
+	if len(args) != numArgs || args[0].members != nil || args[1].members != nil {
+		return fmt.Errorf("expecting a witness URI and a weight for %s", Weight)
+	}
 
-	const minPercentArgsNo = 2
-	if len(minPercentArgs) != minPercentArgsNo {
-		return fmt.Errorf("expected 2 but got %d arguments for MinPercent policy", len(minPercentArgs))
+	weight, err := strconv.Atoi(args[1].value)
+	if err != nil || weight <= 0 {
+		return fmt.Errorf("weight[%s] of witness [%s] must be a positive integer", args[1].value, args[0].value)
 	}
 
-	minPercent, err := strconv.Atoi(minPercentArgs[0])
-	if err != nil {
-		return fmt.Errorf("first argument for OutOf policy must be an integer between 0 and 100: %w", err)
+	if p.cfg.Weights == nil {
+		p.cfg.Weights = make(map[string]int)
+	}
+
+	p.cfg.Weights[args[0].value] = weight
+
+	return nil
+}
+
+func (p *parser) newRule(rule *Rule) *Expression {
+	if rule.Members != nil {
+		p.extended = true
 	}
 
-	if minPercent < 0 || minPercent > 100 {
-		return fmt.Errorf("first argument for OutOf policy must be an integer between 0 and 100")
+	p.rules = append(p.rules, rule)
+
+	return &Expression{Rule: rule}
+}
+
+type arg struct {
+	value   string
+	members []string
+}
+
+// parseArgs parses the comma-separated arguments of a function up to the closing parenthesis. An argument
+// is either a word or a set of words in braces.
+func (p *parser) parseArgs() ([]*arg, error) {
+	var args []*arg
+
+	for {
+		t := p.next()
+
+		switch t.typ {
+		case tokenWord:
+			args = append(args, &arg{value: t.value})
+		case tokenLBrace:
+			members, err := p.parseSet()
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, &arg{members: members})
+		case tokenRParen:
+			if len(args) == 0 {
+				return args, nil
+			}
+
+			return nil, fmt.Errorf("unexpected ')' in policy")
+		default:
+			return nil, fmt.Errorf("unexpected '%s' in arguments", t.value)
+		}
+
+		switch t := p.next(); t.typ {
+		case tokenComma:
+		case tokenRParen:
+			return args, nil
+		default:
+			return nil, fmt.Errorf("expecting ',' or ')' but got '%s' in arguments", t.value)
+		}
 	}
+}
+
+// parseSet parses a comma-separated set of witness URIs up to the closing brace.
+func (p *parser) parseSet() ([]string, error) {
+	var members []string
 
-	switch minPercentArgs[1] {
-	case RoleSystem:
-		wp.MinPercentSystem = minPercent
+	for {
+		t := p.next()
+		if t.typ != tokenWord {
+			return nil, fmt.Errorf("expecting witness URI but got '%s' in set", t.value)
+		}
+
+		members = append(members, t.value)
 
-	case RoleBatch:
-		wp.MinPercentBatch = minPercent
+		switch t := p.next(); t.typ {
+		case tokenComma:
+		case tokenRBrace:
+			return members, nil
+		default:
+			return nil, fmt.Errorf("expecting ',' or '}' but got '%s' in set", t.value)
+		}
+	}
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+// peekAt returns the token at the given offset from the current position without consuming it.
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return token{typ: tokenEOF}
+	}
+
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) peekWord(value string) bool {
+	t := p.peek()
+
+	return t.typ == tokenWord && t.value == value
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+
+	return t
+}
 
+func isKeyword(word string) bool {
+	switch word {
+	case OutOf, MinPercent, MinWeight, LogRequired, AND, OR, Group, Weight, Of:
+		return true
 	default:
-		return fmt.Errorf("role '%s' not supported for MinPercent policy", minPercentArgs[1])
+		return false
 	}
+}
 
-	return nil
+func joinArgs(args []*arg) string {
+	values := make([]string, len(args))
+
+	for i, a := range args {
+		if a.members != nil {
+			values[i] = fmt.Sprintf("{%s}", strings.Join(a.members, ","))
+		} else {
+			values[i] = a.value
+		}
+	}
+
+	return strings.Join(values, ",")
 }
 
-func (wp *WitnessPolicyConfig) String() string {
-	return fmt.Sprintf("minBatch:%d, minSystem:%d, percentBatch:%d, percentSystem:%d, operator: %s, log:%t",
-		wp.MinNumberBatch, wp.MinNumberSystem, wp.MinPercentBatch, wp.MinPercentSystem, wp.Operator, wp.LogRequired)
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenLParen
+	tokenRParen
+	tokenLBrace
+	tokenRBrace
+	tokenComma
+)
+
+type token struct {
+	typ   tokenType
+	value string
 }
 
-func and(a, b bool) bool {
-	return a && b
+var delimiters = map[rune]tokenType{
+	'(': tokenLParen,
+	')': tokenRParen,
+	'{': tokenLBrace,
+	'}': tokenRBrace,
+	',': tokenComma,
 }
 
-func or(a, b bool) bool {
-	return a || b
+// tokenize splits the policy into words and delimiters. Whitespace (including newlines) separates words
+// and is otherwise ignored.
+func tokenize(policy string) []token {
+	var tokens []token
+
+	var word strings.Builder
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, token{typ: tokenWord, value: word.String()})
+			word.Reset()
+		}
+	}
+
+	for _, r := range policy {
+		if typ, ok := delimiters[r]; ok {
+			flush()
+
+			tokens = append(tokens, token{typ: typ, value: string(r)})
+
+			continue
+		}
+
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			flush()
+
+			continue
+		}
+
+		word.WriteRune(r)
+	}
+
+	flush()
+
+	return tokens
 }
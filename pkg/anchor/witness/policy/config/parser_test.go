@@ -167,6 +167,196 @@ func TestParse_LogRequired(t *testing.T) {
 		require.Equal(t, 100, wp.MinPercentBatch)
 		require.Equal(t, 100, wp.MinPercentSystem)
 		require.Equal(t, true, wp.LogRequired)
+		require.Equal(t, 0, wp.MinLogs)
 		require.Equal(t, and(true, false), wp.OperatorFnc(true, false))
+		require.NotContains(t, wp.String(), "minLogs")
+	})
+
+	t.Run("success - minimum number of logs", func(t *testing.T) {
+		wp, err := Parse("OutOf(1,system) LogRequired(2)")
+		require.NoError(t, err)
+		require.NotNil(t, wp)
+
+		require.Nil(t, wp.Expression)
+		require.Equal(t, 1, wp.MinNumberSystem)
+		require.True(t, wp.LogRequired)
+		require.Equal(t, 2, wp.MinLogs)
+		require.Contains(t, wp.String(), "minLogs:2")
+
+		wp, err = Parse("LogRequired(3) 2 of {https://w1.com,https://w2.com}")
+		require.NoError(t, err)
+		require.NotNil(t, wp.Expression)
+		require.Equal(t, 3, wp.MinLogs)
+		require.Contains(t, wp.String(), "minLogs:3")
+	})
+
+	t.Run("success - followed by a parenthesized expression", func(t *testing.T) {
+		wp, err := Parse("LogRequired (OutOf(1,batch) OR OutOf(1,system))")
+		require.NoError(t, err)
+		require.NotNil(t, wp.Expression)
+		require.True(t, wp.LogRequired)
+		require.Equal(t, 0, wp.MinLogs)
+	})
+
+	t.Run("error - invalid minimum number of logs", func(t *testing.T) {
+		_, err := Parse("LogRequired(0)")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "minimum number of logs[0] for LogRequired must be a positive integer")
+
+		_, err = Parse("LogRequired(-1)")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be a positive integer")
+
+		// Anything other than a single integer is parsed as a parenthesized expression.
+		_, err = Parse("LogRequired(x)")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rule not supported: x")
+
+		_, err = Parse("LogRequired(1,2)")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting ')' but got ','")
+
+		_, err = Parse("LogRequired(1")
+		require.Error(t, err)
+	})
+}
+
+func TestParse_Expression(t *testing.T) {
+	const (
+		w1 = "https://w1.eu/services/orb"
+		w2 = "https://w2.eu/services/orb"
+		w3 = "https://w3.eu/services/orb"
+		r1 = "https://regulator.com/services/orb"
+	)
+
+	t.Run("success - named groups, weights and nested expressions", func(t *testing.T) {
+		wp, err := Parse(`Group(eu-witnesses, ` + w1 + `, ` + w2 + `, ` + w3 + `)
+			Group(regulator,` + r1 + `)
+			Weight(` + r1 + `,3)
+			LogRequired
+			(OutOf(2,eu-witnesses) AND regulator) OR MinWeight(4,{` + w1 + `,` + r1 + `})`)
+		require.NoError(t, err)
+		require.NotNil(t, wp.Expression)
+
+		require.Equal(t, []string{w1, w2, w3}, wp.Groups["eu-witnesses"])
+		require.Equal(t, []string{r1}, wp.Groups["regulator"])
+		require.Equal(t, 3, wp.WitnessWeight(r1))
+		require.Equal(t, 1, wp.WitnessWeight(w1))
+		require.True(t, wp.LogRequired)
+
+		require.Equal(t, "((OutOf(2,eu-witnesses) AND MinPercent(100,regulator)) OR MinWeight(4,{"+w1+","+r1+"}))",
+			wp.Expression.String())
+		require.Contains(t, wp.String(), "expression:")
+
+		require.Equal(t, OR, wp.Expression.Operator)
+		require.Len(t, wp.Expression.Operands, 2)
+
+		rule := wp.Expression.Operands[0].Operands[0].Rule
+		require.Equal(t, OutOf, rule.Type)
+		require.Equal(t, 2, rule.Value)
+		require.False(t, rule.IsRole())
+		require.Equal(t, []string{w1, w2, w3}, wp.GroupMembers(rule))
+
+		rule = wp.Expression.Operands[1].Rule
+		require.Equal(t, MinWeight, rule.Type)
+		require.Equal(t, []string{w1, r1}, wp.GroupMembers(rule))
+	})
+
+	t.Run("success - n of group", func(t *testing.T) {
+		wp, err := Parse("2 of {" + w1 + "," + w2 + "," + w3 + "} AND OutOf(1,system)")
+		require.NoError(t, err)
+		require.NotNil(t, wp.Expression)
+		require.Equal(t, "(OutOf(2,{"+w1+","+w2+","+w3+"}) AND OutOf(1,system))", wp.Expression.String())
+
+		rule := wp.Expression.Operands[1].Rule
+		require.True(t, rule.IsRole())
+		require.Nil(t, wp.GroupMembers(rule))
+	})
+
+	t.Run("success - AND takes precedence over OR", func(t *testing.T) {
+		wp, err := Parse("OutOf(1,system) OR OutOf(1,batch) AND MinPercent(50,system)")
+		require.NoError(t, err)
+		require.NotNil(t, wp.Expression)
+		require.Equal(t, "(OutOf(1,system) OR (OutOf(1,batch) AND MinPercent(50,system)))", wp.Expression.String())
+	})
+
+	t.Run("success - definitions only (default policy)", func(t *testing.T) {
+		wp, err := Parse("Group(eu," + w1 + ")")
+		require.NoError(t, err)
+		require.Nil(t, wp.Expression)
+		require.Equal(t, 100, wp.MinPercentBatch)
+		require.Equal(t, 100, wp.MinPercentSystem)
+	})
+
+	t.Run("success - legacy policy", func(t *testing.T) {
+		wp, err := Parse("OutOf(2, system)  AND\nMinPercent(50,batch)")
+		require.NoError(t, err)
+		require.Nil(t, wp.Expression)
+		require.Equal(t, 2, wp.MinNumberSystem)
+		require.Equal(t, 50, wp.MinPercentBatch)
+	})
+
+	t.Run("error - undefined group", func(t *testing.T) {
+		_, err := Parse("OutOf(2,eu)")
+		require.EqualError(t, err, "role 'eu' not supported for OutOf policy")
+
+		_, err = Parse("eu")
+		require.EqualError(t, err, "rule not supported: eu")
+
+		_, err = Parse("2 of eu")
+		require.EqualError(t, err, "role 'eu' not supported for 'of' rule")
+	})
+
+	t.Run("error - invalid group definition", func(t *testing.T) {
+		_, err := Parse("Group(eu)")
+		require.Contains(t, err.Error(), "expecting a name and at least one witness URI")
+
+		_, err = Parse("Group(system," + w1 + ")")
+		require.Contains(t, err.Error(), "'system' is a reserved word")
+
+		_, err = Parse("Group(eu," + w1 + ") Group(eu," + w2 + ")")
+		require.Contains(t, err.Error(), "group 'eu' is already defined")
+	})
+
+	t.Run("error - invalid weight", func(t *testing.T) {
+		_, err := Parse("Weight(" + w1 + ")")
+		require.Contains(t, err.Error(), "expecting a witness URI and a weight")
+
+		_, err = Parse("Weight(" + w1 + ",0)")
+		require.Contains(t, err.Error(), "must be a positive integer")
+	})
+
+	t.Run("error - invalid MinWeight", func(t *testing.T) {
+		_, err := Parse("MinWeight(-1,system)")
+		require.Contains(t, err.Error(), "first argument[-1] for MinWeight policy rule must be 0 or positive integer")
+
+		_, err = Parse("MinWeight({a},system)")
+		require.Contains(t, err.Error(), "first argument for MinWeight policy must be an integer")
+	})
+
+	t.Run("error - syntax", func(t *testing.T) {
+		_, err := Parse("(OutOf(1,system)")
+		require.Contains(t, err.Error(), "expecting ')'")
+
+		_, err = Parse("OutOf(1,system))")
+		require.Contains(t, err.Error(), "unexpected ')' in policy")
+
+		_, err = Parse("OutOf(1,system) AND")
+		require.Contains(t, err.Error(), "reached end of policy")
+
+		_, err = Parse("OutOf(1,system OR")
+		require.Contains(t, err.Error(), "expecting ',' or ')'")
+
+		_, err = Parse("OutOf(1,{a,b)")
+		require.Contains(t, err.Error(), "expecting ',' or '}'")
+
+		_, err = Parse("{a,}")
+		require.Contains(t, err.Error(), "expecting witness URI")
+
+		_, err = Parse("2 of ,")
+		require.Contains(t, err.Error(), "expecting group after '2 of'")
+
+		_, err = Parse("-1 of system")
+		require.Contains(t, err.Error(), "must be 0 or positive integer")
 	})
 }
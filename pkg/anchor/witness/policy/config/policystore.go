@@ -8,14 +8,23 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
 
 	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
 )
 
-const policyKey = "witness-policy"
+const (
+	policyKey = "witness-policy"
+
+	scopedPolicyTag = "witnessPolicyScope"
+)
+
+var logger = log.New("witness-policy-store")
 
 // Store implements the witness policy config store.
 type Store struct {
@@ -69,6 +78,117 @@ func (s *Store) GetPolicy() (string, error) {
 	return policyCfg.Policy, nil
 }
 
+// PutScopedPolicy stores the witness policy for the given scope.
+func (s *Store) PutScopedPolicy(scope *Scope, policyStr string) error {
+	if err := scope.Validate(); err != nil {
+		return err
+	}
+
+	valueBytes, err := s.marshal(&ScopedPolicy{Scope: *scope, Policy: policyStr})
+	if err != nil {
+		return fmt.Errorf("marshal scoped witness policy: %w", err)
+	}
+
+	// The tag has no value since tag values may not contain ':' (which namespaces usually do). The namespace
+	// and origin are part of the key.
+	err = s.store.Put(scopedPolicyKey(scope), valueBytes, storage.Tag{Name: scopedPolicyTag})
+	if err != nil {
+		return orberrors.NewTransientf("store witness policy for %s: %w", scope, err)
+	}
+
+	return nil
+}
+
+// GetScopedPolicy returns the witness policy for the given scope. If no policy exists for the exact scope
+// then storage.ErrDataNotFound is returned.
+func (s *Store) GetScopedPolicy(scope *Scope) (string, error) {
+	if err := scope.Validate(); err != nil {
+		return "", err
+	}
+
+	policyBytes, err := s.store.Get(scopedPolicyKey(scope))
+	if err != nil {
+		return "", err
+	}
+
+	scopedPolicy := &ScopedPolicy{}
+
+	err = s.unmarshal(policyBytes, scopedPolicy)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal witness policy for %s: %w", scope, err)
+	}
+
+	return scopedPolicy.Policy, nil
+}
+
+// DeleteScopedPolicy deletes the witness policy for the given scope.
+func (s *Store) DeleteScopedPolicy(scope *Scope) error {
+	if err := scope.Validate(); err != nil {
+		return err
+	}
+
+	_, err := s.store.Get(scopedPolicyKey(scope))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return fmt.Errorf("witness policy for %s: %w", scope, orberrors.ErrContentNotFound)
+		}
+
+		return orberrors.NewTransientf("get witness policy for %s: %w", scope, err)
+	}
+
+	err = s.store.Delete(scopedPolicyKey(scope))
+	if err != nil {
+		return orberrors.NewTransientf("delete witness policy for %s: %w", scope, err)
+	}
+
+	return nil
+}
+
+// GetScopedPolicies returns all scoped witness policies.
+func (s *Store) GetScopedPolicies() ([]*ScopedPolicy, error) {
+	it, err := s.store.Query(scopedPolicyTag)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query scoped witness policies: %w", err)
+	}
+
+	defer store.CloseIterator(it)
+
+	var policies []*ScopedPolicy
+
+	for {
+		ok, e := it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("query next scoped witness policy: %w", e)
+		}
+
+		if !ok {
+			break
+		}
+
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get scoped witness policy value: %w", e)
+		}
+
+		scopedPolicy := &ScopedPolicy{}
+
+		e = s.unmarshal(value, scopedPolicy)
+		if e != nil {
+			logger.Warn("Error unmarshalling scoped witness policy. The item will be ignored.", log.WithError(e))
+
+			continue
+		}
+
+		policies = append(policies, scopedPolicy)
+	}
+
+	return policies, nil
+}
+
+func scopedPolicyKey(scope *Scope) string {
+	return fmt.Sprintf("%s|%s|%s", policyKey, scope.Namespace, scope.Origin)
+}
+
 //nolint:tagliatelle
 type policyCfg struct {
 	Policy string `json:"Policy"`
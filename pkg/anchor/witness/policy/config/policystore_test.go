@@ -11,8 +11,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
 	"github.com/stretchr/testify/require"
 
+	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/store/mocks"
 )
 
@@ -102,3 +105,115 @@ func TestStore_GetPolicy(t *testing.T) {
 		require.Empty(t, policy)
 	})
 }
+
+func TestStore_ScopedPolicies(t *testing.T) {
+	nsScope := &Scope{Namespace: "did:orb"}
+	originScope := &Scope{Namespace: "did:orb", Origin: "https://orb.domain1.com"}
+
+	t.Run("success", func(t *testing.T) {
+		ms, err := mem.NewProvider().OpenStore("config")
+		require.NoError(t, err)
+
+		s := NewPolicyStore(ms)
+
+		require.NoError(t, s.PutPolicy(testPolicy))
+		require.NoError(t, s.PutScopedPolicy(nsScope, "OutOf(1,system)"))
+		require.NoError(t, s.PutScopedPolicy(originScope, "OutOf(2,system)"))
+
+		policy, err := s.GetScopedPolicy(originScope)
+		require.NoError(t, err)
+		require.Equal(t, "OutOf(2,system)", policy)
+
+		policies, err := s.GetScopedPolicies()
+		require.NoError(t, err)
+		require.Len(t, policies, 2)
+
+		require.NoError(t, s.DeleteScopedPolicy(originScope))
+
+		_, err = s.GetScopedPolicy(originScope)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		err = s.DeleteScopedPolicy(originScope)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		policies, err = s.GetScopedPolicies()
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		require.Equal(t, *nsScope, policies[0].Scope)
+
+		// The global policy is not affected.
+		policy, err = s.GetPolicy()
+		require.NoError(t, err)
+		require.Equal(t, testPolicy, policy)
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		s := NewPolicyStore(&mocks.Store{})
+
+		require.Error(t, s.PutScopedPolicy(&Scope{}, testPolicy))
+
+		_, err := s.GetScopedPolicy(&Scope{})
+		require.Error(t, err)
+
+		require.Error(t, s.DeleteScopedPolicy(&Scope{}))
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		ms := &mocks.Store{}
+		ms.PutReturns(errors.New("injected put error"))
+		ms.GetReturns(nil, errors.New("injected get error"))
+		ms.QueryReturns(nil, errors.New("injected query error"))
+
+		s := NewPolicyStore(ms)
+
+		err := s.PutScopedPolicy(nsScope, testPolicy)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+
+		_, err = s.GetScopedPolicy(nsScope)
+		require.Contains(t, err.Error(), "injected get error")
+
+		err = s.DeleteScopedPolicy(nsScope)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+
+		_, err = s.GetScopedPolicies()
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		ms := &mocks.Store{}
+		ms.DeleteReturns(errors.New("injected delete error"))
+
+		err := NewPolicyStore(ms).DeleteScopedPolicy(nsScope)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected delete error")
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		s := NewPolicyStore(&mocks.Store{})
+		s.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		require.Error(t, s.PutScopedPolicy(nsScope, testPolicy))
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		ms, err := mem.NewProvider().OpenStore("config")
+		require.NoError(t, err)
+
+		s := NewPolicyStore(ms)
+		require.NoError(t, s.PutScopedPolicy(nsScope, testPolicy))
+
+		s.unmarshal = func([]byte, interface{}) error { return errors.New("injected unmarshal error") }
+
+		_, err = s.GetScopedPolicy(nsScope)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected unmarshal error")
+
+		// Invalid entries are ignored.
+		policies, err := s.GetScopedPolicies()
+		require.NoError(t, err)
+		require.Empty(t, policies)
+	})
+}
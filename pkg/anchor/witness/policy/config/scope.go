@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Scope restricts a witness policy to the anchors of a DID namespace and/or to the operations
+// of an anchor origin. At least one of the fields must be set.
+type Scope struct {
+	Namespace string `json:"namespace,omitempty"`
+	Origin    string `json:"origin,omitempty"`
+}
+
+// Validate returns an error if the scope is empty.
+func (s *Scope) Validate() error {
+	if s.Namespace == "" && s.Origin == "" {
+		return errors.New("either namespace or origin must be specified in the scope")
+	}
+
+	return nil
+}
+
+// String returns a readable string for the scope.
+func (s *Scope) String() string {
+	return fmt.Sprintf("namespace[%s] origin[%s]", s.Namespace, s.Origin)
+}
+
+// ScopedPolicy is a witness policy that applies to the anchors within a scope.
+type ScopedPolicy struct {
+	Scope
+	Policy string `json:"policy"`
+}
+
+// AnchorScope contains the DID namespace of an anchor batch and the anchor origins of the
+// operations in the batch. It is used to resolve the witness policies that apply to the batch.
+type AnchorScope struct {
+	Namespace string   `json:"namespace,omitempty"`
+	Origins   []string `json:"origins,omitempty"`
+}
+
+// Scopes returns the scopes to resolve for the anchor batch, i.e. one scope per anchor origin or,
+// if the batch has no anchor origins, a scope for the namespace only.
+func (s *AnchorScope) Scopes() []*Scope {
+	if len(s.Origins) == 0 {
+		return []*Scope{{Namespace: s.Namespace}}
+	}
+
+	scopes := make([]*Scope, len(s.Origins))
+
+	for i, origin := range s.Origins {
+		scopes[i] = &Scope{Namespace: s.Namespace, Origin: origin}
+	}
+
+	return scopes
+}
+
+// Candidates returns the scopes whose policy applies to this scope, from the most specific to the least
+// specific. A policy for both the namespace and the origin takes precedence over a policy for the origin only,
+// which takes precedence over a policy for the namespace only.
+func (s *Scope) Candidates() []*Scope {
+	var candidates []*Scope
+
+	if s.Origin != "" {
+		if s.Namespace != "" {
+			candidates = append(candidates, &Scope{Namespace: s.Namespace, Origin: s.Origin})
+		}
+
+		candidates = append(candidates, &Scope{Origin: s.Origin})
+	}
+
+	if s.Namespace != "" {
+		candidates = append(candidates, &Scope{Namespace: s.Namespace})
+	}
+
+	return candidates
+}
@@ -0,0 +1,52 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	t.Run("validate", func(t *testing.T) {
+		require.NoError(t, (&Scope{Namespace: "did:orb"}).Validate())
+		require.NoError(t, (&Scope{Origin: "https://orb.domain1.com"}).Validate())
+		require.EqualError(t, (&Scope{}).Validate(), "either namespace or origin must be specified in the scope")
+	})
+
+	t.Run("candidates", func(t *testing.T) {
+		require.Equal(t, []*Scope{
+			{Namespace: "did:orb", Origin: "https://orb.domain1.com"},
+			{Origin: "https://orb.domain1.com"},
+			{Namespace: "did:orb"},
+		}, (&Scope{Namespace: "did:orb", Origin: "https://orb.domain1.com"}).Candidates())
+
+		require.Equal(t, []*Scope{{Origin: "https://orb.domain1.com"}},
+			(&Scope{Origin: "https://orb.domain1.com"}).Candidates())
+
+		require.Equal(t, []*Scope{{Namespace: "did:orb"}}, (&Scope{Namespace: "did:orb"}).Candidates())
+		require.Empty(t, (&Scope{}).Candidates())
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, "namespace[did:orb] origin[https://orb.domain1.com]",
+			(&Scope{Namespace: "did:orb", Origin: "https://orb.domain1.com"}).String())
+	})
+}
+
+func TestAnchorScope_Scopes(t *testing.T) {
+	require.Equal(t, []*Scope{{Namespace: "did:orb"}}, (&AnchorScope{Namespace: "did:orb"}).Scopes())
+
+	require.Equal(t, []*Scope{
+		{Namespace: "did:orb", Origin: "https://orb.domain1.com"},
+		{Namespace: "did:orb", Origin: "https://orb.domain2.com"},
+	}, (&AnchorScope{
+		Namespace: "did:orb",
+		Origins:   []string{"https://orb.domain1.com", "https://orb.domain2.com"},
+	}).Scopes())
+}
@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+)
+
+// Evaluation contains the result of evaluating the witness policy against the proofs of an anchor.
+type Evaluation struct {
+	Policy    string           `json:"policy"`
+	Satisfied bool             `json:"satisfied"`
+	Condition *ConditionResult `json:"condition"`
+}
+
+// ConditionResult contains the result of evaluating a single condition of the witness policy. A condition is
+// either a rule (e.g. OutOf(2,system)) or an operator (AND, OR) that's applied to the operands.
+type ConditionResult struct {
+	Condition string `json:"condition"`
+	Satisfied bool   `json:"satisfied"`
+
+	// Members is the number of witnesses that the rule applies to.
+	Members int `json:"members,omitempty"`
+	// Collected contains the URIs of the witnesses whose proofs count towards the rule (or the distinct logs
+	// for LogRequired(n)).
+	Collected []string `json:"collected,omitempty"`
+	// Weight is the total weight of the collected witnesses (MinWeight rules only).
+	Weight int `json:"weight,omitempty"`
+	// Minimum is the minimum number of distinct logs (LogRequired(n) only).
+	Minimum int `json:"minimum,omitempty"`
+
+	Operands []*ConditionResult `json:"operands,omitempty"`
+}
+
+// Explain evaluates the witness policy for the provided witnesses and returns the result of each condition
+// of the policy. Unlike Evaluate, all conditions are evaluated (i.e. there's no short-circuit).
+func (wp *WitnessPolicy) Explain(witnesses []*proof.WitnessProof) (*Evaluation, error) {
+	return wp.ExplainForScope(nil, witnesses)
+}
+
+// ExplainForScope explains the witness policies that apply to the given anchor scope. If more than one policy
+// applies then the condition of the evaluation is the AND of the conditions of the policies.
+func (wp *WitnessPolicy) ExplainForScope(scope *config.AnchorScope, witnesses []*proof.WitnessProof) (*Evaluation, error) {
+	cfgs, err := wp.getWitnessPolicyConfigs(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfgs) == 1 {
+		result := explainPolicy(cfgs[0], witnesses)
+
+		return &Evaluation{
+			Policy:    cfgs[0].String(),
+			Satisfied: result.Satisfied,
+			Condition: result,
+		}, nil
+	}
+
+	result := &ConditionResult{
+		Condition: config.AND,
+		Satisfied: true,
+	}
+
+	policies := make([]string, len(cfgs))
+
+	for i, cfg := range cfgs {
+		r := explainPolicy(cfg, witnesses)
+
+		if !r.Satisfied {
+			result.Satisfied = false
+		}
+
+		result.Operands = append(result.Operands, r)
+		policies[i] = fmt.Sprintf("(%s)", cfg)
+	}
+
+	return &Evaluation{
+		Policy:    strings.Join(policies, " "+config.AND+" "),
+		Satisfied: result.Satisfied,
+		Condition: result,
+	}, nil
+}
+
+func explainPolicy(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) *ConditionResult {
+	var result *ConditionResult
+
+	if cfg.Expression != nil {
+		result = explainExpression(cfg, cfg.Expression, witnesses)
+	} else {
+		result = explainLegacyPolicy(cfg, witnesses)
+	}
+
+	if cfg.MinLogs == 0 {
+		return result
+	}
+
+	logs := distinctLogs(cfg, witnesses)
+
+	logsResult := &ConditionResult{
+		Condition: fmt.Sprintf("%s(%d)", config.LogRequired, cfg.MinLogs),
+		Satisfied: len(logs) >= cfg.MinLogs,
+		Collected: logs,
+		Minimum:   cfg.MinLogs,
+	}
+
+	return &ConditionResult{
+		Condition: config.AND,
+		Satisfied: result.Satisfied && logsResult.Satisfied,
+		Operands:  []*ConditionResult{result, logsResult},
+	}
+}
+
+func explainExpression(cfg *config.WitnessPolicyConfig, expr *config.Expression,
+	witnesses []*proof.WitnessProof,
+) *ConditionResult {
+	if expr.Rule != nil {
+		return explainRule(cfg, expr.Rule, witnesses)
+	}
+
+	result := &ConditionResult{
+		Condition: expr.Operator,
+		Satisfied: expr.Operator == config.AND,
+	}
+
+	for _, operand := range expr.Operands {
+		r := explainExpression(cfg, operand, witnesses)
+
+		if expr.Operator == config.OR && r.Satisfied {
+			result.Satisfied = true
+		}
+
+		if expr.Operator == config.AND && !r.Satisfied {
+			result.Satisfied = false
+		}
+
+		result.Operands = append(result.Operands, r)
+	}
+
+	return result
+}
+
+func explainRule(cfg *config.WitnessPolicyConfig, rule *config.Rule, witnesses []*proof.WitnessProof) *ConditionResult {
+	all := make([]*proof.Witness, len(witnesses))
+
+	for i, w := range witnesses {
+		all[i] = w.Witness
+	}
+
+	members := groupMembers(cfg, rule, all)
+
+	collected := make(map[string]bool)
+
+	for _, w := range witnesses {
+		if members[w.URI.String()] && w.Proof != nil && checkLog(cfg.LogRequired, w.HasLog) {
+			collected[w.URI.String()] = true
+		}
+	}
+
+	result := &ConditionResult{
+		Condition: rule.String(),
+		Members:   len(members),
+		Collected: sortedKeys(collected),
+	}
+
+	switch rule.Type {
+	case config.OutOf:
+		result.Satisfied = len(collected) >= rule.Value
+	case config.MinWeight:
+		result.Weight = totalWeight(cfg, collected)
+		result.Satisfied = result.Weight >= rule.Value
+	default:
+		result.Satisfied = evaluate(len(collected), len(members), 0, rule.Value)
+	}
+
+	return result
+}
+
+// explainLegacyPolicy explains a policy that only applies OutOf and MinPercent to the batch and system roles.
+func explainLegacyPolicy(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) *ConditionResult {
+	batch := explainLegacyCondition(cfg, proof.WitnessTypeBatch, cfg.MinNumberBatch, cfg.MinPercentBatch, witnesses)
+	system := explainLegacyCondition(cfg, proof.WitnessTypeSystem, cfg.MinNumberSystem, cfg.MinPercentSystem, witnesses)
+
+	return &ConditionResult{
+		Condition: cfg.Operator,
+		Satisfied: cfg.OperatorFnc(batch.Satisfied, system.Satisfied),
+		Operands:  []*ConditionResult{batch, system},
+	}
+}
+
+func explainLegacyCondition(cfg *config.WitnessPolicyConfig, witnessType proof.WitnessType, minNumber, minPercent int,
+	witnesses []*proof.WitnessProof,
+) *ConditionResult {
+	collected := make(map[string]bool)
+
+	total := 0
+	totalCollected := 0
+
+	for _, w := range witnesses {
+		if w.Type != witnessType {
+			continue
+		}
+
+		total++
+
+		if checkLog(cfg.LogRequired, w.HasLog) && w.Proof != nil {
+			totalCollected++
+
+			collected[w.URI.String()] = true
+		}
+	}
+
+	condition := fmt.Sprintf("%s(%d,%s)", config.MinPercent, minPercent, witnessType)
+
+	if minNumber != 0 {
+		condition = fmt.Sprintf("(%s(%d,%s) OR %s)", config.OutOf, minNumber, witnessType, condition)
+	}
+
+	return &ConditionResult{
+		Condition: condition,
+		Satisfied: evaluate(totalCollected, total, minNumber, minPercent),
+		Members:   total,
+		Collected: sortedKeys(collected),
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,144 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+func TestExplain(t *testing.T) {
+	witness1URL := testutil.MustParseURL("https://w1.com/services/orb")
+	witness2URL := testutil.MustParseURL("https://w2.com/services/orb")
+	witness3URL := testutil.MustParseURL("https://w3.com/services/orb")
+
+	witnessProofs := []*proof.WitnessProof{
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeBatch, URI: vocab.NewURLProperty(witness1URL)},
+			Proof:   []byte("proof"),
+		},
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL)},
+			Proof:   []byte("proof"),
+		},
+		{
+			Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness3URL)},
+		},
+	}
+
+	t.Run("expression", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns(fmt.Sprintf("OutOf(1,batch) AND (OutOf(2,{%s,%s}) OR MinWeight(1,system))",
+			witness2URL, witness3URL), nil)
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		evaluation, err := wp.Explain(witnessProofs)
+		require.NoError(t, err)
+		require.True(t, evaluation.Satisfied)
+		require.NotEmpty(t, evaluation.Policy)
+
+		condition := evaluation.Condition
+		require.Equal(t, "AND", condition.Condition)
+		require.True(t, condition.Satisfied)
+		require.Len(t, condition.Operands, 2)
+
+		require.Equal(t, "OutOf(1,batch)", condition.Operands[0].Condition)
+		require.True(t, condition.Operands[0].Satisfied)
+		require.Equal(t, []string{witness1URL.String()}, condition.Operands[0].Collected)
+
+		or := condition.Operands[1]
+		require.Equal(t, "OR", or.Condition)
+		require.True(t, or.Satisfied)
+		require.Len(t, or.Operands, 2)
+
+		// All sub-conditions are evaluated even though the first operand of the AND was enough to decide the result.
+		require.False(t, or.Operands[0].Satisfied)
+		require.Equal(t, 2, or.Operands[0].Members)
+		require.Equal(t, []string{witness2URL.String()}, or.Operands[0].Collected)
+
+		require.True(t, or.Operands[1].Satisfied)
+		require.Equal(t, 1, or.Operands[1].Weight)
+	})
+
+	t.Run("minimum number of logs", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns("OutOf(1,system) LogRequired(2)", nil)
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		evaluation, err := wp.Explain([]*proof.WitnessProof{
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL), HasLog: true},
+				Proof:   []byte(`{"proof":{"domain":"https://vct1.com/log"}}`),
+			},
+		})
+		require.NoError(t, err)
+		require.False(t, evaluation.Satisfied)
+		require.Contains(t, evaluation.Policy, "minLogs:2")
+
+		condition := evaluation.Condition
+		require.Equal(t, "AND", condition.Condition)
+		require.Len(t, condition.Operands, 2)
+		require.True(t, condition.Operands[0].Satisfied)
+
+		require.Equal(t, "LogRequired(2)", condition.Operands[1].Condition)
+		require.False(t, condition.Operands[1].Satisfied)
+		require.Equal(t, []string{"https://vct1.com/log"}, condition.Operands[1].Collected)
+	})
+
+	t.Run("legacy policy", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns("MinPercent(100,batch) AND OutOf(2,system)", nil)
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		evaluation, err := wp.Explain(witnessProofs)
+		require.NoError(t, err)
+		require.False(t, evaluation.Satisfied)
+
+		condition := evaluation.Condition
+		require.Equal(t, "AND", condition.Condition)
+		require.Len(t, condition.Operands, 2)
+
+		require.Equal(t, "MinPercent(100,batch)", condition.Operands[0].Condition)
+		require.True(t, condition.Operands[0].Satisfied)
+
+		require.Equal(t, "(OutOf(2,system) OR MinPercent(100,system))", condition.Operands[1].Condition)
+		require.False(t, condition.Operands[1].Satisfied)
+		require.Equal(t, 2, condition.Operands[1].Members)
+		require.Equal(t, []string{witness2URL.String()}, condition.Operands[1].Collected)
+
+		ok, err := wp.Evaluate(witnessProofs)
+		require.NoError(t, err)
+		require.Equal(t, ok, evaluation.Satisfied)
+	})
+
+	t.Run("error - invalid policy", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		require.NoError(t, wp.cache.SetWithExpire(WitnessPolicyKey, "OutOf(x,system)", defaultPolicyCacheExpiry))
+
+		evaluation, err := wp.Explain(witnessProofs)
+		require.Error(t, err)
+		require.Nil(t, evaluation)
+		require.Contains(t, err.Error(), "failed to parse policy config")
+	})
+}
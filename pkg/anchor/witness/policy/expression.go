@@ -0,0 +1,231 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package policy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+// evaluateExpression evaluates the expression of a policy that uses named witness groups, weights
+// or nested expressions.
+func evaluateExpression(cfg *config.WitnessPolicyConfig, expr *config.Expression,
+	witnesses []*proof.WitnessProof,
+) bool {
+	if expr.Rule != nil {
+		return evaluateRule(cfg, expr.Rule, witnesses)
+	}
+
+	for _, operand := range expr.Operands {
+		satisfied := evaluateExpression(cfg, operand, witnesses)
+
+		if expr.Operator == config.OR && satisfied {
+			return true
+		}
+
+		if expr.Operator == config.AND && !satisfied {
+			return false
+		}
+	}
+
+	return expr.Operator == config.AND
+}
+
+func evaluateRule(cfg *config.WitnessPolicyConfig, rule *config.Rule, witnesses []*proof.WitnessProof) bool {
+	return explainRule(cfg, rule, witnesses).Satisfied
+}
+
+// selectForExpression selects the minimum number of witnesses required to satisfy the given expression. The
+// witnesses that were already selected (for other parts of the expression) are taken into account, and only
+// the newly selected witnesses are returned.
+func (wp *WitnessPolicy) selectForExpression(cfg *config.WitnessPolicyConfig, expr *config.Expression,
+	witnesses, eligible, selected []*proof.Witness,
+) ([]*proof.Witness, error) {
+	if expr.Rule != nil {
+		return wp.selectForRule(cfg, expr.Rule, witnesses, eligible, selected)
+	}
+
+	if expr.Operator == config.AND {
+		var newlySelected []*proof.Witness
+
+		for _, operand := range expr.Operands {
+			current := append(append([]*proof.Witness{}, selected...), newlySelected...)
+
+			s, err := wp.selectForExpression(cfg, operand, witnesses, eligible, current)
+			if err != nil {
+				return nil, err
+			}
+
+			newlySelected = append(newlySelected, s...)
+		}
+
+		return newlySelected, nil
+	}
+
+	// OR: choose the operand that requires the fewest additional witnesses.
+	var (
+		smallest []*proof.Witness
+		found    bool
+		firstErr error
+	)
+
+	for _, operand := range expr.Operands {
+		s, err := wp.selectForExpression(cfg, operand, witnesses, eligible, selected)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		if !found || len(s) < len(smallest) {
+			smallest = s
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, firstErr
+	}
+
+	return smallest, nil
+}
+
+func (wp *WitnessPolicy) selectForRule(cfg *config.WitnessPolicyConfig, rule *config.Rule,
+	witnesses, eligible, selected []*proof.Witness,
+) ([]*proof.Witness, error) {
+	members := groupMembers(cfg, rule, witnesses)
+
+	alreadySelected := make(map[string]bool)
+
+	for _, w := range selected {
+		if members[w.URI.String()] {
+			alreadySelected[w.URI.String()] = true
+		}
+	}
+
+	candidates := uniqueWitnesses(eligible, func(w *proof.Witness) bool {
+		return members[w.URI.String()] && !alreadySelected[w.URI.String()]
+	})
+
+	if rule.Type == config.MinWeight {
+		return selectByWeight(cfg, candidates, rule.Value-totalWeight(cfg, alreadySelected))
+	}
+
+	minSelection := rule.Value
+
+	if rule.Type == config.MinPercent {
+		minSelection = int(math.Ceil(float64(rule.Value) / maxPercent * float64(len(members))))
+	}
+
+	minSelection -= len(alreadySelected)
+
+	if minSelection <= 0 {
+		return nil, nil
+	}
+
+	selection, err := wp.selector.Select(candidates, minSelection)
+	if err != nil {
+		return nil, fmt.Errorf("select witnesses for rule %s: %w", rule, err)
+	}
+
+	return selection, nil
+}
+
+// selectByWeight selects the witnesses with the highest weights until the required weight is reached.
+func selectByWeight(cfg *config.WitnessPolicyConfig, candidates []*proof.Witness,
+	requiredWeight int,
+) ([]*proof.Witness, error) {
+	if requiredWeight <= 0 {
+		return nil, nil
+	}
+
+	sorted := append([]*proof.Witness{}, candidates...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return cfg.WitnessWeight(sorted[i].URI.String()) > cfg.WitnessWeight(sorted[j].URI.String())
+	})
+
+	var selection []*proof.Witness
+
+	weight := 0
+
+	for _, w := range sorted {
+		if weight >= requiredWeight {
+			break
+		}
+
+		selection = append(selection, w)
+		weight += cfg.WitnessWeight(w.URI.String())
+	}
+
+	if weight < requiredWeight {
+		return nil, fmt.Errorf("unable to select witnesses with a total weight of %d from %d witnesses: %w",
+			requiredWeight, len(candidates), orberrors.ErrWitnessesNotFound)
+	}
+
+	return selection, nil
+}
+
+// groupMembers returns the URIs of the witnesses in the group that's referenced by the rule. The members of
+// the batch and system roles are the given witnesses of the corresponding type, whereas the members of a named
+// (or inline) group are defined in the policy.
+func groupMembers(cfg *config.WitnessPolicyConfig, rule *config.Rule, witnesses []*proof.Witness) map[string]bool {
+	members := make(map[string]bool)
+
+	if rule.IsRole() {
+		for _, w := range witnesses {
+			if string(w.Type) == rule.Group {
+				members[w.URI.String()] = true
+			}
+		}
+
+		return members
+	}
+
+	for _, uri := range cfg.GroupMembers(rule) {
+		members[uri] = true
+	}
+
+	return members
+}
+
+func totalWeight(cfg *config.WitnessPolicyConfig, uris map[string]bool) int {
+	weight := 0
+
+	for uri := range uris {
+		weight += cfg.WitnessWeight(uri)
+	}
+
+	return weight
+}
+
+// uniqueWitnesses returns the witnesses that satisfy the given filter. If a witness appears more than once
+// (e.g. as both a batch and a system witness) then only the first instance is returned.
+func uniqueWitnesses(witnesses []*proof.Witness, filter func(w *proof.Witness) bool) []*proof.Witness {
+	var result []*proof.Witness
+
+	added := make(map[string]bool)
+
+	for _, w := range witnesses {
+		if added[w.URI.String()] || !filter(w) {
+			continue
+		}
+
+		added[w.URI.String()] = true
+
+		result = append(result, w)
+	}
+
+	return result
+}
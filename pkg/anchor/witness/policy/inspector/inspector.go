@@ -8,8 +8,10 @@ package inspector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/trustbloc/logutil-go/pkg/log"
@@ -17,6 +19,8 @@ import (
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/linkset"
@@ -43,6 +47,12 @@ type Providers struct {
 	Outbox          outboxProvider
 	WitnessStore    witnessStore
 	WitnessPolicy   witnessPolicy
+
+	// WitnessStats (optional) records the witnesses that did not return a proof in time.
+	WitnessStats witnessStats
+	// PolicyScopes (optional) provides the scope that's used to resolve the witness policies of an anchor.
+	// If not set then the global witness policy applies.
+	PolicyScopes policyScopeStore
 }
 
 type witnessStore interface {
@@ -50,8 +60,61 @@ type witnessStore interface {
 	UpdateWitnessSelection(anchorID string, witnesses []*url.URL, selected bool) error
 }
 
+type witnessStats interface {
+	AddTimeout(anchorID string, witness *url.URL) error
+}
+
+type policyScopeStore interface {
+	GetPolicyScope(anchorID string) (*policycfg.AnchorScope, error)
+}
+
 type witnessPolicy interface {
-	Select(witnesses []*proof.Witness, excluded ...*proof.Witness) ([]*proof.Witness, error)
+	SelectForScope(scope *policycfg.AnchorScope, witnesses []*proof.Witness,
+		excluded ...*proof.Witness) ([]*proof.Witness, error)
+	ExplainForScope(scope *policycfg.AnchorScope, witnesses []*proof.WitnessProof) (*policy.Evaluation, error)
+	SelectLogWitnesses(witnesses []*proof.Witness, n int, exclude ...*proof.Witness) ([]*proof.Witness, error)
+}
+
+// ActionType is the type of action that the inspector takes for an anchor.
+type ActionType string
+
+const (
+	// ActionNone indicates that the inspector won't take any action since the witness policy is satisfied.
+	ActionNone ActionType = "none"
+	// ActionReOffer indicates that the anchor will be offered to additional witnesses.
+	ActionReOffer ActionType = "re-offer"
+	// ActionAbandon indicates that no additional witnesses can be selected, so the anchor won't be re-offered.
+	ActionAbandon ActionType = "abandon"
+)
+
+// Explanation explains why an anchor is pending.
+type Explanation struct {
+	AnchorID   string                 `json:"anchorID"`
+	Scope      *policycfg.AnchorScope `json:"scope,omitempty"`
+	Witnesses  []*WitnessExplanation  `json:"witnesses"`
+	Policy     *policy.Evaluation     `json:"policy"`
+	NextAction *Action                `json:"nextAction"`
+}
+
+// WitnessExplanation contains the state of a witness of an anchor.
+type WitnessExplanation struct {
+	URI           string            `json:"uri"`
+	Type          proof.WitnessType `json:"type"`
+	HasLog        bool              `json:"hasLog"`
+	Selected      bool              `json:"selected"`
+	ProofReceived bool              `json:"proofReceived"`
+}
+
+// Action is the action that the inspector will take when it next checks the policy of an anchor.
+type Action struct {
+	Type        ActionType `json:"type"`
+	Description string     `json:"description"`
+	// Time is the (approximate) time of the action.
+	Time *time.Time `json:"time,omitempty"`
+	// TimedOutWitnesses contains the selected witnesses that haven't returned a proof and will be excluded.
+	TimedOutWitnesses []string `json:"timedOutWitnesses,omitempty"`
+	// CandidateWitnesses contains the witnesses that would be selected if the policy were checked now.
+	CandidateWitnesses []string `json:"candidateWitnesses,omitempty"`
 }
 
 // Outbox defines outbox.
@@ -79,7 +142,7 @@ func (c *Inspector) CheckPolicy(anchorID string) error {
 		return fmt.Errorf("get anchor event: %w", err)
 	}
 
-	witnessesIRI, err := c.getAdditionalWitnesses(anchorLink.Anchor().String())
+	witnessesIRI, timedOutWitnessesIRI, err := c.getAdditionalWitnesses(anchorLink.Anchor().String())
 	if err != nil {
 		return fmt.Errorf("failed to get additional witnesses: %w", err)
 	}
@@ -96,6 +159,12 @@ func (c *Inspector) CheckPolicy(anchorID string) error {
 			anchorLink.Anchor(), err)
 	}
 
+	// The timeouts are recorded only after the anchor was re-offered so that they aren't recorded again if
+	// the policy check is retried. (The witness store also records a timeout only once per anchor and witness.)
+	for _, w := range timedOutWitnessesIRI {
+		c.addTimeout(anchorLink.Anchor().String(), w)
+	}
+
 	return nil
 }
 
@@ -133,10 +202,12 @@ func (c *Inspector) postOfferActivity(ctx context.Context, anchorLink *linkset.L
 	return nil
 }
 
-func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, error) {
+// getAdditionalWitnesses selects additional witnesses for the anchor. The selected witnesses that didn't return
+// a proof (i.e. timed out) are also returned.
+func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, []*url.URL, error) {
 	witnesses, err := c.WitnessStore.Get(anchorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get witnesses for anchorID[%s]: %w", anchorID, err)
+		return nil, nil, fmt.Errorf("failed to get witnesses for anchorID[%s]: %w", anchorID, err)
 	}
 
 	var allWitnesses []*proof.Witness
@@ -145,6 +216,8 @@ func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, error)
 
 	var selectedWitnessesIRI []*url.URL
 
+	var timedOutWitnessesIRI []*url.URL
+
 	// exclude failed witnesses from the witness selection list
 	for _, w := range witnesses {
 		if w.Selected {
@@ -156,6 +229,8 @@ func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, error)
 					"This witness will be ignored during re-selection of witnesses.",
 					logfields.WithWitnessURI(w.URI), logfields.WithAnchorURIString(anchorID))
 
+				timedOutWitnessesIRI = append(timedOutWitnessesIRI, w.URI.URL())
+
 				excludeWitness := &proof.Witness{
 					Type:     w.Type,
 					URI:      w.URI,
@@ -177,9 +252,14 @@ func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, error)
 		allWitnesses = append(allWitnesses, witness)
 	}
 
-	newlySelectedWitnesses, err := c.WitnessPolicy.Select(allWitnesses, excludeWitnesses...)
+	scope, err := c.getPolicyScope(anchorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newlySelectedWitnesses, err := c.WitnessPolicy.SelectForScope(scope, allWitnesses, excludeWitnesses...)
 	if err != nil {
-		return nil, fmt.Errorf("select witnesses for anchorID[%s]: %w", anchorID, err)
+		return nil, nil, fmt.Errorf("select witnesses for anchorID[%s]: %w", anchorID, err)
 	}
 
 	newlySelectedWitnessesIRI, _ := getUniqueWitnesses(newlySelectedWitnesses)
@@ -187,7 +267,19 @@ func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, error)
 	additionalWitnessesIRI := difference(newlySelectedWitnessesIRI, selectedWitnessesIRI)
 
 	if len(additionalWitnessesIRI) == 0 {
-		return nil, fmt.Errorf("unable to select additional witnesses for anchorID[%s] from newly selected "+
+		evaluation, e := c.WitnessPolicy.ExplainForScope(scope, witnesses)
+		if e != nil {
+			return nil, nil, fmt.Errorf("explain witness policy for anchorID[%s]: %w", anchorID, e)
+		}
+
+		additionalWitnessesIRI, err = c.selectLogWitnesses(evaluation, allWitnesses)
+		if err != nil {
+			return nil, nil, fmt.Errorf("select witnesses with a log for anchorID[%s]: %w", anchorID, err)
+		}
+	}
+
+	if len(additionalWitnessesIRI) == 0 {
+		return nil, nil, fmt.Errorf("unable to select additional witnesses for anchorID[%s] from newly selected "+
 			"witnesses[%s] and previously selected witnesses[%s] with exclude witnesses[%s]: %w",
 			anchorID, newlySelectedWitnessesIRI, selectedWitnessesIRI, excludeWitnesses, orberrors.ErrWitnessesNotFound)
 	}
@@ -195,13 +287,230 @@ func (c *Inspector) getAdditionalWitnesses(anchorID string) ([]*url.URL, error)
 	// update selected flag for additional witnesses
 	err = c.WitnessStore.UpdateWitnessSelection(anchorID, additionalWitnessesIRI, true)
 	if err != nil {
-		return nil, fmt.Errorf("update witness selection flag for anchorID[%s]: %w", anchorID, err)
+		return nil, nil, fmt.Errorf("update witness selection flag for anchorID[%s]: %w", anchorID, err)
 	}
 
 	logger.Debug("Selected witnesses for anchor", logfields.WithTotal(len(newlySelectedWitnessesIRI)),
 		logfields.WithAnchorURIString(anchorID), logfields.WithWitnessURIs(newlySelectedWitnessesIRI...))
 
-	return additionalWitnessesIRI, nil
+	return additionalWitnessesIRI, timedOutWitnessesIRI, nil
+}
+
+// Explain returns the witnesses of the given anchor along with the result of evaluating the witness policy
+// and the action that will be taken the next time that the policy of the anchor is checked. The witness store
+// isn't updated.
+func (c *Inspector) Explain(anchorID string) (*Explanation, error) {
+	witnesses, err := c.WitnessStore.Get(anchorID)
+	if err != nil {
+		return nil, fmt.Errorf("get witnesses for anchorID[%s]: %w", anchorID, err)
+	}
+
+	scope, err := c.getPolicyScope(anchorID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation, err := c.WitnessPolicy.ExplainForScope(scope, witnesses)
+	if err != nil {
+		return nil, fmt.Errorf("explain witness policy for anchorID[%s]: %w", anchorID, err)
+	}
+
+	explanation := &Explanation{
+		AnchorID: anchorID,
+		Scope:    scope,
+		Policy:   evaluation,
+	}
+
+	var (
+		allWitnesses     []*proof.Witness
+		excludeWitnesses []*proof.Witness
+		selectedIRIs     []*url.URL
+		timedOut         []string
+	)
+
+	for _, w := range witnesses {
+		explanation.Witnesses = append(explanation.Witnesses, &WitnessExplanation{
+			URI:           w.URI.String(),
+			Type:          w.Type,
+			HasLog:        w.HasLog,
+			Selected:      w.Selected,
+			ProofReceived: w.Proof != nil,
+		})
+
+		witness := &proof.Witness{Type: w.Type, URI: w.URI, HasLog: w.HasLog, Selected: w.Selected}
+
+		if w.Selected {
+			selectedIRIs = append(selectedIRIs, w.URI.URL())
+
+			if w.Proof == nil {
+				excludeWitnesses = append(excludeWitnesses, witness)
+				timedOut = append(timedOut, w.URI.String())
+			}
+		}
+
+		allWitnesses = append(allWitnesses, witness)
+	}
+
+	explanation.NextAction = c.explainNextAction(scope, evaluation, allWitnesses, excludeWitnesses, selectedIRIs, timedOut)
+
+	return explanation, nil
+}
+
+// explainNextAction determines the action that CheckPolicy would take for the anchor.
+func (c *Inspector) explainNextAction(scope *policycfg.AnchorScope, evaluation *policy.Evaluation,
+	allWitnesses, excludeWitnesses []*proof.Witness, selectedIRIs []*url.URL, timedOut []string,
+) *Action {
+	if evaluation.Satisfied {
+		return &Action{
+			Type:        ActionNone,
+			Description: "The witness policy is satisfied. The anchor will be completed when the proofs are processed.",
+		}
+	}
+
+	action := &Action{
+		Type:              ActionReOffer,
+		TimedOutWitnesses: timedOut,
+	}
+
+	newlySelected, err := c.WitnessPolicy.SelectForScope(scope, allWitnesses, excludeWitnesses...)
+	if err != nil {
+		action.Type = ActionAbandon
+		action.Description = fmt.Sprintf("Additional witnesses cannot be selected: %s. "+
+			"The anchor will no longer be monitored.", err)
+
+		return action
+	}
+
+	newlySelectedIRIs, _ := getUniqueWitnesses(newlySelected)
+
+	additional := difference(newlySelectedIRIs, selectedIRIs)
+
+	if len(additional) == 0 {
+		additional, err = c.selectLogWitnesses(evaluation, allWitnesses)
+		if err != nil {
+			action.Type = ActionAbandon
+			action.Description = fmt.Sprintf("Additional witnesses with a log cannot be selected: %s. "+
+				"The anchor will no longer be monitored.", err)
+
+			return action
+		}
+	}
+
+	if len(additional) == 0 {
+		action.Type = ActionAbandon
+		action.Description = "No additional witnesses are available. The anchor will no longer be monitored."
+
+		return action
+	}
+
+	for _, u := range additional {
+		action.CandidateWitnesses = append(action.CandidateWitnesses, u.String())
+	}
+
+	action.Description = "The selected witnesses that did not return a proof will be excluded and the anchor " +
+		"will be offered to additional witnesses. Witness selection may be random, so the candidate witnesses " +
+		"are indicative only."
+
+	return action
+}
+
+// selectLogWitnesses selects additional witnesses with a log if the witness policy isn't satisfied only because the
+// proofs of the selected witnesses were added to too few distinct logs, i.e. the selected witnesses share logs.
+// Nil is returned if the policy isn't satisfied for another reason or if there are no more witnesses with a log.
+func (c *Inspector) selectLogWitnesses(evaluation *policy.Evaluation, allWitnesses []*proof.Witness) ([]*url.URL, error) {
+	n, ok := missingLogs(evaluation.Condition)
+	if !ok || n <= 0 {
+		return nil, nil
+	}
+
+	var selected []*proof.Witness
+
+	for _, w := range allWitnesses {
+		if w.Selected {
+			selected = append(selected, w)
+		}
+	}
+
+	logWitnesses, err := c.WitnessPolicy.SelectLogWitnesses(allWitnesses, n, selected...)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrWitnessesNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	logWitnessesIRI, _ := getUniqueWitnesses(logWitnesses)
+
+	logger.Info("Witness policy requires more distinct logs. Selected additional witnesses with a log.",
+		logfields.WithTotal(n), logfields.WithWitnessURIs(logWitnessesIRI...))
+
+	return logWitnessesIRI, nil
+}
+
+// missingLogs returns the number of additional distinct logs that are required to satisfy the given condition.
+// False is returned if the condition isn't satisfied for any reason other than too few distinct logs.
+func missingLogs(condition *policy.ConditionResult) (int, bool) {
+	if condition == nil {
+		return 0, false
+	}
+
+	if condition.Satisfied {
+		return 0, true
+	}
+
+	if strings.HasPrefix(condition.Condition, policycfg.LogRequired+"(") {
+		return condition.Minimum - len(condition.Collected), true
+	}
+
+	if condition.Condition != policycfg.AND || len(condition.Operands) == 0 {
+		return 0, false
+	}
+
+	var missing int
+
+	for _, operand := range condition.Operands {
+		n, ok := missingLogs(operand)
+		if !ok {
+			return 0, false
+		}
+
+		if n > missing {
+			missing = n
+		}
+	}
+
+	return missing, true
+}
+
+// getPolicyScope returns the scope that's used to resolve the witness policies of the anchor. Nil is returned
+// if no scope was stored for the anchor, in which case the global witness policy applies.
+func (c *Inspector) getPolicyScope(anchorID string) (*policycfg.AnchorScope, error) {
+	if c.PolicyScopes == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	scope, err := c.PolicyScopes.GetPolicyScope(anchorID)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("get policy scope for anchorID[%s]: %w", anchorID, err)
+	}
+
+	return scope, nil
+}
+
+func (c *Inspector) addTimeout(anchorID string, witness *url.URL) {
+	if c.WitnessStats == nil {
+		return
+	}
+
+	if err := c.WitnessStats.AddTimeout(anchorID, witness); err != nil {
+		logger.Warn("Error recording witness timeout", logfields.WithWitnessURI(witness),
+			logfields.WithAnchorURIString(anchorID), log.WithError(err))
+	}
 }
 
 func getUniqueWitnesses(witnesses []*proof.Witness) ([]*url.URL, map[string]bool) {
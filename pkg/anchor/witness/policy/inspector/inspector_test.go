@@ -9,6 +9,7 @@ package inspector
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"testing"
@@ -18,8 +19,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	policymocks "github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
 	"github.com/trustbloc/orb/pkg/linkset"
 	"github.com/trustbloc/orb/pkg/pubsub/mempubsub"
 	anchorlinkstore "github.com/trustbloc/orb/pkg/store/anchorlink"
@@ -101,6 +106,57 @@ func TestInspector_CheckPolicy(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("success - witness timeout recorded", func(t *testing.T) {
+		anchorLinkBytes, err := json.Marshal(anchorLink)
+		require.NoError(t, err)
+
+		s := &mocks.Store{}
+		s.GetReturns(anchorLinkBytes, nil)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(s, nil)
+
+		anchorLinkStore, err := anchorlinkstore.New(p)
+		require.NoError(t, err)
+
+		selectedWitnessURL, err := url.Parse("http://domain.com/service")
+		require.NoError(t, err)
+
+		respondedWitnessURL, err := url.Parse("http://responded-domain.com/service")
+		require.NoError(t, err)
+
+		notSelectedWitnessURL, err := url.Parse("http://other-domain.com/service")
+		require.NoError(t, err)
+
+		witnessStore := &policymocks.WitnessStore{}
+		witnessStore.GetReturns([]*proof.WitnessProof{
+			{Witness: &proof.Witness{URI: vocab.NewURLProperty(selectedWitnessURL), Selected: true}},
+			{
+				Witness: &proof.Witness{URI: vocab.NewURLProperty(respondedWitnessURL), Selected: true},
+				Proof:   []byte("proof"),
+			},
+			{Witness: &proof.Witness{URI: vocab.NewURLProperty(notSelectedWitnessURL), Selected: false}},
+		}, nil)
+
+		witnessStats := &mockWitnessStats{err: fmt.Errorf("injected stats error")}
+
+		providers := &Providers{
+			AnchorLinkStore: anchorLinkStore,
+			Outbox:          func() Outbox { return &mockOutbox{} },
+			WitnessStore:    witnessStore,
+			WitnessPolicy:   &mockWitnessPolicy{},
+			WitnessStats:    witnessStats,
+		}
+
+		c, err := New(providers, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		err = c.CheckPolicy(anchorLink.Anchor().String())
+		require.NoError(t, err)
+		require.Len(t, witnessStats.timeouts, 1)
+		require.Equal(t, selectedWitnessURL.String(), witnessStats.timeouts[0].String())
+	})
+
 	t.Run("error - get anchor event error", func(t *testing.T) {
 		anchorLinkStore, err := anchorlinkstore.New(mem.NewProvider())
 		require.NoError(t, err)
@@ -151,11 +207,14 @@ func TestInspector_CheckPolicy(t *testing.T) {
 			{Witness: &proof.Witness{URI: vocab.NewURLProperty(notSelectedWitnessURL), Selected: false}},
 		}, nil)
 
+		witnessStats := &mockWitnessStats{}
+
 		providers := &Providers{
 			AnchorLinkStore: anchorLinkStore,
 			Outbox:          func() Outbox { return &mockOutbox{Err: fmt.Errorf("outbox error")} },
 			WitnessStore:    witnessStore,
 			WitnessPolicy:   &mockWitnessPolicy{},
+			WitnessStats:    witnessStats,
 		}
 
 		c, err := New(providers, testMaxWitnessDelay)
@@ -164,6 +223,9 @@ func TestInspector_CheckPolicy(t *testing.T) {
 		err = c.CheckPolicy(anchorLink.Anchor().String())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "outbox error")
+
+		// Timeouts are only recorded after the anchor was re-offered.
+		require.Empty(t, witnessStats.timeouts)
 	})
 
 	t.Run("error - no additional witnesses selected", func(t *testing.T) {
@@ -204,6 +266,95 @@ func TestInspector_CheckPolicy(t *testing.T) {
 		)
 	})
 
+	t.Run("success - witnesses with a log selected when logs are missing", func(t *testing.T) {
+		anchorLinkStore, err := anchorlinkstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = anchorLinkStore.Put(anchorLink)
+		require.NoError(t, err)
+
+		selectedWitness := &proof.Witness{
+			URI: vocab.NewURLProperty(testutil.MustParseURL("http://domain.com/service")), HasLog: true, Selected: true,
+		}
+
+		logWitness := &proof.Witness{
+			URI: vocab.NewURLProperty(testutil.MustParseURL("http://log-domain.com/service")), HasLog: true,
+		}
+
+		witnessStore := &policymocks.WitnessStore{}
+		witnessStore.GetReturns([]*proof.WitnessProof{
+			{Witness: selectedWitness, Proof: []byte(`{"proof":{"domain":"https://vct1.com/log"}}`)},
+			{Witness: logWitness},
+		}, nil)
+
+		witnessPolicy := &mockWitnessPolicy{
+			Witnesses: []*proof.Witness{selectedWitness},
+			Evaluation: &policy.Evaluation{
+				Condition: &policy.ConditionResult{
+					Condition: policycfg.AND,
+					Operands: []*policy.ConditionResult{
+						{Condition: "OutOf(1,system)", Satisfied: true},
+						{Condition: "LogRequired(2)", Collected: []string{"https://vct1.com/log"}, Minimum: 2},
+					},
+				},
+			},
+			LogWitnesses: []*proof.Witness{logWitness},
+		}
+
+		witnessStats := &mockWitnessStats{}
+
+		providers := &Providers{
+			AnchorLinkStore: anchorLinkStore,
+			Outbox:          func() Outbox { return &mockOutbox{} },
+			WitnessStore:    witnessStore,
+			WitnessPolicy:   witnessPolicy,
+			WitnessStats:    witnessStats,
+		}
+
+		c, err := New(providers, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		require.NoError(t, c.CheckPolicy(anchorLink.Anchor().String()))
+		require.Equal(t, 1, witnessPolicy.logWitnessesRequested)
+		require.Equal(t, 1, witnessStore.UpdateWitnessSelectionCallCount())
+
+		_, updated, selected := witnessStore.UpdateWitnessSelectionArgsForCall(0)
+		require.True(t, selected)
+		require.Len(t, updated, 1)
+		require.Equal(t, logWitness.URI.String(), updated[0].String())
+		require.Empty(t, witnessStats.timeouts)
+	})
+
+	t.Run("error - explain policy error", func(t *testing.T) {
+		anchorLinkStore, err := anchorlinkstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		err = anchorLinkStore.Put(anchorLink)
+		require.NoError(t, err)
+
+		selectedWitness := &proof.Witness{URI: vocab.NewURLProperty(testutil.MustParseURL("http://domain.com/service")), Selected: true}
+
+		witnessStore := &policymocks.WitnessStore{}
+		witnessStore.GetReturns([]*proof.WitnessProof{{Witness: selectedWitness, Proof: []byte("proof")}}, nil)
+
+		providers := &Providers{
+			AnchorLinkStore: anchorLinkStore,
+			Outbox:          func() Outbox { return &mockOutbox{} },
+			WitnessStore:    witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{
+				Witnesses:  []*proof.Witness{selectedWitness},
+				ExplainErr: errors.New("injected explain error"),
+			},
+		}
+
+		c, err := New(providers, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		err = c.CheckPolicy(anchorLink.Anchor().String())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected explain error")
+	})
+
 	t.Run("error - witness store error", func(t *testing.T) {
 		anchorLinkStore, err := anchorlinkstore.New(mem.NewProvider())
 		require.NoError(t, err)
@@ -252,6 +403,42 @@ func TestInspector_CheckPolicy(t *testing.T) {
 	})
 }
 
+func TestMissingLogs(t *testing.T) {
+	logsCondition := &policy.ConditionResult{Condition: "LogRequired(3)", Collected: []string{"log1"}, Minimum: 3}
+
+	n, ok := missingLogs(logsCondition)
+	require.True(t, ok)
+	require.Equal(t, 2, n)
+
+	n, ok = missingLogs(&policy.ConditionResult{
+		Condition: policycfg.AND,
+		Operands: []*policy.ConditionResult{
+			{Condition: "OutOf(1,system)", Satisfied: true},
+			logsCondition,
+			{Condition: "LogRequired(2)", Collected: []string{"log1"}, Minimum: 2},
+		},
+	})
+	require.True(t, ok)
+	require.Equal(t, 2, n)
+
+	// The rules aren't satisfied.
+	_, ok = missingLogs(&policy.ConditionResult{
+		Condition: policycfg.AND,
+		Operands:  []*policy.ConditionResult{{Condition: "OutOf(1,system)"}, logsCondition},
+	})
+	require.False(t, ok)
+
+	_, ok = missingLogs(&policy.ConditionResult{Condition: "OR", Operands: []*policy.ConditionResult{logsCondition}})
+	require.False(t, ok)
+
+	_, ok = missingLogs(nil)
+	require.False(t, ok)
+
+	n, ok = missingLogs(&policy.ConditionResult{Condition: "OutOf(1,system)", Satisfied: true})
+	require.True(t, ok)
+	require.Zero(t, n)
+}
+
 func TestWriter_postOfferActivity(t *testing.T) {
 	ps := mempubsub.New(mempubsub.Config{})
 	defer ps.Stop()
@@ -328,12 +515,217 @@ func (w *mockWitnessStore) UpdateWitnessSelection(anchorID string, witnesses []*
 	return nil
 }
 
+func TestInspector_Explain(t *testing.T) {
+	selectedWitnessURL := testutil.MustParseURL("http://domain.com/service")
+	respondedWitnessURL := testutil.MustParseURL("http://responded-domain.com/service")
+	notSelectedWitnessURL := testutil.MustParseURL("http://other-domain.com/service")
+
+	witnessStore := &policymocks.WitnessStore{}
+	witnessStore.GetReturns([]*proof.WitnessProof{
+		{Witness: &proof.Witness{URI: vocab.NewURLProperty(selectedWitnessURL), Selected: true}},
+		{
+			Witness: &proof.Witness{URI: vocab.NewURLProperty(respondedWitnessURL), Selected: true},
+			Proof:   []byte("proof"),
+		},
+		{Witness: &proof.Witness{URI: vocab.NewURLProperty(notSelectedWitnessURL), Selected: false}},
+	}, nil)
+
+	const anchorID = "hl:uEiD1W_21fZwLNqV-T10km8jMvsT_lQZmmR4vIHQD7Wu73g"
+
+	t.Run("re-offer", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore: witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{
+				Evaluation: &policy.Evaluation{Policy: "OutOf(2,system)"},
+			},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, anchorID, explanation.AnchorID)
+		require.Equal(t, "OutOf(2,system)", explanation.Policy.Policy)
+		require.Len(t, explanation.Witnesses, 3)
+		require.False(t, explanation.Witnesses[0].ProofReceived)
+		require.True(t, explanation.Witnesses[1].ProofReceived)
+		require.False(t, explanation.Witnesses[2].Selected)
+
+		require.Equal(t, ActionReOffer, explanation.NextAction.Type)
+		require.Equal(t, []string{selectedWitnessURL.String()}, explanation.NextAction.TimedOutWitnesses)
+		require.Equal(t, []string{notSelectedWitnessURL.String()}, explanation.NextAction.CandidateWitnesses)
+
+		// The witness selection must not be updated.
+		require.Zero(t, witnessStore.UpdateWitnessSelectionCallCount())
+	})
+
+	t.Run("policy satisfied", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore:  witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{Evaluation: &policy.Evaluation{Satisfied: true}},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, ActionNone, explanation.NextAction.Type)
+	})
+
+	t.Run("no additional witnesses", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore: witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{
+				Witnesses: []*proof.Witness{{URI: vocab.NewURLProperty(selectedWitnessURL)}},
+			},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, ActionAbandon, explanation.NextAction.Type)
+	})
+
+	t.Run("witness selection error", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore:  witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{Err: fmt.Errorf("witness selection error")},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, ActionAbandon, explanation.NextAction.Type)
+		require.Contains(t, explanation.NextAction.Description, "witness selection error")
+	})
+
+	t.Run("error - witness store", func(t *testing.T) {
+		ws := &policymocks.WitnessStore{}
+		ws.GetReturns(nil, fmt.Errorf("witness store error"))
+
+		c, err := New(&Providers{WitnessStore: ws, WitnessPolicy: &mockWitnessPolicy{}}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.Error(t, err)
+		require.Nil(t, explanation)
+		require.Contains(t, err.Error(), "witness store error")
+	})
+
+	t.Run("scoped policy", func(t *testing.T) {
+		scope := &policycfg.AnchorScope{Namespace: "did:orb", Origins: []string{"https://orb.domain1.com"}}
+
+		wp := &mockWitnessPolicy{Evaluation: &policy.Evaluation{Satisfied: true}}
+
+		c, err := New(&Providers{
+			WitnessStore:  witnessStore,
+			WitnessPolicy: wp,
+			PolicyScopes:  &mockPolicyScopeStore{scope: scope},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, scope, explanation.Scope)
+		require.Equal(t, scope, wp.scope)
+	})
+
+	t.Run("scope not found -> global policy", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore:  witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{Evaluation: &policy.Evaluation{Satisfied: true}},
+			PolicyScopes:  &mockPolicyScopeStore{err: orberrors.ErrContentNotFound},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.NoError(t, err)
+		require.Nil(t, explanation.Scope)
+	})
+
+	t.Run("error - policy scope", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore:  witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{},
+			PolicyScopes:  &mockPolicyScopeStore{err: fmt.Errorf("injected scope error")},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		_, err = c.Explain(anchorID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected scope error")
+	})
+
+	t.Run("error - explain policy", func(t *testing.T) {
+		c, err := New(&Providers{
+			WitnessStore:  witnessStore,
+			WitnessPolicy: &mockWitnessPolicy{ExplainErr: fmt.Errorf("explain error")},
+		}, testMaxWitnessDelay)
+		require.NoError(t, err)
+
+		explanation, err := c.Explain(anchorID)
+		require.Error(t, err)
+		require.Nil(t, explanation)
+		require.Contains(t, err.Error(), "explain error")
+	})
+}
+
+type mockPolicyScopeStore struct {
+	scope *policycfg.AnchorScope
+	err   error
+}
+
+func (m *mockPolicyScopeStore) GetPolicyScope(string) (*policycfg.AnchorScope, error) {
+	return m.scope, m.err
+}
+
+type mockWitnessStats struct {
+	timeouts []*url.URL
+	err      error
+}
+
+func (m *mockWitnessStats) AddTimeout(_ string, witness *url.URL) error {
+	m.timeouts = append(m.timeouts, witness)
+
+	return m.err
+}
+
 type mockWitnessPolicy struct {
-	Witnesses []*proof.Witness
-	Err       error
+	Witnesses  []*proof.Witness
+	Err        error
+	Evaluation *policy.Evaluation
+	ExplainErr error
+
+	LogWitnesses []*proof.Witness
+	LogErr       error
+
+	scope                 *policycfg.AnchorScope
+	logWitnessesRequested int
 }
 
-func (wp *mockWitnessPolicy) Select(witnesses []*proof.Witness, _ ...*proof.Witness) ([]*proof.Witness, error) {
+func (wp *mockWitnessPolicy) SelectLogWitnesses(_ []*proof.Witness, n int, _ ...*proof.Witness) ([]*proof.Witness, error) {
+	wp.logWitnessesRequested = n
+
+	return wp.LogWitnesses, wp.LogErr
+}
+
+func (wp *mockWitnessPolicy) ExplainForScope(scope *policycfg.AnchorScope, _ []*proof.WitnessProof) (*policy.Evaluation, error) {
+	wp.scope = scope
+
+	if wp.ExplainErr != nil {
+		return nil, wp.ExplainErr
+	}
+
+	if wp.Evaluation != nil {
+		return wp.Evaluation, nil
+	}
+
+	return &policy.Evaluation{}, nil
+}
+
+func (wp *mockWitnessPolicy) SelectForScope(scope *policycfg.AnchorScope, witnesses []*proof.Witness,
+	_ ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	wp.scope = scope
+
 	if wp.Err != nil {
 		return nil, wp.Err
 	}
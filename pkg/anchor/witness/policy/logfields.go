@@ -77,6 +77,13 @@ func newConfigMarshaller(cfg *config.WitnessPolicyConfig) *configMarshaller {
 }
 
 func (m *configMarshaller) MarshalLogObject(e zapcore.ObjectEncoder) error {
+	if m.cfg.Expression != nil {
+		e.AddString("expression", m.cfg.Expression.String())
+		e.AddBool("logRequired", m.cfg.LogRequired)
+
+		return nil
+	}
+
 	if m.cfg.MinNumberBatch > 0 {
 		e.AddInt("minBatch", m.cfg.MinNumberBatch)
 	}
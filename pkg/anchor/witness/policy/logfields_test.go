@@ -38,6 +38,15 @@ func TestConfigMarshaller(t *testing.T) {
 	require.Equal(t, cfg.MinPercentBatch, encoder.Fields["minPercentBatch"])
 	require.Equal(t, cfg.Operator, encoder.Fields["operator"])
 	require.Equal(t, cfg.LogRequired, encoder.Fields["logRequired"])
+
+	cfg, err := config.Parse("Group(eu,https://w1.eu,https://w2.eu) OutOf(1,eu) OR system")
+	require.NoError(t, err)
+
+	encoder = zapcore.NewMapObjectEncoder()
+
+	require.NoError(t, newConfigMarshaller(cfg).MarshalLogObject(encoder))
+	require.Equal(t, "(OutOf(1,eu) OR MinPercent(100,system))", encoder.Fields["expression"])
+	require.Nil(t, encoder.Fields["minPercentBatch"])
 }
 
 func TestWitnessMarshaller(t *testing.T) {
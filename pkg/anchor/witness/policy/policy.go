@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package policy
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/bluele/gcache"
@@ -20,6 +22,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy/selector/random"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/vct"
 )
 
 // WitnessPolicy evaluates witness policy.
@@ -28,13 +31,17 @@ type WitnessPolicy struct {
 	cache       gCache
 	cacheExpiry time.Duration
 
-	selector selector
+	selector        selector
+	scopedRetriever scopedPolicyRetriever
+	aliases         map[string]string
 }
 
 const (
 	// WitnessPolicyKey is witness policy key in config store.
 	WitnessPolicyKey = "witness-policy"
 
+	scopedPoliciesKey = "witness-policy-scoped"
+
 	maxPercent = 100
 
 	defaultCacheSize = 10
@@ -55,15 +62,52 @@ type policyRetriever interface {
 	GetPolicy() (string, error)
 }
 
+type scopedPolicyRetriever interface {
+	GetScopedPolicies() ([]*config.ScopedPolicy, error)
+}
+
+// Opt is a witness policy option.
+type Opt func(wp *WitnessPolicy)
+
+// WithSelector sets the selector that's used to select witnesses. If not set then witnesses are selected randomly.
+func WithSelector(s selector) Opt {
+	return func(wp *WitnessPolicy) {
+		wp.selector = s
+	}
+}
+
+// WithScopedPolicies sets the retriever of the witness policies that are scoped to a DID namespace and/or an
+// anchor origin. If not set then the global witness policy applies to all anchors.
+func WithScopedPolicies(r scopedPolicyRetriever) Opt {
+	return func(wp *WitnessPolicy) {
+		wp.scopedRetriever = r
+	}
+}
+
+// WithNamespaceAliases sets the aliases of the DID namespace. A witness policy that's scoped to an alias
+// applies to the anchors of the namespace.
+func WithNamespaceAliases(namespace string, aliases ...string) Opt {
+	return func(wp *WitnessPolicy) {
+		for _, alias := range aliases {
+			wp.aliases[alias] = namespace
+		}
+	}
+}
+
 // New will create new witness policy evaluator.
-func New(retriever policyRetriever, policyCacheExpiry time.Duration) (*WitnessPolicy, error) {
+func New(retriever policyRetriever, policyCacheExpiry time.Duration, opts ...Opt) (*WitnessPolicy, error) {
 	wp := &WitnessPolicy{
 		retriever:   retriever,
 		cacheExpiry: policyCacheExpiry,
 		selector:    random.New(),
+		aliases:     make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(wp)
 	}
 
-	wp.cache = gcache.New(defaultCacheSize).ARC().LoaderExpireFunc(wp.loadWitnessPolicy).Build()
+	wp.cache = gcache.New(defaultCacheSize).ARC().LoaderExpireFunc(wp.load).Build()
 
 	policy, _, err := wp.loadWitnessPolicy("")
 	if err != nil {
@@ -83,11 +127,66 @@ func New(retriever policyRetriever, policyCacheExpiry time.Duration) (*WitnessPo
 
 // Evaluate evaluates if witness policy has been satisfied for provided witnesses.
 func (wp *WitnessPolicy) Evaluate(witnesses []*proof.WitnessProof) (bool, error) {
-	cfg, err := wp.getWitnessPolicyConfig()
+	return wp.EvaluateForScope(nil, witnesses)
+}
+
+// EvaluateForScope evaluates if the witness policies that apply to the given anchor scope have been satisfied
+// for the provided witnesses. If the scope is nil then the global witness policy is evaluated.
+func (wp *WitnessPolicy) EvaluateForScope(scope *config.AnchorScope, witnesses []*proof.WitnessProof) (bool, error) {
+	cfgs, err := wp.getWitnessPolicyConfigs(scope)
+	if err != nil {
+		return false, err
+	}
+
+	for _, cfg := range cfgs {
+		if !wp.evaluate(cfg, witnesses) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// EvaluatePolicy evaluates if the given (candidate) witness policy is satisfied for the provided witnesses.
+// Unlike Evaluate, the configured witness policies are not used.
+func (wp *WitnessPolicy) EvaluatePolicy(policy string, witnesses []*proof.WitnessProof) (bool, error) {
+	cfg, err := parsePolicy(policy)
 	if err != nil {
 		return false, err
 	}
 
+	return wp.evaluate(cfg, witnesses), nil
+}
+
+func (wp *WitnessPolicy) evaluate(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) bool {
+	if !wp.evaluateRules(cfg, witnesses) {
+		return false
+	}
+
+	if cfg.MinLogs == 0 {
+		return true
+	}
+
+	logs := distinctLogs(cfg, witnesses)
+
+	evaluated := len(logs) >= cfg.MinLogs
+
+	logger.Debug("Minimum number of distinct logs was evaluated.", logfields.WithMinimum(cfg.MinLogs),
+		logfields.WithLogURLStrings(logs...), withEvaluatedField(evaluated))
+
+	return evaluated
+}
+
+func (wp *WitnessPolicy) evaluateRules(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) bool {
+	if cfg.Expression != nil {
+		evaluated := evaluateExpression(cfg, cfg.Expression, witnesses)
+
+		logger.Debug("Witness policy expression was evaluated.",
+			withPolicyConfigField(cfg), withEvaluatedField(evaluated), withWitnessProofsField(witnesses))
+
+		return evaluated
+	}
+
 	totalSystemWitnesses := 0
 	collectedSystemWitnesses := 0
 
@@ -123,7 +222,15 @@ func (wp *WitnessPolicy) Evaluate(witnesses []*proof.WitnessProof) (bool, error)
 		withPolicyConfigField(cfg), withEvaluatedField(evaluated), withBatchConditionField(batchCondition),
 		withSystemConditionField(systemCondition), withWitnessProofsField(witnesses))
 
-	return evaluated, nil
+	return evaluated
+}
+
+func (wp *WitnessPolicy) load(key interface{}) (interface{}, *time.Duration, error) {
+	if key == scopedPoliciesKey {
+		return wp.loadScopedPolicies()
+	}
+
+	return wp.loadWitnessPolicy(key)
 }
 
 func (wp *WitnessPolicy) loadWitnessPolicy(interface{}) (interface{}, *time.Duration, error) {
@@ -137,21 +244,154 @@ func (wp *WitnessPolicy) loadWitnessPolicy(interface{}) (interface{}, *time.Dura
 	return policy, &wp.cacheExpiry, nil
 }
 
+// loadScopedPolicies loads the scoped witness policies (scope -> policy). Namespace aliases are replaced
+// with the namespace.
+func (wp *WitnessPolicy) loadScopedPolicies() (interface{}, *time.Duration, error) {
+	policies := make(map[config.Scope]string)
+
+	if wp.scopedRetriever == nil {
+		return policies, &wp.cacheExpiry, nil
+	}
+
+	scopedPolicies, err := wp.scopedRetriever.GetScopedPolicies()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, p := range scopedPolicies {
+		policies[config.Scope{Namespace: wp.resolveAlias(p.Namespace), Origin: p.Origin}] = p.Policy
+	}
+
+	logger.Debug("Loaded scoped witness policies from store", logfields.WithTotal(len(policies)))
+
+	return policies, &wp.cacheExpiry, nil
+}
+
+func (wp *WitnessPolicy) resolveAlias(namespace string) string {
+	if ns, ok := wp.aliases[namespace]; ok {
+		return ns
+	}
+
+	return namespace
+}
+
 func (wp *WitnessPolicy) getWitnessPolicyConfig() (*config.WitnessPolicyConfig, error) {
+	policy, err := wp.getPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePolicy(policy)
+}
+
+// getWitnessPolicyConfigs returns the configs of the witness policies that apply to the given anchor scope.
+//
+// The policy for an anchor origin in the batch is resolved from the most specific scope to the least specific
+// scope, i.e. namespace and origin, origin only, namespace only and, finally, the global policy. All of the
+// (distinct) policies that were resolved for the batch apply.
+func (wp *WitnessPolicy) getWitnessPolicyConfigs(scope *config.AnchorScope) ([]*config.WitnessPolicyConfig, error) {
+	policies, err := wp.ResolvePolicies(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	cfgs := make([]*config.WitnessPolicyConfig, len(policies))
+
+	for i, policy := range policies {
+		cfgs[i], err = parsePolicy(policy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cfgs, nil
+}
+
+// ResolvePolicies returns the (distinct) witness policies that apply to the given anchor scope. If the scope
+// is nil or no scoped policy applies then the global witness policy is returned.
+func (wp *WitnessPolicy) ResolvePolicies(scope *config.AnchorScope) ([]string, error) {
+	if scope == nil {
+		policy, err := wp.getPolicy()
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{policy}, nil
+	}
+
+	scopedPolicies, err := wp.getScopedPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	var policies []string
+
+	resolved := make(map[string]bool)
+
+	for _, s := range scope.Scopes() {
+		s.Namespace = wp.resolveAlias(s.Namespace)
+
+		policy, ok := resolveScopedPolicy(scopedPolicies, s)
+		if !ok {
+			policy, err = wp.getPolicy()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !resolved[policy] {
+			resolved[policy] = true
+
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies, nil
+}
+
+func resolveScopedPolicy(scopedPolicies map[config.Scope]string, scope *config.Scope) (string, bool) {
+	for _, candidate := range scope.Candidates() {
+		if policy, ok := scopedPolicies[*candidate]; ok {
+			return policy, true
+		}
+	}
+
+	return "", false
+}
+
+func (wp *WitnessPolicy) getPolicy() (string, error) {
 	value, err := wp.cache.Get(WitnessPolicyKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve policy from policy cache: %w", err)
+		return "", fmt.Errorf("failed to retrieve policy from policy cache: %w", err)
 	}
 
 	if value == nil {
-		return nil, fmt.Errorf("failed to retrieve policy from policy cache (nil value)")
+		return "", fmt.Errorf("failed to retrieve policy from policy cache (nil value)")
 	}
 
 	policy, ok := value.(string)
 	if !ok {
-		return nil, fmt.Errorf("unexpected interface '%T' for witness policy value in policy cache", value)
+		return "", fmt.Errorf("unexpected interface '%T' for witness policy value in policy cache", value)
 	}
 
+	return policy, nil
+}
+
+func (wp *WitnessPolicy) getScopedPolicies() (map[config.Scope]string, error) {
+	value, err := wp.cache.Get(scopedPoliciesKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve scoped policies from policy cache: %w", err)
+	}
+
+	policies, ok := value.(map[config.Scope]string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected interface '%T' for scoped witness policies in policy cache", value)
+	}
+
+	return policies, nil
+}
+
+func parsePolicy(policy string) (*config.WitnessPolicyConfig, error) {
 	policyCfg, err := config.Parse(policy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse policy config from policy[%s]: %w", policy, err)
@@ -170,6 +410,43 @@ func evaluate(collected, total, minNumber, minPercent int) bool {
 		percentCollected >= float64(minPercent)/maxPercent
 }
 
+// distinctLogs returns the distinct logs (sorted) to which the counted proofs were added. A witness may provide
+// a proof from each of its logs (primary and mirrors) and the log of a proof is identified by its domain.
+func distinctLogs(cfg *config.WitnessPolicyConfig, witnesses []*proof.WitnessProof) []string {
+	logs := make(map[string]struct{})
+
+	for _, w := range witnesses {
+		if w.Proof == nil || !checkLog(cfg.LogRequired, w.HasLog) {
+			continue
+		}
+
+		witnessProof := &vct.Proof{}
+
+		if err := json.Unmarshal(w.Proof, witnessProof); err != nil {
+			logger.Warn("Error unmarshalling witness proof. The proof will not be counted towards the minimum "+
+				"number of logs.", logfields.WithWitnessURI(w.URI), log.WithError(err))
+
+			continue
+		}
+
+		for _, p := range witnessProof.Proofs() {
+			if domain, ok := p["domain"].(string); ok && domain != "" {
+				logs[domain] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(logs))
+
+	for l := range logs {
+		result = append(result, l)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
 func checkLog(logRequired, hasLog bool) bool {
 	if logRequired {
 		return hasLog
@@ -181,11 +458,55 @@ func checkLog(logRequired, hasLog bool) bool {
 
 // Select selects min number of witnesses required based on witness policy.
 func (wp *WitnessPolicy) Select(witnesses []*proof.Witness, exclude ...*proof.Witness) ([]*proof.Witness, error) {
-	cfg, err := wp.getWitnessPolicyConfig()
+	return wp.SelectForScope(nil, witnesses, exclude...)
+}
+
+// SelectForScope selects the min number of witnesses that are required to satisfy all of the witness policies
+// that apply to the given anchor scope. If the scope is nil then the global witness policy is used.
+func (wp *WitnessPolicy) SelectForScope(scope *config.AnchorScope, witnesses []*proof.Witness,
+	exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	cfgs, err := wp.getWitnessPolicyConfigs(scope)
 	if err != nil {
 		return nil, err
 	}
 
+	var selected []*proof.Witness
+
+	for _, cfg := range cfgs {
+		s, e := wp.selectWitnesses(cfg, witnesses, exclude...)
+		if e != nil {
+			return nil, e
+		}
+
+		selected = append(selected, difference(s, selected)...)
+	}
+
+	return selected, nil
+}
+
+func (wp *WitnessPolicy) selectWitnesses(cfg *config.WitnessPolicyConfig, witnesses []*proof.Witness,
+	exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	selected, err := wp.selectForRules(cfg, witnesses, exclude...)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MinLogs == 0 {
+		return selected, nil
+	}
+
+	return wp.selectMinLogWitnesses(cfg.MinLogs, witnesses, selected, exclude...)
+}
+
+func (wp *WitnessPolicy) selectForRules(cfg *config.WitnessPolicyConfig, witnesses []*proof.Witness,
+	exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	if cfg.Expression != nil {
+		return wp.selectForPolicyExpression(witnesses, cfg, exclude...)
+	}
+
 	selectedBatchWitnesses, selectedSystemWitnesses, err := wp.selectBatchAndSystemWitnesses(witnesses, cfg, exclude...)
 	if err != nil {
 		return nil, err
@@ -202,6 +523,82 @@ func (wp *WitnessPolicy) Select(witnesses []*proof.Witness, exclude ...*proof.Wi
 	return selectedBatchWitnesses, nil
 }
 
+// selectMinLogWitnesses ensures that at least minLogs of the selected witnesses have a log. Each witness with a log
+// adds its proof to at least one log, so this is the minimum number of witnesses that's required to satisfy
+// LogRequired(n). If the proofs of the selected witnesses turn out to share logs then the inspector selects
+// additional witnesses (see SelectLogWitnesses).
+func (wp *WitnessPolicy) selectMinLogWitnesses(minLogs int, witnesses, selected []*proof.Witness,
+	exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	var logWitnesses int
+
+	for _, w := range selected {
+		if w.HasLog {
+			logWitnesses++
+		}
+	}
+
+	if logWitnesses >= minLogs {
+		return selected, nil
+	}
+
+	excludeSelected := append(append([]*proof.Witness{}, exclude...), selected...)
+
+	additional, err := wp.SelectLogWitnesses(witnesses, minLogs-logWitnesses, excludeSelected...)
+	if err != nil {
+		return nil, fmt.Errorf("select witnesses for minimum number of logs [%d]: %w", minLogs, err)
+	}
+
+	logger.Debug("Selected additional witnesses with a log", logfields.WithMinimum(minLogs),
+		withWitnessesField(additional))
+
+	return append(selected, additional...), nil
+}
+
+// SelectLogWitnesses selects the given number of witnesses that have a log, excluding the given witnesses.
+func (wp *WitnessPolicy) SelectLogWitnesses(witnesses []*proof.Witness, n int,
+	exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	var eligible []*proof.Witness
+
+	for _, w := range witnesses {
+		if w.HasLog && !isExcluded(w, exclude...) {
+			eligible = append(eligible, w)
+		}
+	}
+
+	selected, err := wp.selector.Select(eligible, n)
+	if err != nil {
+		return nil, fmt.Errorf("select %d witnesses with a log from eligible%s: %w", n, eligible, err)
+	}
+
+	return selected, nil
+}
+
+// selectForPolicyExpression selects the min number of witnesses that are required to fulfill the policy expression.
+func (wp *WitnessPolicy) selectForPolicyExpression(witnesses []*proof.Witness,
+	cfg *config.WitnessPolicyConfig, exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
+	var eligible []*proof.Witness
+
+	for _, w := range witnesses {
+		if checkLog(cfg.LogRequired, w.HasLog) && !isExcluded(w, exclude...) {
+			eligible = append(eligible, w)
+		}
+	}
+
+	selected, err := wp.selectForExpression(cfg, cfg.Expression, witnesses, eligible, nil)
+	if err != nil {
+		return nil, fmt.Errorf("select witnesses based on witnesses%s, eligible%s, exclude%s, policy[%s]: %w",
+			witnesses, eligible, exclude, cfg, err)
+	}
+
+	logger.Debug("Selected witnesses for policy expression", logfields.WithTotal(len(selected)),
+		withPolicyConfigField(cfg), withWitnessesField(selected))
+
+	return selected, nil
+}
+
 // selects min number of batch and system witnesses that are required to fulfill witness policy.
 //
 //nolint:cyclop
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package policy
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"testing"
@@ -15,8 +16,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
 )
 
 const (
@@ -32,6 +36,17 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, wp)
 	})
 
+	t.Run("success - with selector", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+
+		s := &mockSelector{}
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry, WithSelector(s))
+		require.NoError(t, err)
+		require.NotNil(t, wp)
+		require.Equal(t, s, wp.selector)
+	})
+
 	t.Run("success - call to cache loader function", func(t *testing.T) {
 		policyStore := &mocks.PolicyStore{}
 		policyStore.GetPolicyReturns("MinPercent(30,system) AND MinPercent(70,batch)", nil)
@@ -1193,6 +1208,513 @@ func TestSelect(t *testing.T) {
 	})
 }
 
+func TestEvaluate_Expression(t *testing.T) {
+	const (
+		eu1       = "https://eu1.com/services/orb"
+		eu2       = "https://eu2.com/services/orb"
+		eu3       = "https://eu3.com/services/orb"
+		regulator = "https://regulator.com/services/orb"
+		system1   = "https://system1.com/services/orb"
+	)
+
+	const policy = "Group(eu," + eu1 + "," + eu2 + "," + eu3 + ") Group(regulator," + regulator + ") " +
+		"Weight(" + regulator + ",3) (2 of eu AND regulator) OR MinWeight(4,{" + eu1 + "," + regulator + "})"
+
+	newWitnessProof := func(uri string, witnessType proof.WitnessType, withProof bool) *proof.WitnessProof {
+		wp := &proof.WitnessProof{
+			Witness: &proof.Witness{
+				Type: witnessType,
+				URI:  vocab.NewURLProperty(testutil.MustParseURL(uri)),
+			},
+		}
+
+		if withProof {
+			wp.Proof = []byte("proof")
+		}
+
+		return wp
+	}
+
+	policyStore := &mocks.PolicyStore{}
+	policyStore.GetPolicyReturns(policy, nil)
+
+	wp, err := New(policyStore, defaultPolicyCacheExpiry)
+	require.NoError(t, err)
+
+	t.Run("satisfied - 2 of eu and regulator", func(t *testing.T) {
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			newWitnessProof(eu2, proof.WitnessTypeSystem, true),
+			newWitnessProof(eu3, proof.WitnessTypeBatch, true),
+			newWitnessProof(regulator, proof.WitnessTypeSystem, true),
+			newWitnessProof(system1, proof.WitnessTypeSystem, false),
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("satisfied - weight", func(t *testing.T) {
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			newWitnessProof(eu1, proof.WitnessTypeSystem, true),
+			newWitnessProof(eu2, proof.WitnessTypeSystem, false),
+			newWitnessProof(regulator, proof.WitnessTypeSystem, true),
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("not satisfied - no proof from regulator", func(t *testing.T) {
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			newWitnessProof(eu1, proof.WitnessTypeSystem, true),
+			newWitnessProof(eu2, proof.WitnessTypeSystem, true),
+			newWitnessProof(eu3, proof.WitnessTypeSystem, true),
+			newWitnessProof(regulator, proof.WitnessTypeSystem, false),
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("not satisfied - log required", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns("LogRequired OutOf(1,{"+eu1+"}) OR MinWeight(2,system)", nil)
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		ok, err := wp.Evaluate([]*proof.WitnessProof{
+			newWitnessProof(eu1, proof.WitnessTypeSystem, true),
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("candidate policy", func(t *testing.T) {
+		witnesses := []*proof.WitnessProof{
+			newWitnessProof(eu1, proof.WitnessTypeSystem, true),
+			newWitnessProof(eu2, proof.WitnessTypeSystem, false),
+		}
+
+		ok, err := wp.EvaluatePolicy("OutOf(1,system)", witnesses)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = wp.EvaluatePolicy("MinPercent(100,system)", witnesses)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, err = wp.EvaluatePolicy("OutOf(1,xxx)", witnesses)
+		require.Error(t, err)
+	})
+}
+
+func TestEvaluate_MinLogs(t *testing.T) {
+	w1 := testutil.MustParseURL("https://w1.com/services/orb")
+	w2 := testutil.MustParseURL("https://w2.com/services/orb")
+
+	newProof := func(witnessURI *url.URL, hasLog bool, proofJSON string) *proof.WitnessProof {
+		return &proof.WitnessProof{
+			Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witnessURI), HasLog: hasLog},
+			Proof:   []byte(proofJSON),
+		}
+	}
+
+	wp, err := New(&mocks.PolicyStore{}, defaultPolicyCacheExpiry)
+	require.NoError(t, err)
+
+	t.Run("satisfied - primary and mirror logs of a single witness", func(t *testing.T) {
+		ok, err := wp.EvaluatePolicy("OutOf(1,system) LogRequired(2)", []*proof.WitnessProof{
+			newProof(w1, true, `{"proof":{"domain":"https://vct1.com/log"},`+
+				`"additionalProofs":[{"domain":"https://vct2.com/log"}]}`),
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("satisfied - logs of multiple witnesses", func(t *testing.T) {
+		ok, err := wp.EvaluatePolicy("LogRequired(2) 2 of {"+w1.String()+","+w2.String()+"}", []*proof.WitnessProof{
+			newProof(w1, true, `{"proof":{"domain":"https://vct1.com/log"}}`),
+			newProof(w2, true, `{"proof":{"domain":"https://vct2.com/log"}}`),
+		})
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("not satisfied - same log", func(t *testing.T) {
+		ok, err := wp.EvaluatePolicy("OutOf(1,system) LogRequired(2)", []*proof.WitnessProof{
+			newProof(w1, true, `{"proof":{"domain":"https://vct1.com/log"}}`),
+			newProof(w2, true, `{"proof":{"domain":"https://vct1.com/log"}}`),
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("not satisfied - witness without log and invalid proof aren't counted", func(t *testing.T) {
+		ok, err := wp.EvaluatePolicy("OutOf(1,system) LogRequired(2)", []*proof.WitnessProof{
+			newProof(w1, true, `{"proof":{"domain":"https://vct1.com/log"}}`),
+			newProof(w2, false, `{"proof":{"domain":"https://vct2.com/log"}}`),
+			newProof(w2, true, `{`),
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("not satisfied - rules not satisfied", func(t *testing.T) {
+		ok, err := wp.EvaluatePolicy("OutOf(2,system) LogRequired(1)", []*proof.WitnessProof{
+			newProof(w1, true, `{"proof":{"domain":"https://vct1.com/log"}}`),
+			{Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(w2), HasLog: true}},
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestSelect_MinLogs(t *testing.T) {
+	newWitness := func(uri string, hasLog bool) *proof.Witness {
+		return &proof.Witness{
+			Type:   proof.WitnessTypeSystem,
+			URI:    vocab.NewURLProperty(testutil.MustParseURL(uri)),
+			HasLog: hasLog,
+		}
+	}
+
+	w1 := newWitness("https://w1.com/services/orb", true)
+	w2 := newWitness("https://w2.com/services/orb", true)
+	w3 := newWitness("https://w3.com/services/orb", true)
+	w4 := newWitness("https://w4.com/services/orb", false)
+
+	newPolicy := func(policy string) *WitnessPolicy {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns(policy, nil)
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		return wp
+	}
+
+	t.Run("witnesses with a log are added to the selection", func(t *testing.T) {
+		wp := newPolicy("OutOf(1,system) LogRequired(3)")
+
+		selected, err := wp.Select([]*proof.Witness{w1, w2, w3, w4})
+		require.NoError(t, err)
+		require.Len(t, selected, 3)
+
+		for _, w := range selected {
+			require.True(t, w.HasLog)
+		}
+	})
+
+	t.Run("selection already contains enough witnesses with a log", func(t *testing.T) {
+		wp := newPolicy("OutOf(2,system) LogRequired(1)")
+
+		selected, err := wp.Select([]*proof.Witness{w1, w2, w3})
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+	})
+
+	t.Run("excluded witnesses aren't selected", func(t *testing.T) {
+		wp := newPolicy("OutOf(1,system) LogRequired(2)")
+
+		selected, err := wp.Select([]*proof.Witness{w1, w2, w3, w4}, w1)
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+		require.NotContains(t, selected, w1)
+	})
+
+	t.Run("not enough witnesses with a log", func(t *testing.T) {
+		wp := newPolicy("OutOf(1,system) LogRequired(3)")
+
+		_, err := wp.Select([]*proof.Witness{w1, w2, w4})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, orberrors.ErrWitnessesNotFound))
+		require.Contains(t, err.Error(), "minimum number of logs [3]")
+	})
+
+	t.Run("select log witnesses", func(t *testing.T) {
+		wp := newPolicy("OutOf(1,system)")
+
+		selected, err := wp.SelectLogWitnesses([]*proof.Witness{w1, w2, w3, w4}, 1, w1, w2)
+		require.NoError(t, err)
+		require.Equal(t, []*proof.Witness{w3}, selected)
+	})
+}
+
+func TestSelect_Expression(t *testing.T) {
+	const (
+		eu1       = "https://eu1.com/services/orb"
+		eu2       = "https://eu2.com/services/orb"
+		eu3       = "https://eu3.com/services/orb"
+		regulator = "https://regulator.com/services/orb"
+		system1   = "https://system1.com/services/orb"
+	)
+
+	newWitness := func(uri string, witnessType proof.WitnessType) *proof.Witness {
+		return &proof.Witness{
+			Type: witnessType,
+			URI:  vocab.NewURLProperty(testutil.MustParseURL(uri)),
+		}
+	}
+
+	witnesses := []*proof.Witness{
+		newWitness(eu1, proof.WitnessTypeSystem),
+		newWitness(eu2, proof.WitnessTypeSystem),
+		newWitness(eu3, proof.WitnessTypeBatch),
+		newWitness(regulator, proof.WitnessTypeSystem),
+		newWitness(system1, proof.WitnessTypeSystem),
+	}
+
+	newPolicy := func(policy string) *WitnessPolicy {
+		policyStore := &mocks.PolicyStore{}
+		policyStore.GetPolicyReturns(policy, nil)
+
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		return wp
+	}
+
+	t.Run("AND", func(t *testing.T) {
+		wp := newPolicy("Group(eu," + eu1 + "," + eu2 + "," + eu3 + ") Group(regulator," + regulator + ") " +
+			"2 of eu AND regulator")
+
+		selected, err := wp.Select(witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 3)
+
+		uris := make(map[string]bool)
+		for _, w := range selected {
+			uris[w.URI.String()] = true
+		}
+
+		require.True(t, uris[regulator])
+		require.False(t, uris[system1])
+	})
+
+	t.Run("AND - overlapping groups", func(t *testing.T) {
+		wp := newPolicy("OutOf(1,{" + eu1 + "}) AND OutOf(1,{" + eu1 + "," + eu2 + "})")
+
+		selected, err := wp.Select(witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 1)
+		require.Equal(t, eu1, selected[0].URI.String())
+	})
+
+	t.Run("OR - fewest witnesses", func(t *testing.T) {
+		wp := newPolicy("OutOf(3,system) OR OutOf(1,{" + regulator + "})")
+
+		selected, err := wp.Select(witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 1)
+		require.Equal(t, regulator, selected[0].URI.String())
+	})
+
+	t.Run("OR - excluded witness", func(t *testing.T) {
+		wp := newPolicy("OutOf(3,system) OR OutOf(1,{" + regulator + "})")
+
+		selected, err := wp.Select(witnesses, newWitness(regulator, proof.WitnessTypeSystem))
+		require.NoError(t, err)
+		require.Len(t, selected, 3)
+	})
+
+	t.Run("MinPercent", func(t *testing.T) {
+		wp := newPolicy("MinPercent(50,system) AND batch")
+
+		selected, err := wp.Select(witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 3)
+	})
+
+	t.Run("MinWeight", func(t *testing.T) {
+		wp := newPolicy("Weight(" + regulator + ",3) MinWeight(4,system)")
+
+		selected, err := wp.Select(witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+		require.Equal(t, regulator, selected[0].URI.String())
+	})
+
+	t.Run("error - insufficient witnesses", func(t *testing.T) {
+		wp := newPolicy("OutOf(2,{" + eu1 + ",https://unknown.com/services/orb})")
+
+		_, err := wp.Select(witnesses)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, orberrors.ErrWitnessesNotFound))
+
+		wp = newPolicy("MinWeight(10,system) OR OutOf(5,system)")
+
+		_, err = wp.Select(witnesses)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, orberrors.ErrWitnessesNotFound))
+	})
+}
+
+func TestWitnessPolicy_Scoped(t *testing.T) {
+	const (
+		origin1 = "https://origin1.com"
+		origin2 = "https://origin2.com"
+		origin3 = "https://origin3.com"
+		origin4 = "https://origin4.com"
+
+		globalPolicy          = "OutOf(1,system)"
+		namespacePolicy       = "OutOf(2,system)"
+		originPolicy          = "MinPercent(100,system)"
+		namespaceOriginPolicy = "OutOf(3,system)"
+		aliasPolicy           = "MinPercent(50,system)"
+	)
+
+	policyStore := &mocks.PolicyStore{}
+	policyStore.GetPolicyReturns(globalPolicy, nil)
+
+	scopedPolicies := &mockScopedPolicyRetriever{
+		policies: []*config.ScopedPolicy{
+			{Scope: config.Scope{Namespace: "did:orb"}, Policy: namespacePolicy},
+			{Scope: config.Scope{Origin: origin1}, Policy: originPolicy},
+			{Scope: config.Scope{Namespace: "did:orb", Origin: origin2}, Policy: namespaceOriginPolicy},
+			{Scope: config.Scope{Namespace: "did:alias", Origin: origin4}, Policy: aliasPolicy},
+		},
+	}
+
+	wp, err := New(policyStore, defaultPolicyCacheExpiry, WithScopedPolicies(scopedPolicies),
+		WithNamespaceAliases("did:orb", "did:alias"))
+	require.NoError(t, err)
+
+	t.Run("resolve policies", func(t *testing.T) {
+		for _, tc := range []struct {
+			scope    *config.AnchorScope
+			expected []string
+		}{
+			{scope: nil, expected: []string{globalPolicy}},
+			{scope: &config.AnchorScope{Namespace: "did:orb"}, expected: []string{namespacePolicy}},
+			{scope: &config.AnchorScope{Namespace: "did:orb", Origins: []string{origin1}}, expected: []string{originPolicy}},
+			{
+				scope:    &config.AnchorScope{Namespace: "did:orb", Origins: []string{origin2}},
+				expected: []string{namespaceOriginPolicy},
+			},
+			{scope: &config.AnchorScope{Namespace: "did:orb", Origins: []string{origin3}}, expected: []string{namespacePolicy}},
+			{scope: &config.AnchorScope{Namespace: "did:other", Origins: []string{origin3}}, expected: []string{globalPolicy}},
+			{scope: &config.AnchorScope{Namespace: "did:alias", Origins: []string{origin4}}, expected: []string{aliasPolicy}},
+			{scope: &config.AnchorScope{Namespace: "did:orb", Origins: []string{origin4}}, expected: []string{aliasPolicy}},
+			{
+				scope:    &config.AnchorScope{Namespace: "did:orb", Origins: []string{origin1, origin3, origin1}},
+				expected: []string{originPolicy, namespacePolicy},
+			},
+		} {
+			policies, err := wp.ResolvePolicies(tc.scope)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, policies)
+		}
+	})
+
+	newWitnessProof := func(uri string, withProof bool) *proof.WitnessProof {
+		wp := &proof.WitnessProof{
+			Witness: &proof.Witness{
+				Type: proof.WitnessTypeSystem,
+				URI:  vocab.NewURLProperty(testutil.MustParseURL(uri)),
+			},
+		}
+
+		if withProof {
+			wp.Proof = []byte("proof")
+		}
+
+		return wp
+	}
+
+	// The batch contains operations from origin1 (100% of system witnesses) and origin3 (2 system witnesses).
+	scope := &config.AnchorScope{Namespace: "did:orb", Origins: []string{origin1, origin3}}
+
+	t.Run("evaluate - all policies must be satisfied", func(t *testing.T) {
+		witnesses := []*proof.WitnessProof{
+			newWitnessProof("https://w1.com/services/orb", true),
+			newWitnessProof("https://w2.com/services/orb", true),
+			newWitnessProof("https://w3.com/services/orb", false),
+		}
+
+		ok, err := wp.EvaluateForScope(scope, witnesses)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		// The global policy is satisfied.
+		ok, err = wp.Evaluate(witnesses)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		witnesses[2] = newWitnessProof("https://w3.com/services/orb", true)
+
+		ok, err = wp.EvaluateForScope(scope, witnesses)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("select - witnesses are selected for all policies", func(t *testing.T) {
+		witnesses := []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(testutil.MustParseURL("https://w1.com/services/orb"))},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(testutil.MustParseURL("https://w2.com/services/orb"))},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(testutil.MustParseURL("https://w3.com/services/orb"))},
+		}
+
+		selected, err := wp.SelectForScope(scope, witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 3)
+
+		selected, err = wp.SelectForScope(&config.AnchorScope{Namespace: "did:orb"}, witnesses)
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+	})
+
+	t.Run("explain - multiple policies", func(t *testing.T) {
+		evaluation, err := wp.ExplainForScope(scope, []*proof.WitnessProof{
+			newWitnessProof("https://w1.com/services/orb", true),
+			newWitnessProof("https://w2.com/services/orb", true),
+			newWitnessProof("https://w3.com/services/orb", false),
+		})
+		require.NoError(t, err)
+		require.False(t, evaluation.Satisfied)
+		require.Equal(t, config.AND, evaluation.Condition.Condition)
+		require.Len(t, evaluation.Condition.Operands, 2)
+		require.False(t, evaluation.Condition.Operands[0].Satisfied)
+		require.True(t, evaluation.Condition.Operands[1].Satisfied)
+	})
+
+	t.Run("no scoped policy retriever -> global policy", func(t *testing.T) {
+		wp, err := New(policyStore, defaultPolicyCacheExpiry)
+		require.NoError(t, err)
+
+		policies, err := wp.ResolvePolicies(scope)
+		require.NoError(t, err)
+		require.Equal(t, []string{globalPolicy}, policies)
+	})
+
+	t.Run("error - scoped policy retriever", func(t *testing.T) {
+		wp, err := New(policyStore, defaultPolicyCacheExpiry,
+			WithScopedPolicies(&mockScopedPolicyRetriever{err: errors.New("injected retriever error")}))
+		require.NoError(t, err)
+
+		_, err = wp.EvaluateForScope(scope, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected retriever error")
+
+		_, err = wp.SelectForScope(scope, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected retriever error")
+
+		_, err = wp.ExplainForScope(scope, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected retriever error")
+	})
+
+	t.Run("error - invalid scoped policy", func(t *testing.T) {
+		wp, err := New(policyStore, defaultPolicyCacheExpiry,
+			WithScopedPolicies(&mockScopedPolicyRetriever{
+				policies: []*config.ScopedPolicy{{Scope: config.Scope{Origin: origin1}, Policy: "OutOf(x,system)"}},
+			}))
+		require.NoError(t, err)
+
+		_, err = wp.EvaluateForScope(scope, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse policy config")
+	})
+}
+
 func TestIntersection(t *testing.T) {
 	witnessURL, err := url.Parse("https://witness.com/service")
 	require.NoError(t, err)
@@ -1334,3 +1856,28 @@ func (mc *mockCache) SetWithExpire(interface{}, interface{}, time.Duration) erro
 
 	return nil
 }
+
+type mockScopedPolicyRetriever struct {
+	policies []*config.ScopedPolicy
+	err      error
+}
+
+func (m *mockScopedPolicyRetriever) GetScopedPolicies() ([]*config.ScopedPolicy, error) {
+	return m.policies, m.err
+}
+
+type mockSelector struct {
+	err error
+}
+
+func (ms *mockSelector) Select(witnesses []*proof.Witness, n int) ([]*proof.Witness, error) {
+	if ms.err != nil {
+		return nil, ms.err
+	}
+
+	if n > len(witnesses) {
+		return nil, orberrors.ErrWitnessesNotFound
+	}
+
+	return witnesses[:n], nil
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resthandler
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 
@@ -76,7 +77,8 @@ func (pc *PolicyConfigurator) handle(w http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		logger.Error("Invalid witness policy", log.WithError(err), logfields.WithWitnessPolicy(policyStr))
 
-		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+		// Include the reason in the response since the policy may be complex (e.g. groups and nested expressions).
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("%s Invalid witness policy: %s", badRequestResponse, err)))
 
 		return
 	}
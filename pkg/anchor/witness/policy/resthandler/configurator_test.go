@@ -56,6 +56,27 @@ func TestHandler(t *testing.T) {
 		require.NoError(t, result.Body.Close())
 	})
 
+	t.Run("success - named groups and nested expressions", func(t *testing.T) {
+		policyStore := &mocks.PolicyStore{}
+
+		policyConfigurator := New(policyStore)
+		require.NotNil(t, policyConfigurator)
+
+		const policy = "Group(regulator,https://regulator.com/services/orb) " +
+			"2 of {https://w1.com/services/orb,https://w2.com/services/orb,https://w3.com/services/orb} AND regulator"
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(policy))
+
+		policyConfigurator.handle(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+		require.Equal(t, 1, policyStore.PutPolicyCallCount())
+		require.Equal(t, policy, policyStore.PutPolicyArgsForCall(0))
+	})
+
 	t.Run("error - reader error", func(t *testing.T) {
 		policyStore := &mocks.PolicyStore{}
 
@@ -95,7 +116,7 @@ func TestHandler(t *testing.T) {
 
 		respBytes, err := io.ReadAll(result.Body)
 		require.NoError(t, err)
-		require.Equal(t, []byte(badRequestResponse), respBytes)
+		require.Equal(t, badRequestResponse+" Invalid witness policy: rule not supported: InvalidPolicy", string(respBytes))
 		require.NoError(t, result.Body.Close())
 	})
 
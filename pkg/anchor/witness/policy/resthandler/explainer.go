@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/inspector"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/store/anchorstatus"
+)
+
+const (
+	explainEndpoint = "/policy/explain"
+
+	anchorParam = "anchor"
+)
+
+type anchorStatusStore interface {
+	GetStatusInfo(anchorID string) (*anchorstatus.StatusInfo, error)
+}
+
+type anchorExplainer interface {
+	Explain(anchorID string) (*inspector.Explanation, error)
+}
+
+// Explanation explains why an anchor is (or isn't) pending the witness policy.
+type Explanation struct {
+	*inspector.Explanation
+
+	Status proof.AnchorIndexStatus `json:"status"`
+	// ExpiryTime is the time after which an in-process anchor will no longer be monitored.
+	ExpiryTime *time.Time `json:"expiryTime,omitempty"`
+}
+
+// PolicyExplainer explains why an anchor is still pending the witness policy. The response includes the
+// witnesses of the anchor, the proofs that were received, the result of evaluating each condition of the
+// witness policy and the next action of the policy inspector.
+type PolicyExplainer struct {
+	statusStore anchorStatusStore
+	explainer   anchorExplainer
+	marshal     func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the policy explainer.
+func (pe *PolicyExplainer) Path() string {
+	return explainEndpoint
+}
+
+// Method returns the HTTP REST method for the policy explainer.
+func (pe *PolicyExplainer) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the PolicyExplainer service.
+func (pe *PolicyExplainer) Handler() common.HTTPRequestHandler {
+	return pe.handle
+}
+
+// NewExplainer returns a new PolicyExplainer.
+func NewExplainer(statusStore anchorStatusStore, explainer anchorExplainer) *PolicyExplainer {
+	return &PolicyExplainer{
+		statusStore: statusStore,
+		explainer:   explainer,
+		marshal:     json.Marshal,
+	}
+}
+
+func (pe *PolicyExplainer) handle(w http.ResponseWriter, req *http.Request) {
+	anchorID, err := getAnchorID(req.URL.Query().Get(anchorParam))
+	if err != nil {
+		logger.Debug("Invalid explain request", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	explanation, err := pe.explain(anchorID)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			logger.Debug("Anchor not found", logfields.WithAnchorURIString(anchorID), log.WithError(err))
+
+			writeResponse(w, http.StatusNotFound, nil)
+
+			return
+		}
+
+		logger.Error("Error explaining witness policy for anchor", logfields.WithAnchorURIString(anchorID),
+			log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := pe.marshal(explanation)
+	if err != nil {
+		logger.Error("Error marshalling explanation", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (pe *PolicyExplainer) explain(anchorID string) (*Explanation, error) {
+	statusInfo, err := pe.statusStore.GetStatusInfo(anchorID)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation, err := pe.explainer.Explain(anchorID)
+	if err != nil {
+		if statusInfo.Status != proof.AnchorIndexStatusCompleted {
+			return nil, err
+		}
+
+		// The witnesses of an anchor may be deleted after the anchor has completed processing.
+		logger.Debug("Unable to explain witness policy for completed anchor", logfields.WithAnchorURIString(anchorID),
+			log.WithError(err))
+
+		explanation = &inspector.Explanation{AnchorID: anchorID}
+	}
+
+	switch {
+	case statusInfo.Status == proof.AnchorIndexStatusCompleted:
+		explanation.NextAction = &inspector.Action{
+			Type:        inspector.ActionNone,
+			Description: "The anchor has completed processing.",
+		}
+	case explanation.NextAction != nil:
+		nextCheckTime := statusInfo.NextCheckTime
+		explanation.NextAction.Time = &nextCheckTime
+	}
+
+	e := &Explanation{
+		Explanation: explanation,
+		Status:      statusInfo.Status,
+	}
+
+	if statusInfo.Status != proof.AnchorIndexStatusCompleted {
+		expiryTime := statusInfo.ExpiryTime
+		e.ExpiryTime = &expiryTime
+	}
+
+	return e, nil
+}
+
+// getAnchorID returns the anchor ID (i.e. the hashlink of the anchor without metadata) from the given
+// hashlink or resource hash.
+func getAnchorID(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return "", errors.New("anchor parameter is required")
+	}
+
+	if !strings.HasPrefix(value, hashlink.HLPrefix) {
+		return hashlink.GetHashLinkFromResourceHash(value), nil
+	}
+
+	resourceHash, err := hashlink.GetResourceHashFromHashLink(value)
+	if err != nil {
+		return "", err
+	}
+
+	if resourceHash == "" {
+		return "", errors.New("invalid hashlink")
+	}
+
+	return hashlink.GetHashLinkFromResourceHash(resourceHash), nil
+}
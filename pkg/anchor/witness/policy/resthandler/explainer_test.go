@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/inspector"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/anchorstatus"
+)
+
+const (
+	testResourceHash = "uEiD1W_21fZwLNqV-T10km8jMvsT_lQZmmR4vIHQD7Wu73g"
+	testAnchorID     = "hl:" + testResourceHash
+)
+
+func TestNewExplainer(t *testing.T) {
+	policyExplainer := NewExplainer(&mockAnchorStatusStore{}, &mockAnchorExplainer{})
+	require.NotNil(t, policyExplainer)
+	require.Equal(t, explainEndpoint, policyExplainer.Path())
+	require.Equal(t, http.MethodGet, policyExplainer.Method())
+	require.NotNil(t, policyExplainer.Handler())
+}
+
+func TestPolicyExplainer_Handler(t *testing.T) {
+	nextCheckTime := time.Now().Add(time.Minute)
+
+	inProcessStatus := &mockAnchorStatusStore{
+		info: &anchorstatus.StatusInfo{
+			Status:        proof.AnchorIndexStatusInProcess,
+			NextCheckTime: nextCheckTime,
+			ExpiryTime:    nextCheckTime.Add(time.Hour),
+		},
+	}
+
+	explainer := &mockAnchorExplainer{
+		explanation: &inspector.Explanation{
+			AnchorID:   testAnchorID,
+			Policy:     &policy.Evaluation{Policy: "OutOf(2,system)"},
+			NextAction: &inspector.Action{Type: inspector.ActionReOffer},
+		},
+	}
+
+	t.Run("success - in process", func(t *testing.T) {
+		for _, anchor := range []string{testAnchorID, testResourceHash, testAnchorID + ":uoQ-BeEJpcGZz"} {
+			explanation := explain(t, NewExplainer(inProcessStatus, explainer), anchor, http.StatusOK)
+			require.Equal(t, testAnchorID, explainer.anchorID)
+			require.Equal(t, proof.AnchorIndexStatusInProcess, explanation.Status)
+			require.NotNil(t, explanation.ExpiryTime)
+			require.Equal(t, "OutOf(2,system)", explanation.Policy.Policy)
+			require.Equal(t, inspector.ActionReOffer, explanation.NextAction.Type)
+			require.NotNil(t, explanation.NextAction.Time)
+			require.True(t, nextCheckTime.Equal(*explanation.NextAction.Time))
+		}
+	})
+
+	t.Run("success - completed", func(t *testing.T) {
+		statusStore := &mockAnchorStatusStore{
+			info: &anchorstatus.StatusInfo{Status: proof.AnchorIndexStatusCompleted},
+		}
+
+		explanation := explain(t, NewExplainer(statusStore, &mockAnchorExplainer{err: errors.New("not found")}),
+			testAnchorID, http.StatusOK)
+		require.Equal(t, proof.AnchorIndexStatusCompleted, explanation.Status)
+		require.Nil(t, explanation.ExpiryTime)
+		require.Equal(t, inspector.ActionNone, explanation.NextAction.Type)
+	})
+
+	t.Run("missing anchor", func(t *testing.T) {
+		explain(t, NewExplainer(inProcessStatus, explainer), "", http.StatusBadRequest)
+		explain(t, NewExplainer(inProcessStatus, explainer), "hl:", http.StatusBadRequest)
+	})
+
+	t.Run("anchor not found", func(t *testing.T) {
+		statusStore := &mockAnchorStatusStore{err: orberrors.ErrContentNotFound}
+
+		explain(t, NewExplainer(statusStore, explainer), testAnchorID, http.StatusNotFound)
+	})
+
+	t.Run("status store error", func(t *testing.T) {
+		statusStore := &mockAnchorStatusStore{err: errors.New("injected status store error")}
+
+		explain(t, NewExplainer(statusStore, explainer), testAnchorID, http.StatusInternalServerError)
+	})
+
+	t.Run("explainer error", func(t *testing.T) {
+		explain(t, NewExplainer(inProcessStatus, &mockAnchorExplainer{err: errors.New("injected explainer error")}),
+			testAnchorID, http.StatusInternalServerError)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		policyExplainer := NewExplainer(inProcessStatus, explainer)
+		policyExplainer.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		explain(t, policyExplainer, testAnchorID, http.StatusInternalServerError)
+	})
+}
+
+func explain(t *testing.T, policyExplainer *PolicyExplainer, anchor string, expectedStatus int) *Explanation {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, explainEndpoint+"?anchor="+url.QueryEscape(anchor), http.NoBody)
+
+	policyExplainer.handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, result.Body.Close())
+	require.NoError(t, err)
+
+	if expectedStatus != http.StatusOK {
+		return nil
+	}
+
+	explanation := &Explanation{}
+	require.NoError(t, json.Unmarshal(respBytes, explanation))
+
+	return explanation
+}
+
+type mockAnchorStatusStore struct {
+	info *anchorstatus.StatusInfo
+	err  error
+}
+
+func (m *mockAnchorStatusStore) GetStatusInfo(string) (*anchorstatus.StatusInfo, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	info := *m.info
+
+	return &info, nil
+}
+
+type mockAnchorExplainer struct {
+	explanation *inspector.Explanation
+	err         error
+	anchorID    string
+}
+
+func (m *mockAnchorExplainer) Explain(anchorID string) (*inspector.Explanation, error) {
+	m.anchorID = anchorID
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	explanation := *m.explanation
+	action := *m.explanation.NextAction
+	explanation.NextAction = &action
+
+	return &explanation, nil
+}
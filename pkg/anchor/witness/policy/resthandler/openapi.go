@@ -6,6 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 
 package resthandler
 
+import (
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/simulator"
+	"github.com/trustbloc/orb/pkg/anchor/witness/rewitness"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
 // swagger:parameters policyGetReq
 type policyGetReq struct { //nolint: unused
 }
@@ -38,10 +45,182 @@ type policyPostResp struct { //nolint: unused
 
 // postPolicy swagger:route POST /policy policy policyPostReq
 //
-// Retrieves the current witness policy.
+// Updates the witness policy. The policy may define named witness groups (Group), witness weights (Weight) and nested AND/OR expressions of OutOf, MinPercent and MinWeight rules, for example: Group(regulator,https://regulator.com/services/orb) 2 of {https://w1.com/services/orb,https://w2.com/services/orb,https://w3.com/services/orb} AND regulator
 //
 // Responses:
 //
 //	200: policyPostResp
+//
+//nolint:lll
 func postPolicy() { //nolint: unused
 }
+
+// swagger:parameters witnessStatsGetReq
+type witnessStatsGetReq struct { //nolint: unused
+	// The period (e.g. 1h, 24h) for which the stats are aggregated. The default is 24h and the maximum is 168h.
+	// in: query
+	Window string `json:"window"`
+
+	// The URI of the witness. If not specified then the stats of all witnesses are returned.
+	// in: query
+	Witness string `json:"witness"`
+}
+
+// swagger:response witnessStatsGetResp
+type witnessStatsGetResp struct { //nolint: unused
+	Body []witness.StatsSummary
+}
+
+// getWitnessStats swagger:route GET /witness-stats policy witnessStatsGetReq
+//
+// Retrieves the proof statistics (requests, proofs, timeouts, re-offers and p50/p95 proof turnaround times) of the witnesses.
+//
+// Responses:
+//
+//	200: witnessStatsGetResp
+//
+//nolint:lll
+func getWitnessStats() { //nolint: unused
+}
+
+// swagger:parameters policyExplainGetReq
+type policyExplainGetReq struct { //nolint: unused
+	// The ID (hashlink) or resource hash of the anchor.
+	// in: query
+	// required: true
+	Anchor string `json:"anchor"`
+}
+
+// swagger:response policyExplainGetResp
+type policyExplainGetResp struct { //nolint: unused
+	Body Explanation
+}
+
+// explainPolicy swagger:route GET /policy/explain policy policyExplainGetReq
+//
+// Explains why an anchor is still pending the witness policy. The response includes the witnesses of the anchor, the proofs that were received, the result of evaluating each condition of the witness policy and the next action of the policy inspector.
+//
+// Responses:
+//
+//	200: policyExplainGetResp
+//
+//nolint:lll
+func explainPolicy() { //nolint: unused
+}
+
+// swagger:parameters witnessRevocationPostReq
+type witnessRevocationPostReq struct { //nolint: unused
+	// in: body
+	Body RevocationRequest
+}
+
+// swagger:response witnessRevocationPostResp
+type witnessRevocationPostResp struct { //nolint: unused
+	Body rewitness.Result
+}
+
+// revokeWitness swagger:route POST /witness-revocations policy witnessRevocationPostReq
+//
+// Revokes the proofs of a witness that were created on or after the given time (for example, if the witness' key was compromised). The proofs are removed from pending anchors and anchors that were witnessed using a revoked proof are offered to other witnesses in order to obtain replacement proofs. The updated anchors are then published.
+//
+// Responses:
+//
+//	200: witnessRevocationPostResp
+//
+//nolint:lll
+func revokeWitness() { //nolint: unused
+}
+
+// swagger:parameters scopedPolicyPostReq
+type scopedPolicyPostReq struct { //nolint: unused
+	// in: body
+	Body config.ScopedPolicy
+}
+
+// swagger:response scopedPolicyPostResp
+type scopedPolicyPostResp struct { //nolint: unused
+}
+
+// postScopedPolicy swagger:route POST /policy/scoped policy scopedPolicyPostReq
+//
+// Stores a witness policy for a DID namespace and/or an anchor origin. When an anchor batch is witnessed, the policy for the namespace and origin of each operation takes precedence over the policy for the origin, which takes precedence over the policy for the namespace. If no scoped policy matches then the global witness policy applies. If the operations in a batch resolve to different policies then all of the policies must be satisfied.
+//
+// Responses:
+//
+//	200: scopedPolicyPostResp
+//
+//nolint:lll
+func postScopedPolicy() { //nolint: unused
+}
+
+// swagger:parameters scopedPolicyGetReq
+type scopedPolicyGetReq struct { //nolint: unused
+	// in: query
+	Namespace string `json:"namespace"`
+
+	// in: query
+	Origin string `json:"origin"`
+}
+
+// swagger:response scopedPolicyGetResp
+type scopedPolicyGetResp struct { //nolint: unused
+	Body []*config.ScopedPolicy
+}
+
+// getScopedPolicies swagger:route GET /policy/scoped policy scopedPolicyGetReq
+//
+// Retrieves the scoped witness policies. If namespace and/or origin is specified then only the policy for that exact scope is returned.
+//
+// Responses:
+//
+//	200: scopedPolicyGetResp
+//
+//nolint:lll
+func getScopedPolicies() { //nolint: unused
+}
+
+// swagger:parameters scopedPolicyDeleteReq
+type scopedPolicyDeleteReq struct { //nolint: unused
+	// in: query
+	Namespace string `json:"namespace"`
+
+	// in: query
+	Origin string `json:"origin"`
+}
+
+// swagger:response scopedPolicyDeleteResp
+type scopedPolicyDeleteResp struct { //nolint: unused
+}
+
+// deleteScopedPolicy swagger:route DELETE /policy/scoped policy scopedPolicyDeleteReq
+//
+// Deletes the witness policy for the given namespace and/or origin.
+//
+// Responses:
+//
+//	200: scopedPolicyDeleteResp
+func deleteScopedPolicy() { //nolint: unused
+}
+
+// swagger:parameters policySimulatePostReq
+type policySimulatePostReq struct { //nolint: unused
+	// in: body
+	Body SimulationRequest
+}
+
+// swagger:response policySimulatePostResp
+type policySimulatePostResp struct { //nolint: unused
+	Body simulator.Report
+}
+
+// simulatePolicy swagger:route POST /policy/simulate policy policySimulatePostReq
+//
+// Evaluates a candidate witness policy against the most recent anchors (including pending anchors) without changing the current witness policy. The proofs of each anchor are replayed in the order in which they were received. The response includes the number of anchors that would have completed or stalled, how long the completed anchors would have taken and the result for each anchor.
+//
+// Responses:
+//
+//	200: policySimulatePostResp
+//
+//nolint:lll
+func simulatePolicy() { //nolint: unused
+}
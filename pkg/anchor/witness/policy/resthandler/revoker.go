@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/rewitness"
+)
+
+const revocationsEndpoint = "/witness-revocations"
+
+type witnessRevoker interface {
+	Revoke(ctx context.Context, witness *url.URL, since time.Time, reason string) (*rewitness.Result, error)
+}
+
+// RevocationRequest contains the witness whose proofs are to be revoked.
+type RevocationRequest struct {
+	Witness string `json:"witness"`
+	// Since is the time from which the proofs of the witness are untrusted. If not specified then
+	// all proofs of the witness are revoked.
+	Since  *time.Time `json:"since,omitempty"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// WitnessRevoker revokes the proofs of a witness (for example, if the witness' key was compromised) and
+// re-witnesses the anchors that relied on those proofs.
+type WitnessRevoker struct {
+	revoker witnessRevoker
+	marshal func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the witness revoker.
+func (wr *WitnessRevoker) Path() string {
+	return revocationsEndpoint
+}
+
+// Method returns the HTTP REST method for the witness revoker.
+func (wr *WitnessRevoker) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the WitnessRevoker service.
+func (wr *WitnessRevoker) Handler() common.HTTPRequestHandler {
+	return wr.handle
+}
+
+// NewRevoker returns a new WitnessRevoker.
+func NewRevoker(revoker witnessRevoker) *WitnessRevoker {
+	return &WitnessRevoker{
+		revoker: revoker,
+		marshal: json.Marshal,
+	}
+}
+
+func (wr *WitnessRevoker) handle(w http.ResponseWriter, req *http.Request) {
+	witnessURI, since, reason, err := getRevocationRequest(req)
+	if err != nil {
+		logger.Debug("Invalid witness revocation request", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("%s %s", badRequestResponse, err)))
+
+		return
+	}
+
+	result, err := wr.revoker.Revoke(req.Context(), witnessURI, since, reason)
+	if err != nil {
+		logger.Error("Error revoking witness", logfields.WithWitnessURI(witnessURI), log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := wr.marshal(result)
+	if err != nil {
+		logger.Error("Error marshalling revocation result", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func getRevocationRequest(req *http.Request) (*url.URL, time.Time, string, error) {
+	reqBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("read request body: %w", err)
+	}
+
+	request := &RevocationRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("invalid request: %w", err)
+	}
+
+	if request.Witness == "" {
+		return nil, time.Time{}, "", errors.New("witness is required")
+	}
+
+	witnessURI, err := url.Parse(request.Witness)
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("invalid witness [%s]: %w", request.Witness, err)
+	}
+
+	var since time.Time
+
+	if request.Since != nil {
+		since = *request.Since
+	}
+
+	return witnessURI, since, request.Reason, nil
+}
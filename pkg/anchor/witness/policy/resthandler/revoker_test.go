@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/rewitness"
+)
+
+func TestNewRevoker(t *testing.T) {
+	revoker := NewRevoker(&mockWitnessRevoker{})
+	require.NotNil(t, revoker)
+	require.Equal(t, revocationsEndpoint, revoker.Path())
+	require.Equal(t, http.MethodPost, revoker.Method())
+	require.NotNil(t, revoker.Handler())
+}
+
+func TestWitnessRevoker_Handler(t *testing.T) {
+	const witnessURI = "https://w1.com/services/orb"
+
+	result := &rewitness.Result{
+		Witness: witnessURI,
+		Anchors: []*rewitness.AnchorResult{{AnchorID: testAnchorID, Status: rewitness.StatusReOffered}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		revoker := &mockWitnessRevoker{result: result}
+
+		since := time.Date(2022, 3, 15, 0, 0, 0, 0, time.UTC)
+
+		respBytes := revoke(t, NewRevoker(revoker),
+			`{"witness":"`+witnessURI+`","since":"2022-03-15T00:00:00Z","reason":"key compromised"}`, http.StatusOK)
+
+		require.Equal(t, witnessURI, revoker.witness.String())
+		require.True(t, since.Equal(revoker.since))
+		require.Equal(t, "key compromised", revoker.reason)
+
+		r := &rewitness.Result{}
+		require.NoError(t, json.Unmarshal(respBytes, r))
+		require.Len(t, r.Anchors, 1)
+		require.Equal(t, rewitness.StatusReOffered, r.Anchors[0].Status)
+	})
+
+	t.Run("success - all proofs", func(t *testing.T) {
+		revoker := &mockWitnessRevoker{result: result}
+
+		revoke(t, NewRevoker(revoker), `{"witness":"`+witnessURI+`"}`, http.StatusOK)
+		require.True(t, revoker.since.IsZero())
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		revoker := NewRevoker(&mockWitnessRevoker{result: result})
+
+		revoke(t, revoker, `{`, http.StatusBadRequest)
+		revoke(t, revoker, `{}`, http.StatusBadRequest)
+		revoke(t, revoker, `{"witness":":invalid"}`, http.StatusBadRequest)
+		revoke(t, revoker, `{"witness":"`+witnessURI+`","since":"yesterday"}`, http.StatusBadRequest)
+	})
+
+	t.Run("revoke error", func(t *testing.T) {
+		revoke(t, NewRevoker(&mockWitnessRevoker{err: errors.New("injected revoke error")}),
+			`{"witness":"`+witnessURI+`"}`, http.StatusInternalServerError)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		revoker := NewRevoker(&mockWitnessRevoker{result: result})
+		revoker.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		revoke(t, revoker, `{"witness":"`+witnessURI+`"}`, http.StatusInternalServerError)
+	})
+}
+
+func revoke(t *testing.T, revoker *WitnessRevoker, request string, expectedStatus int) []byte {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, revocationsEndpoint, bytes.NewBufferString(request))
+
+	revoker.handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, result.Body.Close())
+	require.NoError(t, err)
+
+	return respBytes
+}
+
+type mockWitnessRevoker struct {
+	result *rewitness.Result
+	err    error
+
+	witness *url.URL
+	since   time.Time
+	reason  string
+}
+
+func (m *mockWitnessRevoker) Revoke(_ context.Context, witness *url.URL, since time.Time,
+	reason string,
+) (*rewitness.Result, error) {
+	m.witness = witness
+	m.since = since
+	m.reason = reason
+
+	return m.result, m.err
+}
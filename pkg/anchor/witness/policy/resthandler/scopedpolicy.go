@@ -0,0 +1,261 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	scopedEndpoint = "/policy/scoped"
+
+	namespaceParam = "namespace"
+	originParam    = "origin"
+)
+
+type scopedPolicyStore interface {
+	PutScopedPolicy(scope *config.Scope, policyStr string) error
+	GetScopedPolicy(scope *config.Scope) (string, error)
+	DeleteScopedPolicy(scope *config.Scope) error
+	GetScopedPolicies() ([]*config.ScopedPolicy, error)
+}
+
+// ScopedPolicyConfigurator stores a witness policy that applies to the anchors of a DID namespace and/or
+// the operations of an anchor origin.
+type ScopedPolicyConfigurator struct {
+	store scopedPolicyStore
+}
+
+// Path returns the HTTP REST endpoint for the ScopedPolicyConfigurator service.
+func (pc *ScopedPolicyConfigurator) Path() string {
+	return scopedEndpoint
+}
+
+// Method returns the HTTP REST method for the ScopedPolicyConfigurator service.
+func (pc *ScopedPolicyConfigurator) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the ScopedPolicyConfigurator service.
+func (pc *ScopedPolicyConfigurator) Handler() common.HTTPRequestHandler {
+	return pc.handle
+}
+
+// NewScopedConfigurator returns a new ScopedPolicyConfigurator.
+func NewScopedConfigurator(store scopedPolicyStore) *ScopedPolicyConfigurator {
+	return &ScopedPolicyConfigurator{
+		store: store,
+	}
+}
+
+func (pc *ScopedPolicyConfigurator) handle(w http.ResponseWriter, req *http.Request) {
+	scopedPolicy, err := getScopedPolicy(req)
+	if err != nil {
+		logger.Debug("Invalid scoped witness policy request", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("%s %s", badRequestResponse, err)))
+
+		return
+	}
+
+	err = pc.store.PutScopedPolicy(&scopedPolicy.Scope, scopedPolicy.Policy)
+	if err != nil {
+		logger.Error("Error storing scoped witness policy", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Debug("Stored scoped witness policy", logfields.WithNamespace(scopedPolicy.Namespace),
+		logfields.WithAnchorOrigin(scopedPolicy.Origin), logfields.WithWitnessPolicy(scopedPolicy.Policy))
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+// ScopedPolicyRetriever retrieves the scoped witness policies. If the namespace and/or origin query parameters
+// are specified then only the policy for the exact scope is returned.
+type ScopedPolicyRetriever struct {
+	store   scopedPolicyStore
+	marshal func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the ScopedPolicyRetriever service.
+func (pr *ScopedPolicyRetriever) Path() string {
+	return scopedEndpoint
+}
+
+// Method returns the HTTP REST method for the ScopedPolicyRetriever service.
+func (pr *ScopedPolicyRetriever) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the ScopedPolicyRetriever service.
+func (pr *ScopedPolicyRetriever) Handler() common.HTTPRequestHandler {
+	return pr.handle
+}
+
+// NewScopedRetriever returns a new ScopedPolicyRetriever.
+func NewScopedRetriever(store scopedPolicyStore) *ScopedPolicyRetriever {
+	return &ScopedPolicyRetriever{
+		store:   store,
+		marshal: json.Marshal,
+	}
+}
+
+func (pr *ScopedPolicyRetriever) handle(w http.ResponseWriter, req *http.Request) {
+	policies, err := pr.getPolicies(getScope(req))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			writeResponse(w, http.StatusNotFound, nil)
+
+			return
+		}
+
+		logger.Error("Error retrieving scoped witness policies", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := pr.marshal(policies)
+	if err != nil {
+		logger.Error("Error marshalling scoped witness policies", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (pr *ScopedPolicyRetriever) getPolicies(scope *config.Scope) ([]*config.ScopedPolicy, error) {
+	if scope.Validate() != nil {
+		policies, err := pr.store.GetScopedPolicies()
+		if err != nil {
+			return nil, err
+		}
+
+		if policies == nil {
+			policies = []*config.ScopedPolicy{}
+		}
+
+		return policies, nil
+	}
+
+	policy, err := pr.store.GetScopedPolicy(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*config.ScopedPolicy{{Scope: *scope, Policy: policy}}, nil
+}
+
+// ScopedPolicyDeleter deletes the witness policy of a scope. Anchors within the scope are then subject to the
+// policy of a less specific scope or the global policy.
+type ScopedPolicyDeleter struct {
+	store scopedPolicyStore
+}
+
+// Path returns the HTTP REST endpoint for the ScopedPolicyDeleter service.
+func (pd *ScopedPolicyDeleter) Path() string {
+	return scopedEndpoint
+}
+
+// Method returns the HTTP REST method for the ScopedPolicyDeleter service.
+func (pd *ScopedPolicyDeleter) Method() string {
+	return http.MethodDelete
+}
+
+// Handler returns the HTTP REST handle for the ScopedPolicyDeleter service.
+func (pd *ScopedPolicyDeleter) Handler() common.HTTPRequestHandler {
+	return pd.handle
+}
+
+// NewScopedDeleter returns a new ScopedPolicyDeleter.
+func NewScopedDeleter(store scopedPolicyStore) *ScopedPolicyDeleter {
+	return &ScopedPolicyDeleter{
+		store: store,
+	}
+}
+
+func (pd *ScopedPolicyDeleter) handle(w http.ResponseWriter, req *http.Request) {
+	scope := getScope(req)
+
+	if err := scope.Validate(); err != nil {
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("%s %s", badRequestResponse, err)))
+
+		return
+	}
+
+	err := pd.store.DeleteScopedPolicy(scope)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			writeResponse(w, http.StatusNotFound, nil)
+
+			return
+		}
+
+		logger.Error("Error deleting scoped witness policy", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logger.Debug("Deleted scoped witness policy", logfields.WithNamespace(scope.Namespace),
+		logfields.WithAnchorOrigin(scope.Origin))
+
+	writeResponse(w, http.StatusOK, nil)
+}
+
+func getScopedPolicy(req *http.Request) (*config.ScopedPolicy, error) {
+	reqBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	scopedPolicy := &config.ScopedPolicy{}
+
+	err = json.Unmarshal(reqBytes, scopedPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	err = scopedPolicy.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = config.Parse(scopedPolicy.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness policy: %w", err)
+	}
+
+	return scopedPolicy, nil
+}
+
+func getScope(req *http.Request) *config.Scope {
+	return &config.Scope{
+		Namespace: req.URL.Query().Get(namespaceParam),
+		Origin:    req.URL.Query().Get(originParam),
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+)
+
+func TestNewScopedHandlers(t *testing.T) {
+	s := newScopedPolicyStore(t)
+
+	configurator := NewScopedConfigurator(s)
+	require.Equal(t, scopedEndpoint, configurator.Path())
+	require.Equal(t, http.MethodPost, configurator.Method())
+	require.NotNil(t, configurator.Handler())
+
+	retriever := NewScopedRetriever(s)
+	require.Equal(t, scopedEndpoint, retriever.Path())
+	require.Equal(t, http.MethodGet, retriever.Method())
+	require.NotNil(t, retriever.Handler())
+
+	deleter := NewScopedDeleter(s)
+	require.Equal(t, scopedEndpoint, deleter.Path())
+	require.Equal(t, http.MethodDelete, deleter.Method())
+	require.NotNil(t, deleter.Handler())
+}
+
+func TestScopedPolicyHandlers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s := newScopedPolicyStore(t)
+
+		configurator := NewScopedConfigurator(s)
+		retriever := NewScopedRetriever(s)
+		deleter := NewScopedDeleter(s)
+
+		handleScoped(t, configurator.handle, http.MethodPost, "",
+			`{"namespace":"did:orb","policy":"MinPercent(100,batch)"}`, http.StatusOK)
+		handleScoped(t, configurator.handle, http.MethodPost, "",
+			`{"namespace":"did:orb","origin":"https://orb.domain1.com","policy":"OutOf(2,system)"}`, http.StatusOK)
+
+		var policies []*config.ScopedPolicy
+
+		require.NoError(t, json.Unmarshal(handleScoped(t, retriever.handle, http.MethodGet, "", "", http.StatusOK),
+			&policies))
+		require.Len(t, policies, 2)
+
+		require.NoError(t, json.Unmarshal(handleScoped(t, retriever.handle, http.MethodGet,
+			"?namespace=did:orb&origin=https://orb.domain1.com", "", http.StatusOK), &policies))
+		require.Len(t, policies, 1)
+		require.Equal(t, "OutOf(2,system)", policies[0].Policy)
+
+		handleScoped(t, deleter.handle, http.MethodDelete, "?namespace=did:orb", "", http.StatusOK)
+		handleScoped(t, deleter.handle, http.MethodDelete, "?namespace=did:orb", "", http.StatusNotFound)
+		handleScoped(t, retriever.handle, http.MethodGet, "?namespace=did:orb", "", http.StatusNotFound)
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		respBytes := handleScoped(t, NewScopedRetriever(newScopedPolicyStore(t)).handle, http.MethodGet, "", "",
+			http.StatusOK)
+		require.Equal(t, "[]", string(respBytes))
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		s := newScopedPolicyStore(t)
+
+		configurator := NewScopedConfigurator(s)
+
+		handleScoped(t, configurator.handle, http.MethodPost, "", `{`, http.StatusBadRequest)
+		handleScoped(t, configurator.handle, http.MethodPost, "", `{"policy":"OutOf(2,system)"}`,
+			http.StatusBadRequest)
+		handleScoped(t, configurator.handle, http.MethodPost, "", `{"namespace":"did:orb","policy":"OutOf(2,xxx)"}`,
+			http.StatusBadRequest)
+
+		handleScoped(t, NewScopedDeleter(s).handle, http.MethodDelete, "", "", http.StatusBadRequest)
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		s := &mockScopedPolicyStore{err: errors.New("injected store error")}
+
+		handleScoped(t, NewScopedConfigurator(s).handle, http.MethodPost, "",
+			`{"namespace":"did:orb","policy":"OutOf(2,system)"}`, http.StatusInternalServerError)
+		handleScoped(t, NewScopedRetriever(s).handle, http.MethodGet, "", "", http.StatusInternalServerError)
+		handleScoped(t, NewScopedRetriever(s).handle, http.MethodGet, "?origin=https://orb.domain1.com", "",
+			http.StatusInternalServerError)
+		handleScoped(t, NewScopedDeleter(s).handle, http.MethodDelete, "?namespace=did:orb", "",
+			http.StatusInternalServerError)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		retriever := NewScopedRetriever(newScopedPolicyStore(t))
+		retriever.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		handleScoped(t, retriever.handle, http.MethodGet, "", "", http.StatusInternalServerError)
+	})
+}
+
+func newScopedPolicyStore(t *testing.T) *config.Store {
+	t.Helper()
+
+	store, err := mem.NewProvider().OpenStore("config")
+	require.NoError(t, err)
+
+	return config.NewPolicyStore(store)
+}
+
+func handleScoped(t *testing.T, handle func(http.ResponseWriter, *http.Request), method, query, request string,
+	expectedStatus int,
+) []byte {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(method, scopedEndpoint+query, bytes.NewBufferString(request))
+
+	handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, result.Body.Close())
+	require.NoError(t, err)
+
+	return respBytes
+}
+
+type mockScopedPolicyStore struct {
+	err error
+}
+
+func (m *mockScopedPolicyStore) PutScopedPolicy(*config.Scope, string) error {
+	return m.err
+}
+
+func (m *mockScopedPolicyStore) GetScopedPolicy(*config.Scope) (string, error) {
+	return "", m.err
+}
+
+func (m *mockScopedPolicyStore) DeleteScopedPolicy(*config.Scope) error {
+	return m.err
+}
+
+func (m *mockScopedPolicyStore) GetScopedPolicies() ([]*config.ScopedPolicy, error) {
+	return nil, m.err
+}
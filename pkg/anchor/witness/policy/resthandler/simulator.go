@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/simulator"
+)
+
+const (
+	simulateEndpoint = "/policy/simulate"
+
+	defaultSimulatedAnchors = 100
+	maxSimulatedAnchors     = 1000
+)
+
+type policySimulator interface {
+	Simulate(policy string, maxAnchors int) (*simulator.Report, error)
+}
+
+// SimulationRequest contains the candidate witness policy to simulate.
+type SimulationRequest struct {
+	Policy string `json:"policy"`
+	// Anchors is the maximum number of recent anchors against which the policy is evaluated.
+	// If not specified then the policy is evaluated against the last 100 anchors.
+	Anchors int `json:"anchors,omitempty"`
+}
+
+// PolicySimulator evaluates a candidate witness policy against the recent anchors (without changing the
+// current witness policy) and reports the anchors that would have completed or stalled.
+type PolicySimulator struct {
+	simulator policySimulator
+	marshal   func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the policy simulator.
+func (ps *PolicySimulator) Path() string {
+	return simulateEndpoint
+}
+
+// Method returns the HTTP REST method for the policy simulator.
+func (ps *PolicySimulator) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the PolicySimulator service.
+func (ps *PolicySimulator) Handler() common.HTTPRequestHandler {
+	return ps.handle
+}
+
+// NewSimulator returns a new PolicySimulator.
+func NewSimulator(simulator policySimulator) *PolicySimulator {
+	return &PolicySimulator{
+		simulator: simulator,
+		marshal:   json.Marshal,
+	}
+}
+
+func (ps *PolicySimulator) handle(w http.ResponseWriter, req *http.Request) {
+	request, err := getSimulationRequest(req)
+	if err != nil {
+		logger.Debug("Invalid witness policy simulation request", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("%s %s", badRequestResponse, err)))
+
+		return
+	}
+
+	report, err := ps.simulator.Simulate(request.Policy, request.Anchors)
+	if err != nil {
+		logger.Error("Error simulating witness policy", logfields.WithWitnessPolicy(request.Policy), log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	respBytes, err := ps.marshal(report)
+	if err != nil {
+		logger.Error("Error marshalling simulation report", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func getSimulationRequest(req *http.Request) (*SimulationRequest, error) {
+	reqBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	request := &SimulationRequest{}
+
+	err = json.Unmarshal(reqBytes, request)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if strings.TrimSpace(request.Policy) == "" {
+		return nil, errors.New("policy is required")
+	}
+
+	_, err = config.Parse(request.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid witness policy: %w", err)
+	}
+
+	switch {
+	case request.Anchors < 0 || request.Anchors > maxSimulatedAnchors:
+		return nil, fmt.Errorf("anchors must be between 1 and %d", maxSimulatedAnchors)
+	case request.Anchors == 0:
+		request.Anchors = defaultSimulatedAnchors
+	}
+
+	return request, nil
+}
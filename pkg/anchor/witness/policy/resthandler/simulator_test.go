@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/simulator"
+)
+
+func TestNewSimulator(t *testing.T) {
+	s := NewSimulator(&mockPolicySimulator{})
+	require.NotNil(t, s)
+	require.Equal(t, simulateEndpoint, s.Path())
+	require.Equal(t, http.MethodPost, s.Method())
+	require.NotNil(t, s.Handler())
+}
+
+func TestPolicySimulator_Handler(t *testing.T) {
+	report := &simulator.Report{
+		Policy:    "OutOf(2,system)",
+		Total:     1,
+		Completed: 1,
+		Anchors:   []*simulator.AnchorResult{{AnchorID: testAnchorID, Status: simulator.StatusCompleted}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		s := &mockPolicySimulator{report: report}
+
+		respBytes := simulate(t, NewSimulator(s), `{"policy":"OutOf(2,system)","anchors":10}`, http.StatusOK)
+		require.Equal(t, "OutOf(2,system)", s.policy)
+		require.Equal(t, 10, s.maxAnchors)
+
+		r := &simulator.Report{}
+		require.NoError(t, json.Unmarshal(respBytes, r))
+		require.Equal(t, 1, r.Completed)
+		require.Len(t, r.Anchors, 1)
+	})
+
+	t.Run("success - default anchors", func(t *testing.T) {
+		s := &mockPolicySimulator{report: report}
+
+		simulate(t, NewSimulator(s), `{"policy":"OutOf(2,system)"}`, http.StatusOK)
+		require.Equal(t, defaultSimulatedAnchors, s.maxAnchors)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		s := NewSimulator(&mockPolicySimulator{report: report})
+
+		simulate(t, s, `{`, http.StatusBadRequest)
+		simulate(t, s, `{}`, http.StatusBadRequest)
+		simulate(t, s, `{"policy":"OutOf(2,xxx)"}`, http.StatusBadRequest)
+		simulate(t, s, `{"policy":"OutOf(2,system)","anchors":-1}`, http.StatusBadRequest)
+		simulate(t, s, `{"policy":"OutOf(2,system)","anchors":1001}`, http.StatusBadRequest)
+	})
+
+	t.Run("simulate error", func(t *testing.T) {
+		simulate(t, NewSimulator(&mockPolicySimulator{err: errors.New("injected simulate error")}),
+			`{"policy":"OutOf(2,system)"}`, http.StatusInternalServerError)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		s := NewSimulator(&mockPolicySimulator{report: report})
+		s.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		simulate(t, s, `{"policy":"OutOf(2,system)"}`, http.StatusInternalServerError)
+	})
+}
+
+func simulate(t *testing.T, s *PolicySimulator, request string, expectedStatus int) []byte {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, simulateEndpoint, bytes.NewBufferString(request))
+
+	s.handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, result.Body.Close())
+	require.NoError(t, err)
+
+	return respBytes
+}
+
+type mockPolicySimulator struct {
+	report *simulator.Report
+	err    error
+
+	policy     string
+	maxAnchors int
+}
+
+func (m *mockPolicySimulator) Simulate(policy string, maxAnchors int) (*simulator.Report, error) {
+	m.policy = policy
+	m.maxAnchors = maxAnchors
+
+	return m.report, m.err
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
+const (
+	statsEndpoint = "/witness-stats"
+
+	windowParam  = "window"
+	witnessParam = "witness"
+
+	defaultStatsWindow = 24 * time.Hour
+)
+
+type statsStore interface {
+	GetStats(witness *url.URL) (*witness.Stats, error)
+	GetAllStats() ([]*witness.Stats, error)
+}
+
+// StatsRetriever retrieves the proof statistics of the witnesses.
+type StatsRetriever struct {
+	store   statsStore
+	marshal func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the stats retriever.
+func (sr *StatsRetriever) Path() string {
+	return statsEndpoint
+}
+
+// Method returns the HTTP REST method for the stats retriever.
+func (sr *StatsRetriever) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the StatsRetriever service.
+func (sr *StatsRetriever) Handler() common.HTTPRequestHandler {
+	return sr.handle
+}
+
+// NewStatsRetriever returns a new StatsRetriever.
+func NewStatsRetriever(store statsStore) *StatsRetriever {
+	return &StatsRetriever{
+		store:   store,
+		marshal: json.Marshal,
+	}
+}
+
+func (sr *StatsRetriever) handle(w http.ResponseWriter, req *http.Request) {
+	window, witnessURI, err := getStatsParams(req)
+	if err != nil {
+		logger.Debug("Invalid witness stats request", log.WithError(err))
+
+		writeResponse(w, http.StatusBadRequest, []byte(fmt.Sprintf("%s %s", badRequestResponse, err)))
+
+		return
+	}
+
+	allStats, err := sr.getStats(witnessURI)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			writeResponse(w, http.StatusNotFound, nil)
+
+			return
+		}
+
+		logger.Error("Error retrieving witness stats", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	since := time.Now().Add(-window)
+
+	summaries := make([]*witness.StatsSummary, len(allStats))
+
+	for i, stats := range allStats {
+		summaries[i] = stats.Summarize(since)
+	}
+
+	respBytes, err := sr.marshal(summaries)
+	if err != nil {
+		logger.Error("Error marshalling witness stats", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, respBytes)
+}
+
+func (sr *StatsRetriever) getStats(witnessURI *url.URL) ([]*witness.Stats, error) {
+	if witnessURI == nil {
+		return sr.store.GetAllStats()
+	}
+
+	stats, err := sr.store.GetStats(witnessURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*witness.Stats{stats}, nil
+}
+
+func getStatsParams(req *http.Request) (time.Duration, *url.URL, error) {
+	window := defaultStatsWindow
+
+	if windowStr := req.URL.Query().Get(windowParam); windowStr != "" {
+		w, err := time.ParseDuration(windowStr)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid window [%s]: %w", windowStr, err)
+		}
+
+		if w <= 0 || w > witness.MaxStatsRetention {
+			return 0, nil, fmt.Errorf("window [%s] must be greater than 0 and not more than %s",
+				windowStr, witness.MaxStatsRetention)
+		}
+
+		window = w
+	}
+
+	witnessStr := req.URL.Query().Get(witnessParam)
+	if witnessStr == "" {
+		return window, nil, nil
+	}
+
+	witnessURI, err := url.Parse(witnessStr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid witness [%s]: %w", witnessStr, err)
+	}
+
+	return window, witnessURI, nil
+}
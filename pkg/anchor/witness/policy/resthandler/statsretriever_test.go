@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
+func TestNewStatsRetriever(t *testing.T) {
+	statsRetriever := NewStatsRetriever(&mockStatsStore{})
+	require.NotNil(t, statsRetriever)
+	require.Equal(t, statsEndpoint, statsRetriever.Path())
+	require.Equal(t, http.MethodGet, statsRetriever.Method())
+	require.NotNil(t, statsRetriever.Handler())
+}
+
+func TestStatsRetriever_Handler(t *testing.T) {
+	witness1URL := testutil.MustParseURL("https://domain1.com/services/orb")
+	witness2URL := testutil.MustParseURL("https://domain2.com/services/orb")
+
+	now := time.Now().Truncate(witness.StatsWindowPeriod)
+
+	statsStore := &mockStatsStore{
+		stats: map[string]*witness.Stats{
+			witness1URL.String(): {
+				WitnessURI: vocab.NewURLProperty(witness1URL),
+				Windows: []*witness.StatsWindow{
+					{Start: now.Add(-48 * time.Hour), Requests: 10, Proofs: 5, Timeouts: 5},
+					{Start: now, Requests: 3, Proofs: 2, Timeouts: 1, ReOffers: 1},
+				},
+			},
+			witness2URL.String(): {
+				WitnessURI: vocab.NewURLProperty(witness2URL),
+				Windows: []*witness.StatsWindow{
+					{Start: now, Requests: 1},
+				},
+			},
+		},
+	}
+
+	t.Run("success - all witnesses", func(t *testing.T) {
+		summaries := getStatsSummaries(t, NewStatsRetriever(statsStore), statsEndpoint, http.StatusOK)
+		require.Len(t, summaries, 2)
+	})
+
+	t.Run("success - single witness", func(t *testing.T) {
+		summaries := getStatsSummaries(t, NewStatsRetriever(statsStore),
+			statsEndpoint+"?witness="+url.QueryEscape(witness1URL.String()), http.StatusOK)
+		require.Len(t, summaries, 1)
+		require.Equal(t, witness1URL.String(), summaries[0].WitnessURI.String())
+		require.Equal(t, int64(3), summaries[0].Requests)
+		require.Equal(t, int64(2), summaries[0].Proofs)
+		require.Equal(t, int64(1), summaries[0].Timeouts)
+		require.Equal(t, int64(1), summaries[0].ReOffers)
+	})
+
+	t.Run("success - window", func(t *testing.T) {
+		summaries := getStatsSummaries(t, NewStatsRetriever(statsStore),
+			statsEndpoint+"?window=72h&witness="+url.QueryEscape(witness1URL.String()), http.StatusOK)
+		require.Len(t, summaries, 1)
+		require.Equal(t, int64(13), summaries[0].Requests)
+	})
+
+	t.Run("witness not found", func(t *testing.T) {
+		getStatsSummaries(t, NewStatsRetriever(statsStore),
+			statsEndpoint+"?witness="+url.QueryEscape("https://domain3.com/services/orb"), http.StatusNotFound)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		getStatsSummaries(t, NewStatsRetriever(statsStore), statsEndpoint+"?window=xxx", http.StatusBadRequest)
+		getStatsSummaries(t, NewStatsRetriever(statsStore), statsEndpoint+"?window=-1h", http.StatusBadRequest)
+		getStatsSummaries(t, NewStatsRetriever(statsStore), statsEndpoint+"?window=1000h", http.StatusBadRequest)
+	})
+
+	t.Run("invalid witness", func(t *testing.T) {
+		getStatsSummaries(t, NewStatsRetriever(statsStore), statsEndpoint+"?witness=%3A", http.StatusBadRequest)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		getStatsSummaries(t, NewStatsRetriever(&mockStatsStore{err: errors.New("injected store error")}),
+			statsEndpoint, http.StatusInternalServerError)
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		statsRetriever := NewStatsRetriever(statsStore)
+		statsRetriever.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		getStatsSummaries(t, statsRetriever, statsEndpoint, http.StatusInternalServerError)
+	})
+}
+
+func getStatsSummaries(t *testing.T, statsRetriever *StatsRetriever, target string,
+	expectedStatus int,
+) []*witness.StatsSummary {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+
+	statsRetriever.handle(rw, req)
+
+	result := rw.Result()
+	require.Equal(t, expectedStatus, result.StatusCode)
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, result.Body.Close())
+	require.NoError(t, err)
+
+	if expectedStatus != http.StatusOK {
+		return nil
+	}
+
+	var summaries []*witness.StatsSummary
+	require.NoError(t, json.Unmarshal(respBytes, &summaries))
+
+	return summaries
+}
+
+type mockStatsStore struct {
+	stats map[string]*witness.Stats
+	err   error
+}
+
+func (m *mockStatsStore) GetStats(witnessURI *url.URL) (*witness.Stats, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	stats, ok := m.stats[witnessURI.String()]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return stats, nil
+}
+
+func (m *mockStatsStore) GetAllStats() ([]*witness.Stats, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var allStats []*witness.Stats
+
+	for _, stats := range m.stats {
+		allStats = append(allStats, stats)
+	}
+
+	return allStats, nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scored
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
+var logger = log.New("witness-selector")
+
+const (
+	defaultReferenceTurnaround = 10 * time.Second
+	defaultCacheExpiry         = time.Minute
+	defaultCacheSize           = 1000
+
+	// defaultScore is the score of a witness without stats.
+	defaultScore = 1.0
+
+	// minScore ensures that an unhealthy witness is still selected occasionally so that its
+	// stats are updated when it recovers.
+	minScore = 0.01
+)
+
+type statsProvider interface {
+	GetStats(witness *url.URL) (*witness.Stats, error)
+}
+
+// Selector selects n out of m witnesses, preferring witnesses that have historically returned proofs
+// quickly and reliably. Witnesses are selected randomly, where the probability of selecting a witness
+// is proportional to its score. The score is derived from the witness' failure rate and average proof
+// turnaround time. Witnesses without stats are given the score of a witness which has never failed and
+// whose turnaround time is the reference turnaround time.
+type Selector struct {
+	statsProvider       statsProvider
+	referenceTurnaround time.Duration
+	cacheExpiry         time.Duration
+	cache               gcache.Cache
+	rand                *rand.Rand
+	randMutex           sync.Mutex
+}
+
+// Opt is a selector option.
+type Opt func(s *Selector)
+
+// WithReferenceTurnaround sets the expected proof turnaround time of a witness. Witnesses that are faster
+// than the reference turnaround time score higher and slower witnesses score lower.
+func WithReferenceTurnaround(value time.Duration) Opt {
+	return func(s *Selector) {
+		s.referenceTurnaround = value
+	}
+}
+
+// WithCacheExpiry sets the expiry time of the cached witness scores.
+func WithCacheExpiry(value time.Duration) Opt {
+	return func(s *Selector) {
+		s.cacheExpiry = value
+	}
+}
+
+// New returns a new scored selector.
+func New(statsProvider statsProvider, opts ...Opt) *Selector {
+	s := &Selector{
+		statsProvider:       statsProvider,
+		referenceTurnaround: defaultReferenceTurnaround,
+		cacheExpiry:         defaultCacheExpiry,
+		rand:                rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.cache = gcache.New(defaultCacheSize).ARC().
+		Expiration(s.cacheExpiry).
+		LoaderFunc(func(key interface{}) (interface{}, error) {
+			return s.loadScore(key.(string)) //nolint:forcetypeassert
+		}).Build()
+
+	return s
+}
+
+// Select selects n witnesses out of provided list of witnesses.
+func (s *Selector) Select(witnesses []*proof.Witness, n int) ([]*proof.Witness, error) {
+	l := len(witnesses)
+
+	if n > l {
+		return nil, fmt.Errorf("unable to select %d witnesses from witness array of length %d: %w",
+			n, len(witnesses), orberrors.ErrWitnessesNotFound)
+	}
+
+	if n == l {
+		return witnesses, nil
+	}
+
+	// Weighted random sampling without replacement (Efraimidis-Spirakis): each witness is assigned
+	// the key u^(1/score), where u is a uniform random number in (0,1), and the n witnesses with
+	// the largest keys are selected.
+	type candidate struct {
+		witness *proof.Witness
+		key     float64
+	}
+
+	candidates := make([]candidate, l)
+
+	for i, w := range witnesses {
+		candidates[i] = candidate{
+			witness: w,
+			key:     math.Pow(s.random(), 1/s.score(w)),
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+
+	selected := make([]*proof.Witness, n)
+
+	for i := 0; i < n; i++ {
+		selected[i] = candidates[i].witness
+	}
+
+	return selected, nil
+}
+
+func (s *Selector) score(w *proof.Witness) float64 {
+	if w.URI == nil {
+		return defaultScore
+	}
+
+	score, err := s.cache.Get(w.URI.String())
+	if err != nil {
+		logger.Warn("Error getting witness score. The default score will be used.",
+			logfields.WithWitnessURI(w.URI), log.WithError(err))
+
+		return defaultScore
+	}
+
+	return score.(float64) //nolint:forcetypeassert
+}
+
+func (s *Selector) loadScore(witnessURI string) (float64, error) {
+	u, err := url.Parse(witnessURI)
+	if err != nil {
+		return 0, fmt.Errorf("parse witness URI [%s]: %w", witnessURI, err)
+	}
+
+	stats, err := s.statsProvider.GetStats(u)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			return defaultScore, nil
+		}
+
+		return 0, fmt.Errorf("get stats for witness [%s]: %w", witnessURI, err)
+	}
+
+	score := s.calculateScore(stats)
+
+	logger.Debug("Calculated witness score", logfields.WithWitnessURIString(witnessURI),
+		logfields.WithScore(score))
+
+	return score, nil
+}
+
+// calculateScore returns the score of a witness, where a higher score indicates a healthier witness. A witness
+// that has never failed and whose turnaround time is the reference turnaround time has a score of 1.
+func (s *Selector) calculateScore(stats *witness.Stats) float64 {
+	reliability := 1 - stats.FailureRate
+
+	turnaround := stats.Turnaround
+	if stats.Proofs == 0 {
+		turnaround = s.referenceTurnaround
+	}
+
+	speed := float64(s.referenceTurnaround) / float64(s.referenceTurnaround+turnaround)
+
+	return math.Max(2*reliability*speed, minScore)
+}
+
+func (s *Selector) random() float64 {
+	s.randMutex.Lock()
+	defer s.randMutex.Unlock()
+
+	return s.rand.Float64()
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scored
+
+import (
+	"errors"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
+var (
+	witness1URL = testutil.MustParseURL("https://domain1.com/services/orb")
+	witness2URL = testutil.MustParseURL("https://domain2.com/services/orb")
+	witness3URL = testutil.MustParseURL("https://domain3.com/services/orb")
+)
+
+func TestNew(t *testing.T) {
+	s := New(&mockStatsProvider{}, WithReferenceTurnaround(time.Second), WithCacheExpiry(time.Second))
+	require.NotNil(t, s)
+	require.Equal(t, time.Second, s.referenceTurnaround)
+	require.Equal(t, time.Second, s.cacheExpiry)
+}
+
+func TestSelect(t *testing.T) {
+	witnesses := []*proof.Witness{
+		{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL)},
+		{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL)},
+		{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness3URL)},
+	}
+
+	t.Run("prefers healthy witnesses", func(t *testing.T) {
+		statsProvider := &mockStatsProvider{
+			stats: map[string]*witness.Stats{
+				// Fast and reliable.
+				witness1URL.String(): {Proofs: 100, Turnaround: time.Second},
+				// Slow.
+				witness2URL.String(): {Proofs: 100, Turnaround: 2 * time.Minute},
+				// Unreliable.
+				witness3URL.String(): {Proofs: 1, Timeouts: 99, Turnaround: time.Second, FailureRate: 0.99},
+			},
+		}
+
+		s := New(statsProvider)
+		s.rand = rand.New(rand.NewSource(1)) //nolint:gosec
+
+		counts := make(map[string]int)
+
+		for i := 0; i < 1000; i++ {
+			selected, err := s.Select(witnesses, 1)
+			require.NoError(t, err)
+			require.Len(t, selected, 1)
+
+			counts[selected[0].URI.String()]++
+		}
+
+		require.Greater(t, counts[witness1URL.String()], 800)
+		require.Greater(t, counts[witness2URL.String()], 0)
+		require.Less(t, counts[witness3URL.String()], counts[witness2URL.String()])
+
+		selected, err := s.Select(witnesses, 2)
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+		require.NotEqual(t, selected[0].URI.String(), selected[1].URI.String())
+	})
+
+	t.Run("witnesses without stats", func(t *testing.T) {
+		s := New(&mockStatsProvider{})
+
+		selected, err := s.Select(witnesses, 2)
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+	})
+
+	t.Run("stats provider error", func(t *testing.T) {
+		s := New(&mockStatsProvider{err: errors.New("injected stats error")})
+
+		selected, err := s.Select(witnesses, 2)
+		require.NoError(t, err)
+		require.Len(t, selected, 2)
+	})
+
+	t.Run("select all", func(t *testing.T) {
+		s := New(&mockStatsProvider{})
+
+		selected, err := s.Select(witnesses, 3)
+		require.NoError(t, err)
+		require.Equal(t, witnesses, selected)
+	})
+
+	t.Run("error - not enough witnesses", func(t *testing.T) {
+		s := New(&mockStatsProvider{})
+
+		selected, err := s.Select(witnesses, 4)
+		require.Error(t, err)
+		require.Empty(t, selected)
+		require.True(t, errors.Is(err, orberrors.ErrWitnessesNotFound))
+		require.Contains(t, err.Error(), "unable to select 4 witnesses from witness array of length 3")
+	})
+}
+
+func TestCalculateScore(t *testing.T) {
+	s := New(&mockStatsProvider{}, WithReferenceTurnaround(10*time.Second))
+
+	require.Equal(t, 1.0, s.calculateScore(&witness.Stats{}))
+	require.Equal(t, 1.0, s.calculateScore(&witness.Stats{Proofs: 1, Turnaround: 10 * time.Second}))
+	require.Greater(t, s.calculateScore(&witness.Stats{Proofs: 1, Turnaround: time.Second}), 1.0)
+	require.Less(t, s.calculateScore(&witness.Stats{Proofs: 1, Turnaround: time.Minute}), 1.0)
+	require.Equal(t, 0.5, s.calculateScore(&witness.Stats{Proofs: 1, Turnaround: 10 * time.Second, FailureRate: 0.5}))
+	require.Equal(t, minScore, s.calculateScore(&witness.Stats{Timeouts: 10, FailureRate: 1}))
+}
+
+type mockStatsProvider struct {
+	stats map[string]*witness.Stats
+	err   error
+}
+
+func (m *mockStatsProvider) GetStats(witnessURI *url.URL) (*witness.Stats, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	stats, ok := m.stats[witnessURI.String()]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,365 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
+var logger = log.New("policy-simulator")
+
+// Status is the simulated status of an anchor.
+type Status string
+
+const (
+	// StatusCompleted indicates that the candidate policy would have been satisfied by the proofs of the anchor.
+	StatusCompleted Status = "completed"
+	// StatusStalled indicates that the candidate policy would not have been satisfied by the proofs of the
+	// anchor, i.e. the anchor would have been pending until additional witnesses returned a proof.
+	StatusStalled Status = "stalled"
+)
+
+// Providers contains the providers required by the simulator.
+type Providers struct {
+	WitnessStore  witnessStore
+	StatusStore   statusStore
+	WitnessPolicy witnessPolicy
+}
+
+type witnessStore interface {
+	Get(anchorID string) ([]*proof.WitnessProof, error)
+	GetTimings(anchorID string) ([]*witness.WitnessTiming, error)
+	GetRecentWitnessedAnchors(maxAnchors int) ([]*witness.WitnessedAnchor, error)
+}
+
+type statusStore interface {
+	GetInProcessAnchors() ([]string, error)
+}
+
+type witnessPolicy interface {
+	EvaluatePolicy(policy string, witnesses []*proof.WitnessProof) (bool, error)
+}
+
+// Report contains the results of a witness policy simulation.
+type Report struct {
+	Policy string `json:"policy"`
+	// Total is the number of anchors against which the policy was evaluated.
+	Total int `json:"total"`
+	// Completed is the number of anchors for which the policy would have been satisfied.
+	Completed int `json:"completed"`
+	// Stalled is the number of anchors for which the policy would not have been satisfied.
+	Stalled int `json:"stalled"`
+
+	// AverageDuration and MaxDuration are the average and maximum times from the offer to the witnesses
+	// until the candidate policy would have been satisfied (for completed anchors with known timings).
+	AverageDuration time.Duration `json:"averageDuration"`
+	MaxDuration     time.Duration `json:"maxDuration"`
+	// ActualAverageDuration is the average time that it took to satisfy the current witness policy for the
+	// anchors that were witnessed. It may be used to compare the candidate policy with the current policy.
+	ActualAverageDuration time.Duration `json:"actualAverageDuration"`
+
+	Anchors []*AnchorResult `json:"anchors"`
+}
+
+// AnchorResult contains the simulated result of the candidate policy for a single anchor.
+type AnchorResult struct {
+	AnchorID string `json:"anchorID"`
+	Status   Status `json:"status"`
+	// Pending is true if the anchor is still pending the current witness policy.
+	Pending bool `json:"pending,omitempty"`
+	// ProofsRequired is the number of proofs (in the order in which they were received) that would have
+	// been required to satisfy the candidate policy.
+	ProofsRequired int `json:"proofsRequired,omitempty"`
+	// Duration is the time from the offer to the witnesses until the candidate policy would have been
+	// satisfied. The duration is zero if the anchor would have stalled or if the timings are unknown.
+	Duration time.Duration `json:"duration,omitempty"`
+	// ActualDuration is the time that it took to satisfy the current witness policy (zero if the anchor is
+	// still pending or if the timings are unknown).
+	ActualDuration time.Duration `json:"actualDuration,omitempty"`
+}
+
+// Simulator evaluates a candidate witness policy against the recent anchors in order to preview the
+// impact of a policy change before it's committed.
+type Simulator struct {
+	*Providers
+}
+
+type anchorSample struct {
+	anchorID       string
+	pending        bool
+	witnesses      []*proof.WitnessProof
+	timings        []*witness.WitnessTiming
+	offeredTime    int64
+	actualDuration time.Duration
+}
+
+// New returns a new witness policy simulator.
+func New(providers *Providers) *Simulator {
+	return &Simulator{
+		Providers: providers,
+	}
+}
+
+// Simulate evaluates the given candidate policy against (at most) the given number of the most recent anchors,
+// including the anchors that are still pending. The witnesses and proofs of each anchor are replayed in the order
+// in which the proofs were received. Note that the candidate policy is evaluated against the witnesses that were
+// selected by the current policy, so an anchor stalls if the candidate policy requires a witness that wasn't
+// selected. Scoped witness policies are not taken into account, i.e. the candidate policy is evaluated as if
+// it were the only witness policy.
+func (s *Simulator) Simulate(policy string, maxAnchors int) (*Report, error) {
+	if _, err := config.Parse(policy); err != nil {
+		return nil, fmt.Errorf("invalid witness policy: %w", err)
+	}
+
+	samples, err := s.getSamples(maxAnchors)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Policy:  policy,
+		Total:   len(samples),
+		Anchors: make([]*AnchorResult, len(samples)),
+	}
+
+	var totalDuration, totalActualDuration time.Duration
+
+	var numDurations, numActualDurations int
+
+	for i, sample := range samples {
+		result, e := s.simulate(policy, sample)
+		if e != nil {
+			return nil, fmt.Errorf("simulate policy for anchor [%s]: %w", sample.anchorID, e)
+		}
+
+		report.Anchors[i] = result
+
+		if result.ActualDuration > 0 {
+			totalActualDuration += result.ActualDuration
+			numActualDurations++
+		}
+
+		if result.Status == StatusStalled {
+			report.Stalled++
+
+			continue
+		}
+
+		report.Completed++
+
+		if result.Duration > 0 {
+			totalDuration += result.Duration
+			numDurations++
+
+			if result.Duration > report.MaxDuration {
+				report.MaxDuration = result.Duration
+			}
+		}
+	}
+
+	if numDurations > 0 {
+		report.AverageDuration = totalDuration / time.Duration(numDurations)
+	}
+
+	if numActualDurations > 0 {
+		report.ActualAverageDuration = totalActualDuration / time.Duration(numActualDurations)
+	}
+
+	logger.Info("Simulated witness policy", logfields.WithWitnessPolicy(policy), logfields.WithTotal(report.Total))
+
+	return report, nil
+}
+
+func (s *Simulator) simulate(policy string, sample *anchorSample) (*AnchorResult, error) {
+	result := &AnchorResult{
+		AnchorID:       sample.anchorID,
+		Pending:        sample.pending,
+		Status:         StatusStalled,
+		ActualDuration: sample.actualDuration,
+	}
+
+	// Start with no proofs and add the proofs in the order in which they were received.
+	witnesses := make([]*proof.WitnessProof, len(sample.witnesses))
+
+	var received []*receivedProof
+
+	for i, w := range sample.witnesses {
+		witnesses[i] = &proof.WitnessProof{Witness: w.Witness}
+
+		if w.Proof != nil {
+			received = append(received, &receivedProof{
+				witness:   witnesses[i],
+				proof:     w.Proof,
+				proofTime: proofTime(w, sample.timings),
+			})
+		}
+	}
+
+	sort.SliceStable(received, func(i, j int) bool {
+		return received[i].before(received[j])
+	})
+
+	satisfied, err := s.WitnessPolicy.EvaluatePolicy(policy, witnesses)
+	if err != nil {
+		return nil, err
+	}
+
+	if satisfied {
+		result.Status = StatusCompleted
+
+		return result, nil
+	}
+
+	for i, r := range received {
+		r.witness.Proof = r.proof
+
+		satisfied, err = s.WitnessPolicy.EvaluatePolicy(policy, witnesses)
+		if err != nil {
+			return nil, err
+		}
+
+		if satisfied {
+			result.Status = StatusCompleted
+			result.ProofsRequired = i + 1
+
+			if r.proofTime > 0 && sample.offeredTime > 0 && r.proofTime >= sample.offeredTime {
+				result.Duration = time.Duration(r.proofTime-sample.offeredTime) * time.Millisecond
+			}
+
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Simulator) getSamples(maxAnchors int) ([]*anchorSample, error) {
+	if maxAnchors <= 0 {
+		return nil, nil
+	}
+
+	samples, err := s.getPendingSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]bool)
+
+	for _, sample := range samples {
+		pending[sample.anchorID] = true
+	}
+
+	witnessedAnchors, err := s.WitnessStore.GetRecentWitnessedAnchors(maxAnchors)
+	if err != nil {
+		return nil, fmt.Errorf("get recent witnessed anchors: %w", err)
+	}
+
+	for _, anchor := range witnessedAnchors {
+		// An anchor that was re-opened (for example, after a witness was revoked) is simulated as a pending anchor.
+		if pending[anchor.AnchorID] {
+			continue
+		}
+
+		sample := &anchorSample{
+			anchorID:    anchor.AnchorID,
+			witnesses:   anchor.Witnesses,
+			timings:     anchor.Timings,
+			offeredTime: witness.OfferedTime(anchor.Timings),
+		}
+
+		if sample.offeredTime > 0 && anchor.WitnessedTime >= sample.offeredTime {
+			sample.actualDuration = time.Duration(anchor.WitnessedTime-sample.offeredTime) * time.Millisecond
+		}
+
+		samples = append(samples, sample)
+	}
+
+	// Simulate the most recently offered anchors.
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].offeredTime > samples[j].offeredTime
+	})
+
+	if len(samples) > maxAnchors {
+		samples = samples[:maxAnchors]
+	}
+
+	return samples, nil
+}
+
+func (s *Simulator) getPendingSamples() ([]*anchorSample, error) {
+	anchorIDs, err := s.StatusStore.GetInProcessAnchors()
+	if err != nil {
+		return nil, fmt.Errorf("get in-process anchors: %w", err)
+	}
+
+	var samples []*anchorSample
+
+	for _, anchorID := range anchorIDs {
+		witnesses, e := s.WitnessStore.Get(anchorID)
+		if e != nil {
+			// The witness data may have expired.
+			logger.Debug("Unable to get witnesses for pending anchor. The anchor won't be simulated.",
+				logfields.WithAnchorURIString(anchorID), log.WithError(e))
+
+			continue
+		}
+
+		timings, e := s.WitnessStore.GetTimings(anchorID)
+		if e != nil {
+			return nil, fmt.Errorf("get witness timings for anchor [%s]: %w", anchorID, e)
+		}
+
+		samples = append(samples, &anchorSample{
+			anchorID:    anchorID,
+			pending:     true,
+			witnesses:   witnesses,
+			timings:     timings,
+			offeredTime: witness.OfferedTime(timings),
+		})
+	}
+
+	return samples, nil
+}
+
+type receivedProof struct {
+	witness   *proof.WitnessProof
+	proof     []byte
+	proofTime int64
+}
+
+// before returns true if this proof was received before the given proof. Proofs with an unknown
+// receipt time are ordered last.
+func (p *receivedProof) before(other *receivedProof) bool {
+	if p.proofTime == 0 {
+		return false
+	}
+
+	return other.proofTime == 0 || p.proofTime < other.proofTime
+}
+
+func proofTime(w *proof.WitnessProof, timings []*witness.WitnessTiming) int64 {
+	if w.URI == nil {
+		return 0
+	}
+
+	for _, t := range timings {
+		if t.Witness == w.URI.String() {
+			return t.ProofTime
+		}
+	}
+
+	return 0
+}
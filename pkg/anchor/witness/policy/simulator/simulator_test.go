@@ -0,0 +1,271 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package simulator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/mocks"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/witness"
+)
+
+const (
+	w1 = "https://w1.com/services/orb"
+	w2 = "https://w2.com/services/orb"
+	w3 = "https://w3.com/services/orb"
+
+	anchor1 = "hl:uEiAnchor1"
+	anchor2 = "hl:uEiAnchor2"
+	anchor3 = "hl:uEiAnchor3"
+)
+
+func TestSimulator_Simulate(t *testing.T) {
+	const offeredTime = int64(1000000)
+
+	policyStore := &mocks.PolicyStore{}
+	policyStore.GetPolicyReturns("MinPercent(50,system)", nil)
+
+	wp, err := policy.New(policyStore, time.Minute)
+	require.NoError(t, err)
+
+	// Anchor1 was witnessed by w1 (after 2s) and w2 (after 5s). The current policy was satisfied after 2s.
+	witnessed1 := &witness.WitnessedAnchor{
+		AnchorID: anchor1,
+		Witnesses: []*proof.WitnessProof{
+			newWitnessProof(w1, true), newWitnessProof(w2, true), newWitnessProof(w3, false),
+		},
+		WitnessedTime: offeredTime + 2000,
+		Timings: []*witness.WitnessTiming{
+			{Witness: w1, SelectedTime: offeredTime, ProofTime: offeredTime + 2000},
+			{Witness: w2, SelectedTime: offeredTime, ProofTime: offeredTime + 5000},
+			{Witness: w3},
+		},
+	}
+
+	// Anchor2 was witnessed by w1 only (after 1s).
+	witnessed2 := &witness.WitnessedAnchor{
+		AnchorID:      anchor2,
+		Witnesses:     []*proof.WitnessProof{newWitnessProof(w1, true), newWitnessProof(w2, false)},
+		WitnessedTime: offeredTime - 9000,
+		Timings: []*witness.WitnessTiming{
+			{Witness: w1, SelectedTime: offeredTime - 10000, ProofTime: offeredTime - 9000},
+			{Witness: w2, SelectedTime: offeredTime - 10000},
+		},
+	}
+
+	// Anchor3 is pending with a proof from w3 (after 3s).
+	witnessStore := &mockWitnessStore{
+		witnessed: []*witness.WitnessedAnchor{witnessed1, witnessed2},
+		pending: map[string][]*proof.WitnessProof{
+			anchor3: {newWitnessProof(w1, false), newWitnessProof(w3, true)},
+		},
+		timings: map[string][]*witness.WitnessTiming{
+			anchor3: {
+				{Witness: w1, SelectedTime: offeredTime + 10000},
+				{Witness: w3, SelectedTime: offeredTime + 10000, ProofTime: offeredTime + 13000},
+			},
+		},
+	}
+
+	statusStore := &mockStatusStore{anchors: []string{anchor3, "hl:uEiExpired"}}
+
+	s := New(&Providers{
+		WitnessStore:  witnessStore,
+		StatusStore:   statusStore,
+		WitnessPolicy: wp,
+	})
+
+	t.Run("all proofs required", func(t *testing.T) {
+		report, err := s.Simulate("MinPercent(100,system)", 10)
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Total)
+		require.Equal(t, 0, report.Completed)
+		require.Equal(t, 3, report.Stalled)
+		require.Zero(t, report.AverageDuration)
+		require.Equal(t, 1500*time.Millisecond, report.ActualAverageDuration)
+
+		// The most recently offered anchor is first.
+		require.Equal(t, anchor3, report.Anchors[0].AnchorID)
+		require.True(t, report.Anchors[0].Pending)
+		require.Equal(t, anchor1, report.Anchors[1].AnchorID)
+		require.Equal(t, anchor2, report.Anchors[2].AnchorID)
+	})
+
+	t.Run("two proofs required", func(t *testing.T) {
+		report, err := s.Simulate("OutOf(2,system)", 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Completed)
+		require.Equal(t, 2, report.Stalled)
+		require.Equal(t, 5*time.Second, report.AverageDuration)
+		require.Equal(t, 5*time.Second, report.MaxDuration)
+
+		result := report.Anchors[1]
+		require.Equal(t, anchor1, result.AnchorID)
+		require.Equal(t, StatusCompleted, result.Status)
+		require.Equal(t, 2, result.ProofsRequired)
+		require.Equal(t, 5*time.Second, result.Duration)
+		require.Equal(t, 2*time.Second, result.ActualDuration)
+	})
+
+	t.Run("one proof required", func(t *testing.T) {
+		report, err := s.Simulate("OutOf(1,system)", 10)
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Completed)
+		require.Equal(t, 0, report.Stalled)
+		require.Equal(t, 2*time.Second, report.AverageDuration)
+		require.Equal(t, 3*time.Second, report.MaxDuration)
+	})
+
+	t.Run("no proofs required", func(t *testing.T) {
+		report, err := s.Simulate("OutOf(0,system)", 10)
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Completed)
+		require.Zero(t, report.Anchors[0].ProofsRequired)
+		require.Zero(t, report.AverageDuration)
+	})
+
+	t.Run("max anchors", func(t *testing.T) {
+		report, err := s.Simulate("OutOf(1,system)", 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Total)
+		require.Equal(t, anchor3, report.Anchors[0].AnchorID)
+
+		report, err = s.Simulate("OutOf(1,system)", 0)
+		require.NoError(t, err)
+		require.Zero(t, report.Total)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		_, err := s.Simulate("OutOf(1,xxx)", 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid witness policy")
+	})
+
+	t.Run("status store error", func(t *testing.T) {
+		s := New(&Providers{
+			WitnessStore:  witnessStore,
+			StatusStore:   &mockStatusStore{err: errors.New("injected status error")},
+			WitnessPolicy: wp,
+		})
+
+		_, err := s.Simulate("OutOf(1,system)", 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected status error")
+	})
+
+	t.Run("witness store errors", func(t *testing.T) {
+		s := New(&Providers{
+			WitnessStore:  &mockWitnessStore{err: errors.New("injected witness error")},
+			StatusStore:   &mockStatusStore{},
+			WitnessPolicy: wp,
+		})
+
+		_, err := s.Simulate("OutOf(1,system)", 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected witness error")
+
+		s = New(&Providers{
+			WitnessStore: &mockWitnessStore{
+				pending:   witnessStore.pending,
+				timingErr: errors.New("injected timing error"),
+			},
+			StatusStore:   statusStore,
+			WitnessPolicy: wp,
+		})
+
+		_, err = s.Simulate("OutOf(1,system)", 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected timing error")
+	})
+
+	t.Run("evaluate error", func(t *testing.T) {
+		s := New(&Providers{
+			WitnessStore:  witnessStore,
+			StatusStore:   statusStore,
+			WitnessPolicy: &mockWitnessPolicy{err: errors.New("injected evaluate error")},
+		})
+
+		_, err := s.Simulate("OutOf(1,system)", 10)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected evaluate error")
+	})
+}
+
+func newWitnessProof(uri string, withProof bool) *proof.WitnessProof {
+	wp := &proof.WitnessProof{
+		Witness: &proof.Witness{
+			Type:     proof.WitnessTypeSystem,
+			URI:      vocab.NewURLProperty(testutil.MustParseURL(uri)),
+			Selected: true,
+		},
+	}
+
+	if withProof {
+		wp.Proof = []byte("proof")
+	}
+
+	return wp
+}
+
+type mockWitnessStore struct {
+	witnessed []*witness.WitnessedAnchor
+	pending   map[string][]*proof.WitnessProof
+	timings   map[string][]*witness.WitnessTiming
+	err       error
+	timingErr error
+}
+
+func (m *mockWitnessStore) Get(anchorID string) ([]*proof.WitnessProof, error) {
+	witnesses, ok := m.pending[anchorID]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return witnesses, nil
+}
+
+func (m *mockWitnessStore) GetTimings(anchorID string) ([]*witness.WitnessTiming, error) {
+	return m.timings[anchorID], m.timingErr
+}
+
+func (m *mockWitnessStore) GetRecentWitnessedAnchors(maxAnchors int) ([]*witness.WitnessedAnchor, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if len(m.witnessed) > maxAnchors {
+		return m.witnessed[:maxAnchors], nil
+	}
+
+	return m.witnessed, nil
+}
+
+type mockStatusStore struct {
+	anchors []string
+	err     error
+}
+
+func (m *mockStatusStore) GetInProcessAnchors() ([]string, error) {
+	return m.anchors, m.err
+}
+
+type mockWitnessPolicy struct {
+	err error
+}
+
+func (m *mockWitnessPolicy) EvaluatePolicy(string, []*proof.WitnessProof) (bool, error) {
+	return false, m.err
+}
@@ -0,0 +1,341 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rewitness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-go/pkg/canonicalizer"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/datauri"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/linkset"
+	witnessstore "github.com/trustbloc/orb/pkg/store/witness"
+	"github.com/trustbloc/orb/pkg/vct"
+)
+
+var logger = log.New("rewitness")
+
+// Status is the outcome of re-witnessing an anchor.
+type Status string
+
+const (
+	// StatusPublished indicates that the witness policy was satisfied by the remaining proofs, so the
+	// anchor was published without the revoked proof.
+	StatusPublished Status = "published"
+	// StatusReOffered indicates that the anchor was offered to additional witnesses in order to obtain
+	// replacement proofs.
+	StatusReOffered Status = "re-offered"
+	// StatusNoWitnesses indicates that no additional witnesses could be selected for the anchor.
+	StatusNoWitnesses Status = "no-witnesses"
+	// StatusFailed indicates that an error occurred while re-witnessing the anchor.
+	StatusFailed Status = "failed"
+)
+
+// Result contains the anchors that were affected by the revocation of a witness.
+type Result struct {
+	Witness string `json:"witness"`
+	// PendingAnchors contains the anchors that are currently being witnessed and from which the proofs
+	// of the revoked witness were removed.
+	PendingAnchors []string `json:"pendingAnchors,omitempty"`
+	// Anchors contains the witnessed anchors that relied on a revoked proof.
+	Anchors []*AnchorResult `json:"anchors,omitempty"`
+}
+
+// AnchorResult contains the outcome of re-witnessing a single anchor.
+//
+//nolint:tagliatelle
+type AnchorResult struct {
+	AnchorID string `json:"anchorID"`
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+type witnessStore interface {
+	Revoke(witness *url.URL, since time.Time, reason string) ([]string, error)
+	GetWitnessedAnchors(witness *url.URL) ([]*witnessstore.WitnessedAnchor, error)
+	Restore(anchorID string, witnesses []*proof.WitnessProof, scope *policycfg.AnchorScope) error
+}
+
+type anchorGraph interface {
+	Read(hl string) (*linkset.Linkset, error)
+}
+
+type anchorLinkStore interface {
+	Put(anchorLink *linkset.Link) error
+}
+
+type statusStore interface {
+	Reopen(anchorID string) error
+}
+
+type proofHandler interface {
+	ReevaluatePolicy(ctx context.Context, anchorID string) (bool, error)
+}
+
+type policyHandler interface {
+	CheckPolicy(anchorID string) error
+}
+
+// Providers contains the providers required by the service.
+type Providers struct {
+	WitnessStore    witnessStore
+	VCStore         storage.Store
+	AnchorGraph     anchorGraph
+	AnchorLinkStore anchorLinkStore
+	StatusStore     statusStore
+	ProofHandler    proofHandler
+	PolicyHandler   policyHandler
+	DocLoader       ld.DocumentLoader
+}
+
+// Service revokes the proofs of a witness and re-witnesses the anchors that relied on those proofs.
+type Service struct {
+	*Providers
+
+	dataURIMediaType datauri.MediaType
+}
+
+// New returns a new re-witness service.
+func New(providers *Providers, dataURIMediaType datauri.MediaType) *Service {
+	return &Service{
+		Providers:        providers,
+		dataURIMediaType: dataURIMediaType,
+	}
+}
+
+// Revoke marks the proofs of the given witness that were created on or after the given time as untrusted.
+// The proofs are removed from the anchors that are currently being witnessed. Anchors that have already been
+// witnessed and that rely on a revoked proof are re-opened: the revoked proof is removed from the anchor's
+// verifiable credential and, if the remaining proofs don't satisfy the witness policy, the anchor is offered
+// to additional witnesses. Once the witness policy is satisfied, the updated anchor linkset is published.
+func (s *Service) Revoke(ctx context.Context, witness *url.URL, since time.Time, reason string) (*Result, error) {
+	pending, err := s.WitnessStore.Revoke(witness, since, reason)
+	if err != nil {
+		return nil, fmt.Errorf("revoke witness[%s]: %w", witness, err)
+	}
+
+	anchors, err := s.WitnessStore.GetWitnessedAnchors(witness)
+	if err != nil {
+		return nil, fmt.Errorf("get witnessed anchors for witness[%s]: %w", witness, err)
+	}
+
+	result := &Result{
+		Witness:        witness.String(),
+		PendingAnchors: pending,
+	}
+
+	for _, anchor := range anchors {
+		revoked, e := isRevoked(anchor.Proof(witness), since)
+		if e != nil {
+			// Err on the side of caution and re-witness the anchor.
+			logger.Warn("Unable to determine whether the proof of a revoked witness applies to the anchor",
+				logfields.WithAnchorURIString(anchor.AnchorID), logfields.WithWitnessURI(witness), log.WithError(e))
+		} else if !revoked {
+			logger.Debug("Proof of revoked witness was created before the revocation time. Anchor will not be re-witnessed.",
+				logfields.WithAnchorURIString(anchor.AnchorID), logfields.WithWitnessURI(witness))
+
+			continue
+		}
+
+		status, e := s.rewitness(ctx, witness, anchor)
+		if e != nil {
+			logger.Error("Error re-witnessing anchor", logfields.WithAnchorURIString(anchor.AnchorID),
+				logfields.WithWitnessURI(witness), log.WithError(e))
+
+			result.Anchors = append(result.Anchors, &AnchorResult{
+				AnchorID: anchor.AnchorID,
+				Status:   StatusFailed,
+				Error:    e.Error(),
+			})
+
+			continue
+		}
+
+		logger.Info("Re-witnessed anchor", logfields.WithAnchorURIString(anchor.AnchorID),
+			logfields.WithWitnessURI(witness), logfields.WithStatus(string(status)))
+
+		result.Anchors = append(result.Anchors, &AnchorResult{
+			AnchorID: anchor.AnchorID,
+			Status:   status,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Service) rewitness(ctx context.Context, witness *url.URL, anchor *witnessstore.WitnessedAnchor) (Status, error) {
+	anchorLink, err := s.removeProof(anchor, anchor.Proof(witness))
+	if err != nil {
+		return "", err
+	}
+
+	// The witnesses are restored before the anchor link is rewritten. Each of the following steps overwrites
+	// the previous state of the anchor, so if a step fails then the anchor may be re-witnessed again.
+	err = s.restoreWitnesses(witness, anchor)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.AnchorLinkStore.Put(anchorLink)
+	if err != nil {
+		return "", fmt.Errorf("store anchor link: %w", err)
+	}
+
+	err = s.StatusStore.Reopen(anchor.AnchorID)
+	if err != nil {
+		return "", fmt.Errorf("reopen anchor: %w", err)
+	}
+
+	satisfied, err := s.ProofHandler.ReevaluatePolicy(ctx, anchor.AnchorID)
+	if err != nil {
+		return "", fmt.Errorf("re-evaluate witness policy: %w", err)
+	}
+
+	if satisfied {
+		return StatusPublished, nil
+	}
+
+	err = s.PolicyHandler.CheckPolicy(anchor.AnchorID)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrWitnessesNotFound) {
+			return StatusNoWitnesses, nil
+		}
+
+		return "", fmt.Errorf("check witness policy: %w", err)
+	}
+
+	return StatusReOffered, nil
+}
+
+// removeProof returns the published anchor link with the given proof removed from the verifiable credential.
+func (s *Service) removeProof(anchor *witnessstore.WitnessedAnchor, proofBytes []byte) (*linkset.Link, error) {
+	vcBytes, err := s.VCStore.Get(anchor.VCID)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, fmt.Errorf("get verifiable credential[%s]: %w", anchor.VCID, orberrors.ErrContentNotFound)
+		}
+
+		return nil, orberrors.NewTransientf("get verifiable credential[%s]: %w", anchor.VCID, err)
+	}
+
+	vc, err := verifiable.ParseCredential(vcBytes,
+		verifiable.WithDisabledProofCheck(),
+		verifiable.WithJSONLDDocumentLoader(s.DocLoader),
+		verifiable.WithStrictValidation(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse verifiable credential[%s]: %w", anchor.VCID, err)
+	}
+
+	var revokedProof vct.Proof
+
+	err = json.Unmarshal(proofBytes, &revokedProof)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal revoked proof: %w", err)
+	}
+
+	revokedProofs := revokedProof.Proofs()
+
+	var proofs []verifiable.Proof
+
+	for _, p := range vc.Proofs {
+		if !containsProof(revokedProofs, p) {
+			proofs = append(proofs, p)
+		}
+	}
+
+	if len(proofs) == len(vc.Proofs) {
+		logger.Warn("Revoked proof not found in verifiable credential of anchor",
+			logfields.WithAnchorURIString(anchor.AnchorID), logfields.WithVerifiableCredentialID(vc.ID))
+	}
+
+	vc.Proofs = proofs
+
+	anchorLinkset, err := s.AnchorGraph.Read(anchor.AnchorLinksetHL)
+	if err != nil {
+		return nil, fmt.Errorf("read anchor linkset[%s]: %w", anchor.AnchorLinksetHL, err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, fmt.Errorf("anchor linkset[%s] is empty", anchor.AnchorLinksetHL)
+	}
+
+	vcBytes, err = canonicalizer.MarshalCanonical(vc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal verifiable credential[%s]: %w", vc.ID, err)
+	}
+
+	vcDataURI, err := datauri.New(vcBytes, s.dataURIMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("create data URI from VC: %w", err)
+	}
+
+	return linkset.NewLink(
+		anchorLink.Anchor(), anchorLink.Author(), anchorLink.Profile(),
+		anchorLink.Original(), anchorLink.Related(),
+		linkset.NewReference(vcDataURI, linkset.TypeJSONLD),
+	), nil
+}
+
+// restoreWitnesses replaces the witnesses (and policy scope) of the anchor with the witnesses of the witnessed
+// anchor (excluding the revoked witness) so that the anchor may be witnessed again. The witnesses that provided a proof remain selected and the remaining witnesses are
+// candidates for re-selection.
+func (s *Service) restoreWitnesses(revokedWitness *url.URL, anchor *witnessstore.WitnessedAnchor) error {
+	var witnesses []*proof.WitnessProof
+
+	for _, w := range anchor.Witnesses {
+		if w.URI.String() == revokedWitness.String() {
+			continue
+		}
+
+		witnesses = append(witnesses, &proof.WitnessProof{
+			Witness: &proof.Witness{
+				Type:     w.Type,
+				URI:      w.URI,
+				HasLog:   w.HasLog,
+				Selected: w.Proof != nil,
+			},
+			Proof: w.Proof,
+		})
+	}
+
+	err := s.WitnessStore.Restore(anchor.AnchorID, witnesses, anchor.Scope)
+	if err != nil {
+		return fmt.Errorf("restore witnesses: %w", err)
+	}
+
+	return nil
+}
+
+func containsProof(proofs []verifiable.Proof, proof verifiable.Proof) bool {
+	for _, p := range proofs {
+		if reflect.DeepEqual(p, proof) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRevoked(proofBytes []byte, since time.Time) (bool, error) {
+	return (&witnessstore.Revocation{Since: since}).AppliesToProof(proofBytes)
+}
@@ -0,0 +1,345 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rewitness
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/util"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	"github.com/trustbloc/orb/pkg/datauri"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+	witnessstore "github.com/trustbloc/orb/pkg/store/witness"
+)
+
+const (
+	anchorID        = "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw"
+	anchorLinksetHL = "hl:uEiC6PTR6rRVbrvx2g06lYRwBDwWvO-8ZZdqBuvXUvYgBWg"
+	vcID            = "d53b1df9-1acf-4389-a006-0f88496afe46"
+)
+
+func TestService_Revoke(t *testing.T) {
+	revokedWitness := testutil.MustParseURL("https://orb.domain2.com")
+	otherWitness := testutil.MustParseURL("http://orb.vct:8077/maple2020")
+	candidateWitness := testutil.MustParseURL("https://orb.domain3.com")
+
+	// The revoked proof was created on 2022-03-15.
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	witnessedAnchor := &witnessstore.WitnessedAnchor{
+		AnchorID:        anchorID,
+		VCID:            vcID,
+		AnchorLinksetHL: anchorLinksetHL,
+		Scope:           &policycfg.AnchorScope{Namespace: "did:orb", Origins: []string{"https://orb.domain1.com"}},
+		Witnesses: []*proof.WitnessProof{
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(otherWitness), Selected: true},
+				Proof:   []byte(otherProof),
+			},
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(revokedWitness), Selected: true},
+				Proof:   []byte(revokedProof),
+			},
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(candidateWitness)},
+			},
+		},
+	}
+
+	newService := func(t *testing.T) (*Service, *mockProviders) {
+		t.Helper()
+
+		vcStore, err := mem.NewProvider().OpenStore("verifiable")
+		require.NoError(t, err)
+		require.NoError(t, vcStore.Put(vcID, []byte(vcWithTwoProofs)))
+
+		m := &mockProviders{
+			pending:   []string{"hl:pending"},
+			witnessed: []*witnessstore.WitnessedAnchor{witnessedAnchor},
+		}
+
+		return New(&Providers{
+			WitnessStore:    m,
+			VCStore:         vcStore,
+			AnchorGraph:     m,
+			AnchorLinkStore: m,
+			StatusStore:     m,
+			ProofHandler:    m,
+			PolicyHandler:   m,
+			DocLoader:       testutil.GetLoader(t),
+		}, datauri.MediaTypeDataURIGzipBase64), m
+	}
+
+	t.Run("published", func(t *testing.T) {
+		s, m := newService(t)
+		m.satisfied = true
+
+		result, err := s.Revoke(context.Background(), revokedWitness, since, "key compromised")
+		require.NoError(t, err)
+		require.Equal(t, revokedWitness.String(), result.Witness)
+		require.Equal(t, []string{"hl:pending"}, result.PendingAnchors)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, anchorID, result.Anchors[0].AnchorID)
+		require.Equal(t, StatusPublished, result.Anchors[0].Status)
+
+		require.Equal(t, "key compromised", m.reason)
+		require.Equal(t, anchorID, m.reopened)
+		require.Equal(t, anchorID, m.restoredID)
+		require.Equal(t, witnessedAnchor.Scope, m.scope)
+
+		// The revoked witness is removed and the witness without a proof is a candidate for re-selection.
+		require.Len(t, m.restored, 2)
+		require.Equal(t, otherWitness.String(), m.restored[0].URI.String())
+		require.True(t, m.restored[0].Selected)
+		require.Equal(t, candidateWitness.String(), m.restored[1].URI.String())
+		require.False(t, m.restored[1].Selected)
+
+		require.NotNil(t, m.anchorLink)
+		require.Equal(t, anchorID, m.anchorLink.Anchor().String())
+
+		vc, err := util.VerifiableCredentialFromAnchorLink(m.anchorLink,
+			verifiable.WithDisabledProofCheck(),
+			verifiable.WithJSONLDDocumentLoader(testutil.GetLoader(t)),
+		)
+		require.NoError(t, err)
+		require.Len(t, vc.Proofs, 1)
+		require.Equal(t, "http://orb.vct:8077/maple2020", vc.Proofs[0]["domain"])
+	})
+
+	t.Run("re-offered", func(t *testing.T) {
+		s, _ := newService(t)
+
+		result, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.NoError(t, err)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, StatusReOffered, result.Anchors[0].Status)
+	})
+
+	t.Run("no witnesses", func(t *testing.T) {
+		s, m := newService(t)
+		m.checkPolicyErr = orberrors.ErrWitnessesNotFound
+
+		result, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.NoError(t, err)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, StatusNoWitnesses, result.Anchors[0].Status)
+	})
+
+	t.Run("proof created before revocation -> not affected", func(t *testing.T) {
+		s, m := newService(t)
+
+		result, err := s.Revoke(context.Background(), revokedWitness, time.Now(), "")
+		require.NoError(t, err)
+		require.Empty(t, result.Anchors)
+		require.Empty(t, m.reopened)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		s, m := newService(t)
+		m.reevaluateErr = errors.New("injected re-evaluate error")
+
+		result, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.NoError(t, err)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, StatusFailed, result.Anchors[0].Status)
+		require.Contains(t, result.Anchors[0].Error, "injected re-evaluate error")
+	})
+
+	t.Run("failed - restore witnesses", func(t *testing.T) {
+		s, m := newService(t)
+		m.restoreErr = errors.New("injected restore error")
+
+		result, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.NoError(t, err)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, StatusFailed, result.Anchors[0].Status)
+		require.Contains(t, result.Anchors[0].Error, "injected restore error")
+
+		// The anchor link isn't rewritten and the anchor isn't reopened so that the anchor may be re-witnessed again.
+		require.Nil(t, m.anchorLink)
+		require.Empty(t, m.reopened)
+
+		// Retry.
+		m.restoreErr = nil
+
+		result, err = s.Revoke(context.Background(), revokedWitness, since, "")
+		require.NoError(t, err)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, StatusReOffered, result.Anchors[0].Status)
+		require.NotNil(t, m.anchorLink)
+		require.Len(t, m.restored, 2)
+	})
+
+	t.Run("failed - VC not found", func(t *testing.T) {
+		s, _ := newService(t)
+
+		vcStore, err := mem.NewProvider().OpenStore("verifiable")
+		require.NoError(t, err)
+
+		s.VCStore = vcStore
+
+		result, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.NoError(t, err)
+		require.Len(t, result.Anchors, 1)
+		require.Equal(t, StatusFailed, result.Anchors[0].Status)
+		require.Contains(t, result.Anchors[0].Error, "content not found")
+	})
+
+	t.Run("error - revoke", func(t *testing.T) {
+		s, m := newService(t)
+		m.revokeErr = errors.New("injected revoke error")
+
+		_, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected revoke error")
+	})
+
+	t.Run("error - get witnessed anchors", func(t *testing.T) {
+		s, m := newService(t)
+		m.getWitnessedErr = errors.New("injected get error")
+
+		_, err := s.Revoke(context.Background(), revokedWitness, since, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected get error")
+	})
+}
+
+type mockProviders struct {
+	pending         []string
+	witnessed       []*witnessstore.WitnessedAnchor
+	revokeErr       error
+	getWitnessedErr error
+	reevaluateErr   error
+	checkPolicyErr  error
+	restoreErr      error
+	satisfied       bool
+
+	reason     string
+	restoredID string
+	restored   []*proof.WitnessProof
+	reopened   string
+	anchorLink *linkset.Link
+	scope      *policycfg.AnchorScope
+}
+
+func (m *mockProviders) Revoke(_ *url.URL, _ time.Time, reason string) ([]string, error) {
+	m.reason = reason
+
+	return m.pending, m.revokeErr
+}
+
+func (m *mockProviders) GetWitnessedAnchors(*url.URL) ([]*witnessstore.WitnessedAnchor, error) {
+	return m.witnessed, m.getWitnessedErr
+}
+
+func (m *mockProviders) Restore(anchorID string, witnesses []*proof.WitnessProof,
+	scope *policycfg.AnchorScope,
+) error {
+	if m.restoreErr != nil {
+		return m.restoreErr
+	}
+
+	m.restoredID = anchorID
+	m.restored = witnesses
+	m.scope = scope
+
+	return nil
+}
+
+func (m *mockProviders) Read(string) (*linkset.Linkset, error) {
+	return linkset.New(linkset.NewLink(
+		testutil.MustParseURL(anchorID),
+		testutil.MustParseURL("https://orb.domain1.com/services/orb"),
+		testutil.MustParseURL("https://w3id.org/orb#v0"),
+		nil, nil, nil,
+	)), nil
+}
+
+func (m *mockProviders) Put(anchorLink *linkset.Link) error {
+	m.anchorLink = anchorLink
+
+	return nil
+}
+
+func (m *mockProviders) Reopen(anchorID string) error {
+	m.reopened = anchorID
+
+	return nil
+}
+
+func (m *mockProviders) ReevaluatePolicy(context.Context, string) (bool, error) {
+	return m.satisfied, m.reevaluateErr
+}
+
+func (m *mockProviders) CheckPolicy(string) error {
+	return m.checkPolicyErr
+}
+
+const (
+	vcWithTwoProofs = `{
+  "@context": ["https://www.w3.org/2018/credentials/v1", "https://w3id.org/security/suites/ed25519-2020/v1"],
+  "credentialSubject": "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw",
+  "id": "https://orb.domain1.com/vc/d53b1df9-1acf-4389-a006-0f88496afe46",
+  "issuanceDate": "2022-03-15T21:21:54.62437567Z",
+  "issuer": "https://orb.domain1.com",
+  "proof": [
+    {
+      "created": "2022-03-15T21:21:54.631Z",
+      "domain": "http://orb.vct:8077/maple2020",
+      "proofPurpose": "assertionMethod",
+      "proofValue": "gRPF8XAA4iYMwl26RmFGUoN99wuUnD_igmvIlzzDpPRLVDtmA8wrNbUdJIAKKhyMJFju8OjciSGYMY_bDRjBAw",
+      "type": "Ed25519Signature2020",
+      "verificationMethod": "did:web:orb.domain1.com#orb1key2"
+    },
+    {
+      "created": "2022-03-15T21:21:54.744899145Z",
+      "domain": "https://orb.domain2.com",
+      "proofPurpose": "assertionMethod",
+      "proofValue": "FX58osRrwU11IrUfhVTi0ucrNEq05Cv94CQNvd8SdoY66fAjwU2--m8plvxwVnXmxnlV23i6htkq4qI8qrDgAA",
+      "type": "Ed25519Signature2020",
+      "verificationMethod": "did:web:orb.domain2.com#orb2key"
+    }
+  ],
+  "type": "VerifiableCredential"
+}`
+
+	otherProof = `{
+  "@context": ["https://w3id.org/security/suites/ed25519-2020/v1"],
+  "proof": {
+    "created": "2022-03-15T21:21:54.631Z",
+    "domain": "http://orb.vct:8077/maple2020",
+    "proofPurpose": "assertionMethod",
+    "proofValue": "gRPF8XAA4iYMwl26RmFGUoN99wuUnD_igmvIlzzDpPRLVDtmA8wrNbUdJIAKKhyMJFju8OjciSGYMY_bDRjBAw",
+    "type": "Ed25519Signature2020",
+    "verificationMethod": "did:web:orb.domain1.com#orb1key2"
+  }
+}`
+
+	revokedProof = `{
+  "@context": ["https://w3id.org/security/suites/ed25519-2020/v1"],
+  "proof": {
+    "created": "2022-03-15T21:21:54.744899145Z",
+    "domain": "https://orb.domain2.com",
+    "proofPurpose": "assertionMethod",
+    "proofValue": "FX58osRrwU11IrUfhVTi0ucrNEq05Cv94CQNvd8SdoY66fAjwU2--m8plvxwVnXmxnlV23i6htkq4qI8qrDgAA",
+    "type": "Ed25519Signature2020",
+    "verificationMethod": "did:web:orb.domain2.com#orb2key"
+  }
+}`
+)
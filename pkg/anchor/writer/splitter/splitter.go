@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package splitter
+
+import (
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-svc-go/pkg/batch/cutter"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+var logger = log.New("anchor-batch-splitter")
+
+// Split reasons.
+const (
+	// ReasonMaxOperations indicates that the batch exceeded the maximum number of operations per anchor.
+	ReasonMaxOperations = "max-operations"
+	// ReasonMaxSize indicates that the batch exceeded the maximum size of an anchor.
+	ReasonMaxSize = "max-size"
+	// ReasonMaxWitnesses indicates that the batch exceeded the maximum number of distinct batch witnesses per anchor.
+	ReasonMaxWitnesses = "max-witnesses"
+)
+
+type metricsProvider interface {
+	WriteAnchorIncrementBatchSplitCount(reason string)
+}
+
+// Config holds the limits that are applied to each anchor. A value of zero (or less) disables the
+// corresponding limit.
+type Config struct {
+	// MaxOperations is the maximum number of operations in an anchor.
+	MaxOperations int
+	// MaxSize is the maximum total size (in bytes) of the operation requests in an anchor, which is an
+	// estimate of the serialized size of the anchor's batch files.
+	MaxSize int
+	// MaxWitnesses is the maximum number of distinct batch witnesses (i.e. anchor origins) in an anchor.
+	MaxWitnesses int
+}
+
+// Enabled returns true if any of the limits is enabled.
+func (c *Config) Enabled() bool {
+	return c.MaxOperations > 0 || c.MaxSize > 0 || c.MaxWitnesses > 0
+}
+
+// Queue wraps the queue of pending operations from which the batch writer cuts batches. Peek only returns the
+// operations at the head of the queue that fit within the configured limits, so a batch that would exceed the limits
+// is cut short and the remaining operations stay at the head of the queue for the next batch (i.e. the next anchor).
+// Since the remaining operations are never removed from the queue, the order of the operations for each suffix is
+// preserved.
+type Queue struct {
+	cutter.OperationQueue
+	*Config
+
+	metrics metricsProvider
+}
+
+// NewQueue returns a new operation queue that cuts batches which exceed the configured limits.
+func NewQueue(target cutter.OperationQueue, cfg Config, metrics metricsProvider) *Queue {
+	return &Queue{
+		OperationQueue: target,
+		Config:         &cfg,
+		metrics:        metrics,
+	}
+}
+
+// Peek returns (up to) the given number of operations from the head of the queue that fit within the configured
+// limits. The operations are not removed from the queue.
+func (q *Queue) Peek(num uint) (operation.QueuedOperationsAtTime, error) {
+	ops, err := q.OperationQueue.Peek(num)
+	if err != nil {
+		return nil, err
+	}
+
+	n, reason := q.cut(ops)
+
+	if n < len(ops) {
+		logger.Info("Cutting batch short since it exceeds the anchor limits. The remaining operations will be "+
+			"included in the next anchor.", logfields.WithReason(reason), logfields.WithTotal(n),
+			logfields.WithTotalDeferred(len(ops)-n), logfields.WithConfig(q.Config))
+
+		q.metrics.WriteAnchorIncrementBatchSplitCount(reason)
+	}
+
+	return ops[:n], nil
+}
+
+// cut returns the number of operations at the head of the given operations that fit within the limits
+// and the reason for cutting the remaining operations.
+func (q *Queue) cut(ops operation.QueuedOperationsAtTime) (int, string) {
+	origins := make(map[string]struct{})
+	size := 0
+
+	for i, op := range ops {
+		if reason := q.exceedsLimit(&op.QueuedOperation, i, size, origins); reason != "" {
+			return i, reason
+		}
+
+		size += len(op.OperationRequest)
+
+		if origin, ok := op.AnchorOrigin.(string); ok {
+			origins[origin] = struct{}{}
+		}
+	}
+
+	return len(ops), ""
+}
+
+// exceedsLimit returns the reason if adding the given operation to the preceding operations would exceed
+// one of the limits. The first operation is always included so that progress is made.
+func (q *Queue) exceedsLimit(op *operation.QueuedOperation, numIncluded, size int,
+	origins map[string]struct{},
+) string {
+	if numIncluded == 0 {
+		return ""
+	}
+
+	if q.MaxOperations > 0 && numIncluded >= q.MaxOperations {
+		return ReasonMaxOperations
+	}
+
+	if q.MaxSize > 0 && size+len(op.OperationRequest) > q.MaxSize {
+		return ReasonMaxSize
+	}
+
+	if q.MaxWitnesses > 0 {
+		if origin, ok := op.AnchorOrigin.(string); ok {
+			if _, exists := origins[origin]; !exists && len(origins) >= q.MaxWitnesses {
+				return ReasonMaxWitnesses
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package splitter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-svc-go/pkg/api/operation"
+	"github.com/trustbloc/sidetree-svc-go/pkg/batch/cutter"
+	"github.com/trustbloc/sidetree-svc-go/pkg/batch/opqueue"
+	svcmocks "github.com/trustbloc/sidetree-svc-go/pkg/mocks"
+)
+
+const (
+	origin1 = "https://orb.domain1.com/services/orb"
+	origin2 = "https://orb.domain2.com/services/orb"
+	origin3 = "https://orb.domain3.com/services/orb"
+)
+
+func TestConfig_Enabled(t *testing.T) {
+	require.False(t, (&Config{}).Enabled())
+	require.True(t, (&Config{MaxOperations: 1}).Enabled())
+	require.True(t, (&Config{MaxSize: 1}).Enabled())
+	require.True(t, (&Config{MaxWitnesses: 1}).Enabled())
+}
+
+func TestQueue_Peek(t *testing.T) {
+	t.Run("no limits", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		q := newQueue(t, Config{}, metrics, newOps(10, origin1)...)
+
+		ops, err := q.Peek(10)
+		require.NoError(t, err)
+		require.Len(t, ops, 10)
+		require.Empty(t, metrics.counts)
+	})
+
+	t.Run("max operations", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		q := newQueue(t, Config{MaxOperations: 3}, metrics, newOps(5, origin1)...)
+
+		ops, err := q.Peek(5)
+		require.NoError(t, err)
+		require.Len(t, ops, 3)
+		require.Equal(t, 1, metrics.counts[ReasonMaxOperations])
+		require.Equal(t, uint(5), q.Len())
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		q := newQueue(t, Config{MaxSize: 100}, metrics,
+			newOp("suffix1", origin1, 60),
+			newOp("suffix2", origin1, 60), // exceeds the size
+			newOp("suffix3", origin1, 40),
+		)
+
+		ops, err := q.Peek(3)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		require.Equal(t, "suffix1", ops[0].UniqueSuffix)
+		require.Equal(t, 1, metrics.counts[ReasonMaxSize])
+	})
+
+	t.Run("first operation is always included", func(t *testing.T) {
+		q := newQueue(t, Config{MaxSize: 100}, &mockMetrics{}, newOp("suffix1", origin1, 200))
+
+		ops, err := q.Peek(1)
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+	})
+
+	t.Run("max witnesses", func(t *testing.T) {
+		metrics := &mockMetrics{}
+
+		q := newQueue(t, Config{MaxWitnesses: 2}, metrics,
+			newOp("suffix1", origin1, 10),
+			newOp("suffix2", origin2, 10),
+			newOp("suffix3", origin1, 10), // existing witness
+			newOp("suffix4", nil, 10),     // unknown anchor origin
+			newOp("suffix5", origin3, 10), // exceeds the number of witnesses
+			newOp("suffix6", origin1, 10),
+		)
+
+		ops, err := q.Peek(6)
+		require.NoError(t, err)
+		require.Len(t, ops, 4)
+		require.Equal(t, 1, metrics.counts[ReasonMaxWitnesses])
+	})
+
+	t.Run("peek error", func(t *testing.T) {
+		q := NewQueue(&mockQueue{err: errors.New("injected peek error")}, Config{MaxOperations: 1}, &mockMetrics{})
+
+		_, err := q.Peek(1)
+		require.EqualError(t, err, "injected peek error")
+	})
+}
+
+func TestQueue_Cut(t *testing.T) {
+	pc := svcmocks.NewMockProtocolClient()
+	pc.Protocol.MaxOperationCount = 3
+	pc.CurrentVersion.ProtocolReturns(pc.Protocol)
+
+	// The batch cutter takes up to three operations at a time but the anchor is limited to two witnesses.
+	// The operations that are cut must remain at the head of the queue (ahead of the pending operations
+	// for the same suffix) so that the order of the operations for each suffix is preserved.
+	q := newQueue(t, Config{MaxWitnesses: 1}, &mockMetrics{},
+		newOp("suffix1", origin1, 10),
+		newOp("suffix2", origin2, 10), // exceeds the number of witnesses
+		newOp("suffix3", origin2, 10),
+		newOp("suffix2", origin1, 10), // pending behind the cut
+		newOp("suffix3", origin1, 10), // pending behind the cut
+	)
+
+	c := cutter.New(pc, q)
+
+	var batches [][]string
+
+	for {
+		result, err := c.Cut(true)
+		require.NoError(t, err)
+
+		if len(result.Operations) == 0 {
+			break
+		}
+
+		var batch []string
+
+		for _, op := range result.Operations {
+			batch = append(batch, fmt.Sprintf("%s:%s", op.UniqueSuffix, op.AnchorOrigin))
+		}
+
+		batches = append(batches, batch)
+
+		result.Ack()
+	}
+
+	require.Equal(t, [][]string{
+		{"suffix1:" + origin1},
+		{"suffix2:" + origin2, "suffix3:" + origin2},
+		{"suffix2:" + origin1, "suffix3:" + origin1},
+	}, batches)
+}
+
+func newQueue(t *testing.T, cfg Config, metrics metricsProvider, ops ...*operation.QueuedOperation) *Queue {
+	t.Helper()
+
+	q := NewQueue(&opqueue.MemQueue{}, cfg, metrics)
+
+	for _, op := range ops {
+		_, err := q.Add(op, 0)
+		require.NoError(t, err)
+	}
+
+	return q
+}
+
+func newOps(n int, origin interface{}) []*operation.QueuedOperation {
+	ops := make([]*operation.QueuedOperation, n)
+
+	for i := 0; i < n; i++ {
+		ops[i] = newOp(fmt.Sprintf("suffix%d", i), origin, 10)
+	}
+
+	return ops
+}
+
+func newOp(suffix string, origin interface{}, size int) *operation.QueuedOperation {
+	return &operation.QueuedOperation{
+		UniqueSuffix:     suffix,
+		AnchorOrigin:     origin,
+		OperationRequest: make([]byte, size),
+	}
+}
+
+type mockQueue struct {
+	opqueue.MemQueue
+
+	err error
+}
+
+func (q *mockQueue) Peek(uint) (operation.QueuedOperationsAtTime, error) {
+	return nil, q.err
+}
+
+type mockMetrics struct {
+	counts map[string]int
+}
+
+func (m *mockMetrics) WriteAnchorIncrementBatchSplitCount(reason string) {
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+
+	m.counts[reason]++
+}
@@ -39,6 +39,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/subject"
 	"github.com/trustbloc/orb/pkg/anchor/util"
 	"github.com/trustbloc/orb/pkg/anchor/vcpubsub"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	"github.com/trustbloc/orb/pkg/datauri"
 	discoveryrest "github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
@@ -121,6 +122,11 @@ type Providers struct {
 	VCStore                storage.Store
 	GeneratorRegistry      generatorRegistry
 	AnchorLinkBuilder      anchorLinkBuilder
+	PeerHealth             peerHealth
+}
+
+type peerHealth interface {
+	IsAvailable(iri *url.URL) bool
 }
 
 type webfingerClient interface {
@@ -134,10 +140,13 @@ type activityStore interface {
 type witnessStore interface {
 	Put(anchorEventID string, witnesses []*proof.Witness) error
 	Delete(anchorEventID string) error
+	RecordWitnessedAnchor(anchorID, vcID, anchorLinksetHL string) error
+	PutPolicyScope(anchorID string, scope *policycfg.AnchorScope) error
 }
 
 type witnessPolicy interface {
-	Select(witnesses []*proof.Witness, exclude ...*proof.Witness) ([]*proof.Witness, error)
+	SelectForScope(scope *policycfg.AnchorScope, witnesses []*proof.Witness,
+		exclude ...*proof.Witness) ([]*proof.Witness, error)
 }
 
 type witness interface {
@@ -255,11 +264,17 @@ func (c *Writer) WriteAnchor(anchor string, attachments []*svcprotocol.AnchorDoc
 	}
 
 	// figure out witness list for this anchor file
-	batchWitnesses, err := c.getWitnessesFromBatchOperations(refs)
+	batchWitnesses, origins, err := c.getWitnessesFromBatchOperations(refs)
 	if err != nil {
 		return fmt.Errorf("failed to create witness list: %w", err)
 	}
 
+	// The scope is used to resolve the witness policies that apply to the anchor.
+	scope := &policycfg.AnchorScope{
+		Namespace: payload.Namespace,
+		Origins:   origins,
+	}
+
 	anchorLink, vcBytes, err := c.buildAnchorLink(payload, batchWitnesses)
 	if err != nil {
 		return fmt.Errorf("build anchor linkset for core index [%s]: %w", payload.CoreIndex, err)
@@ -281,7 +296,7 @@ func (c *Writer) WriteAnchor(anchor string, attachments []*svcprotocol.AnchorDoc
 	defer span.End()
 
 	// send an offer activity to witnesses (request witnessing anchor credential from non-local witness logs)
-	err = c.postOfferActivity(ctx, anchorLink, vcBytes, batchWitnesses)
+	err = c.postOfferActivity(ctx, anchorLink, vcBytes, batchWitnesses, scope)
 	if err != nil {
 		return fmt.Errorf("failed to post new offer activity for core index[%s]: %w",
 			payload.CoreIndex, err)
@@ -459,29 +474,34 @@ func (c *Writer) signCredentialWithLocalWitnessLog(vc *verifiable.Credential) (*
 		return nil, fmt.Errorf("failed to unmarshal local witness proof for anchor credential[%s]: %w", vc.ID, err)
 	}
 
-	vc.Proofs = append(vc.Proofs, witnessProof.Proof)
+	// The local witness may provide a proof from each of its logs (primary and mirrors).
+	proofs := witnessProof.Proofs()
 
-	var (
-		createdTime time.Time
-		domain      string
-	)
+	vc.Proofs = append(vc.Proofs, proofs...)
 
-	if created, ok := witnessProof.Proof["created"].(string); ok {
-		createdTime, err = time.Parse(time.RFC3339, created)
-		if err != nil {
-			return nil, fmt.Errorf("parse created: %w", err)
-		}
-	}
+	watchStartTime := time.Now()
 
-	if domainVal, ok := witnessProof.Proof["domain"].(string); ok {
-		domain = domainVal
-	}
+	for _, proof := range proofs {
+		var (
+			createdTime time.Time
+			domain      string
+		)
 
-	watchStartTime := time.Now()
+		if created, ok := proof["created"].(string); ok {
+			createdTime, err = time.Parse(time.RFC3339, created)
+			if err != nil {
+				return nil, fmt.Errorf("parse created: %w", err)
+			}
+		}
 
-	err = c.MonitoringSvc.Watch(vc, time.Now().Add(c.maxWitnessDelay), domain, createdTime)
-	if err != nil {
-		return nil, fmt.Errorf("failed to setup monitoring for local witness for anchor credential[%s]: %w", vc.ID, err)
+		if domainVal, ok := proof["domain"].(string); ok {
+			domain = domainVal
+		}
+
+		err = c.MonitoringSvc.Watch(vc, time.Now().Add(c.maxWitnessDelay), domain, createdTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup monitoring for local witness for anchor credential[%s]: %w", vc.ID, err)
+		}
 	}
 
 	c.metrics.WriteAnchorSignLocalWatchTime(time.Since(watchStartTime))
@@ -503,7 +523,7 @@ func (c *Writer) handle(ctx context.Context, anchorLinkset *linkset.Linkset) err
 		c.metrics.ProcessWitnessedAnchorCredentialTime(time.Since(startTime))
 	}()
 
-	err := c.storeVC(anchorLink)
+	vcID, err := c.storeVC(anchorLink)
 	if err != nil {
 		return fmt.Errorf("store verifiable credential from anchor event[%s]: %w", anchorLink.Anchor(), err)
 	}
@@ -521,6 +541,13 @@ func (c *Writer) handle(ctx context.Context, anchorLinkset *linkset.Linkset) err
 		return fmt.Errorf("publish anchor[%s] ref [%s]: %w", anchorLink.Anchor(), anchorLinksetHL, err)
 	}
 
+	// Keep a record of the witnesses of the anchor so that the anchor may be witnessed again
+	// if the proof of one of the witnesses is revoked.
+	err = c.WitnessStore.RecordWitnessedAnchor(anchorLink.Anchor().String(), vcID, anchorLinksetHL)
+	if err != nil {
+		logger.Warn("Error recording witnesses for anchor", logfields.WithAnchorURI(anchorLink.Anchor()), log.WithError(err))
+	}
+
 	err = c.deleteTransientData(anchorLink)
 	if err != nil {
 		// this is a clean-up task so no harm if there was an error
@@ -553,30 +580,31 @@ func (c *Writer) deleteTransientData(anchorLink *linkset.Link) error {
 	return nil
 }
 
-func (c *Writer) storeVC(anchorLink *linkset.Link) error {
+// storeVC stores the verifiable credential of the anchor and returns the key under which it was stored.
+func (c *Writer) storeVC(anchorLink *linkset.Link) (string, error) {
 	vc, err := util.VerifiableCredentialFromAnchorLink(anchorLink,
 		verifiable.WithDisabledProofCheck(),
 		verifiable.WithJSONLDDocumentLoader(c.DocumentLoader),
 		verifiable.WithStrictValidation(),
 	)
 	if err != nil {
-		return fmt.Errorf("failed get verifiable credential from anchor link: %w", err)
+		return "", fmt.Errorf("failed get verifiable credential from anchor link: %w", err)
 	}
 
 	vcBytes, err := json.Marshal(vc)
 	if err != nil {
-		return fmt.Errorf("failed to marshal vc[%s]: %w", vc.ID, err)
+		return "", fmt.Errorf("failed to marshal vc[%s]: %w", vc.ID, err)
 	}
 
 	parts := strings.Split(vc.ID, "/")
 	id := parts[len(parts)-1]
 
-	err = c.VCStore.Put(id, vcBytes)
+	err = c.VCStore.Put(id, vcBytes, storage.Tag{Name: util.VCStoreTagName})
 	if err != nil {
-		return fmt.Errorf("failed to store vc[%s]: %w", id, err)
+		return "", fmt.Errorf("failed to store vc[%s]: %w", id, err)
 	}
 
-	return nil
+	return id, nil
 }
 
 // postCreateActivity creates and posts create activity (announces anchor credential to followers).
@@ -622,7 +650,9 @@ func (c *Writer) postCreateActivity(ctx context.Context, anchorLinkset *linkset.
 }
 
 // postOfferActivity creates and posts offer activity (requests witnessing of anchor credential).
-func (c *Writer) postOfferActivity(ctx context.Context, anchorLink *linkset.Link, localProofBytes []byte, batchWitnesses []string) error {
+func (c *Writer) postOfferActivity(ctx context.Context, anchorLink *linkset.Link, localProofBytes []byte,
+	batchWitnesses []string, scope *policycfg.AnchorScope,
+) error {
 	postOfferActivityStartTime := time.Now()
 
 	defer c.metrics.WriteAnchorPostOfferActivityTime(time.Since(postOfferActivityStartTime))
@@ -630,11 +660,19 @@ func (c *Writer) postOfferActivity(ctx context.Context, anchorLink *linkset.Link
 	logger.Debug("Sending anchor linkset to system and batch witnesses",
 		logfields.WithAnchorURI(anchorLink.Anchor()), logfields.WithWitnessURIStrings(batchWitnesses...))
 
-	selectedWitnessesIRIs, allWitnesses, err := c.getWitnesses(batchWitnesses)
+	selectedWitnessesIRIs, allWitnesses, err := c.getWitnesses(batchWitnesses, scope)
 	if err != nil {
 		return fmt.Errorf("failed to get witnesses: %w", err)
 	}
 
+	if scope != nil {
+		// Store the scope before posting the offer since it's required in order to evaluate the proofs.
+		err = c.WitnessStore.PutPolicyScope(anchorLink.Anchor().String(), scope)
+		if err != nil {
+			return fmt.Errorf("store policy scope: %w", err)
+		}
+	}
+
 	selectedWitnessesIRIs = append(selectedWitnessesIRIs, vocab.PublicIRI)
 
 	startTime := time.Now()
@@ -687,36 +725,42 @@ func (c *Writer) postOfferActivity(ctx context.Context, anchorLink *linkset.Link
 	return nil
 }
 
-// getWitnessesFromBatchOperations returns the list of anchor origins for all dids in the Sidetree batch.
+// getWitnessesFromBatchOperations returns the list of witnesses (resolved from the anchor origins) and the list of
+// anchor origins for all dids in the Sidetree batch.
 // Create and recover operations contain anchor origin in operation references.
 // For update and deactivate operations we have to 'resolve' did in order to figure out anchor origin.
-func (c *Writer) getWitnessesFromBatchOperations(refs []*svcoperation.Reference) ([]string, error) {
+func (c *Writer) getWitnessesFromBatchOperations(refs []*svcoperation.Reference) ([]string, []string, error) {
 	getWitnessesStartTime := time.Now()
 
 	defer c.metrics.WriteAnchorGetWitnessesTime(time.Since(getWitnessesStartTime))
 
-	var witnesses []string
+	var witnesses, origins []string
 
 	uniqueWitnesses := make(map[string]bool)
+	uniqueOrigins := make(map[string]bool)
 
 	for _, ref := range refs {
-		resolvedWitness, err := c.resolveWitness(ref)
+		anchorOrigin, resolvedWitness, err := c.resolveWitness(ref)
 		if err != nil {
-			return nil, fmt.Errorf("resolve witness: %w", err)
+			return nil, nil, fmt.Errorf("resolve witness: %w", err)
 		}
 
-		_, ok := uniqueWitnesses[resolvedWitness]
-
-		if !ok {
+		if !uniqueWitnesses[resolvedWitness] {
 			witnesses = append(witnesses, resolvedWitness)
 			uniqueWitnesses[resolvedWitness] = true
 		}
+
+		if !uniqueOrigins[anchorOrigin] {
+			origins = append(origins, anchorOrigin)
+			uniqueOrigins[anchorOrigin] = true
+		}
 	}
 
-	return witnesses, nil
+	return witnesses, origins, nil
 }
 
-func (c *Writer) resolveWitness(ref *svcoperation.Reference) (string, error) {
+// resolveWitness returns the anchor origin of the operation and the witness that was resolved from the anchor origin.
+func (c *Writer) resolveWitness(ref *svcoperation.Reference) (string, string, error) {
 	var anchorOriginObj interface{}
 
 	switch ref.Type {
@@ -733,7 +777,7 @@ func (c *Writer) resolveWitness(ref *svcoperation.Reference) (string, error) {
 			// origin object will not be set and we have to resolve document in order to get it
 			result, err := c.OpProcessor.Resolve(ref.UniqueSuffix)
 			if err != nil {
-				return "", fmt.Errorf("resolve unique suffix [%s]: %w", ref.UniqueSuffix, err)
+				return "", "", fmt.Errorf("resolve unique suffix [%s]: %w", ref.UniqueSuffix, err)
 			}
 
 			logger.Debug("Resolved anchor origin for operation",
@@ -743,12 +787,12 @@ func (c *Writer) resolveWitness(ref *svcoperation.Reference) (string, error) {
 		}
 
 	default:
-		return "", fmt.Errorf("operation type '%s' not supported for assembling witness list", ref.Type)
+		return "", "", fmt.Errorf("operation type '%s' not supported for assembling witness list", ref.Type)
 	}
 
 	anchorOrigin, ok := anchorOriginObj.(string)
 	if !ok {
-		return "", fmt.Errorf("unexpected interface '%T' for anchor origin", anchorOriginObj)
+		return "", "", fmt.Errorf("unexpected interface '%T' for anchor origin", anchorOriginObj)
 	}
 
 	resolvedWitness := anchorOrigin
@@ -762,7 +806,7 @@ func (c *Writer) resolveWitness(ref *svcoperation.Reference) (string, error) {
 
 		resolvedWitness, err = c.resourceResolver.ResolveHostMetaLink(anchorOrigin, discoveryrest.ActivityJSONType)
 		if err != nil {
-			return "", fmt.Errorf("failed to resolve witness: %w", err)
+			return "", "", fmt.Errorf("failed to resolve witness: %w", err)
 		}
 
 		c.metrics.WriteAnchorResolveHostMetaLinkTime(time.Since(resolveStartTime))
@@ -771,7 +815,7 @@ func (c *Writer) resolveWitness(ref *svcoperation.Reference) (string, error) {
 	logger.Debug("Successfully resolved witness for anchor origin",
 		logfields.WithWitnessURIString(resolvedWitness), logfields.WithAnchorOrigin(anchorOrigin))
 
-	return resolvedWitness, nil
+	return anchorOrigin, resolvedWitness, nil
 }
 
 // Read reads transactions since transaction time.
@@ -782,8 +826,8 @@ func (c *Writer) Read(_ int) (bool, *txnapi.SidetreeTxn) {
 	return false, nil
 }
 
-func (c *Writer) getWitnesses(batchOpsWitnesses []string) (selectedWitnessesIRI []*url.URL,
-	witnesses []*proof.Witness, err error,
+func (c *Writer) getWitnesses(batchOpsWitnesses []string, scope *policycfg.AnchorScope) (
+	selectedWitnessesIRI []*url.URL, witnesses []*proof.Witness, err error,
 ) {
 	batchWitnesses, err := c.getBatchWitnesses(batchOpsWitnesses)
 	if err != nil {
@@ -798,7 +842,7 @@ func (c *Writer) getWitnesses(batchOpsWitnesses []string) (selectedWitnessesIRI
 	witnesses = append(witnesses, batchWitnesses...)
 	witnesses = append(witnesses, systemWitnesses...)
 
-	selectedWitnesses, err := c.WitnessPolicy.Select(witnesses)
+	selectedWitnesses, err := c.selectWitnesses(scope, witnesses)
 	if err != nil {
 		return nil, nil, fmt.Errorf("select witnesses: %w", err)
 	}
@@ -831,6 +875,47 @@ func (c *Writer) getWitnesses(batchOpsWitnesses []string) (selectedWitnessesIRI
 	return selectedWitnessesIRI, updateWitnessSelectionFlag(witnesses, selectedWitnessesMap), nil
 }
 
+// selectWitnesses selects witnesses according to the witness policy, excluding the witnesses whose circuit is open
+// (i.e. those that have been failing consistently). If the witness policy cannot be satisfied without the
+// unavailable witnesses then the selection is made from all witnesses.
+func (c *Writer) selectWitnesses(scope *policycfg.AnchorScope, witnesses []*proof.Witness) ([]*proof.Witness, error) {
+	unavailable := c.getUnavailableWitnesses(witnesses)
+
+	if len(unavailable) > 0 {
+		selectedWitnesses, err := c.WitnessPolicy.SelectForScope(scope, witnesses, unavailable...)
+		if err == nil {
+			return selectedWitnesses, nil
+		}
+
+		logger.Warn("Unable to satisfy the witness policy without the unavailable witnesses. "+
+			"Selecting from all witnesses.", logfields.WithTotal(len(unavailable)), log.WithError(err))
+	}
+
+	return c.WitnessPolicy.SelectForScope(scope, witnesses)
+}
+
+func (c *Writer) getUnavailableWitnesses(witnesses []*proof.Witness) []*proof.Witness {
+	if c.PeerHealth == nil {
+		return nil
+	}
+
+	var unavailable []*proof.Witness
+
+	for _, w := range witnesses {
+		if w.URI == nil || w.URI.URL() == nil {
+			continue
+		}
+
+		if !c.PeerHealth.IsAvailable(w.URI.URL()) {
+			logger.Debug("Witness is currently unavailable", logfields.WithWitnessURI(w.URI.URL()))
+
+			unavailable = append(unavailable, w)
+		}
+	}
+
+	return unavailable
+}
+
 func updateWitnessSelectionFlag(witnesses []*proof.Witness, selectedWitnesses map[string]bool) []*proof.Witness {
 	for _, w := range witnesses {
 		if _, ok := selectedWitnesses[w.URI.String()]; ok {
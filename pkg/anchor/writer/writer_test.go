@@ -40,6 +40,7 @@ import (
 	"github.com/trustbloc/orb/pkg/anchor/builder"
 	"github.com/trustbloc/orb/pkg/anchor/graph"
 	anchormocks "github.com/trustbloc/orb/pkg/anchor/mocks"
+	policycfg "github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	writermocks "github.com/trustbloc/orb/pkg/anchor/writer/mocks"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
@@ -1237,6 +1238,38 @@ func TestWriter_handle(t *testing.T) {
 		require.NoError(t, c.handle(context.Background(), anchorLinkset))
 	})
 
+	t.Run("error - record witnessed anchor error (log only)", func(t *testing.T) {
+		anchorEventStore, err := anchorlinkstore.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		vcStore, err := mem.NewProvider().OpenStore("verifiable")
+		require.NoError(t, err)
+
+		providers := &Providers{
+			AnchorGraph:       anchorGraph,
+			DidAnchors:        memdidanchor.New(),
+			AnchorBuilder:     &mockTxnBuilder{},
+			Outbox:            &mockOutbox{},
+			Signer:            &mockSigner{},
+			AnchorLinkStore:   anchorEventStore,
+			WitnessStore:      &mockWitnessStore{RecordErr: fmt.Errorf("record error")},
+			VCStore:           vcStore,
+			DocumentLoader:    testutil.GetLoader(t),
+			GeneratorRegistry: generator.NewRegistry(),
+			AnchorLinkBuilder: anchorlinkset.NewBuilder(generator.NewRegistry()),
+		}
+
+		c, err := New(namespace, apServiceIRI, apServiceIRI, casIRI, vocab.JSONMediaType, providers,
+			&anchormocks.AnchorPublisher{}, ps, testMaxWitnessDelay, signWithLocalWitness, nil,
+			5, &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		anchorLinkset := &linkset.Linkset{}
+		require.NoError(t, json.Unmarshal([]byte(jsonAnchorLinkset), anchorLinkset))
+
+		require.NoError(t, c.handle(context.Background(), anchorLinkset))
+	})
+
 	t.Run("error - delete anchor event error (transient store - log only)", func(t *testing.T) {
 		storeProviderWithErr := &mockstore.Provider{
 			OpenStoreReturn: &mockstore.Store{ErrDelete: fmt.Errorf("error delete")},
@@ -1389,7 +1422,7 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			5, &mocks.MetricsProvider{})
 		require.NoError(t, err)
 
-		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"})
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"}, nil)
 		require.NoError(t, err)
 	})
 
@@ -1403,7 +1436,7 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			5, &mocks.MetricsProvider{})
 		require.NoError(t, err)
 
-		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{":xyz"})
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{":xyz"}, nil)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "missing protocol scheme")
 	})
@@ -1422,11 +1455,31 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			5, &mocks.MetricsProvider{})
 		require.NoError(t, err)
 
-		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"})
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"}, nil)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "witness store error")
 	})
 
+	t.Run("error - store policy scope error", func(t *testing.T) {
+		providers := &Providers{
+			Outbox:        &mockOutbox{},
+			WitnessStore:  &mockWitnessStore{PutScopeErr: fmt.Errorf("injected scope error")},
+			WitnessPolicy: &mockWitnessPolicy{},
+			ActivityStore: &mockActivityStore{},
+			WFClient:      wfClient,
+		}
+
+		c, err := New(namespace, apServiceIRI, apServiceIRI, casIRI, vocab.JSONMediaType, providers,
+			&anchormocks.AnchorPublisher{}, ps, testMaxWitnessDelay, signWithLocalWitness, nil,
+			5, &mocks.MetricsProvider{})
+		require.NoError(t, err)
+
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"},
+			&policycfg.AnchorScope{Namespace: namespace, Origins: []string{"https://abc.com"}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected scope error")
+	})
+
 	t.Run("webfinger client error (batch and system witness) - ignores witnesses for domains that are down",
 		func(t *testing.T) {
 			wfClientWithErr := &writermocks.WebFingerCLient{}
@@ -1455,11 +1508,11 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			require.NoError(t, err)
 
 			// test error for batch witness
-			err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"})
+			err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"}, nil)
 			require.NoError(t, err)
 
 			// test error for system witness (no batch witnesses)
-			err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{})
+			err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{}, nil)
 			require.NoError(t, err)
 		},
 	)
@@ -1477,7 +1530,7 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			5, &mocks.MetricsProvider{})
 		require.NoError(t, err)
 
-		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"})
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"}, nil)
 		require.Error(t, err)
 		require.Contains(t, err.Error(),
 			"failed to query references for system witnesses: activity store error")
@@ -1498,7 +1551,7 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			5, &mocks.MetricsProvider{})
 		require.NoError(t, err)
 
-		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"})
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"}, nil)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "outbox error")
 	})
@@ -1518,7 +1571,7 @@ func TestWriter_postOfferActivity(t *testing.T) {
 			5, &mocks.MetricsProvider{})
 		require.NoError(t, err)
 
-		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"})
+		err = c.postOfferActivity(context.Background(), anchorLink, nil, []string{"https://abc.com/services/orb"}, nil)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to get witnesses: select witnesses: witness selection error")
 	})
@@ -1610,9 +1663,10 @@ func TestWriter_getWitnesses(t *testing.T) {
 			},
 		}
 
-		witnesses, err := c.getWitnessesFromBatchOperations(opRefs)
+		witnesses, origins, err := c.getWitnessesFromBatchOperations(opRefs)
 		require.NoError(t, err)
 		require.Equal(t, 5, len(witnesses))
+		require.Equal(t, []string{testAnchorOrigin}, origins)
 
 		expectedWitnessTemplate := "%s/%d/services/orb"
 
@@ -1647,7 +1701,7 @@ func TestWriter_getWitnesses(t *testing.T) {
 			},
 		}
 
-		witnesses, err := c.getWitnessesFromBatchOperations(opRefs)
+		witnesses, _, err := c.getWitnessesFromBatchOperations(opRefs)
 		require.Error(t, err)
 		require.Nil(t, witnesses)
 		require.Contains(t, err.Error(), "operation type 'invalid' not supported for assembling witness list")
@@ -1675,13 +1729,49 @@ func TestWriter_getWitnesses(t *testing.T) {
 			},
 		}
 
-		witnesses, err := c.getWitnessesFromBatchOperations(opRefs)
+		witnesses, _, err := c.getWitnessesFromBatchOperations(opRefs)
 		require.Error(t, err)
 		require.Nil(t, witnesses)
 		require.Contains(t, err.Error(), "unexpected interface 'int' for anchor origin")
 	})
 }
 
+func TestWriter_selectWitnesses(t *testing.T) {
+	witness1 := &proof.Witness{URI: vocab.NewURLProperty(testutil.MustParseURL("https://domain1.com/services/orb"))}
+	witness2 := &proof.Witness{URI: vocab.NewURLProperty(testutil.MustParseURL("https://domain2.com/services/orb"))}
+
+	witnesses := []*proof.Witness{witness1, witness2}
+
+	peerHealth := &mockPeerHealth{unavailable: map[string]bool{witness2.URI.String(): true}}
+
+	t.Run("No peer health provider", func(t *testing.T) {
+		c := &Writer{Providers: &Providers{WitnessPolicy: &mockWitnessPolicy{}}}
+
+		selected, err := c.selectWitnesses(nil, witnesses)
+		require.NoError(t, err)
+		require.Equal(t, witnesses, selected)
+	})
+
+	t.Run("Unavailable witness excluded", func(t *testing.T) {
+		c := &Writer{Providers: &Providers{WitnessPolicy: &mockWitnessPolicy{}, PeerHealth: peerHealth}}
+
+		selected, err := c.selectWitnesses(nil, witnesses)
+		require.NoError(t, err)
+		require.Equal(t, []*proof.Witness{witness1}, selected)
+	})
+
+	t.Run("Policy not satisfied without unavailable witness", func(t *testing.T) {
+		c := &Writer{Providers: &Providers{
+			WitnessPolicy: &mockWitnessPolicy{ExcludeErr: errors.New("not enough witnesses")},
+			PeerHealth:    peerHealth,
+		}}
+
+		selected, err := c.selectWitnesses(nil, witnesses)
+		require.NoError(t, err)
+		require.Equal(t, witnesses, selected)
+	})
+}
+
 func TestWriter_getBatchWitnessesIRI(t *testing.T) {
 	ps := mempubsub.New(mempubsub.Config{})
 	defer ps.Stop()
@@ -1905,8 +1995,10 @@ func (a *mockActivityStore) QueryReferences(spi.ReferenceType, *spi.Criteria, ..
 }
 
 type mockWitnessStore struct {
-	PutErr    error
-	DeleteErr error
+	PutErr      error
+	DeleteErr   error
+	RecordErr   error
+	PutScopeErr error
 }
 
 func (w *mockWitnessStore) Put(vcID string, witnesses []*proof.Witness) error {
@@ -1925,6 +2017,14 @@ func (w *mockWitnessStore) Delete(vcID string) error {
 	return nil
 }
 
+func (w *mockWitnessStore) RecordWitnessedAnchor(string, string, string) error {
+	return w.RecordErr
+}
+
+func (w *mockWitnessStore) PutPolicyScope(string, *policycfg.AnchorScope) error {
+	return w.PutScopeErr
+}
+
 type mockstatusStore struct {
 	Err error
 }
@@ -1974,11 +2074,14 @@ func (m httpMock) Do(req *http.Request) (*http.Response, error) {
 }
 
 type mockWitnessPolicy struct {
-	Witnesses []*proof.Witness
-	Err       error
+	Witnesses  []*proof.Witness
+	Err        error
+	ExcludeErr error
 }
 
-func (wp *mockWitnessPolicy) Select(witnesses []*proof.Witness, _ ...*proof.Witness) ([]*proof.Witness, error) {
+func (wp *mockWitnessPolicy) SelectForScope(_ *policycfg.AnchorScope, witnesses []*proof.Witness,
+	exclude ...*proof.Witness,
+) ([]*proof.Witness, error) {
 	if wp.Err != nil {
 		return nil, wp.Err
 	}
@@ -1987,7 +2090,37 @@ func (wp *mockWitnessPolicy) Select(witnesses []*proof.Witness, _ ...*proof.Witn
 		return wp.Witnesses, nil
 	}
 
-	return witnesses, nil
+	if len(exclude) == 0 {
+		return witnesses, nil
+	}
+
+	if wp.ExcludeErr != nil {
+		return nil, wp.ExcludeErr
+	}
+
+	excluded := make(map[string]bool)
+
+	for _, w := range exclude {
+		excluded[w.URI.String()] = true
+	}
+
+	var selected []*proof.Witness
+
+	for _, w := range witnesses {
+		if !excluded[w.URI.String()] {
+			selected = append(selected, w)
+		}
+	}
+
+	return selected, nil
+}
+
+type mockPeerHealth struct {
+	unavailable map[string]bool
+}
+
+func (m *mockPeerHealth) IsAvailable(iri *url.URL) bool {
+	return !m.unavailable[iri.String()]
 }
 
 const jsonAnchorLinkset = `{
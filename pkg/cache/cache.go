@@ -157,6 +157,24 @@ func (c *Cache) MarkAsStale(key interface{}) {
 	}
 }
 
+// Remove removes the entry for the given key so that it is loaded from the source upon next access. The
+// previously cached value is returned (or nil if the entry was not found or was never successfully loaded).
+func (c *Cache) Remove(key interface{}) interface{} {
+	c.mutex.Lock()
+	e, found := c.data[key]
+	delete(c.data, key)
+	c.mutex.Unlock()
+
+	if !found {
+		return nil
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.value
+}
+
 // getEntry returns an existing entry for the given key or adds a new entry.
 // If the entry already exists then true is returned otherwise false if a new entry
 // was added.
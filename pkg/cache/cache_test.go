@@ -171,6 +171,34 @@ func TestCache_Get(t *testing.T) {
 	})
 }
 
+func TestCache_Remove(t *testing.T) {
+	const key = "key1"
+
+	i := 0
+
+	c := New(
+		func(key interface{}) (interface{}, error) {
+			i++
+
+			return fmt.Sprintf("value%d", i), nil
+		},
+		WithName("test-cache"),
+	)
+
+	require.Nil(t, c.Remove(key))
+
+	v, err := c.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, "value1", v)
+
+	require.Equal(t, "value1", c.Remove(key))
+	require.Nil(t, c.Remove(key))
+
+	v, err = c.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, "value2", v)
+}
+
 func TestCache_Concurrency(t *testing.T) {
 	var numCalls atomic.Int32
 
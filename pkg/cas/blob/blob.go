@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bluele/gcache"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/ipfs"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+var logger = log.New("cas-blob")
+
+const defaultCacheSize = 1000
+
+// Store is a blob store that persists content under a key (the resource hash of the content).
+type Store interface {
+	// Put stores the given content under the given key. Since keys are content addresses, storing
+	// the same key twice is expected to be harmless.
+	Put(key string, content []byte) error
+	// Get returns the content stored under the given key or orberrors.ErrContentNotFound if the key
+	// doesn't exist.
+	Get(key string) ([]byte, error)
+	// Type returns the type of the store (used for metrics).
+	Type() string
+}
+
+type metricsProvider interface {
+	CASIncrementCacheHitCount()
+	CASReadTime(casType string, value time.Duration)
+}
+
+// Client is a content-addressable storage client that stores content as blobs in a Store (e.g. a directory tree
+// or an S3-compatible object store). Content is addressed by resource hash and the hashlinks that are produced are
+// the same as those produced by the local (database) CAS.
+type Client struct {
+	store      Store
+	ipfsClient *ipfs.Client
+	opts       []extendedcasclient.CIDFormatOption
+	cache      gcache.Cache
+	metrics    metricsProvider
+	casLink    string
+	hl         *hashlink.HashLink
+}
+
+// New returns a new blob CAS client that uses the given store.
+// ipfsClient is optional, but if provided (not nil), then writes will go to IPFS in addition to the blob store.
+// Reads are always done on only the blob store.
+func New(store Store, casLink string, ipfsClient *ipfs.Client, metrics metricsProvider,
+	cacheSize int, opts ...extendedcasclient.CIDFormatOption,
+) *Client {
+	if cacheSize == 0 {
+		cacheSize = defaultCacheSize
+	}
+
+	c := &Client{
+		store:      store,
+		ipfsClient: ipfsClient,
+		opts:       opts,
+		metrics:    metrics,
+		hl:         hashlink.New(),
+		casLink:    casLink,
+	}
+
+	c.cache = gcache.New(cacheSize).ARC().
+		LoaderFunc(func(key interface{}) (interface{}, error) {
+			content, err := c.get(key.(string)) //nolint:forcetypeassert
+			if err != nil {
+				return nil, err
+			}
+
+			logger.Debug("Content was cached", logfields.WithKey(key.(string))) //nolint:forcetypeassert
+
+			return content, nil
+		},
+		).Build()
+
+	return c
+}
+
+// Write writes the given content to the blob store (and IPFS if configured) using the default CID version.
+// Returns the hashlink of the content.
+func (c *Client) Write(content []byte) (string, error) {
+	return c.WriteWithCIDFormat(content, c.opts...)
+}
+
+// WriteWithCIDFormat writes the given content to the blob store (and IPFS if configured) using the
+// CID format specified by opts. Returns the hashlink of the content. Content that's larger than a single UnixFS
+// chunk is addressed by the root of its UnixFS DAG (the same as the local CAS) unless the flat hash option is specified.
+func (c *Client) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFormatOption) (string, error) {
+	if len(content) == 0 {
+		return "", errors.New("empty content")
+	}
+
+	options, err := extendedcasclient.GetCIDFormatOptions(opts...)
+	if err != nil {
+		return "", err
+	}
+
+	resourceHash, err := options.ResourceHash(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
+	}
+
+	logger.Debug("Writing to blob store", logfields.WithHash(resourceHash), logfields.WithType(c.store.Type()))
+
+	err = c.store.Put(resourceHash, content)
+	if err != nil {
+		return "", orberrors.NewTransient(fmt.Errorf("failed to put content into blob store: %w", err))
+	}
+
+	links := []string{c.casLink + "/" + resourceHash}
+
+	if c.ipfsClient != nil {
+		cid, writeErr := c.ipfsClient.WriteWithCIDFormat(content, opts...)
+		if writeErr != nil {
+			return "", orberrors.NewTransient(fmt.Errorf("failed to put content into IPFS (but it was "+
+				"successfully stored in the blob store): %w", writeErr))
+		}
+
+		links = append(links, "ipfs://"+cid)
+	}
+
+	if err = c.cache.Set(resourceHash, content); err != nil {
+		// This shouldn't be possible.
+		logger.Warn("Error caching content for resource hash", logfields.WithHash(resourceHash), log.WithError(err))
+	}
+
+	metadata, err := c.hl.CreateMetadataFromLinks(links)
+	if err != nil {
+		return "", fmt.Errorf("failed to create metadata from links: %w", err)
+	}
+
+	formattedResourceHash, err := options.FormatResourceHash(resourceHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to format resource hash: %w", err)
+	}
+
+	return hashlink.GetHashLink(formattedResourceHash, metadata), nil
+}
+
+// GetPrimaryWriterType returns the primary writer type. Content is addressed by resource hash (the same as the
+// local CAS) so "local" is returned.
+func (c *Client) GetPrimaryWriterType() string {
+	return "local"
+}
+
+// Read reads the content of the given address (resource hash or CID) from the blob store.
+func (c *Client) Read(address string) ([]byte, error) {
+	address, err := multihash.CIDOrMultihashToMultihash(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache.Has(address) {
+		c.metrics.CASIncrementCacheHitCount()
+	}
+
+	content, err := c.cache.Get(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return content.([]byte), nil //nolint:forcetypeassert
+}
+
+func (c *Client) get(address string) ([]byte, error) {
+	startTime := time.Now()
+
+	defer func() { c.metrics.CASReadTime(c.store.Type(), time.Since(startTime)) }()
+
+	content, err := c.store.Get(address)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get content from blob store: %w", err))
+	}
+
+	return content, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blob_test
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/blob"
+	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	localcas "github.com/trustbloc/orb/pkg/store/cas"
+)
+
+const casLink = "https://domain.com/cas"
+
+func TestClient_Write_Read(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		store := newMockStore()
+
+		client := blob.New(store, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		hl, err := client.Write([]byte("content"))
+		require.NoError(t, err)
+
+		rs, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+		require.Equal(t, "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw", rs)
+		require.Contains(t, store.blobs, rs)
+
+		content, err := client.Read(rs)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+
+		// Read from a new client so that the content isn't cached.
+		content, err = blob.New(store, casLink, nil, &orbmocks.MetricsProvider{}, 0).Read(rs)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+
+		require.Equal(t, "local", client.GetPrimaryWriterType())
+	})
+
+	t.Run("Same hashlink as local CAS", func(t *testing.T) {
+		local, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		localHL, err := local.Write([]byte("content"))
+		require.NoError(t, err)
+
+		blobHL, err := blob.New(newMockStore(), casLink, nil, &orbmocks.MetricsProvider{}, 0).Write([]byte("content"))
+		require.NoError(t, err)
+
+		require.Equal(t, localHL, blobHL)
+	})
+
+	t.Run("Multiple chunks", func(t *testing.T) {
+		client := blob.New(newMockStore(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		content := bytes.Repeat([]byte("content"), unixfs.ChunkSize)
+
+		hl, err := client.Write(content)
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		expectedRH, err := unixfs.ResourceHash(content)
+		require.NoError(t, err)
+		require.Equal(t, expectedRH, rh)
+
+		cid, err := unixfs.CID(content)
+		require.NoError(t, err)
+
+		c, err := client.Read(cid.String())
+		require.NoError(t, err)
+		require.Equal(t, content, c)
+
+		// Content may also be stored under the flat hash of the content (as it was by earlier versions).
+		hl, err = client.WriteWithCIDFormat(content, extendedcasclient.WithFlatHash())
+		require.NoError(t, err)
+
+		rh, err = hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		flatHash, err := unixfs.FlatResourceHash(content)
+		require.NoError(t, err)
+		require.Equal(t, flatHash, rh)
+
+		c, err = client.Read(flatHash)
+		require.NoError(t, err)
+		require.Equal(t, content, c)
+	})
+
+	t.Run("Empty content", func(t *testing.T) {
+		client := blob.New(newMockStore(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		hl, err := client.Write(nil)
+		require.EqualError(t, err, "empty content")
+		require.Empty(t, hl)
+	})
+
+	t.Run("Put error", func(t *testing.T) {
+		store := newMockStore()
+		store.putErr = errors.New("injected put error")
+
+		client := blob.New(store, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		hl, err := client.Write([]byte("content"))
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+		require.Empty(t, hl)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		client := blob.New(newMockStore(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		content, err := client.Read("uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw")
+		require.ErrorIs(t, err, orberrors.ErrContentNotFound)
+		require.Nil(t, content)
+	})
+
+	t.Run("Get error", func(t *testing.T) {
+		store := newMockStore()
+		store.getErr = errors.New("injected get error")
+
+		client := blob.New(store, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+
+		content, err := client.Read("uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+		require.Nil(t, content)
+	})
+}
+
+type mockStore struct {
+	mutex  sync.Mutex
+	blobs  map[string][]byte
+	putErr error
+	getErr error
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{blobs: make(map[string][]byte)}
+}
+
+func (m *mockStore) Put(key string, content []byte) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.blobs[key] = content
+
+	return nil
+}
+
+func (m *mockStore) Get(key string) ([]byte, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	content, ok := m.blobs[key]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return content, nil
+}
+
+func (m *mockStore) Type() string {
+	return "mock"
+}
@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	// StoreType is the type of the filesystem blob store.
+	StoreType = "filesystem"
+
+	dirPerm  = 0o750
+	filePerm = 0o640
+
+	// shardLen is the number of trailing characters of the key that are used as the name of the sub-directory
+	// in which the blob is stored. (The leading characters of a resource hash are the multihash prefix and are
+	// therefore the same for all keys.)
+	shardLen = 2
+)
+
+// Store is a blob store over a local directory tree. Each blob is stored in a file named by its key in a
+// sub-directory (shard) named by the last characters of the key.
+type Store struct {
+	dir string
+}
+
+// New returns a new filesystem blob store rooted at the given directory. The directory is created if it
+// doesn't exist.
+func New(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, errors.New("directory is required")
+	}
+
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("create directory [%s]: %w", dir, err)
+	}
+
+	return &Store{dir: dir}, nil
+}
+
+// Type returns the type of the store.
+func (s *Store) Type() string {
+	return StoreType
+}
+
+// Put stores the content in a file named by the given key. The content is first written to a temporary file
+// which is then renamed so that readers never see a partially written blob.
+func (s *Store) Put(key string, content []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(path); err == nil {
+		// The blob is content-addressed so there's nothing to do if it already exists.
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+
+	if err = os.MkdirAll(dir, dirPerm); err != nil {
+		return fmt.Errorf("create directory [%s]: %w", dir, err)
+	}
+
+	f, err := os.CreateTemp(dir, "."+key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+
+	tmpPath := f.Name()
+
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if e := f.Close(); e != nil && err == nil {
+		err = e
+	}
+
+	if err == nil {
+		err = os.Chmod(tmpPath, filePerm)
+	}
+
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath) //nolint:errcheck
+
+		return fmt.Errorf("write blob [%s]: %w", key, err)
+	}
+
+	return nil
+}
+
+// Get returns the content stored under the given key or orberrors.ErrContentNotFound if the key doesn't exist.
+func (s *Store) Get(key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, fmt.Errorf("read blob [%s]: %w", key, err)
+	}
+
+	return content, nil
+}
+
+func (s *Store) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || strings.HasPrefix(key, ".") {
+		return "", fmt.Errorf("invalid key [%s]", key)
+	}
+
+	shard := key
+	if len(key) > shardLen {
+		shard = key[len(key)-shardLen:]
+	}
+
+	return filepath.Join(s.dir, shard, key), nil
+}
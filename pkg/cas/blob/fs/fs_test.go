@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const key = "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"
+
+func TestNew(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "cas")
+
+		s, err := New(dir)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, StoreType, s.Type())
+
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		require.True(t, info.IsDir())
+	})
+
+	t.Run("No directory", func(t *testing.T) {
+		s, err := New("")
+		require.EqualError(t, err, "directory is required")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore_Put_Get(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s, err := New(dir)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(key, []byte("content")))
+
+		// Storing the same blob again is harmless.
+		require.NoError(t, s.Put(key, []byte("content")))
+
+		content, err := s.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+
+		_, err = os.Stat(filepath.Join(dir, "cw", key))
+		require.NoError(t, err)
+
+		// No temporary files should be left behind.
+		entries, err := os.ReadDir(filepath.Join(dir, "cw"))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		s, err := New(t.TempDir())
+		require.NoError(t, err)
+
+		content, err := s.Get(key)
+		require.ErrorIs(t, err, orberrors.ErrContentNotFound)
+		require.Nil(t, content)
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		s, err := New(t.TempDir())
+		require.NoError(t, err)
+
+		for _, k := range []string{"", "../etc/passwd", `a\b`, ".hidden"} {
+			require.Error(t, s.Put(k, []byte("content")))
+
+			_, err = s.Get(k)
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("Write error", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s, err := New(dir)
+		require.NoError(t, err)
+
+		// A file in place of the shard directory causes the write to fail.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cw"), []byte("x"), filePerm))
+
+		err = s.Put(key, []byte("content"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create directory")
+	})
+}
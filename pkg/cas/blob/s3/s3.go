@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+var logger = log.New("cas-s3")
+
+const (
+	// StoreType is the type of the S3 blob store.
+	StoreType = "s3"
+
+	defaultRegion  = "us-east-1"
+	defaultTimeout = 30 * time.Second
+
+	// maxErrorBodySize is the maximum number of bytes of an error response that are included in the error.
+	maxErrorBodySize = 512
+)
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Config holds the parameters of the S3-compatible object store.
+type Config struct {
+	// Endpoint is the base URL of the object store, e.g. https://s3.us-east-1.amazonaws.com or
+	// http://localhost:9000. Objects are addressed using path-style URLs (endpoint/bucket/key).
+	Endpoint string
+	// Region is the region used for signing requests. Defaults to us-east-1.
+	Region string
+	// Bucket is the name of the bucket that holds the blobs.
+	Bucket string
+	// KeyPrefix is an optional prefix that is prepended to all object keys.
+	KeyPrefix string
+	// AccessKeyID is the access key ID. If not set then requests are not signed.
+	AccessKeyID string
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+	// Timeout is the HTTP request timeout. Defaults to 30s.
+	Timeout time.Duration
+}
+
+// Store is a blob store over an S3-compatible object store API.
+type Store struct {
+	Config
+
+	endpoint   *url.URL
+	httpClient httpClient
+	now        func() time.Time
+}
+
+// New returns a new S3 blob store.
+func New(cfg Config) (*Store, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("endpoint is required")
+	}
+
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse endpoint [%s]: %w", cfg.Endpoint, err)
+	}
+
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint [%s]", cfg.Endpoint)
+	}
+
+	if cfg.Region == "" {
+		cfg.Region = defaultRegion
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &Store{
+		Config:     cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		now:        time.Now,
+	}, nil
+}
+
+// Type returns the type of the store.
+func (s *Store) Type() string {
+	return StoreType
+}
+
+// Put stores the content in an object named by the given key.
+func (s *Store) Put(key string, content []byte) error {
+	resp, err := s.do(http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+
+	defer closeAndLog(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError(http.MethodPut, key, resp)
+	}
+
+	return nil
+}
+
+// Get returns the content stored under the given key or orberrors.ErrContentNotFound if the key doesn't exist.
+func (s *Store) Get(key string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeAndLog(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		content, e := io.ReadAll(resp.Body)
+		if e != nil {
+			return nil, fmt.Errorf("read object [%s]: %w", key, e)
+		}
+
+		return content, nil
+	case http.StatusNotFound:
+		return nil, orberrors.ErrContentNotFound
+	default:
+		return nil, newStatusError(http.MethodGet, key, resp)
+	}
+}
+
+func (s *Store) do(method, key string, content []byte) (*http.Response, error) {
+	if key == "" {
+		return nil, errors.New("key is required")
+	}
+
+	objectURL := s.objectURL(key)
+
+	var body io.Reader
+	if content != nil {
+		body = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequest(method, objectURL, body) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf("new request [%s %s]: %w", method, objectURL, err)
+	}
+
+	if s.AccessKeyID != "" {
+		signV4(req, hashHex(content), s.AccessKeyID, s.SecretAccessKey, s.Region, s.now())
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, objectURL, err)
+	}
+
+	return resp, nil
+}
+
+func (s *Store) objectURL(key string) string {
+	segments := []string{uriEscape(s.Bucket)}
+
+	for _, segment := range strings.Split(s.KeyPrefix+key, "/") {
+		segments = append(segments, uriEscape(segment))
+	}
+
+	return s.endpoint.String() + "/" + strings.Join(segments, "/")
+}
+
+func newStatusError(method, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)) //nolint:errcheck
+
+	return fmt.Errorf("%s object [%s] returned status %d: %s", method, key, resp.StatusCode, body)
+}
+
+func closeAndLog(rc io.Closer) {
+	if err := rc.Close(); err != nil {
+		logger.Warn("Failed to close response body", log.WithError(err), logfields.WithType(StoreType))
+	}
+}
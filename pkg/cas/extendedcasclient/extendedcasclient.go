@@ -6,7 +6,42 @@ SPDX-License-Identifier: Apache-2.0
 
 package extendedcasclient
 
-import casapi "github.com/trustbloc/sidetree-svc-go/pkg/api/cas"
+import (
+	"fmt"
+
+	"github.com/multiformats/go-multibase"
+	casapi "github.com/trustbloc/sidetree-svc-go/pkg/api/cas"
+
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+// Multibase is the name of a multibase encoding.
+type Multibase string
+
+// Supported multibase encodings.
+const (
+	MultibaseBase32    Multibase = "base32"
+	MultibaseBase58BTC Multibase = "base58btc"
+	MultibaseBase64URL Multibase = "base64url"
+)
+
+// Codec is the name of a (multicodec) content type of a v1 CID.
+type Codec string
+
+// Supported codecs.
+const (
+	CodecRaw     Codec = "raw"
+	CodecDAGJSON Codec = "dag-json"
+	CodecDAGCBOR Codec = "dag-cbor"
+)
+
+// Multicodec codes of the supported codecs.
+const (
+	rawCode     = 0x55
+	dagJSONCode = 0x0129
+	dagCBORCode = 0x71
+)
 
 // CIDFormatOption is an option for specifying the CID format used in a WriteWithCIDFormat call.
 type CIDFormatOption func(opts *CIDFormatOptions)
@@ -14,6 +49,14 @@ type CIDFormatOption func(opts *CIDFormatOptions)
 // CIDFormatOptions represent CID format options for use in a Client.WriteWithCIDFormat call.
 type CIDFormatOptions struct {
 	CIDVersion int
+	// Multibase is the multibase encoding of the CID (or hashlink resource hash). If not set then the
+	// default encoding is used, i.e. base32 for v1 CIDs and base64url for resource hashes.
+	Multibase Multibase
+	// Codec is the content type of a v1 CID. If not set then raw is used.
+	Codec Codec
+	// FlatHash indicates that content which spans multiple chunks is addressed by the hash of the entire
+	// content (as it was by earlier versions) rather than by the root of its UnixFS DAG.
+	FlatHash bool
 }
 
 // WithCIDVersion sets the CID version to be used in a WriteWithCIDFormat call.
@@ -24,6 +67,156 @@ func WithCIDVersion(cidVersion int) CIDFormatOption {
 	}
 }
 
+// WithMultibase sets the multibase encoding to be used in a WriteWithCIDFormat call.
+// Currently, base32, base58btc and base64url are the only valid options.
+func WithMultibase(mb Multibase) CIDFormatOption {
+	return func(opts *CIDFormatOptions) {
+		opts.Multibase = mb
+	}
+}
+
+// WithCodec sets the codec of the v1 CID to be used in a WriteWithCIDFormat call.
+// Currently, raw, dag-json and dag-cbor are the only valid options.
+func WithCodec(codec Codec) CIDFormatOption {
+	return func(opts *CIDFormatOptions) {
+		opts.Codec = codec
+	}
+}
+
+// WithFlatHash indicates that content which spans multiple chunks should be addressed by the hash of the
+// entire content rather than by the root of its UnixFS DAG. This option is used when storing content that's
+// referenced by a hashlink that was produced by an earlier version.
+func WithFlatHash() CIDFormatOption {
+	return func(opts *CIDFormatOptions) {
+		opts.FlatHash = true
+	}
+}
+
+// GetCIDFormatOptions applies the given options to the default options (CID v1 and raw codec) and
+// validates the result.
+func GetCIDFormatOptions(opts ...CIDFormatOption) (CIDFormatOptions, error) {
+	options := CIDFormatOptions{CIDVersion: 1}
+
+	for _, option := range opts {
+		if option != nil {
+			option(&options)
+		}
+	}
+
+	if options.CIDVersion != 0 && options.CIDVersion != 1 {
+		return CIDFormatOptions{},
+			fmt.Errorf("%d is not a supported CID version. It must be either 0 or 1", options.CIDVersion)
+	}
+
+	if options.Multibase != "" {
+		if _, err := options.MultibaseEncoding(multibase.Base32); err != nil {
+			return CIDFormatOptions{}, err
+		}
+	}
+
+	if options.Codec == "" {
+		options.Codec = CodecRaw
+	}
+
+	if _, err := options.CodecCode(); err != nil {
+		return CIDFormatOptions{}, err
+	}
+
+	if options.CIDVersion == 0 && options.Codec != CodecRaw {
+		return CIDFormatOptions{}, fmt.Errorf("CID version 0 doesn't support the %s codec", options.Codec)
+	}
+
+	if options.CIDVersion == 0 && options.Multibase != "" && options.Multibase != MultibaseBase58BTC {
+		return CIDFormatOptions{}, fmt.Errorf("CID version 0 doesn't support the %s multibase", options.Multibase)
+	}
+
+	return options, nil
+}
+
+// MultibaseEncoding returns the multibase encoding for the multibase option or the given default encoding if the
+// multibase option isn't set.
+func (o *CIDFormatOptions) MultibaseEncoding(defaultEncoding multibase.Encoding) (multibase.Encoding, error) {
+	switch o.Multibase {
+	case MultibaseBase32:
+		return multibase.Base32, nil
+	case MultibaseBase58BTC:
+		return multibase.Base58BTC, nil
+	case MultibaseBase64URL:
+		return multibase.Base64url, nil
+	case "":
+		return defaultEncoding, nil
+	default:
+		return 0, fmt.Errorf("%s is not a supported multibase. It must be one of base32, base58btc or base64url",
+			o.Multibase)
+	}
+}
+
+// CodecCode returns the multicodec code of the codec option.
+func (o *CIDFormatOptions) CodecCode() (uint64, error) {
+	switch o.Codec {
+	case CodecRaw, "":
+		return rawCode, nil
+	case CodecDAGJSON:
+		return dagJSONCode, nil
+	case CodecDAGCBOR:
+		return dagCBORCode, nil
+	default:
+		return 0, fmt.Errorf("%s is not a supported codec. It must be one of raw, dag-json or dag-cbor", o.Codec)
+	}
+}
+
+// ResourceHash returns the (base64url multibase-encoded) resource hash of the given content. Content that spans
+// multiple chunks is addressed by the root of its (v1) UnixFS DAG unless the flat hash option is set or the CID
+// version is 0. IPFS builds a different DAG for v0 CIDs (dag-pb leaves) so the v1 DAG root wouldn't be the address
+// of the content in IPFS anyway.
+func (o *CIDFormatOptions) ResourceHash(content []byte) (string, error) {
+	if o.FlatHash || o.CIDVersion == 0 {
+		return unixfs.FlatResourceHash(content)
+	}
+
+	return unixfs.ResourceHash(content)
+}
+
+// VerifyCID returns an error if the given CID, as returned by IPFS for the given content, isn't the CID of the
+// root of the content's UnixFS DAG as computed locally. CIDs of version 0 aren't verified since IPFS wraps even
+// small content in a dag-pb node, so a v0 CID is never the hash of the content.
+func (o *CIDFormatOptions) VerifyCID(cid string, content []byte) error {
+	if o.CIDVersion == 0 {
+		return nil
+	}
+
+	hash, err := multihash.CIDToMultihash(cid)
+	if err != nil {
+		return fmt.Errorf("get multihash from CID [%s]: %w", cid, err)
+	}
+
+	expectedHash, err := unixfs.ResourceHash(content)
+	if err != nil {
+		return fmt.Errorf("create resource hash: %w", err)
+	}
+
+	if hash != expectedHash {
+		return fmt.Errorf("CID [%s] returned by IPFS does not match the hash of the content [%s]", cid, expectedHash)
+	}
+
+	return nil
+}
+
+// FormatResourceHash re-encodes the given (base64url multibase-encoded) resource hash using the multibase option.
+// The resource hash is returned as is if the multibase option isn't set.
+func (o *CIDFormatOptions) FormatResourceHash(resourceHash string) (string, error) {
+	encoding, err := o.MultibaseEncoding(multibase.Base64url)
+	if err != nil {
+		return "", err
+	}
+
+	if encoding == multibase.Base64url {
+		return resourceHash, nil
+	}
+
+	return multihash.ToMultibase(resourceHash, encoding)
+}
+
 // Client represents a CAS client with an additional method that allows the CID format
 // to be specified for a specific write.
 type Client interface {
@@ -0,0 +1,613 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/versions/1_0/txnprovider/models"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/linkset"
+	"github.com/trustbloc/orb/pkg/multihash"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+var logger = log.New("cas-gc")
+
+const (
+	taskName = "cas-gc"
+
+	defaultInterval             = 24 * time.Hour
+	defaultGracePeriod          = 7 * 24 * time.Hour
+	defaultMaxSweepCount        = 10000
+	defaultCompressionAlgorithm = "GZIP"
+)
+
+var errMaxSweepCountReached = errors.New("maximum sweep count reached")
+
+type taskManager interface {
+	RegisterTask(taskType string, interval time.Duration, handler func())
+}
+
+type casStore interface {
+	Read(address string) ([]byte, error)
+	QueryCreatedBefore(t time.Time, fn func(resourceHash string) error) error
+	Delete(resourceHash string) error
+}
+
+type anchorRefStore interface {
+	ForEachLink(fn func(link *url.URL) error) error
+}
+
+type anchorLinkStore interface {
+	ForEach(fn func(anchorLink *linkset.Link) error) error
+}
+
+type anchorLinksetBuilder interface {
+	GetPayloadFromAnchorLink(anchorLink *linkset.Link) (*subject.Payload, error)
+}
+
+type decompressor interface {
+	Decompress(alg string, data []byte) ([]byte, error)
+}
+
+type archiveStore interface {
+	Put(key string, content []byte) error
+}
+
+// Config contains configuration parameters for the garbage collector.
+type Config struct {
+	// Interval is the interval at which the garbage collector runs.
+	Interval time.Duration
+	// GracePeriod is the minimum age of unreferenced content before it's deleted. This protects content that was
+	// written by a batch that hasn't been anchored yet.
+	GracePeriod time.Duration
+	// MaxSweepCount is the maximum number of items that are deleted (or reported, in dry-run mode) in a single run.
+	MaxSweepCount int
+	// DryRun indicates that unreferenced content is only reported and not deleted.
+	DryRun bool
+	// CompressionAlgorithm is the algorithm used to compress Sidetree files. Defaults to GZIP.
+	CompressionAlgorithm string
+}
+
+// Providers contains the providers of the garbage collector.
+type Providers struct {
+	// CAS is the local CAS from which unreferenced content is deleted.
+	CAS casStore
+	// AnchorRefStore holds the references to processed and pending anchor linksets (the anchor graph).
+	AnchorRefStore anchorRefStore
+	// AnchorLinkStore holds anchor links that are waiting to be witnessed.
+	AnchorLinkStore anchorLinkStore
+	// VCStore holds anchor credentials.
+	VCStore              storage.Store
+	AnchorLinksetBuilder anchorLinksetBuilder
+	Decompressor         decompressor
+	// Archive is optional. If set then unreferenced content is copied to the archive before it's deleted.
+	Archive archiveStore
+}
+
+// Report contains the results of a garbage collection run.
+type Report struct {
+	// StartTime is the time that the run started.
+	StartTime time.Time `json:"startTime"`
+	// Duration is the duration of the run.
+	Duration time.Duration `json:"duration"`
+	// DryRun indicates whether the run was in dry-run mode.
+	DryRun bool `json:"dryRun"`
+	// Marked is the number of items of content that are reachable from live anchors.
+	Marked int `json:"marked"`
+	// Candidates contains the resource hashes of unreferenced content that is older than the grace period.
+	Candidates []string `json:"candidates,omitempty"`
+	// Deleted is the number of items that were deleted.
+	Deleted int `json:"deleted"`
+	// Archived is the number of items that were archived.
+	Archived int `json:"archived"`
+	// Skipped contains the hashlinks of malformed anchor linksets. The linksets themselves are kept but the content
+	// that they reference (and their previous anchors) can't be marked from them.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Collector is a mark-and-sweep garbage collector for the local CAS. The mark phase walks the live anchors in the
+// anchor graph, the anchor link store and the VC store and marks all CAS content that's reachable from them
+// (anchor linksets, core and provisional index files, proof files and chunk files). The sweep phase then deletes
+// (or archives and deletes) all unmarked content that was written before the grace period. The collector is run by
+// a task so that only one server instance in a domain performs garbage collection.
+type Collector struct {
+	*Config
+	*Providers
+
+	mutex      sync.RWMutex
+	lastReport *Report
+	now        func() time.Time
+}
+
+// New returns a new garbage collector and registers its task with the task manager.
+func New(cfg Config, providers *Providers, taskMgr taskManager) *Collector {
+	c := &Collector{
+		Config:    resolveConfig(&cfg),
+		Providers: providers,
+		now:       time.Now,
+	}
+
+	logger.Info("Registering CAS garbage collection task.", logfields.WithTaskMonitorInterval(c.Interval),
+		logfields.WithMinAge(c.GracePeriod), logfields.WithTotal(c.MaxSweepCount))
+
+	taskMgr.RegisterTask(taskName, c.Interval, c.run)
+
+	return c
+}
+
+// LastReport returns the report of the last garbage collection run or nil if no run has completed.
+func (c *Collector) LastReport() *Report {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.lastReport
+}
+
+// Collect performs a garbage collection run and returns the report. If an error occurs while marking live content
+// then nothing is deleted.
+func (c *Collector) Collect() (*Report, error) {
+	report := &Report{
+		StartTime: c.now(),
+		DryRun:    c.DryRun,
+	}
+
+	m := &marker{Collector: c, marked: make(map[string]struct{})}
+
+	if err := m.markAll(); err != nil {
+		return nil, fmt.Errorf("mark live content: %w", err)
+	}
+
+	report.Marked = len(m.marked)
+	report.Skipped = m.skipped
+
+	if err := c.sweep(m.marked, report); err != nil {
+		return nil, fmt.Errorf("sweep unreferenced content: %w", err)
+	}
+
+	report.Duration = c.now().Sub(report.StartTime)
+
+	c.mutex.Lock()
+	c.lastReport = report
+	c.mutex.Unlock()
+
+	return report, nil
+}
+
+func (c *Collector) run() {
+	report, err := c.Collect()
+	if err != nil {
+		logger.Error("Error collecting unreferenced CAS content", log.WithError(err))
+
+		return
+	}
+
+	logger.Info("Completed CAS garbage collection run", logfields.WithDryRun(report.DryRun),
+		logfields.WithMarked(report.Marked), logfields.WithCandidates(len(report.Candidates)),
+		logfields.WithDeleted(report.Deleted), logfields.WithArchived(report.Archived),
+		logfields.WithSkipped(len(report.Skipped)))
+}
+
+func (c *Collector) sweep(marked map[string]struct{}, report *Report) error {
+	cutoff := report.StartTime.Add(-c.GracePeriod)
+
+	err := c.CAS.QueryCreatedBefore(cutoff, func(resourceHash string) error {
+		if _, ok := marked[resourceHash]; ok {
+			return nil
+		}
+
+		report.Candidates = append(report.Candidates, resourceHash)
+
+		if len(report.Candidates) >= c.MaxSweepCount {
+			return errMaxSweepCountReached
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMaxSweepCountReached) {
+		return err
+	}
+
+	if c.DryRun {
+		return nil
+	}
+
+	for _, resourceHash := range report.Candidates {
+		if c.Archive != nil {
+			if err = c.archive(resourceHash); err != nil {
+				return err
+			}
+
+			report.Archived++
+		}
+
+		if err = c.CAS.Delete(resourceHash); err != nil {
+			return err
+		}
+
+		logger.Debug("Deleted unreferenced CAS content", logfields.WithHash(resourceHash))
+
+		report.Deleted++
+	}
+
+	return nil
+}
+
+func (c *Collector) archive(resourceHash string) error {
+	content, err := c.CAS.Read(resourceHash)
+	if err != nil {
+		return fmt.Errorf("read content [%s] for archiving: %w", resourceHash, err)
+	}
+
+	if err = c.Archive.Put(resourceHash, content); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("archive content [%s]: %w", resourceHash, err))
+	}
+
+	return nil
+}
+
+// marker marks the content that's reachable from live anchors. Anchor linksets are processed from a work list
+// (rather than recursively) since the chain of previous anchors may be long.
+type marker struct {
+	*Collector
+
+	marked  map[string]struct{}
+	anchors []string
+	skipped []string
+}
+
+func (m *marker) markAll() error {
+	err := m.AnchorRefStore.ForEachLink(func(link *url.URL) error {
+		m.anchors = append(m.anchors, link.String())
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("iterate anchor refs: %w", err)
+	}
+
+	err = m.AnchorLinkStore.ForEach(m.markAnchorLink)
+	if err != nil {
+		return fmt.Errorf("iterate anchor links: %w", err)
+	}
+
+	if err = m.markAnchorCredentials(); err != nil {
+		return err
+	}
+
+	for len(m.anchors) > 0 {
+		hl := m.anchors[len(m.anchors)-1]
+		m.anchors = m.anchors[:len(m.anchors)-1]
+
+		if err = m.markAnchorLinkset(hl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mark marks the given URI (hashlink, CID or resource hash) and returns the resource hash and true if the URI
+// wasn't already marked.
+func (m *marker) mark(uri string) (string, bool, error) {
+	resourceHash, err := toResourceHash(uri)
+	if err != nil {
+		return "", false, err
+	}
+
+	if _, ok := m.marked[resourceHash]; ok {
+		return resourceHash, false, nil
+	}
+
+	m.marked[resourceHash] = struct{}{}
+
+	return resourceHash, true, nil
+}
+
+func (m *marker) markAnchorLinkset(hl string) error {
+	resourceHash, isNew, err := m.mark(hl)
+	if err != nil || !isNew {
+		return err
+	}
+
+	content, err := m.read(resourceHash)
+	if err != nil || content == nil {
+		return err
+	}
+
+	anchorLink, err := parseAnchorLinkset(content)
+	if err != nil {
+		m.skip(hl, err)
+
+		return nil
+	}
+
+	payload, err := m.AnchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
+	if err != nil {
+		m.skip(hl, fmt.Errorf("get payload from anchor link: %w", err))
+
+		return nil
+	}
+
+	return m.markPayload(payload)
+}
+
+// skip records a malformed anchor linkset so that a single bad linkset doesn't prevent garbage collection.
+func (m *marker) skip(hl string, err error) {
+	logger.Warn("Skipping malformed anchor linkset", logfields.WithHashlink(hl), log.WithError(err))
+
+	m.skipped = append(m.skipped, hl)
+}
+
+func parseAnchorLinkset(content []byte) (*linkset.Link, error) {
+	anchorLinkset := &linkset.Linkset{}
+
+	if err := json.Unmarshal(content, anchorLinkset); err != nil {
+		return nil, fmt.Errorf("unmarshal anchor linkset: %w", err)
+	}
+
+	anchorLink := anchorLinkset.Link()
+	if anchorLink == nil {
+		return nil, errors.New("empty anchor linkset")
+	}
+
+	return anchorLink, nil
+}
+
+func (m *marker) markAnchorLink(anchorLink *linkset.Link) error {
+	payload, err := m.AnchorLinksetBuilder.GetPayloadFromAnchorLink(anchorLink)
+	if err != nil {
+		return fmt.Errorf("get payload from anchor link [%s]: %w", anchorLink.Anchor(), err)
+	}
+
+	return m.markPayload(payload)
+}
+
+func (m *marker) markPayload(payload *subject.Payload) error {
+	if err := m.markCoreIndex(payload.CoreIndex); err != nil {
+		return err
+	}
+
+	for _, previous := range payload.PreviousAnchors {
+		if previous.Anchor != "" {
+			m.anchors = append(m.anchors, previous.Anchor)
+		}
+	}
+
+	return nil
+}
+
+func (m *marker) markCoreIndex(uri string) error {
+	content, err := m.markAndRead(uri)
+	if err != nil || content == nil {
+		return err
+	}
+
+	coreIndexFile, err := models.ParseCoreIndexFile(content)
+	if err != nil {
+		return fmt.Errorf("parse core index file [%s]: %w", uri, err)
+	}
+
+	if err = m.markFile(coreIndexFile.CoreProofFileURI); err != nil {
+		return err
+	}
+
+	return m.markProvisionalIndex(coreIndexFile.ProvisionalIndexFileURI)
+}
+
+func (m *marker) markProvisionalIndex(uri string) error {
+	content, err := m.markAndRead(uri)
+	if err != nil || content == nil {
+		return err
+	}
+
+	provisionalIndexFile, err := models.ParseProvisionalIndexFile(content)
+	if err != nil {
+		return fmt.Errorf("parse provisional index file [%s]: %w", uri, err)
+	}
+
+	if err = m.markFile(provisionalIndexFile.ProvisionalProofFileURI); err != nil {
+		return err
+	}
+
+	for _, chunk := range provisionalIndexFile.Chunks {
+		if err = m.markFile(chunk.ChunkFileURI); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *marker) markFile(uri string) error {
+	if uri == "" {
+		return nil
+	}
+
+	_, _, err := m.mark(uri)
+
+	return err
+}
+
+// markAndRead marks the given Sidetree file and returns its decompressed content. Nil is returned if the URI is
+// empty, if the file was already marked or if the file isn't in the local CAS.
+func (m *marker) markAndRead(uri string) ([]byte, error) {
+	if uri == "" {
+		return nil, nil
+	}
+
+	resourceHash, isNew, err := m.mark(uri)
+	if err != nil || !isNew {
+		return nil, err
+	}
+
+	content, err := m.read(resourceHash)
+	if err != nil || content == nil {
+		return nil, err
+	}
+
+	content, err = m.Decompressor.Decompress(m.CompressionAlgorithm, content)
+	if err != nil {
+		return nil, fmt.Errorf("decompress file [%s]: %w", uri, err)
+	}
+
+	return content, nil
+}
+
+// read returns the content for the given resource hash or nil if the content isn't in the local CAS.
+func (m *marker) read(resourceHash string) ([]byte, error) {
+	content, err := m.CAS.Read(resourceHash)
+	if err != nil {
+		if errors.Is(err, orberrors.ErrContentNotFound) {
+			logger.Debug("Referenced content not found in local CAS", logfields.WithHash(resourceHash))
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read content [%s]: %w", resourceHash, err)
+	}
+
+	return content, nil
+}
+
+type anchorCredential struct {
+	Subject json.RawMessage `json:"credentialSubject"`
+}
+
+type credentialSubject struct {
+	Anchor string `json:"anchor"`
+}
+
+func (m *marker) markAnchorCredentials() error {
+	iter, err := m.VCStore.Query(anchorutil.VCStoreTagName)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("query VC store: %w", err))
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+	}
+
+	for ok {
+		vcBytes, e := iter.Value()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator value: %w", e))
+		}
+
+		coreIndexes, e := coreIndexesFromCredential(vcBytes)
+		if e != nil {
+			return e
+		}
+
+		for _, coreIndex := range coreIndexes {
+			if err = m.markCoreIndex(coreIndex); err != nil {
+				return err
+			}
+		}
+
+		ok, err = iter.Next()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// coreIndexesFromCredential returns the core index hashlinks in the subject of the given anchor credential. The
+// subject may be a single object or an array of objects.
+func coreIndexesFromCredential(vcBytes []byte) ([]string, error) {
+	vc := &anchorCredential{}
+
+	if err := json.Unmarshal(vcBytes, vc); err != nil {
+		return nil, fmt.Errorf("unmarshal anchor credential: %w", err)
+	}
+
+	if len(vc.Subject) == 0 {
+		return nil, nil
+	}
+
+	var subjects []credentialSubject
+
+	if strings.HasPrefix(strings.TrimSpace(string(vc.Subject)), "[") {
+		if err := json.Unmarshal(vc.Subject, &subjects); err != nil {
+			return nil, fmt.Errorf("unmarshal anchor credential subjects: %w", err)
+		}
+	} else {
+		s := credentialSubject{}
+
+		if err := json.Unmarshal(vc.Subject, &s); err != nil {
+			return nil, fmt.Errorf("unmarshal anchor credential subject: %w", err)
+		}
+
+		subjects = append(subjects, s)
+	}
+
+	var coreIndexes []string
+
+	for _, s := range subjects {
+		if s.Anchor != "" {
+			coreIndexes = append(coreIndexes, s.Anchor)
+		}
+	}
+
+	return coreIndexes, nil
+}
+
+// toResourceHash returns the base64url-encoded multihash for the given hashlink, CID or resource hash, which is the
+// key of the content in the local CAS.
+func toResourceHash(uri string) (string, error) {
+	if strings.HasPrefix(uri, hashlink.HLPrefix) {
+		resourceHash, err := hashlink.GetResourceHashFromHashLink(uri)
+		if err != nil {
+			return "", err
+		}
+
+		uri = resourceHash
+	}
+
+	return multihash.CIDOrMultihashToMultihash(uri)
+}
+
+func resolveConfig(cfg *Config) *Config {
+	config := *cfg
+
+	if config.Interval == 0 {
+		config.Interval = defaultInterval
+	}
+
+	if config.GracePeriod == 0 {
+		config.GracePeriod = defaultGracePeriod
+	}
+
+	if config.MaxSweepCount == 0 {
+		config.MaxSweepCount = defaultMaxSweepCount
+	}
+
+	if config.CompressionAlgorithm == "" {
+		config.CompressionAlgorithm = defaultCompressionAlgorithm
+	}
+
+	return &config
+}
@@ -0,0 +1,591 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+	"github.com/trustbloc/sidetree-svc-go/pkg/compression"
+	"github.com/trustbloc/sidetree-svc-go/pkg/versions/1_0/txnprovider/models"
+
+	"github.com/trustbloc/orb/pkg/anchor/subject"
+	anchorutil "github.com/trustbloc/orb/pkg/anchor/util"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/linkset"
+)
+
+const gzipAlgorithm = "GZIP"
+
+func TestNew(t *testing.T) {
+	taskMgr := &mockTaskManager{}
+
+	c := New(Config{}, &Providers{}, taskMgr)
+	require.NotNil(t, c)
+	require.Equal(t, taskName, taskMgr.taskID)
+	require.Equal(t, defaultInterval, taskMgr.interval)
+	require.Equal(t, defaultGracePeriod, c.GracePeriod)
+	require.Equal(t, defaultMaxSweepCount, c.MaxSweepCount)
+	require.Equal(t, defaultCompressionAlgorithm, c.CompressionAlgorithm)
+	require.Nil(t, c.LastReport())
+}
+
+func TestCollector_Collect(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		archive := newMockArchive()
+		g.providers.Archive = archive
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		report, err := c.Collect()
+		require.NoError(t, err)
+		require.False(t, report.DryRun)
+		require.Equal(t, 15, report.Marked)
+		require.Equal(t, []string{g.orphan}, report.Candidates)
+		require.Equal(t, 1, report.Deleted)
+		require.Equal(t, 1, report.Archived)
+		require.Equal(t, report, c.LastReport())
+
+		require.False(t, g.cas.contains(g.orphan))
+		require.True(t, g.cas.contains(g.recentOrphan))
+		require.Contains(t, archive.content, g.orphan)
+
+		for _, resourceHash := range g.live {
+			require.True(t, g.cas.contains(resourceHash))
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		c := New(Config{GracePeriod: time.Hour, DryRun: true}, g.providers, &mockTaskManager{})
+
+		report, err := c.Collect()
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, []string{g.orphan}, report.Candidates)
+		require.Zero(t, report.Deleted)
+		require.True(t, g.cas.contains(g.orphan))
+	})
+
+	t.Run("max sweep count", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		for i := 0; i < 5; i++ {
+			g.cas.put(t, []byte(fmt.Sprintf("orphan %d", i)), time.Now().Add(-2*time.Hour))
+		}
+
+		c := New(Config{GracePeriod: time.Hour, MaxSweepCount: 3}, g.providers, &mockTaskManager{})
+
+		report, err := c.Collect()
+		require.NoError(t, err)
+		require.Len(t, report.Candidates, 3)
+		require.Equal(t, 3, report.Deleted)
+	})
+
+	t.Run("referenced content not found -> skipped", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.cas.remove(g.previousAnchor)
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		report, err := c.Collect()
+		require.NoError(t, err)
+		require.Equal(t, 10, report.Marked)
+		// The files of the previous anchor are no longer referenced.
+		require.Len(t, report.Candidates, 6)
+		require.Contains(t, report.Candidates, g.orphan)
+	})
+
+	t.Run("malformed anchor linkset -> skipped", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		old := time.Now().Add(-2 * time.Hour)
+
+		invalidHL := hashlink.GetHashLinkFromResourceHash(g.cas.put(t, []byte("{"), old))
+		emptyHL := hashlink.GetHashLinkFromResourceHash(g.cas.put(t, []byte("{}"), old))
+		unknownHL := hashlink.GetHashLinkFromResourceHash(g.cas.put(t,
+			marshal(t, linkset.New(linkset.NewLink(testutil.MustParseURL("hl:unknown"), nil, nil, nil, nil, nil))), old))
+
+		refStore := g.providers.AnchorRefStore.(*mockAnchorRefStore)
+		refStore.links = append(refStore.links,
+			testutil.MustParseURL(invalidHL), testutil.MustParseURL(emptyHL), testutil.MustParseURL(unknownHL),
+		)
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		report, err := c.Collect()
+		require.NoError(t, err)
+		require.Len(t, report.Skipped, 3)
+		require.Contains(t, report.Skipped, invalidHL)
+		require.Contains(t, report.Skipped, emptyHL)
+		require.Contains(t, report.Skipped, unknownHL)
+		require.Equal(t, []string{g.orphan}, report.Candidates)
+
+		for _, hl := range report.Skipped {
+			resourceHash, e := hashlink.GetResourceHashFromHashLink(hl)
+			require.NoError(t, e)
+			require.True(t, g.cas.contains(resourceHash))
+		}
+
+		for _, resourceHash := range g.live {
+			require.True(t, g.cas.contains(resourceHash))
+		}
+	})
+
+	t.Run("CAS read error -> nothing deleted", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.cas.errRead = errors.New("injected read error")
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		report, err := c.Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected read error")
+		require.Nil(t, report)
+		require.True(t, g.cas.contains(g.orphan))
+	})
+
+	t.Run("anchor ref store error", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.providers.AnchorRefStore = &mockAnchorRefStore{err: orberrors.NewTransient(errors.New("injected query error"))}
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("anchor link store error", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.providers.AnchorLinkStore = &mockAnchorLinkStore{err: errors.New("injected query error")}
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("VC store error", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.providers.VCStore = &mockstore.Store{ErrQuery: errors.New("injected query error")}
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("payload error", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.providers.AnchorLinksetBuilder = &mockAnchorLinksetBuilder{err: errors.New("injected payload error")}
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected payload error")
+	})
+
+	t.Run("sweep error", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.cas.errQuery = errors.New("injected query error")
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("archive error -> not deleted", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		archive := newMockArchive()
+		archive.err = errors.New("injected put error")
+
+		g.providers.Archive = archive
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+		require.True(t, g.cas.contains(g.orphan))
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		g := newTestGraph(t)
+
+		g.cas.errDelete = errors.New("injected delete error")
+
+		c := New(Config{GracePeriod: time.Hour}, g.providers, &mockTaskManager{})
+
+		_, err := c.Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected delete error")
+	})
+}
+
+func TestCollector_Run(t *testing.T) {
+	g := newTestGraph(t)
+
+	taskMgr := &mockTaskManager{}
+
+	c := New(Config{GracePeriod: time.Hour, DryRun: true}, g.providers, taskMgr)
+
+	taskMgr.handler()
+
+	require.NotNil(t, c.LastReport())
+	require.Equal(t, []string{g.orphan}, c.LastReport().Candidates)
+
+	g.cas.errQuery = errors.New("injected query error")
+
+	taskMgr.handler()
+
+	require.NotNil(t, c.LastReport())
+}
+
+func TestCoreIndexesFromCredential(t *testing.T) {
+	t.Run("single subject", func(t *testing.T) {
+		coreIndexes, err := coreIndexesFromCredential([]byte(`{"credentialSubject":{"anchor":"hl:uEiA1"}}`))
+		require.NoError(t, err)
+		require.Equal(t, []string{"hl:uEiA1"}, coreIndexes)
+	})
+
+	t.Run("multiple subjects", func(t *testing.T) {
+		coreIndexes, err := coreIndexesFromCredential(
+			[]byte(`{"credentialSubject":[{"anchor":"hl:uEiA1"},{"id":"x"},{"anchor":"hl:uEiA2"}]}`))
+		require.NoError(t, err)
+		require.Equal(t, []string{"hl:uEiA1", "hl:uEiA2"}, coreIndexes)
+	})
+
+	t.Run("no subject", func(t *testing.T) {
+		coreIndexes, err := coreIndexesFromCredential([]byte(`{"id":"x"}`))
+		require.NoError(t, err)
+		require.Empty(t, coreIndexes)
+	})
+
+	t.Run("invalid credential", func(t *testing.T) {
+		_, err := coreIndexesFromCredential([]byte(`{`))
+		require.Error(t, err)
+
+		_, err = coreIndexesFromCredential([]byte(`{"credentialSubject":[1]}`))
+		require.Error(t, err)
+
+		_, err = coreIndexesFromCredential([]byte(`{"credentialSubject":1}`))
+		require.Error(t, err)
+	})
+}
+
+type testGraph struct {
+	providers *Providers
+	cas       *mockCAS
+
+	// live contains the resource hashes of all content that's reachable from live anchors.
+	live           []string
+	previousAnchor string
+	orphan         string
+	recentOrphan   string
+}
+
+// newTestGraph creates the following content:
+//   - an anchor linkset (referenced by the anchor ref store) whose core index references a core proof file and a
+//     provisional index file which references a provisional proof file and a chunk file
+//   - a previous anchor linkset (referenced only by the first anchor) with a core index file
+//   - a pending anchor link (in the anchor link store) with a core index file
+//   - an anchor credential (in the VC store) with a core index file
+//   - an old orphan and a recent orphan.
+func newTestGraph(t *testing.T) *testGraph {
+	t.Helper()
+
+	old := time.Now().Add(-2 * time.Hour)
+
+	cas := newMockCAS()
+	builder := &mockAnchorLinksetBuilder{payloads: make(map[string]*subject.Payload)}
+	cp := compression.New(compression.WithDefaultAlgorithms())
+
+	g := &testGraph{cas: cas}
+
+	putFile := func(content []byte) string {
+		compressed, err := cp.Compress(gzipAlgorithm, content)
+		require.NoError(t, err)
+
+		resourceHash := cas.put(t, compressed, old)
+
+		g.live = append(g.live, resourceHash)
+
+		return hashlink.GetHashLinkFromResourceHash(resourceHash)
+	}
+
+	putCoreIndex := func(name string) string {
+		coreIndexFile := &models.CoreIndexFile{
+			CoreProofFileURI: putFile([]byte(name + " core proof")),
+			ProvisionalIndexFileURI: putFile(marshal(t, &models.ProvisionalIndexFile{
+				ProvisionalProofFileURI: putFile([]byte(name + " provisional proof")),
+				Chunks:                  []models.Chunk{{ChunkFileURI: putFile([]byte(name + " chunk"))}},
+			})),
+		}
+
+		return putFile(marshal(t, coreIndexFile))
+	}
+
+	newAnchorLink := func(name string, payload *subject.Payload) *linkset.Link {
+		anchorURL := testutil.MustParseURL("hl:" + name)
+
+		builder.payloads[anchorURL.String()] = payload
+
+		return linkset.NewLink(anchorURL, nil, nil, nil, nil, nil)
+	}
+
+	putAnchorLinkset := func(anchorLink *linkset.Link) string {
+		resourceHash := cas.put(t, marshal(t, linkset.New(anchorLink)), old)
+
+		g.live = append(g.live, resourceHash)
+
+		return hashlink.GetHashLinkFromResourceHash(resourceHash)
+	}
+
+	previousAnchorHL := putAnchorLinkset(newAnchorLink("previous", &subject.Payload{CoreIndex: putCoreIndex("previous")}))
+
+	g.previousAnchor, _ = hashlink.GetResourceHashFromHashLink(previousAnchorHL) //nolint:errcheck
+
+	anchorHL := putAnchorLinkset(newAnchorLink("anchor", &subject.Payload{
+		CoreIndex: putCoreIndex("anchor"),
+		PreviousAnchors: []*subject.SuffixAnchor{
+			{Suffix: "create"},
+			{Suffix: "update", Anchor: previousAnchorHL},
+		},
+	}))
+
+	pendingAnchorLink := newAnchorLink("pending", &subject.Payload{CoreIndex: putFile(marshal(t, &models.CoreIndexFile{}))})
+
+	vcStore, err := mem.NewProvider().OpenStore("verifiable")
+	require.NoError(t, err)
+
+	require.NoError(t, vcStore.Put("vc1",
+		[]byte(fmt.Sprintf(`{"id":"vc1","credentialSubject":{"anchor":"%s"}}`,
+			putFile(marshal(t, &models.CoreIndexFile{CoreProofFileURI: putFile([]byte("vc core proof"))})))),
+		storage.Tag{Name: anchorutil.VCStoreTagName},
+	))
+
+	g.orphan = cas.put(t, []byte("orphan"), old)
+	g.recentOrphan = cas.put(t, []byte("recent orphan"), time.Now())
+
+	g.providers = &Providers{
+		CAS:                  cas,
+		AnchorRefStore:       &mockAnchorRefStore{links: []*url.URL{testutil.MustParseURL(anchorHL)}},
+		AnchorLinkStore:      &mockAnchorLinkStore{links: []*linkset.Link{pendingAnchorLink}},
+		VCStore:              vcStore,
+		AnchorLinksetBuilder: builder,
+		Decompressor:         cp,
+	}
+
+	return g
+}
+
+func marshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return b
+}
+
+type casEntry struct {
+	content []byte
+	created time.Time
+}
+
+type mockCAS struct {
+	entries   map[string]*casEntry
+	order     []string
+	errRead   error
+	errQuery  error
+	errDelete error
+}
+
+func newMockCAS() *mockCAS {
+	return &mockCAS{entries: make(map[string]*casEntry)}
+}
+
+func (m *mockCAS) put(t *testing.T, content []byte, created time.Time) string {
+	t.Helper()
+
+	resourceHash, err := hashlink.New().CreateResourceHash(content)
+	require.NoError(t, err)
+
+	m.entries[resourceHash] = &casEntry{content: content, created: created}
+	m.order = append(m.order, resourceHash)
+
+	return resourceHash
+}
+
+func (m *mockCAS) remove(resourceHash string) {
+	delete(m.entries, resourceHash)
+}
+
+func (m *mockCAS) contains(resourceHash string) bool {
+	_, ok := m.entries[resourceHash]
+
+	return ok
+}
+
+func (m *mockCAS) Read(address string) ([]byte, error) {
+	if m.errRead != nil {
+		return nil, m.errRead
+	}
+
+	e, ok := m.entries[address]
+	if !ok {
+		return nil, orberrors.ErrContentNotFound
+	}
+
+	return e.content, nil
+}
+
+func (m *mockCAS) QueryCreatedBefore(t time.Time, fn func(resourceHash string) error) error {
+	if m.errQuery != nil {
+		return m.errQuery
+	}
+
+	for _, resourceHash := range m.order {
+		e, ok := m.entries[resourceHash]
+		if !ok || !e.created.Before(t) {
+			continue
+		}
+
+		if err := fn(resourceHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *mockCAS) Delete(resourceHash string) error {
+	if m.errDelete != nil {
+		return m.errDelete
+	}
+
+	delete(m.entries, resourceHash)
+
+	return nil
+}
+
+type mockAnchorRefStore struct {
+	links []*url.URL
+	err   error
+}
+
+func (m *mockAnchorRefStore) ForEachLink(fn func(link *url.URL) error) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	for _, link := range m.links {
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type mockAnchorLinkStore struct {
+	links []*linkset.Link
+	err   error
+}
+
+func (m *mockAnchorLinkStore) ForEach(fn func(anchorLink *linkset.Link) error) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	for _, link := range m.links {
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type mockAnchorLinksetBuilder struct {
+	payloads map[string]*subject.Payload
+	err      error
+}
+
+func (m *mockAnchorLinksetBuilder) GetPayloadFromAnchorLink(anchorLink *linkset.Link) (*subject.Payload, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	payload, ok := m.payloads[anchorLink.Anchor().String()]
+	if !ok {
+		return nil, fmt.Errorf("payload not found for anchor [%s]", anchorLink.Anchor())
+	}
+
+	return payload, nil
+}
+
+type mockArchive struct {
+	content map[string][]byte
+	err     error
+}
+
+func newMockArchive() *mockArchive {
+	return &mockArchive{content: make(map[string][]byte)}
+}
+
+func (m *mockArchive) Put(key string, content []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	m.content[key] = content
+
+	return nil
+}
+
+type mockTaskManager struct {
+	taskID   string
+	interval time.Duration
+	handler  func()
+}
+
+func (m *mockTaskManager) RegisterTask(taskID string, interval time.Duration, handler func()) {
+	m.taskID = taskID
+	m.interval = interval
+	m.handler = handler
+}
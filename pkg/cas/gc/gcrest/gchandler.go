@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gcrest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/cas/gc"
+)
+
+var logger = log.New("cas-gc-rest", log.WithFields(logfields.WithServiceEndpoint(gcPath)))
+
+const (
+	gcPath                      = "/cas-gc"
+	notFoundResponse            = "Not Found.\n"
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type collector interface {
+	LastReport() *gc.Report
+}
+
+// ReportHandler implements a REST handler that returns the report of the last CAS garbage collection run,
+// including the candidates for deletion (which are only reported in dry-run mode).
+type ReportHandler struct {
+	collector collector
+	marshal   func(v interface{}) ([]byte, error)
+}
+
+// NewReportHandler returns a new REST handler that returns the report of the last CAS garbage collection run.
+func NewReportHandler(c collector) *ReportHandler {
+	return &ReportHandler{
+		collector: c,
+		marshal:   json.Marshal,
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *ReportHandler) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *ReportHandler) Path() string {
+	return gcPath
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *ReportHandler) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *ReportHandler) handleGet(w http.ResponseWriter, _ *http.Request) {
+	report := h.collector.LastReport()
+	if report == nil {
+		logger.Debug("No CAS garbage collection run has completed on this server instance")
+
+		writeResponse(w, http.StatusNotFound, []byte(notFoundResponse))
+
+		return
+	}
+
+	reportBytes, err := h.marshal(report)
+	if err != nil {
+		logger.Error("Error marshalling CAS garbage collection report", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, reportBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			log.WriteResponseBodyError(logger, err)
+
+			return
+		}
+
+		log.WroteResponse(logger, body)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gcrest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/gc"
+)
+
+const gcURL = "https://example.com/cas-gc"
+
+func TestNewReportHandler(t *testing.T) {
+	h := NewReportHandler(&mockCollector{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, "/cas-gc", h.Path())
+}
+
+func TestReportHandler_Handler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c := &mockCollector{
+			report: &gc.Report{
+				DryRun:     true,
+				Marked:     15,
+				Candidates: []string{"uEiCeCwiJh3ikvBDjDFjeAcX0gVjzR7dygDPb8Vbd4FpWQQ"},
+				Skipped:    []string{"hl:uEiDuIicNljP8PoHJk6_aA7w1d4U3FAvDMfF7Dsh7fkw3Wg"},
+			},
+		}
+
+		h := NewReportHandler(c)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, gcURL, http.NoBody)
+
+		h.handleGet(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		report := &gc.Report{}
+		require.NoError(t, json.Unmarshal(respBytes, report))
+		require.True(t, report.DryRun)
+		require.Equal(t, 15, report.Marked)
+		require.Equal(t, c.report.Candidates, report.Candidates)
+		require.Equal(t, c.report.Skipped, report.Skipped)
+	})
+
+	t.Run("No report", func(t *testing.T) {
+		h := NewReportHandler(&mockCollector{})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, gcURL, http.NoBody)
+
+		h.handleGet(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusNotFound, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewReportHandler(&mockCollector{report: &gc.Report{}})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, gcURL, http.NoBody)
+
+		h.handleGet(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+type mockCollector struct {
+	report *gc.Report
+}
+
+func (m *mockCollector) LastReport() *gc.Report {
+	return m.report
+}
@@ -8,6 +8,7 @@ package ipfs
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -15,11 +16,15 @@ import (
 	"time"
 
 	"github.com/bluele/gcache"
+	"github.com/fxamacker/cbor/v2"
+	gocid "github.com/ipfs/go-cid"
 	shell "github.com/ipfs/go-ipfs-api"
+	"github.com/multiformats/go-multibase"
 	"github.com/trustbloc/logutil-go/pkg/log"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/multihash"
@@ -32,6 +37,9 @@ var logger = log.New(logModule)
 const (
 	defaultCacheSize = 1000
 	casType          = "ipfs"
+
+	// dagJSONCodec is the multicodec code of dag-json (not defined by go-cid).
+	dagJSONCodec = 0x0129
 )
 
 type metricsProvider interface {
@@ -94,31 +102,61 @@ func (m *Client) Write(content []byte) (string, error) {
 		return "", err
 	}
 
-	links := []string{"ipfs://" + cid}
+	options, err := getOptions(m.opts)
+	if err != nil {
+		return "", err
+	}
 
-	hl, err := m.hl.CreateHashLink(content, links)
+	// The resource hash is the same as the one that's produced by the local CAS, i.e. content that's larger than
+	// a single chunk is addressed by the root of its UnixFS DAG (for v1 CIDs).
+	resourceHash, err := options.ResourceHash(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource hash for ipfs: %w", err)
+	}
+
+	resourceHash, err = options.FormatResourceHash(resourceHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to format resource hash for ipfs: %w", err)
+	}
+
+	metadata, err := m.hl.CreateMetadataFromLinks([]string{"ipfs://" + cid})
 	if err != nil {
 		return "", fmt.Errorf("failed to create hashlink for ipfs: %w", err)
 	}
 
+	hl := hashlink.GetHashLink(resourceHash, metadata)
+
 	logger.Debug("Wrote content to IPFS", logfields.WithHashlink(hl), logfields.WithCID(cid))
 
 	return hl, nil
 }
 
 // WriteWithCIDFormat writes the given content to IPFS using the provided CID format options.
-// Returns the address (CID) of the content.
-// TODO (#443): Support v1 CID formats (different multibases and multicodecs) other than just the IPFS default.
+// Returns the address (CID) of the content. Content is always added with raw leaves. If a codec other than raw
+// or a multibase other than the default is requested then the CID is re-encoded, i.e. the CID refers to the
+// same block (multihash) in IPFS. Since the content isn't re-encoded, it must already be (canonically) encoded
+// in the requested codec, otherwise an error is returned. Content that spans multiple chunks may only be written
+// with the raw codec since its CID refers to the root (dag-pb) node of the UnixFS DAG. An error is returned if a v1
+// CID returned by IPFS doesn't match the locally computed root of the content's UnixFS DAG.
 func (m *Client) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFormatOption) (string, error) {
 	if len(content) == 0 {
 		return "", errors.New("empty content")
 	}
 
-	options, err := getOptions(opts)
+	options, err := extendedcasclient.GetCIDFormatOptions(opts...)
 	if err != nil {
 		return "", err
 	}
 
+	if options.Codec != extendedcasclient.CodecRaw && unixfs.IsChunked(len(content)) {
+		return "", fmt.Errorf("content of size %d spans multiple chunks and cannot be written with the %s codec",
+			len(content), options.Codec)
+	}
+
+	if err = validateCodec(content, options.Codec); err != nil {
+		return "", fmt.Errorf("content cannot be written with the %s codec: %w", options.Codec, err)
+	}
+
 	var v1AddOpt []shell.AddOpts
 
 	if options.CIDVersion == 1 {
@@ -134,6 +172,19 @@ func (m *Client) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CI
 		return "", orberrors.NewTransient(err)
 	}
 
+	// Make sure that IPFS built the same DAG as the local CAS (e.g. that it isn't configured with
+	// a different chunker), otherwise the content would be addressed differently.
+	if err = options.VerifyCID(cid, content); err != nil {
+		return "", err
+	}
+
+	if options.CIDVersion == 1 && (options.Multibase != "" || options.Codec != extendedcasclient.CodecRaw) {
+		cid, err = formatCID(cid, &options)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	logger.Debug("Wrote content to IPFS", logfields.WithCID(cid), logfields.WithCIDVersion(options.CIDVersion))
 
 	return cid, nil
@@ -146,14 +197,27 @@ func (m *Client) GetPrimaryWriterType() string {
 
 // Read reads the content for the given CID from CAS.
 // returns the contents of CID.
+// If a (v1) CID is derived from a hash and no content is found then the content is read using the dag-pb CID
+// of the hash since the hash may be the root of the UnixFS DAG of content that spans multiple chunks.
 func (m *Client) Read(cidOrHash string) ([]byte, error) {
 	logger.Debug("Reading CID or hash from IPFS", logfields.WithKey(cidOrHash))
 
-	cid, err := m.getCID(cidOrHash)
+	cid, dagCID, err := m.getCID(cidOrHash)
 	if err != nil {
 		return nil, fmt.Errorf("value[%s] passed to ipfs reader is not CID and cannot be converted to CID: %w", cidOrHash, err)
 	}
 
+	content, err := m.read(cid)
+	if err != nil && dagCID != "" && errors.Is(err, orberrors.ErrContentNotFound) {
+		logger.Debug("Content not found for raw CID. Reading dag-pb CID.", logfields.WithCID(dagCID))
+
+		return m.read(dagCID)
+	}
+
+	return content, err
+}
+
+func (m *Client) read(cid string) ([]byte, error) {
 	if m.cache.Has(cid) {
 		m.metrics.CASIncrementCacheHitCount()
 	}
@@ -202,73 +266,199 @@ func (m *Client) get(cid string) ([]byte, error) {
 	return content, nil
 }
 
-func (m *Client) getCID(cidOrHash string) (string, error) {
+// getCID returns the CID for the given CID, hashlink or hash. If a v1 CID is derived from a hash then the
+// dag-pb CID of the hash is also returned.
+func (m *Client) getCID(cidOrHash string) (string, string, error) {
 	cid := cidOrHash
 
 	if strings.HasPrefix(cidOrHash, hashlink.HLPrefix) {
 		hashlinkInfo, err := m.hl.ParseHashLink(cidOrHash)
 		if err != nil {
-			return "", fmt.Errorf("failed to parse hash link in ipfs client: %w", err)
+			return "", "", fmt.Errorf("failed to parse hash link in ipfs client: %w", err)
 		}
 
 		cid = hashlinkInfo.ResourceHash
 	}
 
-	if !multihash.IsValidCID(cid) {
-		var err error
-
-		cid, err = m.getCIDFromHash(cid)
+	if multihash.IsValidCID(cid) {
+		readable, err := readableCID(cid)
 		if err != nil {
-			return "", fmt.Errorf("failed to get cid in ipfs reader: %w", err)
+			return "", "", err
 		}
 
-		logger.Debug("Converted multihash to CID", logfields.WithMultihash(cidOrHash), logfields.WithCID(cid))
+		return readable, "", nil
 	}
 
-	return cid, nil
+	hash := cid
+
+	cid, dagCID, err := m.getCIDFromHash(hash)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get cid in ipfs reader: %w", err)
+	}
+
+	logger.Debug("Converted multihash to CID", logfields.WithMultihash(cidOrHash), logfields.WithCID(cid))
+
+	return cid, dagCID, nil
 }
 
-func (m *Client) getCIDFromHash(hash string) (string, error) {
+func (m *Client) getCIDFromHash(hash string) (string, string, error) {
 	options, err := getOptions(m.opts)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	var cid string
-
 	switch options.CIDVersion {
 	case 0:
-		cid, err = multihash.ToV0CID(hash)
-		if err != nil {
-			return "", fmt.Errorf("value[%s] cannot be converted to V0 CID: %w", hash, err)
+		cid, e := multihash.ToV0CID(hash)
+		if e != nil {
+			return "", "", fmt.Errorf("value[%s] cannot be converted to V0 CID: %w", hash, e)
 		}
+
+		return cid, "", nil
 	case 1:
-		cid, err = multihash.ToV1CID(hash)
-		if err != nil {
-			return "", fmt.Errorf("value[%s] cannot be converted to V1 CID: %w", hash, err)
+		cid, e := multihash.ToV1CID(hash)
+		if e != nil {
+			return "", "", fmt.Errorf("value[%s] cannot be converted to V1 CID: %w", hash, e)
+		}
+
+		dagCID, e := multihash.ToV1DagPBCID(hash)
+		if e != nil {
+			return "", "", fmt.Errorf("value[%s] cannot be converted to V1 dag-pb CID: %w", hash, e)
 		}
+
+		return cid, dagCID, nil
 	default:
-		return "", fmt.Errorf("cid version[%d] not supported", options.CIDVersion)
+		return "", "", fmt.Errorf("cid version[%d] not supported", options.CIDVersion)
 	}
-
-	return cid, nil
 }
 
 func getOptions(opts []extendedcasclient.CIDFormatOption) (extendedcasclient.CIDFormatOptions, error) {
-	options := extendedcasclient.CIDFormatOptions{CIDVersion: 1}
+	return extendedcasclient.GetCIDFormatOptions(opts...)
+}
 
-	for _, option := range opts {
-		if option != nil {
-			option(&options)
-		}
+// formatCID re-encodes the given (v1) CID returned by IPFS using the codec and multibase in the given options.
+func formatCID(cid string, options *extendedcasclient.CIDFormatOptions) (string, error) {
+	codec, err := options.CodecCode()
+	if err != nil {
+		return "", err
+	}
+
+	encoding, err := options.MultibaseEncoding(multibase.Base32)
+	if err != nil {
+		return "", err
+	}
+
+	parsedCID, err := gocid.Decode(cid)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode CID [%s] returned by IPFS: %w", cid, err)
+	}
+
+	if parsedCID.Type() != gocid.Raw {
+		// Content that spans multiple chunks is addressed by the root of its UnixFS DAG, so only the
+		// multibase may be changed.
+		codec = parsedCID.Type()
+	}
+
+	formattedCID, err := gocid.NewCidV1(codec, parsedCID.Hash()).StringOfBase(encoding)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode CID [%s]: %w", cid, err)
+	}
+
+	return formattedCID, nil
+}
+
+// validateCodec returns an error if the given content isn't canonically encoded in the given codec, i.e. if the
+// CID of the content with the given codec wouldn't refer to a valid block.
+func validateCodec(content []byte, codec extendedcasclient.Codec) error {
+	switch codec {
+	case extendedcasclient.CodecDAGJSON:
+		return validateDAGJSON(content)
+	case extendedcasclient.CodecDAGCBOR:
+		return validateDAGCBOR(content)
+	default:
+		return nil
 	}
+}
 
-	if options.CIDVersion != 0 && options.CIDVersion != 1 {
-		return extendedcasclient.CIDFormatOptions{},
-			fmt.Errorf("%d is not a supported CID version. It must be either 0 or 1", options.CIDVersion)
+// validateDAGJSON ensures that the content is JSON with sorted map keys and no insignificant whitespace.
+func validateDAGJSON(content []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var doc interface{}
+
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
 	}
 
-	return options, nil
+	if decoder.More() {
+		return errors.New("invalid JSON: unexpected data after top-level value")
+	}
+
+	buf := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encode JSON: %w", err)
+	}
+
+	if !bytes.Equal(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), content) {
+		return errors.New("JSON is not canonically encoded")
+	}
+
+	return nil
+}
+
+// validateDAGCBOR ensures that the content is CBOR with canonically sorted map keys and 64-bit floats.
+func validateDAGCBOR(content []byte) error {
+	var doc interface{}
+
+	if err := cbor.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("invalid CBOR: %w", err)
+	}
+
+	encMode, err := cbor.EncOptions{Sort: cbor.SortCanonical, ShortestFloat: cbor.ShortestFloatNone}.EncMode()
+	if err != nil {
+		return fmt.Errorf("create CBOR encoder: %w", err)
+	}
+
+	encoded, err := encMode.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("encode CBOR: %w", err)
+	}
+
+	if !bytes.Equal(encoded, content) {
+		return errors.New("CBOR is not canonically encoded")
+	}
+
+	return nil
+}
+
+// readableCID returns a CID that may be used to read the content of the given CID from IPFS. V1 CIDs are
+// re-encoded using the default multibase (base32). Since content is always added with raw leaves, the block
+// of a CID with the dag-json or dag-cbor codec is read using the raw CID of the same multihash.
+func readableCID(cid string) (string, error) {
+	if strings.HasPrefix(cid, "/ipns/") {
+		return cid, nil
+	}
+
+	parsedCID, err := gocid.Decode(cid)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode CID [%s]: %w", cid, err)
+	}
+
+	if parsedCID.Version() == 0 {
+		return cid, nil
+	}
+
+	switch parsedCID.Type() {
+	case dagJSONCodec, gocid.DagCBOR:
+		return gocid.NewCidV1(gocid.Raw, parsedCID.Hash()).String(), nil
+	default:
+		return parsedCID.String(), nil
+	}
 }
 
 func closeAndLog(rc io.Closer) {
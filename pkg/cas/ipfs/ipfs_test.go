@@ -13,10 +13,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
 	dctest "github.com/ory/dockertest/v3"
 	dc "github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/require"
@@ -24,8 +28,10 @@ import (
 
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	"github.com/trustbloc/orb/pkg/cas/ipfs/mocks"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
 )
 
 //go:generate counterfeiter -o ./mocks/ipfsclient.gen.go --fake-name IPFSClient . ipfsClient
@@ -205,6 +211,163 @@ func TestWrite(t *testing.T) {
 	})
 }
 
+func TestWriteWithCIDFormat(t *testing.T) {
+	const rawCID = "bafkreihnoabliopjvscf6irvpwbcxlauirzq7pnwafwt5skdekl3t3e7om"
+
+	t.Run("dag-cbor codec", func(t *testing.T) {
+		content := []byte{0xa1, 0x61, 0x61, 0x01} // {"a": 1}
+
+		ipfs := &mocks.IPFSClient{}
+		ipfs.AddReturns(newRawCID(t, content).String(), nil)
+
+		cas := newClient(ipfs, 0, &orbmocks.MetricsProvider{})
+
+		cid, err := cas.WriteWithCIDFormat(content,
+			extendedcasclient.WithCodec(extendedcasclient.CodecDAGCBOR))
+		require.NoError(t, err)
+		require.Equal(t, gocid.NewCidV1(gocid.DagCBOR, newRawCID(t, content).Hash()).String(), cid)
+
+		ipfs.CatReturns(newMockReader(content), nil)
+
+		read, err := cas.Read(cid)
+		require.NoError(t, err)
+		require.Equal(t, content, read)
+		require.Equal(t, newRawCID(t, content).String(), ipfs.CatArgsForCall(0))
+	})
+
+	t.Run("dag-json codec with base64url multibase", func(t *testing.T) {
+		content := []byte(`{"a":1,"b":["c"]}`)
+
+		ipfs := &mocks.IPFSClient{}
+		ipfs.AddReturns(newRawCID(t, content).String(), nil)
+
+		cas := newClient(ipfs, 0, &orbmocks.MetricsProvider{})
+
+		cid, err := cas.WriteWithCIDFormat(content,
+			extendedcasclient.WithCodec(extendedcasclient.CodecDAGJSON),
+			extendedcasclient.WithMultibase(extendedcasclient.MultibaseBase64URL))
+		require.NoError(t, err)
+
+		expectedCID, err := gocid.NewCidV1(dagJSONCodec, newRawCID(t, content).Hash()).StringOfBase(multibase.Base64url)
+		require.NoError(t, err)
+		require.Equal(t, expectedCID, cid)
+
+		ipfs.CatReturns(newMockReader(content), nil)
+
+		read, err := cas.Read(cid)
+		require.NoError(t, err)
+		require.Equal(t, content, read)
+		require.Equal(t, newRawCID(t, content).String(), ipfs.CatArgsForCall(0))
+	})
+
+	t.Run("error - content isn't valid dag-json", func(t *testing.T) {
+		for _, content := range []string{"content", `{"b":1,"a":2}`, `{"a": 1}`, `{"a":1}{}`} {
+			cas := newClient(&mocks.IPFSClient{}, 0, &orbmocks.MetricsProvider{})
+
+			cid, err := cas.WriteWithCIDFormat([]byte(content),
+				extendedcasclient.WithCodec(extendedcasclient.CodecDAGJSON))
+			require.Error(t, err)
+			require.Empty(t, cid)
+			require.Contains(t, err.Error(), "content cannot be written with the dag-json codec")
+		}
+	})
+
+	t.Run("error - content isn't valid dag-cbor", func(t *testing.T) {
+		for _, content := range [][]byte{
+			[]byte("content"),
+			{0xa2, 0x62, 0x61, 0x61, 0x01, 0x61, 0x62, 0x02}, // {"aa": 1, "b": 2} (keys not sorted by length)
+			{0xf9, 0x3c, 0x00}, // 16-bit float
+		} {
+			cas := newClient(&mocks.IPFSClient{}, 0, &orbmocks.MetricsProvider{})
+
+			cid, err := cas.WriteWithCIDFormat(content,
+				extendedcasclient.WithCodec(extendedcasclient.CodecDAGCBOR))
+			require.Error(t, err)
+			require.Empty(t, cid)
+			require.Contains(t, err.Error(), "content cannot be written with the dag-cbor codec")
+		}
+	})
+
+	t.Run("base58btc multibase", func(t *testing.T) {
+		ipfs := &mocks.IPFSClient{}
+		ipfs.AddReturns(rawCID, nil)
+
+		cas := newClient(ipfs, 0, &orbmocks.MetricsProvider{},
+			extendedcasclient.WithMultibase(extendedcasclient.MultibaseBase58BTC))
+
+		hl, err := cas.Write([]byte("content"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(hl, "hl:zQm"))
+
+		cid, err := cas.WriteWithCIDFormat([]byte("content"),
+			extendedcasclient.WithMultibase(extendedcasclient.MultibaseBase58BTC))
+		require.NoError(t, err)
+		require.Equal(t, "zb2rhnd8t1gYEQMzhAEB1VRfejM2jkcSLEazTMCut6HpRUUgz", cid)
+	})
+
+	t.Run("error - codec not supported for content that spans multiple chunks", func(t *testing.T) {
+		cas := newClient(&mocks.IPFSClient{}, 0, &orbmocks.MetricsProvider{})
+
+		cid, err := cas.WriteWithCIDFormat(make([]byte, 256*1024+1),
+			extendedcasclient.WithCodec(extendedcasclient.CodecDAGCBOR))
+		require.Error(t, err)
+		require.Empty(t, cid)
+		require.Contains(t, err.Error(), "spans multiple chunks and cannot be written with the dag-cbor codec")
+	})
+
+	t.Run("error - CID returned by IPFS doesn't match the content", func(t *testing.T) {
+		ipfs := &mocks.IPFSClient{}
+		ipfs.AddReturns(newRawCID(t, []byte("other content")).String(), nil)
+
+		cas := newClient(ipfs, 0, &orbmocks.MetricsProvider{})
+
+		cid, err := cas.WriteWithCIDFormat([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, cid)
+		require.Contains(t, err.Error(), "does not match the hash of the content")
+
+		hl, err := cas.Write([]byte("content"))
+		require.Error(t, err)
+		require.Empty(t, hl)
+		require.Contains(t, err.Error(), "does not match the hash of the content")
+	})
+
+	t.Run("v0 CID - content that spans multiple chunks is addressed by the flat hash", func(t *testing.T) {
+		content := make([]byte, 256*1024+1)
+
+		const v0CID = "QmbSnCcHziqhjNRyaunfcCvxPiV3fNL3fWL8nUrp5yqwD5"
+
+		ipfs := &mocks.IPFSClient{}
+		ipfs.AddReturns(v0CID, nil)
+
+		cas := newClient(ipfs, 0, &orbmocks.MetricsProvider{}, extendedcasclient.WithCIDVersion(0))
+
+		hl, err := cas.Write(content)
+		require.NoError(t, err)
+
+		flatHash, err := unixfs.FlatResourceHash(content)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(hl, "hl:"+flatHash+":"))
+	})
+
+	t.Run("error - unsupported codec", func(t *testing.T) {
+		cas := newClient(&mocks.IPFSClient{}, 0, &orbmocks.MetricsProvider{})
+
+		cid, err := cas.WriteWithCIDFormat([]byte("content"), extendedcasclient.WithCodec("dag-pb"))
+		require.EqualError(t, err, "dag-pb is not a supported codec. It must be one of raw, dag-json or dag-cbor")
+		require.Empty(t, cid)
+	})
+}
+
+func newRawCID(t *testing.T, content []byte) gocid.Cid {
+	t.Helper()
+
+	hash, err := mh.Sum(content, mh.SHA2_256, -1)
+	require.NoError(t, err)
+
+	return gocid.NewCidV1(gocid.Raw, hash)
+}
+
 func TestRead(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ipfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -234,6 +397,31 @@ func TestRead(t *testing.T) {
 		require.Empty(t, cid)
 	})
 
+	t.Run("success - content spans multiple chunks (dag-pb CID)", func(t *testing.T) {
+		dagCID, err := multihash.ToV1DagPBCID("uEiAWradITyYpRGT3pMhcKfPL8kpJBGePjFjZOlS0zqAUqw")
+		require.NoError(t, err)
+
+		ipfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("arg") != dagCID {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, errWrite := w.Write([]byte("context deadline exceeded"))
+				require.NoError(t, errWrite)
+
+				return
+			}
+
+			fmt.Fprint(w, "content")
+		}))
+		defer ipfs.Close()
+
+		cas := New(ipfs.URL, 20*time.Second, 0, &orbmocks.MetricsProvider{})
+		require.NotNil(t, cas)
+
+		read, err := cas.Read("uEiAWradITyYpRGT3pMhcKfPL8kpJBGePjFjZOlS0zqAUqw")
+		require.NoError(t, err)
+		require.Equal(t, "content", string(read))
+	})
+
 	t.Run("error - context deadline exceeded (content not found)", func(t *testing.T) {
 		ipfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -21,6 +21,7 @@ import (
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	"github.com/trustbloc/orb/pkg/activitypub/client/transport"
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/multihash"
@@ -182,6 +183,19 @@ func (h *Resolver) getResourceHashWithPossibleDomainAndLinks(hashWithPossibleHin
 	case "ipfs":
 		resourceHash = hashWithPossibleHintParts[1]
 
+		if multihash.IsValidCID(resourceHash) {
+			// The CID may have been written using any of the supported CID formats, so it's used as is.
+			hash, err := multihash.CIDToMultihash(resourceHash)
+			if err != nil {
+				return "", "", nil, fmt.Errorf("CID[%s] cannot be converted to a multihash: %w", resourceHash, err)
+			}
+
+			links = []string{ipfsPrefix + resourceHash}
+			resourceHash = hash
+
+			break
+		}
+
 		cid, err := multihash.ToV1CID(resourceHash)
 		if err != nil {
 			return "", "", nil, fmt.Errorf("resource hash[%s] cannot be converted to V1 CID: %w", resourceHash, err)
@@ -300,8 +314,11 @@ func (h *Resolver) getAndStoreDataFromIPFS(cid, resourceHash string) ([]byte, st
 	return resp, localHL, nil
 }
 
+// storeLocallyAndVerifyHash stores the given data in the local CAS and verifies that the resulting resource hash
+// matches the requested resource hash. Content that spans multiple chunks may be addressed either by the root of its
+// UnixFS DAG or, if it was written by an earlier version, by the hash of the entire content.
 func (h *Resolver) storeLocallyAndVerifyHash(data []byte, resourceHash string) (string, error) {
-	newHLFromLocalCAS, err := h.localCAS.Write(data)
+	newHLFromLocalCAS, err := h.writeLocally(data, resourceHash)
 	if err != nil {
 		return "", fmt.Errorf("failed to write data to CAS "+
 			"(and calculate CID in the process of doing so): %w", err)
@@ -316,7 +333,9 @@ func (h *Resolver) storeLocallyAndVerifyHash(data []byte, resourceHash string) (
 			"(and get resource hash in the process of doing so): %w", err)
 	}
 
-	if newResourceHash != resourceHash {
+	// The resource hashes may be encoded using different multibases (or the requested resource hash may be a CID)
+	// so the multihashes are compared.
+	if !sameMultihash(newResourceHash, resourceHash) {
 		return "", fmt.Errorf("successfully stored data into the local CAS, but the resource hash produced by "+
 			"the local CAS (%s) does not match the resource hash from the original request (%s)",
 			newResourceHash, resourceHash)
@@ -325,6 +344,47 @@ func (h *Resolver) storeLocallyAndVerifyHash(data []byte, resourceHash string) (
 	return newHLFromLocalCAS, nil
 }
 
+// writeLocally writes the data to the local CAS. If the data spans multiple chunks and the requested resource hash
+// is the hash of the entire content then the data is stored under that hash rather than under the root of its
+// UnixFS DAG.
+func (h *Resolver) writeLocally(data []byte, resourceHash string) (string, error) {
+	if !unixfs.IsChunked(len(data)) {
+		return h.localCAS.Write(data)
+	}
+
+	flatResourceHash, err := unixfs.FlatResourceHash(data)
+	if err != nil {
+		return "", err
+	}
+
+	if !sameMultihash(flatResourceHash, resourceHash) {
+		return h.localCAS.Write(data)
+	}
+
+	logger.Debug("Data is addressed by the hash of the entire content. Storing it under the same hash.",
+		logfields.WithHash(resourceHash))
+
+	return h.localCAS.WriteWithCIDFormat(data, extendedcasclient.WithFlatHash())
+}
+
+func sameMultihash(hash1, hash2 string) bool {
+	if hash1 == hash2 {
+		return true
+	}
+
+	mh1, err := multihash.CIDOrMultihashToMultihash(hash1)
+	if err != nil {
+		return false
+	}
+
+	mh2, err := multihash.CIDOrMultihashToMultihash(hash2)
+	if err != nil {
+		return false
+	}
+
+	return mh1 == mh2
+}
+
 // WebCASResolver is used to resolve data from another Orb server's CAS.
 type WebCASResolver struct {
 	httpClient         httpClient
@@ -20,6 +20,8 @@ import (
 	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	ariesmockstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
 	ariesstorage "github.com/hyperledger/aries-framework-go/spi/storage"
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
 
@@ -32,11 +34,13 @@ import (
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	resolvermocks "github.com/trustbloc/orb/pkg/cas/resolver/mocks"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	"github.com/trustbloc/orb/pkg/discovery/endpoint/restapi"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
 	"github.com/trustbloc/orb/pkg/store/cas"
 	"github.com/trustbloc/orb/pkg/webcas"
 	webfingerclient "github.com/trustbloc/orb/pkg/webfinger/client"
@@ -111,6 +115,69 @@ func TestResolver_Resolve(t *testing.T) {
 				require.Equal(t, string(data), sampleData)
 				require.NotEmpty(t, localHL)
 			})
+
+			t.Run("v1 - resource hash with base58btc multibase", func(t *testing.T) {
+				rh, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+				require.NoError(t, err)
+
+				rh, err = multihash.ToMultibase(rh, multibase.Base58BTC)
+				require.NoError(t, err)
+
+				data, localHL, err := resolver.Resolve(nil, rh, []byte(sampleData))
+				require.NoError(t, err)
+				require.Equal(t, string(data), sampleData)
+				require.NotEmpty(t, localHL)
+			})
+
+			t.Run("v1 - dag-cbor CID", func(t *testing.T) {
+				rh, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+				require.NoError(t, err)
+
+				cid, err := multihash.ToV1CIDWithFormat(rh, gocid.DagCBOR, multibase.Base32)
+				require.NoError(t, err)
+
+				data, localHL, err := resolver.Resolve(nil, cid, []byte(sampleData))
+				require.NoError(t, err)
+				require.Equal(t, string(data), sampleData)
+				require.NotEmpty(t, localHL)
+			})
+		})
+		t.Run("Content over 256KB addressed by either the flat hash or the UnixFS DAG root", func(t *testing.T) {
+			casClient := createInMemoryCAS(t)
+			resolver := createNewResolver(t, casClient, nil)
+
+			content := make([]byte, unixfs.ChunkSize+100)
+			for i := range content {
+				content[i] = byte(i)
+			}
+
+			dagRootHash, err := unixfs.ResourceHash(content)
+			require.NoError(t, err)
+
+			flatHash, err := hashlink.New().CreateResourceHash(content)
+			require.NoError(t, err)
+			require.NotEqual(t, dagRootHash, flatHash)
+
+			data, localHL, err := resolver.Resolve(nil, flatHash, content)
+			require.NoError(t, err)
+			require.Equal(t, content, data)
+
+			rh, err := hashlink.GetResourceHashFromHashLink(localHL)
+			require.NoError(t, err)
+			require.Equal(t, flatHash, rh)
+
+			// The content should be found locally under the flat hash.
+			data, err = casClient.Read(flatHash)
+			require.NoError(t, err)
+			require.Equal(t, content, data)
+
+			data, localHL, err = resolver.Resolve(nil, dagRootHash, content)
+			require.NoError(t, err)
+			require.Equal(t, content, data)
+
+			rh, err = hashlink.GetResourceHashFromHashLink(localHL)
+			require.NoError(t, err)
+			require.Equal(t, dagRootHash, rh)
 		})
 		t.Run("No need to get data from remote since it was found locally", func(t *testing.T) {
 			casClient := createInMemoryCAS(t)
@@ -366,6 +433,38 @@ func TestResolver_Resolve(t *testing.T) {
 		require.NotEmpty(t, localHL)
 	})
 
+	t.Run("Had to retrieve data from ipfs via ipfs hint (base58btc dag-cbor CID)", func(t *testing.T) {
+		resourceHash, err := hashlink.New().CreateResourceHash([]byte(sampleData))
+		require.NoError(t, err)
+
+		cid, err := multihash.ToV1CIDWithFormat(resourceHash, gocid.DagCBOR, multibase.Base58BTC)
+		require.NoError(t, err)
+
+		rawCID, err := multihash.ToV1CID(resourceHash)
+		require.NoError(t, err)
+
+		ipfsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("arg") != rawCID {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			fmt.Fprint(w, sampleData)
+		}))
+		defer ipfsServer.Close()
+
+		ipfsClient := ipfs.New(ipfsServer.URL, 5*time.Second, 0, &orbmocks.MetricsProvider{})
+		require.NotNil(t, ipfsClient)
+
+		resolver := createNewResolver(t, createInMemoryCAS(t), ipfsClient)
+
+		data, localHL, err := resolver.Resolve(nil, "ipfs:"+cid, nil)
+		require.NoError(t, err)
+		require.Equal(t, string(data), sampleData)
+		require.NotEmpty(t, localHL)
+	})
+
 	t.Run("Retrieve from IPFS using links", func(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			data := []byte(sampleData)
@@ -0,0 +1,555 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrub
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	mh "github.com/multiformats/go-multihash"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
+	"github.com/trustbloc/orb/pkg/store/casscrub"
+)
+
+var logger = log.New("cas-scrub")
+
+const (
+	taskName = "cas-scrub"
+
+	defaultInterval          = time.Hour
+	defaultMaxItemsPerRun    = 1000
+	defaultMaxRepairAttempts = 10
+
+	ipfsPrefix = "ipfs://"
+)
+
+var errMaxItemsReached = errors.New("maximum items per run reached")
+
+type taskManager interface {
+	RegisterTask(taskType string, interval time.Duration, handler func())
+}
+
+type casStore interface {
+	ForEachCreatedSince(since int64, fn func(resourceHash string, content []byte, createdTime int64) error) error
+	Delete(resourceHash string) error
+}
+
+type casResolver interface {
+	Resolve(webCASURL *url.URL, hashWithPossibleHint string, data []byte) ([]byte, string, error)
+}
+
+type anchorRefStore interface {
+	GetLinks(anchorHash string) ([]*url.URL, error)
+}
+
+type scrubStore interface {
+	Put(e *casscrub.Entry) error
+	Query(status casscrub.Status) ([]*casscrub.Entry, error)
+	GetCheckpoint() (*casscrub.Checkpoint, error)
+	PutCheckpoint(cp *casscrub.Checkpoint) error
+}
+
+type metricsProvider interface {
+	CASScrubIncrementCheckedCount()
+	CASScrubIncrementCorruptedCount()
+	CASScrubIncrementRepairedCount()
+	CASScrubRunTime(value time.Duration)
+}
+
+// Config contains configuration parameters for the scrubber.
+type Config struct {
+	// Interval is the interval at which the scrubber runs.
+	Interval time.Duration
+	// MaxItemsPerRun is the maximum number of items that are checked in a single run. The next run resumes
+	// from where the previous run stopped.
+	MaxItemsPerRun int
+	// MaxRepairAttempts is the maximum number of times that the repair of a corrupted item is attempted
+	// before the item is marked as failed.
+	MaxRepairAttempts int
+	// IPFSEnabled indicates that corrupted content may be re-fetched from IPFS.
+	IPFSEnabled bool
+	// PeerWebCASURLs contains the base URLs of peer WebCAS endpoints (e.g. https://orb.domain2.com/cas)
+	// from which corrupted content may be re-fetched.
+	PeerWebCASURLs []string
+}
+
+// Providers contains the providers of the scrubber.
+type Providers struct {
+	// CAS is the local CAS whose content is verified.
+	CAS casStore
+	// Resolver re-fetches corrupted content and stores it in the local CAS.
+	Resolver casResolver
+	// Store holds the quarantined entries and the scrubber checkpoint.
+	Store scrubStore
+	// AnchorRefStore holds the hashlinks (including metadata) that were recorded for anchor linksets. The links
+	// in the metadata are used as repair sources.
+	AnchorRefStore anchorRefStore
+	Metrics        metricsProvider
+}
+
+// Report contains the results of a scrub run.
+type Report struct {
+	// StartTime is the time that the run started.
+	StartTime time.Time `json:"startTime"`
+	// Duration is the duration of the run.
+	Duration time.Duration `json:"duration"`
+	// Checked is the number of items whose content was verified.
+	Checked int `json:"checked"`
+	// Corrupted is the number of items whose content doesn't match their resource hash.
+	Corrupted int `json:"corrupted"`
+	// Repaired is the number of quarantined items that were re-fetched (including items from previous runs).
+	Repaired int `json:"repaired"`
+	// Failed is the number of quarantined items for which all repair attempts have been exhausted.
+	Failed int `json:"failed"`
+	// CycleCompleted indicates that this run completed a pass over the local CAS.
+	CycleCompleted bool `json:"cycleCompleted"`
+}
+
+// Status contains the current status of the scrubber.
+type Status struct {
+	// LastReport is the report of the last run (on this server instance).
+	LastReport *Report `json:"lastReport,omitempty"`
+	// Checkpoint is the (Unix) created time of the next item of content to be checked.
+	Checkpoint int64 `json:"checkpoint"`
+	// Cycles is the number of complete passes over the local CAS.
+	Cycles int `json:"cycles"`
+	// Quarantined contains the pending and failed quarantine entries (without content).
+	Quarantined []*casscrub.Entry `json:"quarantined,omitempty"`
+}
+
+// Scrubber verifies the integrity of the content in the local CAS by re-hashing the content and comparing the
+// hash with the key under which the content is stored. Corrupted content is removed from the local CAS and stored
+// in a quarantine store, after which the content is re-fetched using the recorded hashlink metadata, IPFS or a peer
+// WebCAS endpoint. Each run checks at most MaxItemsPerRun items (in order of created time) and saves a checkpoint
+// so that the next run resumes where the previous run stopped. The scrubber is run by a task so that only one
+// server instance in a domain scrubs the CAS.
+type Scrubber struct {
+	*Config
+	*Providers
+
+	hl         *hashlink.HashLink
+	mutex      sync.RWMutex
+	lastReport *Report
+	now        func() time.Time
+}
+
+// New returns a new scrubber and registers its task with the task manager.
+func New(cfg Config, providers *Providers, taskMgr taskManager) *Scrubber {
+	s := &Scrubber{
+		Config:    resolveConfig(&cfg),
+		Providers: providers,
+		hl:        hashlink.New(),
+		now:       time.Now,
+	}
+
+	logger.Info("Registering CAS scrub task.", logfields.WithTaskMonitorInterval(s.Interval),
+		logfields.WithTotal(s.MaxItemsPerRun), logfields.WithMaxRetries(s.MaxRepairAttempts))
+
+	taskMgr.RegisterTask(taskName, s.Interval, s.run)
+
+	return s
+}
+
+// LastReport returns the report of the last scrub run or nil if no run has completed.
+func (s *Scrubber) LastReport() *Report {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.lastReport
+}
+
+// Status returns the current status of the scrubber.
+func (s *Scrubber) Status() (*Status, error) {
+	cp, err := s.Store.GetCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	}
+
+	status := &Status{
+		LastReport: s.LastReport(),
+		Checkpoint: cp.CreatedTime,
+		Cycles:     cp.Cycles,
+	}
+
+	for _, st := range []casscrub.Status{casscrub.StatusPending, casscrub.StatusFailed} {
+		entries, e := s.Store.Query(st)
+		if e != nil {
+			return nil, fmt.Errorf("query quarantine entries: %w", e)
+		}
+
+		for _, entry := range entries {
+			entry.Content = nil
+
+			status.Quarantined = append(status.Quarantined, entry)
+		}
+	}
+
+	return status, nil
+}
+
+// Scrub performs a scrub run and returns the report. The repair of previously quarantined content is retried
+// before new content is checked.
+func (s *Scrubber) Scrub() (*Report, error) {
+	report := &Report{
+		StartTime: s.now(),
+	}
+
+	if err := s.retryPending(report); err != nil {
+		return nil, fmt.Errorf("retry repair of quarantined content: %w", err)
+	}
+
+	cp, err := s.Store.GetCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	}
+
+	corrupted, next, err := s.check(cp.CreatedTime, report)
+	if err != nil {
+		return nil, fmt.Errorf("check content: %w", err)
+	}
+
+	for _, entry := range corrupted {
+		if err = s.quarantine(entry, report); err != nil {
+			return nil, fmt.Errorf("quarantine content [%s]: %w", entry.ResourceHash, err)
+		}
+	}
+
+	if next == 0 {
+		cp.Cycles++
+		report.CycleCompleted = true
+	}
+
+	cp.CreatedTime = next
+
+	if err = s.Store.PutCheckpoint(cp); err != nil {
+		return nil, fmt.Errorf("save checkpoint: %w", err)
+	}
+
+	report.Duration = s.now().Sub(report.StartTime)
+
+	s.Metrics.CASScrubRunTime(report.Duration)
+
+	s.mutex.Lock()
+	s.lastReport = report
+	s.mutex.Unlock()
+
+	return report, nil
+}
+
+func (s *Scrubber) run() {
+	report, err := s.Scrub()
+	if err != nil {
+		logger.Error("Error scrubbing CAS content", log.WithError(err))
+
+		return
+	}
+
+	logger.Info("Completed CAS scrub run", logfields.WithConfig(report))
+}
+
+// check verifies the content that was created at or after the given time. The corrupted entries are returned
+// along with the created time from which the next run should resume, or zero if all content was checked.
+// A run only stops at a created time boundary so that no item is skipped by the next run.
+func (s *Scrubber) check(since int64, report *Report) ([]*casscrub.Entry, int64, error) {
+	var corrupted []*casscrub.Entry
+
+	var next, lastCreatedTime int64
+
+	err := s.CAS.ForEachCreatedSince(since, func(resourceHash string, content []byte, createdTime int64) error {
+		if report.Checked >= s.MaxItemsPerRun && createdTime > lastCreatedTime {
+			next = createdTime
+
+			return errMaxItemsReached
+		}
+
+		lastCreatedTime = createdTime
+
+		report.Checked++
+		s.Metrics.CASScrubIncrementCheckedCount()
+
+		intact, actualHash, err := verify(resourceHash, content)
+		if intact {
+			return nil
+		}
+
+		entry := &casscrub.Entry{
+			ResourceHash: resourceHash,
+			ActualHash:   actualHash,
+			Status:       casscrub.StatusPending,
+			DetectedAt:   s.now(),
+			Content:      content,
+		}
+
+		if err != nil {
+			entry.LastError = err.Error()
+		}
+
+		corrupted = append(corrupted, entry)
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMaxItemsReached) {
+		return nil, 0, err
+	}
+
+	return corrupted, next, nil
+}
+
+// verify returns true if the given content matches its resource hash. Content that's larger than a single UnixFS
+// chunk may be addressed either by the root of its UnixFS DAG or (if it was stored by an earlier version) by the
+// hash of the entire content, so the content is intact if either hash matches. If the resource hash wasn't produced
+// using a supported hash function then the content can't be verified and is assumed to be intact. Otherwise the
+// actual (UnixFS) resource hash of the content is also returned.
+func verify(resourceHash string, content []byte) (bool, string, error) {
+	code, err := multihash.HashCode(resourceHash)
+	if err != nil || code != mh.SHA2_256 {
+		logger.Warn("Resource hash isn't a supported multihash. The content can't be verified.",
+			logfields.WithHash(resourceHash), log.WithError(err))
+
+		return true, "", nil
+	}
+
+	resourceHashes, err := unixfs.ResourceHashes(content)
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, rh := range resourceHashes {
+		if rh == resourceHash {
+			return true, "", nil
+		}
+	}
+
+	return false, resourceHashes[0], nil
+}
+
+// quarantine moves the corrupted content from the local CAS to the quarantine store and attempts to repair it.
+func (s *Scrubber) quarantine(entry *casscrub.Entry, report *Report) error {
+	logger.Warn("Content in local CAS doesn't match its resource hash. Quarantining.",
+		logfields.WithHash(entry.ResourceHash), logfields.WithMultihash(entry.ActualHash))
+
+	report.Corrupted++
+	s.Metrics.CASScrubIncrementCorruptedCount()
+
+	if err := s.Store.Put(entry); err != nil {
+		return err
+	}
+
+	if err := s.CAS.Delete(entry.ResourceHash); err != nil {
+		return err
+	}
+
+	return s.repair(entry, report)
+}
+
+func (s *Scrubber) retryPending(report *Report) error {
+	entries, err := s.Store.Query(casscrub.StatusPending)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err = s.repair(entry, report); err != nil {
+			return fmt.Errorf("repair content [%s]: %w", entry.ResourceHash, err)
+		}
+	}
+
+	return nil
+}
+
+// repair re-fetches the content of the given quarantine entry and updates the entry with the result.
+func (s *Scrubber) repair(entry *casscrub.Entry, report *Report) error {
+	entry.Attempts++
+
+	source, err := s.refetch(entry)
+	if err != nil {
+		entry.LastError = err.Error()
+
+		if entry.Attempts >= s.MaxRepairAttempts {
+			entry.Status = casscrub.StatusFailed
+
+			report.Failed++
+
+			logger.Error("Giving up on repair of corrupted CAS content", logfields.WithHash(entry.ResourceHash),
+				logfields.WithRetries(entry.Attempts), log.WithError(err))
+		} else {
+			logger.Warn("Error repairing corrupted CAS content", logfields.WithHash(entry.ResourceHash),
+				logfields.WithRetries(entry.Attempts), log.WithError(err))
+		}
+
+		return s.Store.Put(entry)
+	}
+
+	logger.Info("Repaired corrupted CAS content", logfields.WithHash(entry.ResourceHash),
+		logfields.WithSource(source))
+
+	entry.Status = casscrub.StatusRepaired
+	entry.Source = source
+	entry.LastError = ""
+	entry.RepairedAt = s.now()
+
+	report.Repaired++
+	s.Metrics.CASScrubIncrementRepairedCount()
+
+	return s.Store.Put(entry)
+}
+
+// refetch re-fetches the content of the given entry from each of the candidate sources in turn until it's
+// successfully retrieved (and stored in the local CAS) by the resolver. The resolver verifies that the content
+// matches the resource hash. The source of the content is returned.
+func (s *Scrubber) refetch(entry *casscrub.Entry) (string, error) {
+	links, err := s.repairLinks(entry)
+	if err != nil {
+		return "", err
+	}
+
+	if len(links) == 0 {
+		return "", errors.New("no repair sources found")
+	}
+
+	var errMsgs []string
+
+	for _, link := range links {
+		metadata, e := s.hl.CreateMetadataFromLinks([]string{link})
+		if e != nil {
+			return "", fmt.Errorf("create metadata from link [%s]: %w", link, e)
+		}
+
+		_, _, e = s.Resolver.Resolve(nil, hashlink.GetHashLink(entry.ResourceHash, metadata), nil)
+		if e != nil {
+			logger.Debug("Error re-fetching corrupted CAS content", logfields.WithHash(entry.ResourceHash),
+				logfields.WithLink(link), log.WithError(e))
+
+			errMsgs = append(errMsgs, fmt.Sprintf("%s: %s", link, e))
+
+			continue
+		}
+
+		return link, nil
+	}
+
+	return "", errors.New(strings.Join(errMsgs, "; "))
+}
+
+// repairLinks returns the links from which the content of the given entry may be re-fetched. The links in the
+// hashlink metadata recorded for the resource hash are tried first, followed by IPFS and the configured peer
+// WebCAS endpoints.
+func (s *Scrubber) repairLinks(entry *casscrub.Entry) ([]string, error) {
+	links, err := s.recordedLinks(entry.ResourceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.IPFSEnabled {
+		cids, err := ipfsCIDs(entry)
+		if err != nil {
+			return nil, fmt.Errorf("convert resource hash to CID: %w", err)
+		}
+
+		for _, cid := range cids {
+			links = appendIfMissing(links, ipfsPrefix+cid)
+		}
+	}
+
+	for _, peerURL := range s.PeerWebCASURLs {
+		links = appendIfMissing(links, strings.TrimSuffix(peerURL, "/")+"/"+entry.ResourceHash)
+	}
+
+	return links, nil
+}
+
+// recordedLinks returns the links in the metadata of the hashlinks that were recorded for the given
+// resource hash (i.e. the anchor references of an anchor linkset).
+func (s *Scrubber) recordedLinks(resourceHash string) ([]string, error) {
+	if s.AnchorRefStore == nil {
+		return nil, nil
+	}
+
+	hashLinks, err := s.AnchorRefStore.GetLinks(resourceHash)
+	if err != nil {
+		return nil, fmt.Errorf("get anchor references: %w", err)
+	}
+
+	var links []string
+
+	for _, hl := range hashLinks {
+		hlInfo, err := s.hl.ParseHashLink(hl.String())
+		if err != nil {
+			logger.Warn("Ignoring invalid anchor reference", logfields.WithHashlinkURI(hl), log.WithError(err))
+
+			continue
+		}
+
+		for _, link := range hlInfo.Links {
+			links = appendIfMissing(links, link)
+		}
+	}
+
+	return links, nil
+}
+
+// ipfsCIDs returns the CIDs by which the content of the given entry may be addressed in IPFS. Content that's no
+// larger than a single UnixFS chunk is addressed by a raw CID. Larger content is addressed by a dag-pb CID if the
+// resource hash is the root of its UnixFS DAG, or by a raw CID if the resource hash is the flat hash of the content
+// (as stored by earlier versions). The two can't be told apart from the resource hash, so both CIDs are returned.
+// (Corruption is assumed not to change the size of the content across the chunk boundary.)
+func ipfsCIDs(entry *casscrub.Entry) ([]string, error) {
+	var cids []string
+
+	if unixfs.IsChunked(len(entry.Content)) {
+		cid, err := multihash.ToV1DagPBCID(entry.ResourceHash)
+		if err != nil {
+			return nil, err
+		}
+
+		cids = append(cids, cid)
+	}
+
+	cid, err := multihash.ToV1CID(entry.ResourceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(cids, cid), nil
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
+
+func resolveConfig(cfg *Config) *Config {
+	config := *cfg
+
+	if config.Interval == 0 {
+		config.Interval = defaultInterval
+	}
+
+	if config.MaxItemsPerRun == 0 {
+		config.MaxItemsPerRun = defaultMaxItemsPerRun
+	}
+
+	if config.MaxRepairAttempts == 0 {
+		config.MaxRepairAttempts = defaultMaxRepairAttempts
+	}
+
+	return &config
+}
@@ -0,0 +1,662 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrub
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	mh "github.com/multiformats/go-multihash"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
+	"github.com/trustbloc/orb/pkg/store/casscrub"
+)
+
+const peerWebCASURL = "https://orb.domain2.com/cas"
+
+func TestNew(t *testing.T) {
+	taskMgr := &mockTaskManager{}
+
+	s := New(Config{}, &Providers{}, taskMgr)
+	require.NotNil(t, s)
+	require.Equal(t, taskName, taskMgr.taskID)
+	require.Equal(t, defaultInterval, taskMgr.interval)
+	require.Equal(t, defaultMaxItemsPerRun, s.MaxItemsPerRun)
+	require.Equal(t, defaultMaxRepairAttempts, s.MaxRepairAttempts)
+	require.Nil(t, s.LastReport())
+}
+
+func TestScrubber_Scrub(t *testing.T) {
+	t.Run("no corruption", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.add(t, []byte("content1"), 100)
+		cas.add(t, []byte("content2"), 101)
+		cas.add(t, make([]byte, unixfs.ChunkSize+1), 102)
+
+		s := New(Config{PeerWebCASURLs: []string{peerWebCASURL}}, newProviders(t, cas, &mockResolver{}),
+			&mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 3, report.Checked)
+		require.Zero(t, report.Corrupted)
+		require.True(t, report.CycleCompleted)
+		require.Equal(t, report, s.LastReport())
+
+		status, err := s.Status()
+		require.NoError(t, err)
+		require.Equal(t, 1, status.Cycles)
+		require.Zero(t, status.Checkpoint)
+		require.Empty(t, status.Quarantined)
+	})
+
+	t.Run("chunked content stored under the flat hash isn't corrupted", func(t *testing.T) {
+		content := make([]byte, unixfs.ChunkSize+1)
+
+		flatHash, err := unixfs.FlatResourceHash(content)
+		require.NoError(t, err)
+
+		cas := newMockCAS()
+		cas.put(flatHash, content, 100)
+		cas.add(t, content, 101)
+
+		s := New(Config{IPFSEnabled: true}, newProviders(t, cas, &mockResolver{}), &mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Checked)
+		require.Zero(t, report.Corrupted)
+		require.Empty(t, cas.deleted)
+	})
+
+	t.Run("content with an unsupported hash function isn't checked", func(t *testing.T) {
+		sha512Hash, err := mh.Sum([]byte("content"), mh.SHA2_512, -1)
+		require.NoError(t, err)
+
+		cas := newMockCAS()
+		cas.put("u"+base64.RawURLEncoding.EncodeToString(sha512Hash), []byte("c0ntent"), 100)
+		cas.put("not-a-multihash", []byte("content"), 101)
+
+		s := New(Config{IPFSEnabled: true}, newProviders(t, cas, &mockResolver{}), &mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Checked)
+		require.Zero(t, report.Corrupted)
+		require.Empty(t, cas.deleted)
+	})
+
+	t.Run("corrupted content repaired from peer", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.add(t, []byte("content1"), 100)
+		rh := cas.addCorrupted(t, []byte("content2"), []byte("c0ntent2"), 101)
+
+		resolver := &mockResolver{
+			cas:     cas,
+			content: map[string][]byte{rh: []byte("content2")},
+			err:     map[string]error{ipfsPrefix: errors.New("IPFS not available")},
+		}
+
+		providers := newProviders(t, cas, resolver)
+
+		s := New(Config{IPFSEnabled: true, PeerWebCASURLs: []string{peerWebCASURL + "/"}}, providers,
+			&mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Checked)
+		require.Equal(t, 1, report.Corrupted)
+		require.Equal(t, 1, report.Repaired)
+		require.Zero(t, report.Failed)
+		require.Equal(t, []string{rh}, cas.deleted)
+		require.Len(t, resolver.links, 2)
+		require.True(t, strings.HasPrefix(resolver.links[0], ipfsPrefix))
+		require.Equal(t, peerWebCASURL+"/"+rh, resolver.links[1])
+
+		entry, err := providers.Store.(*casscrub.Store).Get(rh)
+		require.NoError(t, err)
+		require.Equal(t, casscrub.StatusRepaired, entry.Status)
+		require.Equal(t, peerWebCASURL+"/"+rh, entry.Source)
+		require.Equal(t, []byte("c0ntent2"), entry.Content)
+		require.NotEmpty(t, entry.ActualHash)
+		require.Empty(t, entry.LastError)
+
+		status, err := s.Status()
+		require.NoError(t, err)
+		require.Empty(t, status.Quarantined)
+
+		// The repaired content passes the next check.
+		report, err = s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Checked)
+		require.Zero(t, report.Corrupted)
+	})
+
+	t.Run("corrupted chunked content repaired from IPFS", func(t *testing.T) {
+		content := make([]byte, unixfs.ChunkSize+1)
+		corruptedContent := make([]byte, unixfs.ChunkSize+1)
+		corruptedContent[0] = 1
+
+		cas := newMockCAS()
+		rh := cas.addCorrupted(t, content, corruptedContent, 100)
+
+		resolver := &mockResolver{
+			cas:     cas,
+			content: map[string][]byte{rh: content},
+		}
+
+		s := New(Config{IPFSEnabled: true}, newProviders(t, cas, resolver), &mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Corrupted)
+		require.Equal(t, 1, report.Repaired)
+
+		cid, err := multihash.ToV1DagPBCID(rh)
+		require.NoError(t, err)
+		require.Equal(t, []string{ipfsPrefix + cid}, resolver.links)
+	})
+
+	t.Run("corrupted chunked content stored under the flat hash repaired from IPFS", func(t *testing.T) {
+		content := make([]byte, unixfs.ChunkSize+1)
+		corruptedContent := make([]byte, unixfs.ChunkSize+1)
+		corruptedContent[0] = 1
+
+		rh, err := unixfs.FlatResourceHash(content)
+		require.NoError(t, err)
+
+		dagPBCID, err := multihash.ToV1DagPBCID(rh)
+		require.NoError(t, err)
+
+		rawCID, err := multihash.ToV1CID(rh)
+		require.NoError(t, err)
+
+		cas := newMockCAS()
+		cas.put(rh, corruptedContent, 100)
+
+		resolver := &mockResolver{
+			cas:     cas,
+			content: map[string][]byte{rh: content},
+			err:     map[string]error{ipfsPrefix + dagPBCID: errors.New("not found")},
+		}
+
+		s := New(Config{IPFSEnabled: true}, newProviders(t, cas, resolver), &mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Corrupted)
+		require.Equal(t, 1, report.Repaired)
+		require.Equal(t, []string{ipfsPrefix + dagPBCID, ipfsPrefix + rawCID}, resolver.links)
+	})
+
+	t.Run("corrupted content repaired from recorded hashlink metadata", func(t *testing.T) {
+		cas := newMockCAS()
+		rh := cas.addCorrupted(t, []byte("content1"), []byte("c0ntent1"), 100)
+
+		domain3Link := "https://orb.domain3.com/cas/" + rh
+
+		metadata, err := hashlink.New().CreateMetadataFromLinks([]string{domain3Link, peerWebCASURL + "/" + rh})
+		require.NoError(t, err)
+
+		resolver := &mockResolver{
+			cas:     cas,
+			content: map[string][]byte{rh: []byte("content1")},
+			err:     map[string]error{domain3Link: errors.New("not found")},
+		}
+
+		providers := newProviders(t, cas, resolver)
+		providers.AnchorRefStore = &mockAnchorRefStore{
+			links: map[string][]*url.URL{
+				rh: {
+					testutil.MustParseURL("hl:invalid"),
+					testutil.MustParseURL(hashlink.GetHashLink(rh, metadata)),
+				},
+			},
+		}
+
+		s := New(Config{PeerWebCASURLs: []string{peerWebCASURL}}, providers, &mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Repaired)
+		require.Equal(t, []string{domain3Link, peerWebCASURL + "/" + rh}, resolver.links)
+
+		entry, err := providers.Store.(*casscrub.Store).Get(rh)
+		require.NoError(t, err)
+		require.Equal(t, peerWebCASURL+"/"+rh, entry.Source)
+	})
+
+	t.Run("anchor reference store error", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.addCorrupted(t, []byte("content1"), []byte("c0ntent1"), 100)
+
+		providers := newProviders(t, cas, &mockResolver{})
+		providers.AnchorRefStore = &mockAnchorRefStore{err: errors.New("injected store error")}
+
+		s := New(Config{PeerWebCASURLs: []string{peerWebCASURL}}, providers, &mockTaskManager{})
+
+		_, err := s.Scrub()
+		require.NoError(t, err)
+
+		status, err := s.Status()
+		require.NoError(t, err)
+		require.Len(t, status.Quarantined, 1)
+		require.Contains(t, status.Quarantined[0].LastError, "injected store error")
+	})
+
+	t.Run("repair retried until max attempts", func(t *testing.T) {
+		cas := newMockCAS()
+		rh := cas.addCorrupted(t, []byte("content1"), []byte("c0ntent1"), 100)
+
+		resolver := &mockResolver{
+			cas: cas,
+			err: map[string]error{peerWebCASURL: errors.New("not found")},
+		}
+
+		s := New(Config{MaxRepairAttempts: 2, PeerWebCASURLs: []string{peerWebCASURL}},
+			newProviders(t, cas, resolver), &mockTaskManager{})
+
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Corrupted)
+		require.Zero(t, report.Repaired)
+		require.Zero(t, report.Failed)
+
+		status, err := s.Status()
+		require.NoError(t, err)
+		require.Len(t, status.Quarantined, 1)
+		require.Equal(t, rh, status.Quarantined[0].ResourceHash)
+		require.Equal(t, casscrub.StatusPending, status.Quarantined[0].Status)
+		require.Equal(t, 1, status.Quarantined[0].Attempts)
+		require.Contains(t, status.Quarantined[0].LastError, "not found")
+		require.Nil(t, status.Quarantined[0].Content)
+
+		report, err = s.Scrub()
+		require.NoError(t, err)
+		require.Zero(t, report.Checked)
+		require.Zero(t, report.Corrupted)
+		require.Equal(t, 1, report.Failed)
+
+		status, err = s.Status()
+		require.NoError(t, err)
+		require.Len(t, status.Quarantined, 1)
+		require.Equal(t, casscrub.StatusFailed, status.Quarantined[0].Status)
+		require.Equal(t, 2, status.Quarantined[0].Attempts)
+
+		// Failed entries aren't retried.
+		report, err = s.Scrub()
+		require.NoError(t, err)
+		require.Zero(t, report.Failed)
+	})
+
+	t.Run("no repair sources", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.addCorrupted(t, []byte("content1"), []byte("c0ntent1"), 100)
+
+		s := New(Config{}, newProviders(t, cas, &mockResolver{}), &mockTaskManager{})
+
+		_, err := s.Scrub()
+		require.NoError(t, err)
+
+		status, err := s.Status()
+		require.NoError(t, err)
+		require.Len(t, status.Quarantined, 1)
+		require.Contains(t, status.Quarantined[0].LastError, "no repair sources found")
+	})
+
+	t.Run("max items per run", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.add(t, []byte("content1"), 100)
+		cas.add(t, []byte("content2"), 100)
+		cas.add(t, []byte("content3"), 101)
+		cas.add(t, []byte("content4"), 102)
+
+		s := New(Config{MaxItemsPerRun: 1}, newProviders(t, cas, &mockResolver{}), &mockTaskManager{})
+
+		// The run only stops at a created time boundary.
+		report, err := s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 2, report.Checked)
+		require.False(t, report.CycleCompleted)
+
+		status, err := s.Status()
+		require.NoError(t, err)
+		require.Equal(t, int64(101), status.Checkpoint)
+		require.Zero(t, status.Cycles)
+
+		report, err = s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Checked)
+		require.False(t, report.CycleCompleted)
+
+		report, err = s.Scrub()
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Checked)
+		require.True(t, report.CycleCompleted)
+
+		status, err = s.Status()
+		require.NoError(t, err)
+		require.Zero(t, status.Checkpoint)
+		require.Equal(t, 1, status.Cycles)
+	})
+
+	t.Run("CAS query error", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.errQuery = errors.New("injected query error")
+
+		s := New(Config{}, newProviders(t, cas, &mockResolver{}), &mockTaskManager{})
+
+		_, err := s.Scrub()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("CAS delete error", func(t *testing.T) {
+		cas := newMockCAS()
+		cas.addCorrupted(t, []byte("content1"), []byte("c0ntent1"), 100)
+		cas.errDelete = errors.New("injected delete error")
+
+		s := New(Config{}, newProviders(t, cas, &mockResolver{}), &mockTaskManager{})
+
+		_, err := s.Scrub()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected delete error")
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		errExpected := errors.New("injected store error")
+
+		t.Run("query", func(t *testing.T) {
+			store, err := casscrub.New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrQuery: errExpected}})
+			require.NoError(t, err)
+
+			providers := newProviders(t, newMockCAS(), &mockResolver{})
+			providers.Store = store
+
+			_, err = New(Config{}, providers, &mockTaskManager{}).Scrub()
+			require.ErrorIs(t, err, errExpected)
+		})
+
+		t.Run("get checkpoint", func(t *testing.T) {
+			providers := newProviders(t, newMockCAS(), &mockResolver{})
+			providers.Store = &mockScrubStore{scrubStore: providers.Store, errGetCheckpoint: errExpected}
+
+			_, err := New(Config{}, providers, &mockTaskManager{}).Scrub()
+			require.ErrorIs(t, err, errExpected)
+		})
+
+		t.Run("put checkpoint", func(t *testing.T) {
+			providers := newProviders(t, newMockCAS(), &mockResolver{})
+			providers.Store = &mockScrubStore{scrubStore: providers.Store, errPutCheckpoint: errExpected}
+
+			_, err := New(Config{}, providers, &mockTaskManager{}).Scrub()
+			require.ErrorIs(t, err, errExpected)
+		})
+
+		t.Run("put entry", func(t *testing.T) {
+			cas := newMockCAS()
+			cas.addCorrupted(t, []byte("content1"), []byte("c0ntent1"), 100)
+
+			providers := newProviders(t, cas, &mockResolver{})
+			providers.Store = &mockScrubStore{scrubStore: providers.Store, errPut: errExpected}
+
+			_, err := New(Config{}, providers, &mockTaskManager{}).Scrub()
+			require.ErrorIs(t, err, errExpected)
+			require.Empty(t, cas.deleted)
+		})
+	})
+}
+
+func TestScrubber_Status(t *testing.T) {
+	errExpected := errors.New("injected store error")
+
+	t.Run("query error", func(t *testing.T) {
+		providers := newProviders(t, newMockCAS(), &mockResolver{})
+		providers.Store = &mockScrubStore{scrubStore: providers.Store, errQuery: errExpected}
+
+		_, err := New(Config{}, providers, &mockTaskManager{}).Status()
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("get checkpoint error", func(t *testing.T) {
+		providers := newProviders(t, newMockCAS(), &mockResolver{})
+		providers.Store = &mockScrubStore{scrubStore: providers.Store, errGetCheckpoint: errExpected}
+
+		_, err := New(Config{}, providers, &mockTaskManager{}).Status()
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func TestScrubber_Run(t *testing.T) {
+	cas := newMockCAS()
+	cas.add(t, []byte("content1"), 100)
+
+	taskMgr := &mockTaskManager{}
+
+	s := New(Config{}, newProviders(t, cas, &mockResolver{}), taskMgr)
+
+	taskMgr.handler()
+
+	require.NotNil(t, s.LastReport())
+	require.Equal(t, 1, s.LastReport().Checked)
+
+	cas.errQuery = errors.New("injected query error")
+
+	taskMgr.handler()
+
+	require.NotNil(t, s.LastReport())
+}
+
+func newProviders(t *testing.T, cas *mockCAS, resolver *mockResolver) *Providers {
+	t.Helper()
+
+	store, err := casscrub.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	return &Providers{
+		CAS:      cas,
+		Resolver: resolver,
+		Store:    store,
+		Metrics:  &orbmocks.MetricsProvider{},
+	}
+}
+
+type casItem struct {
+	resourceHash string
+	content      []byte
+	createdTime  int64
+}
+
+type mockCAS struct {
+	items     []*casItem
+	deleted   []string
+	errQuery  error
+	errDelete error
+}
+
+func newMockCAS() *mockCAS {
+	return &mockCAS{}
+}
+
+func (m *mockCAS) add(t *testing.T, content []byte, createdTime int64) string {
+	t.Helper()
+
+	rh, err := unixfs.ResourceHash(content)
+	require.NoError(t, err)
+
+	m.put(rh, content, createdTime)
+
+	return rh
+}
+
+func (m *mockCAS) addCorrupted(t *testing.T, content, corruptedContent []byte, createdTime int64) string {
+	t.Helper()
+
+	rh, err := unixfs.ResourceHash(content)
+	require.NoError(t, err)
+
+	m.put(rh, corruptedContent, createdTime)
+
+	return rh
+}
+
+func (m *mockCAS) put(resourceHash string, content []byte, createdTime int64) {
+	m.items = append(m.items, &casItem{resourceHash: resourceHash, content: content, createdTime: createdTime})
+
+	sort.SliceStable(m.items, func(i, j int) bool { return m.items[i].createdTime < m.items[j].createdTime })
+}
+
+func (m *mockCAS) ForEachCreatedSince(since int64,
+	fn func(resourceHash string, content []byte, createdTime int64) error,
+) error {
+	if m.errQuery != nil {
+		return m.errQuery
+	}
+
+	for _, item := range m.items {
+		if item.createdTime < since {
+			continue
+		}
+
+		if err := fn(item.resourceHash, item.content, item.createdTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *mockCAS) Delete(resourceHash string) error {
+	if m.errDelete != nil {
+		return m.errDelete
+	}
+
+	m.deleted = append(m.deleted, resourceHash)
+
+	var items []*casItem
+
+	for _, item := range m.items {
+		if item.resourceHash != resourceHash {
+			items = append(items, item)
+		}
+	}
+
+	m.items = items
+
+	return nil
+}
+
+// mockResolver resolves content from the configured map and writes it to the mock CAS. An error is returned
+// for links that start with a prefix in the err map.
+type mockResolver struct {
+	cas     *mockCAS
+	content map[string][]byte
+	err     map[string]error
+	links   []string
+}
+
+func (m *mockResolver) Resolve(_ *url.URL, hashWithPossibleHint string, _ []byte) ([]byte, string, error) {
+	hlInfo, err := hashlink.New().ParseHashLink(hashWithPossibleHint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	m.links = append(m.links, hlInfo.Links...)
+
+	for prefix, e := range m.err {
+		if strings.HasPrefix(hlInfo.Links[0], prefix) {
+			return nil, "", e
+		}
+	}
+
+	content, ok := m.content[hlInfo.ResourceHash]
+	if !ok {
+		return nil, "", errors.New("content not found")
+	}
+
+	m.cas.put(hlInfo.ResourceHash, content, time.Now().Unix())
+
+	return content, "hl:" + hlInfo.ResourceHash, nil
+}
+
+type mockAnchorRefStore struct {
+	links map[string][]*url.URL
+	err   error
+}
+
+func (m *mockAnchorRefStore) GetLinks(anchorHash string) ([]*url.URL, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.links[anchorHash], nil
+}
+
+type mockScrubStore struct {
+	scrubStore
+
+	errPut           error
+	errQuery         error
+	errGetCheckpoint error
+	errPutCheckpoint error
+}
+
+func (m *mockScrubStore) Put(e *casscrub.Entry) error {
+	if m.errPut != nil {
+		return m.errPut
+	}
+
+	return m.scrubStore.Put(e)
+}
+
+func (m *mockScrubStore) Query(status casscrub.Status) ([]*casscrub.Entry, error) {
+	if m.errQuery != nil {
+		return nil, m.errQuery
+	}
+
+	return m.scrubStore.Query(status)
+}
+
+func (m *mockScrubStore) GetCheckpoint() (*casscrub.Checkpoint, error) {
+	if m.errGetCheckpoint != nil {
+		return nil, m.errGetCheckpoint
+	}
+
+	return m.scrubStore.GetCheckpoint()
+}
+
+func (m *mockScrubStore) PutCheckpoint(cp *casscrub.Checkpoint) error {
+	if m.errPutCheckpoint != nil {
+		return m.errPutCheckpoint
+	}
+
+	return m.scrubStore.PutCheckpoint(cp)
+}
+
+type mockTaskManager struct {
+	taskID   string
+	interval time.Duration
+	handler  func()
+}
+
+func (m *mockTaskManager) RegisterTask(taskID string, interval time.Duration, handler func()) {
+	m.taskID = taskID
+	m.interval = interval
+	m.handler = handler
+}
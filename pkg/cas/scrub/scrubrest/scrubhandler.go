@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrubrest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/cas/scrub"
+)
+
+var logger = log.New("cas-scrub-rest", log.WithFields(logfields.WithServiceEndpoint(scrubPath)))
+
+const (
+	scrubPath                   = "/cas-scrub"
+	internalServerErrorResponse = "Internal Server Error.\n"
+)
+
+type scrubber interface {
+	Status() (*scrub.Status, error)
+}
+
+// StatusHandler implements a REST handler that returns the status of the CAS scrubber, i.e. the report
+// of the last run, the checkpoint and the quarantined entries.
+type StatusHandler struct {
+	scrubber scrubber
+	marshal  func(v interface{}) ([]byte, error)
+}
+
+// NewStatusHandler returns a new REST handler that returns the status of the CAS scrubber.
+func NewStatusHandler(s scrubber) *StatusHandler {
+	return &StatusHandler{
+		scrubber: s,
+		marshal:  json.Marshal,
+	}
+}
+
+// Method returns the HTTP method, which is always GET.
+func (h *StatusHandler) Method() string {
+	return http.MethodGet
+}
+
+// Path returns the base path of the target URL for this handler.
+func (h *StatusHandler) Path() string {
+	return scrubPath
+}
+
+// Handler returns the handler that should be invoked when an HTTP GET is requested to the target endpoint.
+// This handler must be registered with an HTTP server.
+func (h *StatusHandler) Handler() common.HTTPRequestHandler {
+	return h.handleGet
+}
+
+func (h *StatusHandler) handleGet(w http.ResponseWriter, _ *http.Request) {
+	status, err := h.scrubber.Status()
+	if err != nil {
+		logger.Error("Error retrieving CAS scrub status", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	statusBytes, err := h.marshal(status)
+	if err != nil {
+		logger.Error("Error marshalling CAS scrub status", log.WithError(err))
+
+		writeResponse(w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	writeResponse(w, http.StatusOK, statusBytes)
+}
+
+func writeResponse(w http.ResponseWriter, status int, body []byte) {
+	w.WriteHeader(status)
+
+	if len(body) > 0 {
+		if _, err := w.Write(body); err != nil {
+			log.WriteResponseBodyError(logger, err)
+
+			return
+		}
+
+		log.WroteResponse(logger, body)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrubrest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/cas/scrub"
+	"github.com/trustbloc/orb/pkg/store/casscrub"
+)
+
+const scrubURL = "https://example.com/cas-scrub"
+
+func TestNewStatusHandler(t *testing.T) {
+	h := NewStatusHandler(&mockScrubber{})
+	require.NotNil(t, h.Handler())
+	require.Equal(t, http.MethodGet, h.Method())
+	require.Equal(t, "/cas-scrub", h.Path())
+}
+
+func TestStatusHandler_Handler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		s := &mockScrubber{
+			status: &scrub.Status{
+				LastReport: &scrub.Report{Checked: 10, Corrupted: 1},
+				Checkpoint: 1000,
+				Cycles:     2,
+				Quarantined: []*casscrub.Entry{
+					{
+						ResourceHash: "uEiCeCwiJh3ikvBDjDFjeAcX0gVjzR7dygDPb8Vbd4FpWQQ",
+						Status:       casscrub.StatusFailed,
+						Attempts:     10,
+					},
+				},
+			},
+		}
+
+		h := NewStatusHandler(s)
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, scrubURL, http.NoBody)
+
+		h.handleGet(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusOK, result.StatusCode)
+
+		respBytes, err := io.ReadAll(result.Body)
+		require.NoError(t, err)
+		require.NoError(t, result.Body.Close())
+
+		status := &scrub.Status{}
+		require.NoError(t, json.Unmarshal(respBytes, status))
+		require.Equal(t, 10, status.LastReport.Checked)
+		require.Equal(t, 1, status.LastReport.Corrupted)
+		require.Equal(t, int64(1000), status.Checkpoint)
+		require.Equal(t, 2, status.Cycles)
+		require.Len(t, status.Quarantined, 1)
+		require.Equal(t, casscrub.StatusFailed, status.Quarantined[0].Status)
+	})
+
+	t.Run("Status error", func(t *testing.T) {
+		h := NewStatusHandler(&mockScrubber{err: errors.New("injected status error")})
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, scrubURL, http.NoBody)
+
+		h.handleGet(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+
+	t.Run("Marshal error", func(t *testing.T) {
+		h := NewStatusHandler(&mockScrubber{status: &scrub.Status{}})
+		h.marshal = func(v interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		rw := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, scrubURL, http.NoBody)
+
+		h.handleGet(rw, req)
+
+		result := rw.Result()
+		require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+		require.NoError(t, result.Body.Close())
+	})
+}
+
+type mockScrubber struct {
+	status *scrub.Status
+	err    error
+}
+
+func (m *mockScrubber) Status() (*scrub.Status, error) {
+	return m.status, m.err
+}
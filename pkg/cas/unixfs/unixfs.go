@@ -0,0 +1,285 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package unixfs builds the UnixFS DAG of content in the same way as IPFS does when content is added with
+// CID version 1 and default settings, i.e. fixed-size chunks of 256KiB, raw leaves, and a balanced layout with
+// at most 174 links per node. This allows a CAS that doesn't store content in IPFS to produce the same addresses
+// as IPFS for content that is larger than a single chunk.
+package unixfs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	gocid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+const (
+	// ChunkSize is the size of the chunks into which content is split.
+	ChunkSize = 256 * 1024
+
+	// MaxLinks is the maximum number of links in a DAG node.
+	MaxLinks = 174
+
+	// UnixFS data type for a file.
+	unixfsTypeFile = 2
+
+	// Protobuf wire types.
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// Node is a node in the UnixFS DAG of some content.
+type Node struct {
+	// CID is the CID of the node.
+	CID gocid.Cid
+	// Block is the encoded node, i.e. the chunk of content for a (raw) leaf or the dag-pb encoded node for
+	// an intermediate node.
+	Block []byte
+
+	// fileSize is the size of the content that's spanned by this node.
+	fileSize uint64
+	// cumulativeSize is the size of the encoded node plus the encoded nodes of all of its descendants.
+	cumulativeSize uint64
+}
+
+// CID returns the CID (version 1) of the given content. If the content fits in a single chunk then the CID has
+// the 'raw' codec and is the hash of the content, otherwise the CID has the 'dag-pb' codec and is the hash of
+// the root node of the UnixFS DAG.
+func CID(content []byte) (gocid.Cid, error) {
+	root, err := Build(content, nil)
+	if err != nil {
+		return gocid.Undef, err
+	}
+
+	return root.CID, nil
+}
+
+// ResourceHash returns the base64url multibase-encoded multihash of the CID of the given content. For content that
+// fits in a single chunk this is the same as the hashlink resource hash of the content.
+func ResourceHash(content []byte) (string, error) {
+	cid, err := CID(content)
+	if err != nil {
+		return "", err
+	}
+
+	return "u" + base64.RawURLEncoding.EncodeToString(cid.Hash()), nil
+}
+
+// FlatResourceHash returns the base64url multibase-encoded sha2-256 multihash of the entire content, i.e. the
+// hashlink resource hash of the content. Content that spans multiple chunks was addressed this way by earlier
+// versions (and may still be referenced by hashlinks that were produced by them).
+func FlatResourceHash(content []byte) (string, error) {
+	hash, err := mh.Sum(content, mh.SHA2_256, -1)
+	if err != nil {
+		return "", fmt.Errorf("compute multihash: %w", err)
+	}
+
+	return "u" + base64.RawURLEncoding.EncodeToString(hash), nil
+}
+
+// ResourceHashes returns all of the resource hashes by which the given content may be addressed. The first
+// resource hash is the one returned by ResourceHash. If the content spans multiple chunks then the flat resource
+// hash of the content is also returned.
+func ResourceHashes(content []byte) ([]string, error) {
+	resourceHash, err := ResourceHash(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsChunked(len(content)) {
+		return []string{resourceHash}, nil
+	}
+
+	flatResourceHash, err := FlatResourceHash(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{resourceHash, flatResourceHash}, nil
+}
+
+// MatchesResourceHash returns true if the given resource hash is one of the resource hashes by which the given
+// content may be addressed, i.e. the hash of the root of the content's UnixFS DAG or the hash of the entire content.
+func MatchesResourceHash(content []byte, resourceHash string) (bool, error) {
+	resourceHashes, err := ResourceHashes(content)
+	if err != nil {
+		return false, err
+	}
+
+	for _, hash := range resourceHashes {
+		if hash == resourceHash {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IsChunked returns true if content of the given size is split into multiple chunks.
+func IsChunked(size int) bool {
+	return size > ChunkSize
+}
+
+// Build builds the UnixFS DAG of the given content and returns the root node. If visit is not nil then it is
+// invoked for every node in the DAG (children before parents).
+func Build(content []byte, visit func(n *Node) error) (*Node, error) {
+	if len(content) == 0 {
+		return nil, errors.New("empty content")
+	}
+
+	b := &builder{content: content, visit: visit}
+
+	root, err := b.newLeaf()
+	if err != nil {
+		return nil, err
+	}
+
+	// Balanced layout: each iteration adds a level to the tree. The previous root becomes the first child of the
+	// new root and the rest of the new root is filled with subtrees of the same depth as the previous root.
+	for depth := 1; !b.done(); depth++ {
+		root, err = b.fill([]*Node{root}, depth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+type builder struct {
+	content []byte
+	offset  int
+	visit   func(n *Node) error
+}
+
+func (b *builder) done() bool {
+	return b.offset >= len(b.content)
+}
+
+func (b *builder) newLeaf() (*Node, error) {
+	end := b.offset + ChunkSize
+	if end > len(b.content) {
+		end = len(b.content)
+	}
+
+	chunk := b.content[b.offset:end]
+	b.offset = end
+
+	hash, err := mh.Sum(chunk, mh.SHA2_256, -1)
+	if err != nil {
+		return nil, fmt.Errorf("hash chunk: %w", err)
+	}
+
+	n := &Node{
+		CID:            gocid.NewCidV1(gocid.Raw, hash),
+		Block:          chunk,
+		fileSize:       uint64(len(chunk)),
+		cumulativeSize: uint64(len(chunk)),
+	}
+
+	return n, b.emit(n)
+}
+
+// fill adds children to the given children until MaxLinks is reached or all content is consumed. The added children
+// are leaves if depth is 1, otherwise they're subtrees of the given depth minus one.
+func (b *builder) fill(children []*Node, depth int) (*Node, error) {
+	for len(children) < MaxLinks && !b.done() {
+		var child *Node
+
+		var err error
+
+		if depth == 1 {
+			child, err = b.newLeaf()
+		} else {
+			child, err = b.fill(nil, depth-1)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, child)
+	}
+
+	n, err := newIntermediateNode(children)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, b.emit(n)
+}
+
+func (b *builder) emit(n *Node) error {
+	if b.visit == nil {
+		return nil
+	}
+
+	return b.visit(n)
+}
+
+func newIntermediateNode(children []*Node) (*Node, error) {
+	var fileSize, cumulativeSize uint64
+
+	// UnixFS Data message: Type, filesize and blocksizes.
+	var data []byte
+
+	for _, c := range children {
+		fileSize += c.fileSize
+		cumulativeSize += c.cumulativeSize
+	}
+
+	data = appendVarintField(data, 1, unixfsTypeFile)
+	data = appendVarintField(data, 3, fileSize)
+
+	for _, c := range children {
+		data = appendVarintField(data, 4, c.fileSize)
+	}
+
+	// dag-pb PBNode message: Links are encoded before Data.
+	var block []byte
+
+	for _, c := range children {
+		// PBLink message: Hash, Name (always present, empty for file chunks) and Tsize.
+		var link []byte
+
+		link = appendBytesField(link, 1, c.CID.Bytes())
+		link = appendBytesField(link, 2, nil)
+		link = appendVarintField(link, 3, c.cumulativeSize)
+
+		block = appendBytesField(block, 2, link)
+	}
+
+	block = appendBytesField(block, 1, data)
+
+	hash, err := mh.Sum(block, mh.SHA2_256, -1)
+	if err != nil {
+		return nil, fmt.Errorf("hash node: %w", err)
+	}
+
+	return &Node{
+		CID:            gocid.NewCidV1(gocid.DagProtobuf, hash),
+		Block:          block,
+		fileSize:       fileSize,
+		cumulativeSize: cumulativeSize + uint64(len(block)),
+	}, nil
+}
+
+func appendVarintField(b []byte, field int, value uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field<<3|wireVarint))
+
+	return binary.AppendUvarint(b, value)
+}
+
+func appendBytesField(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field<<3|wireBytes))
+	b = binary.AppendUvarint(b, uint64(len(value)))
+
+	return append(b, value...)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package unixfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	gocid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
+)
+
+func TestCID(t *testing.T) {
+	t.Run("Single chunk", func(t *testing.T) {
+		for _, content := range [][]byte{[]byte("content"), bytes.Repeat([]byte("a"), ChunkSize)} {
+			cid, err := CID(content)
+			require.NoError(t, err)
+			require.Equal(t, uint64(gocid.Raw), cid.Type())
+
+			resourceHash, err := hashlink.New().CreateResourceHash(content)
+			require.NoError(t, err)
+
+			v1CID, err := multihash.ToV1CID(resourceHash)
+			require.NoError(t, err)
+			require.Equal(t, v1CID, cid.String())
+
+			rh, err := ResourceHash(content)
+			require.NoError(t, err)
+			require.Equal(t, resourceHash, rh)
+		}
+	})
+
+	t.Run("Multiple chunks", func(t *testing.T) {
+		content := bytes.Repeat([]byte("a"), ChunkSize+1)
+
+		cid, err := CID(content)
+		require.NoError(t, err)
+		require.Equal(t, uint64(gocid.DagProtobuf), cid.Type())
+
+		resourceHash, err := hashlink.New().CreateResourceHash(content)
+		require.NoError(t, err)
+
+		rh, err := ResourceHash(content)
+		require.NoError(t, err)
+		require.NotEqual(t, resourceHash, rh)
+
+		mhFromCID, err := multihash.CIDToMultihash(cid.String())
+		require.NoError(t, err)
+		require.Equal(t, mhFromCID, rh)
+	})
+
+	t.Run("Empty content", func(t *testing.T) {
+		_, err := CID(nil)
+		require.EqualError(t, err, "empty content")
+
+		_, err = ResourceHash(nil)
+		require.EqualError(t, err, "empty content")
+	})
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("Two leaves", func(t *testing.T) {
+		content := make([]byte, ChunkSize+10)
+		for i := range content {
+			content[i] = byte(i)
+		}
+
+		var nodes []*Node
+
+		root, err := Build(content, func(n *Node) error {
+			nodes = append(nodes, n)
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, nodes, 3)
+		require.Equal(t, root, nodes[2])
+
+		require.Equal(t, content[:ChunkSize], nodes[0].Block)
+		require.Equal(t, content[ChunkSize:], nodes[1].Block)
+
+		// Links (with an empty name) followed by the UnixFS data (type file, file size and block sizes).
+		var expected []byte
+
+		for _, leaf := range nodes[:2] {
+			var link []byte
+
+			link = appendBytesField(link, 1, leaf.CID.Bytes())
+			link = append(link, 0x12, 0x00)
+			link = appendVarintField(link, 3, uint64(len(leaf.Block)))
+
+			expected = appendBytesField(expected, 2, link)
+		}
+
+		expected = appendBytesField(expected, 1,
+			appendVarintField(appendVarintField(appendVarintField(appendVarintField(nil,
+				1, 2), 3, ChunkSize+10), 4, ChunkSize), 4, 10))
+
+		require.Equal(t, expected, root.Block)
+		require.Equal(t, uint64(ChunkSize+10), root.fileSize)
+		require.Equal(t, uint64(ChunkSize+10+len(root.Block)), root.cumulativeSize)
+	})
+
+	t.Run("Balanced layout", func(t *testing.T) {
+		content := bytes.Repeat([]byte("a"), (MaxLinks+1)*ChunkSize)
+
+		var leaves, intermediate int
+
+		root, err := Build(content, func(n *Node) error {
+			if n.CID.Type() == gocid.Raw {
+				leaves++
+			} else {
+				intermediate++
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, MaxLinks+1, leaves)
+
+		// A full node with MaxLinks leaves, a node with a single leaf and the root.
+		require.Equal(t, 3, intermediate)
+		require.Equal(t, uint64(len(content)), root.fileSize)
+	})
+
+	t.Run("Visit error", func(t *testing.T) {
+		errExpected := errors.New("injected visit error")
+
+		_, err := Build([]byte("content"), func(*Node) error {
+			return errExpected
+		})
+		require.ErrorIs(t, err, errExpected)
+	})
+}
+
+func TestResourceHashes(t *testing.T) {
+	t.Run("Single chunk", func(t *testing.T) {
+		content := []byte("content")
+
+		resourceHash, err := hashlink.New().CreateResourceHash(content)
+		require.NoError(t, err)
+
+		flatHash, err := FlatResourceHash(content)
+		require.NoError(t, err)
+		require.Equal(t, resourceHash, flatHash)
+
+		hashes, err := ResourceHashes(content)
+		require.NoError(t, err)
+		require.Equal(t, []string{resourceHash}, hashes)
+	})
+
+	t.Run("Multiple chunks", func(t *testing.T) {
+		content := bytes.Repeat([]byte("a"), ChunkSize+1)
+
+		flatHash, err := hashlink.New().CreateResourceHash(content)
+		require.NoError(t, err)
+
+		fh, err := FlatResourceHash(content)
+		require.NoError(t, err)
+		require.Equal(t, flatHash, fh)
+
+		rh, err := ResourceHash(content)
+		require.NoError(t, err)
+
+		hashes, err := ResourceHashes(content)
+		require.NoError(t, err)
+		require.Equal(t, []string{rh, flatHash}, hashes)
+	})
+
+	t.Run("Empty content", func(t *testing.T) {
+		_, err := ResourceHashes(nil)
+		require.EqualError(t, err, "empty content")
+	})
+}
+
+func TestIsChunked(t *testing.T) {
+	require.False(t, IsChunked(ChunkSize))
+	require.True(t, IsChunked(ChunkSize+1))
+}
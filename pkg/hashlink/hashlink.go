@@ -14,8 +14,12 @@ import (
 	"strings"
 
 	"github.com/fxamacker/cbor/v2"
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
 	"github.com/multiformats/go-multihash"
 	"github.com/trustbloc/sidetree-go/pkg/hashing"
+
+	orbmultihash "github.com/trustbloc/orb/pkg/multihash"
 )
 
 const (
@@ -273,8 +277,10 @@ func stringEntry(entry interface{}) (string, error) {
 	return entryStr, nil
 }
 
+// isValidMultihash validates the given resource hash, which may either be a multihash (encoded with any multibase)
+// or a v1 CID (with any multibase and codec).
 func (hl *HashLink) isValidMultihash(encodedMultihash string) error {
-	multihashBytes, err := hl.decoder(encodedMultihash)
+	multihashBytes, err := hl.decodeResourceHash(encodedMultihash)
 	if err != nil {
 		return fmt.Errorf("failed to decode encoded multihash: %w", err)
 	}
@@ -291,6 +297,30 @@ func (hl *HashLink) isValidMultihash(encodedMultihash string) error {
 	return nil
 }
 
+func (hl *HashLink) decodeResourceHash(enc string) ([]byte, error) {
+	if enc != "" && orbmultihash.IsValidCID(enc) {
+		cid, err := gocid.Decode(enc)
+		if err != nil {
+			return nil, err
+		}
+
+		return cid.Hash(), nil
+	}
+
+	multihashBytes, err := hl.decoder(enc)
+	if err == nil {
+		return multihashBytes, nil
+	}
+
+	// The resource hash may be encoded using a multibase other than the default.
+	_, multihashBytes, e := multibase.Decode(enc)
+	if e != nil {
+		return nil, err
+	}
+
+	return multihashBytes, nil
+}
+
 // ToString parses the given hashlink(s) and returns a human-readable form.
 func ToString(hl ...*url.URL) string {
 	str := ""
@@ -253,6 +253,19 @@ func TestHashLink_ParseHashLink(t *testing.T) {
 		require.Equal(t, []string{exampleURL}, hlInfo.Links)
 	})
 
+	t.Run("success - resource hash with other multibases and codecs", func(t *testing.T) {
+		hl := New()
+
+		for _, rh := range []string{
+			"bciqh7a5rmv77d7ctxew4dakiuhlf37bnjmp2hvtxfbfn3uqacjwza2i",    // base32 multihash
+			"bafyreid7qoywk77r7rj3slobqfekdvs57qwuwh5d2z3sqsw52iabe3mqne", // base32 dag-cbor CID
+		} {
+			hlInfo, err := hl.ParseHashLink(HLPrefix + rh)
+			require.NoError(t, err)
+			require.Equal(t, rh, hlInfo.ResourceHash)
+		}
+	})
+
 	t.Run("success - with links", func(t *testing.T) {
 		testRH := "uEiB_g7Flf_H8U7ktwYFIodZd_C1LH6PWdyhK3dIAEm2QaQ"
 		testMD := "uoQ-CeEdodHRwczovL2V4YW1wbGUuY29tL2Nhcy91RWlBc2l3amFYT1lEbU9IeG12RGwzTXgwVGZKMHVDYXI1WVhxdW1qRkpVTklCZ3g1aXBmczovL1FtVUI5TnI3UnBxTllRcHloNFc5cjNSUU50dGlQUTZCUTlpUUxrdzlMenRKRno"
@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	"github.com/trustbloc/orb/pkg/datauri"
 	"github.com/trustbloc/orb/pkg/hashlink"
 )
@@ -462,14 +463,13 @@ func validateOriginal(original []*Reference, anchorHL *url.URL) error {
 		return fmt.Errorf("invalid 'original' content: %w", err)
 	}
 
-	hashOfOriginal, err := hashlink.New().CreateResourceHash(content)
+	ok, err := unixfs.MatchesResourceHash(content, anchorHL.Opaque)
 	if err != nil {
 		return fmt.Errorf("create hashlink from 'original' content: %w", err)
 	}
 
-	if hashOfOriginal != anchorHL.Opaque {
-		return fmt.Errorf("hash [%s] of the 'original' content does not match the anchor hash [%s]",
-			hashOfOriginal, anchorHL.Opaque)
+	if !ok {
+		return fmt.Errorf("hash of the 'original' content does not match the anchor hash [%s]", anchorHL.Opaque)
 	}
 
 	return nil
@@ -23,6 +23,10 @@ func (m *MetricsProvider) OutboxResolveInboxesTime(value time.Duration) {
 func (m *MetricsProvider) InboxHandlerTime(activityType string, value time.Duration) {
 }
 
+// InboxIncrementThrottledCount increments the number of inbox requests that were throttled by the given limit scope.
+func (m *MetricsProvider) InboxIncrementThrottledCount(scope string) {
+}
+
 // WriteAnchorTime records the time it takes to write an anchor credential and post an 'Offer' activity.
 func (m *MetricsProvider) WriteAnchorTime(value time.Duration) {
 }
@@ -75,6 +79,33 @@ func (m *MetricsProvider) WriteAnchorSignLocalWatchTime(value time.Duration) {
 func (m *MetricsProvider) WriteAnchorResolveHostMetaLinkTime(value time.Duration) {
 }
 
+// WitnessIncrementRequestCount increments the number of times that an anchor was offered to the given witness.
+func (m *MetricsProvider) WitnessIncrementRequestCount(witness string) {
+}
+
+// WitnessIncrementReOfferCount increments the number of times that an anchor was re-offered to the given witness
+// because other witnesses did not return a proof in time.
+func (m *MetricsProvider) WitnessIncrementReOfferCount(witness string) {
+}
+
+// WitnessIncrementTimeoutCount increments the number of times that the given witness did not return a proof in time.
+func (m *MetricsProvider) WitnessIncrementTimeoutCount(witness string) {
+}
+
+// WitnessIncrementOfferRejectedCount increments the number of 'Offer' activities that were rejected by this
+// witness for the given reason.
+func (m *MetricsProvider) WitnessIncrementOfferRejectedCount(reason string) {
+}
+
+// WriteAnchorIncrementBatchSplitCount increments the number of times that a batch of operations was split into
+// multiple anchors because the given limit was exceeded.
+func (m *MetricsProvider) WriteAnchorIncrementBatchSplitCount(reason string) {
+}
+
+// WitnessProofTurnaroundTime records the time between the selection of the given witness and the receipt of its proof.
+func (m *MetricsProvider) WitnessProofTurnaroundTime(witness string, value time.Duration) {
+}
+
 // ProcessWitnessedAnchorCredentialTime records the time it takes to process a witnessed anchor credential
 // by publishing it to the Observer and posting a 'Create' activity.
 func (m *MetricsProvider) ProcessWitnessedAnchorCredentialTime(value time.Duration) {
@@ -138,6 +169,22 @@ func (m *MetricsProvider) CASIncrementCacheHitCount() {
 func (m *MetricsProvider) CASReadTime(casType string, value time.Duration) {
 }
 
+// CASScrubIncrementCheckedCount increments the number of CAS items checked by the scrubber.
+func (m *MetricsProvider) CASScrubIncrementCheckedCount() {
+}
+
+// CASScrubIncrementCorruptedCount increments the number of corrupted CAS items detected by the scrubber.
+func (m *MetricsProvider) CASScrubIncrementCorruptedCount() {
+}
+
+// CASScrubIncrementRepairedCount increments the number of corrupted CAS items repaired by the scrubber.
+func (m *MetricsProvider) CASScrubIncrementRepairedCount() {
+}
+
+// CASScrubRunTime records the time it takes for a single run of the CAS scrubber.
+func (m *MetricsProvider) CASScrubRunTime(value time.Duration) {
+}
+
 // BatchSize records the size of an operation batch.
 func (m *MetricsProvider) BatchSize(float64) {
 }
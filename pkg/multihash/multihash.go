@@ -16,7 +16,7 @@ import (
 	mh "github.com/multiformats/go-multihash"
 )
 
-// IsValidCID returns true if value passed in is a valid CID.
+// IsValidCID returns true if value passed in is a valid CID. A v1 CID may be encoded using any multibase.
 func IsValidCID(value string) bool {
 	if strings.HasPrefix(value, "/ipns/") {
 		return true
@@ -27,11 +27,21 @@ func IsValidCID(value string) bool {
 		return false
 	}
 
-	if cid.String() != value {
+	if cid.Version() == 0 {
+		return cid.String() == value
+	}
+
+	encoding, _, err := multibase.Decode(value)
+	if err != nil {
+		return false
+	}
+
+	cidStr, err := cid.StringOfBase(encoding)
+	if err != nil {
 		return false
 	}
 
-	return true
+	return cidStr == value
 }
 
 // ToV0CID takes a multibase-encoded multihash and converts it to a V0 CID.
@@ -54,6 +64,48 @@ func ToV1CID(multibaseEncodedMultihash string) (string, error) {
 	return gocid.NewCidV1(gocid.Raw, multihash).String(), nil
 }
 
+// ToV1DagPBCID takes a multibase-encoded multihash and converts it to a V1 CID with the dag-pb codec, which is
+// the CID of the root of a UnixFS DAG (i.e. the CID of content that spans multiple chunks).
+func ToV1DagPBCID(multibaseEncodedMultihash string) (string, error) {
+	multihash, err := getMultihashFromMultibaseEncodedMultihash(multibaseEncodedMultihash)
+	if err != nil {
+		return "", err
+	}
+
+	return gocid.NewCidV1(gocid.DagProtobuf, multihash).String(), nil
+}
+
+// ToV1CIDWithFormat takes a multibase-encoded multihash and converts it to a V1 CID with the given codec which is
+// encoded using the given multibase.
+func ToV1CIDWithFormat(multibaseEncodedMultihash string, codec uint64, base multibase.Encoding) (string, error) {
+	multihash, err := getMultihashFromMultibaseEncodedMultihash(multibaseEncodedMultihash)
+	if err != nil {
+		return "", err
+	}
+
+	cid, err := gocid.NewCidV1(codec, multihash).StringOfBase(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode CID: %w", err)
+	}
+
+	return cid, nil
+}
+
+// ToMultibase re-encodes the given multibase-encoded multihash using the given multibase.
+func ToMultibase(multibaseEncodedMultihash string, base multibase.Encoding) (string, error) {
+	multihash, err := getMultihashFromMultibaseEncodedMultihash(multibaseEncodedMultihash)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := multibase.Encode(base, multihash)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode multihash: %w", err)
+	}
+
+	return encoded, nil
+}
+
 // CIDToMultihash takes a V0 or V1 CID and converts it to a multibase-encoded (with base64url as the base) multihash.
 func CIDToMultihash(cid string) (string, error) {
 	parsedCID, err := gocid.Decode(cid)
@@ -71,6 +123,42 @@ func CIDToMultihash(cid string) (string, error) {
 	return multibaseEncodedMultihash, nil
 }
 
+// CIDOrMultihashToMultihash converts the given CID or multihash (encoded with any multibase) to a multibase-encoded
+// (with base64url as the base) multihash. Values that are neither a CID nor a multibase-encoded multihash are
+// returned as is.
+func CIDOrMultihashToMultihash(cidOrMultihash string) (string, error) {
+	if IsValidCID(cidOrMultihash) {
+		return CIDToMultihash(cidOrMultihash)
+	}
+
+	encoding, _, err := multibase.Decode(cidOrMultihash)
+	if err != nil || encoding == multibase.Base64url {
+		return cidOrMultihash, nil
+	}
+
+	multihash, err := ToMultibase(cidOrMultihash, multibase.Base64url)
+	if err != nil {
+		return cidOrMultihash, nil //nolint:nilerr
+	}
+
+	return multihash, nil
+}
+
+// HashCode returns the (multicodec) code of the hash function of the given multibase-encoded multihash.
+func HashCode(multibaseEncodedMultihash string) (uint64, error) {
+	multihash, err := getMultihashFromMultibaseEncodedMultihash(multibaseEncodedMultihash)
+	if err != nil {
+		return 0, err
+	}
+
+	decoded, err := mh.Decode(multihash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode multihash: %w", err)
+	}
+
+	return decoded.Code, nil
+}
+
 func getMultihashFromMultibaseEncodedMultihash(multibaseEncodedMultihash string) (mh.Multihash, error) {
 	_, multihashBytes, err := multibase.Decode(multibaseEncodedMultihash)
 	if err != nil {
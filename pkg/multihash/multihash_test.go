@@ -9,6 +9,8 @@ package multihash_test
 import (
 	"testing"
 
+	gocid "github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
 	"github.com/stretchr/testify/require"
 
 	"github.com/trustbloc/orb/pkg/multihash"
@@ -29,6 +31,17 @@ func TestIsValidCID(t *testing.T) {
 		require.False(t, valid)
 	})
 
+	t.Run("success - true (other multibases and codecs)", func(t *testing.T) {
+		require.True(t, multihash.IsValidCID("zdpuApBVMJDcbmYtdfnp7yGvK1DTysLMjd25jq5GSwbqtDpzF"))
+		require.True(t, multihash.IsValidCID("uAakCEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA"))
+		require.True(t, multihash.IsValidCID("QmS6haUrtQ8tcTTLCMdknWXAhUci1g1wfHorxM65RxNc5R"))
+	})
+
+	t.Run("success - false (multihash encoded with other multibases)", func(t *testing.T) {
+		require.False(t, multihash.IsValidCID("zQmS6haUrtQ8tcTTLCMdknWXAhUci1g1wfHorxM65RxNc5R"))
+		require.False(t, multihash.IsValidCID("bciqdpw64dxuar57jqrtkpm2gshqhsabz2w34aeluyzgd6n6xwi6bgja"))
+	})
+
 	t.Run("success - ipns", func(t *testing.T) {
 		valid := multihash.IsValidCID("/ipns/k51qzi5uqu5dgkmm1afrkmex5mzpu5r774jstpxjmro6mdsaullur27nfxle1q/.well-known/host-meta.json")
 		require.True(t, valid)
@@ -75,6 +88,62 @@ func TestToV1CID(t *testing.T) {
 	})
 }
 
+func TestToV1DagPBCID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		v1CID, err := multihash.ToV1DagPBCID("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA")
+		require.NoError(t, err)
+		require.Equal(t, "bafybeibx3pob32ai67uyizvhwndjdydzaa45ln6acf2mmtb7g7l3epateq", v1CID)
+	})
+	t.Run("Fail to decode multibase-encoded multihash", func(t *testing.T) {
+		v1CID, err := multihash.ToV1DagPBCID("")
+		require.EqualError(t, err, "failed to decode multibase-encoded multihash: "+
+			"cannot decode multibase for zero length string")
+		require.Empty(t, v1CID)
+	})
+}
+
+func TestToV1CIDWithFormat(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		cid, err := multihash.ToV1CIDWithFormat("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA",
+			gocid.DagCBOR, multibase.Base58BTC)
+		require.NoError(t, err)
+		require.Equal(t, "zdpuApBVMJDcbmYtdfnp7yGvK1DTysLMjd25jq5GSwbqtDpzF", cid)
+
+		cid, err = multihash.ToV1CIDWithFormat("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA",
+			0x0129, multibase.Base64url)
+		require.NoError(t, err)
+		require.Equal(t, "uAakCEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", cid)
+
+		cid, err = multihash.ToV1CIDWithFormat("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA",
+			gocid.Raw, multibase.Base32)
+		require.NoError(t, err)
+		require.Equal(t, "bafkreibx3pob32ai67uyizvhwndjdydzaa45ln6acf2mmtb7g7l3epateq", cid)
+	})
+	t.Run("Fail to decode multibase-encoded multihash", func(t *testing.T) {
+		cid, err := multihash.ToV1CIDWithFormat("", gocid.Raw, multibase.Base32)
+		require.Error(t, err)
+		require.Empty(t, cid)
+	})
+	t.Run("Unsupported multibase", func(t *testing.T) {
+		cid, err := multihash.ToV1CIDWithFormat("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", gocid.Raw, multibase.Encoding('x'))
+		require.Error(t, err)
+		require.Empty(t, cid)
+	})
+}
+
+func TestToMultibase(t *testing.T) {
+	mh, err := multihash.ToMultibase("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", multibase.Base32)
+	require.NoError(t, err)
+	require.Equal(t, "bciqdpw64dxuar57jqrtkpm2gshqhsabz2w34aeluyzgd6n6xwi6bgja", mh)
+
+	mh, err = multihash.ToMultibase(mh, multibase.Base58BTC)
+	require.NoError(t, err)
+	require.Equal(t, "zQmS6haUrtQ8tcTTLCMdknWXAhUci1g1wfHorxM65RxNc5R", mh)
+
+	_, err = multihash.ToMultibase("", multibase.Base32)
+	require.Error(t, err)
+}
+
 func TestCIDToMultihash(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		t.Run("V0 CID", func(t *testing.T) {
@@ -94,3 +163,48 @@ func TestCIDToMultihash(t *testing.T) {
 		require.Empty(t, multihashFromCID)
 	})
 }
+
+func TestCIDOrMultihashToMultihash(t *testing.T) {
+	t.Run("CID", func(t *testing.T) {
+		mh, err := multihash.CIDOrMultihashToMultihash("bafybeibx3pob32ai67uyizvhwndjdydzaa45ln6acf2mmtb7g7l3epateq")
+		require.NoError(t, err)
+		require.Equal(t, "uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", mh)
+	})
+	t.Run("Multihash", func(t *testing.T) {
+		mh, err := multihash.CIDOrMultihashToMultihash("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA")
+		require.NoError(t, err)
+		require.Equal(t, "uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", mh)
+	})
+	t.Run("CID with other multibase and codec", func(t *testing.T) {
+		mh, err := multihash.CIDOrMultihashToMultihash("zdpuApBVMJDcbmYtdfnp7yGvK1DTysLMjd25jq5GSwbqtDpzF")
+		require.NoError(t, err)
+		require.Equal(t, "uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", mh)
+	})
+	t.Run("Multihash with other multibase", func(t *testing.T) {
+		mh, err := multihash.CIDOrMultihashToMultihash("bciqdpw64dxuar57jqrtkpm2gshqhsabz2w34aeluyzgd6n6xwi6bgja")
+		require.NoError(t, err)
+		require.Equal(t, "uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA", mh)
+	})
+	t.Run("Not a multihash", func(t *testing.T) {
+		mh, err := multihash.CIDOrMultihashToMultihash("cid1")
+		require.NoError(t, err)
+		require.Equal(t, "cid1", mh)
+	})
+	t.Run("IPNS path", func(t *testing.T) {
+		_, err := multihash.CIDOrMultihashToMultihash("/ipns/name")
+		require.Error(t, err)
+	})
+}
+
+func TestHashCode(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		code, err := multihash.HashCode("uEiA329wd6Aj36YRmp7NGkeB5ADnVt8ARdMZMPzfXsjwTJA")
+		require.NoError(t, err)
+		require.Equal(t, uint64(0x12), code) // sha2-256
+	})
+
+	t.Run("Not a multihash", func(t *testing.T) {
+		_, err := multihash.HashCode("cid1")
+		require.Error(t, err)
+	})
+}
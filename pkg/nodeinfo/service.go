@@ -17,6 +17,7 @@ import (
 	"github.com/trustbloc/logutil-go/pkg/log"
 
 	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
 	apstore "github.com/trustbloc/orb/pkg/activitypub/store/spi"
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/lifecycle"
@@ -25,6 +26,30 @@ import (
 
 var logger = log.New("nodeinfo")
 
+const peerHealthMetadataKey = "peerHealth"
+
+type peerHealthProvider interface {
+	GetAll() []*peerhealth.Health
+}
+
+// PeerHealthMetadata contains a summary of the health of the ActivityPub peers of this server.
+type PeerHealthMetadata struct {
+	Total       int      `json:"total"`
+	Available   int      `json:"available"`
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// Option is a NodeInfo service option.
+type Option func(s *Service)
+
+// WithPeerHealth sets the peer health provider. If set, a summary of the health of
+// the ActivityPub peers is included in the NodeInfo metadata.
+func WithPeerHealth(p peerHealthProvider) Option {
+	return func(s *Service) {
+		s.peerHealth = p
+	}
+}
+
 type stats struct {
 	Posts    uint64
 	Comments uint64
@@ -45,13 +70,16 @@ type Service struct {
 	stats                   *stats
 	mutex                   sync.RWMutex
 	multipleTagQueryCapable bool
+	peerHealth              peerHealthProvider
 }
 
 // NewService returns a new NodeInfo service.
 // If this Orb server uses a storage provider that can do queries using 2 tags, then we can take advantage of a
 // feature in the underlying Aries storage provider to update the stats more efficiently.
 // If logger is nil, then a default will be used.
-func NewService(serviceIRI *url.URL, refreshInterval time.Duration, apStore apstore.Store, multipleTagQueryCapable bool) *Service {
+func NewService(serviceIRI *url.URL, refreshInterval time.Duration, apStore apstore.Store, multipleTagQueryCapable bool,
+	opts ...Option,
+) *Service {
 	r := &Service{
 		apStore:                 apStore,
 		serviceIRI:              serviceIRI,
@@ -61,6 +89,10 @@ func NewService(serviceIRI *url.URL, refreshInterval time.Duration, apStore apst
 		multipleTagQueryCapable: multipleTagQueryCapable,
 	}
 
+	for _, opt := range opts {
+		opt(r)
+	}
+
 	r.Lifecycle = lifecycle.New("nodeinfo",
 		lifecycle.WithStart(r.start),
 		lifecycle.WithStop(r.stop))
@@ -102,6 +134,32 @@ func (r *Service) GetNodeInfo(version Version) *NodeInfo {
 			LocalPosts:    int(stats.Posts),
 			LocalComments: int(stats.Comments),
 		},
+		Metadata: r.getMetadata(),
+	}
+}
+
+func (r *Service) getMetadata() map[string]interface{} {
+	if r.peerHealth == nil {
+		return nil
+	}
+
+	peers := r.peerHealth.GetAll()
+	if len(peers) == 0 {
+		return nil
+	}
+
+	md := &PeerHealthMetadata{Total: len(peers)}
+
+	for _, p := range peers {
+		if p.State == peerhealth.StateOpen {
+			md.Unavailable = append(md.Unavailable, p.IRI)
+		} else {
+			md.Available++
+		}
+	}
+
+	return map[string]interface{}{
+		peerHealthMetadataKey: md,
 	}
 }
 
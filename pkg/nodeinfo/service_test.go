@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package nodeinfo
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/trustbloc/logutil-go/pkg/log"
 
+	"github.com/trustbloc/orb/pkg/activitypub/peerhealth"
 	"github.com/trustbloc/orb/pkg/activitypub/store/ariesstore"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
 	"github.com/trustbloc/orb/pkg/activitypub/store/spi"
@@ -123,3 +125,28 @@ func runServiceTest(t *testing.T, apStore spi.Store, multipleTagQueryCapable boo
 	require.Equal(t, numCreates, nodeInfo.Usage.LocalPosts)
 	require.Equal(t, numLikes, nodeInfo.Usage.LocalComments)
 }
+
+func TestService_PeerHealthMetadata(t *testing.T) {
+	serviceIRI := testutil.MustParseURL("https://example.com/services/orb")
+	service2IRI := testutil.MustParseURL("https://domain2.com/services/orb")
+	service3IRI := testutil.MustParseURL("https://domain3.com/services/orb")
+
+	peerHealth := peerhealth.New(peerhealth.Config{FailureThreshold: 1})
+
+	s := NewService(serviceIRI, time.Minute, memstore.New(""), false, WithPeerHealth(peerHealth))
+	require.NotNil(t, s)
+
+	require.Empty(t, s.GetNodeInfo(V2_1).Metadata)
+
+	peerHealth.RecordSuccess(service2IRI, time.Millisecond)
+	peerHealth.RecordFailure(service3IRI, errors.New("injected error"))
+
+	nodeInfo := s.GetNodeInfo(V2_1)
+	require.NotNil(t, nodeInfo)
+
+	md, ok := nodeInfo.Metadata[peerHealthMetadataKey].(*PeerHealthMetadata)
+	require.True(t, ok)
+	require.Equal(t, 2, md.Total)
+	require.Equal(t, 1, md.Available)
+	require.Equal(t, []string{service3IRI.String()}, md.Unavailable)
+}
@@ -47,6 +47,18 @@ func (nm NoOptMetrics) CASWriteTime(value time.Duration) {}
 // CASReadTime records the time it takes to read a document from CAS storage.
 func (nm NoOptMetrics) CASReadTime(casType string, value time.Duration) {}
 
+// CASScrubIncrementCheckedCount increments the number of CAS items checked by the scrubber.
+func (nm NoOptMetrics) CASScrubIncrementCheckedCount() {}
+
+// CASScrubIncrementCorruptedCount increments the number of corrupted CAS items detected by the scrubber.
+func (nm NoOptMetrics) CASScrubIncrementCorruptedCount() {}
+
+// CASScrubIncrementRepairedCount increments the number of corrupted CAS items repaired by the scrubber.
+func (nm NoOptMetrics) CASScrubIncrementRepairedCount() {}
+
+// CASScrubRunTime records the time it takes for a single run of the CAS scrubber.
+func (nm NoOptMetrics) CASScrubRunTime(value time.Duration) {}
+
 // PutPublishedOperations records the time to store published operations.
 func (nm NoOptMetrics) PutPublishedOperations(duration time.Duration) {}
 
@@ -109,6 +121,9 @@ func (nm NoOptMetrics) ProcessDIDTime(value time.Duration) {}
 // InboxHandlerTime records the time it takes to handle an activity posted to the inbox.
 func (nm NoOptMetrics) InboxHandlerTime(activityType string, value time.Duration) {}
 
+// InboxIncrementThrottledCount increments the number of inbox requests that were throttled by the given limit scope.
+func (nm NoOptMetrics) InboxIncrementThrottledCount(scope string) {}
+
 // OutboxPostTime records the time it takes to post a message to the outbox.
 func (nm NoOptMetrics) OutboxPostTime(value time.Duration) {}
 
@@ -161,6 +176,27 @@ func (nm NoOptMetrics) WriteAnchorSignLocalWatchTime(value time.Duration) {}
 // WriteAnchorResolveHostMetaLinkTime records the time it takes to resolve host meta link.
 func (nm NoOptMetrics) WriteAnchorResolveHostMetaLinkTime(value time.Duration) {}
 
+// WitnessIncrementRequestCount increments the number of times that an anchor was offered to the given witness.
+func (nm NoOptMetrics) WitnessIncrementRequestCount(witness string) {}
+
+// WitnessIncrementReOfferCount increments the number of times that an anchor was re-offered to the given witness
+// because other witnesses did not return a proof in time.
+func (nm NoOptMetrics) WitnessIncrementReOfferCount(witness string) {}
+
+// WitnessIncrementTimeoutCount increments the number of times that the given witness did not return a proof in time.
+func (nm NoOptMetrics) WitnessIncrementTimeoutCount(witness string) {}
+
+// WriteAnchorIncrementBatchSplitCount increments the number of times that a batch of operations was split into
+// multiple anchors because the given limit was exceeded.
+func (nm NoOptMetrics) WriteAnchorIncrementBatchSplitCount(reason string) {}
+
+// WitnessIncrementOfferRejectedCount increments the number of 'Offer' activities that were rejected by this
+// witness for the given reason.
+func (nm NoOptMetrics) WitnessIncrementOfferRejectedCount(reason string) {}
+
+// WitnessProofTurnaroundTime records the time between the selection of the given witness and the receipt of its proof.
+func (nm NoOptMetrics) WitnessProofTurnaroundTime(witness string, value time.Duration) {}
+
 // AddOperationTime records the time it takes to add an operation to the queue.
 func (nm NoOptMetrics) AddOperationTime(value time.Duration) {}
 
@@ -40,6 +40,12 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.WriteAnchorStoreTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorSignLocalWatchTime(time.Second) })
 		require.NotPanics(t, func() { m.WriteAnchorResolveHostMetaLinkTime(time.Second) })
+		require.NotPanics(t, func() { m.WitnessIncrementRequestCount("https://witness.com") })
+		require.NotPanics(t, func() { m.WitnessIncrementReOfferCount("https://witness.com") })
+		require.NotPanics(t, func() { m.WitnessIncrementTimeoutCount("https://witness.com") })
+		require.NotPanics(t, func() { m.WitnessIncrementOfferRejectedCount("quota-exceeded") })
+		require.NotPanics(t, func() { m.WriteAnchorIncrementBatchSplitCount("max-operations") })
+		require.NotPanics(t, func() { m.WitnessProofTurnaroundTime("https://witness.com", time.Second) })
 		require.NotPanics(t, func() { m.ProcessWitnessedAnchorCredentialTime(time.Second) })
 		require.NotPanics(t, func() { m.AddOperationTime(time.Second) })
 		require.NotPanics(t, func() { m.BatchCutTime(time.Second) })
@@ -51,9 +57,14 @@ func TestMetrics(t *testing.T) {
 		require.NotPanics(t, func() { m.CASResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.CASIncrementCacheHitCount() })
 		require.NotPanics(t, func() { m.CASReadTime("local", time.Second) })
+		require.NotPanics(t, func() { m.CASScrubIncrementCheckedCount() })
+		require.NotPanics(t, func() { m.CASScrubIncrementCorruptedCount() })
+		require.NotPanics(t, func() { m.CASScrubIncrementRepairedCount() })
+		require.NotPanics(t, func() { m.CASScrubRunTime(time.Second) })
 		require.NotPanics(t, func() { m.DocumentCreateUpdateTime(time.Second) })
 		require.NotPanics(t, func() { m.DocumentResolveTime(time.Second) })
 		require.NotPanics(t, func() { m.OutboxIncrementActivityCount("Create") })
+		require.NotPanics(t, func() { m.InboxIncrementThrottledCount("actor") })
 		require.NotPanics(t, func() { m.DBPutTime("CouchDB", time.Second) })
 		require.NotPanics(t, func() { m.DBGetTime("CouchDB", time.Second) })
 		require.NotPanics(t, func() { m.DBGetTagsTime("CouchDB", time.Second) })
@@ -23,6 +23,13 @@ var logger = metrics.Logger
 
 var createOnce sync.Once
 
+const (
+	// witnessLabel is the label of the metrics that are partitioned by witness URI.
+	witnessLabel = "witness"
+	// reasonLabel is the label of the metrics that are partitioned by rejection reason.
+	reasonLabel = "reason"
+)
+
 type httpServer interface {
 	Start() error
 	Stop(ctx context.Context) error
@@ -83,6 +90,7 @@ type PromMetrics struct {
 	apOutboxResolveInboxesTime prometheus.Histogram
 	apInboxHandlerTimes        map[string]prometheus.Histogram
 	apOutboxActivityCounts     map[string]prometheus.Counter
+	apInboxThrottledCounts     map[string]prometheus.Counter
 
 	anchorWriteTime                          prometheus.Histogram
 	anchorWitnessTime                        prometheus.Histogram
@@ -99,6 +107,12 @@ type PromMetrics struct {
 	anchorWriteStoreTime                     prometheus.Histogram
 	anchorWriteSignLocalWatchTime            prometheus.Histogram
 	anchorWriteResolveHostMetaLinkTime       prometheus.Histogram
+	anchorWitnessRequestCounts               *prometheus.CounterVec
+	anchorWitnessReOfferCounts               *prometheus.CounterVec
+	anchorWitnessTimeoutCounts               *prometheus.CounterVec
+	anchorWitnessOfferRejectedCounts         *prometheus.CounterVec
+	anchorWriteBatchSplitCounts              *prometheus.CounterVec
+	anchorWitnessProofTurnaroundTimes        *prometheus.HistogramVec
 
 	opqueueAddOperationTime  prometheus.Histogram
 	opqueueBatchCutTime      prometheus.Histogram
@@ -113,6 +127,11 @@ type PromMetrics struct {
 	casCacheHitCount prometheus.Counter
 	casReadTimes     map[string]prometheus.Histogram
 
+	casScrubCheckedCount   prometheus.Counter
+	casScrubCorruptedCount prometheus.Counter
+	casScrubRepairedCount  prometheus.Counter
+	casScrubRunTime        prometheus.Histogram
+
 	docCreateUpdateTime prometheus.Histogram
 	docResolveTime      prometheus.Histogram
 
@@ -199,6 +218,12 @@ func newMetrics() metrics.Metrics {
 		anchorWriteStoreTime:                         newAnchorWriteStoreTime(),
 		anchorWriteSignLocalWatchTime:                newAnchorWriteSignLocalWatchTime(),
 		anchorWriteResolveHostMetaLinkTime:           newAnchorWriteResolveHostMetaLinkTime(),
+		anchorWitnessRequestCounts:                   newAnchorWitnessRequestCounts(),
+		anchorWitnessReOfferCounts:                   newAnchorWitnessReOfferCounts(),
+		anchorWitnessTimeoutCounts:                   newAnchorWitnessTimeoutCounts(),
+		anchorWitnessOfferRejectedCounts:             newAnchorWitnessOfferRejectedCounts(),
+		anchorWriteBatchSplitCounts:                  newAnchorWriteBatchSplitCounts(),
+		anchorWitnessProofTurnaroundTimes:            newAnchorWitnessProofTurnaroundTimes(),
 		opqueueAddOperationTime:                      newOpQueueAddOperationTime(),
 		opqueueBatchCutTime:                          newOpQueueBatchCutTime(),
 		opqueueBatchRollbackTime:                     newOpQueueBatchRollbackTime(),
@@ -209,10 +234,15 @@ func newMetrics() metrics.Metrics {
 		casResolveTime:                               newCASResolveTime(),
 		casReadTimes:                                 newCASReadTimes(),
 		casCacheHitCount:                             newCASCacheHitCount(),
+		casScrubCheckedCount:                         newCASScrubCheckedCount(),
+		casScrubCorruptedCount:                       newCASScrubCorruptedCount(),
+		casScrubRepairedCount:                        newCASScrubRepairedCount(),
+		casScrubRunTime:                              newCASScrubRunTime(),
 		docCreateUpdateTime:                          newDocCreateUpdateTime(),
 		docResolveTime:                               newDocResolveTime(),
 		apInboxHandlerTimes:                          newInboxHandlerTimes(activityTypes),
 		apOutboxActivityCounts:                       newOutboxActivityCounts(activityTypes),
+		apInboxThrottledCounts:                       newInboxThrottledCounts(),
 		dbPutTimes:                                   newDBPutTime(dbTypes),
 		dbGetTimes:                                   newDBGetTime(dbTypes),
 		dbGetTagsTimes:                               newDBGetTagsTime(dbTypes),
@@ -281,11 +311,14 @@ func registerMetrics(pm *PromMetrics) { //nolint:cyclop
 		pm.opqueueAddOperationTime, pm.opqueueBatchCutTime, pm.opqueueBatchRollbackTime,
 		pm.opqueueBatchSize, pm.observerProcessAnchorTime, pm.observerProcessDIDTime,
 		pm.casWriteTime, pm.casResolveTime, pm.casCacheHitCount,
+		pm.casScrubCheckedCount, pm.casScrubCorruptedCount, pm.casScrubRepairedCount, pm.casScrubRunTime,
 		pm.docCreateUpdateTime, pm.docResolveTime,
 		pm.vctWitnessAddProofVCTNilTimes, pm.vctWitnessAddVCTimes, pm.vctWitnessAddProofTimes,
 		pm.vctWitnessAddWebFingerTimes, pm.vctWitnessVerifyVCTimes, pm.vctAddProofParseCredentialTimes,
 		pm.vctAddProofSignTimes, pm.signerSignTimes, pm.signerGetKeyTimes, pm.signerAddLinkedDataProofTimes,
 		pm.anchorWriteResolveHostMetaLinkTime,
+		pm.anchorWitnessRequestCounts, pm.anchorWitnessReOfferCounts, pm.anchorWitnessTimeoutCounts,
+		pm.anchorWitnessOfferRejectedCounts, pm.anchorWitnessProofTurnaroundTimes, pm.anchorWriteBatchSplitCounts,
 		pm.webResolverResolveDocument,
 		pm.resolverResolveDocumentLocallyTimes, pm.resolverGetAnchorOriginEndpointTimes,
 		pm.resolverResolveDocumentFromAnchorOriginTimes,
@@ -338,6 +371,10 @@ func registerMetrics(pm *PromMetrics) { //nolint:cyclop
 		prometheus.MustRegister(c)
 	}
 
+	for _, c := range pm.apInboxThrottledCounts {
+		prometheus.MustRegister(c)
+	}
+
 	for _, c := range pm.casReadTimes {
 		prometheus.MustRegister(c)
 	}
@@ -377,6 +414,13 @@ func (pm *PromMetrics) OutboxIncrementActivityCount(activityType string) {
 	}
 }
 
+// InboxIncrementThrottledCount increments the number of inbox requests that were throttled by the given limit scope.
+func (pm *PromMetrics) InboxIncrementThrottledCount(scope string) {
+	if c, ok := pm.apInboxThrottledCounts[scope]; ok {
+		c.Inc()
+	}
+}
+
 // WriteAnchorTime records the time it takes to write an anchor credential and post an 'Offer' activity.
 func (pm *PromMetrics) WriteAnchorTime(value time.Duration) {
 	pm.anchorWriteTime.Observe(value.Seconds())
@@ -468,6 +512,41 @@ func (pm *PromMetrics) WriteAnchorResolveHostMetaLinkTime(value time.Duration) {
 	logger.Debug("WriteAnchor resolve host meta link time", log.WithDuration(value))
 }
 
+// WitnessIncrementRequestCount increments the number of times that an anchor was offered to the given witness.
+func (pm *PromMetrics) WitnessIncrementRequestCount(witness string) {
+	pm.anchorWitnessRequestCounts.WithLabelValues(witness).Inc()
+}
+
+// WitnessIncrementReOfferCount increments the number of times that an anchor was re-offered to the given witness
+// because other witnesses did not return a proof in time.
+func (pm *PromMetrics) WitnessIncrementReOfferCount(witness string) {
+	pm.anchorWitnessReOfferCounts.WithLabelValues(witness).Inc()
+}
+
+// WitnessIncrementTimeoutCount increments the number of times that the given witness did not return a proof in time.
+func (pm *PromMetrics) WitnessIncrementTimeoutCount(witness string) {
+	pm.anchorWitnessTimeoutCounts.WithLabelValues(witness).Inc()
+}
+
+// WitnessIncrementOfferRejectedCount increments the number of 'Offer' activities that were rejected by this
+// witness for the given reason.
+func (pm *PromMetrics) WitnessIncrementOfferRejectedCount(reason string) {
+	pm.anchorWitnessOfferRejectedCounts.WithLabelValues(reason).Inc()
+}
+
+// WriteAnchorIncrementBatchSplitCount increments the number of times that a batch of operations was split into
+// multiple anchors because the given limit was exceeded.
+func (pm *PromMetrics) WriteAnchorIncrementBatchSplitCount(reason string) {
+	pm.anchorWriteBatchSplitCounts.WithLabelValues(reason).Inc()
+}
+
+// WitnessProofTurnaroundTime records the time between the selection of the given witness and the receipt of its proof.
+func (pm *PromMetrics) WitnessProofTurnaroundTime(witness string, value time.Duration) {
+	pm.anchorWitnessProofTurnaroundTimes.WithLabelValues(witness).Observe(value.Seconds())
+
+	logger.Debug("Witness proof turnaround time", logfields.WithWitnessURIString(witness), log.WithDuration(value))
+}
+
 // WitnessAnchorCredentialTime records the time it takes for a verifiable credential to gather proofs from all
 // required witnesses (according to witness policy). The start time is when the verifiable credential is issued
 // and the end time is the time that the witness policy is satisfied.
@@ -556,6 +635,28 @@ func (pm *PromMetrics) CASReadTime(casType string, value time.Duration) {
 	}
 }
 
+// CASScrubIncrementCheckedCount increments the number of CAS items checked by the scrubber.
+func (pm *PromMetrics) CASScrubIncrementCheckedCount() {
+	pm.casScrubCheckedCount.Inc()
+}
+
+// CASScrubIncrementCorruptedCount increments the number of corrupted CAS items detected by the scrubber.
+func (pm *PromMetrics) CASScrubIncrementCorruptedCount() {
+	pm.casScrubCorruptedCount.Inc()
+}
+
+// CASScrubIncrementRepairedCount increments the number of corrupted CAS items repaired by the scrubber.
+func (pm *PromMetrics) CASScrubIncrementRepairedCount() {
+	pm.casScrubRepairedCount.Inc()
+}
+
+// CASScrubRunTime records the time it takes for a single run of the CAS scrubber.
+func (pm *PromMetrics) CASScrubRunTime(value time.Duration) {
+	pm.casScrubRunTime.Observe(value.Seconds())
+
+	logger.Debug("CASScrubRun time", log.WithDuration(value))
+}
+
 // DocumentCreateUpdateTime records the time it takes the REST handler to process a create/update operation.
 func (pm *PromMetrics) DocumentCreateUpdateTime(value time.Duration) {
 	pm.docCreateUpdateTime.Observe(value.Seconds())
@@ -932,6 +1033,17 @@ func newCounter(subsystem, name, help string, labels prometheus.Labels) promethe
 	})
 }
 
+// newCounterVec returns a counter that's partitioned by the given label. This is used (instead of constant labels)
+// when the label values aren't known in advance.
+func newCounterVec(subsystem, name, help, label string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystem,
+		Name:      name,
+		Help:      help,
+	}, []string{label})
+}
+
 func newGauge(subsystem, name, help string, labels prometheus.Labels) prometheus.Gauge {
 	return prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace:   metrics.Namespace,
@@ -996,6 +1108,20 @@ func newOutboxActivityCounts(activityTypes []string) map[string]prometheus.Count
 	return counters
 }
 
+func newInboxThrottledCounts() map[string]prometheus.Counter {
+	counters := make(map[string]prometheus.Counter)
+
+	for _, scope := range []string{"actor", "global"} {
+		counters[scope] = newCounter(
+			metrics.ActivityPub, metrics.ApInboxThrottledCounterMetric,
+			"The number of requests posted to the inbox that were throttled by the per-actor or global rate limit.",
+			prometheus.Labels{"scope": scope},
+		)
+	}
+
+	return counters
+}
+
 func newAnchorWriteTime() prometheus.Histogram {
 	return newHistogram(
 		metrics.Anchor, metrics.AnchorWriteTimeMetric,
@@ -1119,6 +1245,57 @@ func newAnchorWriteResolveHostMetaLinkTime() prometheus.Histogram {
 	)
 }
 
+func newAnchorWitnessRequestCounts() *prometheus.CounterVec {
+	return newCounterVec(
+		metrics.Anchor, metrics.AnchorWitnessRequestCounterMetric,
+		"The number of times that an anchor was offered to a witness.",
+		witnessLabel,
+	)
+}
+
+func newAnchorWitnessReOfferCounts() *prometheus.CounterVec {
+	return newCounterVec(
+		metrics.Anchor, metrics.AnchorWitnessReOfferCounterMetric,
+		"The number of times that an anchor was re-offered to a witness because other witnesses did not return "+
+			"a proof in time.",
+		witnessLabel,
+	)
+}
+
+func newAnchorWitnessTimeoutCounts() *prometheus.CounterVec {
+	return newCounterVec(
+		metrics.Anchor, metrics.AnchorWitnessTimeoutCounterMetric,
+		"The number of times that a witness did not return a proof within the maximum witness delay.",
+		witnessLabel,
+	)
+}
+
+func newAnchorWitnessOfferRejectedCounts() *prometheus.CounterVec {
+	return newCounterVec(
+		metrics.Anchor, metrics.AnchorWitnessOfferRejectedCounterMetric,
+		"The number of 'Offer' activities that were rejected by admission control (quota, anchor size or origin).",
+		reasonLabel,
+	)
+}
+
+func newAnchorWriteBatchSplitCounts() *prometheus.CounterVec {
+	return newCounterVec(
+		metrics.Anchor, metrics.AnchorWriteBatchSplitCounterMetric,
+		"The number of times that a batch of operations was split into multiple anchors (by the limit that was exceeded).",
+		reasonLabel,
+	)
+}
+
+func newAnchorWitnessProofTurnaroundTimes() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.Anchor,
+		Name:      metrics.AnchorWitnessProofTurnaroundTimeMetric,
+		Help:      "The time (in seconds) between the selection of a witness and the receipt of its proof.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+	}, []string{witnessLabel})
+}
+
 func newOpQueueAddOperationTime() prometheus.Histogram {
 	return newHistogram(
 		metrics.OperationQueue, metrics.OpQueueAddOperationTimeMetric,
@@ -1193,10 +1370,42 @@ func newCASCacheHitCount() prometheus.Counter {
 	)
 }
 
+func newCASScrubCheckedCount() prometheus.Counter {
+	return newCounter(
+		metrics.Cas, metrics.CasScrubCheckedCountMetric,
+		"The number of CAS documents whose content was verified by the scrubber.",
+		nil,
+	)
+}
+
+func newCASScrubCorruptedCount() prometheus.Counter {
+	return newCounter(
+		metrics.Cas, metrics.CasScrubCorruptedCountMetric,
+		"The number of CAS documents whose content was found by the scrubber to not match the content address.",
+		nil,
+	)
+}
+
+func newCASScrubRepairedCount() prometheus.Counter {
+	return newCounter(
+		metrics.Cas, metrics.CasScrubRepairedCountMetric,
+		"The number of corrupted CAS documents that were re-fetched from IPFS or a peer by the scrubber.",
+		nil,
+	)
+}
+
+func newCASScrubRunTime() prometheus.Histogram {
+	return newHistogram(
+		metrics.Cas, metrics.CasScrubRunTimeMetric,
+		"The time (in seconds) that it takes for a single run of the CAS scrubber.",
+		nil,
+	)
+}
+
 func newCASReadTimes() map[string]prometheus.Histogram {
 	times := make(map[string]prometheus.Histogram)
 
-	for _, casType := range []string{"local", "ipfs"} {
+	for _, casType := range []string{"local", "ipfs", "filesystem", "s3"} {
 		times[casType] = newHistogram(
 			metrics.Cas, metrics.CasReadTimeMetric,
 			"The time (in seconds) that it takes to read a document from the CAS storage.",
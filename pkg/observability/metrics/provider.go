@@ -26,6 +26,7 @@ const (
 	ApResolveInboxesTimeMetric    = "outbox_resolve_inboxes_seconds"
 	ApInboxHandlerTimeMetric      = "inbox_handler_seconds"
 	ApOutboxActivityCounterMetric = "outbox_count"
+	ApInboxThrottledCounterMetric = "inbox_throttled_count"
 
 	// Anchor Anchor.
 	Anchor                                         = "anchor"
@@ -44,6 +45,12 @@ const (
 	AnchorWriteSignLocalWitnessLogTimeMetric       = "write_sign_local_witness_log_seconds"
 	AnchorWriteSignLocalWatchTimeMetric            = "write_sign_local_watch_seconds"
 	AnchorWriteResolveHostMetaLinkTimeMetric       = "write_resolve_host_meta_link_seconds"
+	AnchorWitnessRequestCounterMetric              = "witness_request_count"
+	AnchorWitnessReOfferCounterMetric              = "witness_reoffer_count"
+	AnchorWitnessTimeoutCounterMetric              = "witness_timeout_count"
+	AnchorWitnessOfferRejectedCounterMetric        = "witness_offer_rejected_count"
+	AnchorWriteBatchSplitCounterMetric             = "write_batch_split_count"
+	AnchorWitnessProofTurnaroundTimeMetric         = "witness_proof_turnaround_seconds"
 
 	// OperationQueue Operation queue.
 	OperationQueue                 = "opqueue"
@@ -64,6 +71,11 @@ const (
 	CasCacheHitCountMetric = "cache_hit_count"
 	CasReadTimeMetric      = "read_seconds"
 
+	CasScrubCheckedCountMetric   = "scrub_checked_count"
+	CasScrubCorruptedCountMetric = "scrub_corrupted_count"
+	CasScrubRepairedCountMetric  = "scrub_repaired_count"
+	CasScrubRunTimeMetric        = "scrub_run_seconds"
+
 	// Document handler.
 	Document                  = "document"
 	DocCreateUpdateTimeMetric = "create_update_seconds"
@@ -169,6 +181,10 @@ type Metrics interface {
 	CASIncrementCacheHitCount()
 	CASWriteTime(value time.Duration)
 	CASReadTime(casType string, value time.Duration)
+	CASScrubIncrementCheckedCount()
+	CASScrubIncrementCorruptedCount()
+	CASScrubIncrementRepairedCount()
+	CASScrubRunTime(value time.Duration)
 	PutPublishedOperations(duration time.Duration)
 	GetPublishedOperations(duration time.Duration)
 	CASResolveTime(value time.Duration)
@@ -189,6 +205,7 @@ type Metrics interface {
 	ProcessAnchorTime(value time.Duration)
 	ProcessDIDTime(value time.Duration)
 	InboxHandlerTime(activityType string, value time.Duration)
+	InboxIncrementThrottledCount(scope string)
 	OutboxPostTime(value time.Duration)
 	OutboxResolveInboxesTime(value time.Duration)
 	OutboxIncrementActivityCount(activityType string)
@@ -206,6 +223,12 @@ type Metrics interface {
 	WriteAnchorSignLocalWitnessLogTime(value time.Duration)
 	WriteAnchorSignLocalWatchTime(value time.Duration)
 	WriteAnchorResolveHostMetaLinkTime(value time.Duration)
+	WriteAnchorIncrementBatchSplitCount(reason string)
+	WitnessIncrementRequestCount(witness string)
+	WitnessIncrementReOfferCount(witness string)
+	WitnessIncrementTimeoutCount(witness string)
+	WitnessIncrementOfferRejectedCount(reason string)
+	WitnessProofTurnaroundTime(witness string, value time.Duration)
 	AddOperationTime(value time.Duration)
 	BatchCutTime(value time.Duration)
 	BatchRollbackTime(value time.Duration)
@@ -20,13 +20,19 @@ import (
 	"github.com/trustbloc/orb/pkg/store"
 )
 
-const nameSpace = "anchor-link"
+const (
+	nameSpace = "anchor-link"
+
+	// anchorTagName is the tag used for querying all anchor links. It's the same as the name of the anchor
+	// field in the stored anchor link.
+	anchorTagName = "anchor"
+)
 
 var logger = log.New("anchor-link-store")
 
 // New returns new instance of anchor event store.
 func New(p storage.Provider) (*Store, error) {
-	s, err := store.Open(p, nameSpace)
+	s, err := store.Open(p, nameSpace, store.NewTagGroup(anchorTagName))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open vc store: %w", err)
 	}
@@ -58,7 +64,7 @@ func (s *Store) Put(anchorLink *linkset.Link) error {
 
 	logger.Debug("Storing anchor link", logfields.WithAnchorLink(anchorLinkBytes))
 
-	if e := s.store.Put(anchorLink.Anchor().String(), anchorLinkBytes); e != nil {
+	if e := s.store.Put(anchorLink.Anchor().String(), anchorLinkBytes, storage.Tag{Name: anchorTagName}); e != nil {
 		return orberrors.NewTransient(fmt.Errorf("failed to put anchor link: %w", e))
 	}
 
@@ -86,6 +92,46 @@ func (s *Store) Get(id string) (*linkset.Link, error) {
 	return anchorLink, nil
 }
 
+// ForEach invokes the given function for each anchor link in the store. Iteration stops if the function
+// returns an error.
+func (s *Store) ForEach(fn func(anchorLink *linkset.Link) error) error {
+	iter, err := s.store.Query(anchorTagName)
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to query anchor links: %w", err))
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+	}
+
+	for ok {
+		anchorLinkBytes, e := iter.Value()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get iterator value: %w", e))
+		}
+
+		anchorLink := &linkset.Link{}
+
+		if e = s.unmarshal(anchorLinkBytes, &anchorLink); e != nil {
+			return fmt.Errorf("unmarshal anchor link: %w", e)
+		}
+
+		if err = fn(anchorLink); err != nil {
+			return err
+		}
+
+		ok, err = iter.Next()
+		if err != nil {
+			return orberrors.NewTransient(fmt.Errorf("iterator error: %w", err))
+		}
+	}
+
+	return nil
+}
+
 // Delete deletes anchor event by id.
 func (s *Store) Delete(id string) error {
 	if err := s.store.Delete(id); err != nil {
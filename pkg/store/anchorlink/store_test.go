@@ -189,3 +189,51 @@ func TestStore_Delete(t *testing.T) {
 		require.Contains(t, err.Error(), "error delete")
 	})
 }
+
+func TestStore_ForEach(t *testing.T) {
+	anchorIndexURL2 := testutil.MustParseURL("hl:uEiALYp_C4wk2WegpfnCSoSTBdKZ1MVdDadn4rdmZl5GKzQ")
+
+	t.Run("test success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(linkset.NewLink(anchorIndexURL, nil, nil, nil, nil, nil)))
+		require.NoError(t, s.Put(linkset.NewLink(anchorIndexURL2, nil, nil, nil, nil, nil)))
+
+		var anchors []string
+
+		err = s.ForEach(func(anchorLink *linkset.Link) error {
+			anchors = append(anchors, anchorLink.Anchor().String())
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{anchorIndexURL.String(), anchorIndexURL2.String()}, anchors)
+	})
+
+	t.Run("test error from function", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(linkset.NewLink(anchorIndexURL, nil, nil, nil, nil, nil)))
+
+		errExpected := errors.New("injected error")
+
+		err = s.ForEach(func(*linkset.Link) error { return errExpected })
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("test error from store query", func(t *testing.T) {
+		storeProvider := &mockstore.Provider{OpenStoreReturn: &mockstore.Store{
+			ErrQuery: fmt.Errorf("error query"),
+		}}
+
+		s, err := New(storeProvider)
+		require.NoError(t, err)
+
+		err = s.ForEach(func(*linkset.Link) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error query")
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
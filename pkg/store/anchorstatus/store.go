@@ -218,14 +218,36 @@ func (s *Store) getAnchorStatusWithTags(anchorID string, status proof.AnchorInde
 	}, tags
 }
 
-func (s *Store) deleteInProcessStatus(anchorID string) error { //nolint:cyclop
+// Reopen replaces the 'completed' status of an anchor with an 'in-process' status so that the witness policy
+// of the anchor is evaluated again, for example after the proof of a witness has been revoked.
+func (s *Store) Reopen(anchorID string) error {
+	err := s.deleteStatus(anchorID, proof.AnchorIndexStatusCompleted)
+	if err != nil {
+		return orberrors.NewTransientf("delete completed status for anchor[%s]: %w", anchorID, err)
+	}
+
+	err = s.deleteInProcessStatus(anchorID)
+	if err != nil {
+		return orberrors.NewTransientf("delete in-process status for anchor[%s]: %w", anchorID, err)
+	}
+
+	logger.Info("Reopening anchor", logfields.WithAnchorURIString(anchorID))
+
+	return s.AddStatus(anchorID, proof.AnchorIndexStatusInProcess)
+}
+
+func (s *Store) deleteInProcessStatus(anchorID string) error {
+	return s.deleteStatus(anchorID, proof.AnchorIndexStatusInProcess)
+}
+
+func (s *Store) deleteStatus(anchorID string, status proof.AnchorIndexStatus) error { //nolint:cyclop
 	var err error
 
 	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
 
 	query := fmt.Sprintf("%s:%s&&%s:%s",
 		anchorIDTagName, anchorIDEncoded,
-		statusTagName, proof.AnchorIndexStatusInProcess,
+		statusTagName, status,
 	)
 
 	iter, err := s.store.Query(query)
@@ -242,7 +264,7 @@ func (s *Store) deleteInProcessStatus(anchorID string) error { //nolint:cyclop
 	}
 
 	if !ok {
-		// No in-process anchors.
+		// No statuses to delete.
 		return nil
 	}
 
@@ -254,9 +276,7 @@ func (s *Store) deleteInProcessStatus(anchorID string) error { //nolint:cyclop
 			return fmt.Errorf("failed to get status for anchor[%s]: %w", anchorID, e)
 		}
 
-		status := &anchorStatus{}
-
-		e = s.unmarshal(statusBytes, status)
+		e = s.unmarshal(statusBytes, &anchorStatus{})
 		if e != nil {
 			return fmt.Errorf("unmarshal anchor status for anchor[%s]: %w", anchorID, e)
 		}
@@ -283,18 +303,124 @@ func (s *Store) deleteInProcessStatus(anchorID string) error { //nolint:cyclop
 
 		err = s.store.Batch(operations)
 		if err != nil {
-			return fmt.Errorf("failed to delete in process status for anchor [%s]: %w", anchorID, err)
+			return fmt.Errorf("failed to delete %s status for anchor [%s]: %w", status, anchorID, err)
 		}
 
-		logger.Debug("Successfully deleted in-process status data for anchor.",
+		logger.Debug("Successfully deleted status data for anchor.", logfields.WithStatus(string(status)),
 			logfields.WithTotal(len(operations)), logfields.WithAnchorURIString(anchorID))
 	}
 
 	return nil
 }
 
+// StatusInfo contains the processing status of an anchor.
+type StatusInfo struct {
+	Status proof.AnchorIndexStatus `json:"status"`
+	// NextCheckTime is the (approximate) time at which the witness policy of an in-process anchor will be
+	// re-evaluated. This field is zero if the anchor has completed processing.
+	NextCheckTime time.Time `json:"nextCheckTime,omitempty"`
+	// ExpiryTime is the time after which an in-process anchor will no longer be monitored.
+	ExpiryTime time.Time `json:"expiryTime"`
+}
+
 // GetStatus retrieves proof collection status for the given verifiable credential.
 func (s *Store) GetStatus(anchorID string) (proof.AnchorIndexStatus, error) {
+	status, err := s.getStatus(anchorID)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debug("Status for anchor", logfields.WithAnchorEventURIString(anchorID), logfields.WithStatus(string(status.Status)))
+
+	return status.Status, nil
+}
+
+// GetStatusInfo returns the processing status of the given anchor along with the time at which the
+// anchor will next be checked.
+func (s *Store) GetStatusInfo(anchorID string) (*StatusInfo, error) {
+	status, err := s.getStatus(anchorID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &StatusInfo{
+		Status:     status.Status,
+		ExpiryTime: time.Unix(status.ExpiryTime, 0),
+	}
+
+	if status.Status == proof.AnchorIndexStatusCompleted {
+		return info, nil
+	}
+
+	info.NextCheckTime = time.Unix(status.StatusCheckTime, 0)
+
+	// In-process anchors are checked by a periodic task, so if the check time has passed then the anchor
+	// will be checked during the next run of the task.
+	if now := time.Now(); info.NextCheckTime.Before(now) {
+		info.NextCheckTime = now.Add(s.monitoringInterval)
+	}
+
+	return info, nil
+}
+
+// GetInProcessAnchors returns the IDs of the anchors that are still in process, i.e. whose witness
+// policy hasn't been satisfied.
+func (s *Store) GetInProcessAnchors() ([]string, error) {
+	query := fmt.Sprintf("%s:%s", statusTagName, proof.AnchorIndexStatusInProcess)
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query in-process anchors: %w", err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("iterator error for in-process anchors: %w", err)
+	}
+
+	var anchorIDs []string
+
+	// An anchor may have more than one in-process status record.
+	added := make(map[string]bool)
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get iterator value for in-process anchors: %w", e)
+		}
+
+		status := &anchorStatus{}
+
+		e = s.unmarshal(value, status)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal status: %w", e)
+		}
+
+		if !added[status.AnchorID] {
+			anchorID, e := base64.RawURLEncoding.DecodeString(status.AnchorID)
+			if e != nil {
+				return nil, fmt.Errorf("decode anchor ID [%s]: %w", status.AnchorID, e)
+			}
+
+			added[status.AnchorID] = true
+
+			anchorIDs = append(anchorIDs, string(anchorID))
+		}
+
+		ok, e = iter.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("iterator error for in-process anchors: %w", e)
+		}
+	}
+
+	return anchorIDs, nil
+}
+
+// getStatus returns the 'completed' status record of the anchor, if one exists. Otherwise the last
+// status record is returned.
+func (s *Store) getStatus(anchorID string) (*anchorStatus, error) {
 	var err error
 
 	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
@@ -303,7 +429,7 @@ func (s *Store) GetStatus(anchorID string) (proof.AnchorIndexStatus, error) {
 
 	iter, err := s.store.Query(query)
 	if err != nil {
-		return "", orberrors.NewTransient(fmt.Errorf("failed to get statuses for anchor [%s] query[%s]: %w",
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to get statuses for anchor [%s] query[%s]: %w",
 			anchorID, query, err))
 	}
 
@@ -311,43 +437,41 @@ func (s *Store) GetStatus(anchorID string) (proof.AnchorIndexStatus, error) {
 
 	ok, err := iter.Next()
 	if err != nil {
-		return "", orberrors.NewTransient(fmt.Errorf("iterator error for anchor [%s] statuses: %w", anchorID, err))
+		return nil, orberrors.NewTransient(fmt.Errorf("iterator error for anchor [%s] statuses: %w", anchorID, err))
 	}
 
 	if !ok {
-		return "", fmt.Errorf("status not found for anchor [%s]: %w", anchorID, orberrors.ErrContentNotFound)
+		return nil, fmt.Errorf("status not found for anchor [%s]: %w", anchorID, orberrors.ErrContentNotFound)
 	}
 
-	var status proof.AnchorIndexStatus
+	var status *anchorStatus
 
 	for ok {
 		value, err := iter.Value()
 		if err != nil {
-			return "", orberrors.NewTransient(fmt.Errorf("failed to get iterator value for anchor event[%s]: %w",
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get iterator value for anchor event[%s]: %w",
 				anchorID, err))
 		}
 
-		var anchrStatus anchorStatus
+		anchrStatus := &anchorStatus{}
 
-		err = s.unmarshal(value, &anchrStatus)
+		err = s.unmarshal(value, anchrStatus)
 		if err != nil {
-			return "", fmt.Errorf("unmarshal status: %w", err)
+			return nil, fmt.Errorf("unmarshal status: %w", err)
 		}
 
-		status = anchrStatus.Status
+		status = anchrStatus
 
 		if anchrStatus.Status == proof.AnchorIndexStatusCompleted {
-			return proof.AnchorIndexStatusCompleted, nil
+			return anchrStatus, nil
 		}
 
 		ok, err = iter.Next()
 		if err != nil {
-			return "", orberrors.NewTransient(fmt.Errorf("iterator error for anchor event[%s]: %w", anchorID, err))
+			return nil, orberrors.NewTransient(fmt.Errorf("iterator error for anchor event[%s]: %w", anchorID, err))
 		}
 	}
 
-	logger.Debug("Status for anchor", logfields.WithAnchorEventURIString(anchorID), logfields.WithStatus(string(status)))
-
 	return status, nil
 }
 
@@ -240,6 +240,146 @@ func TestStore_Get(t *testing.T) {
 	})
 }
 
+func TestStore_GetStatusInfo(t *testing.T) {
+	taskMgr := testutil.GetTaskMgr(t)
+
+	expiryService := expiry.NewService(taskMgr, time.Second)
+
+	t.Run("success - in process", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime,
+			WithCheckStatusAfterTime(time.Minute))
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.AnchorIndexStatusInProcess))
+
+		info, err := s.GetStatusInfo(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.AnchorIndexStatusInProcess, info.Status)
+		require.True(t, info.NextCheckTime.After(time.Now().Add(30*time.Second)))
+		require.True(t, info.ExpiryTime.After(info.NextCheckTime))
+	})
+
+	t.Run("success - in process, check time passed", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime,
+			WithCheckStatusAfterTime(-time.Minute), WithMonitoringInterval(5*time.Second))
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.AnchorIndexStatusInProcess))
+
+		info, err := s.GetStatusInfo(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.AnchorIndexStatusInProcess, info.Status)
+		require.True(t, info.NextCheckTime.After(time.Now()))
+		require.True(t, info.NextCheckTime.Before(time.Now().Add(6*time.Second)))
+	})
+
+	t.Run("success - completed", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.AnchorIndexStatusInProcess))
+		require.NoError(t, s.AddStatus(vcID, proof.AnchorIndexStatusCompleted))
+
+		info, err := s.GetStatusInfo(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.AnchorIndexStatusCompleted, info.Status)
+		require.True(t, info.NextCheckTime.IsZero())
+	})
+
+	t.Run("error - not found", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		info, err := s.GetStatusInfo(vcID)
+		require.Error(t, err)
+		require.Nil(t, info)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+	})
+}
+
+func TestStore_Reopen(t *testing.T) {
+	taskMgr := testutil.GetTaskMgr(t)
+
+	expiryService := expiry.NewService(taskMgr, time.Second)
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus(vcID, proof.AnchorIndexStatusInProcess))
+		require.NoError(t, s.AddStatus(vcID, proof.AnchorIndexStatusCompleted))
+
+		require.NoError(t, s.Reopen(vcID))
+
+		status, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.AnchorIndexStatusInProcess, status)
+	})
+
+	t.Run("success - no status", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Reopen(vcID))
+
+		status, err := s.GetStatus(vcID)
+		require.NoError(t, err)
+		require.Equal(t, proof.AnchorIndexStatusInProcess, status)
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		err = s.Reopen(vcID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "query error")
+	})
+}
+
+func TestStore_GetInProcessAnchors(t *testing.T) {
+	taskMgr := testutil.GetTaskMgr(t)
+
+	expiryService := expiry.NewService(taskMgr, time.Second)
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddStatus("vc1", proof.AnchorIndexStatusInProcess))
+		require.NoError(t, s.AddStatus("vc1", proof.AnchorIndexStatusInProcess))
+		require.NoError(t, s.AddStatus("vc2", proof.AnchorIndexStatusInProcess))
+		require.NoError(t, s.AddStatus("vc2", proof.AnchorIndexStatusCompleted))
+
+		anchorIDs, err := s.GetInProcessAnchors()
+		require.NoError(t, err)
+		require.Equal(t, []string{"vc1"}, anchorIDs)
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, taskMgr, expiryService, maxWitnessDelayTime)
+		require.NoError(t, err)
+
+		_, err = s.GetInProcessAnchors()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "query error")
+	})
+}
+
 func TestStore_CheckInProcessAnchors(t *testing.T) {
 	taskMgr := testutil.GetTaskMgr(t)
 
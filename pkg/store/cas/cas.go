@@ -9,6 +9,8 @@ package cas
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/bluele/gcache"
@@ -20,6 +22,8 @@ import (
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/multihash"
+	"github.com/trustbloc/orb/pkg/store"
 )
 
 var logger = log.New("cas-store")
@@ -28,6 +32,11 @@ const (
 	dbName           = "cas"
 	defaultCacheSize = 1000
 	casType          = "local"
+
+	// createdTimeTagName is the tag under which the (Unix) time that the content was written is stored.
+	// Content that was written before this tag was introduced doesn't have the tag (and is therefore not
+	// considered by the garbage collector or the scrubber) until it's tagged the first time that it's read.
+	createdTimeTagName = "createdTime"
 )
 
 type metricsProvider interface {
@@ -58,6 +67,11 @@ func New(provider ariesstorage.Provider, casLink string, ipfsClient *ipfs.Client
 		return nil, fmt.Errorf("failed to open store in underlying storage provider: %w", err)
 	}
 
+	err = provider.SetStoreConfig(dbName, ariesstorage.StoreConfiguration{TagNames: []string{createdTimeTagName}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set store configuration in underlying storage provider: %w", err)
+	}
+
 	if cacheSize == 0 {
 		cacheSize = defaultCacheSize
 	}
@@ -96,15 +110,21 @@ func (p *CAS) Write(content []byte) (string, error) {
 
 // WriteWithCIDFormat writes the given content to the underlying local CAS provider (and IPFS if configured) using the
 // CID format specified by opts.
-// Returns the address of the content.
-// TODO (#418): Support creating IPFS-compatible CIDs when content is > 256KB.
-// TODO (#443): Support v1 CID formats (different multibases and multicodecs) other than just the IPFS default.
+// Returns the address of the content. Content that's larger than a single UnixFS chunk (256KB) is addressed by the
+// root of its UnixFS DAG so that the address is the same as the address of the content in IPFS, unless the flat hash
+// option or CID version 0 is specified (in which case the content is stored under the hash of the entire content, as
+// it was by earlier versions).
 func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFormatOption) (string, error) {
 	if len(content) == 0 {
 		return "", errors.New("empty content")
 	}
 
-	resourceHash, err := p.hl.CreateResourceHash(content)
+	options, err := extendedcasclient.GetCIDFormatOptions(opts...)
+	if err != nil {
+		return "", err
+	}
+
+	resourceHash, err := options.ResourceHash(content)
 	if err != nil {
 		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
 	}
@@ -112,7 +132,9 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 	logger.Debug("Writing to CAS store. Content (base64-encoded)",
 		logfields.WithHash(resourceHash), logfields.WithCASData(content))
 
-	err = p.cas.Put(resourceHash, content)
+	err = p.cas.Put(resourceHash, content,
+		ariesstorage.Tag{Name: createdTimeTagName, Value: fmt.Sprintf("%d", time.Now().Unix())},
+	)
 	if err != nil {
 		return "", orberrors.NewTransient(fmt.Errorf("failed to put content into underlying storage provider: %w", err))
 	}
@@ -143,7 +165,14 @@ func (p *CAS) WriteWithCIDFormat(content []byte, opts ...extendedcasclient.CIDFo
 		return "", fmt.Errorf("failed to create resource hash from content: %w", err)
 	}
 
-	return hashlink.GetHashLink(resourceHash, metadata), nil
+	// The content is stored (and linked) using the base64url-encoded resource hash, but the resource hash in
+	// the hashlink may be encoded using a different multibase.
+	formattedResourceHash, err := options.FormatResourceHash(resourceHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to format resource hash: %w", err)
+	}
+
+	return hashlink.GetHashLink(formattedResourceHash, metadata), nil
 }
 
 // GetPrimaryWriterType returns primary writer type.
@@ -151,9 +180,15 @@ func (p *CAS) GetPrimaryWriterType() string {
 	return "local"
 }
 
-// Read reads the content of the given address from the underlying local CAS provider.
+// Read reads the content of the given address from the underlying local CAS provider. The address may either be
+// a resource hash or a CID (e.g. the IPFS CID of content that spans multiple chunks).
 // Returns the content at the given address.
 func (p *CAS) Read(address string) ([]byte, error) {
+	address, err := multihash.CIDOrMultihashToMultihash(address)
+	if err != nil {
+		return nil, err
+	}
+
 	if p.cache.Has(address) {
 		p.metrics.CASIncrementCacheHitCount()
 	}
@@ -180,5 +215,235 @@ func (p *CAS) get(address string) ([]byte, error) {
 		return nil, orberrors.NewTransient(fmt.Errorf("failed to get content from the local CAS provider: %w", err))
 	}
 
+	p.ensureCreatedTimeTag(address, content)
+
 	return content, nil
 }
+
+// ensureCreatedTimeTag tags content that was written before the created time tag was introduced so that
+// the content is considered by the garbage collector and the scrubber. The created time is set to the current
+// time since the actual time that the content was written is unknown.
+func (p *CAS) ensureCreatedTimeTag(address string, content []byte) {
+	tags, err := p.cas.GetTags(address)
+	if err != nil {
+		logger.Warn("Error getting tags of content", logfields.WithHash(address), log.WithError(err))
+
+		return
+	}
+
+	for _, tag := range tags {
+		if tag.Name == createdTimeTagName {
+			return
+		}
+	}
+
+	logger.Debug("Adding created time tag to content", logfields.WithHash(address))
+
+	err = p.cas.Put(address, content,
+		ariesstorage.Tag{Name: createdTimeTagName, Value: fmt.Sprintf("%d", time.Now().Unix())},
+	)
+	if err != nil {
+		logger.Warn("Error adding created time tag to content", logfields.WithHash(address), log.WithError(err))
+	}
+}
+
+// QueryCreatedBefore invokes the given function with the resource hash of each item of content that was written
+// (or last re-written) to the underlying local CAS provider before the given time. Iteration stops if the
+// function returns an error.
+func (p *CAS) QueryCreatedBefore(t time.Time, fn func(resourceHash string) error) error {
+	return p.queryByCreatedTime("<", t.Unix(), false,
+		func(resourceHash string, _ []byte, _ int64) error {
+			return fn(resourceHash)
+		},
+	)
+}
+
+// ForEachCreatedSince invokes the given function with the resource hash, the stored content and the created (Unix)
+// time of each item of content that was written to the underlying local CAS provider at or after the given (Unix)
+// time. Items are returned in ascending order of created time. Iteration stops if the function returns an error.
+func (p *CAS) ForEachCreatedSince(since int64,
+	fn func(resourceHash string, content []byte, createdTime int64) error,
+) error {
+	return p.queryByCreatedTime(">=", since, true, fn)
+}
+
+// queryByCreatedTime invokes the given function for each item of content whose created time satisfies the given
+// comparison, in ascending order of created time. The content is only provided if withContent is true.
+// If the underlying provider doesn't support range queries with a sort order (e.g. the in-memory provider) then
+// all tagged content is scanned instead.
+func (p *CAS) queryByCreatedTime(operator string, value int64, withContent bool,
+	fn func(resourceHash string, content []byte, createdTime int64) error,
+) error {
+	query := fmt.Sprintf("%s%s%d", createdTimeTagName, operator, value)
+
+	iterator, err := p.cas.Query(query,
+		ariesstorage.WithSortOrder(&ariesstorage.SortOptions{
+			Order:   ariesstorage.SortAscending,
+			TagName: createdTimeTagName,
+		}),
+	)
+	if err != nil {
+		logger.Debug("Sorted range query failed. Scanning all tagged content.",
+			logfields.WithQuery(query), log.WithError(err))
+
+		return p.scanByCreatedTime(createdTimeMatcher(operator, value), withContent, fn)
+	}
+
+	defer store.CloseIterator(iterator)
+
+	for {
+		more, e := iterator.Next()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get next value from iterator: %w", e))
+		}
+
+		if !more {
+			return nil
+		}
+
+		resourceHash, e := iterator.Key()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get key from iterator: %w", e))
+		}
+
+		var content []byte
+
+		if withContent {
+			content, e = iterator.Value()
+			if e != nil {
+				return orberrors.NewTransient(fmt.Errorf("failed to get value from iterator: %w", e))
+			}
+		}
+
+		tags, e := iterator.Tags()
+		if e != nil {
+			return orberrors.NewTransient(fmt.Errorf("failed to get tags from iterator: %w", e))
+		}
+
+		if err = fn(resourceHash, content, createdTimeFromTags(tags)); err != nil {
+			return err
+		}
+	}
+}
+
+type createdEntry struct {
+	resourceHash string
+	createdTime  int64
+}
+
+// scanByCreatedTime queries all content that has a created time tag and invokes the given function for the
+// content that matches, in ascending order of created time. Only the resource hashes are held in memory; the
+// content is loaded for each item just before the function is invoked.
+func (p *CAS) scanByCreatedTime(matches func(createdTime int64) bool, withContent bool,
+	fn func(resourceHash string, content []byte, createdTime int64) error,
+) error {
+	entries, err := p.queryCreatedEntries(matches)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].createdTime < entries[j].createdTime
+	})
+
+	for _, entry := range entries {
+		var content []byte
+
+		if withContent {
+			content, err = p.get(entry.resourceHash)
+			if err != nil {
+				if errors.Is(err, orberrors.ErrContentNotFound) {
+					// The content was deleted after the query.
+					continue
+				}
+
+				return err
+			}
+		}
+
+		if err = fn(entry.resourceHash, content, entry.createdTime); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *CAS) queryCreatedEntries(matches func(createdTime int64) bool) ([]*createdEntry, error) {
+	iterator, err := p.cas.Query(createdTimeTagName)
+	if err != nil {
+		return nil, orberrors.NewTransient(fmt.Errorf("failed to query the local CAS provider: %w", err))
+	}
+
+	defer store.CloseIterator(iterator)
+
+	var entries []*createdEntry
+
+	for {
+		more, e := iterator.Next()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get next value from iterator: %w", e))
+		}
+
+		if !more {
+			return entries, nil
+		}
+
+		tags, e := iterator.Tags()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get tags from iterator: %w", e))
+		}
+
+		createdTime := createdTimeFromTags(tags)
+
+		if !matches(createdTime) {
+			continue
+		}
+
+		resourceHash, e := iterator.Key()
+		if e != nil {
+			return nil, orberrors.NewTransient(fmt.Errorf("failed to get key from iterator: %w", e))
+		}
+
+		entries = append(entries, &createdEntry{resourceHash: resourceHash, createdTime: createdTime})
+	}
+}
+
+func createdTimeMatcher(operator string, value int64) func(createdTime int64) bool {
+	if operator == "<" {
+		return func(createdTime int64) bool { return createdTime < value }
+	}
+
+	return func(createdTime int64) bool { return createdTime >= value }
+}
+
+// Delete deletes the content at the given resource hash from the underlying local CAS provider (and the cache).
+// Content that was replicated in IPFS isn't deleted from IPFS.
+func (p *CAS) Delete(resourceHash string) error {
+	p.cache.Remove(resourceHash)
+
+	if err := p.cas.Delete(resourceHash); err != nil {
+		return orberrors.NewTransient(fmt.Errorf("failed to delete content from the local CAS provider: %w", err))
+	}
+
+	logger.Debug("Deleted content from CAS store", logfields.WithHash(resourceHash))
+
+	return nil
+}
+
+func createdTimeFromTags(tags []ariesstorage.Tag) int64 {
+	for _, tag := range tags {
+		if tag.Name == createdTimeTagName {
+			createdTime, err := strconv.ParseInt(tag.Value, 10, 64)
+			if err != nil {
+				logger.Warn("Invalid created time tag", logfields.WithValue(tag.Value), log.WithError(err))
+
+				return 0
+			}
+
+			return createdTime
+		}
+	}
+
+	return 0
+}
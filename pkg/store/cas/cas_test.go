@@ -7,11 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package cas_test
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	"github.com/hyperledger/aries-framework-go-ext/component/storage/mongodb"
 	ariesmemstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mem"
 	ariesmockstorage "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
 	ariesstorage "github.com/hyperledger/aries-framework-go/spi/storage"
@@ -22,9 +24,12 @@ import (
 
 	"github.com/trustbloc/orb/pkg/cas/extendedcasclient"
 	"github.com/trustbloc/orb/pkg/cas/ipfs"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/hashlink"
+	"github.com/trustbloc/orb/pkg/internal/testutil/mongodbtestutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
+	"github.com/trustbloc/orb/pkg/multihash"
 	localcas "github.com/trustbloc/orb/pkg/store/cas"
 )
 
@@ -220,3 +225,275 @@ func startIPFSDockerContainer(t *testing.T) (*dctest.Pool, *dctest.Resource) {
 
 	return pool, ipfsResource
 }
+
+func TestProvider_Write_Read_MultipleChunks(t *testing.T) {
+	provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	content := bytes.Repeat([]byte("content"), unixfs.ChunkSize)
+
+	hl, err := provider.Write(content)
+	require.NoError(t, err)
+
+	rh, err := hashlink.GetResourceHashFromHashLink(hl)
+	require.NoError(t, err)
+
+	// The resource hash is the hash of the root of the UnixFS DAG (the same as in IPFS).
+	cid, err := unixfs.CID(content)
+	require.NoError(t, err)
+
+	cidFromRH, err := multihash.ToV1DagPBCID(rh)
+	require.NoError(t, err)
+	require.Equal(t, cid.String(), cidFromRH)
+
+	// The content may be read using either the resource hash or the CID.
+	for _, address := range []string{rh, cid.String()} {
+		c, e := provider.Read(address)
+		require.NoError(t, e)
+		require.Equal(t, content, c)
+	}
+
+	// Content may also be stored under the flat hash of the content (as it was by earlier versions).
+	hl, err = provider.WriteWithCIDFormat(content, extendedcasclient.WithFlatHash())
+	require.NoError(t, err)
+
+	flatHash, err := hashlink.New().CreateResourceHash(content)
+	require.NoError(t, err)
+
+	rh, err = hashlink.GetResourceHashFromHashLink(hl)
+	require.NoError(t, err)
+	require.Equal(t, flatHash, rh)
+
+	c, err := provider.Read(flatHash)
+	require.NoError(t, err)
+	require.Equal(t, content, c)
+}
+
+func TestProvider_WriteWithCIDFormat(t *testing.T) {
+	provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	t.Run("Success - base32 multibase", func(t *testing.T) {
+		hl, err := provider.WriteWithCIDFormat([]byte("content"),
+			extendedcasclient.WithMultibase(extendedcasclient.MultibaseBase32))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+		require.Equal(t, "bciqo24acwq46tleel4rdk7mcfowbirdtb663malnh3eugiuxxhwj64y", rh)
+
+		// The content is stored under the base64url-encoded resource hash, but it may be read using any multibase.
+		for _, address := range []string{rh, "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"} {
+			c, e := provider.Read(address)
+			require.NoError(t, e)
+			require.Equal(t, "content", string(c))
+		}
+	})
+
+	t.Run("Unsupported multibase", func(t *testing.T) {
+		hl, err := provider.WriteWithCIDFormat([]byte("content"), extendedcasclient.WithMultibase("base16"))
+		require.EqualError(t, err, "base16 is not a supported multibase. It must be one of base32, base58btc or base64url")
+		require.Empty(t, hl)
+	})
+}
+
+func TestProvider_QueryCreatedBefore_Delete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := provider.Write([]byte("content"))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		var resourceHashes []string
+
+		require.NoError(t, provider.QueryCreatedBefore(time.Now().Add(-time.Hour), func(resourceHash string) error {
+			resourceHashes = append(resourceHashes, resourceHash)
+
+			return nil
+		}))
+		require.Empty(t, resourceHashes)
+
+		require.NoError(t, provider.QueryCreatedBefore(time.Now().Add(time.Minute), func(resourceHash string) error {
+			resourceHashes = append(resourceHashes, resourceHash)
+
+			return nil
+		}))
+		require.Equal(t, []string{rh}, resourceHashes)
+
+		require.NoError(t, provider.Delete(rh))
+
+		content, err := provider.Read(rh)
+		require.ErrorIs(t, err, orberrors.ErrContentNotFound)
+		require.Nil(t, content)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{ErrQuery: errors.New("query error")},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.QueryCreatedBefore(time.Now(), func(string) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+
+	t.Run("Delete error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{ErrDelete: errors.New("delete error")},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.Delete("uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "delete error")
+	})
+}
+
+func TestProvider_ForEachCreatedSince(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		provider, err := localcas.New(ariesmemstorage.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		hl, err := provider.Write([]byte("content"))
+		require.NoError(t, err)
+
+		rh, err := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, err)
+
+		var resourceHashes []string
+
+		require.NoError(t, provider.ForEachCreatedSince(time.Now().Add(time.Hour).Unix(),
+			func(resourceHash string, _ []byte, _ int64) error {
+				resourceHashes = append(resourceHashes, resourceHash)
+
+				return nil
+			},
+		))
+		require.Empty(t, resourceHashes)
+
+		since := time.Now().Add(-time.Minute).Unix()
+
+		require.NoError(t, provider.ForEachCreatedSince(since,
+			func(resourceHash string, content []byte, createdTime int64) error {
+				resourceHashes = append(resourceHashes, resourceHash)
+
+				require.Equal(t, []byte("content"), content)
+				require.GreaterOrEqual(t, createdTime, since)
+
+				return nil
+			},
+		))
+		require.Equal(t, []string{rh}, resourceHashes)
+
+		errExpected := errors.New("injected error")
+
+		err = provider.ForEachCreatedSince(since, func(string, []byte, int64) error { return errExpected })
+		require.ErrorIs(t, err, errExpected)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		provider, err := localcas.New(&ariesmockstorage.Provider{
+			OpenStoreReturn: &ariesmockstorage.Store{ErrQuery: errors.New("query error")},
+		}, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		err = provider.ForEachCreatedSince(0, func(string, []byte, int64) error { return nil })
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "query error")
+		require.True(t, orberrors.IsTransient(err))
+	})
+}
+
+func TestProvider_CreatedTimeQueries_MongoDB(t *testing.T) {
+	mongoDBConnString, stopMongo := mongodbtestutil.StartMongoDB(t)
+	defer stopMongo()
+
+	mongoDBProvider, err := mongodb.NewProvider(mongoDBConnString)
+	require.NoError(t, err)
+
+	provider, err := localcas.New(mongoDBProvider, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	var resourceHashes []string
+
+	for _, content := range []string{"content1", "content2"} {
+		hl, e := provider.Write([]byte(content))
+		require.NoError(t, e)
+
+		rh, e := hashlink.GetResourceHashFromHashLink(hl)
+		require.NoError(t, e)
+
+		resourceHashes = append(resourceHashes, rh)
+	}
+
+	var createdBefore []string
+
+	require.NoError(t, provider.QueryCreatedBefore(time.Now().Add(-time.Hour), func(resourceHash string) error {
+		createdBefore = append(createdBefore, resourceHash)
+
+		return nil
+	}))
+	require.Empty(t, createdBefore)
+
+	require.NoError(t, provider.QueryCreatedBefore(time.Now().Add(time.Minute), func(resourceHash string) error {
+		createdBefore = append(createdBefore, resourceHash)
+
+		return nil
+	}))
+	require.ElementsMatch(t, resourceHashes, createdBefore)
+
+	var createdSince []string
+
+	require.NoError(t, provider.ForEachCreatedSince(time.Now().Add(-time.Minute).Unix(),
+		func(resourceHash string, content []byte, _ int64) error {
+			require.NotEmpty(t, content)
+
+			createdSince = append(createdSince, resourceHash)
+
+			return nil
+		},
+	))
+	require.ElementsMatch(t, resourceHashes, createdSince)
+}
+
+func TestProvider_UntaggedContent(t *testing.T) {
+	memProvider := ariesmemstorage.NewProvider()
+
+	provider, err := localcas.New(memProvider, casLink, nil, &orbmocks.MetricsProvider{}, 0)
+	require.NoError(t, err)
+
+	// Content that was written before the created time tag was introduced.
+	s, err := memProvider.OpenStore("cas")
+	require.NoError(t, err)
+
+	const rh = "uEiDtcAK0OemshF8iNX2CK6wURHMPvbYBbT7JQyKXueyfcw"
+
+	require.NoError(t, s.Put(rh, []byte("content")))
+
+	queryCreatedBefore := func() []string {
+		var resourceHashes []string
+
+		require.NoError(t, provider.QueryCreatedBefore(time.Now().Add(time.Minute), func(resourceHash string) error {
+			resourceHashes = append(resourceHashes, resourceHash)
+
+			return nil
+		}))
+
+		return resourceHashes
+	}
+
+	require.Empty(t, queryCreatedBefore())
+
+	// The created time tag is added when the content is read.
+	content, err := provider.Read(rh)
+	require.NoError(t, err)
+	require.Equal(t, "content", string(content))
+
+	require.Equal(t, []string{rh}, queryCreatedBefore())
+}
@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package casscrub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	namespace = "cas-scrub"
+
+	statusTagName = "status"
+
+	// checkpointKey is the key of the checkpoint record. The checkpoint isn't tagged and therefore
+	// isn't returned by Query.
+	checkpointKey = "checkpoint"
+)
+
+// Status is the status of a quarantined CAS entry.
+type Status = string
+
+const (
+	// StatusPending indicates that the corrupted content hasn't been repaired yet and that the repair will be retried.
+	StatusPending Status = "pending"
+
+	// StatusRepaired indicates that the content was re-fetched from IPFS or a peer and stored in the local CAS.
+	StatusRepaired Status = "repaired"
+
+	// StatusFailed indicates that all repair attempts have been exhausted.
+	StatusFailed Status = "failed"
+)
+
+var logger = log.New("cas-scrub-store")
+
+// Entry holds a local CAS entry whose content doesn't match its resource hash.
+type Entry struct {
+	ResourceHash string    `json:"resourceHash"`
+	ActualHash   string    `json:"actualHash,omitempty"`
+	Status       Status    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	Source       string    `json:"source,omitempty"`
+	LastError    string    `json:"lastError,omitempty"`
+	DetectedAt   time.Time `json:"detectedAt"`
+	RepairedAt   time.Time `json:"repairedAt,omitempty"`
+	// Content is the corrupted content that was removed from the local CAS.
+	Content []byte `json:"content,omitempty"`
+}
+
+// Checkpoint holds the position of the scrubber in the local CAS.
+type Checkpoint struct {
+	// CreatedTime is the (Unix) created time of the next item of content to be checked.
+	CreatedTime int64 `json:"createdTime"`
+	// Cycles is the number of complete passes over the local CAS.
+	Cycles int `json:"cycles"`
+}
+
+// Store implements storage for the CAS scrubber, i.e. the quarantined (corrupted) CAS entries and
+// the scrubber checkpoint.
+type Store struct {
+	store     storage.Store
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// New returns a new CAS scrub store.
+func New(provider storage.Provider) (*Store, error) {
+	s, err := store.Open(provider, namespace,
+		store.NewTagGroup(statusTagName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CAS scrub store: %w", err)
+	}
+
+	return &Store{
+		store:     s,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}, nil
+}
+
+// Put stores the given quarantine entry.
+func (s *Store) Put(e *Entry) error {
+	if e.ResourceHash == "" {
+		return fmt.Errorf("resource hash is required")
+	}
+
+	eBytes, err := s.marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal quarantine entry [%s]: %w", e.ResourceHash, err)
+	}
+
+	logger.Debug("Storing quarantined CAS entry", logfields.WithHash(e.ResourceHash), logfields.WithStatus(e.Status))
+
+	err = s.store.Put(e.ResourceHash, eBytes, storage.Tag{Name: statusTagName, Value: e.Status})
+	if err != nil {
+		return orberrors.NewTransientf("store quarantine entry [%s]: %w", e.ResourceHash, err)
+	}
+
+	return nil
+}
+
+// Get returns the quarantine entry for the given resource hash. If the entry is not found then
+// orberrors.ErrContentNotFound is returned.
+func (s *Store) Get(resourceHash string) (*Entry, error) {
+	eBytes, err := s.store.Get(resourceHash)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get quarantine entry [%s]: %w", resourceHash, err)
+	}
+
+	e := &Entry{}
+
+	err = s.unmarshal(eBytes, e)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal quarantine entry [%s]: %w", resourceHash, err)
+	}
+
+	return e, nil
+}
+
+// Query returns all quarantine entries with the given status.
+func (s *Store) Query(status Status) ([]*Entry, error) {
+	query := fmt.Sprintf("%s:%s", statusTagName, status)
+
+	it, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query quarantine entries [%s]: %w", query, err)
+	}
+
+	defer store.CloseIterator(it)
+
+	var entries []*Entry
+
+	ok, err := it.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("iterator error for quarantine entries [%s]: %w", query, err)
+	}
+
+	for ok {
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get iterator value for quarantine entries [%s]: %w", query, e)
+		}
+
+		entry := &Entry{}
+
+		e = s.unmarshal(value, entry)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal quarantine entry: %w", e)
+		}
+
+		entries = append(entries, entry)
+
+		ok, e = it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("iterator error for quarantine entries [%s]: %w", query, e)
+		}
+	}
+
+	logger.Debug("Returning quarantined CAS entries", logfields.WithStatus(status), logfields.WithTotal(len(entries)))
+
+	return entries, nil
+}
+
+// GetCheckpoint returns the scrubber checkpoint. A zero checkpoint is returned if none was stored.
+func (s *Store) GetCheckpoint() (*Checkpoint, error) {
+	cpBytes, err := s.store.Get(checkpointKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return &Checkpoint{}, nil
+		}
+
+		return nil, orberrors.NewTransientf("get checkpoint: %w", err)
+	}
+
+	cp := &Checkpoint{}
+
+	err = s.unmarshal(cpBytes, cp)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+
+	return cp, nil
+}
+
+// PutCheckpoint stores the scrubber checkpoint.
+func (s *Store) PutCheckpoint(cp *Checkpoint) error {
+	cpBytes, err := s.marshal(cp)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+
+	if err = s.store.Put(checkpointKey, cpBytes); err != nil {
+		return orberrors.NewTransientf("store checkpoint: %w", err)
+	}
+
+	return nil
+}
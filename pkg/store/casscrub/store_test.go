@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package casscrub
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	hash1 = "uEiCeCwiJh3ikvBDjDFjeAcX0gVjzR7dygDPb8Vbd4FpWQQ"
+	hash2 = "uEiDAlnqkRTw2SdvUZtTzFC2txZyefuAFT25cyFr2JBqTKA"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		s, err := New(&mockstore.Provider{
+			ErrOpenStore: fmt.Errorf("failed to open store"),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open store")
+		require.Nil(t, s)
+	})
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	e1 := &Entry{
+		ResourceHash: hash1,
+		ActualHash:   hash2,
+		Status:       StatusPending,
+		Attempts:     1,
+		LastError:    "content not found",
+		DetectedAt:   time.Now(),
+		Content:      []byte("corrupted"),
+	}
+
+	e2 := &Entry{
+		ResourceHash: hash2,
+		Status:       StatusRepaired,
+		Attempts:     1,
+		Source:       "ipfs://bafkreie",
+		DetectedAt:   time.Now(),
+		RepairedAt:   time.Now(),
+	}
+
+	require.NoError(t, s.Put(e1))
+	require.NoError(t, s.Put(e2))
+
+	e, err := s.Get(hash1)
+	require.NoError(t, err)
+	require.Equal(t, hash2, e.ActualHash)
+	require.Equal(t, StatusPending, e.Status)
+	require.Equal(t, []byte("corrupted"), e.Content)
+
+	_, err = s.Get("unknown")
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	require.NoError(t, s.PutCheckpoint(&Checkpoint{CreatedTime: 1000, Cycles: 2}))
+
+	entries, err := s.Query(StatusPending)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, hash1, entries[0].ResourceHash)
+
+	entries, err = s.Query(StatusRepaired)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, hash2, entries[0].ResourceHash)
+
+	e1.Status = StatusFailed
+	require.NoError(t, s.Put(e1))
+
+	entries, err = s.Query(StatusPending)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	entries, err = s.Query(StatusFailed)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestStore_Checkpoint(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	cp, err := s.GetCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, &Checkpoint{}, cp)
+
+	require.NoError(t, s.PutCheckpoint(&Checkpoint{CreatedTime: 1000, Cycles: 2}))
+
+	cp, err = s.GetCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), cp.CreatedTime)
+	require.Equal(t, 2, cp.Cycles)
+}
+
+func TestStore_Error(t *testing.T) {
+	t.Run("no resource hash", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.EqualError(t, s.Put(&Entry{}), "resource hash is required")
+	})
+
+	t.Run("put error", func(t *testing.T) {
+		errExpected := errors.New("injected put error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrPut: errExpected}})
+		require.NoError(t, err)
+
+		err = s.Put(&Entry{ResourceHash: hash1})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		err = s.PutCheckpoint(&Checkpoint{})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrGet: errExpected}})
+		require.NoError(t, err)
+
+		_, err = s.Get(hash1)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+
+		_, err = s.GetCheckpoint()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{GetReturn: []byte("{")}})
+		require.NoError(t, err)
+
+		_, err = s.Get(hash1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal quarantine entry")
+
+		_, err = s.GetCheckpoint()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal checkpoint")
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrQuery: errExpected}})
+		require.NoError(t, err)
+
+		_, err = s.Query(StatusFailed)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
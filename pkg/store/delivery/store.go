@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package delivery
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	namespace = "outbox-delivery"
+
+	statusTagName = "status"
+)
+
+// Status is the status of an outbox delivery.
+type Status = string
+
+const (
+	// StatusPending indicates that the delivery failed and will be retried.
+	StatusPending Status = "pending"
+
+	// StatusFailed indicates that all delivery attempts have been exhausted (or that the failure
+	// is persistent) and the delivery is in the dead-letter collection.
+	StatusFailed Status = "failed"
+
+	// StatusParked indicates that the delivery wasn't attempted since the circuit of the target's actors is open.
+	// The delivery is re-driven once the back-off period of the circuit has expired. Parked deliveries don't count
+	// towards the maximum number of delivery attempts.
+	StatusParked Status = "parked"
+)
+
+var logger = log.New("outbox-delivery-store")
+
+// Delivery holds the delivery state of an activity to a single target inbox.
+type Delivery struct {
+	ID           string    `json:"id"`
+	ActivityID   string    `json:"activityId"`
+	ActivityType string    `json:"activityType,omitempty"`
+	Target       string    `json:"target"`
+	ActorIRIs    []string  `json:"actors,omitempty"`
+	Status       Status    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"lastError,omitempty"`
+	LastAttempt  time.Time `json:"lastAttempt"`
+	NextRetry    time.Time `json:"nextRetry,omitempty"`
+}
+
+// Store implements storage for the outbox delivery ledger. Only deliveries that have failed at least
+// once are kept in the ledger. A delivery is removed from the ledger once it succeeds.
+type Store struct {
+	store     storage.Store
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// New returns a new outbox delivery store.
+func New(provider storage.Provider) (*Store, error) {
+	s, err := store.Open(provider, namespace,
+		store.NewTagGroup(statusTagName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox delivery store: %w", err)
+	}
+
+	return &Store{
+		store:     s,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}, nil
+}
+
+// ID returns the ID of the delivery of the given activity to the given target.
+func ID(activityID, target *url.URL) string {
+	h := sha256.Sum256([]byte(activityID.String() + " " + target.String()))
+
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// Put stores the given delivery record.
+func (s *Store) Put(d *Delivery) error {
+	if d.ID == "" {
+		return fmt.Errorf("delivery ID is required")
+	}
+
+	dBytes, err := s.marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshal delivery [%s]: %w", d.ID, err)
+	}
+
+	logger.Debug("Storing delivery record", logfields.WithID(d.ID), logfields.WithStatus(d.Status),
+		logfields.WithTarget(d.Target), logfields.WithDeliveryAttempts(d.Attempts))
+
+	err = s.store.Put(d.ID, dBytes, storage.Tag{Name: statusTagName, Value: d.Status})
+	if err != nil {
+		return orberrors.NewTransientf("store delivery [%s]: %w", d.ID, err)
+	}
+
+	return nil
+}
+
+// Get returns the delivery record for the given ID. If the record is not found then
+// orberrors.ErrContentNotFound is returned.
+func (s *Store) Get(id string) (*Delivery, error) {
+	dBytes, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get delivery [%s]: %w", id, err)
+	}
+
+	d := &Delivery{}
+
+	err = s.unmarshal(dBytes, d)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal delivery [%s]: %w", id, err)
+	}
+
+	return d, nil
+}
+
+// Delete deletes the delivery record for the given ID.
+func (s *Store) Delete(id string) error {
+	if err := s.store.Delete(id); err != nil {
+		return orberrors.NewTransientf("delete delivery [%s]: %w", id, err)
+	}
+
+	return nil
+}
+
+// Query returns all delivery records with the given status.
+func (s *Store) Query(status Status) ([]*Delivery, error) {
+	query := fmt.Sprintf("%s:%s", statusTagName, status)
+
+	it, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query deliveries [%s]: %w", query, err)
+	}
+
+	defer store.CloseIterator(it)
+
+	var deliveries []*Delivery
+
+	ok, err := it.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("iterator error for deliveries [%s]: %w", query, err)
+	}
+
+	for ok {
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get iterator value for deliveries [%s]: %w", query, e)
+		}
+
+		d := &Delivery{}
+
+		e = s.unmarshal(value, d)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal delivery: %w", e)
+		}
+
+		deliveries = append(deliveries, d)
+
+		ok, e = it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("iterator error for deliveries [%s]: %w", query, e)
+		}
+	}
+
+	logger.Debug("Returning deliveries", logfields.WithStatus(status), logfields.WithTotal(len(deliveries)))
+
+	return deliveries, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	activityID = testutil.MustParseURL("https://domain1.com/services/orb/activities/123")
+	target1    = testutil.MustParseURL("https://domain2.com/services/orb/inbox")
+	target2    = testutil.MustParseURL("https://domain3.com/services/orb/inbox")
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		s, err := New(&mockstore.Provider{
+			ErrOpenStore: fmt.Errorf("failed to open store"),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open store")
+		require.Nil(t, s)
+	})
+}
+
+func TestID(t *testing.T) {
+	require.Equal(t, ID(activityID, target1), ID(activityID, target1))
+	require.NotEqual(t, ID(activityID, target1), ID(activityID, target2))
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	d1 := &Delivery{
+		ID:          ID(activityID, target1),
+		ActivityID:  activityID.String(),
+		Target:      target1.String(),
+		Status:      StatusPending,
+		Attempts:    1,
+		LastError:   "connection refused",
+		LastAttempt: time.Now(),
+		NextRetry:   time.Now().Add(time.Second),
+	}
+
+	d2 := &Delivery{
+		ID:          ID(activityID, target2),
+		ActivityID:  activityID.String(),
+		Target:      target2.String(),
+		Status:      StatusFailed,
+		Attempts:    5,
+		LastError:   "server responded with error 500",
+		LastAttempt: time.Now(),
+	}
+
+	require.NoError(t, s.Put(d1))
+	require.NoError(t, s.Put(d2))
+
+	d, err := s.Get(d1.ID)
+	require.NoError(t, err)
+	require.Equal(t, d1.Target, d.Target)
+	require.Equal(t, StatusPending, d.Status)
+	require.Equal(t, 1, d.Attempts)
+
+	deliveries, err := s.Query(StatusFailed)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, d2.ID, deliveries[0].ID)
+
+	deliveries, err = s.Query(StatusPending)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, d1.ID, deliveries[0].ID)
+
+	require.NoError(t, s.Delete(d1.ID))
+
+	_, err = s.Get(d1.ID)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	deliveries, err = s.Query(StatusPending)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
+
+func TestStore_Error(t *testing.T) {
+	t.Run("no ID", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.EqualError(t, s.Put(&Delivery{}), "delivery ID is required")
+	})
+
+	t.Run("put error", func(t *testing.T) {
+		errExpected := errors.New("injected put error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrPut: errExpected}})
+		require.NoError(t, err)
+
+		err = s.Put(&Delivery{ID: "123"})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrGet: errExpected}})
+		require.NoError(t, err)
+
+		_, err = s.Get("123")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{GetReturn: []byte("{")}})
+		require.NoError(t, err)
+
+		_, err = s.Get("123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal delivery")
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		errExpected := errors.New("injected delete error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrDelete: errExpected}})
+		require.NoError(t, err)
+
+		err = s.Delete("123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrQuery: errExpected}})
+		require.NoError(t, err)
+
+		_, err = s.Query(StatusFailed)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
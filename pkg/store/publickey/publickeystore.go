@@ -79,6 +79,22 @@ func (c *Store) GetPublicKey(issuerID, keyID string) (*verifier.PublicKey, error
 	return pk.(*verifier.PublicKey), nil //nolint:forcetypeassert
 }
 
+// Purge removes the public key for the given issuer and key ID from the cache and from persistent storage
+// so that it is fetched from the server upon next access. This should be called when the issuer is known
+// to have rotated its keys.
+func (c *Store) Purge(issuerID, keyID string) error {
+	logger.Info("Purging public key for issuer", logfields.WithIssuer(issuerID), logfields.WithKeyID(keyID))
+
+	c.cache.Remove(cacheKey{issuerID, keyID})
+
+	err := c.store.Delete(fmt.Sprintf("%s-%s", issuerID, keyID))
+	if err != nil {
+		return fmt.Errorf("delete public key - issuer [%s], key ID [%s]: %w", issuerID, keyID, err)
+	}
+
+	return nil
+}
+
 func (c *Store) get(issuerID, keyID string) (*verifier.PublicKey, error) {
 	logger.Info("Loading public key into cache for issuer",
 		logfields.WithIssuer(issuerID), logfields.WithKeyID(keyID))
@@ -148,3 +148,53 @@ func TestStore_GetPublicKey(t *testing.T) {
 		require.NotNil(t, pk)
 	})
 }
+
+func TestStore_Purge(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		numFetches := 0
+
+		s, err := New(p, func(issuerID, keyID string) (*verifier.PublicKey, error) {
+			numFetches++
+
+			return &verifier.PublicKey{}, nil
+		})
+		require.NoError(t, err)
+
+		_, err = s.GetPublicKey("did:web:orb.domain1.com", "key1")
+		require.NoError(t, err)
+		require.Equal(t, 1, numFetches)
+
+		require.NoError(t, s.Purge("did:web:orb.domain1.com", "key1"))
+		require.Equal(t, 1, store.DeleteCallCount())
+		require.Equal(t, "did:web:orb.domain1.com-key1", store.DeleteArgsForCall(0))
+
+		_, err = s.GetPublicKey("did:web:orb.domain1.com", "key1")
+		require.NoError(t, err)
+		require.Equal(t, 2, numFetches)
+	})
+
+	t.Run("DB delete error", func(t *testing.T) {
+		errExpected := errors.New("injected delete error")
+
+		store := &mocks.Store{}
+		store.DeleteReturns(errExpected)
+
+		p := &mocks.Provider{}
+		p.OpenStoreReturns(store, nil)
+
+		s, err := New(p, func(issuerID, keyID string) (*verifier.PublicKey, error) {
+			return &verifier.PublicKey{}, nil
+		})
+		require.NoError(t, err)
+
+		err = s.Purge("did:web:orb.domain1.com", "key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
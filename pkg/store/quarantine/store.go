@@ -0,0 +1,167 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quarantine
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	namespace = "inbox-quarantine"
+
+	quarantinedTagName = "quarantined"
+)
+
+var logger = log.New("inbox-quarantine-store")
+
+// Entry holds an inbound activity that was quarantined by the inbox filter.
+type Entry struct {
+	ID            string          `json:"id"`
+	ActivityID    string          `json:"activityId"`
+	ActivityType  string          `json:"activityType,omitempty"`
+	Actor         string          `json:"actor,omitempty"`
+	Reason        string          `json:"reason,omitempty"`
+	QuarantinedAt time.Time       `json:"quarantinedAt"`
+	Activity      json.RawMessage `json:"activity"`
+}
+
+// Store implements storage for quarantined inbound activities.
+type Store struct {
+	store     storage.Store
+	marshal   func(v interface{}) ([]byte, error)
+	unmarshal func(data []byte, v interface{}) error
+}
+
+// New returns a new quarantine store.
+func New(provider storage.Provider) (*Store, error) {
+	s, err := store.Open(provider, namespace,
+		store.NewTagGroup(quarantinedTagName),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quarantine store: %w", err)
+	}
+
+	return &Store{
+		store:     s,
+		marshal:   json.Marshal,
+		unmarshal: json.Unmarshal,
+	}, nil
+}
+
+// ID returns the ID of the quarantine entry for the given activity.
+func ID(activityID *url.URL) string {
+	h := sha256.Sum256([]byte(activityID.String()))
+
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// Put stores the given quarantine entry.
+func (s *Store) Put(e *Entry) error {
+	if e.ID == "" {
+		return fmt.Errorf("quarantine entry ID is required")
+	}
+
+	eBytes, err := s.marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal quarantine entry [%s]: %w", e.ID, err)
+	}
+
+	logger.Debug("Storing quarantined activity", logfields.WithID(e.ID), logfields.WithActivityType(e.ActivityType))
+
+	err = s.store.Put(e.ID, eBytes, storage.Tag{Name: quarantinedTagName})
+	if err != nil {
+		return orberrors.NewTransientf("store quarantine entry [%s]: %w", e.ID, err)
+	}
+
+	return nil
+}
+
+// Get returns the quarantine entry for the given ID. If the entry is not found then
+// orberrors.ErrContentNotFound is returned.
+func (s *Store) Get(id string) (*Entry, error) {
+	eBytes, err := s.store.Get(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get quarantine entry [%s]: %w", id, err)
+	}
+
+	e := &Entry{}
+
+	err = s.unmarshal(eBytes, e)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal quarantine entry [%s]: %w", id, err)
+	}
+
+	return e, nil
+}
+
+// Delete deletes the quarantine entry for the given ID.
+func (s *Store) Delete(id string) error {
+	if err := s.store.Delete(id); err != nil {
+		return orberrors.NewTransientf("delete quarantine entry [%s]: %w", id, err)
+	}
+
+	return nil
+}
+
+// GetAll returns all quarantine entries.
+func (s *Store) GetAll() ([]*Entry, error) {
+	it, err := s.store.Query(quarantinedTagName)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query quarantine entries: %w", err)
+	}
+
+	defer store.CloseIterator(it)
+
+	var entries []*Entry
+
+	ok, err := it.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("iterator error for quarantine entries: %w", err)
+	}
+
+	for ok {
+		value, e := it.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get iterator value for quarantine entries: %w", e)
+		}
+
+		entry := &Entry{}
+
+		e = s.unmarshal(value, entry)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal quarantine entry: %w", e)
+		}
+
+		entries = append(entries, entry)
+
+		ok, e = it.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("iterator error for quarantine entries: %w", e)
+		}
+	}
+
+	logger.Debug("Returning quarantine entries", logfields.WithTotal(len(entries)))
+
+	return entries, nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package quarantine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	mockstore "github.com/hyperledger/aries-framework-go/component/storageutil/mock"
+	"github.com/stretchr/testify/require"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+)
+
+var (
+	activityID1 = testutil.MustParseURL("https://domain1.com/services/orb/activities/123")
+	activityID2 = testutil.MustParseURL("https://domain1.com/services/orb/activities/456")
+)
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+
+	t.Run("open store error", func(t *testing.T) {
+		s, err := New(&mockstore.Provider{
+			ErrOpenStore: fmt.Errorf("failed to open store"),
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open store")
+		require.Nil(t, s)
+	})
+}
+
+func TestID(t *testing.T) {
+	require.Equal(t, ID(activityID1), ID(activityID1))
+	require.NotEqual(t, ID(activityID1), ID(activityID2))
+}
+
+func TestStore(t *testing.T) {
+	s, err := New(mem.NewProvider())
+	require.NoError(t, err)
+
+	entries, err := s.GetAll()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	e1 := &Entry{
+		ID:            ID(activityID1),
+		ActivityID:    activityID1.String(),
+		ActivityType:  "Create",
+		Actor:         "https://domain1.com/services/orb",
+		Reason:        "rule 0 (payloadSize): payload size 2000 exceeds maximum size 1000",
+		QuarantinedAt: time.Now(),
+		Activity:      []byte(`{"type":"Create"}`),
+	}
+
+	e2 := &Entry{
+		ID:            ID(activityID2),
+		ActivityID:    activityID2.String(),
+		ActivityType:  "Follow",
+		QuarantinedAt: time.Now(),
+		Activity:      []byte(`{"type":"Follow"}`),
+	}
+
+	require.NoError(t, s.Put(e1))
+	require.NoError(t, s.Put(e2))
+
+	e, err := s.Get(e1.ID)
+	require.NoError(t, err)
+	require.Equal(t, e1.ActivityID, e.ActivityID)
+	require.Equal(t, e1.Reason, e.Reason)
+	require.JSONEq(t, string(e1.Activity), string(e.Activity))
+
+	entries, err = s.GetAll()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	require.NoError(t, s.Delete(e1.ID))
+
+	_, err = s.Get(e1.ID)
+	require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+	entries, err = s.GetAll()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, e2.ID, entries[0].ID)
+}
+
+func TestStore_Error(t *testing.T) {
+	t.Run("no ID", func(t *testing.T) {
+		s, err := New(mem.NewProvider())
+		require.NoError(t, err)
+
+		require.EqualError(t, s.Put(&Entry{}), "quarantine entry ID is required")
+	})
+
+	t.Run("put error", func(t *testing.T) {
+		errExpected := errors.New("injected put error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrPut: errExpected}})
+		require.NoError(t, err)
+
+		err = s.Put(&Entry{ID: "123"})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		errExpected := errors.New("injected get error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrGet: errExpected}})
+		require.NoError(t, err)
+
+		_, err = s.Get("123")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{GetReturn: []byte("{")}})
+		require.NoError(t, err)
+
+		_, err = s.Get("123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal quarantine entry")
+	})
+
+	t.Run("delete error", func(t *testing.T) {
+		errExpected := errors.New("injected delete error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrDelete: errExpected}})
+		require.NoError(t, err)
+
+		err = s.Delete("123")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		errExpected := errors.New("injected query error")
+
+		s, err := New(&mockstore.Provider{OpenStoreReturn: &mockstore.Store{ErrQuery: errExpected}})
+		require.NoError(t, err)
+
+		_, err = s.GetAll()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errExpected.Error())
+	})
+}
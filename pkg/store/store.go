@@ -9,6 +9,7 @@ package store
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -27,7 +28,21 @@ import (
 
 var logger = log.New("store")
 
-const idField = "_id"
+const (
+	idField      = "_id"
+	versionField = "version"
+)
+
+// ErrVersionConflict is returned by a conditional write if the stored value was modified by another writer.
+var ErrVersionConflict = errors.New("version conflict")
+
+// VersionedStore is implemented by stores that support optimistic concurrency control.
+type VersionedStore interface {
+	// PutIfVersion stores the given value (which must be a JSON object containing a "version" field) only if the
+	// version of the stored value equals the given version. A version of zero matches a value that doesn't exist
+	// or doesn't have a version. ErrVersionConflict is returned if the stored value has a different version.
+	PutIfVersion(key string, value []byte, version int64) error
+}
 
 // TagGroup defines a group of tags that may be used to create a compound index.
 type TagGroup []string
@@ -262,6 +277,41 @@ func (s *mongoDBWrapper) Batch(operations []storage.Operation) error {
 	return nil
 }
 
+// PutIfVersion stores the given value only if the "version" field of the stored document equals the given version.
+// The document is replaced using an upsert which is filtered by both the key and the version, so if the document
+// exists with a different version then the upsert fails with a duplicate key error.
+func (s *mongoDBWrapper) PutIfVersion(key string, value []byte, version int64) error {
+	var doc map[string]interface{}
+
+	jsonDecoder := json.NewDecoder(bytes.NewReader(value))
+	jsonDecoder.UseNumber()
+
+	if err := jsonDecoder.Decode(&doc); err != nil {
+		return fmt.Errorf("unmarshal document [%s-%s]: %w", s.namespace, key, err)
+	}
+
+	doc[idField] = key
+
+	filter := bson.M{idField: key, versionField: version}
+
+	if version == 0 {
+		filter[versionField] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	err := s.ms.BulkWrite([]mongo.WriteModel{
+		mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(doc).SetUpsert(true),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return fmt.Errorf("put [%s-%s] at version %d: %w", s.namespace, key, version, ErrVersionConflict)
+		}
+
+		return fmt.Errorf("put [%s-%s] at version %d: %w", s.namespace, key, version, err)
+	}
+
+	return nil
+}
+
 // GetTags returns the tags for the given key.
 func (s *mongoDBWrapper) GetTags(string) ([]storage.Tag, error) {
 	panic("not implemented")
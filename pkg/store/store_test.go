@@ -184,6 +184,50 @@ func TestMongoDBPut(t *testing.T) {
 	})
 }
 
+func TestMongoDBPutIfVersion(t *testing.T) {
+	store := &mocks.MongoDBStore{}
+
+	provider := &mocks.MongoDBProvider{}
+	provider.OpenStoreReturns(store, nil)
+
+	s, err := Open(provider, "store1")
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	vs, ok := s.(VersionedStore)
+	require.True(t, ok)
+
+	const key = "key1"
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, vs.PutIfVersion(key, []byte(`{"version":1}`), 0))
+		require.NoError(t, vs.PutIfVersion(key, []byte(`{"version":2}`), 1))
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		require.Error(t, vs.PutIfVersion(key, []byte(`{`), 0))
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		store.BulkWriteReturns(errors.New("failed to perform batch operations after 3 attempts: duplicate key"))
+		defer store.BulkWriteReturns(nil)
+
+		err := vs.PutIfVersion(key, []byte(`{"version":2}`), 1)
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("BulkWrite error", func(t *testing.T) {
+		errExpected := errors.New("injected BulkWrite error")
+
+		store.BulkWriteReturns(errExpected)
+		defer store.BulkWriteReturns(nil)
+
+		err := vs.PutIfVersion(key, []byte(`{"version":2}`), 1)
+		require.ErrorIs(t, err, errExpected)
+		require.NotErrorIs(t, err, ErrVersionConflict)
+	})
+}
+
 func TestMongoDBGet(t *testing.T) {
 	store := &mocks.MongoDBStore{}
 
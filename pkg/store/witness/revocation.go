@@ -0,0 +1,244 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+	"github.com/trustbloc/orb/pkg/vct"
+)
+
+const (
+	revocationType      = "witness-revocation"
+	revocationKeyPrefix = "revocation_"
+)
+
+// Revocation indicates that the proofs of a witness are no longer trusted, for example because the
+// witness' signing key was compromised.
+type Revocation struct {
+	WitnessURI *vocab.URLProperty `json:"witness"`
+	Reason     string             `json:"reason,omitempty"`
+	// Since is the time from which the proofs of the witness are untrusted. Proofs that were created
+	// before this time are still trusted.
+	Since       time.Time `json:"since"`
+	RevokedTime time.Time `json:"revokedTime"`
+}
+
+// Applies returns true if a proof that was created at the given time is revoked.
+func (r *Revocation) Applies(created time.Time) bool {
+	return !created.Before(r.Since)
+}
+
+// AppliesToProof returns true if the revocation applies to the given witness proof, i.e. if the primary proof
+// or any of the additional proofs was created on or after the revocation time. An error is returned if the
+// created time of a proof cannot be determined.
+func (r *Revocation) AppliesToProof(proofBytes []byte) (bool, error) {
+	p := &vct.Proof{}
+
+	err := json.Unmarshal(proofBytes, p)
+	if err != nil {
+		return false, fmt.Errorf("unmarshal proof: %w", err)
+	}
+
+	proofs := p.Proofs()
+	if len(proofs) == 0 {
+		return false, errors.New("no proofs found")
+	}
+
+	for _, vp := range proofs {
+		created, ok := vp["created"].(string)
+		if !ok {
+			return false, errors.New("created time not found in proof")
+		}
+
+		createdTime, err := time.Parse(time.RFC3339, created)
+		if err != nil {
+			return false, fmt.Errorf("parse created time: %w", err)
+		}
+
+		if r.Applies(createdTime) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type revocationEntry struct {
+	EntryType string `json:"entryType"`
+	*Revocation
+}
+
+// Revoke marks the proofs of the given witness that were created on or after the given time as untrusted. The
+// proofs of the witness are also removed from anchors that are currently being witnessed, and the IDs of those
+// anchors are returned.
+func (s *Store) Revoke(witness *url.URL, since time.Time, reason string) ([]string, error) {
+	entryBytes, err := json.Marshal(&revocationEntry{
+		EntryType: revocationType,
+		Revocation: &Revocation{
+			WitnessURI:  vocab.NewURLProperty(witness),
+			Reason:      reason,
+			Since:       since,
+			RevokedTime: time.Now(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal revocation for witness[%s]: %w", witness, err)
+	}
+
+	err = s.store.Put(revocationKey(witness), entryBytes, storage.Tag{Name: typeTagName, Value: revocationType})
+	if err != nil {
+		return nil, orberrors.NewTransientf("store revocation for witness[%s]: %w", witness, err)
+	}
+
+	logger.Info("Revoked witness", logfields.WithWitnessURI(witness), logfields.WithCreatedTime(since))
+
+	anchorIDs, err := s.removeProofs(witness, since)
+	if err != nil {
+		return nil, fmt.Errorf("remove proofs of witness[%s]: %w", witness, err)
+	}
+
+	return anchorIDs, nil
+}
+
+// GetRevocation returns the revocation of the given witness. ErrContentNotFound is returned if the
+// witness hasn't been revoked.
+func (s *Store) GetRevocation(witness *url.URL) (*Revocation, error) {
+	entryBytes, err := s.store.Get(revocationKey(witness))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get revocation for witness[%s]: %w", witness, err)
+	}
+
+	entry := &revocationEntry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal revocation for witness[%s]: %w", witness, err)
+	}
+
+	return entry.Revocation, nil
+}
+
+// removeProofs deletes the proofs of the given witness that were created on or after the given time from the
+// anchors that are currently being witnessed and returns the IDs of those anchors. If the created time of a proof
+// cannot be determined then, to err on the side of caution, the proof is removed.
+func (s *Store) removeProofs(witness *url.URL, since time.Time) ([]string, error) {
+	revocation := &Revocation{Since: since}
+
+	query := fmt.Sprintf(queryExpr, witnessTagName, encodeWitness(witness), typeTagName, proofType)
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("failed to query proofs of witness[%s]: %w", witness, err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("proof iterator error for witness[%s]: %w", witness, err)
+	}
+
+	var (
+		operations []storage.Operation
+		anchorIDs  []string
+	)
+
+	for ok {
+		key, e := iter.Key()
+		if e != nil {
+			return nil, orberrors.NewTransientf("failed to get key from iterator for witness[%s]: %w", witness, e)
+		}
+
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("failed to get proof from iterator for witness[%s]: %w", witness, e)
+		}
+
+		p := &witnessProof{}
+
+		e = json.Unmarshal(value, p)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal proof of witness[%s]: %w", witness, e)
+		}
+
+		anchorID, e := base64.RawURLEncoding.DecodeString(p.AnchorID)
+		if e != nil {
+			return nil, fmt.Errorf("decode anchor ID of proof of witness[%s]: %w", witness, e)
+		}
+
+		if applies(revocation, p.Proof, string(anchorID), witness) {
+			operations = append(operations, storage.Operation{Key: key})
+			anchorIDs = append(anchorIDs, string(anchorID))
+		}
+
+		ok, e = iter.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("proof iterator error for witness[%s]: %w", witness, e)
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil, nil
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return nil, orberrors.NewTransientf("delete proofs of witness[%s]: %w", witness, err)
+	}
+
+	logger.Info("Removed proofs of revoked witness from pending anchors", logfields.WithWitnessURI(witness),
+		logfields.WithAnchorURIStrings(anchorIDs...))
+
+	return anchorIDs, nil
+}
+
+func applies(revocation *Revocation, proofBytes []byte, anchorID string, witness *url.URL) bool {
+	if len(proofBytes) == 0 {
+		// The witness hasn't provided a proof yet.
+		return false
+	}
+
+	revoked, err := revocation.AppliesToProof(proofBytes)
+	if err != nil {
+		logger.Warn("Unable to determine whether the revocation applies to the proof of a witness. The proof will be removed.",
+			logfields.WithAnchorURIString(anchorID), logfields.WithWitnessURI(witness), log.WithError(err))
+
+		return true
+	}
+
+	if !revoked {
+		logger.Debug("Proof of revoked witness was created before the revocation time. The proof will be retained.",
+			logfields.WithAnchorURIString(anchorID), logfields.WithWitnessURI(witness))
+	}
+
+	return revoked
+}
+
+func revocationKey(witness *url.URL) string {
+	return revocationKeyPrefix + encodeWitness(witness)
+}
+
+func encodeWitness(witness *url.URL) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(witness.String()))
+}
@@ -0,0 +1,221 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore_Revoke(t *testing.T) {
+	witness1URL := testutil.MustParseURL("https://w1.com/services/orb")
+	witness2URL := testutil.MustParseURL("https://w2.com/services/orb")
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		const anchor2ID = "id2"
+
+		for _, id := range []string{anchorID, anchor2ID} {
+			require.NoError(t, s.Put(id, []*proof.Witness{
+				{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+				{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL), Selected: true},
+			}))
+		}
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+		require.NoError(t, s.AddProof(anchorID, witness2URL, []byte(proofJSON)))
+		require.NoError(t, s.AddProof(anchor2ID, witness2URL, []byte(proofJSON)))
+
+		revocation, err := s.GetRevocation(witness1URL)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		require.Nil(t, revocation)
+
+		// The proofs were created after the revocation time.
+		since := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+		anchorIDs, err := s.Revoke(witness1URL, since, "key compromised")
+		require.NoError(t, err)
+		require.Equal(t, []string{anchorID}, anchorIDs)
+
+		revocation, err = s.GetRevocation(witness1URL)
+		require.NoError(t, err)
+		require.Equal(t, witness1URL.String(), revocation.WitnessURI.String())
+		require.Equal(t, "key compromised", revocation.Reason)
+		require.True(t, since.Equal(revocation.Since))
+		require.False(t, revocation.RevokedTime.IsZero())
+
+		witnesses, err := s.Get(anchorID)
+		require.NoError(t, err)
+		require.Len(t, witnesses, 2)
+
+		for _, w := range witnesses {
+			if w.URI.String() == witness1URL.String() {
+				require.Nil(t, w.Proof)
+			} else {
+				require.NotNil(t, w.Proof)
+			}
+		}
+
+		// Nothing left to remove.
+		anchorIDs, err = s.Revoke(witness1URL, since, "key compromised")
+		require.NoError(t, err)
+		require.Empty(t, anchorIDs)
+	})
+
+	t.Run("proofs created before revocation time are retained", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+		}))
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+
+		anchorIDs, err := s.Revoke(witness1URL, time.Now().Add(-time.Hour), "key compromised")
+		require.NoError(t, err)
+		require.Empty(t, anchorIDs)
+
+		witnesses, err := s.Get(anchorID)
+		require.NoError(t, err)
+		require.Len(t, witnesses, 1)
+		require.NotNil(t, witnesses[0].Proof)
+	})
+
+	t.Run("proof with no created time is removed", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+		}))
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(`{"proof":{"type":"Ed25519Signature2018"}}`)))
+
+		anchorIDs, err := s.Revoke(witness1URL, time.Now(), "key compromised")
+		require.NoError(t, err)
+		require.Equal(t, []string{anchorID}, anchorIDs)
+	})
+
+	t.Run("error - put error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.PutReturns(fmt.Errorf("injected put error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.Revoke(witness1URL, time.Now(), "")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+	})
+
+	t.Run("error - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("injected query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.Revoke(witness1URL, time.Now(), "")
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+
+	t.Run("error - get revocation error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, fmt.Errorf("injected get error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetRevocation(witness1URL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+	})
+}
+
+func TestRevocation_Applies(t *testing.T) {
+	since := time.Now()
+
+	r := &Revocation{Since: since}
+
+	require.True(t, r.Applies(since))
+	require.True(t, r.Applies(since.Add(time.Second)))
+	require.False(t, r.Applies(since.Add(-time.Second)))
+}
+
+func TestRevocation_AppliesToProof(t *testing.T) {
+	created := time.Date(2021, 4, 20, 20, 5, 35, 0, time.UTC)
+
+	t.Run("primary proof", func(t *testing.T) {
+		applies, err := (&Revocation{Since: created.Add(-time.Second)}).AppliesToProof([]byte(proofJSON))
+		require.NoError(t, err)
+		require.True(t, applies)
+
+		applies, err = (&Revocation{Since: created.Add(time.Second)}).AppliesToProof([]byte(proofJSON))
+		require.NoError(t, err)
+		require.False(t, applies)
+	})
+
+	t.Run("additional proof", func(t *testing.T) {
+		proofBytes := []byte(`{
+  "proof": {"created": "2021-04-20T20:05:35Z"},
+  "additionalProofs": [{"created": "2021-04-21T20:05:35Z"}]
+}`)
+
+		applies, err := (&Revocation{Since: created.Add(time.Hour)}).AppliesToProof(proofBytes)
+		require.NoError(t, err)
+		require.True(t, applies)
+
+		applies, err = (&Revocation{Since: created.Add(48 * time.Hour)}).AppliesToProof(proofBytes)
+		require.NoError(t, err)
+		require.False(t, applies)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		r := &Revocation{Since: created}
+
+		_, err := r.AppliesToProof([]byte("{"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal proof")
+
+		_, err = r.AppliesToProof([]byte(`{}`))
+		require.EqualError(t, err, "no proofs found")
+
+		_, err = r.AppliesToProof([]byte(`{"proof":{"type":"x"}}`))
+		require.EqualError(t, err, "created time not found in proof")
+
+		_, err = r.AppliesToProof([]byte(`{"proof":{"created":"yesterday"}}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse created time")
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+)
+
+const (
+	policyScopeType   = "policy-scope"
+	policyScopePrefix = "scope_"
+)
+
+type policyScopeEntry struct {
+	*Entry
+	Scope *config.AnchorScope `json:"scope"`
+}
+
+// PutPolicyScope saves the scope (DID namespace and anchor origins) of an anchor. The scope is used to resolve
+// the witness policies that apply to the anchor and it's deleted along with the other witness data of the anchor.
+func (s *Store) PutPolicyScope(anchorID string, scope *config.AnchorScope) error {
+	op, err := s.policyScopeOperation(anchorID, scope)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Put(op.Key, op.Value, op.Tags...)
+	if err != nil {
+		return orberrors.NewTransientf("store policy scope for anchor[%s]: %w", anchorID, err)
+	}
+
+	logger.Debug("Stored policy scope for anchor", logfields.WithAnchorURIString(anchorID),
+		logfields.WithNamespace(scope.Namespace))
+
+	return nil
+}
+
+func (s *Store) policyScopeOperation(anchorID string, scope *config.AnchorScope) (storage.Operation, error) {
+	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
+
+	entry := &policyScopeEntry{
+		Entry: &Entry{
+			EntryType:  policyScopeType,
+			AnchorID:   anchorIDEncoded,
+			ExpiryTime: time.Now().Add(s.expiryPeriod).Unix(),
+		},
+		Scope: scope,
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return storage.Operation{}, fmt.Errorf("marshal policy scope for anchor[%s]: %w", anchorID, err)
+	}
+
+	return storage.Operation{
+		Key:   policyScopeKey(anchorIDEncoded),
+		Value: entryBytes,
+		Tags: []storage.Tag{
+			{Name: typeTagName, Value: policyScopeType},
+			{Name: anchorIndexTagName, Value: anchorIDEncoded},
+			{Name: expiryTagName, Value: fmt.Sprintf("%d", entry.ExpiryTime)},
+		},
+	}, nil
+}
+
+// GetPolicyScope returns the scope of the given anchor. ErrContentNotFound is returned if no scope was stored
+// for the anchor.
+func (s *Store) GetPolicyScope(anchorID string) (*config.AnchorScope, error) {
+	entryBytes, err := s.store.Get(policyScopeKey(base64.RawURLEncoding.EncodeToString([]byte(anchorID))))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get policy scope for anchor[%s]: %w", anchorID, err)
+	}
+
+	entry := &policyScopeEntry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal policy scope for anchor[%s]: %w", anchorID, err)
+	}
+
+	return entry.Scope, nil
+}
+
+func policyScopeKey(anchorIDEncoded string) string {
+	return policyScopePrefix + anchorIDEncoded
+}
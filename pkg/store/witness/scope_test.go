@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore_PolicyScope(t *testing.T) {
+	scope := &config.AnchorScope{
+		Namespace: "did:orb",
+		Origins:   []string{"https://orb.domain1.com", "https://orb.domain2.com"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		witnessURL := testutil.MustParseURL("https://w1.com/services/orb")
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witnessURL), Selected: true},
+		}))
+		require.NoError(t, s.PutPolicyScope(anchorID, scope))
+
+		scp, err := s.GetPolicyScope(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, scope, scp)
+
+		require.NoError(t, s.AddProof(anchorID, witnessURL, []byte(proofJSON)))
+		require.NoError(t, s.RecordWitnessedAnchor(anchorID, "vcID", "hl:xxx"))
+
+		anchor, err := s.GetWitnessedAnchor(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, scope, anchor.Scope)
+
+		// The scope is deleted along with the witnesses of the anchor.
+		require.NoError(t, s.Delete(anchorID))
+
+		_, err = s.GetPolicyScope(anchorID)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.PutReturns(fmt.Errorf("injected put error"))
+		store.GetReturns(nil, fmt.Errorf("injected get error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		err = s.PutPolicyScope(anchorID, scope)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected put error")
+
+		_, err = s.GetPolicyScope(anchorID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetPolicyScope(anchorID)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal policy scope")
+	})
+}
@@ -0,0 +1,491 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	statsType      = "witness-stats"
+	statsKeyPrefix = "stats_"
+
+	// statsSmoothingFactor is the weight of the most recent sample in the moving averages of the witness stats.
+	statsSmoothingFactor = 0.2
+
+	// StatsWindowPeriod is the period of a single stats window.
+	StatsWindowPeriod = time.Hour
+	// MaxStatsRetention is the maximum period for which the stats windows of a witness are retained.
+	MaxStatsRetention = 7 * 24 * time.Hour
+
+	// maxStatsUpdateAttempts is the maximum number of times that the stats of a witness are re-read and updated
+	// if the stats were concurrently modified by another server instance.
+	maxStatsUpdateAttempts = 5
+)
+
+// turnaroundBuckets contains the upper bounds of the proof turnaround histogram of a stats window. The last
+// bucket of the histogram (which isn't included here) contains the turnaround times that exceed the last bound.
+var turnaroundBuckets = []time.Duration{ //nolint:gochecknoglobals
+	time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 30 * time.Minute, time.Hour,
+}
+
+// Stats contains the historical proof statistics of a witness.
+type Stats struct {
+	WitnessURI *vocab.URLProperty `json:"witness"`
+
+	// Requests is the number of times that the witness was selected to witness an anchor.
+	Requests int64 `json:"requests"`
+	// Proofs is the number of proofs received from the witness.
+	Proofs int64 `json:"proofs"`
+	// Timeouts is the number of times that the witness did not return a proof within the maximum witness delay.
+	Timeouts int64 `json:"timeouts"`
+	// ReOffers is the number of times that an anchor was offered to the witness because other witnesses
+	// did not return a proof in time.
+	ReOffers int64 `json:"reOffers"`
+
+	// Turnaround is the moving average of the time between the selection of the witness and the
+	// receipt of its proof.
+	Turnaround time.Duration `json:"turnaround"`
+	// FailureRate is the moving average of witness timeouts, where 0 means that the witness always returns
+	// a proof in time and 1 means that the witness never returns a proof in time.
+	FailureRate float64 `json:"failureRate"`
+
+	// Windows contains the stats of the witness for each period (StatsWindowPeriod) within the
+	// retention period (MaxStatsRetention), ordered by start time.
+	Windows []*StatsWindow `json:"windows,omitempty"`
+
+	UpdatedTime time.Time `json:"updatedTime"`
+
+	// Version is incremented each time that the stats are updated and is used to detect concurrent updates.
+	Version int64 `json:"version"`
+}
+
+// StatsWindow contains the stats of a witness for a single period.
+type StatsWindow struct {
+	Start    time.Time `json:"start"`
+	Requests int64     `json:"requests"`
+	Proofs   int64     `json:"proofs"`
+	Timeouts int64     `json:"timeouts"`
+	ReOffers int64     `json:"reOffers"`
+
+	// Turnarounds is a histogram of proof turnaround times (see turnaroundBuckets).
+	Turnarounds   []int64       `json:"turnarounds,omitempty"`
+	MaxTurnaround time.Duration `json:"maxTurnaround,omitempty"`
+}
+
+// StatsSummary contains the aggregated stats of a witness for the windows since a given time.
+type StatsSummary struct {
+	WitnessURI *vocab.URLProperty `json:"witness"`
+	Since      time.Time          `json:"since"`
+	Requests   int64              `json:"requests"`
+	Proofs     int64              `json:"proofs"`
+	Timeouts   int64              `json:"timeouts"`
+	ReOffers   int64              `json:"reOffers"`
+
+	// TurnaroundP50 and TurnaroundP95 are the 50th and 95th percentiles of the proof turnaround times. The
+	// values are approximate since they're calculated from a histogram, i.e. the value is the upper bound of
+	// the histogram bucket that contains the percentile.
+	TurnaroundP50 time.Duration `json:"turnaroundP50"`
+	TurnaroundP95 time.Duration `json:"turnaroundP95"`
+}
+
+// Summarize aggregates the stats of the windows that overlap the period starting at the given time.
+func (s *Stats) Summarize(since time.Time) *StatsSummary {
+	summary := &StatsSummary{
+		WitnessURI: s.WitnessURI,
+		Since:      since,
+	}
+
+	turnarounds := make([]int64, len(turnaroundBuckets)+1)
+
+	var maxTurnaround time.Duration
+
+	for _, w := range s.Windows {
+		if !w.Start.Add(StatsWindowPeriod).After(since) {
+			continue
+		}
+
+		summary.Requests += w.Requests
+		summary.Proofs += w.Proofs
+		summary.Timeouts += w.Timeouts
+		summary.ReOffers += w.ReOffers
+
+		for i, n := range w.Turnarounds {
+			if i < len(turnarounds) {
+				turnarounds[i] += n
+			}
+		}
+
+		if w.MaxTurnaround > maxTurnaround {
+			maxTurnaround = w.MaxTurnaround
+		}
+	}
+
+	summary.TurnaroundP50 = percentile(turnarounds, maxTurnaround, 0.5)
+	summary.TurnaroundP95 = percentile(turnarounds, maxTurnaround, 0.95)
+
+	return summary
+}
+
+type statsEntry struct {
+	EntryType string `json:"entryType"`
+	*Stats
+}
+
+// GetStats returns the proof statistics for the given witness. ErrContentNotFound is returned if
+// no statistics have been recorded for the witness.
+func (s *Store) GetStats(witness *url.URL) (*Stats, error) {
+	entryBytes, err := s.store.Get(statsKey(witness))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get stats for witness[%s]: %w", witness, err)
+	}
+
+	entry := &statsEntry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal stats for witness[%s]: %w", witness, err)
+	}
+
+	return entry.Stats, nil
+}
+
+// GetAllStats returns the proof statistics of all witnesses.
+func (s *Store) GetAllStats() ([]*Stats, error) {
+	query := fmt.Sprintf("%s:%s", typeTagName, statsType)
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("failed to query witness stats: %w", err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("witness stats iterator error: %w", err)
+	}
+
+	var allStats []*Stats
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("failed to get witness stats from iterator: %w", e)
+		}
+
+		entry := &statsEntry{}
+
+		e = json.Unmarshal(value, entry)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal witness stats: %w", e)
+		}
+
+		allStats = append(allStats, entry.Stats)
+
+		ok, e = iter.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("witness stats iterator error: %w", e)
+		}
+	}
+
+	return allStats, nil
+}
+
+// AddTimeout records that the given witness did not return a proof for the given anchor within the maximum
+// witness delay. The timeout is only recorded once for each anchor and witness, i.e. subsequent calls for the
+// same anchor and witness are ignored.
+func (s *Store) AddTimeout(anchorID string, witness *url.URL) error {
+	flagged, err := s.flagTimeout(anchorID, witness)
+	if err != nil {
+		return fmt.Errorf("flag timeout for anchorID[%s], witness[%s]: %w", anchorID, witness, err)
+	}
+
+	if !flagged {
+		logger.Debug("Witness timeout was already recorded for anchor", logfields.WithAnchorURIString(anchorID),
+			logfields.WithWitnessURI(witness))
+
+		return nil
+	}
+
+	err = s.updateStats(witness, func(stats *Stats, window *StatsWindow) {
+		stats.Timeouts++
+		stats.FailureRate = movingAverage(stats.FailureRate, 1, stats.Timeouts+stats.Proofs)
+
+		window.Timeouts++
+	})
+	if err != nil {
+		return fmt.Errorf("add timeout for anchorID[%s], witness[%s]: %w", anchorID, witness, err)
+	}
+
+	s.metrics.WitnessIncrementTimeoutCount(witness.String())
+
+	logger.Debug("Recorded witness timeout for anchor", logfields.WithAnchorURIString(anchorID),
+		logfields.WithWitnessURI(witness))
+
+	return nil
+}
+
+// addRequests records that an anchor was offered to the given witnesses. If reOffer is true then the anchor
+// was offered to the witnesses because other witnesses did not return a proof in time.
+func (s *Store) addRequests(reOffer bool, witnesses ...*url.URL) {
+	for _, w := range witnesses {
+		err := s.updateStats(w, func(stats *Stats, window *StatsWindow) {
+			stats.Requests++
+			window.Requests++
+
+			if reOffer {
+				stats.ReOffers++
+				window.ReOffers++
+			}
+		})
+		if err != nil {
+			logger.Warn("Error recording witness request", logfields.WithWitnessURI(w), log.WithError(err))
+		}
+
+		s.metrics.WitnessIncrementRequestCount(w.String())
+
+		if reOffer {
+			s.metrics.WitnessIncrementReOfferCount(w.String())
+		}
+	}
+}
+
+// addTurnaround records the time between the selection of the witness for the given anchor and the
+// receipt of its proof. Errors are logged since the stats are informational only.
+func (s *Store) addTurnaround(anchorID string, witness *url.URL) {
+	selectedTime, err := s.getSelectedTime(anchorID, witness)
+	if err != nil {
+		logger.Warn("Error getting the selection time of witness for anchor", logfields.WithAnchorURIString(anchorID),
+			logfields.WithWitnessURI(witness), log.WithError(err))
+
+		return
+	}
+
+	var turnaround time.Duration
+
+	if !selectedTime.IsZero() {
+		turnaround = time.Since(selectedTime)
+	}
+
+	err = s.updateStats(witness, func(stats *Stats, window *StatsWindow) {
+		stats.Proofs++
+		stats.FailureRate = movingAverage(stats.FailureRate, 0, stats.Timeouts+stats.Proofs)
+
+		window.Proofs++
+
+		if selectedTime.IsZero() {
+			return
+		}
+
+		stats.Turnaround = time.Duration(movingAverage(float64(stats.Turnaround), float64(turnaround), stats.Proofs))
+
+		window.addTurnaround(turnaround)
+	})
+	if err != nil {
+		logger.Warn("Error recording witness proof turnaround", logfields.WithAnchorURIString(anchorID),
+			logfields.WithWitnessURI(witness), log.WithError(err))
+	}
+
+	if !selectedTime.IsZero() {
+		s.metrics.WitnessProofTurnaroundTime(witness.String(), turnaround)
+	}
+}
+
+func (s *Store) getSelectedTime(anchorID string, witness *url.URL) (time.Time, error) {
+	infos, err := s.getWitnessInfos(anchorID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var selectedTime time.Time
+
+	for _, info := range infos {
+		if info.Witness != nil && info.URI.String() == witness.String() && info.SelectedTime > selectedTime.UnixMilli() {
+			selectedTime = time.UnixMilli(info.SelectedTime)
+		}
+	}
+
+	return selectedTime, nil
+}
+
+// updateStats applies the given update to the stats of the witness and to the current stats window. The stats
+// of a witness are updated by all server instances, so if the store supports conditional writes then the stats
+// are only stored if they weren't modified since they were read, otherwise the update is retried. (Updates
+// within this server instance are serialized.)
+func (s *Store) updateStats(witness *url.URL, update func(stats *Stats, window *StatsWindow)) error {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+
+	var err error
+
+	for i := 0; i < maxStatsUpdateAttempts; i++ {
+		err = s.doUpdateStats(witness, update)
+		if !errors.Is(err, store.ErrVersionConflict) {
+			return err
+		}
+
+		logger.Debug("Witness stats were modified by another instance. Retrying update.",
+			logfields.WithWitnessURI(witness), log.WithError(err))
+	}
+
+	return orberrors.NewTransient(err)
+}
+
+func (s *Store) doUpdateStats(witness *url.URL, update func(stats *Stats, window *StatsWindow)) error {
+	stats, err := s.GetStats(witness)
+	if err != nil {
+		if !errors.Is(err, orberrors.ErrContentNotFound) {
+			return err
+		}
+
+		stats = &Stats{WitnessURI: vocab.NewURLProperty(witness)}
+	}
+
+	now := time.Now()
+
+	update(stats, stats.currentWindow(now))
+
+	version := stats.Version
+
+	stats.UpdatedTime = now
+	stats.Version++
+
+	entryBytes, err := json.Marshal(&statsEntry{EntryType: statsType, Stats: stats})
+	if err != nil {
+		return fmt.Errorf("marshal stats for witness[%s]: %w", witness, err)
+	}
+
+	if vs, ok := s.store.(store.VersionedStore); ok {
+		err = vs.PutIfVersion(statsKey(witness), entryBytes, version)
+		if err != nil {
+			if errors.Is(err, store.ErrVersionConflict) {
+				return err
+			}
+
+			return orberrors.NewTransientf("store stats for witness[%s]: %w", witness, err)
+		}
+
+		return nil
+	}
+
+	err = s.store.Put(statsKey(witness), entryBytes, storage.Tag{Name: typeTagName, Value: statsType})
+	if err != nil {
+		return orberrors.NewTransientf("store stats for witness[%s]: %w", witness, err)
+	}
+
+	return nil
+}
+
+// currentWindow returns the stats window for the given time, creating a new window if necessary. Windows that
+// are older than the retention period are removed.
+func (s *Stats) currentWindow(now time.Time) *StatsWindow {
+	start := now.Truncate(StatsWindowPeriod)
+
+	var windows []*StatsWindow
+
+	for _, w := range s.Windows {
+		if !w.Start.Before(start.Add(-MaxStatsRetention)) {
+			windows = append(windows, w)
+		}
+	}
+
+	s.Windows = windows
+
+	if n := len(s.Windows); n > 0 && s.Windows[n-1].Start.Equal(start) {
+		return s.Windows[n-1]
+	}
+
+	w := &StatsWindow{Start: start}
+
+	s.Windows = append(s.Windows, w)
+
+	return w
+}
+
+func (w *StatsWindow) addTurnaround(turnaround time.Duration) {
+	if len(w.Turnarounds) == 0 {
+		w.Turnarounds = make([]int64, len(turnaroundBuckets)+1)
+	}
+
+	i := 0
+
+	for i < len(turnaroundBuckets) && turnaround > turnaroundBuckets[i] {
+		i++
+	}
+
+	w.Turnarounds[i]++
+
+	if turnaround > w.MaxTurnaround {
+		w.MaxTurnaround = turnaround
+	}
+}
+
+// percentile returns the upper bound of the histogram bucket that contains the given percentile. If the
+// percentile is in the overflow bucket then the maximum value is returned.
+func percentile(histogram []int64, maxValue time.Duration, p float64) time.Duration {
+	var total int64
+
+	for _, n := range histogram {
+		total += n
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(p * float64(total)))
+
+	var cumulative int64
+
+	for i, n := range histogram {
+		cumulative += n
+
+		if cumulative >= rank {
+			if i < len(turnaroundBuckets) && turnaroundBuckets[i] < maxValue {
+				return turnaroundBuckets[i]
+			}
+
+			return maxValue
+		}
+	}
+
+	return maxValue
+}
+
+func statsKey(witness *url.URL) string {
+	return statsKeyPrefix + base64.RawURLEncoding.EncodeToString([]byte(witness.String()))
+}
+
+// movingAverage returns the exponential moving average of the given samples. The first sample
+// (i.e. total is 1) is used as is.
+func movingAverage(avg, sample float64, total int64) float64 {
+	if total <= 1 {
+		return sample
+	}
+
+	return avg + statsSmoothingFactor*(sample-avg)
+}
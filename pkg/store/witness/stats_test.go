@@ -0,0 +1,381 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	orbstore "github.com/trustbloc/orb/pkg/store"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore_Stats(t *testing.T) {
+	witness1URL := testutil.MustParseURL("https://domain1.com/service")
+	witness2URL := testutil.MustParseURL("https://domain2.com/service")
+
+	t.Run("requests and timeouts", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetStats(witness1URL)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL)},
+		}))
+
+		stats, err := s.GetStats(witness1URL)
+		require.NoError(t, err)
+		require.Equal(t, witness1URL.String(), stats.WitnessURI.String())
+		require.Equal(t, int64(1), stats.Requests)
+		require.Zero(t, stats.Timeouts)
+
+		// Witness 2 wasn't selected.
+		_, err = s.GetStats(witness2URL)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		require.NoError(t, s.AddTimeout(anchorID, witness1URL))
+		// The timeout was already recorded for the anchor.
+		require.NoError(t, s.AddTimeout(anchorID, witness1URL))
+		// Witness 2 wasn't selected.
+		require.NoError(t, s.AddTimeout(anchorID, witness2URL))
+
+		stats, err = s.GetStats(witness1URL)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stats.Requests)
+		require.Equal(t, int64(1), stats.Timeouts)
+		require.Equal(t, float64(1), stats.FailureRate)
+		require.Equal(t, int64(2), stats.Version)
+
+		_, err = s.GetStats(witness2URL)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+
+		// A timeout is recorded again if the witness is selected again.
+		require.NoError(t, s.UpdateWitnessSelection(anchorID, []*url.URL{witness1URL}, false))
+		require.NoError(t, s.UpdateWitnessSelection(anchorID, []*url.URL{witness1URL}, true))
+		require.NoError(t, s.AddTimeout(anchorID, witness1URL))
+
+		stats, err = s.GetStats(witness1URL)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), stats.Timeouts)
+	})
+
+	t.Run("concurrent update", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+		}))
+
+		vs := &versionedStore{Store: s.store, conflicts: 2}
+		s.store = vs
+
+		require.NoError(t, s.AddTimeout(anchorID, witness1URL))
+		require.Equal(t, 3, vs.putIfVersionCalls)
+
+		stats, err := s.GetStats(witness1URL)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stats.Timeouts)
+		require.Equal(t, int64(2), stats.Version)
+
+		vs.conflicts = maxStatsUpdateAttempts
+
+		err = s.updateStats(witness1URL, func(stats *Stats, window *StatsWindow) {})
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.True(t, errors.Is(err, orbstore.ErrVersionConflict))
+	})
+
+	t.Run("re-offers and all stats", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		allStats, err := s.GetAllStats()
+		require.NoError(t, err)
+		require.Empty(t, allStats)
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL)},
+		}))
+
+		require.NoError(t, s.UpdateWitnessSelection(anchorID, []*url.URL{witness2URL}, true))
+
+		stats, err := s.GetStats(witness2URL)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), stats.Requests)
+		require.Equal(t, int64(1), stats.ReOffers)
+		require.Len(t, stats.Windows, 1)
+		require.Equal(t, int64(1), stats.Windows[0].Requests)
+		require.Equal(t, int64(1), stats.Windows[0].ReOffers)
+
+		allStats, err = s.GetAllStats()
+		require.NoError(t, err)
+		require.Len(t, allStats, 2)
+	})
+
+	t.Run("proof turnaround", func(t *testing.T) {
+		info := &witnessInfo{
+			Entry: &Entry{EntryType: witnessInfoType},
+			Witness: &proof.Witness{
+				Type:     proof.WitnessTypeSystem,
+				URI:      vocab.NewURLProperty(witness1URL),
+				Selected: true,
+			},
+			SelectedTime: time.Now().Add(-time.Second).UnixMilli(),
+		}
+
+		infoBytes, err := json.Marshal(info)
+		require.NoError(t, err)
+
+		it := &mocks.Iterator{}
+		it.NextReturnsOnCall(0, true, nil)
+		it.ValueReturns(infoBytes, nil)
+
+		store := &mocks.Store{}
+		store.QueryReturns(it, nil)
+		store.GetReturns(nil, storage.ErrDataNotFound)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+		require.Equal(t, 2, store.PutCallCount())
+
+		key, value, _ := store.PutArgsForCall(1)
+		require.Equal(t, statsKey(witness1URL), key)
+
+		entry := &statsEntry{}
+		require.NoError(t, json.Unmarshal(value, entry))
+		require.Equal(t, int64(1), entry.Proofs)
+		require.Zero(t, entry.FailureRate)
+		require.GreaterOrEqual(t, entry.Turnaround, time.Second)
+		require.Len(t, entry.Windows, 1)
+		require.Equal(t, int64(1), entry.Windows[0].Proofs)
+		require.Equal(t, int64(1), entry.Windows[0].Turnarounds[1])
+	})
+
+	t.Run("proof turnaround - query error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		// The stats are informational so the proof is still stored.
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+		require.Equal(t, 1, store.PutCallCount())
+	})
+
+	t.Run("error - get stats", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, fmt.Errorf("get error"))
+		store.QueryReturns(newSelectedWitnessIterator(t, witness1URL), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetStats(witness1URL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "get error")
+
+		err = s.AddTimeout(anchorID, witness1URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get error")
+	})
+
+	t.Run("error - put stats", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, storage.ErrDataNotFound)
+		store.QueryReturns(newSelectedWitnessIterator(t, witness1URL), nil)
+		store.PutReturnsOnCall(1, fmt.Errorf("put error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		err = s.AddTimeout(anchorID, witness1URL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "put error")
+	})
+
+	t.Run("error - flag timeout", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		err = s.AddTimeout(anchorID, witness1URL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "query error")
+		require.Zero(t, store.PutCallCount())
+	})
+
+	t.Run("error - query all stats", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetAllStats()
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "query error")
+	})
+
+	t.Run("error - unmarshal stats", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns([]byte("{"), nil)
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetStats(witness1URL)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal stats")
+	})
+}
+
+func newSelectedWitnessIterator(t *testing.T, witness *url.URL) *mocks.Iterator {
+	t.Helper()
+
+	infoBytes, err := json.Marshal(&witnessInfo{
+		Entry: &Entry{EntryType: witnessInfoType},
+		Witness: &proof.Witness{
+			Type:     proof.WitnessTypeSystem,
+			URI:      vocab.NewURLProperty(witness),
+			Selected: true,
+		},
+	})
+	require.NoError(t, err)
+
+	it := &mocks.Iterator{}
+	it.NextReturnsOnCall(0, true, nil)
+	it.ValueReturns(infoBytes, nil)
+
+	return it
+}
+
+// versionedStore simulates a store that supports conditional writes. The given number of conditional
+// writes fail with a version conflict.
+type versionedStore struct {
+	storage.Store
+
+	conflicts         int
+	putIfVersionCalls int
+}
+
+func (s *versionedStore) PutIfVersion(key string, value []byte, version int64) error {
+	s.putIfVersionCalls++
+
+	if s.conflicts > 0 {
+		s.conflicts--
+
+		return orbstore.ErrVersionConflict
+	}
+
+	current := &Stats{}
+
+	currentBytes, err := s.Get(key)
+	if err == nil {
+		if err := json.Unmarshal(currentBytes, current); err != nil {
+			return err
+		}
+	}
+
+	if current.Version != version {
+		return orbstore.ErrVersionConflict
+	}
+
+	return s.Put(key, value, storage.Tag{Name: typeTagName, Value: statsType})
+}
+
+func TestStats_Summarize(t *testing.T) {
+	now := time.Now().Truncate(StatsWindowPeriod)
+
+	stats := &Stats{}
+
+	// Windows are trimmed to the retention period.
+	stats.Windows = []*StatsWindow{{Start: now.Add(-MaxStatsRetention - time.Hour), Requests: 100}}
+
+	w := stats.currentWindow(now.Add(-2 * time.Hour))
+	w.Requests = 10
+	w.Timeouts = 5
+
+	w = stats.currentWindow(now)
+	require.Len(t, stats.Windows, 2)
+	require.Same(t, w, stats.currentWindow(now.Add(time.Minute)))
+
+	w.Requests = 20
+	w.Proofs = 20
+	w.ReOffers = 2
+
+	for i := 0; i < 18; i++ {
+		w.addTurnaround(3 * time.Second)
+	}
+
+	w.addTurnaround(20 * time.Second)
+	w.addTurnaround(2 * time.Hour)
+
+	summary := stats.Summarize(now.Add(-time.Hour))
+	require.Equal(t, int64(20), summary.Requests)
+	require.Equal(t, int64(20), summary.Proofs)
+	require.Zero(t, summary.Timeouts)
+	require.Equal(t, int64(2), summary.ReOffers)
+	require.Equal(t, 5*time.Second, summary.TurnaroundP50)
+	require.Equal(t, 30*time.Second, summary.TurnaroundP95)
+
+	summary = stats.Summarize(now.Add(-3 * time.Hour))
+	require.Equal(t, int64(30), summary.Requests)
+	require.Equal(t, int64(5), summary.Timeouts)
+
+	require.Zero(t, (&Stats{}).Summarize(now).TurnaroundP50)
+	require.Equal(t, 2*time.Hour, percentile([]int64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, 2*time.Hour, 0.5))
+	require.Equal(t, 3*time.Second, percentile([]int64{0, 1}, 3*time.Second, 0.5))
+}
@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const witnessedTimeTagName = "witnessedTime"
+
+// WitnessTiming contains the time at which a witness was selected for an anchor and the time at which the
+// witness' proof was received. The times are in milliseconds since the epoch and are zero if unknown.
+type WitnessTiming struct {
+	Witness      string `json:"witness"`
+	SelectedTime int64  `json:"selectedTime,omitempty"`
+	ProofTime    int64  `json:"proofTime,omitempty"`
+}
+
+// OfferedTime returns the earliest time (in milliseconds since the epoch) at which a witness was selected,
+// i.e. the time at which the anchor was first offered to the witnesses. Zero is returned if unknown.
+func OfferedTime(timings []*WitnessTiming) int64 {
+	var offeredTime int64
+
+	for _, t := range timings {
+		if t.SelectedTime > 0 && (offeredTime == 0 || t.SelectedTime < offeredTime) {
+			offeredTime = t.SelectedTime
+		}
+	}
+
+	return offeredTime
+}
+
+// GetTimings returns the selection and proof times of the witnesses of the given anchor.
+func (s *Store) GetTimings(anchorID string) ([]*WitnessTiming, error) {
+	infos, err := s.getWitnessInfos(anchorID)
+	if err != nil {
+		return nil, fmt.Errorf("get witnesses for anchor [%s]: %w", anchorID, err)
+	}
+
+	proofs, err := s.getProofs(anchorID)
+	if err != nil {
+		return nil, fmt.Errorf("get witness proofs for anchor [%s]: %w", anchorID, err)
+	}
+
+	return getTimings(infos, proofs), nil
+}
+
+// GetRecentWitnessedAnchors returns (at most) the given number of the most recently witnessed anchors, ordered
+// from the most recent to the least recent. Anchors that were witnessed before witness timings were recorded
+// are not included.
+func (s *Store) GetRecentWitnessedAnchors(maxAnchors int) ([]*WitnessedAnchor, error) {
+	// Not all storage providers support sorting, so the most recent anchors are selected as they're read.
+	query := fmt.Sprintf("%s:%s&&%s", typeTagName, witnessedAnchorType, witnessedTimeTagName)
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query witnessed anchors: %w", err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("witnessed anchor iterator error: %w", err)
+	}
+
+	var anchors []*WitnessedAnchor
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get witnessed anchor from iterator: %w", e)
+		}
+
+		entry := &witnessedAnchorEntry{}
+
+		e = json.Unmarshal(value, entry)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal witnessed anchor: %w", e)
+		}
+
+		anchors = addRecent(anchors, entry.WitnessedAnchor, maxAnchors)
+
+		ok, e = iter.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("witnessed anchor iterator error: %w", e)
+		}
+	}
+
+	return anchors, nil
+}
+
+// addRecent inserts the anchor into the given list (which is sorted by witnessed time in descending order)
+// and truncates the list to the given maximum size.
+func addRecent(anchors []*WitnessedAnchor, anchor *WitnessedAnchor, maxAnchors int) []*WitnessedAnchor {
+	i := sort.Search(len(anchors), func(i int) bool {
+		return anchors[i].WitnessedTime < anchor.WitnessedTime
+	})
+
+	if i >= maxAnchors {
+		return anchors
+	}
+
+	anchors = append(anchors, nil)
+	copy(anchors[i+1:], anchors[i:])
+	anchors[i] = anchor
+
+	if len(anchors) > maxAnchors {
+		anchors = anchors[:maxAnchors]
+	}
+
+	return anchors
+}
+
+func getTimings(infos []*witnessInfo, proofs proofs) []*WitnessTiming {
+	timingMap := make(map[string]*WitnessTiming)
+
+	var timings []*WitnessTiming
+
+	for _, info := range infos {
+		if info.Witness == nil || info.URI == nil {
+			continue
+		}
+
+		t, ok := timingMap[info.URI.String()]
+		if !ok {
+			t = &WitnessTiming{Witness: info.URI.String()}
+
+			if wp := proofs.get(info.URI.URL()); wp != nil {
+				t.ProofTime = wp.ReceivedTime
+			}
+
+			timingMap[t.Witness] = t
+			timings = append(timings, t)
+		}
+
+		if info.SelectedTime > t.SelectedTime {
+			t.SelectedTime = info.SelectedTime
+		}
+	}
+
+	return timings
+}
@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore_Timings(t *testing.T) {
+	witness1URL := testutil.MustParseURL("https://w1.com/services/orb")
+	witness2URL := testutil.MustParseURL("https://w2.com/services/orb")
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL)},
+		}))
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+
+		timings, err := s.GetTimings(anchorID)
+		require.NoError(t, err)
+		require.Len(t, timings, 2)
+
+		for _, timing := range timings {
+			switch timing.Witness {
+			case witness1URL.String():
+				require.NotZero(t, timing.SelectedTime)
+				require.GreaterOrEqual(t, timing.ProofTime, timing.SelectedTime)
+			case witness2URL.String():
+				require.Zero(t, timing.SelectedTime)
+				require.Zero(t, timing.ProofTime)
+			default:
+				t.Fatalf("unexpected witness: %s", timing.Witness)
+			}
+		}
+
+		require.Equal(t, timings[0].SelectedTime+timings[1].SelectedTime, OfferedTime(timings))
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("injected query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetTimings(anchorID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+}
+
+func TestStore_GetRecentWitnessedAnchors(t *testing.T) {
+	witnessURL := testutil.MustParseURL("https://w1.com/services/orb")
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchors, err := s.GetRecentWitnessedAnchors(2)
+		require.NoError(t, err)
+		require.Empty(t, anchors)
+
+		for i := 0; i < 3; i++ {
+			id := fmt.Sprintf("%s_%d", anchorID, i)
+
+			require.NoError(t, s.Put(id, []*proof.Witness{
+				{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witnessURL), Selected: true},
+			}))
+			require.NoError(t, s.AddProof(id, witnessURL, []byte(proofJSON)))
+			require.NoError(t, s.RecordWitnessedAnchor(id, "vcID", "hl:xxx"))
+		}
+
+		anchors, err = s.GetRecentWitnessedAnchors(2)
+		require.NoError(t, err)
+		require.Len(t, anchors, 2)
+		require.GreaterOrEqual(t, anchors[0].WitnessedTime, anchors[1].WitnessedTime)
+
+		for _, anchor := range anchors {
+			require.Len(t, anchor.Timings, 1)
+			require.NotZero(t, anchor.Timings[0].ProofTime)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(nil, fmt.Errorf("injected query error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetRecentWitnessedAnchors(10)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+	})
+}
+
+func TestAddRecent(t *testing.T) {
+	var anchors []*WitnessedAnchor
+
+	for _, witnessedTime := range []int64{3, 1, 5, 4, 2} {
+		anchors = addRecent(anchors, &WitnessedAnchor{WitnessedTime: witnessedTime}, 3)
+	}
+
+	require.Len(t, anchors, 3)
+	require.Equal(t, int64(5), anchors[0].WitnessedTime)
+	require.Equal(t, int64(4), anchors[1].WitnessedTime)
+	require.Equal(t, int64(3), anchors[2].WitnessedTime)
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +22,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/vocab"
 	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/observability/metrics/noop"
 	"github.com/trustbloc/orb/pkg/store"
 	"github.com/trustbloc/orb/pkg/store/expiry"
 )
@@ -34,6 +36,7 @@ const (
 
 	anchorIndexTagName = "anchorID"
 	expiryTagName      = "expiryTime"
+	witnessTagName     = "witness"
 
 	queryExpr = "%s:%s&&%s:%s"
 
@@ -42,11 +45,33 @@ const (
 
 var logger = log.New("witness-store")
 
+type metricsProvider interface {
+	WitnessIncrementRequestCount(witness string)
+	WitnessIncrementReOfferCount(witness string)
+	WitnessIncrementTimeoutCount(witness string)
+	WitnessProofTurnaroundTime(witness string, value time.Duration)
+}
+
+// Opt is a witness store option.
+type Opt func(s *Store)
+
+// WithMetrics sets the metrics provider that's used to record witness stats.
+func WithMetrics(metrics metricsProvider) Opt {
+	return func(s *Store) {
+		s.metrics = metrics
+	}
+}
+
 // New creates new anchor witness store.
-func New(provider storage.Provider, expiryService *expiry.Service, expiryPeriod time.Duration) (*Store, error) {
+func New(provider storage.Provider, expiryService *expiry.Service, expiryPeriod time.Duration,
+	opts ...Opt,
+) (*Store, error) {
 	s, err := store.Open(provider, namespace,
 		store.NewTagGroup(anchorIndexTagName, typeTagName),
 		store.NewTagGroup(expiryTagName),
+		store.NewTagGroup(witnessTagName, typeTagName),
+		store.NewTagGroup(witnessedAnchorTagName, typeTagName),
+		store.NewTagGroup(typeTagName, witnessedTimeTagName),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open anchor witness store: %w", err)
@@ -55,6 +80,11 @@ func New(provider storage.Provider, expiryService *expiry.Service, expiryPeriod
 	ws := &Store{
 		store:        s,
 		expiryPeriod: expiryPeriod,
+		metrics:      &noop.NoOptMetrics{},
+	}
+
+	for _, opt := range opts {
+		opt(ws)
 	}
 
 	expiryService.Register(s, expiryTagName, namespace, expiry.WithExpiryHandler(ws))
@@ -66,6 +96,8 @@ func New(provider storage.Provider, expiryService *expiry.Service, expiryPeriod
 type Store struct {
 	store        storage.Store
 	expiryPeriod time.Duration
+	statsMutex   sync.Mutex // Serializes the stats updates within this server instance.
+	metrics      metricsProvider
 }
 
 // Put saves witnesses into anchor witness store.
@@ -76,9 +108,19 @@ func (s *Store) Put(anchorID string, witnesses []*proof.Witness) error {
 
 	putOptions := &storage.PutOptions{IsNewKey: true}
 
+	now := time.Now()
+
+	var selected []*url.URL
+
 	for i, w := range witnesses {
 		witnessInfo := s.newWitnessInfo(anchorIDEncoded, w)
 
+		if w.Selected {
+			witnessInfo.SelectedTime = now.UnixMilli()
+
+			selected = append(selected, w.URI.URL())
+		}
+
 		value, err := json.Marshal(witnessInfo)
 		if err != nil {
 			return fmt.Errorf("failed to marshal anchor witness: %w", err)
@@ -107,30 +149,55 @@ func (s *Store) Put(anchorID string, witnesses []*proof.Witness) error {
 
 	logger.Debug("Stored witnesses for anchor", logfields.WithTotal(len(witnesses)), logfields.WithAnchorURIString(anchorID))
 
+	s.addRequests(false, selected...)
+
 	return nil
 }
 
 // Delete deletes all witnesses associated with anchor ID.
 func (s *Store) Delete(anchorID string) error {
+	witnessKeys, err := s.getKeys(anchorID)
+	if err != nil {
+		return err
+	}
+
+	if len(witnessKeys) == 0 {
+		logger.Debug("No witnesses to delete for anchor - nothing to do.", logfields.WithAnchorURIString(anchorID))
+
+		return nil
+	}
+
+	operations := make([]storage.Operation, len(witnessKeys))
+
+	for i, k := range witnessKeys {
+		operations[i] = storage.Operation{Key: k}
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransientf("failed to delete witnesses for anchorID[%s]: %w", anchorID, err)
+	}
+
+	logger.Debug("Deleted witnesses for anchor.", logfields.WithTotal(len(witnessKeys)), logfields.WithAnchorURIString(anchorID))
+
+	return nil
+}
+
+// getKeys returns the keys of all of the entries (witnesses, proofs and policy scope) of the given anchor.
+func (s *Store) getKeys(anchorID string) ([]string, error) {
 	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
 	query := fmt.Sprintf("%s:%s", anchorIndexTagName, anchorIDEncoded)
 
 	iter, err := s.store.Query(query)
 	if err != nil {
-		return orberrors.NewTransientf("failed to query witnesses to delete for anchorID[%s]: %w", query, err)
+		return nil, orberrors.NewTransientf("failed to query witnesses to delete for anchorID[%s]: %w", query, err)
 	}
 
 	defer store.CloseIterator(iter)
 
 	ok, err := iter.Next()
 	if err != nil {
-		return orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
-	}
-
-	if !ok {
-		logger.Debug("No witnesses to delete for anchor - nothing to do.", logfields.WithAnchorURIString(anchorID))
-
-		return nil
+		return nil, orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
 	}
 
 	var witnessKeys []string
@@ -140,7 +207,7 @@ func (s *Store) Delete(anchorID string) error {
 
 		key, err = iter.Key()
 		if err != nil {
-			return orberrors.NewTransientf("failed to get witness to delete from iterator value for anchorID[%s]: %w",
+			return nil, orberrors.NewTransientf("failed to get witness to delete from iterator value for anchorID[%s]: %w",
 				anchorID, err)
 		}
 
@@ -148,24 +215,11 @@ func (s *Store) Delete(anchorID string) error {
 
 		ok, err = iter.Next()
 		if err != nil {
-			return orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
+			return nil, orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
 		}
 	}
 
-	operations := make([]storage.Operation, len(witnessKeys))
-
-	for i, k := range witnessKeys {
-		operations[i] = storage.Operation{Key: k}
-	}
-
-	err = s.store.Batch(operations)
-	if err != nil {
-		return orberrors.NewTransientf("failed to delete witnesses for anchorID[%s]: %w", anchorID, err)
-	}
-
-	logger.Debug("Deleted witnesses for anchor.", logfields.WithTotal(len(witnessKeys)), logfields.WithAnchorURIString(anchorID))
-
-	return nil
+	return witnessKeys, nil
 }
 
 // Get retrieves witnesses for the given anchor id.
@@ -184,6 +238,27 @@ func (s *Store) Get(anchorID string) ([]*proof.WitnessProof, error) {
 }
 
 func (s *Store) getWitnesses(anchorID string) ([]*proof.Witness, error) {
+	infos, err := s.getWitnessInfos(anchorID)
+	if err != nil {
+		return nil, err
+	}
+
+	witnesses := make([]*proof.Witness, len(infos))
+
+	for i, info := range infos {
+		witnesses[i] = info.Witness
+	}
+
+	logger.Debug("Retrieved witnesses for anchor", logfields.WithTotal(len(witnesses)), logfields.WithAnchorURIString(anchorID))
+
+	if len(witnesses) == 0 {
+		return nil, fmt.Errorf("anchorID[%s] not found in the store", anchorID)
+	}
+
+	return witnesses, nil
+}
+
+func (s *Store) getWitnessInfos(anchorID string) ([]*witnessInfo, error) {
 	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
 
 	query := fmt.Sprintf(queryExpr, anchorIndexTagName, anchorIDEncoded, typeTagName, witnessInfoType)
@@ -200,7 +275,7 @@ func (s *Store) getWitnesses(anchorID string) ([]*proof.Witness, error) {
 		return nil, orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
 	}
 
-	var witnesses []*proof.Witness
+	var infos []*witnessInfo
 
 	for ok {
 		value, e := iter.Value()
@@ -209,15 +284,15 @@ func (s *Store) getWitnesses(anchorID string) ([]*proof.Witness, error) {
 				anchorID, e)
 		}
 
-		witnessInfo := &witnessInfo{}
+		info := &witnessInfo{}
 
-		e = json.Unmarshal(value, witnessInfo)
+		e = json.Unmarshal(value, info)
 		if e != nil {
 			return nil, fmt.Errorf("failed to unmarshal anchor witness from store value for anchorID[%s]: %w",
 				anchorID, e)
 		}
 
-		witnesses = append(witnesses, witnessInfo.Witness)
+		infos = append(infos, info)
 
 		ok, e = iter.Next()
 		if e != nil {
@@ -225,13 +300,7 @@ func (s *Store) getWitnesses(anchorID string) ([]*proof.Witness, error) {
 		}
 	}
 
-	logger.Debug("Retrieved witnesses for anchor", logfields.WithTotal(len(witnesses)), logfields.WithAnchorURIString(anchorID))
-
-	if len(witnesses) == 0 {
-		return nil, fmt.Errorf("anchorID[%s] not found in the store", anchorID)
-	}
-
-	return witnesses, nil
+	return infos, nil
 }
 
 func (s *Store) getProofs(anchorID string) (proofs, error) {
@@ -291,11 +360,7 @@ func (s *Store) AddProof(anchorID string, witness *url.URL, p []byte) error {
 		return fmt.Errorf("marshal proof for anchorID[%s], witness[%s]: %w", anchorID, witness, err)
 	}
 
-	err = s.store.Put(uuid.New().String(), wpBytes,
-		storage.Tag{Name: typeTagName, Value: wp.EntryType},
-		storage.Tag{Name: anchorIndexTagName, Value: wp.AnchorID},
-		storage.Tag{Name: expiryTagName, Value: fmt.Sprintf("%d", wp.ExpiryTime)},
-	)
+	err = s.store.Put(uuid.New().String(), wpBytes, proofTags(wp, witness)...)
 	if err != nil {
 		return orberrors.NewTransientf("store proof for anchorID[%s], witness[%s]: %w", anchorID, witness, err)
 	}
@@ -303,6 +368,8 @@ func (s *Store) AddProof(anchorID string, witness *url.URL, p []byte) error {
 	logger.Debug("Successfully stored proof for anchor from witness",
 		logfields.WithAnchorURIString(anchorID), logfields.WithWitnessURI(witness), logfields.WithProof(p))
 
+	s.addTurnaround(anchorID, witness)
+
 	return nil
 }
 
@@ -324,25 +391,40 @@ func (s *Store) UpdateWitnessSelection(anchorID string, witnesses []*url.URL, se
 		return orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
 	}
 
-	updatedNo := 0
+	var updated []*url.URL
 
 	witnessesMap := getWitnessesMap(witnesses)
 
+	var selectedTime int64
+	if selected {
+		selectedTime = time.Now().UnixMilli()
+	}
+
 	for ok {
-		key, w, e := getWitness(iter)
+		key, info, e := getWitnessInfo(iter)
 		if e != nil {
 			return fmt.Errorf("get next witness from iterator for anchorID[%s]: %w", anchorID, e)
 		}
 
+		w := info.Witness
+
 		if _, ok = witnessesMap[w.URI.String()]; ok {
 			w.Selected = selected
 
-			e = s.storeWitness(key, w, anchorIDEncoded)
+			updatedInfo := s.newWitnessInfo(anchorIDEncoded, w)
+			updatedInfo.SelectedTime = selectedTime
+
+			// A timeout is recorded at most once per selection of the witness.
+			if !selected {
+				updatedInfo.TimeoutRecorded = info.TimeoutRecorded
+			}
+
+			e = s.storeWitnessInfo(key, updatedInfo)
 			if e != nil {
 				return fmt.Errorf("store witness for anchorID[%s]: %w", anchorID, e)
 			}
 
-			updatedNo++
+			updated = append(updated, w.URI.URL())
 
 			logger.Debug("Updated witness proof for anchor/witness", logfields.WithAnchorURIString(anchorID),
 				logfields.WithWitnessURI(w.URI))
@@ -354,14 +436,67 @@ func (s *Store) UpdateWitnessSelection(anchorID string, witnesses []*url.URL, se
 		}
 	}
 
-	if updatedNo == 0 {
+	if len(updated) == 0 {
 		return fmt.Errorf("witness%s not found for anchorID[%s]", witnesses, anchorID)
 	}
 
+	// Witnesses are only selected after the anchor was stored when other witnesses didn't
+	// return a proof in time, i.e. the anchor is re-offered to the witnesses.
+	if selected {
+		s.addRequests(true, updated...)
+	}
+
 	return nil
 }
 
-func getWitness(iter storage.Iterator) (string, *proof.Witness, error) {
+// flagTimeout flags the records of the given selected witness to indicate that a timeout was recorded for the
+// anchor. False is returned if a timeout was already recorded for the current selection of the witness.
+func (s *Store) flagTimeout(anchorID string, witness *url.URL) (bool, error) {
+	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
+
+	query := fmt.Sprintf(queryExpr, anchorIndexTagName, anchorIDEncoded, typeTagName, witnessInfoType)
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return false, orberrors.NewTransientf("failed to query witnesses to flag for anchorID[%s]: %w", query, err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return false, orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, err)
+	}
+
+	var flagged bool
+
+	for ok {
+		key, info, e := getWitnessInfo(iter)
+		if e != nil {
+			return false, fmt.Errorf("get next witness from iterator for anchorID[%s]: %w", anchorID, e)
+		}
+
+		if info.Witness != nil && info.Selected && !info.TimeoutRecorded && info.URI.String() == witness.String() {
+			info.TimeoutRecorded = true
+
+			e = s.storeWitnessInfo(key, info)
+			if e != nil {
+				return false, fmt.Errorf("store witness for anchorID[%s]: %w", anchorID, e)
+			}
+
+			flagged = true
+		}
+
+		ok, e = iter.Next()
+		if e != nil {
+			return false, orberrors.NewTransientf(iteratorErrMsgFormat, anchorID, e)
+		}
+	}
+
+	return flagged, nil
+}
+
+func getWitnessInfo(iter storage.Iterator) (string, *witnessInfo, error) {
 	value, err := iter.Value()
 	if err != nil {
 		return "", nil, orberrors.NewTransientf("get iterator value: %w", err)
@@ -379,15 +514,13 @@ func getWitness(iter storage.Iterator) (string, *proof.Witness, error) {
 		return "", nil, orberrors.NewTransientf("get key: %w", err)
 	}
 
-	return key, w.Witness, nil
+	return key, w, nil
 }
 
-func (s *Store) storeWitness(key string, w *proof.Witness, anchorIDEncoded string) error {
-	info := s.newWitnessInfo(anchorIDEncoded, w)
-
+func (s *Store) storeWitnessInfo(key string, info *witnessInfo) error {
 	witnessBytes, marshalErr := json.Marshal(info)
 	if marshalErr != nil {
-		return fmt.Errorf("marshal witness[%s]: %w", w.URI, marshalErr)
+		return fmt.Errorf("marshal witness[%s]: %w", info.URI, marshalErr)
 	}
 
 	err := s.store.Put(key, witnessBytes,
@@ -396,7 +529,7 @@ func (s *Store) storeWitness(key string, w *proof.Witness, anchorIDEncoded strin
 		storage.Tag{Name: expiryTagName, Value: fmt.Sprintf("%d", info.ExpiryTime)},
 	)
 	if err != nil {
-		return orberrors.NewTransientf("store witness[%s]: %w", w.URI, err)
+		return orberrors.NewTransientf("store witness[%s]: %w", info.URI, err)
 	}
 
 	return nil
@@ -472,12 +605,21 @@ type Entry struct {
 type witnessInfo struct {
 	*Entry
 	*proof.Witness
+
+	// SelectedTime is the time (in milliseconds since the epoch) at which the witness was selected.
+	SelectedTime int64 `json:"selectedTime,omitempty"`
+	// TimeoutRecorded indicates that a timeout was recorded in the witness stats since the witness didn't
+	// return a proof for the anchor in time.
+	TimeoutRecorded bool `json:"timeoutRecorded,omitempty"`
 }
 
 type witnessProof struct {
 	*Entry
 	WitnessURI *vocab.URLProperty `json:"witness"`
 	Proof      []byte             `json:"proof"`
+
+	// ReceivedTime is the time (in milliseconds since the epoch) at which the proof was received.
+	ReceivedTime int64 `json:"receivedTime,omitempty"`
 }
 
 func (s *Store) newWitnessInfo(anchorID string, w *proof.Witness) *witnessInfo {
@@ -491,6 +633,15 @@ func (s *Store) newWitnessInfo(anchorID string, w *proof.Witness) *witnessInfo {
 	}
 }
 
+func proofTags(wp *witnessProof, witness *url.URL) []storage.Tag {
+	return []storage.Tag{
+		{Name: typeTagName, Value: wp.EntryType},
+		{Name: anchorIndexTagName, Value: wp.AnchorID},
+		{Name: expiryTagName, Value: fmt.Sprintf("%d", wp.ExpiryTime)},
+		{Name: witnessTagName, Value: encodeWitness(witness)},
+	}
+}
+
 func (s *Store) newWitnessProof(anchorID string, uri *url.URL, prf []byte) *witnessProof {
 	return &witnessProof{
 		Entry: &Entry{
@@ -498,8 +649,9 @@ func (s *Store) newWitnessProof(anchorID string, uri *url.URL, prf []byte) *witn
 			AnchorID:   anchorID,
 			ExpiryTime: time.Now().Add(s.expiryPeriod).Unix(),
 		},
-		WitnessURI: vocab.NewURLProperty(uri),
-		Proof:      prf,
+		WitnessURI:   vocab.NewURLProperty(uri),
+		Proof:        prf,
+		ReceivedTime: time.Now().UnixMilli(),
 	}
 }
 
@@ -335,8 +335,11 @@ func TestStore_AddProof(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.QueryReturns(&mocks.Iterator{}, nil)
+
 		provider := &mocks.Provider{}
-		provider.OpenStoreReturns(&mocks.Store{}, nil)
+		provider.OpenStoreReturns(store, nil)
 
 		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
 		require.NoError(t, err)
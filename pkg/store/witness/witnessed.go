@@ -0,0 +1,321 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/store"
+)
+
+const (
+	witnessedAnchorType    = "witnessed-anchor"
+	witnessedAnchorRefType = "witnessed-anchor-ref"
+	witnessedAnchorPrefix  = "witnessed_"
+
+	// The anchor ID of witnessed anchors is stored under a different tag than the one used for the
+	// transient witness data so that the records aren't deleted when the anchor completes processing.
+	witnessedAnchorTagName = "witnessedAnchorID"
+)
+
+// WitnessedAnchor contains the witnesses (and their proofs) of an anchor whose witness policy was satisfied.
+//
+//nolint:tagliatelle
+type WitnessedAnchor struct {
+	AnchorID string `json:"anchorID"`
+	// VCID is the key of the anchor's verifiable credential in the VC store.
+	VCID string `json:"vcID"`
+	// AnchorLinksetHL is the hashlink of the anchor linkset that was published.
+	AnchorLinksetHL string                `json:"anchorLinksetHL"`
+	Witnesses       []*proof.WitnessProof `json:"witnesses"`
+	// Scope is the scope that was used to resolve the witness policies of the anchor (if any).
+	Scope *config.AnchorScope `json:"scope,omitempty"`
+	// WitnessedTime is the time (in milliseconds since the epoch) at which the witness policy was satisfied.
+	WitnessedTime int64 `json:"witnessedTime,omitempty"`
+	// Timings contains the selection and proof times of the witnesses.
+	Timings []*WitnessTiming `json:"timings,omitempty"`
+}
+
+// Proof returns the proof of the given witness or nil if the witness didn't provide a proof for the anchor.
+func (a *WitnessedAnchor) Proof(witness *url.URL) []byte {
+	for _, w := range a.Witnesses {
+		if w.URI != nil && w.URI.String() == witness.String() {
+			return w.Proof
+		}
+	}
+
+	return nil
+}
+
+//nolint:tagliatelle
+type witnessedAnchorRef struct {
+	EntryType string `json:"entryType"`
+	AnchorID  string `json:"anchorID"`
+}
+
+type witnessedAnchorEntry struct {
+	EntryType string `json:"entryType"`
+	*WitnessedAnchor
+}
+
+// RecordWitnessedAnchor saves the witnesses and proofs of an anchor whose witness policy has been satisfied. Unlike
+// the other witness data, these records aren't deleted after the anchor is processed since they're required in order
+// to find the anchors that rely on the proofs of a revoked witness.
+func (s *Store) RecordWitnessedAnchor(anchorID, vcID, anchorLinksetHL string) error {
+	witnesses, err := s.Get(anchorID)
+	if err != nil {
+		return fmt.Errorf("get witnesses for anchor[%s]: %w", anchorID, err)
+	}
+
+	scope, err := s.GetPolicyScope(anchorID)
+	if err != nil && !errors.Is(err, orberrors.ErrContentNotFound) {
+		return fmt.Errorf("get policy scope for anchor[%s]: %w", anchorID, err)
+	}
+
+	timings, err := s.GetTimings(anchorID)
+	if err != nil {
+		return fmt.Errorf("get witness timings for anchor[%s]: %w", anchorID, err)
+	}
+
+	witnessedTime := time.Now().UnixMilli()
+
+	entryBytes, err := json.Marshal(&witnessedAnchorEntry{
+		EntryType: witnessedAnchorType,
+		WitnessedAnchor: &WitnessedAnchor{
+			AnchorID:        anchorID,
+			VCID:            vcID,
+			AnchorLinksetHL: anchorLinksetHL,
+			Witnesses:       witnesses,
+			Scope:           scope,
+			WitnessedTime:   witnessedTime,
+			Timings:         timings,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal witnessed anchor[%s]: %w", anchorID, err)
+	}
+
+	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
+
+	refBytes, err := json.Marshal(&witnessedAnchorRef{
+		EntryType: witnessedAnchorRefType,
+		AnchorID:  anchorIDEncoded,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal witnessed anchor reference[%s]: %w", anchorID, err)
+	}
+
+	operations := []storage.Operation{
+		{
+			Key:   witnessedAnchorKey(anchorIDEncoded),
+			Value: entryBytes,
+			Tags: []storage.Tag{
+				{Name: typeTagName, Value: witnessedAnchorType},
+				{Name: witnessedAnchorTagName, Value: anchorIDEncoded},
+				{Name: witnessedTimeTagName, Value: fmt.Sprintf("%d", witnessedTime)},
+			},
+		},
+	}
+
+	// Add a reference for each witness that provided a proof so that the anchors may be looked up by witness.
+	for _, w := range witnesses {
+		if w.Proof == nil {
+			continue
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   witnessedAnchorKey(anchorIDEncoded) + "_" + encodeWitness(w.URI.URL()),
+			Value: refBytes,
+			Tags: []storage.Tag{
+				{Name: typeTagName, Value: witnessedAnchorRefType},
+				{Name: witnessTagName, Value: encodeWitness(w.URI.URL())},
+			},
+		})
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransientf("store witnessed anchor[%s]: %w", anchorID, err)
+	}
+
+	logger.Debug("Stored witnessed anchor", logfields.WithAnchorURIString(anchorID),
+		logfields.WithAnchorEventURIString(anchorLinksetHL), logfields.WithTotal(len(witnesses)))
+
+	return nil
+}
+
+// GetWitnessedAnchor returns the witnesses of the given anchor. ErrContentNotFound is returned if the witness
+// policy of the anchor hasn't been satisfied.
+func (s *Store) GetWitnessedAnchor(anchorID string) (*WitnessedAnchor, error) {
+	entryBytes, err := s.store.Get(witnessedAnchorKey(base64.RawURLEncoding.EncodeToString([]byte(anchorID))))
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, orberrors.ErrContentNotFound
+		}
+
+		return nil, orberrors.NewTransientf("get witnessed anchor[%s]: %w", anchorID, err)
+	}
+
+	entry := &witnessedAnchorEntry{}
+
+	err = json.Unmarshal(entryBytes, entry)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal witnessed anchor[%s]: %w", anchorID, err)
+	}
+
+	return entry.WitnessedAnchor, nil
+}
+
+// GetWitnessedAnchors returns the witnessed anchors for which the given witness provided a proof.
+func (s *Store) GetWitnessedAnchors(witness *url.URL) ([]*WitnessedAnchor, error) {
+	query := fmt.Sprintf(queryExpr, witnessTagName, encodeWitness(witness), typeTagName, witnessedAnchorRefType)
+
+	iter, err := s.store.Query(query)
+	if err != nil {
+		return nil, orberrors.NewTransientf("query witnessed anchors of witness[%s]: %w", witness, err)
+	}
+
+	defer store.CloseIterator(iter)
+
+	ok, err := iter.Next()
+	if err != nil {
+		return nil, orberrors.NewTransientf("witnessed anchor iterator error for witness[%s]: %w", witness, err)
+	}
+
+	var anchors []*WitnessedAnchor
+
+	for ok {
+		value, e := iter.Value()
+		if e != nil {
+			return nil, orberrors.NewTransientf("get witnessed anchor from iterator for witness[%s]: %w", witness, e)
+		}
+
+		ref := &witnessedAnchorRef{}
+
+		e = json.Unmarshal(value, ref)
+		if e != nil {
+			return nil, fmt.Errorf("unmarshal witnessed anchor reference for witness[%s]: %w", witness, e)
+		}
+
+		anchorID, e := base64.RawURLEncoding.DecodeString(ref.AnchorID)
+		if e != nil {
+			return nil, fmt.Errorf("decode anchor ID of witnessed anchor for witness[%s]: %w", witness, e)
+		}
+
+		anchor, e := s.GetWitnessedAnchor(string(anchorID))
+		if e != nil {
+			return nil, fmt.Errorf("get witnessed anchor for witness[%s]: %w", witness, e)
+		}
+
+		// The anchor may have been re-witnessed without a proof from this witness.
+		if anchor.Proof(witness) != nil {
+			anchors = append(anchors, anchor)
+		}
+
+		ok, e = iter.Next()
+		if e != nil {
+			return nil, orberrors.NewTransientf("witnessed anchor iterator error for witness[%s]: %w", witness, e)
+		}
+	}
+
+	return anchors, nil
+}
+
+// Restore replaces the witnesses, proofs and (optional) policy scope of an anchor that has already been witnessed
+// with the given witnesses, proofs and scope so that the anchor may be witnessed again. The existing entries are
+// deleted and the new entries are stored in a single batch so that, if the batch fails, the existing witnesses
+// of the anchor are retained and Restore may be retried. Unlike Put and AddProof, witness stats aren't updated.
+func (s *Store) Restore(anchorID string, witnesses []*proof.WitnessProof, scope *config.AnchorScope) error {
+	anchorIDEncoded := base64.RawURLEncoding.EncodeToString([]byte(anchorID))
+
+	existingKeys, err := s.getKeys(anchorID)
+	if err != nil {
+		return err
+	}
+
+	var operations []storage.Operation
+
+	for _, key := range existingKeys {
+		// The policy scope is overwritten below (if provided).
+		if scope != nil && key == policyScopeKey(anchorIDEncoded) {
+			continue
+		}
+
+		operations = append(operations, storage.Operation{Key: key})
+	}
+
+	if scope != nil {
+		op, e := s.policyScopeOperation(anchorID, scope)
+		if e != nil {
+			return e
+		}
+
+		operations = append(operations, op)
+	}
+
+	for _, w := range witnesses {
+		info := s.newWitnessInfo(anchorIDEncoded, w.Witness)
+
+		infoBytes, e := json.Marshal(info)
+		if e != nil {
+			return fmt.Errorf("marshal witness[%s] for anchor[%s]: %w", w.URI, anchorID, e)
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   uuid.New().String(),
+			Value: infoBytes,
+			Tags: []storage.Tag{
+				{Name: typeTagName, Value: info.EntryType},
+				{Name: anchorIndexTagName, Value: info.AnchorID},
+				{Name: expiryTagName, Value: fmt.Sprintf("%d", info.ExpiryTime)},
+			},
+		})
+
+		if w.Proof == nil {
+			continue
+		}
+
+		wp := s.newWitnessProof(anchorIDEncoded, w.URI.URL(), w.Proof)
+
+		wpBytes, e := json.Marshal(wp)
+		if e != nil {
+			return fmt.Errorf("marshal proof of witness[%s] for anchor[%s]: %w", w.URI, anchorID, e)
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   uuid.New().String(),
+			Value: wpBytes,
+			Tags:  proofTags(wp, w.URI.URL()),
+		})
+	}
+
+	err = s.store.Batch(operations)
+	if err != nil {
+		return orberrors.NewTransientf("restore witnesses for anchor[%s]: %w", anchorID, err)
+	}
+
+	logger.Debug("Restored witnesses for anchor", logfields.WithAnchorURIString(anchorID),
+		logfields.WithTotal(len(witnesses)))
+
+	return nil
+}
+
+func witnessedAnchorKey(anchorIDEncoded string) string {
+	return witnessedAnchorPrefix + anchorIDEncoded
+}
@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package witness
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trustbloc/orb/pkg/activitypub/vocab"
+	"github.com/trustbloc/orb/pkg/anchor/witness/policy/config"
+	"github.com/trustbloc/orb/pkg/anchor/witness/proof"
+	orberrors "github.com/trustbloc/orb/pkg/errors"
+	"github.com/trustbloc/orb/pkg/internal/testutil"
+	"github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+func TestStore_WitnessedAnchors(t *testing.T) {
+	witness1URL := testutil.MustParseURL("https://w1.com/services/orb")
+	witness2URL := testutil.MustParseURL("https://w2.com/services/orb")
+	witness3URL := testutil.MustParseURL("https://w3.com/services/orb")
+
+	const (
+		vcID            = "62c153d1-a6be-400e-a6a6-5b700b596d9d"
+		anchorLinksetHL = "hl:uEiBqkaTRFZScQsXTw8IDBSpVxiKGqjJCDUcgiwpcd2frLw"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL), Selected: true},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness3URL)},
+		}))
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+		require.NoError(t, s.AddProof(anchorID, witness2URL, []byte(proofJSON)))
+
+		require.NoError(t, s.RecordWitnessedAnchor(anchorID, vcID, anchorLinksetHL))
+
+		// The witnessed anchor is retained after the transient witness data is deleted.
+		require.NoError(t, s.Delete(anchorID))
+
+		anchor, err := s.GetWitnessedAnchor(anchorID)
+		require.NoError(t, err)
+		require.Equal(t, anchorID, anchor.AnchorID)
+		require.Equal(t, vcID, anchor.VCID)
+		require.Equal(t, anchorLinksetHL, anchor.AnchorLinksetHL)
+		require.Len(t, anchor.Witnesses, 3)
+		require.Equal(t, []byte(proofJSON), anchor.Proof(witness1URL))
+		require.Nil(t, anchor.Proof(witness3URL))
+
+		anchors, err := s.GetWitnessedAnchors(witness1URL)
+		require.NoError(t, err)
+		require.Len(t, anchors, 1)
+		require.Equal(t, anchorID, anchors[0].AnchorID)
+
+		anchors, err = s.GetWitnessedAnchors(witness3URL)
+		require.NoError(t, err)
+		require.Empty(t, anchors)
+
+		// Witness the anchor again without witness1.
+		require.NoError(t, s.Restore(anchorID, []*proof.WitnessProof{
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL), Selected: true},
+				Proof:   []byte(proofJSON),
+			},
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness3URL)},
+			},
+		}, nil))
+
+		witnesses, err := s.Get(anchorID)
+		require.NoError(t, err)
+		require.Len(t, witnesses, 2)
+
+		require.NoError(t, s.AddProof(anchorID, witness3URL, []byte(proofJSON)))
+		require.NoError(t, s.RecordWitnessedAnchor(anchorID, vcID, anchorLinksetHL))
+
+		anchors, err = s.GetWitnessedAnchors(witness1URL)
+		require.NoError(t, err)
+		require.Empty(t, anchors)
+
+		anchors, err = s.GetWitnessedAnchors(witness3URL)
+		require.NoError(t, err)
+		require.Len(t, anchors, 1)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		anchor, err := s.GetWitnessedAnchor(anchorID)
+		require.True(t, errors.Is(err, orberrors.ErrContentNotFound))
+		require.Nil(t, anchor)
+
+		require.Error(t, s.RecordWitnessedAnchor(anchorID, vcID, anchorLinksetHL))
+	})
+
+	t.Run("error - store errors", func(t *testing.T) {
+		store := &mocks.Store{}
+		store.GetReturns(nil, fmt.Errorf("injected get error"))
+		store.QueryReturns(nil, fmt.Errorf("injected query error"))
+		store.BatchReturns(fmt.Errorf("injected batch error"))
+
+		provider := &mocks.Provider{}
+		provider.OpenStoreReturns(store, nil)
+
+		s, err := New(provider, testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		_, err = s.GetWitnessedAnchor(anchorID)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected get error")
+
+		_, err = s.GetWitnessedAnchors(witness1URL)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+
+		err = s.Restore(anchorID, []*proof.WitnessProof{
+			{Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL)}},
+		}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected query error")
+
+		store.QueryReturns(&mocks.Iterator{}, nil)
+
+		err = s.Restore(anchorID, []*proof.WitnessProof{
+			{Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL)}},
+		}, nil)
+		require.Error(t, err)
+		require.True(t, orberrors.IsTransient(err))
+		require.Contains(t, err.Error(), "injected batch error")
+	})
+
+	t.Run("restore replaces existing witnesses", func(t *testing.T) {
+		s, err := New(mem.NewProvider(), testutil.GetExpiryService(t), expiryTime)
+		require.NoError(t, err)
+
+		scope := &config.AnchorScope{Namespace: "did:orb", Origins: []string{"https://orb.domain1.com"}}
+
+		require.NoError(t, s.Put(anchorID, []*proof.Witness{
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness1URL), Selected: true},
+			{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL), Selected: true},
+		}))
+
+		require.NoError(t, s.AddProof(anchorID, witness1URL, []byte(proofJSON)))
+		require.NoError(t, s.AddProof(anchorID, witness2URL, []byte(proofJSON)))
+		require.NoError(t, s.PutPolicyScope(anchorID, &config.AnchorScope{Namespace: "did:other"}))
+
+		restored := []*proof.WitnessProof{
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness2URL), Selected: true},
+				Proof:   []byte(proofJSON),
+			},
+			{
+				Witness: &proof.Witness{Type: proof.WitnessTypeSystem, URI: vocab.NewURLProperty(witness3URL)},
+			},
+		}
+
+		// Restoring is idempotent.
+		for i := 0; i < 2; i++ {
+			require.NoError(t, s.Restore(anchorID, restored, scope))
+
+			witnesses, e := s.Get(anchorID)
+			require.NoError(t, e)
+			require.Len(t, witnesses, 2)
+
+			for _, w := range witnesses {
+				require.NotEqual(t, witness1URL.String(), w.URI.String())
+
+				if w.URI.String() == witness2URL.String() {
+					require.NotNil(t, w.Proof)
+				} else {
+					require.Nil(t, w.Proof)
+				}
+			}
+
+			s2, e := s.GetPolicyScope(anchorID)
+			require.NoError(t, e)
+			require.Equal(t, scope, s2)
+		}
+	})
+}
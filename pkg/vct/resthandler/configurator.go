@@ -8,6 +8,8 @@ package resthandler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -93,10 +95,18 @@ func (c *LogConfigurator) handle(w http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	logConfig := &logConfig{
-		URL: logURLStr,
+	logConfig, err := getLogConfig(c.configStore)
+	if err != nil {
+		c.logger.Error("Error retrieving log configuration", log.WithError(err))
+
+		writeResponse(c.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
 	}
 
+	// The mirror logs are preserved when the primary log is updated.
+	logConfig.URL = logURLStr
+
 	valueBytes, err := c.marshal(logConfig)
 	if err != nil {
 		c.logger.Error("Marshal log configuration error", log.WithError(err))
@@ -150,6 +160,33 @@ func writeResponse(logger *log.Log, w http.ResponseWriter, status int, body []by
 	}
 }
 
+// getLogConfig returns the log configuration from the config store or an empty configuration if
+// the log has not been configured.
+func getLogConfig(configStore storage.Store) (*logConfig, error) {
+	logConfigBytes, err := configStore.Get(logURLKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return &logConfig{}, nil
+		}
+
+		return nil, err
+	}
+
+	logCfg := &logConfig{}
+
+	if len(logConfigBytes) == 0 {
+		return logCfg, nil
+	}
+
+	err = json.Unmarshal(logConfigBytes, logCfg)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal log configuration: %w", err)
+	}
+
+	return logCfg, nil
+}
+
 type logConfig struct {
-	URL string `json:"url"`
+	URL     string   `json:"url"`
+	Mirrors []string `json:"mirrors,omitempty"`
 }
@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/spi/storage"
+	"github.com/trustbloc/logutil-go/pkg/log"
+	"github.com/trustbloc/sidetree-svc-go/pkg/restapi/common"
+
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
+)
+
+const mirrorsEndpoint = "/log/mirrors"
+
+// MirrorsConfigurator updates the VCT mirror log URLs in the config store. An anchor credential is added to
+// the primary log as well as to each of the mirror logs when it is witnessed.
+type MirrorsConfigurator struct {
+	configStore     storage.Store
+	logMonitorStore logMonitorStore
+	logger          *log.Log
+	marshal         func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the MirrorsConfigurator service.
+func (c *MirrorsConfigurator) Path() string {
+	return mirrorsEndpoint
+}
+
+// Method returns the HTTP REST method for the configure mirror logs service.
+func (c *MirrorsConfigurator) Method() string {
+	return http.MethodPost
+}
+
+// Handler returns the HTTP REST handle for the MirrorsConfigurator service.
+func (c *MirrorsConfigurator) Handler() common.HTTPRequestHandler {
+	return c.handle
+}
+
+// NewMirrorsConfigurator returns a new MirrorsConfigurator.
+func NewMirrorsConfigurator(cfgStore storage.Store, lmStore logMonitorStore) *MirrorsConfigurator {
+	return &MirrorsConfigurator{
+		configStore:     cfgStore,
+		logMonitorStore: lmStore,
+		logger:          log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(mirrorsEndpoint))),
+		marshal:         json.Marshal,
+	}
+}
+
+func (c *MirrorsConfigurator) handle(w http.ResponseWriter, req *http.Request) {
+	reqBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		log.ReadRequestBodyError(c.logger, err)
+
+		writeResponse(c.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	var mirrors []string
+
+	err = json.Unmarshal(reqBytes, &mirrors)
+	if err != nil {
+		c.logger.Error("Invalid mirror logs request", log.WithError(err))
+
+		writeResponse(c.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+		return
+	}
+
+	for _, mirror := range mirrors {
+		_, err = url.ParseRequestURI(mirror)
+		if err != nil {
+			c.logger.Error("Invalid mirror log URL", logfields.WithLogURLString(mirror), log.WithError(err))
+
+			writeResponse(c.logger, w, http.StatusBadRequest, []byte(badRequestResponse))
+
+			return
+		}
+	}
+
+	logConfig, err := getLogConfig(c.configStore)
+	if err != nil {
+		c.logger.Error("Error retrieving log configuration", log.WithError(err))
+
+		writeResponse(c.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	logConfig.Mirrors = mirrors
+
+	valueBytes, err := c.marshal(logConfig)
+	if err != nil {
+		c.logger.Error("Marshal log configuration error", log.WithError(err))
+
+		writeResponse(c.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	err = c.configStore.Put(logURLKey, valueBytes)
+	if err != nil {
+		c.logger.Error("Error storing mirror log URLs", log.WithError(err))
+
+		writeResponse(c.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	c.logger.Debug("Stored mirror log URLs", logfields.WithLogURLStrings(mirrors...))
+
+	for _, mirror := range mirrors {
+		err = c.logMonitorStore.Activate(mirror)
+		if err != nil {
+			c.logger.Error("Error activating log monitoring for mirror log URL", logfields.WithLogURLString(mirror),
+				log.WithError(err))
+
+			writeResponse(c.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+			return
+		}
+	}
+
+	writeResponse(c.logger, w, http.StatusOK, nil)
+}
+
+// MirrorsRetriever retrieves the current mirror log URLs.
+type MirrorsRetriever struct {
+	configStore storage.Store
+	logger      *log.Log
+	marshal     func(interface{}) ([]byte, error)
+}
+
+// Path returns the HTTP REST endpoint for the mirror logs retriever.
+func (r *MirrorsRetriever) Path() string {
+	return mirrorsEndpoint
+}
+
+// Method returns the HTTP REST method for the mirror logs retriever.
+func (r *MirrorsRetriever) Method() string {
+	return http.MethodGet
+}
+
+// Handler returns the HTTP REST handle for the mirror logs retriever service.
+func (r *MirrorsRetriever) Handler() common.HTTPRequestHandler {
+	return r.handle
+}
+
+// NewMirrorsRetriever returns a new MirrorsRetriever.
+func NewMirrorsRetriever(cfgStore storage.Store) *MirrorsRetriever {
+	return &MirrorsRetriever{
+		configStore: cfgStore,
+		logger:      log.New(loggerModule, log.WithFields(logfields.WithServiceEndpoint(mirrorsEndpoint))),
+		marshal:     json.Marshal,
+	}
+}
+
+func (r *MirrorsRetriever) handle(w http.ResponseWriter, _ *http.Request) {
+	logConfig, err := getLogConfig(r.configStore)
+	if err != nil {
+		r.logger.Error("Error retrieving log configuration", log.WithError(err))
+
+		writeResponse(r.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	mirrors := logConfig.Mirrors
+	if mirrors == nil {
+		mirrors = []string{}
+	}
+
+	respBytes, err := r.marshal(mirrors)
+	if err != nil {
+		r.logger.Error("Marshal mirror log URLs error", log.WithError(err))
+
+		writeResponse(r.logger, w, http.StatusInternalServerError, []byte(internalServerErrorResponse))
+
+		return
+	}
+
+	r.logger.Debug("Retrieved mirror log URLs", logfields.WithLogURLStrings(mirrors...))
+
+	writeResponse(r.logger, w, http.StatusOK, respBytes)
+}
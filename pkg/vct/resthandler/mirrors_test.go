@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resthandler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/aries-framework-go/component/storageutil/mem"
+	"github.com/stretchr/testify/require"
+
+	storemocks "github.com/trustbloc/orb/pkg/store/mocks"
+)
+
+const (
+	testMirrorLogURL1 = "https://vct1.com/log"
+	testMirrorLogURL2 = "https://vct2.com/log"
+)
+
+func TestNewMirrorsConfigurator(t *testing.T) {
+	configStore, err := mem.NewProvider().OpenStore(configStoreName)
+	require.NoError(t, err)
+
+	c := NewMirrorsConfigurator(configStore, &mockLogMonitorStore{})
+	require.NotNil(t, c)
+	require.Equal(t, mirrorsEndpoint, c.Path())
+	require.Equal(t, http.MethodPost, c.Method())
+	require.NotNil(t, c.Handler())
+
+	r := NewMirrorsRetriever(configStore)
+	require.NotNil(t, r)
+	require.Equal(t, mirrorsEndpoint, r.Path())
+	require.Equal(t, http.MethodGet, r.Method())
+	require.NotNil(t, r.Handler())
+}
+
+func TestMirrorsHandler(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		mirrors := fmt.Sprintf(`["%s","%s"]`, testMirrorLogURL1, testMirrorLogURL2)
+
+		// Set the mirrors before the primary log to ensure that they're preserved when the log is updated.
+		status, _ := postMirrors(t, NewMirrorsConfigurator(configStore, &mockLogMonitorStore{}), mirrors)
+		require.Equal(t, http.StatusOK, status)
+
+		rw := httptest.NewRecorder()
+		New(configStore, &mockLogMonitorStore{}).handle(rw,
+			httptest.NewRequest(http.MethodPost, endpoint, bytes.NewBufferString(testLogURL)))
+		require.Equal(t, http.StatusOK, rw.Result().StatusCode)
+		require.NoError(t, rw.Result().Body.Close())
+
+		status, respBytes := getMirrors(t, NewMirrorsRetriever(configStore))
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, mirrors, string(respBytes))
+
+		rw = httptest.NewRecorder()
+		NewRetriever(configStore).handle(rw, httptest.NewRequest(http.MethodGet, endpoint, nil))
+		require.Equal(t, http.StatusOK, rw.Result().StatusCode)
+		require.Equal(t, testLogURL, rw.Body.String())
+		require.NoError(t, rw.Result().Body.Close())
+
+		// Clear the mirrors.
+		status, _ = postMirrors(t, NewMirrorsConfigurator(configStore, &mockLogMonitorStore{}), `[]`)
+		require.Equal(t, http.StatusOK, status)
+
+		status, respBytes = getMirrors(t, NewMirrorsRetriever(configStore))
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, `[]`, string(respBytes))
+	})
+
+	t.Run("error - invalid request", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		c := NewMirrorsConfigurator(configStore, &mockLogMonitorStore{})
+
+		status, respBytes := postMirrors(t, c, `{`)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, badRequestResponse, string(respBytes))
+
+		status, respBytes = postMirrors(t, c, `["InvalidURL"]`)
+		require.Equal(t, http.StatusBadRequest, status)
+		require.Equal(t, badRequestResponse, string(respBytes))
+
+		rw := httptest.NewRecorder()
+		c.handle(rw, httptest.NewRequest(http.MethodPost, mirrorsEndpoint, errReader(0)))
+		require.Equal(t, http.StatusBadRequest, rw.Result().StatusCode)
+		require.NoError(t, rw.Result().Body.Close())
+	})
+
+	t.Run("error - config store error", func(t *testing.T) {
+		configStore := &storemocks.Store{}
+		configStore.GetReturns(nil, errors.New("get error"))
+
+		status, respBytes := postMirrors(t, NewMirrorsConfigurator(configStore, &mockLogMonitorStore{}),
+			fmt.Sprintf(`["%s"]`, testMirrorLogURL1))
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Equal(t, internalServerErrorResponse, string(respBytes))
+
+		status, respBytes = getMirrors(t, NewMirrorsRetriever(configStore))
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Equal(t, internalServerErrorResponse, string(respBytes))
+
+		configStore = &storemocks.Store{}
+		configStore.PutReturns(errors.New("put error"))
+
+		status, respBytes = postMirrors(t, NewMirrorsConfigurator(configStore, &mockLogMonitorStore{}),
+			fmt.Sprintf(`["%s"]`, testMirrorLogURL1))
+		require.Equal(t, http.StatusInternalServerError, status)
+		require.Equal(t, internalServerErrorResponse, string(respBytes))
+	})
+
+	t.Run("error - marshal error", func(t *testing.T) {
+		c := NewMirrorsConfigurator(&storemocks.Store{}, &mockLogMonitorStore{})
+		c.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ := postMirrors(t, c, fmt.Sprintf(`["%s"]`, testMirrorLogURL1))
+		require.Equal(t, http.StatusInternalServerError, status)
+
+		r := NewMirrorsRetriever(&storemocks.Store{})
+		r.marshal = func(interface{}) ([]byte, error) { return nil, errors.New("injected marshal error") }
+
+		status, _ = getMirrors(t, r)
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("error - log monitor store error", func(t *testing.T) {
+		configStore, err := mem.NewProvider().OpenStore(configStoreName)
+		require.NoError(t, err)
+
+		status, _ := postMirrors(t,
+			NewMirrorsConfigurator(configStore, &mockLogMonitorStore{Err: errors.New("log monitor store error")}),
+			fmt.Sprintf(`["%s"]`, testMirrorLogURL1))
+		require.Equal(t, http.StatusInternalServerError, status)
+	})
+}
+
+func postMirrors(t *testing.T, c *MirrorsConfigurator, body string) (int, []byte) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	c.handle(rw, httptest.NewRequest(http.MethodPost, mirrorsEndpoint, bytes.NewBufferString(body)))
+
+	return readResult(t, rw)
+}
+
+func getMirrors(t *testing.T, r *MirrorsRetriever) (int, []byte) {
+	t.Helper()
+
+	rw := httptest.NewRecorder()
+
+	r.handle(rw, httptest.NewRequest(http.MethodGet, mirrorsEndpoint, nil))
+
+	return readResult(t, rw)
+}
+
+func readResult(t *testing.T, rw *httptest.ResponseRecorder) (int, []byte) {
+	t.Helper()
+
+	result := rw.Result()
+
+	respBytes, err := io.ReadAll(result.Body)
+	require.NoError(t, err)
+	require.NoError(t, result.Body.Close())
+
+	return result.StatusCode, respBytes
+}
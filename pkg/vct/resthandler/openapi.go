@@ -45,3 +45,44 @@ type logPostResp struct { //nolint: unused
 //	200: logPostResp
 func postLog() { //nolint: unused
 }
+
+// swagger:parameters logMirrorsGetReq
+type logMirrorsGetReq struct { //nolint: unused
+}
+
+// swagger:response logMirrorsGetResp
+type logMirrorsGetResp struct { //nolint: unused
+	// in: body
+	Body []string
+}
+
+// getLogMirrors swagger:route GET /log/mirrors Log logMirrorsGetReq
+//
+// Retrieves the current mirror logs. An anchor is added to the primary log as well as to each of the mirror logs.
+//
+// Responses:
+//
+//	200: logMirrorsGetResp
+func getLogMirrors() { //nolint: unused
+}
+
+// swagger:parameters logMirrorsPostReq
+type logMirrorsPostReq struct { //nolint: unused
+	// in: body
+	Body []string
+}
+
+// swagger:response logMirrorsPostResp
+type logMirrorsPostResp struct { //nolint: unused
+	Body string
+}
+
+// postLogMirrors swagger:route POST /log/mirrors Log logMirrorsPostReq
+//
+// Sets the mirror logs.
+//
+// Responses:
+//
+//	200: logMirrorsPostResp
+func postLogMirrors() { //nolint: unused
+}
@@ -18,9 +18,11 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/piprate/json-gold/ld"
+	"github.com/trustbloc/logutil-go/pkg/log"
 	"github.com/trustbloc/vct/pkg/client/vct"
 	"github.com/trustbloc/vct/pkg/controller/command"
 
+	logfields "github.com/trustbloc/orb/internal/pkg/log"
 	orberrors "github.com/trustbloc/orb/pkg/errors"
 	"github.com/trustbloc/orb/pkg/vcsigner"
 )
@@ -31,6 +33,8 @@ const (
 	logURLKey = "log-url"
 )
 
+var logger = log.New("vct")
+
 var (
 	// ErrLogEndpointNotConfigured indicates that a log endpoint has not been configured.
 	ErrLogEndpointNotConfigured = errors.New("log endpoint not configured")
@@ -194,25 +198,28 @@ func (c *Client) HealthCheck() error {
 	return vctClient.HealthCheck(context.Background())
 }
 
-// Witness credentials.
-func (c *Client) Witness(anchorCred []byte) ([]byte, error) { //nolint: funlen,cyclop
-	endpoint, err := c.GetLogEndpoint()
+// Witness adds the anchor credential to the primary log and to each of the mirror logs and returns
+// the proofs. The primary log is required, i.e. an error is returned if the credential could not be added
+// to the primary log, whereas mirror logs are best-effort. If no log is configured then the credential
+// is signed without a log.
+func (c *Client) Witness(anchorCred []byte) ([]byte, error) {
+	endpoints, err := c.GetLogEndpoints()
 	if err != nil && !errors.Is(err, ErrDisabled) && !errors.Is(err, ErrLogEndpointNotConfigured) {
 		return nil, fmt.Errorf("failed to get log endpoint for witness: %w", err)
 	}
 
-	if endpoint == "" {
+	ctx := []string{ctxSecurity}
+
+	ctx = append(ctx, c.signer.Context()...)
+
+	if len(endpoints) == 0 {
 		addProofStartTime := time.Now()
 
-		vc, innnerErr := c.addProof(endpoint, anchorCred, time.Now().UnixNano())
+		vc, innnerErr := c.addProof("", anchorCred, time.Now().UnixNano())
 		if innnerErr != nil {
 			return nil, fmt.Errorf("add proof: %w", innnerErr)
 		}
 
-		ctx := []string{ctxSecurity}
-
-		ctx = append(ctx, c.signer.Context()...)
-
 		c.metrics.WitnessAddProofVctNil(time.Since(addProofStartTime))
 
 		return json.Marshal(Proof{
@@ -221,6 +228,34 @@ func (c *Client) Witness(anchorCred []byte) ([]byte, error) { //nolint: funlen,c
 		})
 	}
 
+	proof, err := c.addToLog(endpoints[0], anchorCred)
+	if err != nil {
+		return nil, err
+	}
+
+	var additionalProofs []verifiable.Proof
+
+	for _, mirror := range endpoints[1:] {
+		mirrorProof, e := c.addToLog(mirror, anchorCred)
+		if e != nil {
+			logger.Warn("Error adding anchor credential to mirror log. The proof from this log will not be included.",
+				logfields.WithLogURLString(mirror), log.WithError(e))
+
+			continue
+		}
+
+		additionalProofs = append(additionalProofs, mirrorProof)
+	}
+
+	return json.Marshal(Proof{
+		Context:          ctx,
+		Proof:            proof,
+		AdditionalProofs: additionalProofs,
+	})
+}
+
+// addToLog adds the anchor credential to the given log and returns the proof signed with the log's timestamp.
+func (c *Client) addToLog(endpoint string, anchorCred []byte) (verifiable.Proof, error) { //nolint: funlen
 	addVCStartTime := time.Now()
 
 	vctClient := vct.New(endpoint, vct.WithHTTPClient(c.http),
@@ -291,44 +326,82 @@ func (c *Client) Witness(anchorCred []byte) ([]byte, error) { //nolint: funlen,c
 
 	c.metrics.WitnessVerifyVCTSignature(time.Since(verifyVCTStartTime))
 
-	ctx := []string{ctxSecurity}
-
-	ctx = append(ctx, c.signer.Context()...)
-
-	return json.Marshal(Proof{
-		Context: ctx,
-		Proof:   proof,
-	})
+	return proof, nil
 }
 
 // GetLogEndpoint returns the log endpoint or error, ErrLogEndpointNotConfigured,
 // if a log endpoint has not been configured.
 func (c *Client) GetLogEndpoint() (string, error) {
+	logConfig, err := c.getLogConfig()
+	if err != nil {
+		return "", err
+	}
+
+	return logConfig.URL, nil
+}
+
+// GetLogEndpoints returns the primary log endpoint followed by the mirror log endpoints or error,
+// ErrLogEndpointNotConfigured, if a log endpoint has not been configured. Mirror logs are ignored
+// if no primary log is configured.
+func (c *Client) GetLogEndpoints() ([]string, error) {
+	logConfig, err := c.getLogConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if logConfig.URL == "" {
+		return nil, nil
+	}
+
+	endpoints := []string{logConfig.URL}
+
+	for _, mirror := range logConfig.Mirrors {
+		if mirror != "" && mirror != logConfig.URL {
+			endpoints = append(endpoints, mirror)
+		}
+	}
+
+	return endpoints, nil
+}
+
+func (c *Client) getLogConfig() (*logCfg, error) {
 	value, err := c.configRetriever.GetValue(logURLKey)
 	if err != nil {
 		if errors.Is(err, orberrors.ErrContentNotFound) {
-			return "", ErrLogEndpointNotConfigured
+			return nil, ErrLogEndpointNotConfigured
 		}
 
-		return "", fmt.Errorf("failed to retrieve log endpoint from config cache: %w", err)
+		return nil, fmt.Errorf("failed to retrieve log endpoint from config cache: %w", err)
 	}
 
 	logConfig := &logCfg{}
 
 	err = json.Unmarshal(value, &logConfig)
 	if err != nil {
-		return "", fmt.Errorf("unmarshal log config: %w", err)
+		return nil, fmt.Errorf("unmarshal log config: %w", err)
 	}
 
-	return logConfig.URL, nil
+	return logConfig, nil
 }
 
 type logCfg struct {
-	URL string `json:"url"`
+	URL     string   `json:"url"`
+	Mirrors []string `json:"mirrors,omitempty"`
 }
 
-// Proof represents response.
+// Proof represents response. Proof is the proof from the primary log (or the local proof if no log is
+// configured) and AdditionalProofs contains the proofs from the mirror logs.
 type Proof struct {
-	Context interface{}      `json:"@context"`
-	Proof   verifiable.Proof `json:"proof"`
+	Context          interface{}        `json:"@context"`
+	Proof            verifiable.Proof   `json:"proof"`
+	AdditionalProofs []verifiable.Proof `json:"additionalProofs,omitempty"`
+}
+
+// Proofs returns the primary proof followed by the additional proofs.
+func (p *Proof) Proofs() []verifiable.Proof {
+	if p.Proof == nil {
+		return p.AdditionalProofs
+	}
+
+	return append([]verifiable.Proof{p.Proof}, p.AdditionalProofs...)
 }
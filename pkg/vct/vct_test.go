@@ -108,6 +108,56 @@ func TestClient_Witness(t *testing.T) {
 		require.Equal(t, int64(1663011476458000000), timestampTime.UnixNano())
 	})
 
+	t.Run("Success - mirror logs", func(t *testing.T) {
+		retriever := &mocks.ConfigRetriever{}
+		retriever.GetValueReturns([]byte(
+			`{"url":"https://example.com","mirrors":["https://mirror1.com","https://mirror2.com"]}`), nil)
+
+		mockHTTP := httpMock(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "mirror2.com" {
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewBufferString(`{"message":"mirror error"}`)),
+					StatusCode: http.StatusInternalServerError,
+				}, nil
+			}
+
+			if req.URL.Path == webfingerURL {
+				pubKey := `{"properties":{"https://trustbloc.dev/ns/public-key":` +
+					`"MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEfCc/5CT+K59Dv7+r+MiVX+ARfMeFK9CwdLlicTyjoNJdhFfP4/wnVfXg+vLjrqBYFsYzgokTSTZBSk72WF1RrQ=="}}`
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewBufferString(pubKey)),
+					StatusCode: http.StatusOK,
+				}, nil
+			}
+
+			return &http.Response{
+				Body:       io.NopCloser(bytes.NewBufferString(mockResponse)),
+				StatusCode: http.StatusOK,
+			}, nil
+		})
+
+		client := New(retriever, &mockSigner{}, &mocks.MetricsProvider{}, WithHTTPClient(mockHTTP),
+			WithDocumentLoader(testutil.GetLoader(t)))
+
+		resp, err := client.Witness([]byte(mockVC))
+		require.NoError(t, err)
+
+		var p Proof
+		require.NoError(t, json.Unmarshal(resp, &p))
+
+		require.Equal(t, "https://example.com", p.Proof["domain"])
+
+		// The proof from the second mirror is not included since the mirror returned an error.
+		require.Len(t, p.AdditionalProofs, 1)
+		require.Equal(t, "https://mirror1.com", p.AdditionalProofs[0]["domain"])
+
+		proofs := p.Proofs()
+		require.Len(t, proofs, 2)
+		require.Equal(t, "https://example.com", proofs[0]["domain"])
+		require.Equal(t, "https://mirror1.com", proofs[1]["domain"])
+	})
+
 	t.Run("Error - endpoint retriever error", func(t *testing.T) {
 		retriever := &mocks.ConfigRetriever{}
 		retriever.GetValueReturns(nil, fmt.Errorf("endpoint error"))
@@ -360,6 +410,56 @@ func TestGetLogEndpoint(t *testing.T) {
 	})
 }
 
+func TestGetLogEndpoints(t *testing.T) {
+	t.Run("success - primary and mirrors", func(t *testing.T) {
+		configRetriever := &mocks.ConfigRetriever{}
+		configRetriever.GetValueReturns([]byte(
+			`{"url":"https://vct.com/log","mirrors":["https://vct2.com/log","","https://vct.com/log"]}`), nil)
+
+		client := New(configRetriever, &mockSigner{}, &mocks.MetricsProvider{})
+
+		endpoints, err := client.GetLogEndpoints()
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://vct.com/log", "https://vct2.com/log"}, endpoints)
+	})
+
+	t.Run("success - mirrors ignored without primary", func(t *testing.T) {
+		configRetriever := &mocks.ConfigRetriever{}
+		configRetriever.GetValueReturns([]byte(`{"url":"","mirrors":["https://vct2.com/log"]}`), nil)
+
+		client := New(configRetriever, &mockSigner{}, &mocks.MetricsProvider{})
+
+		endpoints, err := client.GetLogEndpoints()
+		require.NoError(t, err)
+		require.Empty(t, endpoints)
+	})
+
+	t.Run("error - log URL not configured", func(t *testing.T) {
+		configRetriever := &mocks.ConfigRetriever{}
+		configRetriever.GetValueReturns(nil, orberrors.ErrContentNotFound)
+
+		client := New(configRetriever, &mockSigner{}, &mocks.MetricsProvider{})
+
+		endpoints, err := client.GetLogEndpoints()
+		require.True(t, errors.Is(err, ErrLogEndpointNotConfigured))
+		require.Empty(t, endpoints)
+	})
+}
+
+func TestProof_Proofs(t *testing.T) {
+	require.Empty(t, (&Proof{}).Proofs())
+
+	p := &Proof{
+		Proof:            verifiable.Proof{"domain": "https://vct.com/log"},
+		AdditionalProofs: []verifiable.Proof{{"domain": "https://vct2.com/log"}},
+	}
+
+	proofs := p.Proofs()
+	require.Len(t, proofs, 2)
+	require.Equal(t, "https://vct.com/log", proofs[0]["domain"])
+	require.Equal(t, "https://vct2.com/log", proofs[1]["domain"])
+}
+
 type mockSigner struct {
 	Err error
 }
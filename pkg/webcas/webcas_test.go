@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package webcas_test
 
 import (
+	"bytes"
 	"errors"
 	"io"
 	"net/http"
@@ -21,6 +22,7 @@ import (
 	"github.com/trustbloc/orb/pkg/activitypub/resthandler"
 	"github.com/trustbloc/orb/pkg/activitypub/service/mocks"
 	"github.com/trustbloc/orb/pkg/activitypub/store/memstore"
+	"github.com/trustbloc/orb/pkg/cas/unixfs"
 	"github.com/trustbloc/orb/pkg/hashlink"
 	"github.com/trustbloc/orb/pkg/internal/testutil"
 	orbmocks "github.com/trustbloc/orb/pkg/mocks"
@@ -118,6 +120,42 @@ func TestHandler(t *testing.T) {
 		require.Equal(t, http.StatusOK, response.StatusCode)
 		require.Equal(t, sampleAnchorCredential, string(responseBody))
 	})
+	t.Run("Content spanning multiple chunks found by CID", func(t *testing.T) {
+		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
+		require.NoError(t, err)
+
+		content := bytes.Repeat([]byte(sampleAnchorCredential), unixfs.ChunkSize/len(sampleAnchorCredential)+1)
+
+		_, err = casClient.Write(content)
+		require.NoError(t, err)
+
+		cid, err := unixfs.CID(content)
+		require.NoError(t, err)
+
+		webCAS := webcas.New(&resthandler.Config{}, memstore.New(""), &mocks.SignatureVerifier{}, casClient,
+			&apmocks.AuthTokenMgr{})
+		require.NotNil(t, webCAS)
+
+		router := mux.NewRouter()
+
+		router.HandleFunc(webCAS.Path(), webCAS.Handler())
+
+		testServer := httptest.NewServer(router)
+		defer testServer.Close()
+
+		response, err := http.DefaultClient.Get(testServer.URL + "/cas/" + cid.String())
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, response.Body.Close())
+		}()
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, content, responseBody)
+	})
 	t.Run("Content not found", func(t *testing.T) {
 		casClient, err := cas.New(mem.NewProvider(), casLink, nil, &orbmocks.MetricsProvider{}, 0)
 		require.NoError(t, err)